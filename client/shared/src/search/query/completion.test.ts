@@ -454,6 +454,8 @@ describe('getCompletionItems()', () => {
               "contains.content(\${1:TODO}) ",
               "contains(file:\${1:CHANGELOG} content:\${2:fix}) ",
               "contains.commit.after(\${1:1 month ago}) ",
+              "has.description(\${1:language server}) ",
+              "has.topic(\${1:code-search}) ",
               "^repo/with\\\\ a\\\\ space$ "
             ]
         `)
@@ -472,7 +474,9 @@ describe('getCompletionItems()', () => {
               "contains.file(\${1:CHANGELOG}) ",
               "contains.content(\${1:TODO}) ",
               "contains(file:\${1:CHANGELOG} content:\${2:fix}) ",
-              "contains.commit.after(\${1:1 month ago}) "
+              "contains.commit.after(\${1:1 month ago}) ",
+              "has.description(\${1:language server}) ",
+              "has.topic(\${1:code-search}) "
             ]
         `)
     })
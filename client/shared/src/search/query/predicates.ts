@@ -26,6 +26,10 @@ export const PREDICATES: Access[] = [
                     },
                 ],
             },
+            {
+                name: 'has',
+                fields: [{ name: 'description' }, { name: 'topic' }],
+            },
         ],
     },
     {
@@ -164,6 +168,16 @@ export const predicateCompletion = (field: string): Completion[] => {
                 insertText: 'contains.commit.after(${1:1 month ago})',
                 asSnippet: true,
             },
+            {
+                label: 'has.description(...)',
+                insertText: 'has.description(${1:language server})',
+                asSnippet: true,
+            },
+            {
+                label: 'has.topic(...)',
+                insertText: 'has.topic(${1:code-search})',
+                asSnippet: true,
+            },
         ]
     }
     return []
@@ -13,6 +13,8 @@ import { PhabricatorIcon } from '@sourcegraph/shared/src/components/icons'
 import awsCodeCommitSchemaJSON from '../../../../../schema/aws_codecommit.schema.json'
 import bitbucketCloudSchemaJSON from '../../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../../schema/bitbucket_server.schema.json'
+import gerritSchemaJSON from '../../../../../schema/gerrit.schema.json'
+import giteaSchemaJSON from '../../../../../schema/gitea.schema.json'
 import githubSchemaJSON from '../../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../../schema/gitolite.schema.json'
@@ -1177,6 +1179,165 @@ const PERFORCE: AddExternalServiceOptions = {
         },
     ],
 }
+const GERRIT: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GERRIT,
+    title: 'Gerrit',
+    icon: GitIcon,
+    jsonSchema: gerritSchemaJSON,
+    defaultDisplayName: 'Gerrit',
+    defaultConfig: `{
+  "url": "https://gerrit.example.com",
+  "username": "<username>",
+  "password": "<HTTP password>"
+}`,
+    editorActions: [
+        {
+            id: 'setCredentials',
+            label: 'Set HTTP credentials',
+            run: (config: string) => {
+                const value = '<HTTP password>'
+                const edits = setProperty(config, ['password'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addProject',
+            label: 'Add a project',
+            run: (config: string) => {
+                const value = '<project>'
+                const edits = setProperty(config, ['projects', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'excludeProject',
+            label: 'Exclude a project',
+            run: (config: string) => {
+                const value = { name: '<project>' }
+                const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<project>' }
+            },
+        },
+    ],
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    Set <Field>url</Field> to the URL of your Gerrit instance.
+                </li>
+                <li>
+                    Generate an HTTP password for the user Sourcegraph should sync as under{' '}
+                    <b>Settings &gt; HTTP Credentials</b> in Gerrit, and set the <Field>username</Field> and{' '}
+                    <Field>password</Field> fields below.
+                </li>
+                <li>
+                    By default, all projects visible to that user are synced. Set <Field>projects</Field> to sync
+                    only specific projects, and <Field>exclude</Field> to skip some.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/gerrit#configuration"
+                >
+                    the docs for more options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+}
+const GITEA: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GITEA,
+    title: 'Gitea',
+    icon: GitIcon,
+    jsonSchema: giteaSchemaJSON,
+    defaultDisplayName: 'Gitea',
+    defaultConfig: `{
+  "url": "https://gitea.example.com",
+  "token": "<access token>",
+  "orgs": []
+}`,
+    editorActions: [
+        {
+            id: 'setAccessToken',
+            label: 'Set access token',
+            run: (config: string) => {
+                const value = '<access token>'
+                const edits = setProperty(config, ['token'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addOrgRepos',
+            label: 'Add repositories in an organization',
+            run: (config: string) => {
+                const value = '<organization name>'
+                const edits = setProperty(config, ['orgs', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addUserRepos',
+            label: 'Add repositories owned by a user',
+            run: (config: string) => {
+                const value = '<user name>'
+                const edits = setProperty(config, ['users', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'addSearchQueryRepos',
+            label: 'Add repositories matching search query',
+            run: (config: string) => {
+                const value = '<search query>'
+                const edits = setProperty(config, ['repositoryQuery', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'excludeRepo',
+            label: 'Exclude a repository',
+            run: (config: string) => {
+                const value = { name: '<owner>/<repository>' }
+                const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<owner>/<repository>' }
+            },
+        },
+    ],
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    Set <Field>url</Field> to the URL of your Gitea or Forgejo instance.
+                </li>
+                <li>
+                    Create an access token under <b>Settings &gt; Applications</b> in Gitea and set the{' '}
+                    <Field>token</Field> field below. To use the token for batch changes, it needs write access to
+                    repositories.
+                </li>
+                <li>
+                    Use <Field>orgs</Field>, <Field>users</Field>, <Field>repositoryQuery</Field> or{' '}
+                    <Field>repos</Field> to select the repositories to sync, and <Field>exclude</Field> to skip
+                    some.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/gitea#configuration"
+                >
+                    the docs for more options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+}
 const JVM_PACKAGES: AddExternalServiceOptions = {
     kind: ExternalServiceKind.JVMPACKAGES,
     title: 'JVM Dependencies',
@@ -1216,6 +1377,8 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     gitlab: GITLAB_SELF_MANAGED,
     bitbucket: BITBUCKET_CLOUD,
     bitbucketserver: BITBUCKET_SERVER,
+    gerrit: GERRIT,
+    gitea: GITEA,
     aws_codecommit: AWS_CODE_COMMIT,
     srcservegit: SRC_SERVE_GIT,
     gitolite: GITOLITE,
@@ -1237,6 +1400,8 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.GITHUB]: GITHUB_DOTCOM,
     [ExternalServiceKind.BITBUCKETCLOUD]: BITBUCKET_CLOUD,
     [ExternalServiceKind.BITBUCKETSERVER]: BITBUCKET_SERVER,
+    [ExternalServiceKind.GERRIT]: GERRIT,
+    [ExternalServiceKind.GITEA]: GITEA,
     [ExternalServiceKind.GITLAB]: GITLAB_DOTCOM,
     [ExternalServiceKind.GITOLITE]: GITOLITE,
     [ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
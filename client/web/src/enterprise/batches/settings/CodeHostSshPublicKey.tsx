@@ -11,8 +11,10 @@ const configInstructionLinks: Record<ExternalServiceKind, string> = {
     [ExternalServiceKind.GITLAB]: 'https://docs.gitlab.com/ee/ssh/#add-an-ssh-key-to-your-gitlab-account',
     [ExternalServiceKind.BITBUCKETSERVER]:
         'https://confluence.atlassian.com/bitbucketserver/ssh-user-keys-for-personal-use-776639793.html',
+    [ExternalServiceKind.GITEA]: 'https://docs.gitea.io/en-us/faq/#how-can-i-add-ssh-keys-to-my-account',
     [ExternalServiceKind.AWSCODECOMMIT]: 'unsupported',
     [ExternalServiceKind.BITBUCKETCLOUD]: 'unsupported',
+    [ExternalServiceKind.GERRIT]: 'unsupported',
     [ExternalServiceKind.GITOLITE]: 'unsupported',
     [ExternalServiceKind.JVMPACKAGES]: 'unsupported',
     [ExternalServiceKind.OTHER]: 'unsupported',
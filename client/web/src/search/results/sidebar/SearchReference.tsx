@@ -206,6 +206,22 @@ To use this filter, the search query must contain \`type:diff\` or \`type:commit
         examples: ['repo:contains.commit.after(1 month ago)', 'repo:contains.commit.after(june 25 2017)'],
         showSuggestions: false,
     },
+    {
+        type: FilterType.repo,
+        placeholder: parsePlaceholder('has.description({regexp-pattern})'),
+        description:
+            'Search only inside repositories whose description matches the regular expression. The match is case insensitive.',
+        examples: ['repo:has.description(language server)'],
+        showSuggestions: false,
+    },
+    {
+        type: FilterType.repo,
+        placeholder: parsePlaceholder('has.topic({topic})'),
+        description:
+            'Search only inside repositories that are tagged with the topic on the code host. Topics are supported for GitHub and GitLab repositories.',
+        examples: ['repo:has.topic(code-search)'],
+        showSuggestions: false,
+    },
     {
         type: FilterType.rev,
         placeholder: parsePlaceholder('{revision}'),
@@ -0,0 +1,202 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+type auditLogArgs struct {
+	First      int32
+	After      *string
+	Actor      *graphql.ID
+	Actions    *[]string
+	TargetType *string
+	TargetID   *string
+	Since      *DateTime
+	Until      *DateTime
+}
+
+func (r *schemaResolver) AuditLog(ctx context.Context, args *auditLogArgs) (*auditLogConnectionResolver, error) {
+	// 🚨 SECURITY: The audit log can only be viewed by site admins.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opts := database.AuditLogListOptions{Limit: int(args.First)}
+	if args.After != nil {
+		cursor, err := strconv.ParseInt(*args.After, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid cursor")
+		}
+		opts.Cursor = cursor
+	}
+	if args.Actor != nil {
+		userID, err := UnmarshalUserID(*args.Actor)
+		if err != nil {
+			return nil, err
+		}
+		opts.ActorUserID = userID
+	}
+	if args.Actions != nil {
+		for _, action := range *args.Actions {
+			opts.Actions = append(opts.Actions, database.AuditLogAction(action))
+		}
+	}
+	if args.TargetType != nil {
+		opts.TargetType = database.AuditLogTargetType(*args.TargetType)
+	}
+	if args.TargetID != nil {
+		opts.TargetID = *args.TargetID
+	}
+	if args.Since != nil {
+		opts.Since = &args.Since.Time
+	}
+	if args.Until != nil {
+		opts.Until = &args.Until.Time
+	}
+
+	return &auditLogConnectionResolver{db: r.db, opts: opts}, nil
+}
+
+type auditLogConnectionResolver struct {
+	db   dbutil.DB
+	opts database.AuditLogListOptions
+
+	// cache results because they are used by multiple fields
+	once    sync.Once
+	entries []*database.AuditLogEntry
+	next    int64
+	err     error
+}
+
+func (r *auditLogConnectionResolver) compute(ctx context.Context) ([]*database.AuditLogEntry, int64, error) {
+	r.once.Do(func() {
+		opts := r.opts
+		if opts.Limit > 0 {
+			opts.Limit++ // so we can detect if there is a next page
+		}
+
+		r.entries, r.err = database.AuditLogs(r.db).List(ctx, opts)
+		if r.err == nil && r.opts.Limit > 0 && len(r.entries) > r.opts.Limit {
+			r.entries = r.entries[:r.opts.Limit]
+			r.next = r.entries[len(r.entries)-1].ID
+		}
+	})
+	return r.entries, r.next, r.err
+}
+
+func (r *auditLogConnectionResolver) Nodes(ctx context.Context) ([]*auditLogEntryResolver, error) {
+	entries, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*auditLogEntryResolver, 0, len(entries))
+	for _, e := range entries {
+		resolvers = append(resolvers, &auditLogEntryResolver{db: r.db, entry: e})
+	}
+	return resolvers, nil
+}
+
+func (r *auditLogConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := database.AuditLogs(r.db).Count(ctx, r.opts)
+	return int32(count), err
+}
+
+func (r *auditLogConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if next != 0 {
+		return graphqlutil.NextPageCursor(strconv.FormatInt(next, 10)), nil
+	}
+	return graphqlutil.HasNextPage(false), nil
+}
+
+type auditLogEntryResolver struct {
+	db    dbutil.DB
+	entry *database.AuditLogEntry
+}
+
+func (r *auditLogEntryResolver) CreatedAt() DateTime {
+	return DateTime{Time: r.entry.CreatedAt}
+}
+
+func (r *auditLogEntryResolver) Actor(ctx context.Context) (*UserResolver, error) {
+	if r.entry.ActorUserID == 0 {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.db, r.entry.ActorUserID)
+	if err != nil && errcode.IsNotFound(err) {
+		// Don't throw an error if a user has been deleted.
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *auditLogEntryResolver) ActorInternal() bool {
+	return r.entry.ActorInternal
+}
+
+func (r *auditLogEntryResolver) Action() string {
+	return string(r.entry.Action)
+}
+
+func (r *auditLogEntryResolver) TargetType() string {
+	return string(r.entry.TargetType)
+}
+
+func (r *auditLogEntryResolver) TargetID() string {
+	return r.entry.TargetID
+}
+
+func (r *auditLogEntryResolver) Before() (*JSONValue, error) {
+	return auditLogStateValue(r.entry.Before)
+}
+
+func (r *auditLogEntryResolver) After() (*JSONValue, error) {
+	return auditLogStateValue(r.entry.After)
+}
+
+func (r *auditLogEntryResolver) Diff() (string, error) {
+	return r.entry.Diff()
+}
+
+func (r *auditLogEntryResolver) RemoteAddr() string {
+	return r.entry.RemoteAddr
+}
+
+func (r *auditLogEntryResolver) ForwardedFor() string {
+	return r.entry.ForwardedFor
+}
+
+func (r *auditLogEntryResolver) UserAgent() string {
+	return r.entry.UserAgent
+}
+
+func (r *auditLogEntryResolver) Version() string {
+	return r.entry.Version
+}
+
+func auditLogStateValue(state json.RawMessage) (*JSONValue, error) {
+	if len(state) == 0 {
+		return nil, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(state, &v); err != nil {
+		return nil, err
+	}
+	return &JSONValue{Value: v}, nil
+}
@@ -100,14 +100,16 @@ type CampaignsCredentialResolver interface {
 }
 
 type CreateBatchChangeArgs struct {
-	BatchSpec         graphql.ID
-	PublicationStates *[]ChangesetSpecPublicationStateInput
+	BatchSpec             graphql.ID
+	PublicationStates     *[]ChangesetSpecPublicationStateInput
+	AllowFailedWorkspaces bool
 }
 
 type ApplyBatchChangeArgs struct {
-	BatchSpec         graphql.ID
-	EnsureBatchChange *graphql.ID
-	PublicationStates *[]ChangesetSpecPublicationStateInput
+	BatchSpec             graphql.ID
+	EnsureBatchChange     *graphql.ID
+	PublicationStates     *[]ChangesetSpecPublicationStateInput
+	AllowFailedWorkspaces bool
 }
 
 type ChangesetSpecPublicationStateInput struct {
@@ -188,6 +190,7 @@ type CreateBatchChangesCredentialArgs struct {
 	ExternalServiceKind string
 	ExternalServiceURL  string
 	User                *graphql.ID
+	Username            *string
 	Credential          string
 }
 
@@ -241,6 +244,15 @@ type CreateBatchSpecExecutionArgs struct {
 	Namespace *graphql.ID
 }
 
+type ExecuteBatchSpecArgs struct {
+	Spec      string
+	Namespace *graphql.ID
+}
+
+type RetryBatchSpecWorkspaceArgs struct {
+	Workspace graphql.ID
+}
+
 type CloseChangesetsArgs struct {
 	BulkOperationBaseArgs
 }
@@ -250,6 +262,33 @@ type PublishChangesetsArgs struct {
 	Draft bool
 }
 
+type SetBatchChangeAutoMergePolicyArgs struct {
+	BatchChange graphql.ID
+	Policy      *BatchChangeAutoMergePolicyInput
+}
+
+type BatchChangeAutoMergePolicyInput struct {
+	RequiredCheckState  *string
+	RequiredReviewState *string
+	Squash              bool
+	MergeWindow         *ChangesetMergeWindowInput
+}
+
+type ChangesetMergeWindowInput struct {
+	Start    string
+	End      string
+	Weekdays *[]string
+}
+
+type PauseBatchChangeRolloutArgs struct {
+	BatchChange graphql.ID
+}
+
+type ResumeBatchChangeRolloutArgs struct {
+	BatchChange     graphql.ID
+	ReleaseNextWave bool
+}
+
 type BatchChangesResolver interface {
 	//
 	// MUTATIONS
@@ -283,6 +322,11 @@ type BatchChangesResolver interface {
 	CreateBatchSpecExecution(ctx context.Context, args *CreateBatchSpecExecutionArgs) (BatchSpecExecutionResolver, error)
 	CloseChangesets(ctx context.Context, args *CloseChangesetsArgs) (BulkOperationResolver, error)
 	PublishChangesets(ctx context.Context, args *PublishChangesetsArgs) (BulkOperationResolver, error)
+	SetBatchChangeAutoMergePolicy(ctx context.Context, args *SetBatchChangeAutoMergePolicyArgs) (BatchChangeResolver, error)
+	PauseBatchChangeRollout(ctx context.Context, args *PauseBatchChangeRolloutArgs) (BatchChangeResolver, error)
+	ResumeBatchChangeRollout(ctx context.Context, args *ResumeBatchChangeRolloutArgs) (BatchChangeResolver, error)
+	ExecuteBatchSpec(ctx context.Context, args *ExecuteBatchSpecArgs) (BatchSpecResolver, error)
+	RetryBatchSpecWorkspace(ctx context.Context, args *RetryBatchSpecWorkspaceArgs) (BatchSpecWorkspaceResolver, error)
 
 	// Queries
 
@@ -352,6 +396,8 @@ type BatchSpecResolver interface {
 
 	ViewerBatchChangesCodeHosts(ctx context.Context, args *ListViewerBatchChangesCodeHostsArgs) (BatchChangesCodeHostConnectionResolver, error)
 
+	WorkspaceResolution(ctx context.Context) (BatchSpecWorkspaceResolutionResolver, error)
+
 	// TODO(campaigns-deprecation)
 	// Defined so that BatchSpecResolver can act as a CampaignSpec:
 	AppliesToCampaign(ctx context.Context) (BatchChangeResolver, error)
@@ -363,6 +409,48 @@ type BatchSpecResolver interface {
 	ActAsCampaignSpec() bool
 }
 
+type BatchSpecWorkspaceResolutionResolver interface {
+	State() string
+	StartedAt() *DateTime
+	FinishedAt() *DateTime
+	FailureMessage() *string
+	Workspaces(ctx context.Context, args *ListBatchSpecWorkspacesArgs) (BatchSpecWorkspaceConnectionResolver, error)
+}
+
+type ListBatchSpecWorkspacesArgs struct {
+	First int32
+	After *string
+}
+
+type BatchSpecWorkspaceConnectionResolver interface {
+	Nodes(ctx context.Context) ([]BatchSpecWorkspaceResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+	Stats(ctx context.Context) (BatchSpecWorkspacesStatsResolver, error)
+}
+
+type BatchSpecWorkspacesStatsResolver interface {
+	Queued() int32
+	Processing() int32
+	Completed() int32
+	Failed() int32
+}
+
+type BatchSpecWorkspaceResolver interface {
+	ID() graphql.ID
+	Repository(ctx context.Context) (*RepositoryResolver, error)
+	Branch() string
+	Commit() string
+	Path() string
+	OnlyFetchWorkspace() bool
+	State() string
+	StartedAt() *DateTime
+	FinishedAt() *DateTime
+	FailureMessage() *string
+	ExecutionLogs() []ExecutionLogEntryResolver
+	ChangesetSpecs(ctx context.Context) ([]ChangesetSpecResolver, error)
+}
+
 type BatchChangeDescriptionResolver interface {
 	Name() string
 	Description() string
@@ -594,6 +682,8 @@ type BatchChangeResolver interface {
 	DiffStat(ctx context.Context) (*DiffStat, error)
 	CurrentSpec(ctx context.Context) (BatchSpecResolver, error)
 	BulkOperations(ctx context.Context, args *ListBatchChangeBulkOperationArgs) (BulkOperationConnectionResolver, error)
+	AutoMergePolicy(ctx context.Context) (BatchChangeAutoMergePolicyResolver, error)
+	Rollout(ctx context.Context) (BatchChangeRolloutResolver, error)
 
 	// TODO(campaigns-deprecation): This should be removed once we remove batches.
 	// It's here so that in the NodeResolver we can have the same resolver,
@@ -601,6 +691,40 @@ type BatchChangeResolver interface {
 	ActAsCampaign() bool
 }
 
+type BatchChangeAutoMergePolicyResolver interface {
+	// RequiredCheckState returns a value of type *btypes.ChangesetCheckState.
+	RequiredCheckState() *string
+	// RequiredReviewState returns a value of type *btypes.ChangesetReviewState.
+	RequiredReviewState() *string
+	Squash() bool
+	MergeWindow() ChangesetMergeWindowResolver
+}
+
+type ChangesetMergeWindowResolver interface {
+	Start() string
+	End() string
+	Weekdays() []string
+}
+
+type BatchChangeRolloutResolver interface {
+	CurrentWave() int32
+	Paused() bool
+	PausedAt() *DateTime
+	PauseReason() *string
+	MinSuccessRatio() float64
+	PauseOnFailure() bool
+	Waves() []BatchChangeRolloutWaveResolver
+}
+
+type BatchChangeRolloutWaveResolver interface {
+	Index() int32
+	Released() bool
+	Total() int32
+	Succeeded() int32
+	Failed() int32
+	Pending() int32
+}
+
 type BatchChangesConnectionResolver interface {
 	Nodes(ctx context.Context) ([]BatchChangeResolver, error)
 	TotalCount(ctx context.Context) (int32, error)
@@ -700,11 +824,25 @@ type ExternalChangesetResolver interface {
 
 	Error() *string
 	SyncerError() *string
+	AutoMergeBlockedReason() *string
+	RolloutWave() *int32
+	HeldByRollout() bool
+	Dependencies(ctx context.Context) ([]ChangesetDependencyResolver, error)
+	HeldByDependencies() bool
 	ScheduleEstimateAt(ctx context.Context) (*DateTime, error)
 
 	CurrentSpec(ctx context.Context) (VisibleChangesetSpecResolver, error)
 }
 
+type ChangesetDependencyResolver interface {
+	RepositoryName() string
+	Branch() *string
+	BatchChangeName() *string
+	ExternalID() *string
+	Changeset() ChangesetResolver
+	Merged() *bool
+}
+
 type ChangesetEventsConnectionResolver interface {
 	Nodes(ctx context.Context) ([]ChangesetEventResolver, error)
 	TotalCount(ctx context.Context) (int32, error)
@@ -73,6 +73,8 @@ type MonitorActionConnectionResolver interface {
 
 type MonitorAction interface {
 	ToMonitorEmail() (MonitorEmailResolver, bool)
+	ToMonitorWebhook() (MonitorWebhookResolver, bool)
+	ToMonitorSlackWebhook() (MonitorSlackWebhookResolver, bool)
 }
 
 type MonitorEmailResolver interface {
@@ -84,6 +86,22 @@ type MonitorEmailResolver interface {
 	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
 }
 
+type MonitorWebhookResolver interface {
+	ID() graphql.ID
+	Enabled() bool
+	URL() string
+	PayloadTemplate() *string
+	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
+}
+
+type MonitorSlackWebhookResolver interface {
+	ID() graphql.ID
+	Enabled() bool
+	URL() string
+	PayloadTemplate() *string
+	Events(ctx context.Context, args *ListEventsArgs) (MonitorActionEventConnectionResolver, error)
+}
+
 type MonitorEmailRecipient interface {
 	ToUser() (*UserResolver, bool)
 }
@@ -138,7 +156,9 @@ type CreateTriggerArgs struct {
 }
 
 type CreateActionArgs struct {
-	Email *CreateActionEmailArgs
+	Email        *CreateActionEmailArgs
+	Webhook      *CreateActionWebhookArgs
+	SlackWebhook *CreateActionSlackWebhookArgs
 }
 
 type CreateActionEmailArgs struct {
@@ -148,6 +168,18 @@ type CreateActionEmailArgs struct {
 	Header     string
 }
 
+type CreateActionWebhookArgs struct {
+	Enabled         bool
+	URL             string
+	PayloadTemplate *string
+}
+
+type CreateActionSlackWebhookArgs struct {
+	Enabled         bool
+	URL             string
+	PayloadTemplate *string
+}
+
 type ToggleCodeMonitorArgs struct {
 	Id      graphql.ID
 	Enabled bool
@@ -178,8 +210,20 @@ type EditActionEmailArgs struct {
 	Update *CreateActionEmailArgs
 }
 
+type EditActionWebhookArgs struct {
+	Id     *graphql.ID
+	Update *CreateActionWebhookArgs
+}
+
+type EditActionSlackWebhookArgs struct {
+	Id     *graphql.ID
+	Update *CreateActionSlackWebhookArgs
+}
+
 type EditActionArgs struct {
-	Email *EditActionEmailArgs
+	Email        *EditActionEmailArgs
+	Webhook      *EditActionWebhookArgs
+	SlackWebhook *EditActionSlackWebhookArgs
 }
 
 type EditTriggerArgs struct {
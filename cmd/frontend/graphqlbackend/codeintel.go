@@ -22,6 +22,7 @@ type CodeIntelResolver interface {
 	IndexConfiguration(ctx context.Context, id graphql.ID) (IndexConfigurationResolver, error) // TODO - rename ...ForRepo
 	UpdateRepositoryIndexConfiguration(ctx context.Context, args *UpdateRepositoryIndexConfigurationArgs) (*EmptyResponse, error)
 	CommitGraph(ctx context.Context, id graphql.ID) (CodeIntelligenceCommitGraphResolver, error)
+	CodeIntelligenceFreshness(ctx context.Context, id graphql.ID, path string) ([]CodeIntelligenceBranchFreshnessResolver, error)
 	QueueAutoIndexJobForRepo(ctx context.Context, args *struct{ Repository graphql.ID }) (*EmptyResponse, error)
 	GitBlobLSIFData(ctx context.Context, args *GitBlobLSIFDataArgs) (GitBlobLSIFDataResolver, error)
 
@@ -144,6 +145,14 @@ type CodeIntelligenceCommitGraphResolver interface {
 	UpdatedAt(ctx context.Context) (*DateTime, error)
 }
 
+type CodeIntelligenceBranchFreshnessResolver interface {
+	BranchName() string
+	IsDefaultBranch() bool
+	Commit() string
+	Distance() *int32
+	Indexers() []string
+}
+
 type GitBlobLSIFDataResolver interface {
 	GitTreeLSIFDataResolver
 	ToGitTreeLSIFData() (GitTreeLSIFDataResolver, bool)
@@ -152,16 +161,19 @@ type GitBlobLSIFDataResolver interface {
 	Ranges(ctx context.Context, args *LSIFRangesArgs) (CodeIntelligenceRangeConnectionResolver, error)
 	Definitions(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error)
 	References(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
+	Implementations(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
 	Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error)
 	Documentation(ctx context.Context, args *LSIFQueryPositionArgs) (DocumentationResolver, error)
+	Precise() bool
 }
 
 type GitBlobLSIFDataArgs struct {
-	Repo      *types.Repo
-	Commit    api.CommitID
-	Path      string
-	ExactPath bool
-	ToolName  string
+	Repo                *types.Repo
+	Commit              api.CommitID
+	Path                string
+	ExactPath           bool
+	ToolName            string
+	SearchBasedFallback bool
 }
 
 type LSIFRangesArgs struct {
@@ -49,7 +49,7 @@ type InsightSeriesResolver interface {
 type InsightResolver interface {
 	Title() string
 	Description() string
-	Series() []InsightSeriesResolver
+	Series(ctx context.Context) ([]InsightSeriesResolver, error)
 	ID() string
 }
 
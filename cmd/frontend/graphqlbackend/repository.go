@@ -365,6 +365,10 @@ func (r *RepositoryResolver) CodeIntelligenceCommitGraph(ctx context.Context) (C
 	return EnterpriseResolvers.codeIntelResolver.CommitGraph(ctx, r.ID())
 }
 
+func (r *RepositoryResolver) CodeIntelligenceFreshness(ctx context.Context, args *struct{ Path string }) ([]CodeIntelligenceBranchFreshnessResolver, error) {
+	return EnterpriseResolvers.codeIntelResolver.CodeIntelligenceFreshness(ctx, r.ID(), args.Path)
+}
+
 type AuthorizedUserArgs struct {
 	RepositoryID graphql.ID
 	Permission   string
@@ -499,6 +499,8 @@ func (r *searchResolver) toRepoOptions(q query.Q, opts resolveRepositoriesOpts)
 	visibility := query.ParseVisibility(visibilityStr)
 
 	commitAfter, _ := q.StringValue(query.FieldRepoHasCommitAfter)
+	descriptionPatterns, _ := q.RegexpPatterns(query.FieldRepoHasDescription)
+	topics, _ := q.StringValues(query.FieldRepoHasTopic)
 	searchContextSpec, _ := q.StringValue(query.FieldContext)
 
 	var versionContextName string
@@ -513,23 +515,25 @@ func (r *searchResolver) toRepoOptions(q query.Q, opts resolveRepositoriesOpts)
 	}
 
 	return search.RepoOptions{
-		RepoFilters:        repoFilters,
-		MinusRepoFilters:   minusRepoFilters,
-		RepoGroupFilters:   repoGroupFilters,
-		VersionContextName: versionContextName,
-		SearchContextSpec:  searchContextSpec,
-		UserSettings:       r.UserSettings,
-		OnlyForks:          fork == query.Only,
-		NoForks:            fork == query.No,
-		OnlyArchived:       archived == query.Only,
-		NoArchived:         archived == query.No,
-		OnlyPrivate:        visibility == query.Private,
-		OnlyPublic:         visibility == query.Public,
-		CommitAfter:        commitAfter,
-		Query:              q,
-		Ranked:             true,
-		Limit:              opts.limit,
-		CacheLookup:        CacheLookup,
+		RepoFilters:         repoFilters,
+		MinusRepoFilters:    minusRepoFilters,
+		RepoGroupFilters:    repoGroupFilters,
+		VersionContextName:  versionContextName,
+		SearchContextSpec:   searchContextSpec,
+		UserSettings:        r.UserSettings,
+		OnlyForks:           fork == query.Only,
+		NoForks:             fork == query.No,
+		OnlyArchived:        archived == query.Only,
+		NoArchived:          archived == query.No,
+		OnlyPrivate:         visibility == query.Private,
+		OnlyPublic:          visibility == query.Public,
+		CommitAfter:         commitAfter,
+		DescriptionPatterns: descriptionPatterns,
+		Topics:              topics,
+		Query:               q,
+		Ranked:              true,
+		Limit:               opts.limit,
+		CacheLookup:         CacheLookup,
 	}
 }
 
@@ -69,6 +69,9 @@ func NewHandler(db dbutil.DB) http.Handler {
 	// Usage statistics ZIP download
 	r.Get(router.UsageStatsDownload).Handler(trace.Route(http.HandlerFunc(usageStatsArchiveHandler(db))))
 
+	// Audit log JSON lines export
+	r.Get(router.AuditLogExport).Handler(trace.Route(http.HandlerFunc(auditLogExportHandler(db))))
+
 	// Ping retrieval
 	r.Get(router.LatestPing).Handler(trace.Route(http.HandlerFunc(latestPingHandler(db))))
 
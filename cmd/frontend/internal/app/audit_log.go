@@ -0,0 +1,100 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// auditLogExportPageSize is the number of audit log entries read from the
+// database at a time while exporting.
+const auditLogExportPageSize = 1000
+
+// auditLogExportHandler writes the audit log entries matching the filters in
+// the query string as JSON lines, oldest first. The supported filters are
+// "actor" (a numeric user ID), "action" (which may be repeated), "targetType",
+// "targetID", and "since" and "until" (RFC 3339 timestamps).
+func auditLogExportHandler(db dbutil.DB) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 🚨SECURITY: Only site admins may export the audit log.
+		if err := backend.CheckCurrentUserIsSiteAdmin(r.Context(), db); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		opts, err := parseAuditLogExportQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		opts.Ascending = true
+		opts.Limit = auditLogExportPageSize
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"SourcegraphAuditLog.jsonl\"")
+
+		enc := json.NewEncoder(w)
+		store := database.AuditLogs(db)
+		for {
+			entries, err := store.List(r.Context(), opts)
+			if err != nil {
+				// The status code has likely been sent already, so the best
+				// we can do is to truncate the export.
+				log15.Error("Failed to export audit log.", "error", err)
+				return
+			}
+			for _, e := range entries {
+				if err := enc.Encode(e); err != nil {
+					return
+				}
+			}
+			if len(entries) < opts.Limit {
+				return
+			}
+			opts.Cursor = entries[len(entries)-1].ID
+		}
+	}
+}
+
+func parseAuditLogExportQuery(q url.Values) (opts database.AuditLogListOptions, err error) {
+	if v := q.Get("actor"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return opts, errors.Errorf("invalid actor %q", v)
+		}
+		opts.ActorUserID = int32(id)
+	}
+	for _, action := range q["action"] {
+		opts.Actions = append(opts.Actions, database.AuditLogAction(action))
+	}
+	opts.TargetType = database.AuditLogTargetType(q.Get("targetType"))
+	opts.TargetID = q.Get("targetID")
+
+	parseTime := func(name string) (*time.Time, error) {
+		v := q.Get(name)
+		if v == "" {
+			return nil, nil
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, errors.Errorf("invalid %s %q, expected an RFC 3339 timestamp", name, v)
+		}
+		return &t, nil
+	}
+	if opts.Since, err = parseTime("since"); err != nil {
+		return opts, err
+	}
+	if opts.Until, err = parseTime("until"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
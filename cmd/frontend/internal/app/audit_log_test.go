@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestAuditLogExportHandler(t *testing.T) {
+	t.Run("non-admins can't export the audit log", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/site-admin/audit-log/export", nil)
+		rec := httptest.NewRecorder()
+		auditLogExportHandler(new(dbtesting.MockDB))(rec, req)
+
+		if have, want := rec.Code, http.StatusUnauthorized; have != want {
+			t.Errorf("status code: have %d, want %d", have, want)
+		}
+	})
+
+	t.Run("admins can export the audit log", func(t *testing.T) {
+		var calls []database.AuditLogListOptions
+		database.Mocks.AuditLogs.List = func(ctx context.Context, opts database.AuditLogListOptions) ([]*database.AuditLogEntry, error) {
+			calls = append(calls, opts)
+			if opts.Cursor != 0 {
+				return nil, nil
+			}
+			entries := make([]*database.AuditLogEntry, opts.Limit)
+			for i := range entries {
+				entries[i] = &database.AuditLogEntry{ID: int64(i + 1), Action: database.AuditLogActionOrgMemberAdded}
+			}
+			return entries, nil
+		}
+		defer func() { database.Mocks.AuditLogs.List = nil }()
+
+		req, _ := http.NewRequest("GET", "/site-admin/audit-log/export?actor=1&action=OrgMemberAdded&since=2021-08-01T00:00:00Z", nil)
+		rec := httptest.NewRecorder()
+		auditLogExportHandler(new(dbtesting.MockDB))(rec, req.WithContext(backend.WithAuthzBypass(context.Background())))
+
+		if have, want := rec.Header().Get("Content-Type"), "application/x-ndjson"; have != want {
+			t.Errorf("Content-Type: have %q, want %q", have, want)
+		}
+		if have, want := len(calls), 2; have != want {
+			t.Fatalf("unexpected number of pages: have %d, want %d", have, want)
+		}
+		if have, want := calls[1].Cursor, int64(auditLogExportPageSize); have != want {
+			t.Errorf("unexpected cursor: have %d, want %d", have, want)
+		}
+		if have, want := calls[0].Actions, []database.AuditLogAction{database.AuditLogActionOrgMemberAdded}; !cmp.Equal(have, want) || calls[0].ActorUserID != 1 || calls[0].Since == nil || !calls[0].Ascending {
+			t.Errorf("unexpected options: %+v", calls[0])
+		}
+
+		var lines int
+		for _, b := range rec.Body.Bytes() {
+			if b == '\n' {
+				lines++
+			}
+		}
+		if lines != auditLogExportPageSize {
+			t.Errorf("unexpected number of lines: have %d, want %d", lines, auditLogExportPageSize)
+		}
+	})
+
+	t.Run("invalid filters are rejected", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/site-admin/audit-log/export?since=yesterday", nil)
+		rec := httptest.NewRecorder()
+		auditLogExportHandler(new(dbtesting.MockDB))(rec, req.WithContext(backend.WithAuthzBypass(context.Background())))
+
+		if have, want := rec.Code, http.StatusBadRequest; have != want {
+			t.Errorf("status code: have %d, want %d", have, want)
+		}
+	})
+}
@@ -34,6 +34,8 @@ const (
 
 	UsageStatsDownload = "usage-stats.download"
 
+	AuditLogExport = "audit-log.export"
+
 	LatestPing = "pings.latest"
 
 	OldToolsRedirect = "old-tools-redirect"
@@ -93,6 +95,8 @@ func newRouter() *mux.Router {
 
 	base.Path("/site-admin/usage-statistics/archive").Methods("GET").Name(UsageStatsDownload)
 
+	base.Path("/site-admin/audit-log/export").Methods("GET").Name(AuditLogExport)
+
 	base.Path("/site-admin/pings/latest").Methods("GET").Name(LatestPing)
 
 	if envvar.SourcegraphDotComMode() {
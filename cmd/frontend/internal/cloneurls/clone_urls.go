@@ -31,6 +31,8 @@ func ReposourceCloneURLToRepoName(ctx context.Context, db dbutil.DB, cloneURL st
 			extsvc.KindGitHub,
 			extsvc.KindGitLab,
 			extsvc.KindBitbucketServer,
+			extsvc.KindGerrit,
+			extsvc.KindGitea,
 			extsvc.KindAWSCodeCommit,
 			extsvc.KindGitolite,
 			extsvc.KindPhabricator,
@@ -68,6 +70,11 @@ func ReposourceCloneURLToRepoName(ctx context.Context, db dbutil.DB, cloneURL st
 			case *schema.BitbucketServerConnection:
 				rs = reposource.BitbucketServer{BitbucketServerConnection: c}
 				host = c.Url
+			case *schema.GerritConnection:
+				rs = reposource.Gerrit{GerritConnection: c}
+			case *schema.GiteaConnection:
+				rs = reposource.Gitea{GiteaConnection: c}
+				host = c.Url
 			case *schema.AWSCodeCommitConnection:
 				rs = reposource.AWS{AWSCodeCommitConnection: c}
 				// AWS type does not have URL
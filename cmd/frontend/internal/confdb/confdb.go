@@ -3,6 +3,7 @@ package confdb
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -11,7 +12,10 @@ import (
 	"github.com/sourcegraph/jsonx"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf/confdefaults"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 )
 
 // SiteConfig contains the contents of a site config along with associated metadata.
@@ -49,7 +53,33 @@ func SiteCreateIfUpToDate(ctx context.Context, lastID *int32, contents string) (
 		lastID = newLastID
 	}
 
-	return createIfUpToDate(ctx, tx, lastID, contents)
+	previous, err := getLatest(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err = createIfUpToDate(ctx, tx, lastID, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordSiteConfigChange(ctx, tx, previous, latest); err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// recordSiteConfigChange records the change of the site config from previous
+// (which may be nil) to latest in the audit log.
+func recordSiteConfigChange(ctx context.Context, tx dbutil.DB, previous, latest *SiteConfig) error {
+	var before, after interface{}
+	if previous != nil {
+		before = json.RawMessage(jsonc.Normalize(previous.Contents))
+	}
+	if latest != nil {
+		after = json.RawMessage(jsonc.Normalize(latest.Contents))
+	}
+	return database.AuditLogs(tx).Record(ctx, database.AuditLogActionSiteConfigUpdated, database.AuditLogTargetSiteConfig, "", before, after)
 }
 
 // SiteGetLatest returns the site config that was most recently saved to the database.
@@ -72,7 +102,7 @@ func SiteGetLatest(ctx context.Context) (latest *SiteConfig, err error) {
 	return getLatest(ctx, tx)
 }
 
-func newTransaction(ctx context.Context) (tx queryable, done func(), err error) {
+func newTransaction(ctx context.Context) (tx dbutil.DB, done func(), err error) {
 	rtx, err := dbconn.Global.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, nil, err
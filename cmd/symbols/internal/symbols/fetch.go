@@ -20,7 +20,9 @@ type parseRequest struct {
 	data []byte
 }
 
-func (s *Service) fetchRepositoryArchive(ctx context.Context, repo api.RepoName, commitID api.CommitID) (<-chan parseRequest, <-chan error, error) {
+// fetchRepositoryArchive fetches an archive of repo@commit, only including the
+// given paths if paths is non-empty, and returns the files to parse.
+func (s *Service) fetchRepositoryArchive(ctx context.Context, repo api.RepoName, commitID api.CommitID, paths []string) (<-chan parseRequest, <-chan error, error) {
 	fetchQueueSize.Inc()
 	s.fetchSem <- 1 // acquire concurrent fetches semaphore
 	fetchQueueSize.Dec()
@@ -60,8 +62,17 @@ func (s *Service) fetchRepositoryArchive(ctx context.Context, repo api.RepoName,
 		span.Finish()
 	}
 
-	r, err := s.FetchTar(ctx, repo, commitID)
+	var (
+		r   io.ReadCloser
+		err error
+	)
+	if len(paths) > 0 {
+		r, err = s.FetchTarPaths(ctx, repo, commitID, paths)
+	} else {
+		r, err = s.FetchTar(ctx, repo, commitID)
+	}
 	if err != nil {
+		done(err)
 		return nil, nil, err
 	}
 
@@ -0,0 +1,265 @@
+package symbols
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sourcegraph/go-ctags"
+)
+
+func init() {
+	RegisterNativeParser("go", []string{".go"}, newGoParser)
+}
+
+// goParser extracts symbols from Go source files using go/ast. Unlike ctags,
+// it distinguishes methods from functions, reports struct fields and interface
+// methods with their enclosing type, and resolves the kind of the receiver
+// type of methods declared in the same file.
+type goParser struct {
+	patternLengthLimit int
+}
+
+func newGoParser() (ctags.Parser, error) {
+	patternLengthLimit, err := strconv.Atoi(rawPatternLengthLimit)
+	if err != nil {
+		return nil, errors.Errorf("invalid pattern length limit: %s", rawPatternLengthLimit)
+	}
+	return &goParser{patternLengthLimit: patternLengthLimit}, nil
+}
+
+func (p *goParser) Parse(path string, content []byte) ([]*ctags.Entry, error) {
+	fset := token.NewFileSet()
+
+	// Syntax errors are not fatal: like ctags, we report the symbols of
+	// whatever could be parsed.
+	f, err := parser.ParseFile(fset, path, content, 0)
+	if f == nil {
+		return nil, err
+	}
+
+	c := &goSymbolCollector{
+		parser:    p,
+		fset:      fset,
+		path:      path,
+		lines:     bytes.Split(content, []byte("\n")),
+		typeKinds: goTypeKinds(f),
+	}
+	c.collect(f)
+	return c.entries, nil
+}
+
+func (p *goParser) Close() {}
+
+type goSymbolCollector struct {
+	parser *goParser
+	fset   *token.FileSet
+	path   string
+	lines  [][]byte
+
+	// typeKinds maps the names of the types declared in the file to their
+	// symbol kind.
+	typeKinds map[string]string
+
+	entries []*ctags.Entry
+}
+
+func (c *goSymbolCollector) collect(f *ast.File) {
+	if f.Name != nil && f.Name.Name != "" {
+		c.add(f.Name, "package", "", "", "")
+	}
+
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			c.collectFunc(d)
+		case *ast.GenDecl:
+			c.collectGenDecl(d)
+		}
+	}
+}
+
+func (c *goSymbolCollector) collectFunc(d *ast.FuncDecl) {
+	signature := c.signature(d.Type)
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		c.add(d.Name, "func", "", "", signature)
+		return
+	}
+
+	receiver := goTypeName(d.Recv.List[0].Type)
+	receiverKind, ok := c.typeKinds[receiver]
+	if !ok {
+		// The receiver type is declared in another file of the package.
+		receiverKind = "type"
+	}
+	c.add(d.Name, "method", receiver, receiverKind, signature)
+}
+
+func (c *goSymbolCollector) collectGenDecl(d *ast.GenDecl) {
+	for _, spec := range d.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			kind := c.typeKinds[s.Name.Name]
+			c.add(s.Name, kind, "", "", "")
+
+			switch t := s.Type.(type) {
+			case *ast.StructType:
+				c.collectFields(t, s.Name.Name, kind)
+			case *ast.InterfaceType:
+				c.collectMethodSpecs(t, s.Name.Name, kind)
+			}
+
+		case *ast.ValueSpec:
+			kind := "variable"
+			if d.Tok == token.CONST {
+				kind = "constant"
+			}
+			for _, name := range s.Names {
+				c.add(name, kind, "", "", "")
+			}
+		}
+	}
+}
+
+// collectFields adds the fields of the given struct type. Fields of anonymous
+// struct types are scoped to the field they belong to.
+func (c *goSymbolCollector) collectFields(t *ast.StructType, parent, parentKind string) {
+	if t.Fields == nil {
+		return
+	}
+
+	for _, field := range t.Fields.List {
+		names := field.Names
+		if len(names) == 0 {
+			// Embedded fields are named after their type.
+			if ident := goTypeIdent(field.Type); ident != nil {
+				names = []*ast.Ident{ident}
+			}
+		}
+
+		for _, name := range names {
+			c.add(name, "field", parent, parentKind, "")
+			if nested, ok := field.Type.(*ast.StructType); ok {
+				c.collectFields(nested, parent+"."+name.Name, "field")
+			}
+		}
+	}
+}
+
+// collectMethodSpecs adds the methods of the given interface type. Embedded
+// interfaces and type constraints are skipped.
+func (c *goSymbolCollector) collectMethodSpecs(t *ast.InterfaceType, parent, parentKind string) {
+	if t.Methods == nil {
+		return
+	}
+
+	for _, method := range t.Methods.List {
+		ft, ok := method.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+		for _, name := range method.Names {
+			c.add(name, "methodSpec", parent, parentKind, c.signature(ft))
+		}
+	}
+}
+
+func (c *goSymbolCollector) add(name *ast.Ident, kind, parent, parentKind, signature string) {
+	if name == nil || name.Name == "" || name.Name == "_" {
+		return
+	}
+
+	line := c.fset.Position(name.Pos()).Line
+	c.entries = append(c.entries, &ctags.Entry{
+		Name:       name.Name,
+		Path:       c.path,
+		Line:       line,
+		Kind:       kind,
+		Language:   "Go",
+		Parent:     parent,
+		ParentKind: parentKind,
+		Pattern:    c.pattern(line),
+		Signature:  signature,
+	})
+}
+
+// signature formats the parameters and results of the given function type,
+// e.g. "(ctx context.Context, id int) (*Repo, error)".
+func (c *goSymbolCollector) signature(ft *ast.FuncType) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), ft); err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(buf.String(), "func")
+}
+
+// pattern returns the source line in the format ctags uses for patterns,
+// which is what symbol ranges are computed from.
+func (c *goSymbolCollector) pattern(line int) string {
+	if line < 1 || line > len(c.lines) {
+		return ""
+	}
+
+	text := strings.TrimSuffix(string(c.lines[line-1]), "\r")
+	text = strings.NewReplacer(`\`, `\\`, `/`, `\/`).Replace(text)
+	if len(text) > c.parser.patternLengthLimit {
+		// ctags drops the end-of-line anchor of truncated patterns.
+		return "/^" + text[:c.parser.patternLengthLimit] + "/"
+	}
+	return "/^" + text + "$/"
+}
+
+// goTypeKinds returns the symbol kinds of the types declared at the top level
+// of the file, by name.
+func goTypeKinds(f *ast.File) map[string]string {
+	kinds := map[string]string{}
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range d.Specs {
+			s := spec.(*ast.TypeSpec)
+			switch s.Type.(type) {
+			case *ast.StructType:
+				kinds[s.Name.Name] = "struct"
+			case *ast.InterfaceType:
+				kinds[s.Name.Name] = "interface"
+			default:
+				kinds[s.Name.Name] = "type"
+			}
+		}
+	}
+	return kinds
+}
+
+// goTypeName returns the name of the named type in the given type expression,
+// stripping pointers and package qualifiers.
+func goTypeName(expr ast.Expr) string {
+	if ident := goTypeIdent(expr); ident != nil {
+		return ident.Name
+	}
+	return ""
+}
+
+func goTypeIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.SelectorExpr:
+			return e.Sel
+		default:
+			return nil
+		}
+	}
+}
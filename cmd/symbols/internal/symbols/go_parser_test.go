@@ -0,0 +1,99 @@
+package symbols
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/sourcegraph/go-ctags"
+)
+
+func TestGoParser(t *testing.T) {
+	p, err := newGoParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	data := `package foo
+
+const Answer = 42
+
+var _, debug = 1, false
+
+type Server struct {
+	Addr string
+	*Logger
+	opts struct {
+		Verbose bool
+	}
+}
+
+type Handler interface {
+	io.Closer
+	Handle(ctx context.Context, req *Request) error
+}
+
+type ID = string
+
+func New(addr string) *Server { return nil }
+
+func (s *Server) Serve() error { return nil }
+
+func (l List) Len() int { return 0 }
+`
+
+	want := []*ctags.Entry{
+		{Name: "foo", Line: 1, Kind: "package"},
+		{Name: "Answer", Line: 3, Kind: "constant"},
+		{Name: "debug", Line: 5, Kind: "variable"},
+		{Name: "Server", Line: 7, Kind: "struct"},
+		{Name: "Addr", Line: 8, Kind: "field", Parent: "Server", ParentKind: "struct"},
+		{Name: "Logger", Line: 9, Kind: "field", Parent: "Server", ParentKind: "struct"},
+		{Name: "opts", Line: 10, Kind: "field", Parent: "Server", ParentKind: "struct"},
+		{Name: "Verbose", Line: 11, Kind: "field", Parent: "Server.opts", ParentKind: "field"},
+		{Name: "Handler", Line: 15, Kind: "interface"},
+		{Name: "Handle", Line: 17, Kind: "methodSpec", Parent: "Handler", ParentKind: "interface", Signature: "(ctx context.Context, req *Request) error"},
+		{Name: "ID", Line: 20, Kind: "type"},
+		{Name: "New", Line: 22, Kind: "func", Signature: "(addr string) *Server"},
+		{Name: "Serve", Line: 24, Kind: "method", Parent: "Server", ParentKind: "struct", Signature: "() error"},
+		{Name: "Len", Line: 26, Kind: "method", Parent: "List", ParentKind: "type", Signature: "() int"},
+	}
+	for _, e := range want {
+		e.Path = "foo/foo.go"
+		e.Language = "Go"
+	}
+
+	got, err := p.Parse("foo/foo.go", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, got, cmpopts.IgnoreFields(ctags.Entry{}, "Pattern")); d != "" {
+		t.Errorf("mismatch (-want +got):\n%s", d)
+	}
+
+	if have, want := got[len(got)-2].Pattern, `/^func (s *Server) Serve() error { return nil }$/`; have != want {
+		t.Errorf("unexpected pattern: have %q, want %q", have, want)
+	}
+}
+
+func TestGoParser_SyntaxError(t *testing.T) {
+	p, err := newGoParser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.Parse("a.go", []byte("package a\n\nfunc A() {}\n\nfunc B( {\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, e := range got {
+		names = append(names, e.Name)
+	}
+	if d := cmp.Diff([]string{"a", "A"}, names[:2]); d != "" {
+		t.Errorf("mismatch (-want +got):\n%s", d)
+	}
+}
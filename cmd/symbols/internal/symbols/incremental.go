@@ -0,0 +1,219 @@
+package symbols
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/jmoiron/sqlx"
+	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// maxIncrementalPaths is the maximum number of changed paths for which a
+// database is derived from the database of a cached ancestor. Past this, the
+// paths no longer comfortably fit on the git archive command line, and
+// reparsing everything is not much slower anyway.
+const maxIncrementalPaths = 1000
+
+// deletePathsBatchSize is the number of paths deleted per statement, which
+// keeps us under SQLite's limit on the number of bound variables.
+const deletePathsBatchSize = 500
+
+// Changes are the paths that differ between two commits.
+type Changes struct {
+	Added    []string
+	Modified []string
+	Deleted  []string
+}
+
+// ParseGitDiffNameStatus parses the output of
+// `git diff -z --name-status --no-renames`.
+func ParseGitDiffNameStatus(output []byte) (Changes, error) {
+	var changes Changes
+
+	fields := bytes.Split(bytes.TrimSuffix(output, []byte{0}), []byte{0})
+	if len(fields) == 1 && len(fields[0]) == 0 {
+		return changes, nil
+	}
+	if len(fields)%2 != 0 {
+		return Changes{}, errors.Errorf("uneven number of fields in git diff output: %d", len(fields))
+	}
+
+	for i := 0; i < len(fields); i += 2 {
+		status, path := string(fields[i]), string(fields[i+1])
+		switch status {
+		case "A":
+			changes.Added = append(changes.Added, path)
+		case "M", "T":
+			changes.Modified = append(changes.Modified, path)
+		case "D":
+			changes.Deleted = append(changes.Deleted, path)
+		default:
+			return Changes{}, errors.Errorf("unrecognized git diff status %q for path %q", status, path)
+		}
+	}
+
+	return changes, nil
+}
+
+// incrementalUpdatesEnabled returns true if the service has been configured
+// with everything needed to derive databases from cached ancestors.
+func (s *Service) incrementalUpdatesEnabled() bool {
+	return s.FetchTarPaths != nil && s.GitDiff != nil && s.ListAncestors != nil
+}
+
+// writeSymbolsToNewDB writes the symbols of repo@commit to the blank database
+// file `dbFile`. If the database of an ancestor of the commit is cached, it is
+// copied and only the files changed since the ancestor are reparsed. Otherwise
+// all files are parsed.
+func (s *Service) writeSymbolsToNewDB(ctx context.Context, dbFile string, repoName api.RepoName, commitID api.CommitID) error {
+	ok, err := s.writeSymbolsFromCachedAncestor(ctx, dbFile, repoName, commitID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+
+		log15.Warn("Failed to update symbols from a cached ancestor, parsing all files instead.", "repo", repoName, "commitID", commitID, "error", err)
+
+		// Discard whatever was written to the database so far.
+		if err := os.Truncate(dbFile, 0); err != nil {
+			return err
+		}
+	}
+	if ok && err == nil {
+		incrementalUpdates.Inc()
+		return nil
+	}
+
+	return s.writeAllSymbolsToNewDB(ctx, dbFile, repoName, commitID)
+}
+
+// writeSymbolsFromCachedAncestor looks for the database of the nearest cached
+// ancestor of repo@commit and, if there is one, writes an updated copy of it to
+// `dbFile`. It returns false if no suitable ancestor is cached.
+func (s *Service) writeSymbolsFromCachedAncestor(ctx context.Context, dbFile string, repoName api.RepoName, commitID api.CommitID) (bool, error) {
+	if !s.incrementalUpdatesEnabled() {
+		return false, nil
+	}
+
+	ancestors, err := s.ListAncestors(ctx, repoName, commitID, s.MaxAncestorsToSearch)
+	if err != nil {
+		return false, errors.Wrap(err, "listing ancestors")
+	}
+
+	for _, ancestor := range ancestors {
+		f, err := s.cache.OpenIfExists(cacheKey(repoName, ancestor))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+
+		changes, err := s.GitDiff(ctx, repoName, ancestor, commitID)
+		if err != nil {
+			return false, errors.Wrap(err, "diffing against ancestor")
+		}
+		if len(changes.Added)+len(changes.Modified)+len(changes.Deleted) > maxIncrementalPaths {
+			return false, nil
+		}
+
+		log15.Debug("Updating symbols from cached ancestor.", "repo", repoName, "commitID", commitID, "ancestor", ancestor)
+		return true, s.updateSymbolsInDB(ctx, dbFile, f.File, repoName, commitID, changes)
+	}
+
+	return false, nil
+}
+
+// updateSymbolsInDB copies the ancestor database to `dbFile`, removes the
+// symbols of the changed and deleted paths, and inserts the symbols of the
+// changed paths as of repo@commit.
+func (s *Service) updateSymbolsInDB(ctx context.Context, dbFile string, ancestorDB io.Reader, repoName api.RepoName, commitID api.CommitID, changes Changes) error {
+	if err := copyToFile(dbFile, ancestorDB); err != nil {
+		return errors.Wrap(err, "copying ancestor database")
+	}
+
+	db, err := sqlx.Open("sqlite3_with_pcre", dbFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Writing a bunch of rows into sqlite3 is much faster in a transaction.
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	paths := append(append([]string{}, changes.Added...), changes.Modified...)
+
+	if err := deleteSymbolsForPaths(tx, append(append([]string{}, paths...), changes.Deleted...)); err != nil {
+		return err
+	}
+
+	if len(paths) > 0 {
+		insertStatement, err := prepareInsertSymbolStatement(tx)
+		if err != nil {
+			return err
+		}
+
+		err = s.parseUncached(ctx, repoName, commitID, paths, func(symbol result.Symbol) error {
+			symbolInDBValue := symbolToSymbolInDB(symbol)
+			_, err := insertStatement.Exec(&symbolInDBValue)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func deleteSymbolsForPaths(tx *sqlx.Tx, paths []string) error {
+	for len(paths) > 0 {
+		n := deletePathsBatchSize
+		if n > len(paths) {
+			n = len(paths)
+		}
+
+		conds := make([]*sqlf.Query, 0, n)
+		for _, path := range paths[:n] {
+			conds = append(conds, sqlf.Sprintf("%s", path))
+		}
+		q := sqlf.Sprintf("DELETE FROM symbols WHERE path IN (%s)", sqlf.Join(conds, ","))
+		if _, err := tx.Exec(q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+			return err
+		}
+
+		paths = paths[n:]
+	}
+	return nil
+}
+
+func copyToFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+var incrementalUpdates = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "symbols_store_incremental_updates",
+	Help: "The total number of databases derived from the database of a cached ancestor.",
+})
@@ -0,0 +1,124 @@
+package symbols
+
+import (
+	"context"
+	"io"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/go-ctags"
+
+	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/protocol"
+	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/sqliteutil"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseGitDiffNameStatus(t *testing.T) {
+	changes, err := ParseGitDiffNameStatus([]byte("A\x00added.go\x00M\x00modified.go\x00T\x00typechanged.go\x00D\x00deleted.go\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Changes{
+		Added:    []string{"added.go"},
+		Modified: []string{"modified.go", "typechanged.go"},
+		Deleted:  []string{"deleted.go"},
+	}
+	if diff := cmp.Diff(want, changes); diff != "" {
+		t.Errorf("unexpected changes (-want +got):\n%s", diff)
+	}
+
+	if changes, err := ParseGitDiffNameStatus(nil); err != nil || len(changes.Added)+len(changes.Modified)+len(changes.Deleted) != 0 {
+		t.Errorf("unexpected result for empty diff: %+v, %v", changes, err)
+	}
+
+	if _, err := ParseGitDiffNameStatus([]byte("R100\x00old.go\x00")); err == nil {
+		t.Error("expected error for unknown status")
+	}
+}
+
+func TestService_Incremental(t *testing.T) {
+	sqliteutil.MustRegisterSqlite3WithPcre()
+
+	tmpDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { os.RemoveAll(tmpDir) }()
+
+	// The parser emits a single symbol per file, named after the file content.
+	files := map[api.CommitID]map[string]string{
+		"parent": {"a.js": "x", "b.js": "y"},
+		"child":  {"a.js": "z", "c.js": "w"},
+	}
+
+	var fullFetches int
+	var fetchedPaths []string
+	service := Service{
+		FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
+			fullFetches++
+			return createTar(files[commit])
+		},
+		FetchTarPaths: func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			fetchedPaths = append(fetchedPaths, paths...)
+			subset := map[string]string{}
+			for _, path := range paths {
+				subset[path] = files[commit][path]
+			}
+			return createTar(subset)
+		},
+		GitDiff: func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) (Changes, error) {
+			return Changes{Added: []string{"c.js"}, Modified: []string{"a.js"}, Deleted: []string{"b.js"}}, nil
+		},
+		ListAncestors: func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error) {
+			if commit == "child" {
+				return []api.CommitID{"parent"}, nil
+			}
+			return nil, nil
+		},
+		NewParser: func() (ctags.Parser, error) {
+			return contentParser{}, nil
+		},
+		Path: tmpDir,
+	}
+	if err := service.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(commit api.CommitID) []string {
+		res, err := service.search(context.Background(), protocol.SearchArgs{Repo: "r", CommitID: commit, First: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, symbol := range *res {
+			names = append(names, symbol.Path+":"+symbol.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	if diff := cmp.Diff([]string{"a.js:x", "b.js:y"}, search("parent")); diff != "" {
+		t.Errorf("unexpected parent symbols (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"a.js:z", "c.js:w"}, search("child")); diff != "" {
+		t.Errorf("unexpected child symbols (-want +got):\n%s", diff)
+	}
+
+	if fullFetches != 1 {
+		t.Errorf("expected only the parent to be fetched in full, got %d full fetches", fullFetches)
+	}
+	if diff := cmp.Diff([]string{"c.js", "a.js"}, fetchedPaths); diff != "" {
+		t.Errorf("unexpected fetched paths (-want +got):\n%s", diff)
+	}
+}
+
+type contentParser struct{}
+
+func (contentParser) Parse(name string, content []byte) ([]*ctags.Entry, error) {
+	return []*ctags.Entry{{Name: string(content), Path: name}}, nil
+}
+
+func (contentParser) Close() {}
@@ -39,7 +39,9 @@ func (s *Service) startParsers() error {
 	return nil
 }
 
-func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID api.CommitID, callback func(symbol result.Symbol) error) (err error) {
+// parseUncached fetches and parses the files of repo@commit. If paths is
+// non-empty, only the given paths are fetched and parsed.
+func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID api.CommitID, paths []string, callback func(symbol result.Symbol) error) (err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "parseUncached")
 	defer func() {
 		if err != nil {
@@ -50,6 +52,7 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 	}()
 	span.SetTag("repo", string(repo))
 	span.SetTag("commit", string(commitID))
+	span.SetTag("paths", len(paths))
 
 	tr := nettrace.New("parseUncached", string(repo))
 	tr.LazyPrintf("commitID: %s", commitID)
@@ -65,7 +68,7 @@ func (s *Service) parseUncached(ctx context.Context, repo api.RepoName, commitID
 	}()
 
 	tr.LazyPrintf("fetch")
-	parseRequests, errChan, err := s.fetchRepositoryArchive(ctx, repo, commitID)
+	parseRequests, errChan, err := s.fetchRepositoryArchive(ctx, repo, commitID, paths)
 	tr.LazyPrintf("fetch (returned chans)")
 	if err != nil {
 		return err
@@ -0,0 +1,132 @@
+package symbols
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sourcegraph/go-ctags"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+var rawNativeParserLanguages = env.Get("SYMBOLS_NATIVE_PARSERS", "", "comma-separated list of languages parsed by built-in parsers instead of ctags, such as \"go\"")
+
+// nativeParser is a parser for a single language that runs in-process rather
+// than shelling out to ctags.
+type nativeParser struct {
+	// extensions are the file extensions (including the leading dot) handled
+	// by the parser.
+	extensions []string
+
+	// new returns a new instance of the parser. Parsers are pooled by the
+	// service, so an instance is never used concurrently.
+	new func() (ctags.Parser, error)
+}
+
+// nativeParsers maps lower-cased language names to their parser. It is only
+// written to during package initialization.
+var nativeParsers = map[string]nativeParser{}
+
+// RegisterNativeParser registers a parser for the given language, which is
+// used for files with the given extensions when the language is enabled via
+// SYMBOLS_NATIVE_PARSERS. Languages are matched case-insensitively.
+//
+// RegisterNativeParser must be called from an init function, and panics if a
+// parser is already registered for the language.
+func RegisterNativeParser(language string, extensions []string, newParser func() (ctags.Parser, error)) {
+	language = strings.ToLower(language)
+	if _, ok := nativeParsers[language]; ok {
+		panic("symbols: native parser already registered for language " + language)
+	}
+	nativeParsers[language] = nativeParser{extensions: extensions, new: newParser}
+}
+
+// NativeParserLanguages returns the sorted list of languages for which a native
+// parser is registered.
+func NativeParserLanguages() []string {
+	languages := make([]string, 0, len(nativeParsers))
+	for language := range nativeParsers {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// NewParser returns a parser that uses the native parsers enabled via
+// SYMBOLS_NATIVE_PARSERS for the languages they support and ctags for
+// everything else. The ctags process is only started once a file that no
+// native parser handles is parsed.
+func NewParser() (ctags.Parser, error) {
+	return newParser(parseLanguageList(rawNativeParserLanguages), NewCtagsParser)
+}
+
+func newParser(languages []string, newFallback func() (ctags.Parser, error)) (ctags.Parser, error) {
+	p := &languageParser{
+		byExtension: map[string]ctags.Parser{},
+		newFallback: newFallback,
+	}
+	for _, language := range languages {
+		np, ok := nativeParsers[language]
+		if !ok {
+			p.Close()
+			return nil, errors.Errorf("no native parser registered for language %q (available: %s)", language, strings.Join(NativeParserLanguages(), ", "))
+		}
+
+		parser, err := np.new()
+		if err != nil {
+			p.Close()
+			return nil, errors.Wrapf(err, "creating native parser for language %q", language)
+		}
+		p.parsers = append(p.parsers, parser)
+		for _, ext := range np.extensions {
+			p.byExtension[ext] = parser
+		}
+	}
+	return p, nil
+}
+
+func parseLanguageList(raw string) []string {
+	var languages []string
+	for _, language := range strings.Split(raw, ",") {
+		if language = strings.ToLower(strings.TrimSpace(language)); language != "" {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
+
+// languageParser dispatches each file to the native parser registered for its
+// extension, falling back to a lazily started ctags parser.
+type languageParser struct {
+	parsers     []ctags.Parser
+	byExtension map[string]ctags.Parser
+
+	newFallback func() (ctags.Parser, error)
+	fallback    ctags.Parser
+}
+
+func (p *languageParser) Parse(name string, content []byte) ([]*ctags.Entry, error) {
+	if parser, ok := p.byExtension[path.Ext(name)]; ok {
+		return parser.Parse(name, content)
+	}
+
+	if p.fallback == nil {
+		fallback, err := p.newFallback()
+		if err != nil {
+			return nil, err
+		}
+		p.fallback = fallback
+	}
+	return p.fallback.Parse(name, content)
+}
+
+func (p *languageParser) Close() {
+	for _, parser := range p.parsers {
+		parser.Close()
+	}
+	if p.fallback != nil {
+		p.fallback.Close()
+	}
+}
@@ -0,0 +1,73 @@
+package symbols
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/go-ctags"
+)
+
+func TestNewParser(t *testing.T) {
+	var fallbackStarted, fallbackParsed int
+	newFallback := func() (ctags.Parser, error) {
+		fallbackStarted++
+		return mockFallbackParser(func() { fallbackParsed++ }), nil
+	}
+
+	t.Run("native", func(t *testing.T) {
+		fallbackStarted, fallbackParsed = 0, 0
+
+		p, err := newParser([]string{"go"}, newFallback)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer p.Close()
+
+		if _, err := p.Parse("a.go", []byte("package a")); err != nil {
+			t.Fatal(err)
+		}
+		if fallbackStarted != 0 {
+			t.Errorf("expected ctags not to be started for Go files")
+		}
+
+		for i := 0; i < 2; i++ {
+			if _, err := p.Parse("a.js", []byte("var a;")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if fallbackStarted != 1 || fallbackParsed != 2 {
+			t.Errorf("unexpected fallback usage: started=%d parsed=%d", fallbackStarted, fallbackParsed)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		fallbackStarted, fallbackParsed = 0, 0
+
+		p, err := newParser(parseLanguageList(""), newFallback)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer p.Close()
+
+		if _, err := p.Parse("a.go", []byte("package a")); err != nil {
+			t.Fatal(err)
+		}
+		if fallbackParsed != 1 {
+			t.Errorf("expected Go files to be parsed by ctags")
+		}
+	})
+
+	t.Run("unknown language", func(t *testing.T) {
+		if _, err := newParser(parseLanguageList(" Go, cobol "), newFallback); err == nil {
+			t.Error("expected error for language without native parser")
+		}
+	})
+}
+
+type mockFallbackParser func()
+
+func (m mockFallbackParser) Parse(name string, content []byte) ([]*ctags.Entry, error) {
+	m()
+	return nil, nil
+}
+
+func (mockFallbackParser) Close() {}
@@ -99,8 +99,8 @@ func (s *Service) search(ctx context.Context, args protocol.SearchArgs) (*result
 // specified in `args`. If the database doesn't already exist in the disk cache,
 // it will create a new one and write all the symbols into it.
 func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (string, error) {
-	diskcacheFile, err := s.cache.OpenWithPath(ctx, fmt.Sprintf("%d-%s@%s", symbolsDBVersion, args.Repo, args.CommitID), func(fetcherCtx context.Context, tempDBFile string) error {
-		err := s.writeAllSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
+	diskcacheFile, err := s.cache.OpenWithPath(ctx, cacheKey(args.Repo, args.CommitID), func(fetcherCtx context.Context, tempDBFile string) error {
+		err := s.writeSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
 		if err != nil {
 			if err == context.Canceled {
 				log15.Error("Unable to parse repository symbols within the context", "repo", args.Repo, "commit", args.CommitID, "query", args.Query)
@@ -117,6 +117,11 @@ func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (stri
 	return diskcacheFile.File.Name(), err
 }
 
+// cacheKey returns the disk cache key of the database for repo@commit.
+func cacheKey(repo api.RepoName, commitID api.CommitID) string {
+	return fmt.Sprintf("%d-%s@%s", symbolsDBVersion, repo, commitID)
+}
+
 // isLiteralEquality checks if the given regex matches literal strings exactly.
 // Returns whether or not the regex is exact, along with the literal string if
 // so.
@@ -216,8 +221,9 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 // The version of the symbols database schema. This is included in the database
 // filenames to prevent a newer version of the symbols service from attempting
 // to read from a database created by an older (and likely incompatible) symbols
-// service. Increment this when you change the database schema.
-const symbolsDBVersion = 3
+// service. Increment this when you change the database schema or the symbols
+// produced by the parsers.
+const symbolsDBVersion = 4
 
 // symbolInDB is the same as `protocol.Symbol`, but with two additional columns:
 // namelowercase and pathlowercase, which enable indexed case insensitive
@@ -287,10 +293,38 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 		return err
 	}
 
+	if err := createSymbolsTable(tx); err != nil {
+		return err
+	}
+
+	insertStatement, err := prepareInsertSymbolStatement(tx)
+	if err != nil {
+		return err
+	}
+
+	err = s.parseUncached(ctx, repoName, commitID, nil, func(symbol result.Symbol) error {
+		symbolInDBValue := symbolToSymbolInDB(symbol)
+		_, err := insertStatement.Exec(&symbolInDBValue)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createSymbolsTable creates the symbols table and its indexes.
+func createSymbolsTable(tx *sqlx.Tx) error {
 	// The column names are the lowercase version of fields in `symbolInDB`
 	// because sqlx lowercases struct fields by default. See
 	// http://jmoiron.github.io/sqlx/#query
-	_, err = tx.Exec(
+	_, err := tx.Exec(
 		`CREATE TABLE IF NOT EXISTS symbols (
 			name VARCHAR(256) NOT NULL,
 			namelowercase VARCHAR(256) NOT NULL,
@@ -330,28 +364,13 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 		return err
 	}
 
-	insertStatement, err := tx.PrepareNamed(
+	return nil
+}
+
+func prepareInsertSymbolStatement(tx *sqlx.Tx) (*sqlx.NamedStmt, error) {
+	return tx.PrepareNamed(
 		fmt.Sprintf(
 			"INSERT INTO symbols %s VALUES %s",
 			"( name,  namelowercase,  path,  pathlowercase,  line,  kind,  language,  parent,  parentkind,  signature,  pattern,  filelimited)",
 			"(:name, :namelowercase, :path, :pathlowercase, :line, :kind, :language, :parent, :parentkind, :signature, :pattern, :filelimited)"))
-	if err != nil {
-		return err
-	}
-
-	err = s.parseUncached(ctx, repoName, commitID, func(symbol result.Symbol) error {
-		symbolInDBValue := symbolToSymbolInDB(symbol)
-		_, err := insertStatement.Exec(&symbolInDBValue)
-		return err
-	})
-	if err != nil {
-		return err
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
@@ -23,10 +23,26 @@ type Service struct {
 	// determine if the error is a bad request (eg invalid repo).
 	FetchTar func(context.Context, api.RepoName, api.CommitID) (io.ReadCloser, error)
 
+	// FetchTarPaths is like FetchTar, but the archive only includes the given
+	// paths. It is used to fetch the files changed since a cached ancestor.
+	FetchTarPaths func(context.Context, api.RepoName, api.CommitID, []string) (io.ReadCloser, error)
+
 	// MaxConcurrentFetchTar is the maximum number of concurrent calls allowed
-	// to FetchTar. It defaults to 15.
+	// to FetchTar and FetchTarPaths. It defaults to 15.
 	MaxConcurrentFetchTar int
 
+	// GitDiff returns the paths changed between two commits of a repository.
+	GitDiff func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) (Changes, error)
+
+	// ListAncestors returns up to n ancestors of a commit, nearest first.
+	ListAncestors func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error)
+
+	// MaxAncestorsToSearch is the number of ancestors of a commit that are
+	// checked for a cached database that the commit's database can be derived
+	// from. It defaults to 100. Databases are only derived from ancestors if
+	// FetchTarPaths, GitDiff and ListAncestors are all set.
+	MaxAncestorsToSearch int
+
 	NewParser func() (ctags.Parser, error)
 
 	// NumParserProcesses is the maximum number of ctags parser child processes to run.
@@ -63,6 +79,10 @@ func (s *Service) Start() error {
 	}
 	s.fetchSem = make(chan int, s.MaxConcurrentFetchTar)
 
+	if s.MaxAncestorsToSearch == 0 {
+		s.MaxAncestorsToSearch = 100
+	}
+
 	s.cache = &diskcache.Store{
 		Dir:               s.Path,
 		Component:         "symbols",
@@ -12,8 +12,10 @@ import (
 	"os/signal"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/sqliteutil"
@@ -26,6 +28,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 const port = "3184"
@@ -35,6 +38,7 @@ func main() {
 		cacheDir       = env.Get("CACHE_DIR", "/tmp/symbols-cache", "directory to store cached symbols")
 		cacheSizeMB    = env.Get("SYMBOLS_CACHE_SIZE_MB", "100000", "maximum size of the disk cache in megabytes")
 		ctagsProcesses = env.Get("CTAGS_PROCESSES", strconv.Itoa(runtime.GOMAXPROCS(0)), "number of ctags child processes to run")
+		incremental    = env.Get("SYMBOLS_INCREMENTAL_UPDATES", "true", "derive the symbols of a commit from the cached symbols of an ancestor commit when possible")
 	)
 
 	env.Lock()
@@ -58,6 +62,13 @@ func main() {
 		NewParser: symbols.NewParser,
 		Path:      cacheDir,
 	}
+	if enabled, _ := strconv.ParseBool(incremental); enabled {
+		service.FetchTarPaths = func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			return gitserver.DefaultClient.Archive(ctx, repo, gitserver.ArchiveOptions{Treeish: string(commit), Format: "tar", Paths: paths})
+		}
+		service.GitDiff = gitDiff
+		service.ListAncestors = listAncestors
+	}
 	if mb, err := strconv.ParseInt(cacheSizeMB, 10, 64); err != nil {
 		log.Fatalf("Invalid SYMBOLS_CACHE_SIZE_MB: %s", err)
 	} else {
@@ -93,6 +104,56 @@ func main() {
 	}
 }
 
+// gitDiff returns the paths changed between two commits.
+func gitDiff(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) (symbols.Changes, error) {
+	if err := ensureAbsoluteCommits(commitA, commitB); err != nil {
+		return symbols.Changes{}, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "diff", "-z", "--name-status", "--no-renames", string(commitA), string(commitB), "--")
+	cmd.Repo = repo
+	out, err := cmd.Output(ctx)
+	if err != nil {
+		return symbols.Changes{}, err
+	}
+	return symbols.ParseGitDiffNameStatus(out)
+}
+
+// listAncestors returns up to n first-parent ancestors of the commit, nearest
+// first.
+func listAncestors(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error) {
+	if err := ensureAbsoluteCommits(commit); err != nil {
+		return nil, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "rev-list", "--first-parent", "--max-count="+strconv.Itoa(n+1), string(commit), "--")
+	cmd.Repo = repo
+	out, err := cmd.Output(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ancestors []api.CommitID
+	for _, line := range strings.Fields(string(out)) {
+		if line != string(commit) {
+			ancestors = append(ancestors, api.CommitID(line))
+		}
+	}
+	return ancestors, nil
+}
+
+// ensureAbsoluteCommits returns an error if any of the given commits is not an
+// absolute commit ID. This keeps commits from the request from being parsed as
+// git options.
+func ensureAbsoluteCommits(commits ...api.CommitID) error {
+	for _, commit := range commits {
+		if !git.IsAbsoluteRevision(string(commit)) {
+			return errors.Errorf("non-absolute commit ID: %q", commit)
+		}
+	}
+	return nil
+}
+
 func shutdownOnSIGINT(s *http.Server) {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
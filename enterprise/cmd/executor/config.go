@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/google/uuid"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient"
@@ -30,6 +31,8 @@ type Config struct {
 	FirecrackerMemory    string
 	FirecrackerDiskSpace string
 	ImageArchivesPath    string
+	UseSandbox           bool
+	SandboxRootfsPath    string
 	DisableHealthServer  bool
 	HealthServerPort     int
 	MaximumRuntimePerJob time.Duration
@@ -48,16 +51,34 @@ func (c *Config) Load() {
 	c.FirecrackerMemory = c.Get("EXECUTOR_FIRECRACKER_MEMORY", "12G", "How much memory to allocate to each virtual machine or container.")
 	c.FirecrackerDiskSpace = c.Get("EXECUTOR_FIRECRACKER_DISK_SPACE", "20G", "How much disk space to allocate to each virtual machine or container.")
 	c.ImageArchivesPath = c.Get("EXECUTOR_IMAGE_ARCHIVE_PATH", "", "Where to store tar archives of docker images shared by virtual machines.")
+	c.UseSandbox = c.GetBool("EXECUTOR_USE_SANDBOX", "false", "Whether to isolate commands in rootless user namespace sandboxes (via bubblewrap). Requires EXECUTOR_USE_FIRECRACKER=false.")
+	c.SandboxRootfsPath = c.GetOptional("EXECUTOR_SANDBOX_ROOTFS_PATH", "Where to unpack the root filesystems of docker images shared by sandboxes.")
 	c.DisableHealthServer = c.GetBool("EXECUTOR_DISABLE_HEALTHSERVER", "false", "Whether or not to disable the health server.")
 	c.HealthServerPort = c.GetInt("EXECUTOR_HEALTH_SERVER_PORT", "3192", "The port to listen on for the health server.")
 	c.MaximumRuntimePerJob = c.GetInterval("EXECUTOR_MAXIMUM_RUNTIME_PER_JOB", "30m", "The maximum wall time that can be spent on a single job.")
 }
 
+// Validate returns any errors constructed while loading the config, as well as
+// errors for invalid combinations of runtime options.
+func (c *Config) Validate() error {
+	if c.UseSandbox {
+		if c.UseFirecracker {
+			c.AddError(errors.New("EXECUTOR_USE_SANDBOX and EXECUTOR_USE_FIRECRACKER cannot both be enabled"))
+		}
+		if c.SandboxRootfsPath == "" {
+			c.AddError(errors.New("EXECUTOR_SANDBOX_ROOTFS_PATH is required when EXECUTOR_USE_SANDBOX is enabled"))
+		}
+	}
+
+	return c.BaseConfig.Validate()
+}
+
 func (c *Config) APIWorkerOptions(transport http.RoundTripper) apiworker.Options {
 	return apiworker.Options{
 		QueueName:            c.QueueName,
 		WorkerOptions:        c.WorkerOptions(),
 		FirecrackerOptions:   c.FirecrackerOptions(),
+		SandboxOptions:       c.SandboxOptions(),
 		ResourceOptions:      c.ResourceOptions(),
 		MaximumRuntimePerJob: c.MaximumRuntimePerJob,
 		GitServicePath:       "/.executors/git",
@@ -88,6 +109,13 @@ func (c *Config) FirecrackerOptions() command.FirecrackerOptions {
 	}
 }
 
+func (c *Config) SandboxOptions() command.SandboxOptions {
+	return command.SandboxOptions{
+		Enabled:         c.UseSandbox,
+		ImageRootfsPath: c.SandboxRootfsPath,
+	}
+}
+
 func (c *Config) ResourceOptions() command.ResourceOptions {
 	return command.ResourceOptions{
 		NumCPUs:   c.FirecrackerNumCPUs,
@@ -15,6 +15,9 @@ type Operations struct {
 	SetupDockerPull           *observation.Operation
 	SetupDockerSave           *observation.Operation
 	SetupDockerLoad           *observation.Operation
+	SetupCraneDigest          *observation.Operation
+	SetupCraneExport          *observation.Operation
+	SetupTarExtract           *observation.Operation
 	SetupFirecrackerStart     *observation.Operation
 	SetupRm                   *observation.Operation
 	TeardownFirecrackerStop   *observation.Operation
@@ -46,6 +49,9 @@ func NewOperations(observationContext *observation.Context) *Operations {
 		SetupDockerPull:           op("setup.docker.pull"),
 		SetupDockerSave:           op("setup.docker.save"),
 		SetupDockerLoad:           op("setup.docker.load"),
+		SetupCraneDigest:          op("setup.crane.digest"),
+		SetupCraneExport:          op("setup.crane.export"),
+		SetupTarExtract:           op("setup.tar.extract"),
 		SetupRm:                   op("setup.rm"),
 		SetupFirecrackerStart:     op("setup.firecracker.start"),
 		TeardownFirecrackerStop:   op("teardown.firecracker.stop"),
@@ -24,6 +24,9 @@ type command struct {
 	Dir       string
 	Env       []string
 	Operation *observation.Operation
+
+	// Stdout, if set, additionally receives the standard output of the command.
+	Stdout io.Writer
 }
 
 // runCommand invokes the given command on the host machine. The standard output and
@@ -76,7 +79,12 @@ func runCommand(ctx context.Context, command command, logger *Logger) (err error
 	})
 	defer handle.Close()
 
-	pipeReaderWaitGroup := readProcessPipes(handle, stdout, stderr)
+	var stdoutReader io.Reader = stdout
+	if command.Stdout != nil {
+		stdoutReader = io.TeeReader(stdout, command.Stdout)
+	}
+
+	pipeReaderWaitGroup := readProcessPipes(handle, stdoutReader, stderr)
 	exitCode, err := monitorCommand(ctx, cmd, pipeReaderWaitGroup)
 
 	handle.logEntry.ExitCode = exitCode
@@ -96,10 +104,13 @@ func runCommand(ctx context.Context, command command, logger *Logger) (err error
 }
 
 var allowedBinaries = []string{
+	"bwrap",
+	"crane",
 	"docker",
 	"git",
 	"ignite",
 	"src",
+	"tar",
 }
 
 var ErrIllegalCommand = errors.New("illegal command")
@@ -3,13 +3,15 @@ package command
 import (
 	"context"
 
+	"github.com/cockroachdb/errors"
+
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
 
 // Runner is the interface between an executor and the host on which commands
 // are invoked. Having this interface at this level allows us to use the same
 // code paths for local development (via shell + docker) as well as production
-// usage (via Firecracker).
+// usage (via Firecracker or rootless sandboxes).
 type Runner interface {
 	// Setup prepares the runner to invoke a series of commands.
 	Setup(ctx context.Context, imageNames, scriptPaths []string) error
@@ -41,6 +43,9 @@ type Options struct {
 	// FirecrackerOptions configures the behavior of Firecracker virtual machine creation.
 	FirecrackerOptions FirecrackerOptions
 
+	// SandboxOptions configures the behavior of rootless sandboxes.
+	SandboxOptions SandboxOptions
+
 	// ResourceOptions configures the resource limits of docker container and Firecracker
 	// virtual machines running on the executor.
 	ResourceOptions ResourceOptions
@@ -57,6 +62,16 @@ type FirecrackerOptions struct {
 	ImageArchivesPath string
 }
 
+type SandboxOptions struct {
+	// Enabled determines if commands will be run in rootless sandboxes built on Linux
+	// user namespaces (via bubblewrap), which require neither KVM nor a docker daemon.
+	Enabled bool
+
+	// ImageRootfsPath is a path on the host where the root filesystems of docker images
+	// will be unpacked.
+	ImageRootfsPath string
+}
+
 type ResourceOptions struct {
 	// NumCPUs is the number of virtual CPUs a container or VM can use.
 	NumCPUs int
@@ -70,6 +85,10 @@ type ResourceOptions struct {
 
 // NewRunner creates a new runner with the given options.
 func NewRunner(dir string, logger *Logger, options Options, operations *Operations) Runner {
+	if options.SandboxOptions.Enabled {
+		return &sandboxRunner{dir: dir, logger: logger, options: options, operations: operations}
+	}
+
 	if !options.FirecrackerOptions.Enabled {
 		return &dockerRunner{dir: dir, logger: logger, options: options}
 	}
@@ -119,6 +138,41 @@ func (r *firecrackerRunner) Run(ctx context.Context, command CommandSpec) error
 	return runCommand(ctx, formatFirecrackerCommand(command, r.name, r.dir, r.options), r.logger)
 }
 
+type sandboxRunner struct {
+	dir        string
+	logger     *Logger
+	options    Options
+	operations *Operations
+
+	// rootfsPaths are the root filesystems of the images prepared in Setup.
+	rootfsPaths map[string]string
+}
+
+var _ Runner = &sandboxRunner{}
+
+func (r *sandboxRunner) Setup(ctx context.Context, imageNames, scriptPaths []string) error {
+	rootfsPaths, err := setupSandbox(ctx, defaultRunner, r.logger, imageNames, r.options, r.operations)
+	if err != nil {
+		return err
+	}
+
+	r.rootfsPaths = rootfsPaths
+	return nil
+}
+
+func (r *sandboxRunner) Teardown(ctx context.Context) error {
+	return nil
+}
+
+func (r *sandboxRunner) Run(ctx context.Context, command CommandSpec) error {
+	rootfs, ok := r.rootfsPaths[command.Image]
+	if command.Image != "" && !ok {
+		return errors.Errorf("image %s was not set up", command.Image)
+	}
+
+	return runCommand(ctx, formatSandboxCommand(command, r.dir, rootfs, r.options), r.logger)
+}
+
 type runnerWrapper struct{}
 
 var defaultRunner = &runnerWrapper{}
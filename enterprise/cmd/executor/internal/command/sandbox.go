@@ -0,0 +1,272 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+)
+
+const sandboxWorkspaceDir = "/data"
+
+// sandboxMountPoints are the directories that must exist in the root filesystem
+// of an image so that bubblewrap can mount over them. The root filesystem is
+// mounted read-only, so bubblewrap cannot create them itself.
+var sandboxMountPoints = []string{"data", "dev", "proc", "root", "tmp"}
+
+// sandboxDefaultEnv is the environment of commands run in a sandbox before the
+// variables of the command spec are applied. The host environment is not
+// inherited, as its PATH and HOME are meaningless inside of the image.
+var sandboxDefaultEnv = []string{
+	"HOME=/root",
+	"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+}
+
+var (
+	sandboxRootfsLocksMu sync.Mutex
+	sandboxRootfsLocks   = map[string]*sync.Mutex{}
+)
+
+// sandboxRootfsLock returns the lock serializing the preparation of the given root
+// filesystem, so that concurrent jobs using the same image unpack it only once while
+// jobs using other images are not held up.
+func sandboxRootfsLock(rootfs string) *sync.Mutex {
+	sandboxRootfsLocksMu.Lock()
+	defer sandboxRootfsLocksMu.Unlock()
+
+	lock, ok := sandboxRootfsLocks[rootfs]
+	if !ok {
+		lock = &sync.Mutex{}
+		sandboxRootfsLocks[rootfs] = lock
+	}
+	return lock
+}
+
+// formatSandboxCommand constructs the command to run on the host in order to invoke
+// the given spec. If the spec does not specify an image, then the command will be run
+// _directly_ on the host. Otherwise, the command will be run by bubblewrap inside of
+// a set of fresh Linux namespaces (including a user namespace, so no privileges are
+// required on the host), with the unpacked root filesystem of the image mounted
+// read-only as the root directory and the working directory mounted at /data.
+//
+// The given root filesystem of the image must have been unpacked by a successful
+// invocation of setupSandbox. Unlike docker containers and Firecracker virtual machines, resource
+// limits are not enforced on sandboxes; use cgroups on the host to limit the executor
+// as a whole instead.
+func formatSandboxCommand(spec CommandSpec, dir, rootfs string, options Options) command {
+	if spec.Image == "" {
+		return formatRawOrDockerCommand(spec, dir, options)
+	}
+
+	return command{
+		Key: spec.Key,
+		Command: flatten(
+			"bwrap",
+			sandboxNamespaceFlags(),
+			sandboxMountFlags(rootfs, dir),
+			sandboxWorkingDirectoryFlags(spec.Dir),
+			sandboxEnvFlags(spec.Env),
+			"/bin/sh",
+			filepath.Join(sandboxWorkspaceDir, ScriptsPath, spec.ScriptPath),
+		),
+		Operation: spec.Operation,
+	}
+}
+
+// setupSandbox ensures that the root filesystem of each of the given images is
+// unpacked on the host and returns the paths of the root filesystems by image name.
+// Root filesystems are keyed by image digest, so that a mutable tag that has been
+// pushed to since it was last unpacked is refreshed. They are shared between jobs and
+// are not removed on teardown. This is done in several steps for each image:
+//
+//   - Issue a `crane digest` to resolve the current digest of the image. This does
+//     not require a docker daemon.
+//   - If the root filesystem of that digest has not yet been unpacked, issue a
+//     `crane export` to download the flattened filesystem of the image by digest as
+//     a tar archive.
+//   - Extract the archive (without preserving ownership, which would require
+//     privileges) into a temporary directory, and create the mount points required
+//     by bubblewrap.
+//   - Atomically move the temporary directory into place.
+func setupSandbox(ctx context.Context, runner commandRunner, logger *Logger, imageNames []string, options Options, operations *Operations) (map[string]string, error) {
+	rootfsPaths := make(map[string]string, len(imageNames))
+	for _, image := range imageNames {
+		ref, err := resolveSandboxImage(ctx, runner, logger, image, operations)
+		if err != nil {
+			return nil, err
+		}
+
+		rootfs := sandboxRootfsPath(ref, options)
+		if err := setupSandboxRootfs(ctx, runner, logger, image, ref, rootfs, operations); err != nil {
+			return nil, err
+		}
+
+		rootfsPaths[image] = rootfs
+	}
+
+	return rootfsPaths, nil
+}
+
+// resolveSandboxImage returns the full reference of the given image by its current
+// digest (e.g. index.docker.io/library/alpine@sha256:...).
+func resolveSandboxImage(ctx context.Context, runner commandRunner, logger *Logger, image string, operations *Operations) (string, error) {
+	h := sha256.Sum256([]byte(image))
+
+	var stdout bytes.Buffer
+	digestCommand := command{
+		Key:       fmt.Sprintf("setup.crane.digest.%s", hex.EncodeToString(h[:8])),
+		Command:   flatten("crane", "digest", "--full-ref", image),
+		Operation: operations.SetupCraneDigest,
+		Stdout:    &stdout,
+	}
+	if err := runner.RunCommand(ctx, digestCommand, logger); err != nil {
+		return "", errors.Wrap(err, fmt.Sprintf("failed to resolve digest of %s", image))
+	}
+
+	ref := strings.TrimSpace(stdout.String())
+	if !strings.Contains(ref, "@sha256:") {
+		return "", errors.Errorf("failed to resolve digest of %s: unexpected output %q", image, ref)
+	}
+	return ref, nil
+}
+
+func setupSandboxRootfs(ctx context.Context, runner commandRunner, logger *Logger, image, ref, rootfs string, operations *Operations) error {
+	lock := sandboxRootfsLock(rootfs)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := os.Stat(rootfs); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	key := filepath.Base(rootfs)
+	tarfile := rootfs + ".tar"
+	tmpDir := rootfs + ".tmp"
+
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	defer os.Remove(tarfile)
+
+	exportCommand := command{
+		Key:       fmt.Sprintf("setup.crane.export.%s", key),
+		Command:   flatten("crane", "export", ref, tarfile),
+		Operation: operations.SetupCraneExport,
+	}
+	if err := runner.RunCommand(ctx, exportCommand, logger); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to export %s", image))
+	}
+
+	extractCommand := command{
+		Key: fmt.Sprintf("setup.tar.extract.%s", key),
+		Command: flatten(
+			"tar", "--extract",
+			"--file", tarfile,
+			"--directory", tmpDir,
+			"--no-same-owner",
+			// Device nodes cannot be created without privileges, and /dev
+			// is replaced by bubblewrap anyway.
+			"--exclude", "dev/*",
+		),
+		Operation: operations.SetupTarExtract,
+	}
+	if err := runner.RunCommand(ctx, extractCommand, logger); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to extract %s", image))
+	}
+
+	for _, name := range sandboxMountPoints {
+		if err := ensureSandboxMountPoint(filepath.Join(tmpDir, name)); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("failed to prepare root filesystem of %s", image))
+		}
+	}
+
+	return os.Rename(tmpDir, rootfs)
+}
+
+// ensureSandboxMountPoint creates the given directory if it does not exist. An
+// existing entry that is not a directory (e.g. a symlink, which may point outside
+// of the root filesystem) is rejected rather than followed.
+func ensureSandboxMountPoint(path string) error {
+	fi, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return os.Mkdir(path, 0755)
+	}
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return errors.Errorf("/%s is not a directory", filepath.Base(path))
+	}
+	return nil
+}
+
+// sandboxRootfsPath returns the path of the root filesystem of the given image
+// reference, which should be pinned by digest.
+//
+// NOTE: The options.SandboxOptions.ImageRootfsPath needs to exist on the host
+func sandboxRootfsPath(ref string, options Options) string {
+	h := sha256.Sum256([]byte(ref))
+	return filepath.Join(options.SandboxOptions.ImageRootfsPath, "rootfs-"+hex.EncodeToString(h[:8]))
+}
+
+func sandboxNamespaceFlags() []string {
+	return []string{
+		"--unshare-all",
+		"--share-net",
+		"--unshare-user",
+		"--uid", "0",
+		"--gid", "0",
+		"--hostname", "sandbox",
+		"--die-with-parent",
+		"--new-session",
+	}
+}
+
+func sandboxMountFlags(rootfs, dir string) []string {
+	flags := []string{
+		"--ro-bind", rootfs, "/",
+		"--proc", "/proc",
+		"--dev", "/dev",
+		"--tmpfs", "/tmp",
+		"--tmpfs", "/root",
+		"--bind", dir, sandboxWorkspaceDir,
+	}
+
+	// Share the DNS configuration of the host, as the one baked into the image is
+	// unlikely to be correct. This can only be mounted over a regular file.
+	if fi, err := os.Lstat(filepath.Join(rootfs, "etc", "resolv.conf")); err == nil && fi.Mode().IsRegular() {
+		flags = append(flags, "--ro-bind-try", "/etc/resolv.conf", "/etc/resolv.conf")
+	}
+
+	return flags
+}
+
+func sandboxWorkingDirectoryFlags(dir string) []string {
+	return []string{"--chdir", filepath.Join(sandboxWorkspaceDir, dir)}
+}
+
+func sandboxEnvFlags(env []string) []string {
+	flags := []string{"--clearenv"}
+	for _, kv := range append(append([]string{}, sandboxDefaultEnv...), env...) {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		flags = append(flags, "--setenv", parts[0], parts[1])
+	}
+
+	return flags
+}
@@ -0,0 +1,254 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestFormatSandboxCommandRaw(t *testing.T) {
+	actual := formatSandboxCommand(
+		CommandSpec{
+			Command:   []string{"ls", "-a"},
+			Dir:       "subdir",
+			Env:       []string{"TEST=true"},
+			Operation: makeTestOperation(),
+		},
+		"/proj/src",
+		"",
+		Options{},
+	)
+
+	expected := command{
+		Command: []string{"ls", "-a"},
+		Dir:     "/proj/src/subdir",
+		Env:     []string{"TEST=true"},
+	}
+	if diff := cmp.Diff(expected, actual, commandComparer); diff != "" {
+		t.Errorf("unexpected command (-want +got):\n%s", diff)
+	}
+}
+
+func TestFormatSandboxCommandDockerScript(t *testing.T) {
+	rootfs := t.TempDir()
+
+	spec := CommandSpec{
+		Image:      "alpine:latest",
+		ScriptPath: "myscript.sh",
+		Dir:        "subdir",
+		Env:        []string{"TEST=true"},
+		Operation:  makeTestOperation(),
+	}
+
+	expected := command{
+		Command: []string{
+			"bwrap",
+			"--unshare-all", "--share-net", "--unshare-user",
+			"--uid", "0", "--gid", "0",
+			"--hostname", "sandbox",
+			"--die-with-parent", "--new-session",
+			"--ro-bind", rootfs, "/",
+			"--proc", "/proc",
+			"--dev", "/dev",
+			"--tmpfs", "/tmp",
+			"--tmpfs", "/root",
+			"--bind", "/proj/src", "/data",
+			"--chdir", "/data/subdir",
+			"--clearenv",
+			"--setenv", "HOME", "/root",
+			"--setenv", "PATH", "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+			"--setenv", "TEST", "true",
+			"/bin/sh",
+			"/data/.sourcegraph-executor/myscript.sh",
+		},
+	}
+	if diff := cmp.Diff(expected, formatSandboxCommand(spec, "/proj/src", rootfs, Options{}), commandComparer); diff != "" {
+		t.Errorf("unexpected command (-want +got):\n%s", diff)
+	}
+
+	// The host's DNS configuration is mounted over the image's, if it has one.
+	if err := os.MkdirAll(filepath.Join(rootfs, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", "resolv.conf"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := strings.Join(formatSandboxCommand(spec, "/proj/src", rootfs, Options{}).Command, " ")
+	if !strings.Contains(actual, "--bind /proj/src /data --ro-bind-try /etc/resolv.conf /etc/resolv.conf --chdir") {
+		t.Errorf("expected resolv.conf to be mounted, got %q", actual)
+	}
+}
+
+func TestFormatSandboxCommandDockerCommand(t *testing.T) {
+	actual := formatSandboxCommand(
+		CommandSpec{
+			Command: []string{"ls", "-a"},
+			Dir:     "subdir",
+			Env:     []string{"TEST=true"},
+		},
+		"/proj/src",
+		"",
+		Options{},
+	)
+
+	expected := command{
+		Command: []string{
+			"ls", "-a",
+		},
+		Env: []string{"TEST=true"},
+		Dir: "/proj/src/subdir",
+	}
+	if diff := cmp.Diff(expected, actual, commandComparer); diff != "" {
+		t.Errorf("unexpected command (-want +got):\n%s", diff)
+	}
+}
+
+// newMockSandboxCommandRunner returns a command runner that resolves images to the
+// digests in the given map.
+func newMockSandboxCommandRunner(digests map[string]string) *MockCommandRunner {
+	runner := NewMockCommandRunner()
+	runner.RunCommandFunc.SetDefaultHook(func(ctx context.Context, command command, logger *Logger) error {
+		if command.Command[0] == "crane" && command.Command[1] == "digest" {
+			image := command.Command[3]
+			_, err := io.WriteString(command.Stdout, fmt.Sprintf("index.docker.io/library/%s@%s\n", strings.Split(image, ":")[0], digests[image]))
+			return err
+		}
+		return nil
+	})
+	return runner
+}
+
+func TestSetupSandbox(t *testing.T) {
+	digests := map[string]string{
+		"img1":        "sha256:1111",
+		"img2:latest": "sha256:2222",
+	}
+	runner := newMockSandboxCommandRunner(digests)
+	options := Options{
+		SandboxOptions: SandboxOptions{
+			ImageRootfsPath: t.TempDir(),
+		},
+	}
+	operations := NewOperations(&observation.TestContext)
+
+	rootfsPaths, err := setupSandbox(context.Background(), runner, nil, []string{"img1", "img2:latest"}, options, operations)
+	if err != nil {
+		t.Fatalf("unexpected error setting up sandbox: %s", err)
+	}
+
+	var actual []string
+	for _, call := range runner.RunCommandFunc.History() {
+		actual = append(actual, strings.Join(call.Arg1.Command, " "))
+	}
+
+	rootfs1 := sandboxRootfsPath("index.docker.io/library/img1@sha256:1111", options)
+	rootfs2 := sandboxRootfsPath("index.docker.io/library/img2@sha256:2222", options)
+	expected := []string{
+		"crane digest --full-ref img1",
+		"crane export index.docker.io/library/img1@sha256:1111 " + rootfs1 + ".tar",
+		"tar --extract --file " + rootfs1 + ".tar --directory " + rootfs1 + ".tmp --no-same-owner --exclude dev/*",
+		"crane digest --full-ref img2:latest",
+		"crane export index.docker.io/library/img2@sha256:2222 " + rootfs2 + ".tar",
+		"tar --extract --file " + rootfs2 + ".tar --directory " + rootfs2 + ".tmp --no-same-owner --exclude dev/*",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected commands (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"img1": rootfs1, "img2:latest": rootfs2}, rootfsPaths); diff != "" {
+		t.Errorf("unexpected root filesystems (-want +got):\n%s", diff)
+	}
+
+	for _, name := range sandboxMountPoints {
+		if fi, err := os.Stat(filepath.Join(rootfs1, name)); err != nil || !fi.IsDir() {
+			t.Errorf("expected mount point /%s to exist: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(rootfs1 + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected temporary directory to be removed: %v", err)
+	}
+
+	// Root filesystems of digests that have already been unpacked are reused.
+	if _, err := setupSandbox(context.Background(), runner, nil, []string{"img1"}, options, operations); err != nil {
+		t.Fatalf("unexpected error setting up sandbox: %s", err)
+	}
+	if n := len(runner.RunCommandFunc.History()); n != len(expected)+1 {
+		t.Errorf("unexpected number of commands: have %d want %d", n, len(expected)+1)
+	}
+
+	// Tags that have been pushed to since are unpacked again.
+	digests["img2:latest"] = "sha256:3333"
+	rootfsPaths, err = setupSandbox(context.Background(), runner, nil, []string{"img2:latest"}, options, operations)
+	if err != nil {
+		t.Fatalf("unexpected error setting up sandbox: %s", err)
+	}
+	if rootfs3 := sandboxRootfsPath("index.docker.io/library/img2@sha256:3333", options); rootfsPaths["img2:latest"] != rootfs3 {
+		t.Errorf("unexpected root filesystem: have %q want %q", rootfsPaths["img2:latest"], rootfs3)
+	}
+	if n := len(runner.RunCommandFunc.History()); n != len(expected)+4 {
+		t.Errorf("unexpected number of commands: have %d want %d", n, len(expected)+4)
+	}
+}
+
+func TestSetupSandboxSymlinkMountPoint(t *testing.T) {
+	runner := newMockSandboxCommandRunner(map[string]string{"img1": "sha256:1111"})
+	resolveHook := runner.RunCommandFunc.defaultHook
+	runner.RunCommandFunc.SetDefaultHook(func(ctx context.Context, command command, logger *Logger) error {
+		if command.Command[0] != "tar" {
+			return resolveHook(ctx, command, logger)
+		}
+
+		// Simulate an image whose /tmp points outside of the root filesystem.
+		return os.Symlink("/var/tmp", filepath.Join(command.Command[5], "tmp"))
+	})
+	options := Options{
+		SandboxOptions: SandboxOptions{
+			ImageRootfsPath: t.TempDir(),
+		},
+	}
+	operations := NewOperations(&observation.TestContext)
+
+	if _, err := setupSandbox(context.Background(), runner, nil, []string{"img1"}, options, operations); err == nil {
+		t.Fatal("expected error setting up sandbox")
+	}
+	if _, err := os.Stat(sandboxRootfsPath("index.docker.io/library/img1@sha256:1111", options)); !os.IsNotExist(err) {
+		t.Errorf("expected root filesystem not to be put in place: %v", err)
+	}
+}
+
+func TestNewRunner(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  Options
+		expected Runner
+	}{
+		{"docker", Options{}, &dockerRunner{}},
+		{"firecracker", Options{FirecrackerOptions: FirecrackerOptions{Enabled: true}}, &firecrackerRunner{}},
+		{"sandbox", Options{FirecrackerOptions: FirecrackerOptions{Enabled: true}, SandboxOptions: SandboxOptions{Enabled: true}}, &sandboxRunner{}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			runner := NewRunner("/proj", nil, testCase.options, nil)
+			if have, want := fmt.Sprintf("%T", runner), fmt.Sprintf("%T", testCase.expected); have != want {
+				t.Errorf("unexpected runner type: have %s want %s", have, want)
+			}
+		})
+	}
+}
+
+func TestSandboxRunnerRunUnknownImage(t *testing.T) {
+	runner := &sandboxRunner{dir: "/proj", rootfsPaths: map[string]string{"img1": "/rootfs"}}
+
+	if err := runner.Run(context.Background(), CommandSpec{Image: "img2", Operation: makeTestOperation()}); err == nil {
+		t.Fatal("expected error running command in image that was not set up")
+	}
+}
@@ -100,6 +100,7 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 	options := command.Options{
 		ExecutorName:       name.String(),
 		FirecrackerOptions: h.options.FirecrackerOptions,
+		SandboxOptions:     h.options.SandboxOptions,
 		ResourceOptions:    h.options.ResourceOptions,
 	}
 	runner := h.runnerFactory(workingDirectory, logger, options, h.operations)
@@ -42,6 +42,9 @@ type Options struct {
 	// FirecrackerOptions configures the behavior of Firecracker virtual machine creation.
 	FirecrackerOptions command.FirecrackerOptions
 
+	// SandboxOptions configures the behavior of rootless sandboxes.
+	SandboxOptions command.SandboxOptions
+
 	// ResourceOptions configures the resource limits of docker container and Firecracker
 	// virtual machines running on the executor.
 	ResourceOptions command.ResourceOptions
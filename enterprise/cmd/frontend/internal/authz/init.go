@@ -77,6 +77,8 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 				authzNames = append(authzNames, "GitLab")
 			case extsvc.TypeBitbucketServer:
 				authzNames = append(authzNames, "Bitbucket Server")
+			case extsvc.TypeBitbucketCloud:
+				authzNames = append(authzNames, "Bitbucket Cloud")
 			default:
 				authzNames = append(authzNames, t)
 			}
@@ -345,7 +345,7 @@ func (h *UploadHandler) markUploadAsFailed(ctx context.Context, tx DBStore, uplo
 	}
 }
 
-// inferIndexer returns the tool name from the metadata vertex at the start of the the given
+// inferIndexer returns the tool name from the metadata at the start of the the given
 // input stream. This method must destructively read the request body, but will re-assign the
 // Body field with a reader that holds the same information as the original request.
 //
@@ -59,6 +59,7 @@ func newResolver(ctx context.Context, db dbutil.DB, observationContext *observat
 		services.dbStore,
 		services.lsifStore,
 		services.gitserverClient,
+		searchClient{},
 		services.indexEnqueuer,
 		hunkCache,
 		observationContext,
@@ -0,0 +1,61 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+type BranchFreshnessResolver struct {
+	name            string
+	isDefaultBranch bool
+	commit          string
+	nearestUploads  []dbstore.NearestUpload
+}
+
+// NewBranchFreshnessResolver creates a resolver describing the precise code intelligence data
+// visible from the head of a branch. The given nearest uploads are expected to be ordered by
+// ascending distance.
+func NewBranchFreshnessResolver(name string, isDefaultBranch bool, commit string, nearestUploads []dbstore.NearestUpload) *BranchFreshnessResolver {
+	return &BranchFreshnessResolver{
+		name:            name,
+		isDefaultBranch: isDefaultBranch,
+		commit:          commit,
+		nearestUploads:  nearestUploads,
+	}
+}
+
+func (r *BranchFreshnessResolver) BranchName() string {
+	return r.name
+}
+
+func (r *BranchFreshnessResolver) IsDefaultBranch() bool {
+	return r.isDefaultBranch
+}
+
+func (r *BranchFreshnessResolver) Commit() string {
+	return r.commit
+}
+
+func (r *BranchFreshnessResolver) Distance() *int32 {
+	if len(r.nearestUploads) == 0 {
+		return nil
+	}
+
+	distance := int32(r.nearestUploads[0].Distance)
+	return &distance
+}
+
+func (r *BranchFreshnessResolver) Indexers() []string {
+	indexers := make([]string, 0, len(r.nearestUploads))
+	seen := make(map[string]struct{}, len(r.nearestUploads))
+
+	for _, upload := range r.nearestUploads {
+		if _, ok := seen[upload.Indexer]; ok {
+			continue
+		}
+
+		seen[upload.Indexer] = struct{}{}
+		indexers = append(indexers, upload.Indexer)
+	}
+
+	return indexers
+}
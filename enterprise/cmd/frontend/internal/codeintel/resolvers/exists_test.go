@@ -37,7 +37,7 @@ func TestFindClosestDumps(t *testing.T) {
 		return commit != "c4", nil
 	})
 
-	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, &observation.TestContext)
+	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, nil, &observation.TestContext)
 	dumps, err := resolver.findClosestDumps(context.Background(), commitChecker, 42, "deadbeef", "s1/main.go", true, "idx")
 	if err != nil {
 		t.Fatalf("unexpected error finding closest dumps: %s", err)
@@ -92,7 +92,7 @@ func TestFindClosestDumpsInfersClosestUploads(t *testing.T) {
 		return false, nil
 	})
 
-	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, &observation.TestContext)
+	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, nil, &observation.TestContext)
 	dumps, err := resolver.findClosestDumps(context.Background(), commitChecker, 42, "deadbeef", "s1/main.go", true, "idx")
 	if err != nil {
 		t.Fatalf("unexpected error finding closest dumps: %s", err)
@@ -131,7 +131,7 @@ func TestFindClosestDumpsDoesNotInferClosestUploadForUnknownRepository(t *testin
 	mockGitserverClient := NewMockGitserverClient()
 	commitChecker := newCachedCommitChecker(mockGitserverClient)
 
-	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, &observation.TestContext)
+	resolver := newResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, nil, &observation.TestContext)
 	dumps, err := resolver.findClosestDumps(context.Background(), commitChecker, 42, "deadbeef", "s1/main.go", true, "idx")
 	if err != nil {
 		t.Fatalf("unexpected error finding closest dumps: %s", err)
@@ -28,6 +28,7 @@ var ErrIllegalBounds = errors.New("illegal bounds")
 type QueryResolver struct {
 	resolver         resolvers.QueryResolver
 	locationResolver *CachedLocationResolver
+	precise          bool
 }
 
 // NewQueryResolver creates a new QueryResolver with the given resolver that defines all code intel-specific
@@ -37,12 +38,27 @@ func NewQueryResolver(resolver resolvers.QueryResolver, locationResolver *Cached
 	return &QueryResolver{
 		resolver:         resolver,
 		locationResolver: locationResolver,
+		precise:          true,
+	}
+}
+
+// NewSearchBasedQueryResolver creates a new QueryResolver with the given search-based resolver. Data
+// resolved by the returned resolver is marked as imprecise.
+func NewSearchBasedQueryResolver(resolver resolvers.QueryResolver, locationResolver *CachedLocationResolver) gql.GitBlobLSIFDataResolver {
+	return &QueryResolver{
+		resolver:         resolver,
+		locationResolver: locationResolver,
+		precise:          false,
 	}
 }
 
 func (r *QueryResolver) ToGitTreeLSIFData() (gql.GitTreeLSIFDataResolver, bool) { return r, true }
 func (r *QueryResolver) ToGitBlobLSIFData() (gql.GitBlobLSIFDataResolver, bool) { return r, true }
 
+func (r *QueryResolver) Precise() bool {
+	return r.precise
+}
+
 func (r *QueryResolver) Ranges(ctx context.Context, args *gql.LSIFRangesArgs) (gql.CodeIntelligenceRangeConnectionResolver, error) {
 	if args.StartLine < 0 || args.EndLine < args.StartLine {
 		return nil, ErrIllegalBounds
@@ -86,6 +102,24 @@ func (r *QueryResolver) References(ctx context.Context, args *gql.LSIFPagedQuery
 	return NewLocationConnectionResolver(locations, strPtr(cursor), r.locationResolver), nil
 }
 
+func (r *QueryResolver) Implementations(ctx context.Context, args *gql.LSIFPagedQueryPositionArgs) (gql.LocationConnectionResolver, error) {
+	limit := derefInt32(args.First, DefaultReferencesPageSize)
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+	cursor, err := decodeCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, cursor, err := r.resolver.Implementations(ctx, int(args.Line), int(args.Character), limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocationConnectionResolver(locations, strPtr(cursor), r.locationResolver), nil
+}
+
 func (r *QueryResolver) Hover(ctx context.Context, args *gql.LSIFQueryPositionArgs) (gql.HoverResolver, error) {
 	text, rx, exists, err := r.resolver.Hover(ctx, int(args.Line), int(args.Character))
 	if err != nil || !exists {
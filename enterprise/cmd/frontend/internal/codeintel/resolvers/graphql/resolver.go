@@ -246,6 +246,15 @@ func (r *Resolver) CommitGraph(ctx context.Context, id graphql.ID) (gql.CodeInte
 	return r.resolver.CommitGraph(ctx, int(repositoryID))
 }
 
+func (r *Resolver) CodeIntelligenceFreshness(ctx context.Context, id graphql.ID, path string) ([]gql.CodeIntelligenceBranchFreshnessResolver, error) {
+	repositoryID, err := gql.UnmarshalRepositoryID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.resolver.CodeIntelligenceFreshness(ctx, int(repositoryID), path)
+}
+
 func (r *Resolver) QueueAutoIndexJobForRepo(ctx context.Context, args *struct{ Repository graphql.ID }) (*gql.EmptyResponse, error) {
 	if !autoIndexingEnabled() {
 		return nil, errAutoIndexingNotEnabled
@@ -261,11 +270,24 @@ func (r *Resolver) QueueAutoIndexJobForRepo(ctx context.Context, args *struct{ R
 
 func (r *Resolver) GitBlobLSIFData(ctx context.Context, args *gql.GitBlobLSIFDataArgs) (gql.GitBlobLSIFDataResolver, error) {
 	resolver, err := r.resolver.QueryResolver(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if resolver != nil {
+		return NewQueryResolver(resolver, r.locationResolver), nil
+	}
+
+	// Search-based results are only meaningful for a single file
+	if !args.SearchBasedFallback || !args.ExactPath {
+		return nil, nil
+	}
+
+	resolver, err = r.resolver.SearchBasedQueryResolver(ctx, args)
 	if err != nil || resolver == nil {
 		return nil, err
 	}
 
-	return NewQueryResolver(resolver, r.locationResolver), nil
+	return NewSearchBasedQueryResolver(resolver, r.locationResolver), nil
 }
 
 // makeGetUploadsOptions translates the given GraphQL arguments into options defined by the
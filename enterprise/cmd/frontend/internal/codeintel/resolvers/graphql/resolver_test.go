@@ -95,6 +95,52 @@ func TestDeleteLSIFIndexUnauthenticated(t *testing.T) {
 	}
 }
 
+func TestGitBlobLSIFDataSearchBasedFallback(t *testing.T) {
+	db := new(dbtesting.MockDB)
+	mockResolver := resolvermocks.NewMockResolver()
+	mockResolver.SearchBasedQueryResolverFunc.SetDefaultReturn(resolvermocks.NewMockQueryResolver(), nil)
+
+	testCases := []struct {
+		searchBasedFallback bool
+		exactPath           bool
+		expectedResolver    bool
+	}{
+		{searchBasedFallback: false, exactPath: true, expectedResolver: false},
+		{searchBasedFallback: true, exactPath: false, expectedResolver: false},
+		{searchBasedFallback: true, exactPath: true, expectedResolver: true},
+	}
+
+	for _, testCase := range testCases {
+		resolver, err := NewResolver(db, mockResolver).GitBlobLSIFData(context.Background(), &gql.GitBlobLSIFDataArgs{
+			Repo:                &types.Repo{ID: 42},
+			Commit:              "deadbeef",
+			Path:                "main.go",
+			ExactPath:           testCase.exactPath,
+			SearchBasedFallback: testCase.searchBasedFallback,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if !testCase.expectedResolver {
+			if resolver != nil {
+				t.Errorf("unexpected resolver for %+v", testCase)
+			}
+			continue
+		}
+		if resolver == nil {
+			t.Fatalf("expected a resolver for %+v", testCase)
+		}
+		if resolver.Precise() {
+			t.Errorf("expected search-based resolver to be imprecise")
+		}
+	}
+
+	if history := mockResolver.SearchBasedQueryResolverFunc.History(); len(history) != 1 {
+		t.Errorf("unexpected call count. want=%d have=%d", 1, len(history))
+	}
+}
+
 func TestMakeGetUploadsOptions(t *testing.T) {
 	t.Cleanup(func() {
 		database.Mocks.Repos.Get = nil
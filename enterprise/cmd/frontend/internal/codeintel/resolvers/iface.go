@@ -4,11 +4,14 @@ import (
 	"context"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindex/enqueuer"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
 )
@@ -16,6 +19,8 @@ import (
 type GitserverClient interface {
 	CommitExists(ctx context.Context, repositoryID int, commit string) (bool, error)
 	CommitGraph(ctx context.Context, repositoryID int, options gitserver.CommitGraphOptions) (*gitserver.CommitGraph, error)
+	RawContents(ctx context.Context, repositoryID int, commit, file string) ([]byte, error)
+	BranchDescriptions(ctx context.Context, repositoryID int) (map[string][]gitserver.RefDescription, error)
 }
 
 type DBStore interface {
@@ -28,6 +33,7 @@ type DBStore interface {
 	GetDumpsByIDs(ctx context.Context, ids []int) ([]dbstore.Dump, error)
 	FindClosestDumps(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string) ([]dbstore.Dump, error)
 	FindClosestDumpsFromGraphFragment(ctx context.Context, repositoryID int, commit, path string, rootMustEnclosePath bool, indexer string, graph *gitserver.CommitGraph) ([]dbstore.Dump, error)
+	NearestUploadsForCommits(ctx context.Context, repositoryID int, commits []string, path string) (map[string][]dbstore.NearestUpload, error)
 	DefinitionDumps(ctx context.Context, monikers []semantic.QualifiedMonikerData) (_ []dbstore.Dump, err error)
 	ReferenceIDsAndFilters(ctx context.Context, repositoryID int, commit string, monikers []semantic.QualifiedMonikerData, limit, offset int) (_ dbstore.PackageReferenceScanner, _ int, err error)
 	HasRepository(ctx context.Context, repositoryID int) (bool, error)
@@ -47,6 +53,7 @@ type LSIFStore interface {
 	Ranges(ctx context.Context, bundleID int, path string, startLine, endLine int) ([]lsifstore.CodeIntelligenceRange, error)
 	Definitions(ctx context.Context, bundleID int, path string, line, character, limit, offset int) ([]lsifstore.Location, int, error)
 	References(ctx context.Context, bundleID int, path string, line, character, limit, offset int) ([]lsifstore.Location, int, error)
+	Implementations(ctx context.Context, bundleID int, path string, line, character, limit, offset int) ([]lsifstore.Location, int, error)
 	Hover(ctx context.Context, bundleID int, path string, line, character int) (string, lsifstore.Range, bool, error)
 	Diagnostics(ctx context.Context, bundleID int, prefix string, limit, offset int) ([]lsifstore.Diagnostic, int, error)
 	MonikersByPosition(ctx context.Context, bundleID int, path string, line, character int) ([][]semantic.MonikerData, error)
@@ -59,6 +66,16 @@ type LSIFStore interface {
 	DocumentationAtPosition(ctx context.Context, bundleID int, path string, line, character int) ([]string, error)
 }
 
+// SearchClient performs the symbol and text searches that back search-based code intelligence.
+type SearchClient interface {
+	// SearchSymbols returns the symbols with exactly the given name in the given repository at the given commit.
+	SearchSymbols(ctx context.Context, repo api.RepoName, commit api.CommitID, name string, limit int) ([]result.Symbol, error)
+
+	// SearchWord returns the case-sensitive whole-word matches of the given word in the given repository at the
+	// given commit. If include patterns are supplied, only files whose paths match all patterns are searched.
+	SearchWord(ctx context.Context, repo api.RepoName, commit api.CommitID, word string, includePatterns []string, limit int) ([]*protocol.FileMatch, error)
+}
+
 type IndexEnqueuer interface {
 	ForceQueueIndexesForRepository(ctx context.Context, repositoryID int) error
 	InferIndexConfiguration(ctx context.Context, repositoryID int) (*config.IndexConfiguration, error)
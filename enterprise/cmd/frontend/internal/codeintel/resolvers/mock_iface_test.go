@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	protocol1 "github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	enqueuer "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindex/enqueuer"
 	gitserver "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
 	dbstore "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
@@ -15,6 +16,7 @@ import (
 	api "github.com/sourcegraph/sourcegraph/internal/api"
 	basestore "github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	protocol "github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	result "github.com/sourcegraph/sourcegraph/internal/search/result"
 	config "github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 	semantic "github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
 )
@@ -77,6 +79,9 @@ type MockDBStore struct {
 	// MarkRepositoryAsDirtyFunc is an instance of a mock function object
 	// controlling the behavior of the method MarkRepositoryAsDirty.
 	MarkRepositoryAsDirtyFunc *DBStoreMarkRepositoryAsDirtyFunc
+	// NearestUploadsForCommitsFunc is an instance of a mock function object
+	// controlling the behavior of the method NearestUploadsForCommits.
+	NearestUploadsForCommitsFunc *DBStoreNearestUploadsForCommitsFunc
 	// ReferenceIDsAndFiltersFunc is an instance of a mock function object
 	// controlling the behavior of the method ReferenceIDsAndFilters.
 	ReferenceIDsAndFiltersFunc *DBStoreReferenceIDsAndFiltersFunc
@@ -178,6 +183,11 @@ func NewMockDBStore() *MockDBStore {
 				return nil
 			},
 		},
+		NearestUploadsForCommitsFunc: &DBStoreNearestUploadsForCommitsFunc{
+			defaultHook: func(context.Context, int, []string, string) (map[string][]dbstore.NearestUpload, error) {
+				return nil, nil
+			},
+		},
 		ReferenceIDsAndFiltersFunc: &DBStoreReferenceIDsAndFiltersFunc{
 			defaultHook: func(context.Context, int, string, []semantic.QualifiedMonikerData, int, int) (dbstore.PackageReferenceScanner, int, error) {
 				return nil, 0, nil
@@ -251,6 +261,9 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		MarkRepositoryAsDirtyFunc: &DBStoreMarkRepositoryAsDirtyFunc{
 			defaultHook: i.MarkRepositoryAsDirty,
 		},
+		NearestUploadsForCommitsFunc: &DBStoreNearestUploadsForCommitsFunc{
+			defaultHook: i.NearestUploadsForCommits,
+		},
 		ReferenceIDsAndFiltersFunc: &DBStoreReferenceIDsAndFiltersFunc{
 			defaultHook: i.ReferenceIDsAndFilters,
 		},
@@ -2182,6 +2195,124 @@ func (c DBStoreMarkRepositoryAsDirtyFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// DBStoreNearestUploadsForCommitsFunc describes the behavior when the
+// NearestUploadsForCommits method of the parent MockDBStore instance is
+// invoked.
+type DBStoreNearestUploadsForCommitsFunc struct {
+	defaultHook func(context.Context, int, []string, string) (map[string][]dbstore.NearestUpload, error)
+	hooks       []func(context.Context, int, []string, string) (map[string][]dbstore.NearestUpload, error)
+	history     []DBStoreNearestUploadsForCommitsFuncCall
+	mutex       sync.Mutex
+}
+
+// NearestUploadsForCommits delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockDBStore) NearestUploadsForCommits(v0 context.Context, v1 int, v2 []string, v3 string) (map[string][]dbstore.NearestUpload, error) {
+	r0, r1 := m.NearestUploadsForCommitsFunc.nextHook()(v0, v1, v2, v3)
+	m.NearestUploadsForCommitsFunc.appendCall(DBStoreNearestUploadsForCommitsFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// NearestUploadsForCommits method of the parent MockDBStore instance is
+// invoked and the hook queue is empty.
+func (f *DBStoreNearestUploadsForCommitsFunc) SetDefaultHook(hook func(context.Context, int, []string, string) (map[string][]dbstore.NearestUpload, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// NearestUploadsForCommits method of the parent MockDBStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *DBStoreNearestUploadsForCommitsFunc) PushHook(hook func(context.Context, int, []string, string) (map[string][]dbstore.NearestUpload, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreNearestUploadsForCommitsFunc) SetDefaultReturn(r0 map[string][]dbstore.NearestUpload, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, []string, string) (map[string][]dbstore.NearestUpload, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreNearestUploadsForCommitsFunc) PushReturn(r0 map[string][]dbstore.NearestUpload, r1 error) {
+	f.PushHook(func(context.Context, int, []string, string) (map[string][]dbstore.NearestUpload, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreNearestUploadsForCommitsFunc) nextHook() func(context.Context, int, []string, string) (map[string][]dbstore.NearestUpload, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreNearestUploadsForCommitsFunc) appendCall(r0 DBStoreNearestUploadsForCommitsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreNearestUploadsForCommitsFuncCall
+// objects describing the invocations of this function.
+func (f *DBStoreNearestUploadsForCommitsFunc) History() []DBStoreNearestUploadsForCommitsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreNearestUploadsForCommitsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreNearestUploadsForCommitsFuncCall is an object that describes an
+// invocation of method NearestUploadsForCommits on an instance of
+// MockDBStore.
+type DBStoreNearestUploadsForCommitsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[string][]dbstore.NearestUpload
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreNearestUploadsForCommitsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreNearestUploadsForCommitsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DBStoreReferenceIDsAndFiltersFunc describes the behavior when the
 // ReferenceIDsAndFilters method of the parent MockDBStore instance is
 // invoked.
@@ -4177,12 +4308,18 @@ func (c EnqueuerGitserverClientResolveRevisionFuncCall) Results() []interface{}
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
 // used for unit testing.
 type MockGitserverClient struct {
+	// BranchDescriptionsFunc is an instance of a mock function object
+	// controlling the behavior of the method BranchDescriptions.
+	BranchDescriptionsFunc *GitserverClientBranchDescriptionsFunc
 	// CommitExistsFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitExists.
 	CommitExistsFunc *GitserverClientCommitExistsFunc
 	// CommitGraphFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitGraph.
 	CommitGraphFunc *GitserverClientCommitGraphFunc
+	// RawContentsFunc is an instance of a mock function object controlling
+	// the behavior of the method RawContents.
+	RawContentsFunc *GitserverClientRawContentsFunc
 }
 
 // NewMockGitserverClient creates a new mock of the GitserverClient
@@ -4190,6 +4327,11 @@ type MockGitserverClient struct {
 // overwritten.
 func NewMockGitserverClient() *MockGitserverClient {
 	return &MockGitserverClient{
+		BranchDescriptionsFunc: &GitserverClientBranchDescriptionsFunc{
+			defaultHook: func(context.Context, int) (map[string][]gitserver.RefDescription, error) {
+				return nil, nil
+			},
+		},
 		CommitExistsFunc: &GitserverClientCommitExistsFunc{
 			defaultHook: func(context.Context, int, string) (bool, error) {
 				return false, nil
@@ -4200,6 +4342,11 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return nil, nil
 			},
 		},
+		RawContentsFunc: &GitserverClientRawContentsFunc{
+			defaultHook: func(context.Context, int, string, string) ([]byte, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -4208,13 +4355,131 @@ func NewMockGitserverClient() *MockGitserverClient {
 // overwritten.
 func NewMockGitserverClientFrom(i GitserverClient) *MockGitserverClient {
 	return &MockGitserverClient{
+		BranchDescriptionsFunc: &GitserverClientBranchDescriptionsFunc{
+			defaultHook: i.BranchDescriptions,
+		},
 		CommitExistsFunc: &GitserverClientCommitExistsFunc{
 			defaultHook: i.CommitExists,
 		},
 		CommitGraphFunc: &GitserverClientCommitGraphFunc{
 			defaultHook: i.CommitGraph,
 		},
+		RawContentsFunc: &GitserverClientRawContentsFunc{
+			defaultHook: i.RawContents,
+		},
+	}
+}
+
+// GitserverClientBranchDescriptionsFunc describes the behavior when the
+// BranchDescriptions method of the parent MockGitserverClient instance is
+// invoked.
+type GitserverClientBranchDescriptionsFunc struct {
+	defaultHook func(context.Context, int) (map[string][]gitserver.RefDescription, error)
+	hooks       []func(context.Context, int) (map[string][]gitserver.RefDescription, error)
+	history     []GitserverClientBranchDescriptionsFuncCall
+	mutex       sync.Mutex
+}
+
+// BranchDescriptions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) BranchDescriptions(v0 context.Context, v1 int) (map[string][]gitserver.RefDescription, error) {
+	r0, r1 := m.BranchDescriptionsFunc.nextHook()(v0, v1)
+	m.BranchDescriptionsFunc.appendCall(GitserverClientBranchDescriptionsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the BranchDescriptions
+// method of the parent MockGitserverClient instance is invoked and the hook
+// queue is empty.
+func (f *GitserverClientBranchDescriptionsFunc) SetDefaultHook(hook func(context.Context, int) (map[string][]gitserver.RefDescription, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// BranchDescriptions method of the parent MockGitserverClient instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverClientBranchDescriptionsFunc) PushHook(hook func(context.Context, int) (map[string][]gitserver.RefDescription, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *GitserverClientBranchDescriptionsFunc) SetDefaultReturn(r0 map[string][]gitserver.RefDescription, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) (map[string][]gitserver.RefDescription, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *GitserverClientBranchDescriptionsFunc) PushReturn(r0 map[string][]gitserver.RefDescription, r1 error) {
+	f.PushHook(func(context.Context, int) (map[string][]gitserver.RefDescription, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientBranchDescriptionsFunc) nextHook() func(context.Context, int) (map[string][]gitserver.RefDescription, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
 	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientBranchDescriptionsFunc) appendCall(r0 GitserverClientBranchDescriptionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientBranchDescriptionsFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverClientBranchDescriptionsFunc) History() []GitserverClientBranchDescriptionsFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientBranchDescriptionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientBranchDescriptionsFuncCall is an object that describes an
+// invocation of method BranchDescriptions on an instance of
+// MockGitserverClient.
+type GitserverClientBranchDescriptionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[string][]gitserver.RefDescription
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientBranchDescriptionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientBranchDescriptionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
 // GitserverClientCommitExistsFunc describes the behavior when the
@@ -4442,73 +4707,188 @@ func (c GitserverClientCommitGraphFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// MockIndexEnqueuer is a mock implementation of the IndexEnqueuer interface
-// (from the package
-// github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
-// used for unit testing.
-type MockIndexEnqueuer struct {
-	// ForceQueueIndexesForRepositoryFunc is an instance of a mock function
-	// object controlling the behavior of the method
-	// ForceQueueIndexesForRepository.
-	ForceQueueIndexesForRepositoryFunc *IndexEnqueuerForceQueueIndexesForRepositoryFunc
-	// InferIndexConfigurationFunc is an instance of a mock function object
-	// controlling the behavior of the method InferIndexConfiguration.
-	InferIndexConfigurationFunc *IndexEnqueuerInferIndexConfigurationFunc
-}
-
-// NewMockIndexEnqueuer creates a new mock of the IndexEnqueuer interface.
-// All methods return zero values for all results, unless overwritten.
-func NewMockIndexEnqueuer() *MockIndexEnqueuer {
-	return &MockIndexEnqueuer{
-		ForceQueueIndexesForRepositoryFunc: &IndexEnqueuerForceQueueIndexesForRepositoryFunc{
-			defaultHook: func(context.Context, int) error {
-				return nil
-			},
-		},
-		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
-			defaultHook: func(context.Context, int) (*config.IndexConfiguration, error) {
-				return nil, nil
-			},
-		},
-	}
-}
-
-// NewMockIndexEnqueuerFrom creates a new mock of the MockIndexEnqueuer
-// interface. All methods delegate to the given implementation, unless
-// overwritten.
-func NewMockIndexEnqueuerFrom(i IndexEnqueuer) *MockIndexEnqueuer {
-	return &MockIndexEnqueuer{
-		ForceQueueIndexesForRepositoryFunc: &IndexEnqueuerForceQueueIndexesForRepositoryFunc{
-			defaultHook: i.ForceQueueIndexesForRepository,
-		},
-		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
-			defaultHook: i.InferIndexConfiguration,
-		},
-	}
-}
-
-// IndexEnqueuerForceQueueIndexesForRepositoryFunc describes the behavior
-// when the ForceQueueIndexesForRepository method of the parent
-// MockIndexEnqueuer instance is invoked.
-type IndexEnqueuerForceQueueIndexesForRepositoryFunc struct {
-	defaultHook func(context.Context, int) error
-	hooks       []func(context.Context, int) error
-	history     []IndexEnqueuerForceQueueIndexesForRepositoryFuncCall
+// GitserverClientRawContentsFunc describes the behavior when the
+// RawContents method of the parent MockGitserverClient instance is invoked.
+type GitserverClientRawContentsFunc struct {
+	defaultHook func(context.Context, int, string, string) ([]byte, error)
+	hooks       []func(context.Context, int, string, string) ([]byte, error)
+	history     []GitserverClientRawContentsFuncCall
 	mutex       sync.Mutex
 }
 
-// ForceQueueIndexesForRepository delegates to the next hook function in the
-// queue and stores the parameter and result values of this invocation.
-func (m *MockIndexEnqueuer) ForceQueueIndexesForRepository(v0 context.Context, v1 int) error {
-	r0 := m.ForceQueueIndexesForRepositoryFunc.nextHook()(v0, v1)
-	m.ForceQueueIndexesForRepositoryFunc.appendCall(IndexEnqueuerForceQueueIndexesForRepositoryFuncCall{v0, v1, r0})
-	return r0
+// RawContents delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverClient) RawContents(v0 context.Context, v1 int, v2 string, v3 string) ([]byte, error) {
+	r0, r1 := m.RawContentsFunc.nextHook()(v0, v1, v2, v3)
+	m.RawContentsFunc.appendCall(GitserverClientRawContentsFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the
-// ForceQueueIndexesForRepository method of the parent MockIndexEnqueuer
-// instance is invoked and the hook queue is empty.
-func (f *IndexEnqueuerForceQueueIndexesForRepositoryFunc) SetDefaultHook(hook func(context.Context, int) error) {
+// SetDefaultHook sets function that is called when the RawContents method
+// of the parent MockGitserverClient instance is invoked and the hook queue
+// is empty.
+func (f *GitserverClientRawContentsFunc) SetDefaultHook(hook func(context.Context, int, string, string) ([]byte, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RawContents method of the parent MockGitserverClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GitserverClientRawContentsFunc) PushHook(hook func(context.Context, int, string, string) ([]byte, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *GitserverClientRawContentsFunc) SetDefaultReturn(r0 []byte, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) ([]byte, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *GitserverClientRawContentsFunc) PushReturn(r0 []byte, r1 error) {
+	f.PushHook(func(context.Context, int, string, string) ([]byte, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientRawContentsFunc) nextHook() func(context.Context, int, string, string) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientRawContentsFunc) appendCall(r0 GitserverClientRawContentsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientRawContentsFuncCall objects
+// describing the invocations of this function.
+func (f *GitserverClientRawContentsFunc) History() []GitserverClientRawContentsFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientRawContentsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientRawContentsFuncCall is an object that describes an
+// invocation of method RawContents on an instance of MockGitserverClient.
+type GitserverClientRawContentsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []byte
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientRawContentsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientRawContentsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// MockIndexEnqueuer is a mock implementation of the IndexEnqueuer interface
+// (from the package
+// github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
+// used for unit testing.
+type MockIndexEnqueuer struct {
+	// ForceQueueIndexesForRepositoryFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// ForceQueueIndexesForRepository.
+	ForceQueueIndexesForRepositoryFunc *IndexEnqueuerForceQueueIndexesForRepositoryFunc
+	// InferIndexConfigurationFunc is an instance of a mock function object
+	// controlling the behavior of the method InferIndexConfiguration.
+	InferIndexConfigurationFunc *IndexEnqueuerInferIndexConfigurationFunc
+}
+
+// NewMockIndexEnqueuer creates a new mock of the IndexEnqueuer interface.
+// All methods return zero values for all results, unless overwritten.
+func NewMockIndexEnqueuer() *MockIndexEnqueuer {
+	return &MockIndexEnqueuer{
+		ForceQueueIndexesForRepositoryFunc: &IndexEnqueuerForceQueueIndexesForRepositoryFunc{
+			defaultHook: func(context.Context, int) error {
+				return nil
+			},
+		},
+		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
+			defaultHook: func(context.Context, int) (*config.IndexConfiguration, error) {
+				return nil, nil
+			},
+		},
+	}
+}
+
+// NewMockIndexEnqueuerFrom creates a new mock of the MockIndexEnqueuer
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockIndexEnqueuerFrom(i IndexEnqueuer) *MockIndexEnqueuer {
+	return &MockIndexEnqueuer{
+		ForceQueueIndexesForRepositoryFunc: &IndexEnqueuerForceQueueIndexesForRepositoryFunc{
+			defaultHook: i.ForceQueueIndexesForRepository,
+		},
+		InferIndexConfigurationFunc: &IndexEnqueuerInferIndexConfigurationFunc{
+			defaultHook: i.InferIndexConfiguration,
+		},
+	}
+}
+
+// IndexEnqueuerForceQueueIndexesForRepositoryFunc describes the behavior
+// when the ForceQueueIndexesForRepository method of the parent
+// MockIndexEnqueuer instance is invoked.
+type IndexEnqueuerForceQueueIndexesForRepositoryFunc struct {
+	defaultHook func(context.Context, int) error
+	hooks       []func(context.Context, int) error
+	history     []IndexEnqueuerForceQueueIndexesForRepositoryFuncCall
+	mutex       sync.Mutex
+}
+
+// ForceQueueIndexesForRepository delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockIndexEnqueuer) ForceQueueIndexesForRepository(v0 context.Context, v1 int) error {
+	r0 := m.ForceQueueIndexesForRepositoryFunc.nextHook()(v0, v1)
+	m.ForceQueueIndexesForRepositoryFunc.appendCall(IndexEnqueuerForceQueueIndexesForRepositoryFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// ForceQueueIndexesForRepository method of the parent MockIndexEnqueuer
+// instance is invoked and the hook queue is empty.
+func (f *IndexEnqueuerForceQueueIndexesForRepositoryFunc) SetDefaultHook(hook func(context.Context, int) error) {
 	f.defaultHook = hook
 }
 
@@ -4745,6 +5125,9 @@ type MockLSIFStore struct {
 	// HoverFunc is an instance of a mock function object controlling the
 	// behavior of the method Hover.
 	HoverFunc *LSIFStoreHoverFunc
+	// ImplementationsFunc is an instance of a mock function object
+	// controlling the behavior of the method Implementations.
+	ImplementationsFunc *LSIFStoreImplementationsFunc
 	// MonikersByPositionFunc is an instance of a mock function object
 	// controlling the behavior of the method MonikersByPosition.
 	MonikersByPositionFunc *LSIFStoreMonikersByPositionFunc
@@ -4813,6 +5196,11 @@ func NewMockLSIFStore() *MockLSIFStore {
 				return "", lsifstore.Range{}, false, nil
 			},
 		},
+		ImplementationsFunc: &LSIFStoreImplementationsFunc{
+			defaultHook: func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+				return nil, 0, nil
+			},
+		},
 		MonikersByPositionFunc: &LSIFStoreMonikersByPositionFunc{
 			defaultHook: func(context.Context, int, string, int, int) ([][]semantic.MonikerData, error) {
 				return nil, nil
@@ -4870,6 +5258,9 @@ func NewMockLSIFStoreFrom(i LSIFStore) *MockLSIFStore {
 		HoverFunc: &LSIFStoreHoverFunc{
 			defaultHook: i.Hover,
 		},
+		ImplementationsFunc: &LSIFStoreImplementationsFunc{
+			defaultHook: i.Implementations,
+		},
 		MonikersByPositionFunc: &LSIFStoreMonikersByPositionFunc{
 			defaultHook: i.MonikersByPosition,
 		},
@@ -6087,6 +6478,133 @@ func (c LSIFStoreHoverFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
 }
 
+// LSIFStoreImplementationsFunc describes the behavior when the
+// Implementations method of the parent MockLSIFStore instance is invoked.
+type LSIFStoreImplementationsFunc struct {
+	defaultHook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
+	hooks       []func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)
+	history     []LSIFStoreImplementationsFuncCall
+	mutex       sync.Mutex
+}
+
+// Implementations delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) Implementations(v0 context.Context, v1 int, v2 string, v3 int, v4 int, v5 int, v6 int) ([]lsifstore.Location, int, error) {
+	r0, r1, r2 := m.ImplementationsFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
+	m.ImplementationsFunc.appendCall(LSIFStoreImplementationsFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the Implementations
+// method of the parent MockLSIFStore instance is invoked and the hook queue
+// is empty.
+func (f *LSIFStoreImplementationsFunc) SetDefaultHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Implementations method of the parent MockLSIFStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *LSIFStoreImplementationsFunc) PushHook(hook func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *LSIFStoreImplementationsFunc) SetDefaultReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *LSIFStoreImplementationsFunc) PushReturn(r0 []lsifstore.Location, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *LSIFStoreImplementationsFunc) nextHook() func(context.Context, int, string, int, int, int, int) ([]lsifstore.Location, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreImplementationsFunc) appendCall(r0 LSIFStoreImplementationsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of LSIFStoreImplementationsFuncCall objects
+// describing the invocations of this function.
+func (f *LSIFStoreImplementationsFunc) History() []LSIFStoreImplementationsFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreImplementationsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreImplementationsFuncCall is an object that describes an
+// invocation of method Implementations on an instance of MockLSIFStore.
+type LSIFStoreImplementationsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Arg6 is the value of the 7th argument passed to this method
+	// invocation.
+	Arg6 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []lsifstore.Location
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreImplementationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreImplementationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // LSIFStoreMonikersByPositionFunc describes the behavior when the
 // MonikersByPosition method of the parent MockLSIFStore instance is
 // invoked.
@@ -6713,3 +7231,286 @@ func (c RepoUpdaterClientEnqueueRepoUpdateFuncCall) Args() []interface{} {
 func (c RepoUpdaterClientEnqueueRepoUpdateFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
+
+// MockSearchClient is a mock implementation of the SearchClient interface
+// (from the package
+// github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
+// used for unit testing.
+type MockSearchClient struct {
+	// SearchSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method SearchSymbols.
+	SearchSymbolsFunc *SearchClientSearchSymbolsFunc
+	// SearchWordFunc is an instance of a mock function object controlling
+	// the behavior of the method SearchWord.
+	SearchWordFunc *SearchClientSearchWordFunc
+}
+
+// NewMockSearchClient creates a new mock of the SearchClient interface. All
+// methods return zero values for all results, unless overwritten.
+func NewMockSearchClient() *MockSearchClient {
+	return &MockSearchClient{
+		SearchSymbolsFunc: &SearchClientSearchSymbolsFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, string, int) ([]result.Symbol, error) {
+				return nil, nil
+			},
+		},
+		SearchWordFunc: &SearchClientSearchWordFunc{
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, string, []string, int) ([]*protocol1.FileMatch, error) {
+				return nil, nil
+			},
+		},
+	}
+}
+
+// NewMockSearchClientFrom creates a new mock of the MockSearchClient
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockSearchClientFrom(i SearchClient) *MockSearchClient {
+	return &MockSearchClient{
+		SearchSymbolsFunc: &SearchClientSearchSymbolsFunc{
+			defaultHook: i.SearchSymbols,
+		},
+		SearchWordFunc: &SearchClientSearchWordFunc{
+			defaultHook: i.SearchWord,
+		},
+	}
+}
+
+// SearchClientSearchSymbolsFunc describes the behavior when the
+// SearchSymbols method of the parent MockSearchClient instance is invoked.
+type SearchClientSearchSymbolsFunc struct {
+	defaultHook func(context.Context, api.RepoName, api.CommitID, string, int) ([]result.Symbol, error)
+	hooks       []func(context.Context, api.RepoName, api.CommitID, string, int) ([]result.Symbol, error)
+	history     []SearchClientSearchSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// SearchSymbols delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockSearchClient) SearchSymbols(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 string, v4 int) ([]result.Symbol, error) {
+	r0, r1 := m.SearchSymbolsFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.SearchSymbolsFunc.appendCall(SearchClientSearchSymbolsFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SearchSymbols method
+// of the parent MockSearchClient instance is invoked and the hook queue is
+// empty.
+func (f *SearchClientSearchSymbolsFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, string, int) ([]result.Symbol, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SearchSymbols method of the parent MockSearchClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *SearchClientSearchSymbolsFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, string, int) ([]result.Symbol, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *SearchClientSearchSymbolsFunc) SetDefaultReturn(r0 []result.Symbol, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, string, int) ([]result.Symbol, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *SearchClientSearchSymbolsFunc) PushReturn(r0 []result.Symbol, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, api.CommitID, string, int) ([]result.Symbol, error) {
+		return r0, r1
+	})
+}
+
+func (f *SearchClientSearchSymbolsFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, string, int) ([]result.Symbol, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SearchClientSearchSymbolsFunc) appendCall(r0 SearchClientSearchSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SearchClientSearchSymbolsFuncCall objects
+// describing the invocations of this function.
+func (f *SearchClientSearchSymbolsFunc) History() []SearchClientSearchSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]SearchClientSearchSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SearchClientSearchSymbolsFuncCall is an object that describes an
+// invocation of method SearchSymbols on an instance of MockSearchClient.
+type SearchClientSearchSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.CommitID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []result.Symbol
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SearchClientSearchSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SearchClientSearchSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// SearchClientSearchWordFunc describes the behavior when the SearchWord
+// method of the parent MockSearchClient instance is invoked.
+type SearchClientSearchWordFunc struct {
+	defaultHook func(context.Context, api.RepoName, api.CommitID, string, []string, int) ([]*protocol1.FileMatch, error)
+	hooks       []func(context.Context, api.RepoName, api.CommitID, string, []string, int) ([]*protocol1.FileMatch, error)
+	history     []SearchClientSearchWordFuncCall
+	mutex       sync.Mutex
+}
+
+// SearchWord delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockSearchClient) SearchWord(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 string, v4 []string, v5 int) ([]*protocol1.FileMatch, error) {
+	r0, r1 := m.SearchWordFunc.nextHook()(v0, v1, v2, v3, v4, v5)
+	m.SearchWordFunc.appendCall(SearchClientSearchWordFuncCall{v0, v1, v2, v3, v4, v5, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SearchWord method of
+// the parent MockSearchClient instance is invoked and the hook queue is
+// empty.
+func (f *SearchClientSearchWordFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, string, []string, int) ([]*protocol1.FileMatch, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SearchWord method of the parent MockSearchClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *SearchClientSearchWordFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, string, []string, int) ([]*protocol1.FileMatch, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *SearchClientSearchWordFunc) SetDefaultReturn(r0 []*protocol1.FileMatch, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, string, []string, int) ([]*protocol1.FileMatch, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *SearchClientSearchWordFunc) PushReturn(r0 []*protocol1.FileMatch, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, api.CommitID, string, []string, int) ([]*protocol1.FileMatch, error) {
+		return r0, r1
+	})
+}
+
+func (f *SearchClientSearchWordFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, string, []string, int) ([]*protocol1.FileMatch, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SearchClientSearchWordFunc) appendCall(r0 SearchClientSearchWordFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SearchClientSearchWordFuncCall objects
+// describing the invocations of this function.
+func (f *SearchClientSearchWordFunc) History() []SearchClientSearchWordFuncCall {
+	f.mutex.Lock()
+	history := make([]SearchClientSearchWordFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SearchClientSearchWordFuncCall is an object that describes an invocation
+// of method SearchWord on an instance of MockSearchClient.
+type SearchClientSearchWordFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.CommitID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 []string
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*protocol1.FileMatch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SearchClientSearchWordFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SearchClientSearchWordFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
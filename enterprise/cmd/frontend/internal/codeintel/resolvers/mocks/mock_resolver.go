@@ -16,6 +16,10 @@ import (
 // github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/codeintel/resolvers)
 // used for unit testing.
 type MockResolver struct {
+	// CodeIntelligenceFreshnessFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// CodeIntelligenceFreshness.
+	CodeIntelligenceFreshnessFunc *ResolverCodeIntelligenceFreshnessFunc
 	// CommitGraphFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitGraph.
 	CommitGraphFunc *ResolverCommitGraphFunc
@@ -49,6 +53,9 @@ type MockResolver struct {
 	// QueueAutoIndexJobForRepoFunc is an instance of a mock function object
 	// controlling the behavior of the method QueueAutoIndexJobForRepo.
 	QueueAutoIndexJobForRepoFunc *ResolverQueueAutoIndexJobForRepoFunc
+	// SearchBasedQueryResolverFunc is an instance of a mock function object
+	// controlling the behavior of the method SearchBasedQueryResolver.
+	SearchBasedQueryResolverFunc *ResolverSearchBasedQueryResolverFunc
 	// UpdateIndexConfigurationByRepositoryIDFunc is an instance of a mock
 	// function object controlling the behavior of the method
 	// UpdateIndexConfigurationByRepositoryID.
@@ -62,6 +69,11 @@ type MockResolver struct {
 // return zero values for all results, unless overwritten.
 func NewMockResolver() *MockResolver {
 	return &MockResolver{
+		CodeIntelligenceFreshnessFunc: &ResolverCodeIntelligenceFreshnessFunc{
+			defaultHook: func(context.Context, int, string) ([]graphqlbackend.CodeIntelligenceBranchFreshnessResolver, error) {
+				return nil, nil
+			},
+		},
 		CommitGraphFunc: &ResolverCommitGraphFunc{
 			defaultHook: func(context.Context, int) (graphqlbackend.CodeIntelligenceCommitGraphResolver, error) {
 				return nil, nil
@@ -117,6 +129,11 @@ func NewMockResolver() *MockResolver {
 				return nil
 			},
 		},
+		SearchBasedQueryResolverFunc: &ResolverSearchBasedQueryResolverFunc{
+			defaultHook: func(context.Context, *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error) {
+				return nil, nil
+			},
+		},
 		UpdateIndexConfigurationByRepositoryIDFunc: &ResolverUpdateIndexConfigurationByRepositoryIDFunc{
 			defaultHook: func(context.Context, int, string) error {
 				return nil
@@ -134,6 +151,9 @@ func NewMockResolver() *MockResolver {
 // methods delegate to the given implementation, unless overwritten.
 func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 	return &MockResolver{
+		CodeIntelligenceFreshnessFunc: &ResolverCodeIntelligenceFreshnessFunc{
+			defaultHook: i.CodeIntelligenceFreshness,
+		},
 		CommitGraphFunc: &ResolverCommitGraphFunc{
 			defaultHook: i.CommitGraph,
 		},
@@ -167,6 +187,9 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 		QueueAutoIndexJobForRepoFunc: &ResolverQueueAutoIndexJobForRepoFunc{
 			defaultHook: i.QueueAutoIndexJobForRepo,
 		},
+		SearchBasedQueryResolverFunc: &ResolverSearchBasedQueryResolverFunc{
+			defaultHook: i.SearchBasedQueryResolver,
+		},
 		UpdateIndexConfigurationByRepositoryIDFunc: &ResolverUpdateIndexConfigurationByRepositoryIDFunc{
 			defaultHook: i.UpdateIndexConfigurationByRepositoryID,
 		},
@@ -176,6 +199,121 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 	}
 }
 
+// ResolverCodeIntelligenceFreshnessFunc describes the behavior when the
+// CodeIntelligenceFreshness method of the parent MockResolver instance is
+// invoked.
+type ResolverCodeIntelligenceFreshnessFunc struct {
+	defaultHook func(context.Context, int, string) ([]graphqlbackend.CodeIntelligenceBranchFreshnessResolver, error)
+	hooks       []func(context.Context, int, string) ([]graphqlbackend.CodeIntelligenceBranchFreshnessResolver, error)
+	history     []ResolverCodeIntelligenceFreshnessFuncCall
+	mutex       sync.Mutex
+}
+
+// CodeIntelligenceFreshness delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockResolver) CodeIntelligenceFreshness(v0 context.Context, v1 int, v2 string) ([]graphqlbackend.CodeIntelligenceBranchFreshnessResolver, error) {
+	r0, r1 := m.CodeIntelligenceFreshnessFunc.nextHook()(v0, v1, v2)
+	m.CodeIntelligenceFreshnessFunc.appendCall(ResolverCodeIntelligenceFreshnessFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// CodeIntelligenceFreshness method of the parent MockResolver instance is
+// invoked and the hook queue is empty.
+func (f *ResolverCodeIntelligenceFreshnessFunc) SetDefaultHook(hook func(context.Context, int, string) ([]graphqlbackend.CodeIntelligenceBranchFreshnessResolver, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CodeIntelligenceFreshness method of the parent MockResolver instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *ResolverCodeIntelligenceFreshnessFunc) PushHook(hook func(context.Context, int, string) ([]graphqlbackend.CodeIntelligenceBranchFreshnessResolver, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverCodeIntelligenceFreshnessFunc) SetDefaultReturn(r0 []graphqlbackend.CodeIntelligenceBranchFreshnessResolver, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string) ([]graphqlbackend.CodeIntelligenceBranchFreshnessResolver, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverCodeIntelligenceFreshnessFunc) PushReturn(r0 []graphqlbackend.CodeIntelligenceBranchFreshnessResolver, r1 error) {
+	f.PushHook(func(context.Context, int, string) ([]graphqlbackend.CodeIntelligenceBranchFreshnessResolver, error) {
+		return r0, r1
+	})
+}
+
+func (f *ResolverCodeIntelligenceFreshnessFunc) nextHook() func(context.Context, int, string) ([]graphqlbackend.CodeIntelligenceBranchFreshnessResolver, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverCodeIntelligenceFreshnessFunc) appendCall(r0 ResolverCodeIntelligenceFreshnessFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverCodeIntelligenceFreshnessFuncCall
+// objects describing the invocations of this function.
+func (f *ResolverCodeIntelligenceFreshnessFunc) History() []ResolverCodeIntelligenceFreshnessFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverCodeIntelligenceFreshnessFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverCodeIntelligenceFreshnessFuncCall is an object that describes an
+// invocation of method CodeIntelligenceFreshness on an instance of
+// MockResolver.
+type ResolverCodeIntelligenceFreshnessFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []graphqlbackend.CodeIntelligenceBranchFreshnessResolver
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverCodeIntelligenceFreshnessFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverCodeIntelligenceFreshnessFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ResolverCommitGraphFunc describes the behavior when the CommitGraph
 // method of the parent MockResolver instance is invoked.
 type ResolverCommitGraphFunc struct {
@@ -1386,6 +1524,118 @@ func (c ResolverQueueAutoIndexJobForRepoFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// ResolverSearchBasedQueryResolverFunc describes the behavior when the
+// SearchBasedQueryResolver method of the parent MockResolver instance is
+// invoked.
+type ResolverSearchBasedQueryResolverFunc struct {
+	defaultHook func(context.Context, *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error)
+	hooks       []func(context.Context, *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error)
+	history     []ResolverSearchBasedQueryResolverFuncCall
+	mutex       sync.Mutex
+}
+
+// SearchBasedQueryResolver delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockResolver) SearchBasedQueryResolver(v0 context.Context, v1 *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error) {
+	r0, r1 := m.SearchBasedQueryResolverFunc.nextHook()(v0, v1)
+	m.SearchBasedQueryResolverFunc.appendCall(ResolverSearchBasedQueryResolverFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// SearchBasedQueryResolver method of the parent MockResolver instance is
+// invoked and the hook queue is empty.
+func (f *ResolverSearchBasedQueryResolverFunc) SetDefaultHook(hook func(context.Context, *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SearchBasedQueryResolver method of the parent MockResolver instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *ResolverSearchBasedQueryResolverFunc) PushHook(hook func(context.Context, *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverSearchBasedQueryResolverFunc) SetDefaultReturn(r0 resolvers.QueryResolver, r1 error) {
+	f.SetDefaultHook(func(context.Context, *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverSearchBasedQueryResolverFunc) PushReturn(r0 resolvers.QueryResolver, r1 error) {
+	f.PushHook(func(context.Context, *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error) {
+		return r0, r1
+	})
+}
+
+func (f *ResolverSearchBasedQueryResolverFunc) nextHook() func(context.Context, *graphqlbackend.GitBlobLSIFDataArgs) (resolvers.QueryResolver, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverSearchBasedQueryResolverFunc) appendCall(r0 ResolverSearchBasedQueryResolverFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverSearchBasedQueryResolverFuncCall
+// objects describing the invocations of this function.
+func (f *ResolverSearchBasedQueryResolverFunc) History() []ResolverSearchBasedQueryResolverFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverSearchBasedQueryResolverFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverSearchBasedQueryResolverFuncCall is an object that describes an
+// invocation of method SearchBasedQueryResolver on an instance of
+// MockResolver.
+type ResolverSearchBasedQueryResolverFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *graphqlbackend.GitBlobLSIFDataArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 resolvers.QueryResolver
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverSearchBasedQueryResolverFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverSearchBasedQueryResolverFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ResolverUpdateIndexConfigurationByRepositoryIDFunc describes the behavior
 // when the UpdateIndexConfigurationByRepositoryID method of the parent
 // MockResolver instance is invoked.
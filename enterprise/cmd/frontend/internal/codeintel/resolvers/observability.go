@@ -21,6 +21,10 @@ type operations struct {
 	hover                     *observation.Operation
 	ranges                    *observation.Operation
 	references                *observation.Operation
+	implementations           *observation.Operation
+	searchBasedDefinitions    *observation.Operation
+	searchBasedReferences     *observation.Operation
+	searchBasedHover          *observation.Operation
 	documentationPage         *observation.Operation
 	documentationPathInfo     *observation.Operation
 	documentationIDsToPathIDs *observation.Operation
@@ -62,6 +66,10 @@ func newOperations(observationContext *observation.Context) *operations {
 		hover:                     op("Hover"),
 		ranges:                    op("Ranges"),
 		references:                op("References"),
+		implementations:           op("Implementations"),
+		searchBasedDefinitions:    op("SearchBasedDefinitions"),
+		searchBasedReferences:     op("SearchBasedReferences"),
+		searchBasedHover:          op("SearchBasedHover"),
 		documentationPage:         op("DocumentationPage"),
 		documentationPathInfo:     op("DocumentationPathInfo"),
 		documentationIDsToPathIDs: op("DocumentationIDsToPathIDs"),
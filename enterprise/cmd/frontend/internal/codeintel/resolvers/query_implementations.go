@@ -0,0 +1,29 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// Implementations returns the list of source locations that implement the symbol at the given position.
+// Implementations in other repositories are found by a moniker search over the uploads that reference the
+// package defining the symbol, in the same way as remote references.
+func (r *queryResolver) Implementations(ctx context.Context, line, character, limit int, rawCursor string) (_ []AdjustedLocation, _ string, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "Implementations", r.operations.implementations, slowReferencesRequestThreshold, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", r.repositoryID),
+			log.String("commit", r.commit),
+			log.String("path", r.path),
+			log.Int("numUploads", len(r.uploads)),
+			log.String("uploads", uploadIDsToString(r.uploads)),
+			log.Int("line", line),
+			log.Int("character", character),
+		},
+	})
+	defer endObservation()
+
+	return r.pageMonikerLocations(ctx, traceLog, implementationsRelation, line, character, limit, rawCursor)
+}
@@ -0,0 +1,78 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/bloomfilter"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+func TestImplementations(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockLSIFStore := NewMockLSIFStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockPositionAdjuster := noopPositionAdjuster()
+	mockGitserverClient.CommitExistsFunc.SetDefaultReturn(true, nil)
+
+	referenceUploads := []dbstore.Dump{
+		{ID: 250, Commit: "deadbeef1", Root: "sub1/"},
+	}
+	mockDBStore.GetDumpsByIDsFunc.PushReturn(referenceUploads, nil)
+
+	filter, err := bloomfilter.CreateFilter([]string{"Reader"})
+	if err != nil {
+		t.Fatalf("unexpected error encoding bloom filter: %s", err)
+	}
+	mockDBStore.ReferenceIDsAndFiltersFunc.PushReturn(dbstore.PackageReferenceScannerFromSlice(
+		lsifstore.PackageReference{Package: lsifstore.Package{DumpID: 250}, Filter: filter},
+	), 1, nil)
+
+	moniker := semantic.MonikerData{Kind: "export", Scheme: "gomod", Identifier: "Reader", PackageInformationID: "51"}
+	mockLSIFStore.MonikersByPositionFunc.PushReturn([][]semantic.MonikerData{{moniker}}, nil)
+	mockLSIFStore.PackageInformationFunc.PushReturn(semantic.PackageInformationData{Name: "io", Version: "1.0.0"}, true, nil)
+
+	mockLSIFStore.ImplementationsFunc.PushReturn([]lsifstore.Location{{DumpID: 51, Path: "a.go", Range: testRange1}}, 1, nil)
+	mockLSIFStore.BulkMonikerResultsFunc.PushReturn([]lsifstore.Location{{DumpID: 250, Path: "b.go", Range: testRange2}}, 1, nil)
+
+	uploads := []dbstore.Dump{
+		{ID: 51, Commit: "deadbeef", Root: "sub2/"},
+	}
+	resolver := newQueryResolver(
+		mockDBStore,
+		mockLSIFStore,
+		newCachedCommitChecker(mockGitserverClient),
+		mockPositionAdjuster,
+		42,
+		"deadbeef",
+		"s1/main.go",
+		uploads,
+		newOperations(&observation.TestContext),
+	)
+	adjustedLocations, _, err := resolver.Implementations(context.Background(), 10, 20, 50, "")
+	if err != nil {
+		t.Fatalf("unexpected error querying implementations: %s", err)
+	}
+
+	expectedLocations := []AdjustedLocation{
+		{Dump: uploads[0], Path: "sub2/a.go", AdjustedCommit: "deadbeef", AdjustedRange: testRange1},
+		{Dump: referenceUploads[0], Path: "sub1/b.go", AdjustedCommit: "deadbeef1", AdjustedRange: testRange2},
+	}
+	if diff := cmp.Diff(expectedLocations, adjustedLocations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	if history := mockLSIFStore.ReferencesFunc.History(); len(history) != 0 {
+		t.Errorf("unexpected number of calls to References. want=%d have=%d", 0, len(history))
+	}
+	if history := mockLSIFStore.BulkMonikerResultsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of calls to BulkMonikerResults. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != "implementations" {
+		t.Errorf("unexpected table name. want=%q have=%q", "implementations", history[0].Arg1)
+	}
+}
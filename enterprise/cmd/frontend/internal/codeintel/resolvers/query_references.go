@@ -20,6 +20,33 @@ import (
 
 const slowReferencesRequestThreshold = time.Second
 
+// locationRelation describes a relation between a symbol and the locations paged over by
+// pageMonikerLocations.
+type locationRelation struct {
+	// name labels errors returned by the LSIF store.
+	name string
+
+	// tableName is the moniker table searched for locations in remote uploads.
+	tableName string
+
+	// localLocations returns the related locations within a single upload.
+	localLocations func(store LSIFStore, ctx context.Context, bundleID int, path string, line, character, limit, offset int) ([]lsifstore.Location, int, error)
+}
+
+var (
+	referencesRelation = locationRelation{
+		name:           "References",
+		tableName:      "references",
+		localLocations: LSIFStore.References,
+	}
+
+	implementationsRelation = locationRelation{
+		name:           "Implementations",
+		tableName:      "implementations",
+		localLocations: LSIFStore.Implementations,
+	}
+)
+
 // References returns the list of source locations that reference the symbol at the given position.
 func (r *queryResolver) References(ctx context.Context, line, character, limit int, rawCursor string) (_ []AdjustedLocation, _ string, err error) {
 	ctx, traceLog, endObservation := observeResolver(ctx, &err, "References", r.operations.references, slowReferencesRequestThreshold, observation.Args{
@@ -35,6 +62,13 @@ func (r *queryResolver) References(ctx context.Context, line, character, limit i
 	})
 	defer endObservation()
 
+	return r.pageMonikerLocations(ctx, traceLog, referencesRelation, line, character, limit, rawCursor)
+}
+
+// pageMonikerLocations returns a page of the locations related to the symbol at the given position by the
+// given relation. Local results are gathered by traversing the LSIF graph of each visible upload, and remote
+// results by a moniker search over the uploads that define or reference one of the symbol's monikers.
+func (r *queryResolver) pageMonikerLocations(ctx context.Context, traceLog observation.TraceLogger, relation locationRelation, line, character, limit int, rawCursor string) ([]AdjustedLocation, string, error) {
 	// Maintain a map from identifers to hydrated upload records from the database. We use
 	// this map as a quick lookup when constructing the resulting location set. Any additional
 	// upload records pulled back from the database while processing this page will be added
@@ -96,7 +130,7 @@ func (r *queryResolver) References(ctx context.Context, line, character, limit i
 	}
 
 	// Query a single page of location results
-	locations, hasMore, err := r.pageReferences(ctx, adjustedUploads, orderedMonikers, definitionUploadIDs, uploadsByID, &cursor, limit)
+	locations, hasMore, err := r.pageLocations(ctx, relation, adjustedUploads, orderedMonikers, definitionUploadIDs, uploadsByID, &cursor, limit)
 	if err != nil {
 		return nil, "", err
 	}
@@ -234,10 +268,10 @@ func (r *queryResolver) definitionUploadIDsFromCursor(ctx context.Context, adjus
 	return definitionUploadIDs, definitionUploads, nil
 }
 
-// pageReferences returns a slice of the result set denoted by the given cursor. The given cursor will be
+// pageLocations returns a slice of the result set denoted by the given cursor. The given cursor will be
 // adjusted to reflect the offsets required to resolve the next page of results. If there are no more pages
 // left in the result set, a false-valued flag is returned.
-func (r *queryResolver) pageReferences(ctx context.Context, adjustedUploads []adjustedUpload, orderedMonikers []semantic.QualifiedMonikerData, definitionUploadIDs []int, uploadsByID map[int]dbstore.Dump, cursor *referencesCursor, limit int) ([]lsifstore.Location, bool, error) {
+func (r *queryResolver) pageLocations(ctx context.Context, relation locationRelation, adjustedUploads []adjustedUpload, orderedMonikers []semantic.QualifiedMonikerData, definitionUploadIDs []int, uploadsByID map[int]dbstore.Dump, cursor *referencesCursor, limit int) ([]lsifstore.Location, bool, error) {
 	var locations []lsifstore.Location
 
 	// Phase 1: Gather all "local" locations via LSIF graph traversal. We'll continue to request additional
@@ -246,7 +280,7 @@ func (r *queryResolver) pageReferences(ctx context.Context, adjustedUploads []ad
 
 	if !cursor.RemotePhase {
 		for len(locations) < limit {
-			localLocations, hasMore, err := r.pageLocalReferences(ctx, adjustedUploads, cursor, limit-len(locations))
+			localLocations, hasMore, err := r.pageLocalLocations(ctx, relation, adjustedUploads, cursor, limit-len(locations))
 			if err != nil {
 				return nil, false, err
 			}
@@ -266,7 +300,7 @@ func (r *queryResolver) pageReferences(ctx context.Context, adjustedUploads []ad
 
 	if cursor.RemotePhase {
 		for len(locations) < limit {
-			remoteLocations, hasMore, err := r.pageRemoteReferences(ctx, adjustedUploads, orderedMonikers, definitionUploadIDs, uploadsByID, cursor, limit-len(locations))
+			remoteLocations, hasMore, err := r.pageRemoteLocations(ctx, relation, adjustedUploads, orderedMonikers, definitionUploadIDs, uploadsByID, cursor, limit-len(locations))
 			if err != nil {
 				return nil, false, err
 			}
@@ -281,11 +315,11 @@ func (r *queryResolver) pageReferences(ctx context.Context, adjustedUploads []ad
 	return locations, true, nil
 }
 
-// pageLocalReferences returns a slice of the (local) result set denoted by the given cursor fulfilled by
+// pageLocalLocations returns a slice of the (local) result set denoted by the given cursor fulfilled by
 // traversing the LSIF graph. The given cursor will be adjusted to reflect the offsets required to resolve
 // the next page of results. If there are no more pages left in the result set, a false-valued flag is
 // returned.
-func (r *queryResolver) pageLocalReferences(ctx context.Context, adjustedUploads []adjustedUpload, cursor *referencesCursor, limit int) ([]lsifstore.Location, bool, error) {
+func (r *queryResolver) pageLocalLocations(ctx context.Context, relation locationRelation, adjustedUploads []adjustedUpload, cursor *referencesCursor, limit int) ([]lsifstore.Location, bool, error) {
 	var allLocations []lsifstore.Location
 	for i := range adjustedUploads {
 		if len(allLocations) >= limit {
@@ -297,8 +331,9 @@ func (r *queryResolver) pageLocalReferences(ctx context.Context, adjustedUploads
 			continue
 		}
 
-		locations, totalCount, err := r.lsifStore.References(
+		locations, totalCount, err := r.localLocations(
 			ctx,
+			relation,
 			adjustedUploads[i].Upload.ID,
 			adjustedUploads[i].AdjustedPathInBundle,
 			adjustedUploads[i].AdjustedPosition.Line,
@@ -307,7 +342,7 @@ func (r *queryResolver) pageLocalReferences(ctx context.Context, adjustedUploads
 			cursor.LocalOffset,
 		)
 		if err != nil {
-			return nil, false, errors.Wrap(err, "lsifstore.References")
+			return nil, false, err
 		}
 
 		cursor.LocalOffset += len(locations)
@@ -324,15 +359,25 @@ func (r *queryResolver) pageLocalReferences(ctx context.Context, adjustedUploads
 	return allLocations, cursor.LocalBatchOffset < len(adjustedUploads), nil
 }
 
+// localLocations returns the locations related to the symbol at the given position by the given relation
+// within a single upload by traversing its LSIF graph.
+func (r *queryResolver) localLocations(ctx context.Context, relation locationRelation, bundleID int, path string, line, character, limit, offset int) ([]lsifstore.Location, int, error) {
+	locations, totalCount, err := relation.localLocations(r.lsifStore, ctx, bundleID, path, line, character, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "lsifstore."+relation.name)
+	}
+	return locations, totalCount, nil
+}
+
 // maximumIndexesPerMonikerSearch configures the maximum number of reference upload identifiers
 // that can be passed to a single moniker search query.
 const maximumIndexesPerMonikerSearch = 50
 
-// pageRemoteReferences returns a slice of the (remote) result set denoted by the given cursor fulfilled by
+// pageRemoteLocations returns a slice of the (remote) result set denoted by the given cursor fulfilled by
 // performing a moniker search over a group of indexes. The given cursor will be adjusted to reflect the
 // offsets required to resolve the next page of results. If there are no more pages left in the result set,
 // a false-valued flag is returned.
-func (r *queryResolver) pageRemoteReferences(ctx context.Context, adjustedUploads []adjustedUpload, orderedMonikers []semantic.QualifiedMonikerData, definitionUploadIDs []int, uploadsByID map[int]dbstore.Dump, cursor *referencesCursor, limit int) ([]lsifstore.Location, bool, error) {
+func (r *queryResolver) pageRemoteLocations(ctx context.Context, relation locationRelation, adjustedUploads []adjustedUpload, orderedMonikers []semantic.QualifiedMonikerData, definitionUploadIDs []int, uploadsByID map[int]dbstore.Dump, cursor *referencesCursor, limit int) ([]lsifstore.Location, bool, error) {
 	for len(cursor.BatchIDs) == 0 {
 		if cursor.RemoteBatchOffset < 0 {
 			// No more batches
@@ -364,7 +409,7 @@ func (r *queryResolver) pageRemoteReferences(ctx context.Context, adjustedUpload
 	}
 
 	// Perform the moniker search
-	locations, totalCount, err := r.monikerLocations(ctx, monikerSearchUploads, orderedMonikers, "references", limit, cursor.RemoteOffset)
+	locations, totalCount, err := r.monikerLocations(ctx, monikerSearchUploads, orderedMonikers, relation.tableName, limit, cursor.RemoteOffset)
 	if err != nil {
 		return nil, false, err
 	}
@@ -0,0 +1,355 @@
+package resolvers
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cockroachdb/errors"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+const (
+	// searchBasedSymbolsLimit is the maximum number of symbols requested from the symbols service
+	// when looking for the definitions of a name.
+	searchBasedSymbolsLimit = 100
+
+	// searchBasedFileMatchLimit is the maximum number of files requested from searcher when looking
+	// for the references to a name.
+	searchBasedFileMatchLimit = 500
+
+	slowSearchBasedRequestThreshold = time.Second
+)
+
+// searchBasedQueryResolver answers code intelligence queries for a path that is not covered by any
+// precise upload. Definitions are symbols with the same name as the identifier under the requested
+// position, and references are whole-word text matches of that identifier. These results are heuristic
+// and are ranked so that matches closer to the requested path come first.
+//
+// Queries that cannot be answered without precise data (ranges, diagnostics, implementations, and API
+// documentation) return empty results.
+type searchBasedQueryResolver struct {
+	gitserverClient GitserverClient
+	searchClient    SearchClient
+	repositoryID    int
+	repositoryName  api.RepoName
+	commit          string
+	path            string
+	operations      *operations
+}
+
+var _ QueryResolver = &searchBasedQueryResolver{}
+
+// NewSearchBasedQueryResolver creates a new query resolver that answers queries for the given repository,
+// commit, and path from the symbols service and searcher rather than from precise uploads.
+func NewSearchBasedQueryResolver(
+	gitserverClient GitserverClient,
+	searchClient SearchClient,
+	repositoryID int,
+	repositoryName api.RepoName,
+	commit string,
+	path string,
+	operations *operations,
+) QueryResolver {
+	return newSearchBasedQueryResolver(gitserverClient, searchClient, repositoryID, repositoryName, commit, path, operations)
+}
+
+func newSearchBasedQueryResolver(
+	gitserverClient GitserverClient,
+	searchClient SearchClient,
+	repositoryID int,
+	repositoryName api.RepoName,
+	commit string,
+	path string,
+	operations *operations,
+) *searchBasedQueryResolver {
+	return &searchBasedQueryResolver{
+		gitserverClient: gitserverClient,
+		searchClient:    searchClient,
+		repositoryID:    repositoryID,
+		repositoryName:  repositoryName,
+		commit:          commit,
+		path:            path,
+		operations:      operations,
+	}
+}
+
+func (r *searchBasedQueryResolver) observationArgs(line, character int) observation.Args {
+	return observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", r.repositoryID),
+			log.String("commit", r.commit),
+			log.String("path", r.path),
+			log.Int("line", line),
+			log.Int("character", character),
+		},
+	}
+}
+
+// Definitions returns the symbols named after the identifier at the given position.
+func (r *searchBasedQueryResolver) Definitions(ctx context.Context, line, character int) (_ []AdjustedLocation, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "SearchBasedDefinitions", r.operations.searchBasedDefinitions, slowSearchBasedRequestThreshold, r.observationArgs(line, character))
+	defer endObservation()
+
+	name, _, ok, err := r.identifierAtPosition(ctx, line, character)
+	if err != nil || !ok {
+		return nil, err
+	}
+	traceLog(log.String("name", name))
+
+	locations, err := r.definitions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numLocations", len(locations)))
+
+	if len(locations) > DefinitionsLimit {
+		locations = locations[:DefinitionsLimit]
+	}
+
+	return locations, nil
+}
+
+// References returns the whole-word matches of the identifier at the given position. Only files with the
+// same extension as the target path are searched. The cursor is the offset into the ranked result set.
+func (r *searchBasedQueryResolver) References(ctx context.Context, line, character, limit int, rawCursor string) (_ []AdjustedLocation, _ string, err error) {
+	ctx, traceLog, endObservation := observeResolver(ctx, &err, "SearchBasedReferences", r.operations.searchBasedReferences, slowSearchBasedRequestThreshold, r.observationArgs(line, character))
+	defer endObservation()
+
+	offset := 0
+	if rawCursor != "" {
+		if offset, err = strconv.Atoi(rawCursor); err != nil || offset < 0 {
+			return nil, "", errors.Errorf("invalid cursor: %q", rawCursor)
+		}
+	}
+
+	name, _, ok, err := r.identifierAtPosition(ctx, line, character)
+	if err != nil || !ok {
+		return nil, "", err
+	}
+	traceLog(log.String("name", name))
+
+	var includePatterns []string
+	if ext := filepath.Ext(r.path); ext != "" {
+		includePatterns = append(includePatterns, regexp.QuoteMeta(ext)+"$")
+	}
+
+	fileMatches, err := r.searchClient.SearchWord(ctx, r.repositoryName, api.CommitID(r.commit), name, includePatterns, searchBasedFileMatchLimit)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "searchClient.SearchWord")
+	}
+
+	var locations []AdjustedLocation
+	for _, fileMatch := range fileMatches {
+		for _, lineMatch := range fileMatch.LineMatches {
+			for _, offsetAndLength := range lineMatch.OffsetAndLengths {
+				locations = append(locations, r.location(fileMatch.Path, lineMatch.LineNumber, offsetAndLength[0], offsetAndLength[0]+offsetAndLength[1]))
+			}
+		}
+	}
+	rankSearchBasedLocations(r.path, locations)
+	traceLog(log.Int("numLocations", len(locations)))
+
+	if offset >= len(locations) {
+		return nil, "", nil
+	}
+	locations = locations[offset:]
+
+	nextCursor := ""
+	if len(locations) > limit {
+		locations = locations[:limit]
+		nextCursor = strconv.Itoa(offset + limit)
+	}
+
+	return locations, nextCursor, nil
+}
+
+// Hover returns the source line of the highest ranked definition of the identifier at the given position.
+func (r *searchBasedQueryResolver) Hover(ctx context.Context, line, character int) (_ string, _ lsifstore.Range, _ bool, err error) {
+	ctx, _, endObservation := observeResolver(ctx, &err, "SearchBasedHover", r.operations.searchBasedHover, slowSearchBasedRequestThreshold, r.observationArgs(line, character))
+	defer endObservation()
+
+	name, rn, ok, err := r.identifierAtPosition(ctx, line, character)
+	if err != nil || !ok {
+		return "", lsifstore.Range{}, false, err
+	}
+
+	locations, err := r.definitions(ctx, name)
+	if err != nil || len(locations) == 0 {
+		return "", lsifstore.Range{}, false, err
+	}
+
+	definition := locations[0]
+	contents, err := r.gitserverClient.RawContents(ctx, r.repositoryID, r.commit, definition.Path)
+	if err != nil {
+		return "", lsifstore.Range{}, false, errors.Wrap(err, "gitserverClient.RawContents")
+	}
+
+	lines := strings.Split(string(contents), "\n")
+	if definition.AdjustedRange.Start.Line >= len(lines) {
+		return "", lsifstore.Range{}, false, nil
+	}
+	text := strings.TrimSpace(lines[definition.AdjustedRange.Start.Line])
+
+	return "```" + strings.TrimPrefix(filepath.Ext(definition.Path), ".") + "\n" + text + "\n```", rn, true, nil
+}
+
+// Ranges returns no ranges, as search-based results cannot be computed in bulk.
+func (r *searchBasedQueryResolver) Ranges(ctx context.Context, startLine, endLine int) ([]AdjustedCodeIntelligenceRange, error) {
+	return nil, nil
+}
+
+// Implementations returns no locations, as implementations require precise data.
+func (r *searchBasedQueryResolver) Implementations(ctx context.Context, line, character, limit int, rawCursor string) ([]AdjustedLocation, string, error) {
+	return nil, "", nil
+}
+
+// Diagnostics returns no diagnostics, as diagnostics require precise data.
+func (r *searchBasedQueryResolver) Diagnostics(ctx context.Context, limit int) ([]AdjustedDiagnostic, int, error) {
+	return nil, 0, nil
+}
+
+// DocumentationPage returns no page, as API documentation requires precise data.
+func (r *searchBasedQueryResolver) DocumentationPage(ctx context.Context, pathID string) (*semantic.DocumentationPageData, error) {
+	return nil, nil
+}
+
+// DocumentationPathInfo returns no path info, as API documentation requires precise data.
+func (r *searchBasedQueryResolver) DocumentationPathInfo(ctx context.Context, pathID string) (*semantic.DocumentationPathInfoData, error) {
+	return nil, nil
+}
+
+// Documentation returns no documentation, as API documentation requires precise data.
+func (r *searchBasedQueryResolver) Documentation(ctx context.Context, line, character int) ([]*Documentation, error) {
+	return nil, nil
+}
+
+// DocumentationDefinitions returns no locations, as API documentation requires precise data.
+func (r *searchBasedQueryResolver) DocumentationDefinitions(ctx context.Context, pathID string) ([]AdjustedLocation, error) {
+	return nil, nil
+}
+
+// DocumentationReferences returns no locations, as API documentation requires precise data.
+func (r *searchBasedQueryResolver) DocumentationReferences(ctx context.Context, pathID string, limit int, rawCursor string) ([]AdjustedLocation, string, error) {
+	return nil, "", nil
+}
+
+// definitions returns the ranked locations of the symbols with the given name.
+func (r *searchBasedQueryResolver) definitions(ctx context.Context, name string) ([]AdjustedLocation, error) {
+	symbols, err := r.searchClient.SearchSymbols(ctx, r.repositoryName, api.CommitID(r.commit), name, searchBasedSymbolsLimit)
+	if err != nil {
+		return nil, errors.Wrap(err, "searchClient.SearchSymbols")
+	}
+
+	locations := make([]AdjustedLocation, 0, len(symbols))
+	for _, symbol := range symbols {
+		rn := symbol.Range()
+		locations = append(locations, r.location(symbol.Path, rn.Start.Line, rn.Start.Character, rn.End.Character))
+	}
+	rankSearchBasedLocations(r.path, locations)
+
+	return locations, nil
+}
+
+// identifierAtPosition returns the identifier enclosing the given position of the target path, along with
+// its range. A false-valued flag is returned if there is no identifier at that position.
+func (r *searchBasedQueryResolver) identifierAtPosition(ctx context.Context, line, character int) (string, lsifstore.Range, bool, error) {
+	contents, err := r.gitserverClient.RawContents(ctx, r.repositoryID, r.commit, r.path)
+	if err != nil {
+		return "", lsifstore.Range{}, false, errors.Wrap(err, "gitserverClient.RawContents")
+	}
+
+	lines := strings.Split(string(contents), "\n")
+	if line < 0 || line >= len(lines) {
+		return "", lsifstore.Range{}, false, nil
+	}
+
+	text := []rune(lines[line])
+	if character < 0 || character > len(text) {
+		return "", lsifstore.Range{}, false, nil
+	}
+
+	start := character
+	for start > 0 && isIdentifierRune(text[start-1]) {
+		start--
+	}
+	end := character
+	for end < len(text) && isIdentifierRune(text[end]) {
+		end++
+	}
+	if start == end || unicode.IsDigit(text[start]) {
+		return "", lsifstore.Range{}, false, nil
+	}
+
+	return string(text[start:end]), newRange(line, start, line, end), true, nil
+}
+
+// location creates a location within the target repository and commit. Search-based locations are not
+// attached to an upload, so the dump carries only the repository and commit used to resolve the path.
+func (r *searchBasedQueryResolver) location(path string, line, startCharacter, endCharacter int) AdjustedLocation {
+	return AdjustedLocation{
+		Dump: dbstore.Dump{
+			RepositoryID:   r.repositoryID,
+			RepositoryName: string(r.repositoryName),
+			Commit:         r.commit,
+		},
+		Path:           path,
+		AdjustedCommit: r.commit,
+		AdjustedRange:  newRange(line, startCharacter, line, endCharacter),
+	}
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func newRange(startLine, startCharacter, endLine, endCharacter int) lsifstore.Range {
+	return lsifstore.Range{
+		Start: lsifstore.Position{Line: startLine, Character: startCharacter},
+		End:   lsifstore.Position{Line: endLine, Character: endCharacter},
+	}
+}
+
+// rankSearchBasedLocations sorts the given locations in place so that locations in the target path come
+// first, followed by locations in the same directory, then locations in files of the same language (by
+// extension), and finally all other locations. Ties are broken by path and position.
+func rankSearchBasedLocations(path string, locations []AdjustedLocation) {
+	rank := func(candidate string) int {
+		switch {
+		case candidate == path:
+			return 0
+		case filepath.Dir(candidate) == filepath.Dir(path):
+			return 1
+		case filepath.Ext(candidate) == filepath.Ext(path):
+			return 2
+		default:
+			return 3
+		}
+	}
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		if ri, rj := rank(locations[i].Path), rank(locations[j].Path); ri != rj {
+			return ri < rj
+		}
+		if locations[i].Path != locations[j].Path {
+			return locations[i].Path < locations[j].Path
+		}
+
+		si, sj := locations[i].AdjustedRange.Start, locations[j].AdjustedRange.Start
+		if si.Line != sj.Line {
+			return si.Line < sj.Line
+		}
+		return si.Character < sj.Character
+	})
+}
@@ -0,0 +1,156 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+const testSearchBasedSource = `package main
+
+func main() {
+	padLeft("foo", 5)
+}
+`
+
+func TestSearchBasedDefinitions(t *testing.T) {
+	mockGitserverClient := NewMockGitserverClient()
+	mockSearchClient := NewMockSearchClient()
+	mockGitserverClient.RawContentsFunc.SetDefaultReturn([]byte(testSearchBasedSource), nil)
+	mockSearchClient.SearchSymbolsFunc.SetDefaultReturn([]result.Symbol{
+		{Name: "padLeft", Path: "vendor/pad.go", Line: 10, Pattern: "/^func padLeft(s string, n int) string {$/"},
+		{Name: "padLeft", Path: "cmd/pad.go", Line: 3, Pattern: "/^func padLeft() {$/"},
+		{Name: "padLeft", Path: "cmd/main.go", Line: 20, Pattern: "/^func padLeft() {$/"},
+	}, nil)
+
+	resolver := newSearchBasedQueryResolver(mockGitserverClient, mockSearchClient, 42, "github.com/test/repo", "deadbeef", "cmd/main.go", newOperations(&observation.TestContext))
+	locations, err := resolver.Definitions(context.Background(), 3, 3)
+	if err != nil {
+		t.Fatalf("unexpected error querying definitions: %s", err)
+	}
+
+	var paths []string
+	for _, location := range locations {
+		paths = append(paths, location.Path)
+	}
+	if diff := cmp.Diff([]string{"cmd/main.go", "cmd/pad.go", "vendor/pad.go"}, paths); diff != "" {
+		t.Errorf("unexpected definition paths (-want +got):\n%s", diff)
+	}
+
+	expectedDump := dbstore.Dump{RepositoryID: 42, RepositoryName: "github.com/test/repo", Commit: "deadbeef"}
+	if diff := cmp.Diff(expectedDump, locations[0].Dump); diff != "" {
+		t.Errorf("unexpected dump (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(newRange(19, 5, 19, 12), locations[0].AdjustedRange); diff != "" {
+		t.Errorf("unexpected range (-want +got):\n%s", diff)
+	}
+
+	if history := mockSearchClient.SearchSymbolsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of symbol searches. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != "github.com/test/repo" || history[0].Arg2 != api.CommitID("deadbeef") || history[0].Arg3 != "padLeft" {
+		t.Errorf("unexpected symbol search arguments: %v", history[0].Args())
+	}
+}
+
+func TestSearchBasedDefinitionsNoIdentifier(t *testing.T) {
+	mockGitserverClient := NewMockGitserverClient()
+	mockSearchClient := NewMockSearchClient()
+	mockGitserverClient.RawContentsFunc.SetDefaultReturn([]byte(testSearchBasedSource), nil)
+
+	resolver := newSearchBasedQueryResolver(mockGitserverClient, mockSearchClient, 42, "github.com/test/repo", "deadbeef", "cmd/main.go", newOperations(&observation.TestContext))
+	for _, position := range []lsifstore.Position{{Line: 1, Character: 0}, {Line: 3, Character: 16}, {Line: 20, Character: 0}} {
+		locations, err := resolver.Definitions(context.Background(), position.Line, position.Character)
+		if err != nil {
+			t.Fatalf("unexpected error querying definitions: %s", err)
+		}
+		if len(locations) != 0 {
+			t.Errorf("unexpected locations at %v: %v", position, locations)
+		}
+	}
+
+	if history := mockSearchClient.SearchSymbolsFunc.History(); len(history) != 0 {
+		t.Errorf("unexpected number of symbol searches. want=%d have=%d", 0, len(history))
+	}
+}
+
+func TestSearchBasedReferences(t *testing.T) {
+	mockGitserverClient := NewMockGitserverClient()
+	mockSearchClient := NewMockSearchClient()
+	mockGitserverClient.RawContentsFunc.SetDefaultReturn([]byte(testSearchBasedSource), nil)
+	mockSearchClient.SearchWordFunc.SetDefaultReturn([]*protocol.FileMatch{
+		{Path: "other/b.go", LineMatches: []protocol.LineMatch{{LineNumber: 7, OffsetAndLengths: [][2]int{{2, 7}}}}},
+		{Path: "cmd/main.go", LineMatches: []protocol.LineMatch{{LineNumber: 3, OffsetAndLengths: [][2]int{{1, 7}}}}},
+		{Path: "cmd/pad.go", LineMatches: []protocol.LineMatch{{LineNumber: 2, OffsetAndLengths: [][2]int{{5, 7}, {20, 7}}}}},
+	}, nil)
+
+	resolver := newSearchBasedQueryResolver(mockGitserverClient, mockSearchClient, 42, "github.com/test/repo", "deadbeef", "cmd/main.go", newOperations(&observation.TestContext))
+
+	var locations []lsifstore.Location
+	cursor := ""
+	for {
+		page, nextCursor, err := resolver.References(context.Background(), 3, 3, 2, cursor)
+		if err != nil {
+			t.Fatalf("unexpected error querying references: %s", err)
+		}
+		for _, location := range page {
+			locations = append(locations, lsifstore.Location{Path: location.Path, Range: location.AdjustedRange})
+		}
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+
+	expectedLocations := []lsifstore.Location{
+		{Path: "cmd/main.go", Range: newRange(3, 1, 3, 8)},
+		{Path: "cmd/pad.go", Range: newRange(2, 5, 2, 12)},
+		{Path: "cmd/pad.go", Range: newRange(2, 20, 2, 27)},
+		{Path: "other/b.go", Range: newRange(7, 2, 7, 9)},
+	}
+	if diff := cmp.Diff(expectedLocations, locations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+
+	for _, call := range mockSearchClient.SearchWordFunc.History() {
+		if call.Arg3 != "padLeft" {
+			t.Errorf("unexpected word. want=%q have=%q", "padLeft", call.Arg3)
+		}
+		if diff := cmp.Diff([]string{`\.go$`}, call.Arg4); diff != "" {
+			t.Errorf("unexpected include patterns (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestSearchBasedHover(t *testing.T) {
+	mockGitserverClient := NewMockGitserverClient()
+	mockSearchClient := NewMockSearchClient()
+	mockGitserverClient.RawContentsFunc.PushReturn([]byte(testSearchBasedSource), nil)
+	mockGitserverClient.RawContentsFunc.PushReturn([]byte("package main\n\n\tfunc padLeft(s string, n int) string {\n"), nil)
+	mockSearchClient.SearchSymbolsFunc.SetDefaultReturn([]result.Symbol{
+		{Name: "padLeft", Path: "cmd/pad.go", Line: 3},
+	}, nil)
+
+	resolver := newSearchBasedQueryResolver(mockGitserverClient, mockSearchClient, 42, "github.com/test/repo", "deadbeef", "cmd/main.go", newOperations(&observation.TestContext))
+	text, rn, exists, err := resolver.Hover(context.Background(), 3, 3)
+	if err != nil {
+		t.Fatalf("unexpected error querying hover: %s", err)
+	}
+	if !exists {
+		t.Fatalf("expected hover text to exist")
+	}
+
+	if expectedText := "```go\nfunc padLeft(s string, n int) string {\n```"; text != expectedText {
+		t.Errorf("unexpected hover text. want=%q have=%q", expectedText, text)
+	}
+	if diff := cmp.Diff(newRange(3, 1, 3, 8), rn); diff != "" {
+		t.Errorf("unexpected range (-want +got):\n%s", diff)
+	}
+}
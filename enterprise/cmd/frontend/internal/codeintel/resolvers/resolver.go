@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"sort"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/opentracing/opentracing-go/log"
 
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
@@ -31,14 +33,17 @@ type Resolver interface {
 	IndexConfiguration(ctx context.Context, repositoryID int) ([]byte, error)
 	UpdateIndexConfigurationByRepositoryID(ctx context.Context, repositoryID int, configuration string) error
 	CommitGraph(ctx context.Context, repositoryID int) (gql.CodeIntelligenceCommitGraphResolver, error)
+	CodeIntelligenceFreshness(ctx context.Context, repositoryID int, path string) ([]gql.CodeIntelligenceBranchFreshnessResolver, error)
 	QueueAutoIndexJobForRepo(ctx context.Context, repositoryID int) error
 	QueryResolver(ctx context.Context, args *gql.GitBlobLSIFDataArgs) (QueryResolver, error)
+	SearchBasedQueryResolver(ctx context.Context, args *gql.GitBlobLSIFDataArgs) (QueryResolver, error)
 }
 
 type resolver struct {
 	dbStore         DBStore
 	lsifStore       LSIFStore
 	gitserverClient GitserverClient
+	searchClient    SearchClient
 	indexEnqueuer   IndexEnqueuer
 	hunkCache       HunkCache
 	operations      *operations
@@ -49,17 +54,19 @@ func NewResolver(
 	dbStore DBStore,
 	lsifStore LSIFStore,
 	gitserverClient GitserverClient,
+	searchClient SearchClient,
 	indexEnqueuer IndexEnqueuer,
 	hunkCache HunkCache,
 	observationContext *observation.Context,
 ) Resolver {
-	return newResolver(dbStore, lsifStore, gitserverClient, indexEnqueuer, hunkCache, observationContext)
+	return newResolver(dbStore, lsifStore, gitserverClient, searchClient, indexEnqueuer, hunkCache, observationContext)
 }
 
 func newResolver(
 	dbStore DBStore,
 	lsifStore LSIFStore,
 	gitserverClient GitserverClient,
+	searchClient SearchClient,
 	indexEnqueuer IndexEnqueuer,
 	hunkCache HunkCache,
 	observationContext *observation.Context,
@@ -68,6 +75,7 @@ func newResolver(
 		dbStore:         dbStore,
 		lsifStore:       lsifStore,
 		gitserverClient: gitserverClient,
+		searchClient:    searchClient,
 		indexEnqueuer:   indexEnqueuer,
 		hunkCache:       hunkCache,
 		operations:      newOperations(observationContext),
@@ -151,6 +159,49 @@ func (r *resolver) CommitGraph(ctx context.Context, repositoryID int) (gql.CodeI
 	return NewCommitGraphResolver(stale, updatedAt), nil
 }
 
+// CodeIntelligenceFreshness returns the freshness of the precise code intelligence data that can
+// answer queries for the given path at the head of each branch of the given repository. Visible
+// uploads are read from the last commit graph update rather than recalculated.
+func (r *resolver) CodeIntelligenceFreshness(ctx context.Context, repositoryID int, path string) ([]gql.CodeIntelligenceBranchFreshnessResolver, error) {
+	branchDescriptions, err := r.gitserverClient.BranchDescriptions(ctx, repositoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "gitserverClient.BranchDescriptions")
+	}
+
+	commits := make([]string, 0, len(branchDescriptions))
+	for commit := range branchDescriptions {
+		commits = append(commits, commit)
+	}
+
+	nearestUploads, err := r.dbStore.NearestUploadsForCommits(ctx, repositoryID, commits, path)
+	if err != nil {
+		return nil, errors.Wrap(err, "dbStore.NearestUploadsForCommits")
+	}
+
+	branches := make([]*BranchFreshnessResolver, 0, len(commits))
+	for commit, descriptions := range branchDescriptions {
+		for _, branchDescription := range descriptions {
+			branches = append(branches, NewBranchFreshnessResolver(branchDescription.Name, branchDescription.IsDefaultBranch, commit, nearestUploads[commit]))
+		}
+	}
+
+	// Default branch first, then alphabetical
+	sort.Slice(branches, func(i, j int) bool {
+		if branches[i].isDefaultBranch != branches[j].isDefaultBranch {
+			return branches[i].isDefaultBranch
+		}
+
+		return branches[i].name < branches[j].name
+	})
+
+	resolvers := make([]gql.CodeIntelligenceBranchFreshnessResolver, 0, len(branches))
+	for _, branch := range branches {
+		resolvers = append(resolvers, branch)
+	}
+
+	return resolvers, nil
+}
+
 func (r *resolver) QueueAutoIndexJobForRepo(ctx context.Context, repositoryID int) error {
 	return r.indexEnqueuer.ForceQueueIndexesForRepository(ctx, repositoryID)
 }
@@ -200,3 +251,18 @@ func (r *resolver) QueryResolver(ctx context.Context, args *gql.GitBlobLSIFDataA
 		r.operations,
 	), nil
 }
+
+// SearchBasedQueryResolver constructs a new query resolver instance which answers code intel queries for
+// the given repository, commit, and path from symbol and text search results. This resolver is used when
+// no precise upload can answer queries for the path.
+func (r *resolver) SearchBasedQueryResolver(ctx context.Context, args *gql.GitBlobLSIFDataArgs) (QueryResolver, error) {
+	return NewSearchBasedQueryResolver(
+		r.gitserverClient,
+		r.searchClient,
+		int(args.Repo.ID),
+		args.Repo.Name,
+		string(args.Commit),
+		args.Path,
+		r.operations,
+	), nil
+}
@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -9,6 +10,7 @@ import (
 
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/autoindex/enqueuer"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -20,7 +22,7 @@ func TestQueryResolver(t *testing.T) {
 	mockLSIFStore := NewMockLSIFStore()
 	mockGitserverClient := NewMockGitserverClient()
 
-	resolver := NewResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, &observation.TestContext)
+	resolver := NewResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, nil, nil, &observation.TestContext)
 	queryResolver, err := resolver.QueryResolver(context.Background(), &gql.GitBlobLSIFDataArgs{
 		Repo:      &types.Repo{ID: 50},
 		Commit:    api.CommitID("deadbeef"),
@@ -80,7 +82,7 @@ func TestFallbackIndexConfiguration(t *testing.T) {
 	gitServerClient.HeadFunc.SetDefaultReturn("deadbeef", true, nil)
 	gitServerClient.ListFilesFunc.SetDefaultReturn([]string{"go.mod"}, nil)
 
-	resolver := NewResolver(mockDBStore, mockLSIFStore, mockGitserverClient, indexEnqueuer, nil, &observation.TestContext)
+	resolver := NewResolver(mockDBStore, mockLSIFStore, mockGitserverClient, nil, indexEnqueuer, nil, &observation.TestContext)
 	json, err := resolver.IndexConfiguration(context.Background(), 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
@@ -90,3 +92,65 @@ func TestFallbackIndexConfiguration(t *testing.T) {
 		t.Fatalf("Unexpected fallback index configuration:\n%s\n", diff)
 	}
 }
+
+func TestCodeIntelligenceFreshness(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.BranchDescriptionsFunc.SetDefaultReturn(map[string][]gitserver.RefDescription{
+		"c1": {{Name: "main", Type: gitserver.RefTypeBranch, IsDefaultBranch: true}},
+		"c2": {{Name: "feature", Type: gitserver.RefTypeBranch}, {Name: "feature-copy", Type: gitserver.RefTypeBranch}},
+		"c3": {{Name: "abandoned", Type: gitserver.RefTypeBranch}},
+	}, nil)
+	mockDBStore.NearestUploadsForCommitsFunc.SetDefaultReturn(map[string][]dbstore.NearestUpload{
+		"c1": {
+			{UploadID: 1, Root: "", Indexer: "lsif-go", Distance: 2},
+			{UploadID: 2, Root: "web/", Indexer: "lsif-tsc", Distance: 5},
+			{UploadID: 3, Root: "cmd/", Indexer: "lsif-go", Distance: 7},
+		},
+		"c2": {
+			{UploadID: 2, Root: "web/", Indexer: "lsif-tsc", Distance: 0},
+		},
+	}, nil)
+
+	resolver := NewResolver(mockDBStore, nil, mockGitserverClient, nil, nil, nil, &observation.TestContext)
+	branches, err := resolver.CodeIntelligenceFreshness(context.Background(), 50, "web/")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	type branchFreshness struct {
+		BranchName      string
+		IsDefaultBranch bool
+		Commit          string
+		Distance        *int32
+		Indexers        []string
+	}
+	var freshness []branchFreshness
+	for _, branch := range branches {
+		freshness = append(freshness, branchFreshness{branch.BranchName(), branch.IsDefaultBranch(), branch.Commit(), branch.Distance(), branch.Indexers()})
+	}
+
+	distance := func(v int32) *int32 { return &v }
+	expected := []branchFreshness{
+		{BranchName: "main", IsDefaultBranch: true, Commit: "c1", Distance: distance(2), Indexers: []string{"lsif-go", "lsif-tsc"}},
+		{BranchName: "abandoned", Commit: "c3", Indexers: []string{}},
+		{BranchName: "feature", Commit: "c2", Distance: distance(0), Indexers: []string{"lsif-tsc"}},
+		{BranchName: "feature-copy", Commit: "c2", Distance: distance(0), Indexers: []string{"lsif-tsc"}},
+	}
+	if diff := cmp.Diff(expected, freshness); diff != "" {
+		t.Errorf("unexpected branch freshness (-want +got):\n%s", diff)
+	}
+
+	if history := mockDBStore.NearestUploadsForCommitsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of NearestUploadsForCommits calls. want=%d have=%d", 1, len(history))
+	} else {
+		commits := history[0].Arg2
+		sort.Strings(commits)
+		if diff := cmp.Diff([]string{"c1", "c2", "c3"}, commits); diff != "" {
+			t.Errorf("unexpected commits (-want +got):\n%s", diff)
+		}
+		if history[0].Arg3 != "web/" {
+			t.Errorf("unexpected path. want=%q have=%q", "web/", history[0].Arg3)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package codeintel
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/symbols"
+)
+
+// searchFetchTimeout is the time searcher may spend fetching an archive of the target commit.
+const searchFetchTimeout = 10 * time.Second
+
+// searchClient implements resolvers.SearchClient with the symbols service and searcher.
+type searchClient struct{}
+
+func (searchClient) SearchSymbols(ctx context.Context, repo api.RepoName, commit api.CommitID, name string, limit int) ([]result.Symbol, error) {
+	results, err := symbols.DefaultClient.Search(ctx, search.SymbolsParameters{
+		Repo:            repo,
+		CommitID:        commit,
+		Query:           "^" + regexp.QuoteMeta(name) + "$",
+		IsRegExp:        true,
+		IsCaseSensitive: true,
+		First:           limit,
+	})
+	if err != nil || results == nil {
+		return nil, err
+	}
+
+	return *results, nil
+}
+
+func (searchClient) SearchWord(ctx context.Context, repo api.RepoName, commit api.CommitID, word string, includePatterns []string, limit int) ([]*protocol.FileMatch, error) {
+	matches, _, err := searcher.Search(ctx, search.SearcherURLs(), repo, "", commit, false, &search.TextPatternInfo{
+		Pattern:               regexp.QuoteMeta(word),
+		IsRegExp:              true,
+		IsWordMatch:           true,
+		IsCaseSensitive:       true,
+		IncludePatterns:       includePatterns,
+		FileMatchLimit:        int32(limit),
+		PatternMatchesContent: true,
+	}, searchFetchTimeout, nil)
+
+	return matches, err
+}
@@ -61,6 +61,8 @@ func Init(ctx context.Context, db dbutil.DB, outOfBandMigrationRunner *oobmigrat
 	queueOptions := map[string]handler.QueueOptions{
 		"codeintel": codeintelqueue.QueueOptions(db, codeintelConfig, observationContext),
 		"batches":   batches.QueueOptions(db, batchesConfig, observationContext),
+
+		"batch-spec-workspaces": batches.WorkspaceQueueOptions(db, observationContext),
 	}
 
 	handler, err := codeintel.NewCodeIntelUploadHandler(ctx, db, true)
@@ -24,3 +24,14 @@ func QueueOptions(db dbutil.DB, config *Config, observationContext *observation.
 		RecordTransformer: recordTransformer,
 	}
 }
+
+func WorkspaceQueueOptions(db dbutil.DB, observationContext *observation.Context) handler.QueueOptions {
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return transformBatchSpecWorkspace(ctx, db, record.(*btypes.BatchSpecWorkspace))
+	}
+
+	return handler.QueueOptions{
+		Store:             background.NewWorkspaceExecutorStore(basestore.NewWithDB(db, sql.TxOptions{}), observationContext),
+		RecordTransformer: recordTransformer,
+	}
+}
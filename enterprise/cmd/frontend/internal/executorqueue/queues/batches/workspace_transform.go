@@ -0,0 +1,124 @@
+package batches
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// workspaceDiffImage is the image of the step that follows the steps of the
+// batch spec and prints the diff of the changes they made to the workspace.
+const workspaceDiffImage = "alpine/git:v2.30.2"
+
+// workspaceDiffCommands stage all changes in the workspace, except for the
+// scripts the executor writes, and print the diff. The diff is base64 encoded,
+// so that it isn't printed in lines of arbitrary length and can be told apart
+// from the shell tracing on stderr.
+var workspaceDiffCommands = []string{
+	"git add --all -- . ':(exclude).sourcegraph-executor'",
+	"git diff --cached --no-prefix --binary | base64",
+}
+
+// transformBatchSpecWorkspace transforms a *btypes.BatchSpecWorkspace into an
+// apiclient.Job that runs the steps of its batch spec in the workspace,
+// followed by a step that prints the resulting diff.
+func transformBatchSpecWorkspace(ctx context.Context, db dbutil.DB, ws *btypes.BatchSpecWorkspace) (apiclient.Job, error) {
+	// The executor queue has no actor, so we need the internal actor to load
+	// private repositories.
+	ctx = actor.WithInternalActor(ctx)
+
+	s := store.New(db, nil)
+
+	spec, err := s.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: ws.BatchSpecID})
+	if err != nil {
+		return apiclient.Job{}, errors.Wrap(err, "loading batch spec")
+	}
+
+	repo, err := s.Repos().Get(ctx, ws.RepoID)
+	if err != nil {
+		return apiclient.Job{}, errors.Wrap(err, "loading repository")
+	}
+
+	return workspaceJob(spec, repo, ws)
+}
+
+// workspaceJob builds the apiclient.Job for the given workspace of the batch
+// spec in the repository.
+func workspaceJob(spec *btypes.BatchSpec, repo *types.Repo, ws *btypes.BatchSpecWorkspace) (apiclient.Job, error) {
+	tctx := &btypes.WorkspaceTemplateContext{
+		BatchChangeName:        spec.Spec.Name,
+		BatchChangeDescription: spec.Spec.Description,
+		RepositoryName:         string(repo.Name),
+		Branch:                 strings.TrimPrefix(ws.Branch, "refs/heads/"),
+		Path:                   ws.Path,
+	}
+
+	dir := ws.Path
+	if dir == "" {
+		dir = "."
+	}
+
+	steps := make([]apiclient.DockerStep, 0, len(spec.Spec.Steps)+1)
+	for i, step := range spec.Spec.Steps {
+		run, err := tctx.Render(fmt.Sprintf("steps[%d].run", i), step.Run)
+		if err != nil {
+			return apiclient.Job{}, err
+		}
+
+		env, err := stepEnv(tctx, i, step)
+		if err != nil {
+			return apiclient.Job{}, err
+		}
+
+		steps = append(steps, apiclient.DockerStep{
+			Image:    step.Container,
+			Commands: []string{run},
+			Dir:      dir,
+			Env:      env,
+		})
+	}
+
+	steps = append(steps, apiclient.DockerStep{
+		Image:    workspaceDiffImage,
+		Commands: workspaceDiffCommands,
+		Dir:      ".",
+	})
+
+	return apiclient.Job{
+		ID:             int(ws.ID),
+		RepositoryName: string(repo.Name),
+		Commit:         ws.Commit,
+		DockerSteps:    steps,
+	}, nil
+}
+
+// stepEnv returns the rendered environment of the given step as sorted
+// NAME=value pairs.
+func stepEnv(tctx *btypes.WorkspaceTemplateContext, i int, step btypes.BatchSpecStep) ([]string, error) {
+	vars, err := step.Env.Resolve(nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving environment of steps[%d]", i)
+	}
+
+	env := make([]string, 0, len(vars))
+	for k, v := range vars {
+		rendered, err := tctx.Render(fmt.Sprintf("steps[%d].env.%s", i, k), v)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, k+"="+rendered)
+	}
+	sort.Strings(env)
+
+	return env, nil
+}
@@ -0,0 +1,132 @@
+package batches
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestWorkspaceJob(t *testing.T) {
+	spec, err := btypes.NewBatchSpecFromRaw(`
+name: hello-world
+on:
+  - repository: github.com/sourcegraph/sourcegraph
+steps:
+  - run: echo "${{ repository.name }}" >> message.txt
+    container: alpine:3
+    env:
+      FOO: bar
+      PROJECT: ${{ steps.path }}
+  - run: cat message.txt
+    container: alpine:3
+changesetTemplate:
+  title: Hello World
+  body: My first batch change!
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &types.Repo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"}
+
+	t.Run("repository root", func(t *testing.T) {
+		ws := &btypes.BatchSpecWorkspace{
+			ID:     42,
+			RepoID: repo.ID,
+			Branch: "refs/heads/main",
+			Commit: "d34db33f",
+		}
+
+		job, err := workspaceJob(spec, repo, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := apiclient.Job{
+			ID:             42,
+			RepositoryName: "github.com/sourcegraph/sourcegraph",
+			Commit:         "d34db33f",
+			DockerSteps: []apiclient.DockerStep{
+				{
+					Image:    "alpine:3",
+					Commands: []string{`echo "github.com/sourcegraph/sourcegraph" >> message.txt`},
+					Dir:      ".",
+					Env:      []string{"FOO=bar", "PROJECT="},
+				},
+				{
+					Image:    "alpine:3",
+					Commands: []string{"cat message.txt"},
+					Dir:      ".",
+					Env:      []string{},
+				},
+				{
+					Image:    workspaceDiffImage,
+					Commands: workspaceDiffCommands,
+					Dir:      ".",
+				},
+			},
+		}
+		if diff := cmp.Diff(want, job); diff != "" {
+			t.Errorf("unexpected job (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("sub-directory", func(t *testing.T) {
+		ws := &btypes.BatchSpecWorkspace{
+			ID:     43,
+			RepoID: repo.ID,
+			Branch: "refs/heads/main",
+			Commit: "d34db33f",
+			Path:   "a/b",
+		}
+
+		job, err := workspaceJob(spec, repo, ws)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, step := range job.DockerSteps[:2] {
+			if step.Dir != "a/b" {
+				t.Errorf("step %d: wrong dir %q", i, step.Dir)
+			}
+		}
+		if have, want := job.DockerSteps[0].Env, []string{"FOO=bar", "PROJECT=a/b"}; !cmp.Equal(have, want) {
+			t.Errorf("wrong env (-want +got):\n%s", cmp.Diff(want, have))
+		}
+		// The diff is always taken at the repository root.
+		if dir := job.DockerSteps[2].Dir; dir != "." {
+			t.Errorf("wrong dir of diff step %q", dir)
+		}
+	})
+
+	t.Run("unknown template variable", func(t *testing.T) {
+		spec, err := btypes.NewBatchSpecFromRaw(`
+name: hello-world
+on:
+  - repository: github.com/sourcegraph/sourcegraph
+steps:
+  - run: echo "${{ outputs.foo }}"
+    container: alpine:3
+changesetTemplate:
+  title: Hello World
+  body: My first batch change!
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := workspaceJob(spec, repo, &btypes.BatchSpecWorkspace{ID: 44}); err == nil {
+			t.Fatal("expected error, got none")
+		}
+	})
+}
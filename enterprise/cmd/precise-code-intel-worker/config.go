@@ -14,6 +14,9 @@ type Config struct {
 	WorkerPollInterval time.Duration
 	WorkerConcurrency  int
 	WorkerBudget       int64
+
+	CorrelationMemoryBudget int64
+	CorrelationSpillDir     string
 }
 
 func (c *Config) Load() {
@@ -24,4 +27,6 @@ func (c *Config) Load() {
 	c.WorkerPollInterval = c.GetInterval("PRECISE_CODE_INTEL_WORKER_POLL_INTERVAL", "1s", "Interval between queries to the upload queue.")
 	c.WorkerConcurrency = c.GetInt("PRECISE_CODE_INTEL_WORKER_CONCURRENCY", "1", "The maximum number of indexes that can be processed concurrently.")
 	c.WorkerBudget = int64(c.GetInt("PRECISE_CODE_INTEL_WORKER_BUDGET", "0", "The amount of compressed input data (in bytes) a worker can process concurrently. Zero acts as an infinite budget."))
+	c.CorrelationMemoryBudget = int64(c.GetInt("PRECISE_CODE_INTEL_CORRELATION_MEMORY_BUDGET", "0", "The approximate amount of correlation data (in bytes) held in memory while correlating a single upload. Data beyond this budget is spilled to disk. Zero keeps all data in memory."))
+	c.CorrelationSpillDir = c.GetOptional("PRECISE_CODE_INTEL_CORRELATION_SPILL_DIR", "The directory in which correlation data is spilled to disk. Defaults to the system temporary directory.")
 }
@@ -35,6 +35,9 @@ type handler struct {
 	gitserverClient GitserverClient
 	enableBudget    bool
 	budgetRemaining int64
+
+	// correlateOptions bounds the memory used to correlate a single upload.
+	correlateOptions conversion.CorrelateOptions
 }
 
 var _ workerutil.Handler = &handler{}
@@ -98,9 +101,14 @@ func (h *handler) handle(ctx context.Context, upload store.Upload) (requeued boo
 	}
 
 	return false, withUploadData(ctx, h.uploadStore, upload.ID, func(r io.Reader) (err error) {
-		groupedBundleData, err := conversion.Correlate(ctx, r, upload.Root, getChildren)
+		// Canceling this context stops the goroutines producing the grouped bundle data (and
+		// removes any spill file they hold) if it is not fully consumed by writeData.
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		groupedBundleData, err := conversion.CorrelateWithOptions(ctx, r, upload.Root, getChildren, h.correlateOptions)
 		if err != nil {
-			return errors.Wrap(err, "conversion.Correlate")
+			return errors.Wrap(err, "conversion.CorrelateWithOptions")
 		}
 
 		// Note: this is writing to a different database than the block below, so we need to use a
@@ -261,6 +269,9 @@ func writeData(ctx context.Context, lsifStore LSIFStore, id int, groupedBundleDa
 	if err := tx.WriteReferences(ctx, id, groupedBundleData.References); err != nil {
 		return errors.Wrap(err, "store.WriteReferences")
 	}
+	if err := tx.WriteImplementations(ctx, id, groupedBundleData.Implementations); err != nil {
+		return errors.Wrap(err, "store.WriteImplementations")
+	}
 	if err := tx.WriteDocumentationPages(ctx, id, groupedBundleData.DocumentationPages); err != nil {
 		return errors.Wrap(err, "store.WriteDocumentationPages")
 	}
@@ -270,6 +281,9 @@ func writeData(ctx context.Context, lsifStore LSIFStore, id int, groupedBundleDa
 	if err := tx.WriteDocumentationMappings(ctx, id, groupedBundleData.DocumentationMappings); err != nil {
 		return errors.Wrap(err, "store.WriteDocumentationMappings")
 	}
+	if err := groupedBundleData.Err(); err != nil {
+		return errors.Wrap(err, "conversion.CorrelateWithOptions")
+	}
 
 	return nil
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion"
 )
 
 const addr = ":3188"
@@ -103,6 +104,10 @@ func main() {
 		config.WorkerPollInterval,
 		config.WorkerConcurrency,
 		config.WorkerBudget,
+		conversion.CorrelateOptions{
+			MemoryBudget: config.CorrelationMemoryBudget,
+			SpillDir:     config.CorrelationSpillDir,
+		},
 		makeWorkerMetrics(observationContext),
 	)
 
@@ -83,6 +83,7 @@ func TestAuthzProvidersFromConfig(t *testing.T) {
 		cfg                          conf.Unified
 		gitlabConnections            []*schema.GitLabConnection
 		bitbucketServerConnections   []*schema.BitbucketServerConnection
+		bitbucketCloudConnections    []*schema.BitbucketCloudConnection
 		expAuthzAllowAccessByDefault bool
 		expAuthzProviders            func(*testing.T, []authz.Provider)
 		expSeriousProblems           []string
@@ -338,6 +339,19 @@ func TestAuthzProvidersFromConfig(t *testing.T) {
 			expAuthzAllowAccessByDefault: true,
 			expAuthzProviders:            providersEqual(),
 		},
+		{
+			description: "1 Bitbucket Cloud connection with authz disabled",
+			bitbucketCloudConnections: []*schema.BitbucketCloudConnection{
+				{
+					Authorization: nil,
+					Url:           "https://bitbucket.org",
+					Username:      "admin",
+					AppPassword:   "secret-password",
+				},
+			},
+			expAuthzAllowAccessByDefault: true,
+			expAuthzProviders:            providersEqual(),
+		},
 		{
 			description: "Bitbucket Server Oauth config error",
 			cfg:         conf.Unified{},
@@ -466,6 +480,7 @@ func TestAuthzProvidersFromConfig(t *testing.T) {
 		store := fakeStore{
 			gitlabs:          test.gitlabConnections,
 			bitbucketServers: test.bitbucketServerConnections,
+			bitbucketClouds:  test.bitbucketCloudConnections,
 		}
 
 		allowAccessByDefault, authzProviders, seriousProblems, _ := ProvidersFromConfig(
@@ -505,6 +520,7 @@ type fakeStore struct {
 	gitlabs          []*schema.GitLabConnection
 	githubs          []*schema.GitHubConnection
 	bitbucketServers []*schema.BitbucketServerConnection
+	bitbucketClouds  []*schema.BitbucketCloudConnection
 	perforces        []*schema.PerforceConnection
 }
 
@@ -541,6 +557,13 @@ func (s fakeStore) List(ctx context.Context, opt database.ExternalServicesListOp
 					Config: mustMarshalJSONString(bbs),
 				})
 			}
+		case extsvc.KindBitbucketCloud:
+			for _, bbc := range s.bitbucketClouds {
+				svcs = append(svcs, &types.ExternalService{
+					Kind:   kind,
+					Config: mustMarshalJSONString(bbc),
+				})
+			}
 		case extsvc.KindPerforce:
 			for _, p := range s.perforces {
 				svcs = append(svcs, &types.ExternalService{
@@ -0,0 +1,181 @@
+package background
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+func newAutoMergeWorker(ctx context.Context, cstore *store.Store) goroutine.BackgroundRoutine {
+	merger := &autoMerger{store: cstore}
+	autoMerge := goroutine.NewHandlerWithErrorMessage("auto-merge batch changes changesets", merger.run)
+	return goroutine.NewPeriodicGoroutine(ctx, 1*time.Minute, autoMerge)
+}
+
+// autoMerger enqueues merge jobs for the changesets of batch changes with an
+// auto-merge policy once the synced state of a changeset satisfies the
+// policy, and records on all other changesets why they are not merged yet.
+type autoMerger struct {
+	store *store.Store
+}
+
+func (m *autoMerger) run(ctx context.Context) error {
+	policies, err := m.store.ListAutoMergePolicies(ctx)
+	if err != nil {
+		return errors.Wrap(err, "listing auto-merge policies")
+	}
+
+	// A failing policy must not keep the policies of other batch changes
+	// from being applied.
+	var errs *multierror.Error
+	for _, policy := range policies {
+		if err := m.applyPolicy(ctx, policy); err != nil {
+			log15.Error("applying auto-merge policy", "batchChangeID", policy.BatchChangeID, "err", err)
+			errs = multierror.Append(errs, errors.Wrapf(err, "applying auto-merge policy of batch change %d", policy.BatchChangeID))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (m *autoMerger) applyPolicy(ctx context.Context, policy *btypes.AutoMergePolicy) (err error) {
+	batchChange, err := m.store.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: policy.BatchChangeID})
+	if err != nil {
+		return errors.Wrap(err, "loading batch change")
+	}
+
+	published := btypes.ChangesetPublicationStatePublished
+	cs, _, err := m.store.ListChangesets(ctx, store.ListChangesetsOpts{
+		BatchChangeID:        batchChange.ID,
+		OwnedByBatchChangeID: batchChange.ID,
+		PublicationState:     &published,
+		ReconcilerStates:     []btypes.ReconcilerState{btypes.ReconcilerStateCompleted},
+		ExternalStates:       []btypes.ChangesetExternalState{btypes.ChangesetExternalStateOpen, btypes.ChangesetExternalStateDraft},
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing changesets")
+	}
+
+	tx, err := m.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// Reasons recorded while a changeset was open are stale once it has been
+	// merged or closed.
+	if err := tx.ClearAutoMergeBlockedReasons(ctx, batchChange.ID); err != nil {
+		return errors.Wrap(err, "clearing blocked reasons of changesets that are no longer open")
+	}
+
+	now := m.store.Clock()()
+	var jobs []*btypes.ChangesetJob
+	for _, c := range cs {
+		merge, reason, err := m.evaluate(ctx, tx, batchChange, policy, c, now)
+		if err != nil {
+			return err
+		}
+
+		if merge {
+			jobs = append(jobs, &btypes.ChangesetJob{
+				ChangesetID:   c.ID,
+				BatchChangeID: batchChange.ID,
+				// Merge with the credentials of the user who set the policy.
+				UserID:  policy.UserID,
+				State:   btypes.ChangesetJobStateQueued,
+				JobType: btypes.ChangesetJobTypeMerge,
+				Payload: &btypes.ChangesetJobMergePayload{Squash: policy.Squash},
+			})
+		}
+
+		if err := m.recordBlockedReason(ctx, tx, c, reason); err != nil {
+			return err
+		}
+	}
+
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	bulkGroupID, err := store.RandomID()
+	if err != nil {
+		return errors.Wrap(err, "creating bulkGroupID failed")
+	}
+	for _, job := range jobs {
+		job.BulkGroup = bulkGroupID
+	}
+
+	if err := tx.CreateChangesetJob(ctx, jobs...); err != nil {
+		return errors.Wrap(err, "creating changeset jobs")
+	}
+
+	return nil
+}
+
+// evaluate determines whether the given changeset should be merged now. If it
+// should not, the reason is returned, unless a merge is already underway.
+func (m *autoMerger) evaluate(ctx context.Context, tx *store.Store, batchChange *btypes.BatchChange, policy *btypes.AutoMergePolicy, c *btypes.Changeset, now time.Time) (merge bool, reason string, err error) {
+	job, err := tx.GetChangesetJob(ctx, store.GetChangesetJobOpts{
+		BatchChangeID: batchChange.ID,
+		ChangesetID:   c.ID,
+		JobType:       btypes.ChangesetJobTypeMerge,
+	})
+	if err != nil && err != store.ErrNoResults {
+		return false, "", errors.Wrap(err, "loading latest merge job")
+	}
+
+	if job != nil {
+		// Job states are stored in lowercase for workerutil.
+		switch btypes.ChangesetJobState(strings.ToUpper(string(job.State))) {
+		case btypes.ChangesetJobStateQueued, btypes.ChangesetJobStateProcessing, btypes.ChangesetJobStateErrored:
+			// Errored jobs are retried by the bulk processor.
+			return false, "", nil
+
+		case btypes.ChangesetJobStateFailed:
+			// Don't retry a failed merge until the changeset has changed on
+			// the code host, e.g. because a conflict has been resolved.
+			if !c.ExternalUpdatedAt.After(job.FinishedAt) {
+				message := "unknown error"
+				if job.FailureMessage != nil {
+					message = *job.FailureMessage
+				}
+				return false, fmt.Sprintf("Merging the changeset failed: %s", message), nil
+			}
+		}
+	}
+
+	if reason := policy.BlockedReason(c, now); reason != "" {
+		return false, reason, nil
+	}
+
+	return true, "", nil
+}
+
+func (m *autoMerger) recordBlockedReason(ctx context.Context, tx *store.Store, c *btypes.Changeset, reason string) error {
+	var current string
+	if c.AutoMergeBlockedReason != nil {
+		current = *c.AutoMergeBlockedReason
+	}
+	if current == reason {
+		return nil
+	}
+
+	var r *string
+	if reason != "" {
+		r = &reason
+	}
+	if err := tx.UpdateChangesetAutoMergeBlockedReason(ctx, c, r); err != nil {
+		return errors.Wrap(err, "recording blocked reason")
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+)
+
+func TestAutoMerger(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+	tx := dbtest.NewTx(t, db)
+	bstore := store.New(tx, nil)
+	user := ct.CreateTestUser(t, db, true)
+	policyUser := ct.CreateTestUser(t, db, false)
+	repos, _ := ct.CreateTestRepos(t, ctx, db, 1)
+	batchSpec := ct.CreateBatchSpec(t, ctx, bstore, "test-auto-merge", user.ID)
+	batchChange := ct.CreateBatchChange(t, ctx, bstore, "test-auto-merge", user.ID, batchSpec.ID)
+
+	createChangeset := func(externalID string, state btypes.ChangesetExternalState, review btypes.ChangesetReviewState) *btypes.Changeset {
+		return ct.CreateChangeset(t, ctx, bstore, ct.TestChangesetOpts{
+			Repo:                repos[0].ID,
+			BatchChange:         batchChange.ID,
+			OwnedByBatchChange:  batchChange.ID,
+			Metadata:            &github.PullRequest{},
+			ExternalServiceType: extsvc.TypeGitHub,
+			ExternalID:          externalID,
+			ExternalState:       state,
+			ExternalReviewState: review,
+			ExternalCheckState:  btypes.ChangesetCheckStatePassed,
+			PublicationState:    btypes.ChangesetPublicationStatePublished,
+			ReconcilerState:     btypes.ReconcilerStateCompleted,
+		})
+	}
+	eligible := createChangeset("1", btypes.ChangesetExternalStateOpen, btypes.ChangesetReviewStateApproved)
+	pending := createChangeset("2", btypes.ChangesetExternalStateOpen, btypes.ChangesetReviewStatePending)
+	draft := createChangeset("3", btypes.ChangesetExternalStateDraft, btypes.ChangesetReviewStateApproved)
+	closed := createChangeset("4", btypes.ChangesetExternalStateClosed, btypes.ChangesetReviewStateApproved)
+
+	// A reason recorded while the changeset was still open must be cleared.
+	staleReason := "The changeset is a draft."
+	if err := bstore.UpdateChangesetAutoMergeBlockedReason(ctx, closed, &staleReason); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bstore.UpsertAutoMergePolicy(ctx, &btypes.AutoMergePolicy{
+		BatchChangeID:       batchChange.ID,
+		UserID:              policyUser.ID,
+		RequiredCheckState:  btypes.ChangesetCheckStatePassed,
+		RequiredReviewState: btypes.ChangesetReviewStateApproved,
+		Squash:              true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	merger := &autoMerger{store: bstore}
+
+	// Running twice must not enqueue a second merge while the first one is
+	// still queued.
+	for i := 0; i < 2; i++ {
+		if err := merger.run(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	job, err := bstore.GetChangesetJob(ctx, store.GetChangesetJobOpts{ChangesetID: eligible.ID, JobType: btypes.ChangesetJobTypeMerge})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.UserID != policyUser.ID {
+		t.Errorf("wrong user for merge job. want=%d, have=%d", policyUser.ID, job.UserID)
+	}
+	if payload, ok := job.Payload.(*btypes.ChangesetJobMergePayload); !ok || !payload.Squash {
+		t.Errorf("wrong payload for merge job: %+v", job.Payload)
+	}
+	if count, err := bstore.CountBulkOperations(ctx, store.CountBulkOperationsOpts{BatchChangeID: batchChange.ID}); err != nil {
+		t.Fatal(err)
+	} else if count != 1 {
+		t.Errorf("wrong number of bulk operations. want=%d, have=%d", 1, count)
+	}
+
+	for _, tc := range []struct {
+		changeset *btypes.Changeset
+		want      string
+	}{
+		{eligible, ""},
+		{pending, "The review state is PENDING, but APPROVED is required."},
+		{draft, "The changeset is a draft."},
+		{closed, ""},
+	} {
+		if _, err := bstore.GetChangesetJob(ctx, store.GetChangesetJobOpts{ChangesetID: tc.changeset.ID, JobType: btypes.ChangesetJobTypeMerge}); tc.want != "" && err != store.ErrNoResults {
+			t.Errorf("unexpected merge job for changeset %d: %v", tc.changeset.ID, err)
+		}
+
+		c, err := bstore.GetChangeset(ctx, store.GetChangesetOpts{ID: tc.changeset.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var have string
+		if c.AutoMergeBlockedReason != nil {
+			have = *c.AutoMergeBlockedReason
+		}
+		if have != tc.want {
+			t.Errorf("wrong blocked reason for changeset %d. want=%q, have=%q", c.ID, tc.want, have)
+		}
+	}
+}
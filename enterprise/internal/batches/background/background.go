@@ -8,6 +8,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/scheduler"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/service"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -31,6 +32,9 @@ func Routines(ctx context.Context, batchesStore *store.Store, cf *httpcli.Factor
 		newReconcilerWorkerResetter(batchesStore, metrics),
 
 		newSpecExpireWorker(ctx, batchesStore),
+		newAutoMergeWorker(ctx, batchesStore),
+		newRolloutWorker(ctx, batchesStore),
+		newDependencyWorker(ctx, batchesStore),
 
 		scheduler.NewScheduler(ctx, batchesStore),
 
@@ -38,6 +42,10 @@ func Routines(ctx context.Context, batchesStore *store.Store, cf *httpcli.Factor
 		newBulkOperationWorkerResetter(batchesStore, metrics),
 
 		newBatchSpecExecutionResetter(batchesStore, observationContext, metrics),
+
+		newWorkspaceResolverWorker(ctx, batchesStore, service.NewWorkspaceResolver(batchesStore), metrics),
+		newWorkspaceResolverWorkerResetter(batchesStore, metrics),
+		newBatchSpecWorkspaceExecutionResetter(batchesStore, observationContext, metrics),
 	}
 	return routines
 }
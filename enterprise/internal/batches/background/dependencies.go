@@ -0,0 +1,71 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/global"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/reconciler"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+func newDependencyWorker(ctx context.Context, cstore *store.Store) goroutine.BackgroundRoutine {
+	worker := &dependencyWorker{store: cstore}
+	dependencies := goroutine.NewHandlerWithErrorMessage("release changesets held by their dependencies", worker.run)
+	return goroutine.NewPeriodicGoroutine(ctx, 1*time.Minute, dependencies)
+}
+
+// dependencyWorker enqueues the changesets whose publication the reconciler
+// held back because of their dependencies, once all the changesets they
+// depend on have been merged.
+type dependencyWorker struct {
+	store *store.Store
+}
+
+func (w *dependencyWorker) run(ctx context.Context) error {
+	cs, _, err := w.store.ListChangesets(ctx, store.ListChangesetsOpts{
+		OnlyDependenciesHeld: true,
+		ReconcilerStates:     []btypes.ReconcilerState{btypes.ReconcilerStateCompleted},
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing changesets")
+	}
+
+	// A changeset whose dependencies can't be resolved must not keep the
+	// other changesets from being released.
+	var errs *multierror.Error
+	for _, c := range cs {
+		if err := w.release(ctx, c); err != nil {
+			log15.Error("releasing changeset held by its dependencies", "changesetID", c.ID, "err", err)
+			errs = multierror.Append(errs, errors.Wrapf(err, "releasing changeset %d", c.ID))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (w *dependencyWorker) release(ctx context.Context, c *btypes.Changeset) error {
+	var spec *btypes.ChangesetSpec
+	if c.CurrentSpecID != 0 {
+		var err error
+		if spec, err = w.store.GetChangesetSpecByID(ctx, c.CurrentSpecID); err != nil {
+			return errors.Wrap(err, "loading changeset spec")
+		}
+	}
+
+	deps, err := reconciler.ResolveChangesetDependencies(ctx, w.store, c, spec)
+	if err != nil {
+		return err
+	}
+	if !reconciler.ChangesetDependenciesMerged(deps) {
+		return nil
+	}
+
+	return w.store.ReleaseChangesetHeldByDependencies(ctx, c, global.DefaultReconcilerEnqueueState())
+}
@@ -0,0 +1,91 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestDependencyWorker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+	tx := dbtest.NewTx(t, db)
+	bstore := store.New(tx, nil)
+	user := ct.CreateTestUser(t, db, true)
+	repos, _ := ct.CreateTestRepos(t, ctx, db, 2)
+	lib, app := repos[0], repos[1]
+
+	batchSpec := ct.CreateBatchSpec(t, ctx, bstore, "test-dependencies", user.ID)
+	batchChange := ct.CreateBatchChange(t, ctx, bstore, "test-dependencies", user.ID, batchSpec.ID)
+
+	libChangeset := ct.CreateChangeset(t, ctx, bstore, ct.TestChangesetOpts{
+		Repo:               lib.ID,
+		BatchChange:        batchChange.ID,
+		OwnedByBatchChange: batchChange.ID,
+		ExternalID:         "1",
+		ExternalBranch:     "api-v2",
+		ExternalState:      btypes.ChangesetExternalStateOpen,
+		PublicationState:   btypes.ChangesetPublicationStatePublished,
+		ReconcilerState:    btypes.ReconcilerStateCompleted,
+	})
+
+	spec := ct.CreateChangesetSpec(t, ctx, bstore, ct.TestSpecOpts{
+		User:      user.ID,
+		Repo:      app.ID,
+		BatchSpec: batchSpec.ID,
+		HeadRef:   "refs/heads/use-api-v2",
+		Published: true,
+	})
+	spec.Spec.DependsOn = []btypes.ChangesetDependency{{Repository: string(lib.Name), Branch: "api-v2"}}
+	if err := bstore.UpdateChangesetSpec(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	// The reconciler held back the publication of the app changeset.
+	appChangeset := ct.CreateChangeset(t, ctx, bstore, ct.TestChangesetOpts{
+		Repo:               app.ID,
+		BatchChange:        batchChange.ID,
+		OwnedByBatchChange: batchChange.ID,
+		CurrentSpec:        spec.ID,
+		PublicationState:   btypes.ChangesetPublicationStateUnpublished,
+		ReconcilerState:    btypes.ReconcilerStateCompleted,
+	})
+	appChangeset.DependenciesHeld = true
+	if err := bstore.UpdateChangesetDependenciesHeld(ctx, appChangeset); err != nil {
+		t.Fatal(err)
+	}
+
+	worker := &dependencyWorker{store: bstore}
+
+	assertReleased := func(t *testing.T, wantReleased bool) {
+		t.Helper()
+		c, err := bstore.GetChangesetByID(ctx, appChangeset.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if released := c.ReconcilerState == btypes.ReconcilerStateQueued && !c.DependenciesHeld; released != wantReleased {
+			t.Errorf("changeset released=%t, want %t", released, wantReleased)
+		}
+	}
+
+	// The library changeset hasn't been merged yet.
+	if err := worker.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertReleased(t, false)
+
+	libChangeset.ExternalState = btypes.ChangesetExternalStateMerged
+	if err := bstore.UpdateChangeset(ctx, libChangeset); err != nil {
+		t.Fatal(err)
+	}
+	if err := worker.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertReleased(t, true)
+}
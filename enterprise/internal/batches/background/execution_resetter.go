@@ -22,3 +22,17 @@ func newBatchSpecExecutionResetter(s *store.Store, observationContext *observati
 	resetter := dbworker.NewResetter(workerStore, options)
 	return resetter
 }
+
+// newBatchSpecWorkspaceExecutionResetter creates a dbworker.Resetter that
+// re-enqueues lost batch_spec_workspaces for processing.
+func newBatchSpecWorkspaceExecutionResetter(s *store.Store, observationContext *observation.Context, metrics batchChangesMetrics) *dbworker.Resetter {
+	workerStore := NewWorkspaceExecutorStore(s, observationContext)
+
+	options := dbworker.ResetterOptions{
+		Name:     "batch_spec_workspace_executor_resetter",
+		Interval: 1 * time.Minute,
+		Metrics:  metrics.workspaceExecutionResetterMetrics,
+	}
+
+	return dbworker.NewResetter(workerStore, options)
+}
@@ -16,6 +16,10 @@ type batchChangesMetrics struct {
 	reconcilerWorkerResetterMetrics    dbworker.ResetterMetrics
 	bulkProcessorWorkerResetterMetrics dbworker.ResetterMetrics
 	executionResetterMetrics           dbworker.ResetterMetrics
+
+	workspaceResolverWorkerMetrics         workerutil.WorkerMetrics
+	workspaceResolverWorkerResetterMetrics dbworker.ResetterMetrics
+	workspaceExecutionResetterMetrics      dbworker.ResetterMetrics
 }
 
 func newMetrics(observationContext *observation.Context) batchChangesMetrics {
@@ -25,6 +29,10 @@ func newMetrics(observationContext *observation.Context) batchChangesMetrics {
 		reconcilerWorkerResetterMetrics:    makeResetterMetrics(observationContext, "batch_changes_reconciler"),
 		bulkProcessorWorkerResetterMetrics: makeResetterMetrics(observationContext, "batch_changes_bulk_processor"),
 		executionResetterMetrics:           makeResetterMetrics(observationContext, "batch_spec_executor"),
+
+		workspaceResolverWorkerMetrics:         workerutil.NewMetrics(observationContext, "batch_changes_workspace_resolver", nil),
+		workspaceResolverWorkerResetterMetrics: makeResetterMetrics(observationContext, "batch_changes_workspace_resolver"),
+		workspaceExecutionResetterMetrics:      makeResetterMetrics(observationContext, "batch_spec_workspace_executor"),
 	}
 }
 
@@ -0,0 +1,176 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/global"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+func newRolloutWorker(ctx context.Context, cstore *store.Store) goroutine.BackgroundRoutine {
+	worker := &rolloutWorker{store: cstore}
+	rollout := goroutine.NewHandlerWithErrorMessage("release batch changes rollout waves", worker.run)
+	return goroutine.NewPeriodicGoroutine(ctx, 1*time.Minute, rollout)
+}
+
+// rolloutWorker assigns the changesets of batch changes whose batch spec
+// declares rollout waves to their wave, and releases the next wave once the
+// changesets of the earlier waves have succeeded, or pauses the rollout if
+// they failed and the batch spec asks for that.
+type rolloutWorker struct {
+	store *store.Store
+}
+
+func (w *rolloutWorker) run(ctx context.Context) error {
+	rollouts, err := w.store.ListBatchChangeRollouts(ctx)
+	if err != nil {
+		return errors.Wrap(err, "listing rollouts")
+	}
+
+	// A failing rollout must not keep the rollouts of other batch changes
+	// from advancing.
+	var errs *multierror.Error
+	for _, rollout := range rollouts {
+		if err := w.advance(ctx, rollout); err != nil {
+			log15.Error("advancing rollout", "batchChangeID", rollout.BatchChangeID, "err", err)
+			errs = multierror.Append(errs, errors.Wrapf(err, "advancing rollout of batch change %d", rollout.BatchChangeID))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (w *rolloutWorker) advance(ctx context.Context, rollout *btypes.BatchChangeRollout) (err error) {
+	batchChange, err := w.store.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: rollout.BatchChangeID})
+	if err != nil {
+		return errors.Wrap(err, "loading batch change")
+	}
+
+	batchSpec, err := w.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: batchChange.BatchSpecID})
+	if err != nil {
+		return errors.Wrap(err, "loading batch spec")
+	}
+
+	// Rollouts are deleted when a batch spec without waves is applied.
+	spec := batchSpec.Spec.Rollout
+	if spec == nil {
+		return nil
+	}
+
+	tx, err := w.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := w.assignWaves(ctx, tx, batchChange, spec); err != nil {
+		return err
+	}
+
+	if rollout.Paused() {
+		return nil
+	}
+
+	if rollout.CurrentWave < spec.WaveCount()-1 {
+		stats, err := tx.GetRolloutWaveStats(ctx, batchChange.ID)
+		if err != nil {
+			return errors.Wrap(err, "loading wave stats")
+		}
+
+		var released []*btypes.RolloutWaveStats
+		for _, s := range stats {
+			if s.Wave <= rollout.CurrentWave {
+				released = append(released, s)
+			}
+		}
+
+		switch decision, reason := spec.Evaluate(released); decision {
+		case btypes.RolloutDecisionAdvance:
+			rollout.CurrentWave++
+			if err := tx.UpdateBatchChangeRollout(ctx, rollout); err != nil {
+				return errors.Wrap(err, "releasing next wave")
+			}
+
+		case btypes.RolloutDecisionPause:
+			rollout.PausedAt = w.store.Clock()()
+			rollout.PauseReason = reason
+			if err := tx.UpdateBatchChangeRollout(ctx, rollout); err != nil {
+				return errors.Wrap(err, "pausing rollout")
+			}
+			return nil
+		}
+	}
+
+	// This also enqueues changesets that the reconciler held back while their
+	// wave was being released.
+	if err := tx.ReleaseHeldRolloutChangesets(ctx, batchChange.ID, rollout.CurrentWave, global.DefaultReconcilerEnqueueState()); err != nil {
+		return errors.Wrap(err, "releasing held changesets")
+	}
+
+	return nil
+}
+
+// assignWaves assigns the changesets owned by the given batch change that
+// don't have a rollout wave yet to one. Changesets keep their wave when a new
+// batch spec is applied, so that changesets that have been published stay in
+// the released waves.
+func (w *rolloutWorker) assignWaves(ctx context.Context, tx *store.Store, batchChange *btypes.BatchChange, spec *btypes.BatchSpecRollout) error {
+	cs, _, err := tx.ListChangesets(ctx, store.ListChangesetsOpts{
+		BatchChangeID:        batchChange.ID,
+		OwnedByBatchChangeID: batchChange.ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing changesets")
+	}
+
+	assigned := make(map[int32]int32)
+	var unassigned btypes.Changesets
+	for _, c := range cs {
+		if c.RolloutWave != nil {
+			assigned[*c.RolloutWave]++
+		} else {
+			unassigned = append(unassigned, c)
+		}
+	}
+	if len(unassigned) == 0 {
+		return nil
+	}
+
+	repos, err := tx.Repos().GetReposSetByIDs(ctx, unassigned.RepoIDs()...)
+	if err != nil {
+		return errors.Wrap(err, "loading repositories")
+	}
+
+	// Changesets are listed in the order they were created in, so the
+	// assignment is stable across runs.
+	candidates := make([]btypes.RolloutCandidate, 0, len(unassigned))
+	for _, c := range unassigned {
+		candidate := btypes.RolloutCandidate{ChangesetID: c.ID}
+		if repo, ok := repos[c.RepoID]; ok {
+			candidate.RepoName = string(repo.Name)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	waves, err := spec.AssignWaves(assigned, len(cs), candidates)
+	if err != nil {
+		return errors.Wrap(err, "assigning waves")
+	}
+
+	for _, c := range unassigned {
+		wave := waves[c.ID]
+		c.RolloutWave = &wave
+		if err := tx.UpdateChangesetRollout(ctx, c); err != nil {
+			return errors.Wrap(err, "updating changeset rollout wave")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestRolloutWorker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+	tx := dbtest.NewTx(t, db)
+	bstore := store.New(tx, nil)
+	user := ct.CreateTestUser(t, db, true)
+	repos, _ := ct.CreateTestRepos(t, ctx, db, 3)
+
+	batchSpec := ct.CreateBatchSpec(t, ctx, bstore, "test-rollout", user.ID)
+	batchSpec.Spec.Rollout = &btypes.BatchSpecRollout{
+		Waves:          []btypes.BatchSpecRolloutWave{{Count: 1}, {Count: 1}},
+		PauseOnFailure: true,
+	}
+	if err := bstore.UpdateBatchSpec(ctx, batchSpec); err != nil {
+		t.Fatal(err)
+	}
+	batchChange := ct.CreateBatchChange(t, ctx, bstore, "test-rollout", user.ID, batchSpec.ID)
+	if err := bstore.EnsureBatchChangeRollout(ctx, batchChange.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// All changesets have been held back by the reconciler before they were
+	// assigned to a wave.
+	var changesets []*btypes.Changeset
+	for _, repo := range repos {
+		c := ct.CreateChangeset(t, ctx, bstore, ct.TestChangesetOpts{
+			Repo:               repo.ID,
+			BatchChange:        batchChange.ID,
+			OwnedByBatchChange: batchChange.ID,
+			PublicationState:   btypes.ChangesetPublicationStateUnpublished,
+			ReconcilerState:    btypes.ReconcilerStateCompleted,
+		})
+		c.RolloutHeld = true
+		if err := bstore.UpdateChangesetRollout(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		changesets = append(changesets, c)
+	}
+
+	worker := &rolloutWorker{store: bstore}
+
+	reload := func(t *testing.T, c *btypes.Changeset) *btypes.Changeset {
+		t.Helper()
+		reloaded, err := bstore.GetChangesetByID(ctx, c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return reloaded
+	}
+	assertReleased := func(t *testing.T, c *btypes.Changeset, wantWave int32, wantReleased bool) {
+		t.Helper()
+		c = reload(t, c)
+		if c.RolloutWave == nil || *c.RolloutWave != wantWave {
+			t.Errorf("changeset %d in wrong wave. want=%d, have=%v", c.ID, wantWave, c.RolloutWave)
+		}
+		if released := c.ReconcilerState == btypes.ReconcilerStateQueued; released != wantReleased {
+			t.Errorf("changeset %d released=%t, want %t", c.ID, released, wantReleased)
+		}
+	}
+	assertRollout := func(t *testing.T, wantWave int32, wantPaused bool) {
+		t.Helper()
+		rollout, err := bstore.GetBatchChangeRollout(ctx, batchChange.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rollout.CurrentWave != wantWave {
+			t.Errorf("wrong current wave. want=%d, have=%d", wantWave, rollout.CurrentWave)
+		}
+		if rollout.Paused() != wantPaused {
+			t.Errorf("wrong paused state. want=%t, have=%t (%q)", wantPaused, rollout.Paused(), rollout.PauseReason)
+		}
+	}
+	publish := func(t *testing.T, c *btypes.Changeset, external btypes.ChangesetExternalState, check btypes.ChangesetCheckState) {
+		t.Helper()
+		c = reload(t, c)
+		c.PublicationState = btypes.ChangesetPublicationStatePublished
+		c.ReconcilerState = btypes.ReconcilerStateCompleted
+		c.ExternalState = external
+		c.ExternalCheckState = check
+		if err := bstore.UpdateChangeset(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first run assigns the waves and only releases the first one.
+	if err := worker.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertRollout(t, 0, false)
+	assertReleased(t, changesets[0], 0, true)
+	assertReleased(t, changesets[1], 1, false)
+	assertReleased(t, changesets[2], 2, false)
+
+	// Once the changeset in the first wave is merged, the second wave is
+	// released.
+	publish(t, changesets[0], btypes.ChangesetExternalStateMerged, "")
+	if err := worker.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertRollout(t, 1, false)
+	assertReleased(t, changesets[1], 1, true)
+	assertReleased(t, changesets[2], 2, false)
+
+	// A failed changeset in the second wave pauses the rollout.
+	publish(t, changesets[1], btypes.ChangesetExternalStateOpen, btypes.ChangesetCheckStateFailed)
+	if err := worker.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertRollout(t, 1, true)
+	assertReleased(t, changesets[2], 2, false)
+}
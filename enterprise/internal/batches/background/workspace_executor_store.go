@@ -0,0 +1,275 @@
+package background
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/batch-change-utils/overridable"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	"github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+// Default author of the commits of changesets whose changesetTemplate
+// doesn't specify one. They match the defaults of src-cli.
+const (
+	defaultCommitAuthorName  = "Sourcegraph"
+	defaultCommitAuthorEmail = "batch-changes@sourcegraph.com"
+)
+
+var workspaceExecutorStoreOptions = dbworkerstore.Options{
+	Name:              "batch_spec_workspace_executor_store",
+	TableName:         "batch_spec_workspaces",
+	ColumnExpressions: store.BatchSpecWorkspaceColumns.ToSqlf(),
+	Scan:              scanFirstWorkspaceRecord,
+	OrderByExpression: sqlf.Sprintf("batch_spec_workspaces.created_at, batch_spec_workspaces.id"),
+	StalledMaxAge:     executorStalledJobMaximumAge,
+	MaxNumResets:      executorMaximumNumResets,
+	RetryAfter:        1 * time.Minute,
+	MaxNumRetries:     btypes.BatchSpecWorkspaceMaxNumRetries,
+}
+
+// NewWorkspaceExecutorStore creates a dbworker store that wraps the
+// batch_spec_workspaces table.
+func NewWorkspaceExecutorStore(s basestore.ShareableStore, observationContext *observation.Context) dbworkerstore.Store {
+	return &workspaceExecutorStore{Store: dbworkerstore.NewWithMetrics(s.Handle(), workspaceExecutorStoreOptions, observationContext)}
+}
+
+var _ dbworkerstore.Store = &workspaceExecutorStore{}
+
+// workspaceExecutorStore is a thin wrapper around dbworkerstore.Store that
+// creates the changeset specs from the diff in the execution logs of a
+// workspace when the executor marks it as complete.
+type workspaceExecutorStore struct {
+	dbworkerstore.Store
+}
+
+// markWorkspaceCompleteQuery is taken from internal/workerutil/dbworker/store/store.go
+//
+// If that one changes we need to update this one here too.
+const markWorkspaceCompleteQuery = `
+UPDATE batch_spec_workspaces
+SET state = 'completed', finished_at = clock_timestamp()
+WHERE id = %s AND state = 'processing' AND worker_hostname = %s
+RETURNING id
+`
+
+func (s *workspaceExecutorStore) MarkComplete(ctx context.Context, id int, options dbworkerstore.MarkFinalOptions) (_ bool, err error) {
+	// The executor queue has no actor, so we need the internal actor to load
+	// private repositories.
+	ctx = actor.WithInternalActor(ctx)
+
+	batchesStore := store.New(s.Store.Handle().DB(), nil)
+
+	ok, err := markWorkspaceComplete(ctx, batchesStore, int64(id), options.WorkerHostname)
+	if err != nil && errors.HasType(err, &changesetSpecCreationError{}) {
+		// If we couldn't create the changeset specs, we mark the job as failed.
+		return s.Store.MarkFailed(ctx, id, err.Error(), options)
+	}
+	return ok, err
+}
+
+// changesetSpecCreationError is returned by markWorkspaceComplete if the
+// changeset specs couldn't be created from the output of the workspace.
+type changesetSpecCreationError struct{ err error }
+
+func (e *changesetSpecCreationError) Error() string {
+	return fmt.Sprintf("failed to create changeset specs: %s", e.err)
+}
+
+// markWorkspaceComplete creates the changeset specs of the workspace and marks
+// it as completed in a single transaction.
+func markWorkspaceComplete(ctx context.Context, s *store.Store, id int64, workerHostname string) (_ bool, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	ids, err := createWorkspaceChangesetSpecs(ctx, tx, id)
+	if err != nil {
+		return false, &changesetSpecCreationError{err: err}
+	}
+
+	if err := tx.SetBatchSpecWorkspaceChangesetSpecs(ctx, id, ids); err != nil {
+		return false, err
+	}
+
+	_, ok, err := basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(markWorkspaceCompleteQuery, id, workerHostname)))
+	return ok, err
+}
+
+// createWorkspaceChangesetSpecs creates the changeset specs for the diff that
+// the execution of the given workspace produced and returns their IDs. No
+// changeset spec is created if the steps didn't change anything.
+func createWorkspaceChangesetSpecs(ctx context.Context, tx *store.Store, id int64) ([]int64, error) {
+	ws, err := tx.GetBatchSpecWorkspace(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading workspace")
+	}
+
+	spec, err := tx.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: ws.BatchSpecID})
+	if err != nil {
+		return nil, errors.Wrap(err, "loading batch spec")
+	}
+
+	diff, err := extractWorkspaceDiff(ws.ExecutionLogs, len(spec.Spec.Steps))
+	if err != nil {
+		return nil, err
+	}
+	if len(diff) == 0 {
+		return nil, nil
+	}
+
+	repo, err := tx.Repos().Get(ctx, ws.RepoID)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading repository")
+	}
+
+	changesetSpec, err := workspaceChangesetSpec(spec, ws, repo, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.CreateChangesetSpec(ctx, changesetSpec); err != nil {
+		return nil, errors.Wrap(err, "creating changeset spec")
+	}
+
+	return []int64{changesetSpec.ID}, nil
+}
+
+// ErrNoWorkspaceDiff is returned by extractWorkspaceDiff if the execution logs
+// don't contain the output of the diff step.
+var ErrNoWorkspaceDiff = errors.New("no diff found in execution logs")
+
+// extractWorkspaceDiff extracts the base64 encoded diff that the docker step
+// following the steps of the batch spec printed to stdout.
+func extractWorkspaceDiff(logs []workerutil.ExecutionLogEntry, numSteps int) ([]byte, error) {
+	key := fmt.Sprintf("step.docker.%d", numSteps)
+
+	for _, e := range logs {
+		if e.Key != key {
+			continue
+		}
+		if e.ExitCode != 0 {
+			return nil, ErrNoWorkspaceDiff
+		}
+
+		const outputLinePrefix = "stdout: "
+
+		var encoded strings.Builder
+		for _, l := range strings.Split(e.Out, "\n") {
+			if strings.HasPrefix(l, outputLinePrefix) {
+				encoded.WriteString(strings.TrimSpace(l[len(outputLinePrefix):]))
+			}
+		}
+
+		return base64.StdEncoding.DecodeString(encoded.String())
+	}
+
+	return nil, ErrNoWorkspaceDiff
+}
+
+// workspaceChangesetSpec builds the changeset spec that proposes the given
+// diff to the repository of the workspace, according to the
+// changesetTemplate of the batch spec.
+func workspaceChangesetSpec(spec *btypes.BatchSpec, ws *btypes.BatchSpecWorkspace, repo *types.Repo, diff []byte) (*btypes.ChangesetSpec, error) {
+	tmpl := spec.Spec.ChangesetTemplate
+	tctx := &btypes.WorkspaceTemplateContext{
+		BatchChangeName:        spec.Spec.Name,
+		BatchChangeDescription: spec.Spec.Description,
+		RepositoryName:         string(repo.Name),
+		Branch:                 strings.TrimPrefix(ws.Branch, "refs/heads/"),
+		Path:                   ws.Path,
+	}
+
+	var rendered [4]string
+	for i, t := range []struct{ name, tmpl string }{
+		{"changesetTemplate.title", tmpl.Title},
+		{"changesetTemplate.body", tmpl.Body},
+		{"changesetTemplate.branch", tmpl.Branch},
+		{"changesetTemplate.commit.message", tmpl.Commit.Message},
+	} {
+		var err error
+		if rendered[i], err = tctx.Render(t.name, t.tmpl); err != nil {
+			return nil, err
+		}
+	}
+	title, body, branch, message := rendered[0], rendered[1], rendered[2], rendered[3]
+
+	// Every workspace in a repository gets its own changeset, so they need
+	// their own branch.
+	if ws.Path != "" {
+		branch += "-" + strings.ReplaceAll(ws.Path, "/", "-")
+	}
+
+	authorName, authorEmail := defaultCommitAuthorName, defaultCommitAuthorEmail
+	if author := tmpl.Commit.Author; author != nil {
+		authorName, authorEmail = author.Name, author.Email
+	}
+
+	var published batches.PublishedValue
+	if !tmpl.Published.Equal(overridable.BoolOrString{}) {
+		published.Val = tmpl.Published.ValueWithSuffix(string(repo.Name), branch)
+	}
+
+	// The description is marshalled as a map, since the omitempty fields of
+	// ChangesetSpecDescription would drop an empty body, which is required.
+	repoID := graphqlbackend.MarshalRepositoryID(repo.ID)
+	description := map[string]interface{}{
+		"baseRepository": repoID,
+		"baseRef":        ws.Branch,
+		"baseRev":        ws.Commit,
+		"headRepository": repoID,
+		"headRef":        "refs/heads/" + branch,
+		"title":          title,
+		"body":           body,
+		"commits": []btypes.GitCommitDescription{{
+			Message:     message,
+			Diff:        string(diff),
+			AuthorName:  authorName,
+			AuthorEmail: authorEmail,
+		}},
+		"published": published,
+	}
+	if len(tmpl.DependsOn) > 0 {
+		description["dependsOn"] = tmpl.DependsOn
+	}
+	if tmpl.Reviewers != nil {
+		description["reviewers"] = tmpl.Reviewers
+	}
+	raw, err := json.Marshal(description)
+	if err != nil {
+		return nil, err
+	}
+
+	changesetSpec, err := btypes.NewChangesetSpecFromRaw(string(raw))
+	if err != nil {
+		return nil, err
+	}
+	changesetSpec.BatchSpecID = spec.ID
+	changesetSpec.RepoID = repo.ID
+	changesetSpec.UserID = spec.UserID
+
+	return changesetSpec, nil
+}
+
+// scanFirstWorkspaceRecord scans a slice of workspaces and returns the first.
+func scanFirstWorkspaceRecord(rows *sql.Rows, err error) (workerutil.Record, bool, error) {
+	return store.ScanFirstBatchSpecWorkspace(rows, err)
+}
@@ -0,0 +1,148 @@
+package background
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+const testWorkspaceDiff = `diff README.md README.md
+index 671e50a..851b23a 100644
+--- README.md
++++ README.md
+@@ -1,2 +1,2 @@
+ # README
+-This file is hosted at example.com and is a test file.
++This file is hosted at sourcegraph.com and is a test file.
+`
+
+func TestExtractWorkspaceDiff(t *testing.T) {
+	// Split the encoded diff across multiple lines, like base64 does.
+	encoded := base64.StdEncoding.EncodeToString([]byte(testWorkspaceDiff))
+	var out strings.Builder
+	for len(encoded) > 76 {
+		out.WriteString("stdout: " + encoded[:76] + "\n")
+		encoded = encoded[76:]
+	}
+	out.WriteString("stdout: " + encoded + "\n")
+
+	logs := []workerutil.ExecutionLogEntry{
+		{Key: "setup.git.init", Out: "stdout: done\n"},
+		{Key: "step.docker.0", Out: "stderr: + sed -i ...\n"},
+		{Key: "step.docker.1", Out: "stderr: + git add --all\nstderr: + git diff --cached\n" + out.String()},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		have, err := extractWorkspaceDiff(logs, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(testWorkspaceDiff, string(have)); diff != "" {
+			t.Errorf("wrong diff (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("empty diff", func(t *testing.T) {
+		have, err := extractWorkspaceDiff([]workerutil.ExecutionLogEntry{{Key: "step.docker.1"}}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Errorf("expected empty diff, got %q", have)
+		}
+	})
+
+	t.Run("diff step missing", func(t *testing.T) {
+		if _, err := extractWorkspaceDiff(logs[:2], 1); err != ErrNoWorkspaceDiff {
+			t.Errorf("wrong error. want=%s, have=%v", ErrNoWorkspaceDiff, err)
+		}
+	})
+
+	t.Run("diff step failed", func(t *testing.T) {
+		failed := []workerutil.ExecutionLogEntry{{Key: "step.docker.1", ExitCode: 1}}
+		if _, err := extractWorkspaceDiff(failed, 1); err != ErrNoWorkspaceDiff {
+			t.Errorf("wrong error. want=%s, have=%v", ErrNoWorkspaceDiff, err)
+		}
+	})
+}
+
+func TestWorkspaceChangesetSpec(t *testing.T) {
+	spec, err := btypes.NewBatchSpecFromRaw(`
+name: hello-world
+description: Add Hello World
+on:
+  - repositoriesMatchingQuery: file:README.md
+steps:
+  - run: echo Hello World | tee -a README.md
+    container: alpine:3
+changesetTemplate:
+  title: Hello World in ${{ steps.path }}
+  body: ${{ batch_change.description }}
+  branch: ${{ batch_change.name }}
+  commit:
+    message: Append Hello World to ${{ repository.name }}
+  published: false
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec.ID = 1
+	spec.UserID = 2
+
+	repo := &types.Repo{ID: 3, Name: "github.com/sourcegraph/sourcegraph"}
+	ws := &btypes.BatchSpecWorkspace{
+		BatchSpecID: spec.ID,
+		RepoID:      repo.ID,
+		Branch:      "refs/heads/main",
+		Commit:      "d34db33f",
+		Path:        "a/b",
+	}
+
+	cs, err := workspaceChangesetSpec(spec, ws, repo, []byte(testWorkspaceDiff))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cs.BatchSpecID != spec.ID || cs.RepoID != repo.ID || cs.UserID != spec.UserID {
+		t.Errorf("wrong IDs: batch spec %d, repo %d, user %d", cs.BatchSpecID, cs.RepoID, cs.UserID)
+	}
+
+	d := cs.Spec
+	if have, want := d.HeadRef, "refs/heads/hello-world-a-b"; have != want {
+		t.Errorf("wrong head ref. want=%q, have=%q", want, have)
+	}
+	if have, want := d.BaseRef, "refs/heads/main"; have != want {
+		t.Errorf("wrong base ref. want=%q, have=%q", want, have)
+	}
+	if have, want := d.BaseRev, "d34db33f"; have != want {
+		t.Errorf("wrong base rev. want=%q, have=%q", want, have)
+	}
+	if have, want := d.Title, "Hello World in a/b"; have != want {
+		t.Errorf("wrong title. want=%q, have=%q", want, have)
+	}
+	if have, want := d.Body, "Add Hello World"; have != want {
+		t.Errorf("wrong body. want=%q, have=%q", want, have)
+	}
+	if !d.Published.False() {
+		t.Errorf("wrong published value %v", d.Published.Val)
+	}
+
+	wantCommits := []btypes.GitCommitDescription{{
+		Message:     "Append Hello World to github.com/sourcegraph/sourcegraph",
+		Diff:        testWorkspaceDiff,
+		AuthorName:  defaultCommitAuthorName,
+		AuthorEmail: defaultCommitAuthorEmail,
+	}}
+	if diff := cmp.Diff(wantCommits, d.Commits); diff != "" {
+		t.Errorf("wrong commits (-want +got):\n%s", diff)
+	}
+	if cs.DiffStatChanged != 1 {
+		t.Errorf("wrong diff stat changed %d", cs.DiffStatChanged)
+	}
+}
@@ -0,0 +1,132 @@
+package background
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/service"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// workspaceResolverMaxNumRetries is the maximum number of attempts the
+// workspace resolver makes to resolve the workspaces of a batch spec when it
+// fails, for example because a search timed out.
+const workspaceResolverMaxNumRetries = 3
+
+// newWorkspaceResolverWorker creates a dbworker.Worker that fetches queued
+// batch_spec_resolution_jobs and resolves the workspaces of their batch
+// spec, which are then queued for execution by an executor.
+func newWorkspaceResolverWorker(
+	ctx context.Context,
+	s *store.Store,
+	resolver service.WorkspaceResolver,
+	metrics batchChangesMetrics,
+) *workerutil.Worker {
+	w := &workspaceResolverWorker{store: s, resolver: resolver}
+
+	options := workerutil.WorkerOptions{
+		Name:              "batch_changes_workspace_resolver",
+		NumHandlers:       5,
+		HeartbeatInterval: 15 * time.Second,
+		Interval:          5 * time.Second,
+		Metrics:           metrics.workspaceResolverWorkerMetrics,
+	}
+
+	return dbworker.NewWorker(ctx, createWorkspaceResolverDBWorkerStore(s), w.HandlerFunc(), options)
+}
+
+// newWorkspaceResolverWorkerResetter creates a dbworker.Resetter that
+// re-enqueues lost resolution jobs for processing.
+func newWorkspaceResolverWorkerResetter(s *store.Store, metrics batchChangesMetrics) *dbworker.Resetter {
+	options := dbworker.ResetterOptions{
+		Name:     "batch_changes_workspace_resolver_resetter",
+		Interval: 1 * time.Minute,
+		Metrics:  metrics.workspaceResolverWorkerResetterMetrics,
+	}
+
+	return dbworker.NewResetter(createWorkspaceResolverDBWorkerStore(s), options)
+}
+
+func createWorkspaceResolverDBWorkerStore(s *store.Store) dbworkerstore.Store {
+	return dbworkerstore.New(s.Handle(), dbworkerstore.Options{
+		Name:              "batch_changes_workspace_resolver_store",
+		TableName:         "batch_spec_resolution_jobs",
+		ColumnExpressions: store.BatchSpecResolutionJobColumns.ToSqlf(),
+		Scan:              scanFirstResolutionJobRecord,
+
+		OrderByExpression: sqlf.Sprintf("batch_spec_resolution_jobs.created_at, batch_spec_resolution_jobs.id"),
+
+		StalledMaxAge: 60 * time.Second,
+		MaxNumResets:  executorMaximumNumResets,
+
+		RetryAfter:    5 * time.Second,
+		MaxNumRetries: workspaceResolverMaxNumRetries,
+	})
+}
+
+// scanFirstResolutionJobRecord wraps store.ScanFirstBatchSpecResolutionJob
+// to return a generic workerutil.Record.
+func scanFirstResolutionJobRecord(rows *sql.Rows, err error) (workerutil.Record, bool, error) {
+	return store.ScanFirstBatchSpecResolutionJob(rows, err)
+}
+
+type workspaceResolverWorker struct {
+	store    *store.Store
+	resolver service.WorkspaceResolver
+}
+
+func (w *workspaceResolverWorker) HandlerFunc() workerutil.HandlerFunc {
+	return func(ctx context.Context, record workerutil.Record) error {
+		return w.resolve(ctx, record.(*btypes.BatchSpecResolutionJob))
+	}
+}
+
+func (w *workspaceResolverWorker) resolve(ctx context.Context, job *btypes.BatchSpecResolutionJob) (err error) {
+	spec, err := w.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: job.BatchSpecID})
+	if err != nil {
+		return errors.Wrap(err, "loading batch spec")
+	}
+
+	workspaces, err := w.resolver.ResolveWorkspacesForBatchSpec(ctx, spec)
+	if err != nil {
+		return err
+	}
+
+	tx, err := w.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// A previous attempt may have created the workspaces before the job
+	// could be marked as completed.
+	stats, err := tx.GetBatchSpecWorkspaceStats(ctx, spec.ID)
+	if err != nil {
+		return err
+	}
+	if stats.Total > 0 || len(workspaces) == 0 {
+		return nil
+	}
+
+	ws := make([]*btypes.BatchSpecWorkspace, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		ws = append(ws, &btypes.BatchSpecWorkspace{
+			BatchSpecID:        spec.ID,
+			RepoID:             workspace.Repo.ID,
+			Branch:             workspace.Branch,
+			Commit:             string(workspace.Commit),
+			Path:               workspace.Path,
+			OnlyFetchWorkspace: workspace.OnlyFetchWorkspace,
+		})
+	}
+
+	return tx.CreateBatchSpecWorkspace(ctx, ws...)
+}
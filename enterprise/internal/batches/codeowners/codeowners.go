@@ -0,0 +1,232 @@
+// Package codeowners parses CODEOWNERS files in the formats supported by
+// GitHub and GitLab, and computes the owners of files from them.
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// Paths are the paths at which a CODEOWNERS file is looked up in a
+// repository, in order. GitHub looks in .github/, the repository root and
+// docs/, GitLab in the repository root, docs/ and .gitlab/.
+var Paths = []string{".github/CODEOWNERS", ".gitlab/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// maxFileSize is the maximum size of a CODEOWNERS file. Like GitHub, larger
+// CODEOWNERS files are ignored.
+const maxFileSize = 3 * 1024 * 1024
+
+// Read reads and parses the CODEOWNERS file of the repository at the given
+// commit. If the repository has no CODEOWNERS file, or it is larger than
+// maxFileSize, nil is returned.
+func Read(ctx context.Context, repo api.RepoName, commit api.CommitID) (*File, error) {
+	for _, path := range Paths {
+		// Read one byte more than allowed to tell oversized files apart from
+		// files of exactly maxFileSize bytes.
+		content, err := git.ReadFile(ctx, repo, commit, path, maxFileSize+1)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "reading %s", path)
+		}
+		if len(content) > maxFileSize {
+			return nil, nil
+		}
+		return Parse(bytes.NewReader(content))
+	}
+	return nil, nil
+}
+
+// A File is a parsed CODEOWNERS file.
+type File struct {
+	sections []*section
+}
+
+// A section of a CODEOWNERS file. GitHub CODEOWNERS files have a single,
+// unnamed section. In GitLab CODEOWNERS files, the owners of a file are
+// determined for each section separately and then combined.
+type section struct {
+	name  string
+	rules []*rule
+}
+
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// sectionHeader matches the header of a GitLab CODEOWNERS section, such as
+// "^[Documentation][2] @docs-team". The leading caret marks optional
+// sections, the number in brackets the required number of approvals.
+var sectionHeader = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?(.*)$`)
+
+// Parse parses a CODEOWNERS file.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{}
+	sections := map[string]*section{}
+	current := &section{}
+	f.sections = append(f.sections, current)
+	var defaultOwners []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxFileSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if m := sectionHeader.FindStringSubmatch(line); m != nil {
+			// Sections with the same name, ignoring case, are combined.
+			name := strings.ToLower(m[1])
+			if s, ok := sections[name]; ok {
+				current = s
+			} else {
+				current = &section{name: name}
+				sections[name] = current
+				f.sections = append(f.sections, current)
+			}
+			defaultOwners = strings.Fields(m[2])
+			continue
+		}
+
+		fields := splitFields(line)
+		pattern, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+		owners := fields[1:]
+		if len(owners) == 0 {
+			owners = defaultOwners
+		}
+		current.rules = append(current.rules, &rule{pattern: pattern, owners: owners})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Owners returns the owners of the files with the given paths, relative to
+// the repository root, in the order they appear in the CODEOWNERS file.
+func (f *File) Owners(paths ...string) []string {
+	var owners []string
+	seen := map[string]bool{}
+	for _, path := range paths {
+		path = strings.TrimPrefix(path, "/")
+		for _, s := range f.sections {
+			// The last matching rule of a section takes precedence.
+			for i := len(s.rules) - 1; i >= 0; i-- {
+				if !s.rules[i].pattern.MatchString(path) {
+					continue
+				}
+				for _, o := range s.rules[i].owners {
+					if !seen[o] {
+						seen[o] = true
+						owners = append(owners, o)
+					}
+				}
+				break
+			}
+		}
+	}
+	return owners
+}
+
+// splitFields splits a line of a CODEOWNERS file at whitespace that isn't
+// escaped with a backslash. Comments at the end of the line, starting with a
+// "#" after whitespace, are removed.
+func splitFields(line string) []string {
+	var (
+		fields  []string
+		current strings.Builder
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line):
+			i++
+			current.WriteByte(line[i])
+		case c == '#' && current.Len() == 0:
+			i = len(line)
+		case c == ' ' || c == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// compilePattern compiles a CODEOWNERS pattern, which follows the rules of
+// gitignore patterns, into a regular expression matching the paths of the
+// files the pattern applies to.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	// Patterns that contain a slash anywhere but at the end are relative to
+	// the repository root. Others match at any depth.
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	// Patterns ending with a slash only match directories, that is the files
+	// in them.
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return nil, errors.New("empty pattern")
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	// A pattern matching a directory also matches the files in it, unless the
+	// last path segment contains a wildcard: "docs/*" only matches the files
+	// directly in docs/.
+	lastSegment := pattern[strings.LastIndex(pattern, "/")+1:]
+	switch {
+	case directory:
+		b.WriteString("/.*$")
+	case strings.ContainsAny(lastSegment, "*?"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
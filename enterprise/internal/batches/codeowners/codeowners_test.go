@@ -0,0 +1,150 @@
+package codeowners
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestFileOwners(t *testing.T) {
+	tcs := map[string]struct {
+		file  string
+		paths []string
+		want  []string
+	}{
+		"last match takes precedence": {
+			file: `
+# Global owners.
+*       @sourcegraph/everyone
+*.go    @sourcegraph/gophers
+`,
+			paths: []string{"cmd/main.go"},
+			want:  []string{"@sourcegraph/gophers"},
+		},
+		"multiple paths": {
+			file: `
+*       @sourcegraph/everyone
+*.go    @sourcegraph/gophers alice@example.com
+`,
+			paths: []string{"README.md", "main.go", "main_test.go"},
+			want:  []string{"@sourcegraph/everyone", "@sourcegraph/gophers", "alice@example.com"},
+		},
+		"anchored directory": {
+			file: `
+/docs/        @docs
+/build/logs/  @ops
+`,
+			paths: []string{"docs/index.md", "internal/docs/index.md", "build/logs/a/b.log"},
+			want:  []string{"@docs", "@ops"},
+		},
+		"unanchored directory": {
+			file:  `apps/ @apps`,
+			paths: []string{"frontend/apps/web/index.ts"},
+			want:  []string{"@apps"},
+		},
+		"wildcard in last segment": {
+			file:  `docs/* @docs`,
+			paths: []string{"docs/build/index.md"},
+			want:  nil,
+		},
+		"double asterisk": {
+			file: `
+**/logs     @ops
+src/**/*.ts @frontend
+`,
+			paths: []string{"deploy/logs/app.log", "src/a/b/index.ts", "src/index.ts"},
+			want:  []string{"@ops", "@frontend"},
+		},
+		"pattern without owners": {
+			file: `
+*                 @everyone
+/generated/
+`,
+			paths: []string{"generated/schema.go"},
+			want:  nil,
+		},
+		"escaped whitespace and comments": {
+			file:  `/My\ Documents/ @docs # documentation`,
+			paths: []string{"My Documents/file.txt"},
+			want:  []string{"@docs"},
+		},
+		"gitlab sections": {
+			file: `
+*.go @gophers
+
+[Documentation][2] @docs-team
+*.md
+/README.md @product
+
+^[Database]
+*.sql @dba
+
+[documentation]
+/internal/**/*.md @internal-docs
+`,
+			paths: []string{"internal/db/README.md", "internal/db/schema.sql", "README.md"},
+			want:  []string{"@internal-docs", "@dba", "@product"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			f, err := Parse(strings.NewReader(tc.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, f.Owners(tc.paths...)); diff != "" {
+				t.Fatalf("wrong owners (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Cleanup(func() { git.Mocks.ReadFile = nil })
+
+	files := map[string]string{}
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		if commit != "deadbeef" {
+			t.Fatalf("wrong commit %q", commit)
+		}
+		content, ok := files[name]
+		if !ok {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		return []byte(content), nil
+	}
+
+	f, err := Read(context.Background(), "github.com/sourcegraph/sourcegraph", "deadbeef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != nil {
+		t.Fatalf("file returned for repository without CODEOWNERS: %+v", f)
+	}
+
+	files["docs/CODEOWNERS"] = "* @docs"
+	files["CODEOWNERS"] = "* @root"
+	f, err = Read(context.Background(), "github.com/sourcegraph/sourcegraph", "deadbeef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"@root"}, f.Owners("main.go")); diff != "" {
+		t.Fatalf("wrong owners (-want +got):\n%s", diff)
+	}
+
+	files["CODEOWNERS"] = "* @root\n" + strings.Repeat("#", maxFileSize)
+	f, err = Read(context.Background(), "github.com/sourcegraph/sourcegraph", "deadbeef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != nil {
+		t.Fatalf("file returned for oversized CODEOWNERS: %+v", f)
+	}
+}
@@ -0,0 +1,145 @@
+package reconciler
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// ResolvedChangesetDependency is a dependency declared in the changeset spec
+// of a changeset, together with the changeset it refers to.
+type ResolvedChangesetDependency struct {
+	btypes.ChangesetDependency
+
+	// Changeset is nil if the dependency doesn't refer to an existing
+	// changeset (yet).
+	Changeset *btypes.Changeset
+}
+
+// Merged returns whether the changeset the dependency refers to has been
+// merged.
+func (d *ResolvedChangesetDependency) Merged() bool {
+	return d.Changeset != nil && d.Changeset.ExternalState == btypes.ChangesetExternalStateMerged
+}
+
+// ChangesetDependenciesMerged returns whether all the given dependencies have
+// been merged.
+func ChangesetDependenciesMerged(deps []*ResolvedChangesetDependency) bool {
+	for _, d := range deps {
+		if !d.Merged() {
+			return false
+		}
+	}
+	return true
+}
+
+type dependencyStore interface {
+	getBatchChanger
+	GetChangesetDependency(ctx context.Context, opts store.GetChangesetDependencyOpts) (*btypes.Changeset, error)
+}
+
+// ResolveChangesetDependencies resolves the dependencies declared in the given
+// changeset spec of the changeset to the changesets they refer to.
+// Dependencies on the changeset itself are skipped, so that a changeset
+// template can declare a dependency on one of the changesets of its own batch
+// change.
+func ResolveChangesetDependencies(ctx context.Context, tx dependencyStore, ch *btypes.Changeset, spec *btypes.ChangesetSpec) ([]*ResolvedChangesetDependency, error) {
+	if spec == nil || len(spec.Spec.DependsOn) == 0 {
+		return nil, nil
+	}
+
+	// Changesets can only depend on the changesets of batch changes in the
+	// namespace of their own batch change.
+	var owner *btypes.BatchChange
+	if ch.OwnedByBatchChangeID != 0 {
+		var err error
+		if owner, err = loadBatchChange(ctx, tx, ch.OwnedByBatchChangeID); err != nil {
+			return nil, err
+		}
+	}
+
+	deps := make([]*ResolvedChangesetDependency, 0, len(spec.Spec.DependsOn))
+	for _, d := range spec.Spec.DependsOn {
+		dep := &ResolvedChangesetDependency{ChangesetDependency: d}
+
+		opts := store.GetChangesetDependencyOpts{
+			RepoName:   api.RepoName(d.Repository),
+			ExternalID: d.ExternalID,
+		}
+		if d.ExternalID == "" {
+			batchChangeID, err := dependencyBatchChangeID(ctx, tx, owner, d)
+			if err != nil {
+				return nil, err
+			}
+			if batchChangeID == 0 {
+				deps = append(deps, dep)
+				continue
+			}
+			opts.HeadRef = d.HeadRef()
+			opts.OwnedByBatchChangeID = batchChangeID
+		}
+
+		c, err := tx.GetChangesetDependency(ctx, opts)
+		if err != nil && err != store.ErrNoResults {
+			return nil, errors.Wrapf(err, "retrieving changeset dependency in %s", d.Repository)
+		}
+		if c != nil && c.ID == ch.ID {
+			continue
+		}
+		dep.Changeset = c
+		deps = append(deps, dep)
+	}
+
+	return deps, nil
+}
+
+// dependencyBatchChangeID returns the ID of the batch change that created the
+// changeset for the branch of the dependency, or 0 if there's no such batch
+// change.
+func dependencyBatchChangeID(ctx context.Context, tx getBatchChanger, owner *btypes.BatchChange, d btypes.ChangesetDependency) (int64, error) {
+	if owner == nil {
+		return 0, nil
+	}
+	if d.BatchChange == "" || d.BatchChange == owner.Name {
+		return owner.ID, nil
+	}
+
+	batchChange, err := tx.GetBatchChange(ctx, store.GetBatchChangeOpts{
+		Name:            d.BatchChange,
+		NamespaceUserID: owner.NamespaceUserID,
+		NamespaceOrgID:  owner.NamespaceOrgID,
+	})
+	if err != nil {
+		if err == store.ErrNoResults {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "retrieving batch change %q", d.BatchChange)
+	}
+	return batchChange.ID, nil
+}
+
+// holdPublication changes the plan so that the changeset is at most
+// published as a draft. It returns whether the plan shouldn't be executed at
+// all, because the code host of the changeset doesn't support drafts.
+func holdPublication(plan *Plan, ch *btypes.Changeset) bool {
+	ops := make(Operations, 0, len(plan.Ops))
+	for _, op := range plan.Ops {
+		switch op {
+		case btypes.ReconcilerOperationPublish:
+			if !ch.SupportsDraft() {
+				return true
+			}
+			ops = append(ops, btypes.ReconcilerOperationPublishDraft)
+		case btypes.ReconcilerOperationUndraft:
+			// Drafts stay drafts.
+		default:
+			ops = append(ops, op)
+		}
+	}
+	plan.Ops = ops
+	return false
+}
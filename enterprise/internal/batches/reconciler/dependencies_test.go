@@ -0,0 +1,131 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func TestResolveChangesetDependencies(t *testing.T) {
+	owner := &btypes.BatchChange{ID: 1, Name: "use-api-v2", NamespaceUserID: 5}
+	lib := &btypes.BatchChange{ID: 2, Name: "add-api-v2", NamespaceUserID: 5}
+
+	libChangeset := &btypes.Changeset{ID: 20, ExternalState: btypes.ChangesetExternalStateMerged}
+	imported := &btypes.Changeset{ID: 21, ExternalState: btypes.ChangesetExternalStateOpen}
+	ch := &btypes.Changeset{ID: 10, OwnedByBatchChangeID: owner.ID}
+
+	tx := &FakeStore{
+		GetBatchChangeMock: func(_ context.Context, opts store.GetBatchChangeOpts) (*btypes.BatchChange, error) {
+			switch {
+			case opts.ID == owner.ID:
+				return owner, nil
+			case opts.Name == lib.Name && opts.NamespaceUserID == owner.NamespaceUserID:
+				return lib, nil
+			}
+			return nil, store.ErrNoResults
+		},
+		GetChangesetDependencyMock: func(_ context.Context, opts store.GetChangesetDependencyOpts) (*btypes.Changeset, error) {
+			switch opts {
+			case store.GetChangesetDependencyOpts{RepoName: "github.com/sourcegraph/lib", HeadRef: "refs/heads/api-v2", OwnedByBatchChangeID: lib.ID}:
+				return libChangeset, nil
+			case store.GetChangesetDependencyOpts{RepoName: "github.com/sourcegraph/lib", ExternalID: "12"}:
+				return imported, nil
+			case store.GetChangesetDependencyOpts{RepoName: "github.com/sourcegraph/app", HeadRef: "refs/heads/use-api-v2", OwnedByBatchChangeID: owner.ID}:
+				return ch, nil
+			}
+			return nil, store.ErrNoResults
+		},
+	}
+
+	spec := &btypes.ChangesetSpec{Spec: &btypes.ChangesetSpecDescription{
+		DependsOn: []btypes.ChangesetDependency{
+			{Repository: "github.com/sourcegraph/lib", Branch: "api-v2", BatchChange: lib.Name},
+			{Repository: "github.com/sourcegraph/lib", ExternalID: "12"},
+			// The changeset itself is skipped.
+			{Repository: "github.com/sourcegraph/app", Branch: "use-api-v2"},
+			{Repository: "github.com/sourcegraph/lib", Branch: "api-v3", BatchChange: "add-api-v3"},
+			{Repository: "github.com/sourcegraph/docs", Branch: "api-v2"},
+		},
+	}}
+
+	deps, err := ResolveChangesetDependencies(context.Background(), tx, ch, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		Repository string
+		Changeset  int64
+		Merged     bool
+	}
+	have := make([]result, 0, len(deps))
+	for _, d := range deps {
+		r := result{Repository: d.Repository, Merged: d.Merged()}
+		if d.Changeset != nil {
+			r.Changeset = d.Changeset.ID
+		}
+		have = append(have, r)
+	}
+	want := []result{
+		{Repository: "github.com/sourcegraph/lib", Changeset: libChangeset.ID, Merged: true},
+		{Repository: "github.com/sourcegraph/lib", Changeset: imported.ID},
+		{Repository: "github.com/sourcegraph/lib"},
+		{Repository: "github.com/sourcegraph/docs"},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("wrong dependencies (-want +got):\n%s", diff)
+	}
+
+	if ChangesetDependenciesMerged(deps) {
+		t.Fatal("dependencies reported as merged")
+	}
+	if !ChangesetDependenciesMerged(deps[:1]) {
+		t.Fatal("merged dependency reported as not merged")
+	}
+}
+
+func TestHoldPublication(t *testing.T) {
+	tcs := map[string]struct {
+		ops         Operations
+		serviceType string
+		wantOps     Operations
+		wantSkip    bool
+	}{
+		"publish with draft support": {
+			ops:         Operations{btypes.ReconcilerOperationPublish, btypes.ReconcilerOperationPush},
+			serviceType: extsvc.TypeGitHub,
+			wantOps:     Operations{btypes.ReconcilerOperationPublishDraft, btypes.ReconcilerOperationPush},
+		},
+		"publish without draft support": {
+			ops:         Operations{btypes.ReconcilerOperationPublish, btypes.ReconcilerOperationPush},
+			serviceType: extsvc.TypeBitbucketServer,
+			wantSkip:    true,
+		},
+		"undraft": {
+			ops:         Operations{btypes.ReconcilerOperationUndraft, btypes.ReconcilerOperationUpdate},
+			serviceType: extsvc.TypeGitHub,
+			wantOps:     Operations{btypes.ReconcilerOperationUpdate},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			plan := &Plan{Ops: tc.ops}
+			skip := holdPublication(plan, &btypes.Changeset{ExternalServiceType: tc.serviceType})
+			if skip != tc.wantSkip {
+				t.Fatalf("wrong skip. want=%t, have=%t", tc.wantSkip, skip)
+			}
+			if skip {
+				return
+			}
+			if !plan.Ops.Equal(tc.wantOps) {
+				t.Fatalf("wrong operations. want=%v, have=%v", tc.wantOps, plan.Ops)
+			}
+		})
+	}
+}
@@ -209,6 +209,13 @@ func (e *executor) publishChangeset(ctx context.Context, asDraft bool) (err erro
 			}
 		}
 	}
+
+	// Drafts are not ready for review yet, so reviewers are only requested
+	// once the changeset is undrafted.
+	if !asDraft {
+		e.requestReviewers(ctx, cs)
+	}
+
 	// Set the changeset to published.
 	e.ch.PublicationState = btypes.ChangesetPublicationStatePublished
 	return nil
@@ -333,6 +340,8 @@ func (e *executor) undraftChangeset(ctx context.Context) (err error) {
 	if err := draftCss.UndraftChangeset(ctx, cs); err != nil {
 		return errors.Wrap(err, "undrafting changeset")
 	}
+
+	e.requestReviewers(ctx, cs)
 	return nil
 }
 
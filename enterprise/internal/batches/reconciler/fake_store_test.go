@@ -17,7 +17,8 @@ func (e mockMissingErr) Error() string {
 }
 
 type FakeStore struct {
-	GetBatchChangeMock func(context.Context, store.GetBatchChangeOpts) (*btypes.BatchChange, error)
+	GetBatchChangeMock         func(context.Context, store.GetBatchChangeOpts) (*btypes.BatchChange, error)
+	GetChangesetDependencyMock func(context.Context, store.GetChangesetDependencyOpts) (*btypes.Changeset, error)
 }
 
 func (fs *FakeStore) GetBatchChange(ctx context.Context, opts store.GetBatchChangeOpts) (*btypes.BatchChange, error) {
@@ -26,3 +27,10 @@ func (fs *FakeStore) GetBatchChange(ctx context.Context, opts store.GetBatchChan
 	}
 	return nil, mockMissingErr{"GetBatchChange"}
 }
+
+func (fs *FakeStore) GetChangesetDependency(ctx context.Context, opts store.GetChangesetDependencyOpts) (*btypes.Changeset, error) {
+	if fs.GetChangesetDependencyMock != nil {
+		return fs.GetChangesetDependencyMock(ctx, opts)
+	}
+	return nil, mockMissingErr{"GetChangesetDependency"}
+}
@@ -31,6 +31,16 @@ func (ops Operations) IsNone() bool {
 	return len(ops) == 0
 }
 
+// Contains returns whether the given operation is part of the operations.
+func (ops Operations) Contains(op btypes.ReconcilerOperation) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
 func (ops Operations) Equal(b Operations) bool {
 	if len(ops) != len(b) {
 		return false
@@ -88,6 +88,26 @@ func (r *Reconciler) process(ctx context.Context, tx *store.Store, ch *btypes.Ch
 		return err
 	}
 
+	if plan.Ops.Contains(btypes.ReconcilerOperationPublish) || plan.Ops.Contains(btypes.ReconcilerOperationPublishDraft) {
+		held, err := holdForRollout(ctx, tx, ch)
+		if err != nil {
+			return err
+		}
+		if held {
+			log15.Info("Reconciler holding back changeset until its rollout wave is released", "changeset", ch.ID)
+			return nil
+		}
+	}
+
+	held, err := holdForDependencies(ctx, tx, ch, curr, plan)
+	if err != nil {
+		return err
+	}
+	if held {
+		log15.Info("Reconciler holding back changeset until its dependencies are merged", "changeset", ch.ID)
+		return nil
+	}
+
 	log15.Info("Reconciler processing changeset", "changeset", ch.ID, "operations", plan.Ops)
 
 	return executePlan(
@@ -115,3 +135,57 @@ func loadChangesetSpecs(ctx context.Context, tx *store.Store, ch *btypes.Changes
 	}
 	return
 }
+
+// holdForRollout returns whether the rollout of the batch change owning the
+// given changeset holds back its publication. If it does, the changeset is
+// marked as held, so the rollout worker enqueues it again once its wave is
+// released.
+func holdForRollout(ctx context.Context, tx *store.Store, ch *btypes.Changeset) (bool, error) {
+	if ch.OwnedByBatchChangeID == 0 {
+		return false, nil
+	}
+
+	rollout, err := tx.GetBatchChangeRollout(ctx, ch.OwnedByBatchChangeID)
+	if err != nil {
+		if err == store.ErrNoResults {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !rollout.Holds(ch) {
+		return false, nil
+	}
+
+	ch.RolloutHeld = true
+	return true, tx.UpdateChangesetRollout(ctx, ch)
+}
+
+// holdForDependencies returns whether the changesets the given changeset
+// depends on hold back its publication. Until they're merged, the plan is
+// changed so that the changeset is published as a draft on code hosts that
+// support drafts, and not published at all otherwise. Held changesets are
+// marked as such, so the dependency worker enqueues them again once their
+// dependencies have been merged.
+func holdForDependencies(ctx context.Context, tx *store.Store, ch *btypes.Changeset, spec *btypes.ChangesetSpec, plan *Plan) (bool, error) {
+	held := false
+	if plan.Ops.Contains(btypes.ReconcilerOperationPublish) || plan.Ops.Contains(btypes.ReconcilerOperationUndraft) {
+		deps, err := ResolveChangesetDependencies(ctx, tx, ch, spec)
+		if err != nil {
+			return false, err
+		}
+		held = !ChangesetDependenciesMerged(deps)
+	}
+
+	if held != ch.DependenciesHeld {
+		ch.DependenciesHeld = held
+		if err := tx.UpdateChangesetDependenciesHeld(ctx, ch); err != nil {
+			return false, err
+		}
+	}
+
+	if !held {
+		return false, nil
+	}
+	return holdPublication(plan, ch), nil
+}
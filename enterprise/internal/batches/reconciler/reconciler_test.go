@@ -162,3 +162,182 @@ func TestReconcilerProcess_IntegrationTest(t *testing.T) {
 		ct.TruncateTables(t, db, "changeset_events", "changesets", "batch_changes", "batch_specs", "changeset_specs")
 	}
 }
+
+func TestReconcilerProcess_RolloutHold(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := backend.WithAuthzBypass(context.Background())
+	db := dbtest.NewDB(t, "")
+
+	store := store.New(db, nil)
+
+	admin := ct.CreateTestUser(t, db, true)
+
+	rs, extSvc := ct.CreateTestRepos(t, ctx, db, 1)
+	ct.CreateTestSiteCredential(t, store, rs[0])
+
+	state := ct.MockChangesetSyncState(&protocol.RepoInfo{
+		Name: rs[0].Name,
+		VCS:  protocol.VCSInfo{URL: rs[0].URI},
+	})
+	defer state.Unmock()
+
+	internalClient = &mockInternalClient{externalURL: "https://sourcegraph.test"}
+	defer func() { internalClient = api.InternalClient }()
+
+	batchSpec := ct.CreateBatchSpec(t, ctx, store, "reconciler-rollout", admin.ID)
+	batchChange := ct.CreateBatchChange(t, ctx, store, "reconciler-rollout", admin.ID, batchSpec.ID)
+	if err := store.EnsureBatchChangeRollout(ctx, batchChange.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	changesetSpec := ct.CreateChangesetSpec(t, ctx, store, ct.TestSpecOpts{
+		User:      admin.ID,
+		Repo:      rs[0].ID,
+		BatchSpec: batchSpec.ID,
+		HeadRef:   "refs/heads/rollout",
+		Published: true,
+	})
+	changeset := ct.CreateChangeset(t, ctx, store, ct.TestChangesetOpts{
+		Repo:               rs[0].ID,
+		BatchChanges:       []btypes.BatchChangeAssoc{{BatchChangeID: batchChange.ID}},
+		OwnedByBatchChange: batchChange.ID,
+		CurrentSpec:        changesetSpec.ID,
+		PublicationState:   btypes.ChangesetPublicationStateUnpublished,
+	})
+
+	githubPR := buildGithubPR(time.Now(), btypes.ChangesetExternalStateOpen)
+	fakeSource := &sources.FakeChangesetSource{
+		Svc:          extSvc,
+		FakeMetadata: githubPR,
+		WantHeadRef:  changesetSpec.Spec.HeadRef,
+		WantBaseRef:  changesetSpec.Spec.BaseRef,
+	}
+	rec := Reconciler{
+		noSleepBeforeSync: true,
+		gitserverClient:   &ct.FakeGitserverClient{Response: changesetSpec.Spec.HeadRef},
+		sourcer:           sources.NewFakeSourcer(nil, fakeSource),
+		store:             store,
+	}
+
+	// The changeset hasn't been assigned to a wave yet, so it's held back.
+	if err := rec.process(ctx, store, changeset); err != nil {
+		t.Fatalf("reconciler process failed: %s", err)
+	}
+	if fakeSource.CreateChangesetCalled {
+		t.Fatal("held changeset was published")
+	}
+	reloaded, err := store.GetChangesetByID(ctx, changeset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded.RolloutHeld {
+		t.Fatal("changeset not marked as held")
+	}
+
+	// Once its wave is released, it's published.
+	wave := int32(0)
+	reloaded.RolloutWave = &wave
+	reloaded.RolloutHeld = false
+	if err := store.UpdateChangesetRollout(ctx, reloaded); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.process(ctx, store, reloaded); err != nil {
+		t.Fatalf("reconciler process failed: %s", err)
+	}
+	if !fakeSource.CreateChangesetCalled {
+		t.Fatal("released changeset was not published")
+	}
+}
+
+func TestReconcilerProcess_DependenciesHold(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := backend.WithAuthzBypass(context.Background())
+	db := dbtest.NewDB(t, "")
+
+	store := store.New(db, nil)
+
+	admin := ct.CreateTestUser(t, db, true)
+
+	rs, extSvc := ct.CreateTestRepos(t, ctx, db, 2)
+	app, lib := rs[0], rs[1]
+	ct.CreateTestSiteCredential(t, store, app)
+
+	state := ct.MockChangesetSyncState(&protocol.RepoInfo{
+		Name: app.Name,
+		VCS:  protocol.VCSInfo{URL: app.URI},
+	})
+	defer state.Unmock()
+
+	internalClient = &mockInternalClient{externalURL: "https://sourcegraph.test"}
+	defer func() { internalClient = api.InternalClient }()
+
+	batchSpec := ct.CreateBatchSpec(t, ctx, store, "reconciler-dependencies", admin.ID)
+	batchChange := ct.CreateBatchChange(t, ctx, store, "reconciler-dependencies", admin.ID, batchSpec.ID)
+
+	// The library changeset the app changeset depends on is still open.
+	ct.CreateChangeset(t, ctx, store, ct.TestChangesetOpts{
+		Repo:               lib.ID,
+		BatchChanges:       []btypes.BatchChangeAssoc{{BatchChangeID: batchChange.ID}},
+		OwnedByBatchChange: batchChange.ID,
+		ExternalID:         "1",
+		ExternalBranch:     "api-v2",
+		ExternalState:      btypes.ChangesetExternalStateOpen,
+		PublicationState:   btypes.ChangesetPublicationStatePublished,
+		ReconcilerState:    btypes.ReconcilerStateCompleted,
+	})
+
+	changesetSpec := ct.CreateChangesetSpec(t, ctx, store, ct.TestSpecOpts{
+		User:      admin.ID,
+		Repo:      app.ID,
+		BatchSpec: batchSpec.ID,
+		HeadRef:   "refs/heads/use-api-v2",
+		Published: true,
+	})
+	changesetSpec.Spec.DependsOn = []btypes.ChangesetDependency{{Repository: string(lib.Name), Branch: "api-v2"}}
+	if err := store.UpdateChangesetSpec(ctx, changesetSpec); err != nil {
+		t.Fatal(err)
+	}
+	changeset := ct.CreateChangeset(t, ctx, store, ct.TestChangesetOpts{
+		Repo:               app.ID,
+		BatchChanges:       []btypes.BatchChangeAssoc{{BatchChangeID: batchChange.ID}},
+		OwnedByBatchChange: batchChange.ID,
+		CurrentSpec:        changesetSpec.ID,
+		PublicationState:   btypes.ChangesetPublicationStateUnpublished,
+	})
+
+	githubPR := buildGithubPR(time.Now(), btypes.ChangesetExternalStateDraft)
+	fakeSource := &sources.FakeChangesetSource{
+		Svc:          extSvc,
+		FakeMetadata: githubPR,
+		WantHeadRef:  changesetSpec.Spec.HeadRef,
+		WantBaseRef:  changesetSpec.Spec.BaseRef,
+	}
+	rec := Reconciler{
+		noSleepBeforeSync: true,
+		gitserverClient:   &ct.FakeGitserverClient{Response: changesetSpec.Spec.HeadRef},
+		sourcer:           sources.NewFakeSourcer(nil, fakeSource),
+		store:             store,
+	}
+
+	// GitHub supports drafts, so the changeset is published as a draft until
+	// the library changeset is merged.
+	if err := rec.process(ctx, store, changeset); err != nil {
+		t.Fatalf("reconciler process failed: %s", err)
+	}
+	if fakeSource.CreateChangesetCalled || !fakeSource.CreateDraftChangesetCalled {
+		t.Fatal("held changeset was not published as a draft")
+	}
+	reloaded, err := store.GetChangesetByID(ctx, changeset.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded.DependenciesHeld {
+		t.Fatal("changeset not marked as held")
+	}
+}
@@ -0,0 +1,70 @@
+package reconciler
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/codeowners"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// requestReviewers requests reviews of the changeset from the reviewers
+// configured in its changeset spec, if the changeset source supports it.
+// Failing to request reviewers doesn't fail the publication of the
+// changeset, which already exists on the code host at this point.
+func (e *executor) requestReviewers(ctx context.Context, cs *sources.Changeset) {
+	if e.spec == nil || e.spec.Spec.Reviewers == nil {
+		return
+	}
+
+	rcs, ok := e.css.(sources.ReviewerChangesetSource)
+	if !ok {
+		log15.Warn("Changeset source doesn't support requesting reviewers", "changeset", e.ch.ID, "externalServiceType", e.ch.ExternalServiceType)
+		return
+	}
+
+	owners, err := changesetReviewers(ctx, e.repo.Name, e.spec)
+	if err != nil {
+		log15.Error("Determining changeset reviewers", "changeset", e.ch.ID, "err", err)
+		return
+	}
+	if len(owners) == 0 {
+		return
+	}
+
+	if err := rcs.RequestReviewers(ctx, cs, owners); err != nil {
+		log15.Error("Requesting changeset reviewers", "changeset", e.ch.ID, "err", err)
+	}
+}
+
+// changesetReviewers returns the owners whose reviews are requested for the
+// changeset described by the given spec: the code owners of the files changed
+// by it, according to the CODEOWNERS file at its base revision, or the
+// fallback owners if no code owner matches.
+func changesetReviewers(ctx context.Context, repo api.RepoName, spec *btypes.ChangesetSpec) ([]string, error) {
+	reviewers := spec.Spec.Reviewers
+	if !reviewers.CodeOwners {
+		return reviewers.Fallback, nil
+	}
+
+	f, err := codeowners.Read(ctx, repo, api.CommitID(spec.Spec.BaseRev))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading CODEOWNERS")
+	}
+	if f == nil {
+		return reviewers.Fallback, nil
+	}
+
+	paths, err := spec.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing diff")
+	}
+	if owners := f.Owners(paths...); len(owners) > 0 {
+		return owners, nil
+	}
+	return reviewers.Fallback, nil
+}
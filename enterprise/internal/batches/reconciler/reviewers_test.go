@@ -0,0 +1,125 @@
+package reconciler
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/sources"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+const reviewersTestDiff = `diff README.md README.md
+--- README.md
++++ README.md
+@@ -1 +1 @@
+-Hello
++Hello World
+diff cmd/main.go cmd/main.go
+--- cmd/main.go
++++ cmd/main.go
+@@ -1 +1 @@
+-package old
++package main
+`
+
+func mockCodeOwners(t *testing.T, content string) {
+	t.Cleanup(func() { git.Mocks.ReadFile = nil })
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		if commit != "d34db33f" {
+			t.Fatalf("CODEOWNERS read at wrong commit %q", commit)
+		}
+		if content == "" || name != "CODEOWNERS" {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		return []byte(content), nil
+	}
+}
+
+func TestChangesetReviewers(t *testing.T) {
+	fallback := []string{"@sourcegraph/batchers"}
+
+	tcs := map[string]struct {
+		codeOwners     bool
+		codeOwnersFile string
+		want           []string
+	}{
+		"code owners disabled": {
+			codeOwnersFile: "*.go @sourcegraph/gophers",
+			want:           fallback,
+		},
+		"matching code owners": {
+			codeOwners:     true,
+			codeOwnersFile: "*.md @sourcegraph/docs\n/cmd/ @sourcegraph/gophers",
+			want:           []string{"@sourcegraph/docs", "@sourcegraph/gophers"},
+		},
+		"no matching code owners": {
+			codeOwners:     true,
+			codeOwnersFile: "/docs/ @sourcegraph/docs",
+			want:           fallback,
+		},
+		"no CODEOWNERS file": {
+			codeOwners: true,
+			want:       fallback,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			mockCodeOwners(t, tc.codeOwnersFile)
+
+			spec := &btypes.ChangesetSpec{Spec: &btypes.ChangesetSpecDescription{
+				BaseRev:   "d34db33f",
+				Commits:   []btypes.GitCommitDescription{{Diff: reviewersTestDiff}},
+				Reviewers: &btypes.ChangesetReviewers{CodeOwners: tc.codeOwners, Fallback: fallback},
+			}}
+
+			have, err := changesetReviewers(context.Background(), "github.com/sourcegraph/sourcegraph", spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf("wrong reviewers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecutor_RequestReviewers(t *testing.T) {
+	mockCodeOwners(t, "* @sourcegraph/everyone\n*.go @sourcegraph/gophers")
+
+	newExecutor := func(reviewers *btypes.ChangesetReviewers) (*executor, *sources.FakeChangesetSource) {
+		css := &sources.FakeChangesetSource{}
+		return &executor{
+			ch:   &btypes.Changeset{ID: 1},
+			css:  css,
+			repo: &types.Repo{Name: "github.com/sourcegraph/sourcegraph"},
+			spec: &btypes.ChangesetSpec{Spec: &btypes.ChangesetSpecDescription{
+				BaseRev:   "d34db33f",
+				Commits:   []btypes.GitCommitDescription{{Diff: reviewersTestDiff}},
+				Reviewers: reviewers,
+			}},
+		}, css
+	}
+
+	t.Run("no reviewers configured", func(t *testing.T) {
+		e, css := newExecutor(nil)
+		e.requestReviewers(context.Background(), &sources.Changeset{Changeset: e.ch, Repo: e.repo})
+		if css.RequestReviewersCalled {
+			t.Fatal("reviewers requested")
+		}
+	})
+
+	t.Run("code owners", func(t *testing.T) {
+		e, css := newExecutor(&btypes.ChangesetReviewers{CodeOwners: true})
+		e.requestReviewers(context.Background(), &sources.Changeset{Changeset: e.ch, Repo: e.repo})
+		if diff := cmp.Diff([]string{"@sourcegraph/everyone", "@sourcegraph/gophers"}, css.RequestedReviewers); diff != "" {
+			t.Fatalf("wrong reviewers requested (-want +got):\n%s", diff)
+		}
+	})
+}
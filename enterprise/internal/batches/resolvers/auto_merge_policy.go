@@ -0,0 +1,138 @@
+package resolvers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+var _ graphqlbackend.BatchChangeAutoMergePolicyResolver = &autoMergePolicyResolver{}
+
+type autoMergePolicyResolver struct {
+	policy *btypes.AutoMergePolicy
+}
+
+func (r *autoMergePolicyResolver) RequiredCheckState() *string {
+	if r.policy.RequiredCheckState == "" {
+		return nil
+	}
+	state := string(r.policy.RequiredCheckState)
+	return &state
+}
+
+func (r *autoMergePolicyResolver) RequiredReviewState() *string {
+	if r.policy.RequiredReviewState == "" {
+		return nil
+	}
+	state := string(r.policy.RequiredReviewState)
+	return &state
+}
+
+func (r *autoMergePolicyResolver) Squash() bool {
+	return r.policy.Squash
+}
+
+func (r *autoMergePolicyResolver) MergeWindow() graphqlbackend.ChangesetMergeWindowResolver {
+	if r.policy.MergeWindow == nil {
+		return nil
+	}
+	return &mergeWindowResolver{window: r.policy.MergeWindow}
+}
+
+var _ graphqlbackend.ChangesetMergeWindowResolver = &mergeWindowResolver{}
+
+type mergeWindowResolver struct {
+	window *btypes.MergeWindow
+}
+
+func (r *mergeWindowResolver) Start() string {
+	return formatMergeWindowTime(r.window.Start)
+}
+
+func (r *mergeWindowResolver) End() string {
+	return formatMergeWindowTime(r.window.End)
+}
+
+func (r *mergeWindowResolver) Weekdays() []string {
+	weekdays := make([]string, 0, len(r.window.Weekdays))
+	for _, d := range r.window.Weekdays {
+		weekdays = append(weekdays, strings.ToUpper(d.String()))
+	}
+	return weekdays
+}
+
+// unmarshalAutoMergePolicyInput validates the given input and converts it into
+// a policy. A nil input results in a nil policy.
+func unmarshalAutoMergePolicyInput(input *graphqlbackend.BatchChangeAutoMergePolicyInput) (*btypes.AutoMergePolicy, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	policy := &btypes.AutoMergePolicy{Squash: input.Squash}
+
+	if input.RequiredCheckState != nil {
+		policy.RequiredCheckState = btypes.ChangesetCheckState(*input.RequiredCheckState)
+		if !policy.RequiredCheckState.Valid() {
+			return nil, errors.Errorf("invalid required check state: %q", *input.RequiredCheckState)
+		}
+	}
+	if input.RequiredReviewState != nil {
+		policy.RequiredReviewState = btypes.ChangesetReviewState(*input.RequiredReviewState)
+		if !policy.RequiredReviewState.Valid() {
+			return nil, errors.Errorf("invalid required review state: %q", *input.RequiredReviewState)
+		}
+	}
+
+	if w := input.MergeWindow; w != nil {
+		start, err := parseMergeWindowTime(w.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseMergeWindowTime(w.End)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.MergeWindow = &btypes.MergeWindow{Start: start, End: end}
+		if w.Weekdays != nil {
+			for _, name := range *w.Weekdays {
+				weekday, ok := weekdays[name]
+				if !ok {
+					return nil, errors.Errorf("invalid weekday: %q", name)
+				}
+				policy.MergeWindow.Weekdays = append(policy.MergeWindow.Weekdays, weekday)
+			}
+		}
+	}
+
+	return policy, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"SUNDAY":    time.Sunday,
+	"MONDAY":    time.Monday,
+	"TUESDAY":   time.Tuesday,
+	"WEDNESDAY": time.Wednesday,
+	"THURSDAY":  time.Thursday,
+	"FRIDAY":    time.Friday,
+	"SATURDAY":  time.Saturday,
+}
+
+// parseMergeWindowTime parses a time of day formatted as HH:MM into minutes
+// after midnight.
+func parseMergeWindowTime(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, errors.Errorf("invalid merge window time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatMergeWindowTime(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,76 @@
+package resolvers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+func TestUnmarshalAutoMergePolicyInput(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	t.Run("nil", func(t *testing.T) {
+		policy, err := unmarshalAutoMergePolicyInput(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if policy != nil {
+			t.Fatalf("expected nil policy, got %+v", policy)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		policy, err := unmarshalAutoMergePolicyInput(&graphqlbackend.BatchChangeAutoMergePolicyInput{
+			RequiredCheckState:  strPtr("PASSED"),
+			RequiredReviewState: strPtr("APPROVED"),
+			Squash:              true,
+			MergeWindow: &graphqlbackend.ChangesetMergeWindowInput{
+				Start:    "22:30",
+				End:      "02:00",
+				Weekdays: &[]string{"MONDAY", "FRIDAY"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := &btypes.AutoMergePolicy{
+			RequiredCheckState:  btypes.ChangesetCheckStatePassed,
+			RequiredReviewState: btypes.ChangesetReviewStateApproved,
+			Squash:              true,
+			MergeWindow: &btypes.MergeWindow{
+				Start:    22*60 + 30,
+				End:      2 * 60,
+				Weekdays: []time.Weekday{time.Monday, time.Friday},
+			},
+		}
+		if diff := cmp.Diff(want, policy); diff != "" {
+			t.Fatalf("unexpected policy (-want +got):\n%s", diff)
+		}
+
+		window := &mergeWindowResolver{window: policy.MergeWindow}
+		if have, want := window.Start(), "22:30"; have != want {
+			t.Errorf("wrong start. want=%q, have=%q", want, have)
+		}
+		if diff := cmp.Diff([]string{"MONDAY", "FRIDAY"}, window.Weekdays()); diff != "" {
+			t.Errorf("unexpected weekdays (-want +got):\n%s", diff)
+		}
+	})
+
+	for name, input := range map[string]*graphqlbackend.BatchChangeAutoMergePolicyInput{
+		"invalid check state":  {RequiredCheckState: strPtr("GREEN")},
+		"invalid review state": {RequiredReviewState: strPtr("LGTM")},
+		"invalid time":         {MergeWindow: &graphqlbackend.ChangesetMergeWindowInput{Start: "25:00", End: "02:00"}},
+		"invalid weekday":      {MergeWindow: &graphqlbackend.ChangesetMergeWindowInput{Start: "09:00", End: "17:00", Weekdays: &[]string{"FUNDAY"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := unmarshalAutoMergePolicyInput(input); err == nil {
+				t.Fatal("expected error, got none")
+			}
+		})
+	}
+}
@@ -286,3 +286,40 @@ func (r *batchChangeResolver) BulkOperations(
 		opts:          opts,
 	}, nil
 }
+
+func (r *batchChangeResolver) AutoMergePolicy(ctx context.Context) (graphqlbackend.BatchChangeAutoMergePolicyResolver, error) {
+	policy, err := r.store.GetAutoMergePolicy(ctx, r.batchChange.ID)
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &autoMergePolicyResolver{policy: policy}, nil
+}
+
+func (r *batchChangeResolver) Rollout(ctx context.Context) (graphqlbackend.BatchChangeRolloutResolver, error) {
+	rollout, err := r.store.GetBatchChangeRollout(ctx, r.batchChange.ID)
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	batchSpec, err := r.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: r.batchChange.BatchSpecID})
+	if err != nil {
+		return nil, err
+	}
+	if batchSpec.Spec.Rollout == nil {
+		return nil, nil
+	}
+
+	stats, err := r.store.GetRolloutWaveStats(ctx, r.batchChange.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rolloutResolver{rollout: rollout, spec: batchSpec.Spec.Rollout, stats: stats}, nil
+}
@@ -0,0 +1,269 @@
+package resolvers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+const batchSpecWorkspaceIDKind = "BatchSpecWorkspace"
+
+func marshalBatchSpecWorkspaceID(id int64) graphql.ID {
+	return relay.MarshalID(batchSpecWorkspaceIDKind, id)
+}
+
+func unmarshalBatchSpecWorkspaceID(id graphql.ID) (workspaceID int64, err error) {
+	err = relay.UnmarshalSpec(id, &workspaceID)
+	return
+}
+
+type batchSpecWorkspaceResolutionResolver struct {
+	store *store.Store
+	job   *btypes.BatchSpecResolutionJob
+}
+
+var _ graphqlbackend.BatchSpecWorkspaceResolutionResolver = &batchSpecWorkspaceResolutionResolver{}
+
+func (r *batchSpecWorkspaceResolutionResolver) State() string {
+	return strings.ToUpper(string(r.job.State))
+}
+
+func (r *batchSpecWorkspaceResolutionResolver) StartedAt() *graphqlbackend.DateTime {
+	if r.job.StartedAt == nil {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: *r.job.StartedAt}
+}
+
+func (r *batchSpecWorkspaceResolutionResolver) FinishedAt() *graphqlbackend.DateTime {
+	if r.job.FinishedAt == nil {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: *r.job.FinishedAt}
+}
+
+func (r *batchSpecWorkspaceResolutionResolver) FailureMessage() *string {
+	return r.job.FailureMessage
+}
+
+func (r *batchSpecWorkspaceResolutionResolver) Workspaces(ctx context.Context, args *graphqlbackend.ListBatchSpecWorkspacesArgs) (graphqlbackend.BatchSpecWorkspaceConnectionResolver, error) {
+	if err := validateFirstParamDefaults(args.First); err != nil {
+		return nil, err
+	}
+	opts := store.ListBatchSpecWorkspacesOpts{
+		LimitOpts:   store.LimitOpts{Limit: int(args.First)},
+		BatchSpecID: r.job.BatchSpecID,
+	}
+	if args.After != nil {
+		id, err := strconv.Atoi(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		opts.Cursor = int64(id)
+	}
+
+	return &batchSpecWorkspaceConnectionResolver{store: r.store, opts: opts}, nil
+}
+
+type batchSpecWorkspaceConnectionResolver struct {
+	store *store.Store
+	opts  store.ListBatchSpecWorkspacesOpts
+
+	// Cache results because they are used by multiple fields
+	once       sync.Once
+	workspaces []*btypes.BatchSpecWorkspace
+	next       int64
+	err        error
+
+	statsOnce sync.Once
+	stats     btypes.BatchSpecWorkspaceStats
+	statsErr  error
+}
+
+var _ graphqlbackend.BatchSpecWorkspaceConnectionResolver = &batchSpecWorkspaceConnectionResolver{}
+
+func (r *batchSpecWorkspaceConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.BatchSpecWorkspaceResolver, error) {
+	workspaces, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(workspaces))
+	for _, ws := range workspaces {
+		repoIDs = append(repoIDs, ws.RepoID)
+	}
+
+	// 🚨 SECURITY: database.Repos.GetReposSetByIDs uses the authzFilter under the hood and
+	// filters out repositories that the user doesn't have access to.
+	repos, err := r.store.Repos().GetReposSetByIDs(ctx, repoIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.BatchSpecWorkspaceResolver, 0, len(workspaces))
+	for _, ws := range workspaces {
+		// 🚨 SECURITY: Workspaces in repositories the user can't access are
+		// omitted.
+		if _, ok := repos[ws.RepoID]; !ok {
+			continue
+		}
+		resolvers = append(resolvers, &batchSpecWorkspaceResolver{store: r.store, workspace: ws})
+	}
+
+	return resolvers, nil
+}
+
+func (r *batchSpecWorkspaceConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	stats, err := r.computeStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return stats.Total, nil
+}
+
+func (r *batchSpecWorkspaceConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, next, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if next != 0 {
+		return graphqlutil.NextPageCursor(strconv.Itoa(int(next))), nil
+	}
+	return graphqlutil.HasNextPage(false), nil
+}
+
+func (r *batchSpecWorkspaceConnectionResolver) Stats(ctx context.Context) (graphqlbackend.BatchSpecWorkspacesStatsResolver, error) {
+	stats, err := r.computeStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &batchSpecWorkspacesStatsResolver{stats: stats}, nil
+}
+
+func (r *batchSpecWorkspaceConnectionResolver) compute(ctx context.Context) ([]*btypes.BatchSpecWorkspace, int64, error) {
+	r.once.Do(func() {
+		r.workspaces, r.next, r.err = r.store.ListBatchSpecWorkspaces(ctx, r.opts)
+	})
+	return r.workspaces, r.next, r.err
+}
+
+func (r *batchSpecWorkspaceConnectionResolver) computeStats(ctx context.Context) (btypes.BatchSpecWorkspaceStats, error) {
+	r.statsOnce.Do(func() {
+		r.stats, r.statsErr = r.store.GetBatchSpecWorkspaceStats(ctx, r.opts.BatchSpecID)
+	})
+	return r.stats, r.statsErr
+}
+
+type batchSpecWorkspacesStatsResolver struct {
+	stats btypes.BatchSpecWorkspaceStats
+}
+
+var _ graphqlbackend.BatchSpecWorkspacesStatsResolver = &batchSpecWorkspacesStatsResolver{}
+
+func (r *batchSpecWorkspacesStatsResolver) Queued() int32     { return r.stats.Queued }
+func (r *batchSpecWorkspacesStatsResolver) Processing() int32 { return r.stats.Processing }
+func (r *batchSpecWorkspacesStatsResolver) Completed() int32  { return r.stats.Completed }
+
+// Failed includes the workspaces that errored and won't be retried anymore.
+func (r *batchSpecWorkspacesStatsResolver) Failed() int32 {
+	return r.stats.Failed + r.stats.Errored
+}
+
+type batchSpecWorkspaceResolver struct {
+	store     *store.Store
+	workspace *btypes.BatchSpecWorkspace
+}
+
+var _ graphqlbackend.BatchSpecWorkspaceResolver = &batchSpecWorkspaceResolver{}
+
+func (r *batchSpecWorkspaceResolver) ID() graphql.ID {
+	return marshalBatchSpecWorkspaceID(r.workspace.ID)
+}
+
+func (r *batchSpecWorkspaceResolver) Repository(ctx context.Context) (*graphqlbackend.RepositoryResolver, error) {
+	// 🚨 SECURITY: database.Repos.Get uses the authzFilter under the hood and
+	// returns an error if the user doesn't have access to the repository.
+	repo, err := r.store.Repos().Get(ctx, r.workspace.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlbackend.NewRepositoryResolver(r.store.DB(), repo), nil
+}
+
+func (r *batchSpecWorkspaceResolver) Branch() string {
+	return r.workspace.Branch
+}
+
+func (r *batchSpecWorkspaceResolver) Commit() string {
+	return r.workspace.Commit
+}
+
+func (r *batchSpecWorkspaceResolver) Path() string {
+	return r.workspace.Path
+}
+
+func (r *batchSpecWorkspaceResolver) OnlyFetchWorkspace() bool {
+	return r.workspace.OnlyFetchWorkspace
+}
+
+func (r *batchSpecWorkspaceResolver) State() string {
+	return strings.ToUpper(string(r.workspace.State))
+}
+
+func (r *batchSpecWorkspaceResolver) StartedAt() *graphqlbackend.DateTime {
+	if r.workspace.StartedAt == nil {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: *r.workspace.StartedAt}
+}
+
+func (r *batchSpecWorkspaceResolver) FinishedAt() *graphqlbackend.DateTime {
+	if r.workspace.FinishedAt == nil {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: *r.workspace.FinishedAt}
+}
+
+func (r *batchSpecWorkspaceResolver) FailureMessage() *string {
+	return r.workspace.FailureMessage
+}
+
+func (r *batchSpecWorkspaceResolver) ExecutionLogs() []graphqlbackend.ExecutionLogEntryResolver {
+	resolvers := make([]graphqlbackend.ExecutionLogEntryResolver, 0, len(r.workspace.ExecutionLogs))
+	for _, entry := range r.workspace.ExecutionLogs {
+		resolvers = append(resolvers, graphqlbackend.NewExecutionLogEntryResolver(r.store.DB(), entry))
+	}
+	return resolvers
+}
+
+func (r *batchSpecWorkspaceResolver) ChangesetSpecs(ctx context.Context) ([]graphqlbackend.ChangesetSpecResolver, error) {
+	if len(r.workspace.ChangesetSpecIDs) == 0 {
+		return []graphqlbackend.ChangesetSpecResolver{}, nil
+	}
+
+	specs, _, err := r.store.ListChangesetSpecs(ctx, store.ListChangesetSpecsOpts{IDs: r.workspace.ChangesetSpecIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.ChangesetSpecResolver, 0, len(specs))
+	for _, spec := range specs {
+		resolver, err := NewChangesetSpecResolver(ctx, r.store, spec)
+		if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, resolver)
+	}
+
+	return resolvers, nil
+}
@@ -0,0 +1,47 @@
+package resolvers
+
+import (
+	"testing"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+func TestBatchSpecWorkspacesStatsResolver(t *testing.T) {
+	r := &batchSpecWorkspacesStatsResolver{stats: btypes.BatchSpecWorkspaceStats{
+		Total:      10,
+		Queued:     2,
+		Processing: 3,
+		Completed:  1,
+		Errored:    1,
+		Failed:     3,
+	}}
+
+	if have, want := r.Queued(), int32(2); have != want {
+		t.Errorf("wrong queued. want=%d, have=%d", want, have)
+	}
+	if have, want := r.Processing(), int32(3); have != want {
+		t.Errorf("wrong processing. want=%d, have=%d", want, have)
+	}
+	if have, want := r.Completed(), int32(1); have != want {
+		t.Errorf("wrong completed. want=%d, have=%d", want, have)
+	}
+	// Workspaces that errored and won't be retried anymore count as failed.
+	if have, want := r.Failed(), int32(4); have != want {
+		t.Errorf("wrong failed. want=%d, have=%d", want, have)
+	}
+}
+
+func TestBatchSpecWorkspaceResolverID(t *testing.T) {
+	r := &batchSpecWorkspaceResolver{workspace: &btypes.BatchSpecWorkspace{ID: 42, State: btypes.BatchSpecExecutionStateProcessing}}
+
+	id, err := unmarshalBatchSpecWorkspaceID(r.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 {
+		t.Errorf("wrong ID. want=%d, have=%d", 42, id)
+	}
+	if have, want := r.State(), "PROCESSING"; have != want {
+		t.Errorf("wrong state. want=%q, have=%q", want, have)
+	}
+}
@@ -15,12 +15,14 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/externallink"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/reconciler"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/state"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/syncer"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types/scheduler/config"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -387,6 +389,58 @@ func (r *changesetResolver) Error() *string { return r.changeset.FailureMessage
 
 func (r *changesetResolver) SyncerError() *string { return r.changeset.SyncErrorMessage }
 
+func (r *changesetResolver) AutoMergeBlockedReason() *string {
+	return r.changeset.AutoMergeBlockedReason
+}
+
+func (r *changesetResolver) RolloutWave() *int32 { return r.changeset.RolloutWave }
+
+func (r *changesetResolver) HeldByRollout() bool { return r.changeset.RolloutHeld }
+
+func (r *changesetResolver) Dependencies(ctx context.Context) ([]graphqlbackend.ChangesetDependencyResolver, error) {
+	if r.changeset.CurrentSpecID == 0 {
+		return []graphqlbackend.ChangesetDependencyResolver{}, nil
+	}
+
+	spec, err := r.computeSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := reconciler.ResolveChangesetDependencies(ctx, r.store, r.changeset, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(deps))
+	for _, d := range deps {
+		if d.Changeset != nil {
+			repoIDs = append(repoIDs, d.Changeset.RepoID)
+		}
+	}
+
+	// 🚨 SECURITY: database.Repos.GetReposSetByIDs uses the authzFilter under the hood and
+	// filters out repositories that the user doesn't have access to.
+	// Changesets in those repositories are resolved as hidden changesets.
+	repos, err := r.store.Repos().GetReposSetByIDs(ctx, repoIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.ChangesetDependencyResolver, 0, len(deps))
+	for _, d := range deps {
+		resolver := &changesetDependencyResolver{dependency: d}
+		if d.Changeset != nil {
+			resolver.changeset = NewChangesetResolver(r.store, d.Changeset, repos[d.Changeset.RepoID])
+		}
+		resolvers = append(resolvers, resolver)
+	}
+
+	return resolvers, nil
+}
+
+func (r *changesetResolver) HeldByDependencies() bool { return r.changeset.DependenciesHeld }
+
 func (r *changesetResolver) ScheduleEstimateAt(ctx context.Context) (*graphqlbackend.DateTime, error) {
 	// We need to find out how deep in the queue this changeset is.
 	place, err := r.store.GetChangesetPlaceInSchedulerQueue(ctx, r.changeset.ID)
@@ -0,0 +1,57 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/reconciler"
+)
+
+type changesetDependencyResolver struct {
+	dependency *reconciler.ResolvedChangesetDependency
+
+	// changeset is nil if the dependency doesn't refer to an existing
+	// changeset.
+	changeset *changesetResolver
+}
+
+var _ graphqlbackend.ChangesetDependencyResolver = &changesetDependencyResolver{}
+
+func (r *changesetDependencyResolver) RepositoryName() string {
+	return r.dependency.Repository
+}
+
+func (r *changesetDependencyResolver) Branch() *string {
+	if r.dependency.Branch == "" {
+		return nil
+	}
+	return &r.dependency.Branch
+}
+
+func (r *changesetDependencyResolver) BatchChangeName() *string {
+	if r.dependency.BatchChange == "" {
+		return nil
+	}
+	return &r.dependency.BatchChange
+}
+
+func (r *changesetDependencyResolver) ExternalID() *string {
+	if r.dependency.ExternalID == "" {
+		return nil
+	}
+	return &r.dependency.ExternalID
+}
+
+func (r *changesetDependencyResolver) Changeset() graphqlbackend.ChangesetResolver {
+	if r.changeset == nil {
+		return nil
+	}
+	return r.changeset
+}
+
+func (r *changesetDependencyResolver) Merged() *bool {
+	// 🚨 SECURITY: The state of hidden changesets must not be revealed.
+	if r.changeset != nil && !r.changeset.repoAccessible() {
+		return nil
+	}
+	merged := r.dependency.Merged()
+	return &merged
+}
@@ -0,0 +1,41 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/reconciler"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestChangesetDependencyResolverMerged(t *testing.T) {
+	changeset := &btypes.Changeset{ExternalState: btypes.ChangesetExternalStateMerged}
+	dependency := &reconciler.ResolvedChangesetDependency{Changeset: changeset}
+
+	t.Run("accessible changeset", func(t *testing.T) {
+		r := &changesetDependencyResolver{
+			dependency: dependency,
+			changeset:  &changesetResolver{changeset: changeset, repo: &types.Repo{}},
+		}
+		if have := r.Merged(); have == nil || !*have {
+			t.Fatalf("wrong merged value. want=true, have=%v", have)
+		}
+	})
+
+	t.Run("hidden changeset", func(t *testing.T) {
+		r := &changesetDependencyResolver{
+			dependency: dependency,
+			changeset:  &changesetResolver{changeset: changeset},
+		}
+		if have := r.Merged(); have != nil {
+			t.Fatalf("wrong merged value. want=nil, have=%v", *have)
+		}
+	})
+
+	t.Run("no changeset", func(t *testing.T) {
+		r := &changesetDependencyResolver{dependency: &reconciler.ResolvedChangesetDependency{}}
+		if have := r.Merged(); have == nil || *have {
+			t.Fatalf("wrong merged value. want=false, have=%v", have)
+		}
+	})
+}
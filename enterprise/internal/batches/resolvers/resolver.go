@@ -130,6 +130,9 @@ func (r *Resolver) NodeResolvers() map[string]graphqlbackend.NodeByIDFunc {
 		batchSpecExecutionIDKind: func(ctx context.Context, id graphql.ID) (graphqlbackend.Node, error) {
 			return r.batchSpecExecutionByID(ctx, id)
 		},
+		batchSpecWorkspaceIDKind: func(ctx context.Context, id graphql.ID) (graphqlbackend.Node, error) {
+			return r.batchSpecWorkspaceByID(ctx, id)
+		},
 	}
 }
 
@@ -371,6 +374,43 @@ func (r *Resolver) batchSpecExecutionByID(ctx context.Context, id graphql.ID) (g
 	return &batchSpecExecutionResolver{store: r.store, exec: spec}, nil
 }
 
+func (r *Resolver) batchSpecWorkspaceByID(ctx context.Context, id graphql.ID) (graphqlbackend.BatchSpecWorkspaceResolver, error) {
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	workspaceID, err := unmarshalBatchSpecWorkspaceID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if workspaceID == 0 {
+		return nil, nil
+	}
+
+	workspace, err := r.store.GetBatchSpecWorkspace(ctx, workspaceID)
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	spec, err := r.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: workspace.BatchSpecID})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Workspace IDs are sequential, so only the creator of the
+	// batch spec can look its workspaces up by ID. Everyone else needs to
+	// know the unguessable ID of the batch spec.
+	if ok, err := checkSiteAdminOrSameUser(ctx, r.store.DB(), spec.UserID); err != nil || !ok {
+		return nil, err
+	}
+
+	return &batchSpecWorkspaceResolver{store: r.store, workspace: workspace}, nil
+}
+
 func (r *Resolver) CreateBatchChange(ctx context.Context, args *graphqlbackend.CreateBatchChangeArgs) (graphqlbackend.BatchChangeResolver, error) {
 	var err error
 	tr, _ := trace.New(ctx, "Resolver.CreateBatchChange", fmt.Sprintf("BatchSpec %s", args.BatchSpec))
@@ -384,9 +424,10 @@ func (r *Resolver) CreateBatchChange(ctx context.Context, args *graphqlbackend.C
 		FailIfBatchChangeExists: true,
 	}
 	batchChange, err := r.applyOrCreateBatchChange(ctx, &graphqlbackend.ApplyBatchChangeArgs{
-		BatchSpec:         args.BatchSpec,
-		EnsureBatchChange: nil,
-		PublicationStates: args.PublicationStates,
+		BatchSpec:             args.BatchSpec,
+		EnsureBatchChange:     nil,
+		PublicationStates:     args.PublicationStates,
+		AllowFailedWorkspaces: args.AllowFailedWorkspaces,
 	}, opts)
 	if err != nil {
 		return nil, err
@@ -468,6 +509,8 @@ func (r *Resolver) applyOrCreateBatchChange(ctx context.Context, args *graphqlba
 		return nil, err
 	}
 
+	opts.AllowFailedWorkspaces = args.AllowFailedWorkspaces
+
 	svc := service.New(r.store)
 	// 🚨 SECURITY: ApplyBatchChange checks whether the user has permission to
 	// apply the batch spec.
@@ -1022,14 +1065,22 @@ func (r *Resolver) CreateBatchChangesCredential(ctx context.Context, args *graph
 		return nil, errors.New("empty credential not allowed")
 	}
 
+	var username string
+	if args.Username != nil {
+		username = *args.Username
+	}
+	if kind == extsvc.KindBitbucketCloud && username == "" {
+		return nil, errors.New("a username is required for Bitbucket Cloud credentials")
+	}
+
 	if userID != 0 {
-		return r.createBatchChangesUserCredential(ctx, args.ExternalServiceURL, extsvc.KindToType(kind), userID, args.Credential)
+		return r.createBatchChangesUserCredential(ctx, args.ExternalServiceURL, extsvc.KindToType(kind), userID, username, args.Credential)
 	}
 
-	return r.createBatchChangesSiteCredential(ctx, args.ExternalServiceURL, extsvc.KindToType(kind), args.Credential)
+	return r.createBatchChangesSiteCredential(ctx, args.ExternalServiceURL, extsvc.KindToType(kind), username, args.Credential)
 }
 
-func (r *Resolver) createBatchChangesUserCredential(ctx context.Context, externalServiceURL, externalServiceType string, userID int32, credential string) (graphqlbackend.BatchChangesCredentialResolver, error) {
+func (r *Resolver) createBatchChangesUserCredential(ctx context.Context, externalServiceURL, externalServiceType string, userID int32, username, credential string) (graphqlbackend.BatchChangesCredentialResolver, error) {
 	// 🚨 SECURITY: Check that the requesting user can create the credential.
 	if err := backend.CheckSiteAdminOrSameUser(ctx, r.store.DB(), userID); err != nil {
 		return nil, err
@@ -1050,7 +1101,7 @@ func (r *Resolver) createBatchChangesUserCredential(ctx context.Context, externa
 		return nil, ErrDuplicateCredential{}
 	}
 
-	a, err := r.generateAuthenticatorForCredential(ctx, externalServiceType, externalServiceURL, credential)
+	a, err := r.generateAuthenticatorForCredential(ctx, externalServiceType, externalServiceURL, username, credential)
 	if err != nil {
 		return nil, err
 	}
@@ -1062,7 +1113,7 @@ func (r *Resolver) createBatchChangesUserCredential(ctx context.Context, externa
 	return &batchChangesUserCredentialResolver{credential: cred}, nil
 }
 
-func (r *Resolver) createBatchChangesSiteCredential(ctx context.Context, externalServiceURL, externalServiceType string, credential string) (graphqlbackend.BatchChangesCredentialResolver, error) {
+func (r *Resolver) createBatchChangesSiteCredential(ctx context.Context, externalServiceURL, externalServiceType, username, credential string) (graphqlbackend.BatchChangesCredentialResolver, error) {
 	// 🚨 SECURITY: Check that a site credential can only be created
 	// by a site-admin.
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.store.DB()); err != nil {
@@ -1081,7 +1132,7 @@ func (r *Resolver) createBatchChangesSiteCredential(ctx context.Context, externa
 		return nil, ErrDuplicateCredential{}
 	}
 
-	a, err := r.generateAuthenticatorForCredential(ctx, externalServiceType, externalServiceURL, credential)
+	a, err := r.generateAuthenticatorForCredential(ctx, externalServiceType, externalServiceURL, username, credential)
 	if err != nil {
 		return nil, err
 	}
@@ -1096,7 +1147,7 @@ func (r *Resolver) createBatchChangesSiteCredential(ctx context.Context, externa
 	return &batchChangesSiteCredentialResolver{credential: cred}, nil
 }
 
-func (r *Resolver) generateAuthenticatorForCredential(ctx context.Context, externalServiceType, externalServiceURL, credential string) (auth.Authenticator, error) {
+func (r *Resolver) generateAuthenticatorForCredential(ctx context.Context, externalServiceType, externalServiceURL, username, credential string) (auth.Authenticator, error) {
 	svc := service.New(r.store)
 
 	var a auth.Authenticator
@@ -1119,6 +1170,15 @@ func (r *Resolver) generateAuthenticatorForCredential(ctx context.Context, exter
 			PublicKey:  keypair.PublicKey,
 			Passphrase: keypair.Passphrase,
 		}
+	} else if externalServiceType == extsvc.TypeBitbucketCloud {
+		// Bitbucket Cloud app passwords are only valid in combination with
+		// the username they belong to.
+		a = &auth.BasicAuthWithSSH{
+			BasicAuth:  auth.BasicAuth{Username: username, Password: credential},
+			PrivateKey: keypair.PrivateKey,
+			PublicKey:  keypair.PublicKey,
+			Passphrase: keypair.Passphrase,
+		}
 	} else {
 		a = &auth.OAuthBearerTokenWithSSH{
 			OAuthBearerToken: auth.OAuthBearerToken{Token: credential},
@@ -1417,6 +1477,98 @@ func (r *Resolver) PublishChangesets(ctx context.Context, args *graphqlbackend.P
 
 }
 
+func (r *Resolver) SetBatchChangeAutoMergePolicy(ctx context.Context, args *graphqlbackend.SetBatchChangeAutoMergePolicyArgs) (_ graphqlbackend.BatchChangeResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.SetBatchChangeAutoMergePolicy", fmt.Sprintf("BatchChange: %q", args.BatchChange))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
+	if err != nil {
+		return nil, err
+	}
+
+	if batchChangeID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	policy, err := unmarshalAutoMergePolicyInput(args.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: SetAutoMergePolicy checks whether the current user is authorized.
+	batchChange, err := svc.SetAutoMergePolicy(ctx, batchChangeID, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchChangeResolver{store: r.store, batchChange: batchChange}, nil
+}
+
+func (r *Resolver) PauseBatchChangeRollout(ctx context.Context, args *graphqlbackend.PauseBatchChangeRolloutArgs) (_ graphqlbackend.BatchChangeResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.PauseBatchChangeRollout", fmt.Sprintf("BatchChange: %q", args.BatchChange))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
+	if err != nil {
+		return nil, err
+	}
+
+	if batchChangeID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: PauseRollout checks whether the current user is authorized.
+	batchChange, err := svc.PauseRollout(ctx, batchChangeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchChangeResolver{store: r.store, batchChange: batchChange}, nil
+}
+
+func (r *Resolver) ResumeBatchChangeRollout(ctx context.Context, args *graphqlbackend.ResumeBatchChangeRolloutArgs) (_ graphqlbackend.BatchChangeResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.ResumeBatchChangeRollout", fmt.Sprintf("BatchChange: %q, ReleaseNextWave: %t", args.BatchChange, args.ReleaseNextWave))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
+	if err != nil {
+		return nil, err
+	}
+
+	if batchChangeID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: ResumeRollout checks whether the current user is authorized.
+	batchChange, err := svc.ResumeRollout(ctx, batchChangeID, args.ReleaseNextWave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchChangeResolver{store: r.store, batchChange: batchChange}, nil
+}
+
 func (r *Resolver) CreateBatchSpecExecution(ctx context.Context, args *graphqlbackend.CreateBatchSpecExecutionArgs) (_ graphqlbackend.BatchSpecExecutionResolver, err error) {
 	tr, ctx := trace.New(ctx, "Resolver.CreateBatchSpecExecution", "")
 	defer func() {
@@ -1462,6 +1614,73 @@ func (r *Resolver) CreateBatchSpecExecution(ctx context.Context, args *graphqlba
 	return r.batchSpecExecutionByID(ctx, marshalBatchSpecExecutionRandID(exec.RandID))
 }
 
+func (r *Resolver) ExecuteBatchSpec(ctx context.Context, args *graphqlbackend.ExecuteBatchSpecArgs) (_ graphqlbackend.BatchSpecResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.ExecuteBatchSpec", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if err := batchChangesCreateAccess(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	// The number of changesets is only known once the batch spec has been
+	// executed, so server-side execution requires a license.
+	if err := checkLicense(); err != nil {
+		return nil, err
+	}
+
+	opts := service.ExecuteBatchSpecOpts{RawSpec: args.Spec}
+	if args.Namespace != nil {
+		err = graphqlbackend.UnmarshalNamespaceID(*args.Namespace, &opts.NamespaceUserID, &opts.NamespaceOrgID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		opts.NamespaceUserID = actor.FromContext(ctx).UID
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: ExecuteBatchSpec checks whether the current user has
+	// access to the namespace.
+	batchSpec, err := svc.ExecuteBatchSpec(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchSpecResolver{store: r.store, batchSpec: batchSpec}, nil
+}
+
+func (r *Resolver) RetryBatchSpecWorkspace(ctx context.Context, args *graphqlbackend.RetryBatchSpecWorkspaceArgs) (_ graphqlbackend.BatchSpecWorkspaceResolver, err error) {
+	tr, ctx := trace.New(ctx, "Resolver.RetryBatchSpecWorkspace", fmt.Sprintf("Workspace: %q", args.Workspace))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+	if err := enterprise.BatchChangesEnabledForUser(ctx, r.store.DB()); err != nil {
+		return nil, err
+	}
+
+	workspaceID, err := unmarshalBatchSpecWorkspaceID(args.Workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	if workspaceID == 0 {
+		return nil, ErrIDIsZero{}
+	}
+
+	svc := service.New(r.store)
+	// 🚨 SECURITY: RetryBatchSpecWorkspace checks whether the current user is authorized.
+	workspace, err := svc.RetryBatchSpecWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchSpecWorkspaceResolver{store: r.store, workspace: workspace}, nil
+}
+
 func parseBatchChangeState(s *string) (btypes.BatchChangeState, error) {
 	if s == nil {
 		return btypes.BatchChangeStateAny, nil
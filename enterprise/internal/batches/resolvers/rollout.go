@@ -0,0 +1,83 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+var _ graphqlbackend.BatchChangeRolloutResolver = &rolloutResolver{}
+
+type rolloutResolver struct {
+	rollout *btypes.BatchChangeRollout
+	spec    *btypes.BatchSpecRollout
+	stats   []*btypes.RolloutWaveStats
+}
+
+func (r *rolloutResolver) CurrentWave() int32 {
+	return r.rollout.CurrentWave
+}
+
+func (r *rolloutResolver) Paused() bool {
+	return r.rollout.Paused()
+}
+
+func (r *rolloutResolver) PausedAt() *graphqlbackend.DateTime {
+	if !r.rollout.Paused() {
+		return nil
+	}
+	return &graphqlbackend.DateTime{Time: r.rollout.PausedAt}
+}
+
+func (r *rolloutResolver) PauseReason() *string {
+	if !r.rollout.Paused() || r.rollout.PauseReason == "" {
+		return nil
+	}
+	return &r.rollout.PauseReason
+}
+
+func (r *rolloutResolver) MinSuccessRatio() float64 {
+	return r.spec.SuccessRatio()
+}
+
+func (r *rolloutResolver) PauseOnFailure() bool {
+	return r.spec.PauseOnFailure
+}
+
+func (r *rolloutResolver) Waves() []graphqlbackend.BatchChangeRolloutWaveResolver {
+	// Changesets keep their wave when a batch spec with fewer waves is
+	// applied, so those waves are listed as well.
+	count := r.spec.WaveCount()
+	if n := len(r.stats); n > 0 && r.stats[n-1].Wave >= count {
+		count = r.stats[n-1].Wave + 1
+	}
+
+	waves := make([]graphqlbackend.BatchChangeRolloutWaveResolver, 0, count)
+	for i := int32(0); i < count; i++ {
+		wave := &rolloutWaveResolver{
+			stats:    &btypes.RolloutWaveStats{Wave: i},
+			released: i <= r.rollout.CurrentWave,
+		}
+		for _, s := range r.stats {
+			if s.Wave == i {
+				wave.stats = s
+			}
+		}
+		waves = append(waves, wave)
+	}
+
+	return waves
+}
+
+var _ graphqlbackend.BatchChangeRolloutWaveResolver = &rolloutWaveResolver{}
+
+type rolloutWaveResolver struct {
+	stats    *btypes.RolloutWaveStats
+	released bool
+}
+
+func (r *rolloutWaveResolver) Index() int32     { return r.stats.Wave }
+func (r *rolloutWaveResolver) Released() bool   { return r.released }
+func (r *rolloutWaveResolver) Total() int32     { return r.stats.Changesets }
+func (r *rolloutWaveResolver) Succeeded() int32 { return r.stats.Succeeded }
+func (r *rolloutWaveResolver) Failed() int32    { return r.stats.Failed }
+func (r *rolloutWaveResolver) Pending() int32   { return r.stats.Pending }
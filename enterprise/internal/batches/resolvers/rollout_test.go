@@ -0,0 +1,42 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+func TestRolloutResolverWaves(t *testing.T) {
+	r := &rolloutResolver{
+		rollout: &btypes.BatchChangeRollout{CurrentWave: 1},
+		spec: &btypes.BatchSpecRollout{
+			Waves: []btypes.BatchSpecRolloutWave{{Count: 1}, {Count: 1}},
+		},
+		stats: []*btypes.RolloutWaveStats{
+			{Wave: 0, Changesets: 1, Succeeded: 1},
+			{Wave: 2, Changesets: 3, Pending: 3},
+		},
+	}
+
+	type wave struct {
+		Index                             int32
+		Released                          bool
+		Total, Succeeded, Failed, Pending int32
+	}
+
+	var have []wave
+	for _, w := range r.Waves() {
+		have = append(have, wave{w.Index(), w.Released(), w.Total(), w.Succeeded(), w.Failed(), w.Pending()})
+	}
+
+	want := []wave{
+		{Index: 0, Released: true, Total: 1, Succeeded: 1},
+		{Index: 1, Released: true},
+		{Index: 2, Total: 3, Pending: 3},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("wrong waves (-want +got):\n%s", diff)
+	}
+}
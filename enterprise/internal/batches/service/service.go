@@ -634,3 +634,224 @@ func (s *Service) CreateChangesetJobs(ctx context.Context, batchChangeID int64,
 
 	return bulkGroupID, nil
 }
+
+// SetAutoMergePolicy replaces the auto-merge policy of the given batch change.
+// A nil policy disables auto-merging.
+func (s *Service) SetAutoMergePolicy(ctx context.Context, batchChangeID int64, policy *btypes.AutoMergePolicy) (batchChange *btypes.BatchChange, err error) {
+	traceTitle := fmt.Sprintf("batchChange: %d, enabled: %t", batchChangeID, policy != nil)
+	tr, ctx := trace.New(ctx, "service.SetAutoMergePolicy", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	batchChange, err = s.store.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChangeID})
+	if err != nil {
+		return nil, errors.Wrap(err, "loading batch change")
+	}
+
+	// 🚨 SECURITY: Only the author of the batch change can change its auto-merge policy.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), batchChange.InitialApplierID); err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		return batchChange, s.store.DeleteAutoMergePolicy(ctx, batchChange.ID)
+	}
+
+	// Changesets are merged on behalf of the user who set the policy.
+	policy.BatchChangeID = batchChange.ID
+	policy.UserID = actor.FromContext(ctx).UID
+	return batchChange, s.store.UpsertAutoMergePolicy(ctx, policy)
+}
+
+// ErrNoRollout is returned by PauseRollout and ResumeRollout if the batch
+// spec of the batch change doesn't declare rollout waves.
+var ErrNoRollout = errors.New("batch change has no rollout waves")
+
+// PauseRollout pauses the rollout of the given batch change, so that no
+// further changesets are published until it is resumed.
+func (s *Service) PauseRollout(ctx context.Context, batchChangeID int64) (batchChange *btypes.BatchChange, err error) {
+	traceTitle := fmt.Sprintf("batchChange: %d", batchChangeID)
+	tr, ctx := trace.New(ctx, "service.PauseRollout", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	batchChange, rollout, err := s.loadRollout(ctx, batchChangeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rollout.Paused() {
+		return batchChange, nil
+	}
+
+	rollout.PausedAt = s.clock()
+	rollout.PauseReason = "The rollout was paused by a user."
+	return batchChange, s.store.UpdateBatchChangeRollout(ctx, rollout)
+}
+
+// ResumeRollout resumes the paused rollout of the given batch change. If
+// releaseNextWave is true, the next wave is released right away, regardless of
+// the outcome of the earlier waves.
+func (s *Service) ResumeRollout(ctx context.Context, batchChangeID int64, releaseNextWave bool) (batchChange *btypes.BatchChange, err error) {
+	traceTitle := fmt.Sprintf("batchChange: %d, releaseNextWave: %t", batchChangeID, releaseNextWave)
+	tr, ctx := trace.New(ctx, "service.ResumeRollout", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	batchChange, rollout, err := s.loadRollout(ctx, batchChangeID)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSpec, err := s.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: batchChange.BatchSpecID})
+	if err != nil {
+		return nil, errors.Wrap(err, "loading batch spec")
+	}
+	if batchSpec.Spec.Rollout == nil {
+		return nil, ErrNoRollout
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	rollout.PausedAt = time.Time{}
+	rollout.PauseReason = ""
+	if releaseNextWave && rollout.CurrentWave < batchSpec.Spec.Rollout.WaveCount()-1 {
+		rollout.CurrentWave++
+	}
+	if err := tx.UpdateBatchChangeRollout(ctx, rollout); err != nil {
+		return nil, err
+	}
+
+	if err := tx.ReleaseHeldRolloutChangesets(ctx, batchChange.ID, rollout.CurrentWave, global.DefaultReconcilerEnqueueState()); err != nil {
+		return nil, err
+	}
+
+	return batchChange, nil
+}
+
+func (s *Service) loadRollout(ctx context.Context, batchChangeID int64) (*btypes.BatchChange, *btypes.BatchChangeRollout, error) {
+	batchChange, err := s.store.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChangeID})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "loading batch change")
+	}
+
+	// 🚨 SECURITY: Only the author of the batch change can control its rollout.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), batchChange.InitialApplierID); err != nil {
+		return nil, nil, err
+	}
+
+	rollout, err := s.store.GetBatchChangeRollout(ctx, batchChange.ID)
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil, nil, ErrNoRollout
+		}
+		return nil, nil, errors.Wrap(err, "loading rollout")
+	}
+
+	return batchChange, rollout, nil
+}
+
+type ExecuteBatchSpecOpts struct {
+	RawSpec string
+
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+}
+
+// ExecuteBatchSpec creates the BatchSpec and enqueues the resolution of its
+// workspaces, which are then executed server-side.
+func (s *Service) ExecuteBatchSpec(ctx context.Context, opts ExecuteBatchSpecOpts) (spec *btypes.BatchSpec, err error) {
+	actor := actor.FromContext(ctx)
+	tr, ctx := trace.New(ctx, "Service.ExecuteBatchSpec", fmt.Sprintf("Actor %s", actor))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	spec, err = btypes.NewBatchSpecFromRaw(opts.RawSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fail right away instead of when the workspaces are resolved.
+	if err := checkStepsExecutableServerSide(spec); err != nil {
+		return nil, err
+	}
+
+	// Check whether the current user has access to either one of the namespaces.
+	err = s.CheckNamespaceAccess(ctx, opts.NamespaceUserID, opts.NamespaceOrgID)
+	if err != nil {
+		return nil, err
+	}
+	spec.NamespaceOrgID = opts.NamespaceOrgID
+	spec.NamespaceUserID = opts.NamespaceUserID
+	spec.UserID = actor.UID
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.CreateBatchSpec(ctx, spec); err != nil {
+		return nil, err
+	}
+
+	if err := tx.CreateBatchSpecResolutionJob(ctx, &btypes.BatchSpecResolutionJob{BatchSpecID: spec.ID}); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// ErrBatchSpecWorkspaceNotRetryable is returned by RetryBatchSpecWorkspace if
+// the workspace didn't fail.
+var ErrBatchSpecWorkspaceNotRetryable = errors.New("only workspaces that failed can be retried")
+
+// RetryBatchSpecWorkspace enqueues the execution of the given failed
+// workspace again.
+func (s *Service) RetryBatchSpecWorkspace(ctx context.Context, id int64) (ws *btypes.BatchSpecWorkspace, err error) {
+	traceTitle := fmt.Sprintf("workspace: %d", id)
+	tr, ctx := trace.New(ctx, "service.RetryBatchSpecWorkspace", traceTitle)
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	ws, err = s.store.GetBatchSpecWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := s.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: ws.BatchSpecID})
+	if err != nil {
+		return nil, errors.Wrap(err, "loading batch spec")
+	}
+
+	// 🚨 SECURITY: Only the creator of the batch spec can retry its workspaces.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, s.store.DB(), spec.UserID); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: We use database.Repos.Get to check whether the user has access to
+	// the repository of the workspace.
+	if _, err := s.store.Repos().Get(ctx, ws.RepoID); err != nil {
+		return nil, err
+	}
+
+	ws, err = s.store.RetryBatchSpecWorkspace(ctx, ws.ID)
+	if err == store.ErrNoResults {
+		return nil, ErrBatchSpecWorkspaceNotRetryable
+	}
+	return ws, err
+}
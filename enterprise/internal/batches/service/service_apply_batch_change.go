@@ -28,6 +28,19 @@ var ErrMatchingBatchChangeExists = errors.New("a batch change matching the given
 // batchSpec exists in the given namespace but has a different ID.
 var ErrEnsureBatchChangeFailed = errors.New("a batch change in the given namespace and with the given name exists but does not match the given ID")
 
+// ErrBatchSpecExecutionUnfinished is returned by ApplyBatchChange if the batch
+// spec is executed server-side and not all of its workspaces have finished.
+var ErrBatchSpecExecutionUnfinished = errors.New("the batch spec is still being executed, wait for all of its workspaces to finish before applying it")
+
+// ErrBatchSpecResolutionFailed is returned by ApplyBatchChange if the batch
+// spec is executed server-side and resolving its workspaces failed.
+var ErrBatchSpecResolutionFailed = errors.New("resolving the workspaces of the batch spec failed")
+
+// ErrBatchSpecWorkspacesFailed is returned by ApplyBatchChange if the batch
+// spec is executed server-side, some of its workspaces failed and
+// AllowFailedWorkspaces was not set.
+var ErrBatchSpecWorkspacesFailed = errors.New("some workspaces of the batch spec failed, retry them or explicitly allow applying the batch spec without them")
+
 type ApplyBatchChangeOpts struct {
 	BatchSpecRandID     string
 	EnsureBatchChangeID int64
@@ -36,6 +49,11 @@ type ApplyBatchChangeOpts struct {
 	// matching the given batch spec already exists.
 	FailIfBatchChangeExists bool
 
+	// When AllowFailedWorkspaces is true, a batch spec executed server-side
+	// can be applied even if some of its workspaces failed. Their changesets
+	// are skipped.
+	AllowFailedWorkspaces bool
+
 	PublicationStates UiPublicationStates
 }
 
@@ -67,6 +85,10 @@ func (s *Service) ApplyBatchChange(ctx context.Context, opts ApplyBatchChangeOpt
 		return nil, err
 	}
 
+	if err := s.checkBatchSpecExecutionFinished(ctx, batchSpec, opts.AllowFailedWorkspaces); err != nil {
+		return nil, err
+	}
+
 	batchChange, previousSpecID, err := s.ReconcileBatchChange(ctx, batchSpec)
 	if err != nil {
 		return nil, err
@@ -127,6 +149,16 @@ func (s *Service) ApplyBatchChange(ctx context.Context, opts ApplyBatchChangeOpt
 		}
 	}
 
+	// Changesets of batch specs that declare rollout waves are held back by
+	// the reconciler until their wave is released.
+	if batchSpec.Spec.Rollout != nil {
+		if err := tx.EnsureBatchChangeRollout(ctx, batchChange.ID); err != nil {
+			return nil, err
+		}
+	} else if err := tx.DeleteBatchChangeRollout(ctx, batchChange.ID); err != nil {
+		return nil, err
+	}
+
 	// Now we need to wire up the ChangesetSpecs of the new BatchSpec
 	// correctly with the Changesets so that the reconciler can create/update
 	// them.
@@ -166,6 +198,42 @@ func (s *Service) ApplyBatchChange(ctx context.Context, opts ApplyBatchChangeOpt
 	return batchChange, nil
 }
 
+// checkBatchSpecExecutionFinished returns an error if the given batch spec is
+// executed server-side and its workspaces are still being resolved or
+// executed, if resolving them failed, or if some of them failed and
+// allowFailedWorkspaces is false.
+func (s *Service) checkBatchSpecExecutionFinished(ctx context.Context, batchSpec *btypes.BatchSpec, allowFailedWorkspaces bool) error {
+	job, err := s.store.GetBatchSpecResolutionJob(ctx, store.GetBatchSpecResolutionJobOpts{BatchSpecID: batchSpec.ID})
+	if err != nil {
+		// Batch specs created by src-cli have no resolution job.
+		if err == store.ErrNoResults {
+			return nil
+		}
+		return err
+	}
+
+	switch job.State {
+	case btypes.BatchSpecExecutionStateQueued, btypes.BatchSpecExecutionStateProcessing, btypes.BatchSpecExecutionStateErrored:
+		return ErrBatchSpecExecutionUnfinished
+	case btypes.BatchSpecExecutionStateFailed:
+		return ErrBatchSpecResolutionFailed
+	}
+
+	stats, err := s.store.GetBatchSpecWorkspaceStats(ctx, batchSpec.ID)
+	if err != nil {
+		return err
+	}
+	if !stats.Finished() {
+		return ErrBatchSpecExecutionUnfinished
+	}
+	// Errored workspaces that are counted as such have exhausted their retries.
+	if stats.Errored+stats.Failed > 0 && !allowFailedWorkspaces {
+		return ErrBatchSpecWorkspacesFailed
+	}
+
+	return nil
+}
+
 func (s *Service) ReconcileBatchChange(ctx context.Context, batchSpec *btypes.BatchSpec) (batchChange *btypes.BatchChange, previousSpecID int64, err error) {
 	batchChange, err = s.GetBatchChangeMatchingBatchSpec(ctx, batchSpec)
 	if err != nil {
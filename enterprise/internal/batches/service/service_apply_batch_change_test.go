@@ -7,6 +7,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/reconciler"
@@ -1134,6 +1135,87 @@ func TestServiceApplyBatchChange(t *testing.T) {
 			t.Fatalf("ApplyBatchChange returned unexpected error: %s", err)
 		}
 	})
+
+	t.Run("batch spec with rollout waves", func(t *testing.T) {
+		ct.TruncateTables(t, db, "changeset_events", "changesets", "batch_changes", "batch_specs", "changeset_specs")
+		batchSpec := ct.CreateBatchSpec(t, ctx, store, "rollout", admin.ID)
+		batchSpec.Spec.Rollout = &btypes.BatchSpecRollout{
+			Waves: []btypes.BatchSpecRolloutWave{{Count: 1}},
+		}
+		if err := store.UpdateBatchSpec(ctx, batchSpec); err != nil {
+			t.Fatal(err)
+		}
+
+		batchChange, err := svc.ApplyBatchChange(adminCtx, ApplyBatchChangeOpts{
+			BatchSpecRandID: batchSpec.RandID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := store.GetBatchChangeRollout(ctx, batchChange.ID); err != nil {
+			t.Fatalf("rollout not created: %s", err)
+		}
+
+		// Applying a batch spec without waves ends the rollout.
+		batchSpec2 := ct.CreateBatchSpec(t, ctx, store, "rollout", admin.ID)
+		if _, err := svc.ApplyBatchChange(adminCtx, ApplyBatchChangeOpts{
+			BatchSpecRandID: batchSpec2.RandID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := store.GetBatchChangeRollout(ctx, batchChange.ID); err == nil {
+			t.Fatal("rollout not deleted")
+		}
+	})
+
+	t.Run("batch spec executed server-side", func(t *testing.T) {
+		ct.TruncateTables(t, db, "changeset_events", "changesets", "batch_changes", "batch_specs", "changeset_specs")
+		batchSpec := ct.CreateBatchSpec(t, ctx, store, "server-side", admin.ID)
+
+		job := &btypes.BatchSpecResolutionJob{BatchSpecID: batchSpec.ID}
+		if err := store.CreateBatchSpecResolutionJob(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+		workspace := &btypes.BatchSpecWorkspace{BatchSpecID: batchSpec.ID, RepoID: repos[0].ID, Branch: "refs/heads/main", Commit: "d34db33f"}
+		if err := store.CreateBatchSpecWorkspace(ctx, workspace); err != nil {
+			t.Fatal(err)
+		}
+
+		setState := func(table string, id int64, state btypes.BatchSpecExecutionState) {
+			t.Helper()
+			q := sqlf.Sprintf("UPDATE "+table+" SET state = %s WHERE id = %s", state, id)
+			if err := store.Exec(ctx, q); err != nil {
+				t.Fatal(err)
+			}
+		}
+		apply := func(allowFailedWorkspaces bool) error {
+			_, err := svc.ApplyBatchChange(adminCtx, ApplyBatchChangeOpts{
+				BatchSpecRandID:       batchSpec.RandID,
+				AllowFailedWorkspaces: allowFailedWorkspaces,
+			})
+			return err
+		}
+
+		setState("batch_spec_resolution_jobs", job.ID, btypes.BatchSpecExecutionStateFailed)
+		if err := apply(true); err != ErrBatchSpecResolutionFailed {
+			t.Fatalf("wrong error. want=%s, have=%v", ErrBatchSpecResolutionFailed, err)
+		}
+
+		setState("batch_spec_resolution_jobs", job.ID, btypes.BatchSpecExecutionStateCompleted)
+		if err := apply(true); err != ErrBatchSpecExecutionUnfinished {
+			t.Fatalf("wrong error. want=%s, have=%v", ErrBatchSpecExecutionUnfinished, err)
+		}
+
+		setState("batch_spec_workspaces", workspace.ID, btypes.BatchSpecExecutionStateFailed)
+		if err := apply(false); err != ErrBatchSpecWorkspacesFailed {
+			t.Fatalf("wrong error. want=%s, have=%v", ErrBatchSpecWorkspacesFailed, err)
+		}
+		if err := apply(true); err != nil {
+			t.Fatalf("applying with failed workspaces allowed: %s", err)
+		}
+	})
 }
 
 func applyAndListChangesets(ctx context.Context, t *testing.T, svc *Service, batchSpecRandID string, wantChangesets int) (*btypes.BatchChange, btypes.Changesets) {
@@ -166,6 +166,21 @@ func TestServicePermissionLevels(t *testing.T) {
 				_, err := svc.CreateChangesetJobs(currentUserCtx, batchChange.ID, []int64{changeset.ID}, btypes.ChangesetJobTypeComment, btypes.ChangesetJobCommentPayload{Message: "test"}, store.ListChangesetsOpts{})
 				tc.assertFunc(t, err)
 			})
+
+			t.Run("SetAutoMergePolicy", func(t *testing.T) {
+				_, err := svc.SetAutoMergePolicy(currentUserCtx, batchChange.ID, &btypes.AutoMergePolicy{Squash: true})
+				tc.assertFunc(t, err)
+			})
+
+			t.Run("PauseRollout", func(t *testing.T) {
+				_, err := svc.PauseRollout(currentUserCtx, batchChange.ID)
+				tc.assertFunc(t, err)
+			})
+
+			t.Run("ResumeRollout", func(t *testing.T) {
+				_, err := svc.ResumeRollout(currentUserCtx, batchChange.ID, true)
+				tc.assertFunc(t, err)
+			})
 		})
 	}
 }
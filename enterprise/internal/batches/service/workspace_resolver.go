@@ -0,0 +1,425 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gobwas/glob"
+	"github.com/graph-gophers/graphql-go"
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// RepoWorkspace is a directory in a repository revision in which the steps of
+// a batch spec are executed.
+type RepoWorkspace struct {
+	Repo *types.Repo
+
+	// Branch is the full name of the ref that the changes are proposed to,
+	// for example refs/heads/main.
+	Branch string
+	Commit api.CommitID
+
+	// Path is the path of the workspace relative to the repository root, or
+	// empty for the repository root.
+	Path               string
+	OnlyFetchWorkspace bool
+}
+
+// WorkspaceResolver resolves the `on` and `workspaces` of a batch spec into
+// the workspaces the batch spec is executed in.
+type WorkspaceResolver interface {
+	ResolveWorkspacesForBatchSpec(ctx context.Context, spec *btypes.BatchSpec) ([]*RepoWorkspace, error)
+}
+
+// NewWorkspaceResolver returns a WorkspaceResolver that runs the searches of
+// batch specs against the internal GraphQL API of the frontend.
+func NewWorkspaceResolver(s *store.Store) WorkspaceResolver {
+	return &workspaceResolver{store: s, frontendInternalURL: api.InternalClient.URL}
+}
+
+type workspaceResolver struct {
+	store               *store.Store
+	frontendInternalURL string
+}
+
+// ErrUnsupportedStepFeature is returned by ExecuteBatchSpec and
+// ResolveWorkspacesForBatchSpec if a step of the batch spec uses a feature
+// that only src-cli supports.
+var ErrUnsupportedStepFeature = errors.New("steps with files, if or outputs can't be executed server-side yet")
+
+// checkStepsExecutableServerSide returns ErrUnsupportedStepFeature if a step of
+// the given batch spec can't be executed server-side.
+func checkStepsExecutableServerSide(spec *btypes.BatchSpec) error {
+	for _, step := range spec.Spec.Steps {
+		if len(step.Files) > 0 || step.If != nil || len(step.Outputs) > 0 {
+			return ErrUnsupportedStepFeature
+		}
+	}
+	return nil
+}
+
+func (r *workspaceResolver) ResolveWorkspacesForBatchSpec(ctx context.Context, spec *btypes.BatchSpec) ([]*RepoWorkspace, error) {
+	if err := checkStepsExecutableServerSide(spec); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: The searches run with the permissions of the internal
+	// actor, so the matched repositories are loaded with the permissions of
+	// the user who created the batch spec. Repositories the user can't
+	// access are dropped.
+	userCtx := actor.WithActor(ctx, actor.FromUser(spec.UserID))
+
+	revisions, onDefaultBranch, err := r.resolveRepositories(ctx, userCtx, spec.Spec.On)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like src-cli, each workspace configuration is resolved with a single
+	// search for its rootAtLocationOf file in the default branches of all
+	// repositories. Revisions on other branches are searched one by one.
+	dirsByConf := make(map[int]map[api.RepoID][]string)
+
+	var workspaces []*RepoWorkspace
+	for _, rev := range revisions {
+		i, err := matchingWorkspaceConfiguration(rev.Repo, spec.Spec.Workspaces)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolving workspaces in %s", rev.Repo.Name)
+		}
+		if i < 0 {
+			workspaces = append(workspaces, rev)
+			continue
+		}
+		conf := spec.Spec.Workspaces[i]
+
+		dirs, ok := dirsByConf[i]
+		if !onDefaultBranch[rev] {
+			scope := fmt.Sprintf("repo:^%s$@%s", regexp.QuoteMeta(string(rev.Repo.Name)), rev.Commit)
+			if dirs, err = r.searchWorkspaceDirs(ctx, scope, conf); err != nil {
+				return nil, errors.Wrapf(err, "resolving workspaces in %s", rev.Repo.Name)
+			}
+		} else if !ok {
+			scope := fmt.Sprintf("repo:contains.file(%s)", rootAtLocationOfPattern(conf))
+			if dirs, err = r.searchWorkspaceDirs(ctx, scope, conf); err != nil {
+				return nil, err
+			}
+			dirsByConf[i] = dirs
+		}
+
+		for _, dir := range dirs[rev.Repo.ID] {
+			workspaces = append(workspaces, &RepoWorkspace{
+				Repo:               rev.Repo,
+				Branch:             rev.Branch,
+				Commit:             rev.Commit,
+				Path:               dir,
+				OnlyFetchWorkspace: conf.OnlyFetchWorkspace,
+			})
+		}
+	}
+
+	return workspaces, nil
+}
+
+// resolveRepositories resolves the `on` of a batch spec into the repository
+// revisions it targets. Each repository and branch is only returned once,
+// in the order it was first matched. The returned set holds the revisions on
+// the default branch of their repository.
+func (r *workspaceResolver) resolveRepositories(ctx, userCtx context.Context, on []btypes.BatchSpecOn) ([]*RepoWorkspace, map[*RepoWorkspace]bool, error) {
+	var (
+		revisions       []*RepoWorkspace
+		onDefaultBranch = make(map[*RepoWorkspace]bool)
+		seen            = make(map[string]struct{})
+	)
+	add := func(repo *types.Repo, branch string) error {
+		key := fmt.Sprintf("%d:%s", repo.ID, branch)
+		if _, ok := seen[key]; ok {
+			return nil
+		}
+		seen[key] = struct{}{}
+
+		rev, err := resolveRevision(ctx, repo, branch)
+		if err != nil {
+			return err
+		}
+		// Repositories that are empty or still cloning have no revision to
+		// execute the steps on.
+		if rev != nil {
+			revisions = append(revisions, rev)
+			onDefaultBranch[rev] = branch == ""
+		}
+		return nil
+	}
+
+	for _, o := range on {
+		if o.Repository != "" {
+			repo, err := r.store.Repos().GetByName(userCtx, api.RepoName(o.Repository))
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "loading repository %q", o.Repository)
+			}
+			if err := add(repo, o.Branch); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		matches, err := r.search(ctx, o.RepositoriesMatchingQuery)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "searching for %q", o.RepositoriesMatchingQuery)
+		}
+
+		ids := make([]api.RepoID, 0, len(matches))
+		for _, m := range matches {
+			ids = append(ids, m.repoID)
+		}
+		repos, err := r.store.Repos().GetReposSetByIDs(userCtx, ids...)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "loading matched repositories")
+		}
+
+		for _, id := range ids {
+			repo, ok := repos[id]
+			if !ok {
+				continue
+			}
+			// Like src-cli, we skip repositories on code hosts that batch
+			// changes can't create changesets on.
+			if !btypes.IsRepoSupported(&repo.ExternalRepo) {
+				continue
+			}
+			if err := add(repo, ""); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return revisions, onDefaultBranch, nil
+}
+
+// resolveRevision resolves the given branch, or the default branch if it's
+// empty, to a commit. It returns nil if the repository has no default branch
+// yet.
+func resolveRevision(ctx context.Context, repo *types.Repo, branch string) (*RepoWorkspace, error) {
+	ref := "refs/heads/" + strings.TrimPrefix(branch, "refs/heads/")
+	if branch == "" {
+		stdout, _, exitCode, err := git.ExecSafe(ctx, repo.Name, []string{"symbolic-ref", "HEAD"})
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolving default branch of %s", repo.Name)
+		}
+		if exitCode != 0 {
+			return nil, nil
+		}
+		ref = string(bytes.TrimSpace(stdout))
+	}
+
+	commit, err := git.ResolveRevision(ctx, repo.Name, ref, git.ResolveRevisionOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving %s in %s", ref, repo.Name)
+	}
+
+	return &RepoWorkspace{Repo: repo, Branch: ref, Commit: commit}, nil
+}
+
+// matchingWorkspaceConfiguration returns the index of the workspace
+// configuration that applies to the given repository, or -1 if none applies.
+func matchingWorkspaceConfiguration(repo *types.Repo, configs []btypes.WorkspaceConfiguration) (int, error) {
+	matching := -1
+	for i, conf := range configs {
+		if conf.In != "" {
+			g, err := glob.Compile(conf.In)
+			if err != nil {
+				return -1, errors.Wrapf(err, "invalid workspaces.in pattern %q", conf.In)
+			}
+			if !g.Match(string(repo.Name)) {
+				continue
+			}
+		}
+
+		if matching >= 0 {
+			return -1, errors.New("repository matches multiple workspaces.in patterns")
+		}
+		matching = i
+	}
+
+	return matching, nil
+}
+
+func rootAtLocationOfPattern(conf btypes.WorkspaceConfiguration) string {
+	return "(^|/)" + regexp.QuoteMeta(conf.RootAtLocationOf) + "$"
+}
+
+// searchWorkspaceDirs searches the repositories in the given scope for the
+// rootAtLocationOf file of the given workspace configuration, and returns the
+// directories that contain it by repository.
+func (r *workspaceResolver) searchWorkspaceDirs(ctx context.Context, scope string, conf btypes.WorkspaceConfiguration) (map[api.RepoID][]string, error) {
+	query := fmt.Sprintf("%s type:path file:%s", scope, rootAtLocationOfPattern(conf))
+	matches, err := r.search(ctx, query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "searching for %s", conf.RootAtLocationOf)
+	}
+
+	seen := make(map[api.RepoID]map[string]struct{})
+	dirs := make(map[api.RepoID][]string)
+	for _, m := range matches {
+		if m.path == "" {
+			continue
+		}
+		dir := path.Dir(m.path)
+		if dir == "." {
+			dir = ""
+		}
+
+		if seen[m.repoID] == nil {
+			seen[m.repoID] = make(map[string]struct{})
+		}
+		if _, ok := seen[m.repoID][dir]; ok {
+			continue
+		}
+		seen[m.repoID][dir] = struct{}{}
+		dirs[m.repoID] = append(dirs[m.repoID], dir)
+	}
+
+	for _, ds := range dirs {
+		sort.Strings(ds)
+	}
+
+	return dirs, nil
+}
+
+// searchMatch is a repository matched by a search, and the path of the
+// matched file if it was a file match.
+type searchMatch struct {
+	repoID api.RepoID
+	path   string
+}
+
+const workspaceSearchQuery = `query BatchSpecWorkspaceSearch($query: String!) {
+	search(query: $query, version: V2) {
+		results {
+			results {
+				__typename
+				... on Repository {
+					id
+				}
+				... on FileMatch {
+					file {
+						path
+					}
+					repository {
+						id
+					}
+				}
+				... on CommitSearchResult {
+					commit {
+						repository {
+							id
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+type workspaceSearchResponse struct {
+	Data struct {
+		Search struct {
+			Results struct {
+				Results []struct {
+					Typename string `json:"__typename"`
+					ID       graphql.ID
+					File     struct {
+						Path string
+					}
+					Repository struct {
+						ID graphql.ID
+					}
+					Commit struct {
+						Repository struct {
+							ID graphql.ID
+						}
+					}
+				}
+			}
+		}
+	}
+	Errors []interface{}
+}
+
+// search runs the given search query and returns the matched repositories,
+// in the order they were first matched, and files.
+func (r *workspaceResolver) search(ctx context.Context, query string) ([]searchMatch, error) {
+	if !strings.Contains(query, "count:") {
+		query += " count:999999"
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{
+		"query":     workspaceSearchQuery,
+		"variables": map[string]string{"query": query},
+	}); err != nil {
+		return nil, errors.Wrap(err, "Encode")
+	}
+
+	u, err := url.Parse(r.frontendInternalURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "constructing frontend URL")
+	}
+	u.Path = "/.internal/graphql"
+	u.RawQuery = "BatchSpecWorkspaceSearch"
+
+	resp, err := ctxhttp.Post(ctx, nil, u.String(), "application/json", &buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "Post")
+	}
+	defer resp.Body.Close()
+
+	var res workspaceSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, errors.Wrap(err, "Decode")
+	}
+	if len(res.Errors) > 0 {
+		return nil, errors.Errorf("graphql: errors: %v", res.Errors)
+	}
+
+	var matches []searchMatch
+	for _, result := range res.Data.Search.Results.Results {
+		var (
+			id   graphql.ID
+			path string
+		)
+		switch result.Typename {
+		case "Repository":
+			id = result.ID
+		case "FileMatch":
+			id = result.Repository.ID
+			path = result.File.Path
+		case "CommitSearchResult":
+			id = result.Commit.Repository.ID
+		default:
+			continue
+		}
+
+		repoID, err := graphqlbackend.UnmarshalRepositoryID(id)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, searchMatch{repoID: repoID, path: path})
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestWorkspaceResolver_ResolveWorkspacesForBatchSpec(t *testing.T) {
+	repos := map[api.RepoID]*types.Repo{
+		1: {ID: 1, Name: "github.com/sourcegraph/automation-testing", ExternalRepo: api.ExternalRepoSpec{ServiceType: extsvc.TypeGitHub}},
+		2: {ID: 2, Name: "github.com/sourcegraph/monorepo", ExternalRepo: api.ExternalRepoSpec{ServiceType: extsvc.TypeGitHub}},
+		3: {ID: 3, Name: "gitolite.example.com/unsupported", ExternalRepo: api.ExternalRepoSpec{ServiceType: extsvc.TypeGitolite}},
+	}
+
+	database.Mocks.Repos.GetByIDs = func(ctx context.Context, ids ...api.RepoID) ([]*types.Repo, error) {
+		rs := make([]*types.Repo, 0, len(ids))
+		for _, id := range ids {
+			if r, ok := repos[id]; ok {
+				rs = append(rs, r)
+			}
+		}
+		return rs, nil
+	}
+	database.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		for _, r := range repos {
+			if r.Name == name {
+				return r, nil
+			}
+		}
+		return nil, &database.RepoNotFoundErr{Name: name}
+	}
+	git.Mocks.ExecSafe = func(params []string) ([]byte, []byte, int, error) {
+		return []byte("refs/heads/main\n"), nil, 0, nil
+	}
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		return api.CommitID("commit-" + strings.TrimPrefix(spec, "refs/heads/")), nil
+	}
+	t.Cleanup(func() {
+		database.Mocks = database.MockStores{}
+		git.ResetMocks()
+	})
+
+	// searchResults maps search queries to the results returned by the
+	// search API.
+	searchResults := map[string][]map[string]interface{}{
+		"file:README.md count:999999": {
+			repositoryResult(1),
+			repositoryResult(2),
+			repositoryResult(3),
+			fileMatchResult(1, "README.md"),
+		},
+		"repo:contains.file((^|/)package\\.json$) type:path file:(^|/)package\\.json$ count:999999": {
+			fileMatchResult(1, "package.json"),
+			fileMatchResult(2, "package.json"),
+			fileMatchResult(2, "projects/b/package.json"),
+			fileMatchResult(2, "projects/a/package.json"),
+		},
+		"repo:^github\\.com/sourcegraph/monorepo$@commit-feature type:path file:(^|/)package\\.json$ count:999999": {
+			fileMatchResult(2, "projects/c/package.json"),
+		},
+	}
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				Query string
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		queries = append(queries, req.Variables.Query)
+
+		results, ok := searchResults[req.Variables.Query]
+		if !ok {
+			t.Errorf("unexpected search query %q", req.Variables.Query)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"search": map[string]interface{}{
+					"results": map[string]interface{}{"results": results},
+				},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	resolver := &workspaceResolver{store: store.New(&dbtesting.MockDB{}, nil), frontendInternalURL: srv.URL}
+
+	resolve := func(t *testing.T, rawSpec string) []*RepoWorkspace {
+		t.Helper()
+		queries = nil
+
+		spec, err := btypes.NewBatchSpecFromRaw(rawSpec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		spec.UserID = 1
+
+		ws, err := resolver.ResolveWorkspacesForBatchSpec(context.Background(), spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ws
+	}
+
+	t.Run("repositories and search", func(t *testing.T) {
+		have := resolve(t, `
+name: test
+on:
+  - repositoriesMatchingQuery: file:README.md
+  - repository: github.com/sourcegraph/automation-testing
+    branch: feature
+`)
+		want := []*RepoWorkspace{
+			{Repo: repos[1], Branch: "refs/heads/main", Commit: "commit-main"},
+			{Repo: repos[2], Branch: "refs/heads/main", Commit: "commit-main"},
+			{Repo: repos[1], Branch: "refs/heads/feature", Commit: "commit-feature"},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("wrong workspaces (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("monorepo workspaces", func(t *testing.T) {
+		have := resolve(t, `
+name: test
+on:
+  - repositoriesMatchingQuery: file:README.md
+workspaces:
+  - rootAtLocationOf: package.json
+    in: github.com/sourcegraph/mono*
+    onlyFetchWorkspace: true
+`)
+		want := []*RepoWorkspace{
+			{Repo: repos[1], Branch: "refs/heads/main", Commit: "commit-main"},
+			{Repo: repos[2], Branch: "refs/heads/main", Commit: "commit-main", Path: "", OnlyFetchWorkspace: true},
+			{Repo: repos[2], Branch: "refs/heads/main", Commit: "commit-main", Path: "projects/a", OnlyFetchWorkspace: true},
+			{Repo: repos[2], Branch: "refs/heads/main", Commit: "commit-main", Path: "projects/b", OnlyFetchWorkspace: true},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("wrong workspaces (-want +got):\n%s", diff)
+		}
+		if len(queries) != 2 {
+			t.Errorf("wrong number of searches: %q", queries)
+		}
+	})
+
+	t.Run("monorepo workspaces on branch", func(t *testing.T) {
+		have := resolve(t, `
+name: test
+on:
+  - repositoriesMatchingQuery: file:README.md
+  - repository: github.com/sourcegraph/monorepo
+    branch: feature
+workspaces:
+  - rootAtLocationOf: package.json
+    in: github.com/sourcegraph/mono*
+`)
+		want := []*RepoWorkspace{
+			{Repo: repos[1], Branch: "refs/heads/main", Commit: "commit-main"},
+			{Repo: repos[2], Branch: "refs/heads/main", Commit: "commit-main", Path: ""},
+			{Repo: repos[2], Branch: "refs/heads/main", Commit: "commit-main", Path: "projects/a"},
+			{Repo: repos[2], Branch: "refs/heads/main", Commit: "commit-main", Path: "projects/b"},
+			{Repo: repos[2], Branch: "refs/heads/feature", Commit: "commit-feature", Path: "projects/c"},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Errorf("wrong workspaces (-want +got):\n%s", diff)
+		}
+		if len(queries) != 3 {
+			t.Errorf("wrong number of searches: %q", queries)
+		}
+	})
+
+	t.Run("unsupported step features", func(t *testing.T) {
+		spec, err := btypes.NewBatchSpecFromRaw(`
+name: test
+on:
+  - repository: github.com/sourcegraph/automation-testing
+steps:
+  - run: echo hello
+    container: alpine:3
+    outputs:
+      greeting:
+        value: ${{ step.stdout }}
+`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := resolver.ResolveWorkspacesForBatchSpec(context.Background(), spec); err != ErrUnsupportedStepFeature {
+			t.Fatalf("wrong error. want=%s, have=%v", ErrUnsupportedStepFeature, err)
+		}
+	})
+}
+
+func repositoryResult(id api.RepoID) map[string]interface{} {
+	return map[string]interface{}{
+		"__typename": "Repository",
+		"id":         graphqlbackend.MarshalRepositoryID(id),
+	}
+}
+
+func fileMatchResult(id api.RepoID, path string) map[string]interface{} {
+	return map[string]interface{}{
+		"__typename": "FileMatch",
+		"file":       map[string]interface{}{"path": path},
+		"repository": map[string]graphql.ID{"id": graphqlbackend.MarshalRepositoryID(id)},
+	}
+}
@@ -0,0 +1,257 @@
+package sources
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type BitbucketCloudSource struct {
+	client *bitbucketcloud.Client
+	au     auth.Authenticator
+}
+
+// NewBitbucketCloudSource returns a new BitbucketCloudSource from the given external service.
+func NewBitbucketCloudSource(svc *types.ExternalService, cf *httpcli.Factory) (*BitbucketCloudSource, error) {
+	var c schema.BitbucketCloudConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newBitbucketCloudSource(&c, cf)
+}
+
+func newBitbucketCloudSource(c *schema.BitbucketCloudConnection, cf *httpcli.Factory) (*BitbucketCloudSource, error) {
+	apiURL := c.ApiURL
+	if apiURL == "" {
+		apiURL = "https://api.bitbucket.org"
+	}
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	u = extsvc.NormalizeBaseURL(u)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	client := bitbucketcloud.NewClient(u, cli).WithCredentials(c.Username, c.AppPassword)
+
+	return &BitbucketCloudSource{
+		client: client,
+		au:     &auth.BasicAuth{Username: c.Username, Password: c.AppPassword},
+	}, nil
+}
+
+func (s BitbucketCloudSource) GitserverPushConfig(ctx context.Context, store *database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return gitserverPushConfig(ctx, store, repo, s.au)
+}
+
+// WithAuthenticator returns a copy of the source that uses the given
+// authenticator. Bitbucket Cloud only supports username and app password
+// credentials.
+func (s BitbucketCloudSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	var username, password string
+	switch av := a.(type) {
+	case *auth.BasicAuth:
+		username, password = av.Username, av.Password
+	case *auth.BasicAuthWithSSH:
+		username, password = av.Username, av.Password
+	default:
+		return nil, newUnsupportedAuthenticatorError("BitbucketCloudSource", a)
+	}
+
+	return &BitbucketCloudSource{
+		client: s.client.WithCredentials(username, password),
+		au:     a,
+	}, nil
+}
+
+func (s BitbucketCloudSource) ValidateAuthenticator(ctx context.Context) error {
+	_, err := s.client.CurrentUser(ctx)
+	return err
+}
+
+// LoadChangeset loads the latest state of the given Changeset from the codehost.
+func (s BitbucketCloudSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*bitbucketcloud.Repo)
+	number, err := strconv.ParseInt(cs.ExternalID, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "converting external ID")
+	}
+
+	pr, err := s.client.GetPullRequest(ctx, repo, number)
+	if err != nil {
+		if bitbucketcloud.IsNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrap(err, "getting pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, pr, cs)
+}
+
+// CreateChangeset creates the given *Changeset in the code host.
+func (s BitbucketCloudSource) CreateChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	repo := cs.Repo.Metadata.(*bitbucketcloud.Repo)
+
+	pr, err := s.client.CreatePullRequest(ctx, repo, s.changesetToPullRequestInput(cs))
+	if err != nil {
+		return false, errors.Wrap(err, "creating pull request")
+	}
+
+	if err := s.setChangesetMetadata(ctx, repo, pr, cs); err != nil {
+		return false, err
+	}
+
+	// Bitbucket Cloud silently updates an existing pull request for the same
+	// branches instead of returning an error, so we have no way of telling
+	// whether the pull request existed before. Reporting that it did means the
+	// caller will follow up with an update, which is harmless if it didn't.
+	return true, nil
+}
+
+// CloseChangeset declines the given *Changeset on the code host and updates
+// the Metadata column in the *batches.Changeset to the declined pull request.
+func (s BitbucketCloudSource) CloseChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*bitbucketcloud.Repo)
+	pr, ok := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	updated, err := s.client.DeclinePullRequest(ctx, repo, pr.ID)
+	if err != nil {
+		return errors.Wrap(err, "declining pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, cs)
+}
+
+// UpdateChangeset updates the title, body and base branch of the pull request.
+func (s BitbucketCloudSource) UpdateChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*bitbucketcloud.Repo)
+	pr, ok := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	updated, err := s.client.UpdatePullRequest(ctx, repo, pr.ID, s.changesetToPullRequestInput(cs))
+	if err != nil {
+		return errors.Wrap(err, "updating pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, cs)
+}
+
+// ReopenChangeset reopens the *Changeset on the code host and updates the
+// Metadata column in the *batches.Changeset.
+//
+// Declined pull requests cannot be reopened on Bitbucket Cloud, so a new pull
+// request is opened for the same branches instead, and the changeset is
+// pointed at it.
+func (s BitbucketCloudSource) ReopenChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*bitbucketcloud.Repo)
+	pr, ok := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	if pr.State == bitbucketcloud.PullRequestStateOpen {
+		return nil
+	}
+
+	reopened, err := s.client.CreatePullRequest(ctx, repo, bitbucketcloud.PullRequestInput{
+		Title:             pr.Title,
+		Description:       pr.Description,
+		SourceBranch:      pr.Source.Branch.Name,
+		DestinationBranch: pr.Destination.Branch.Name,
+	})
+	if err != nil {
+		return errors.Wrap(err, "reopening pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, reopened, cs)
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s BitbucketCloudSource) CreateComment(ctx context.Context, cs *Changeset, text string) error {
+	repo := cs.Repo.Metadata.(*bitbucketcloud.Repo)
+	pr, ok := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	return s.client.CreatePullRequestComment(ctx, repo, pr.ID, bitbucketcloud.CommentInput{
+		Content: text,
+	})
+}
+
+// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
+// If squash is true, the pull request is squash merged.
+func (s BitbucketCloudSource) MergeChangeset(ctx context.Context, cs *Changeset, squash bool) error {
+	repo := cs.Repo.Metadata.(*bitbucketcloud.Repo)
+	pr, ok := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Bitbucket Cloud pull request")
+	}
+
+	var opts bitbucketcloud.MergePullRequestOpts
+	if squash {
+		strategy := bitbucketcloud.MergeStrategySquash
+		opts.MergeStrategy = &strategy
+	}
+
+	updated, err := s.client.MergePullRequest(ctx, repo, pr.ID, opts)
+	if err != nil {
+		if bitbucketcloud.IsNotMergeable(err) {
+			return &ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "merging pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, cs)
+}
+
+func (s BitbucketCloudSource) changesetToPullRequestInput(cs *Changeset) bitbucketcloud.PullRequestInput {
+	return bitbucketcloud.PullRequestInput{
+		Title:             cs.Title,
+		Description:       cs.Body,
+		SourceBranch:      git.AbbreviateRef(cs.HeadRef),
+		DestinationBranch: git.AbbreviateRef(cs.BaseRef),
+	}
+}
+
+// setChangesetMetadata loads the commit statuses of the pull request, which
+// aren't part of the pull request payload, and sets the changeset metadata.
+func (s BitbucketCloudSource) setChangesetMetadata(ctx context.Context, repo *bitbucketcloud.Repo, pr *bitbucketcloud.PullRequest, cs *Changeset) error {
+	statuses, err := s.client.GetPullRequestStatuses(ctx, repo, pr.ID)
+	if err != nil {
+		return errors.Wrap(err, "getting pull request statuses")
+	}
+	pr.Statuses = statuses
+
+	if err := cs.SetMetadata(pr); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return nil
+}
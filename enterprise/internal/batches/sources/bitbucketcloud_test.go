@@ -0,0 +1,220 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestBitbucketCloudSource_ChangesetSource(t *testing.T) {
+	ctx := context.Background()
+	repo := &types.Repo{Metadata: &bitbucketcloud.Repo{FullName: "sglocal/mux"}}
+
+	newChangeset := func() *Changeset {
+		return &Changeset{
+			Title:     "Title",
+			Body:      "Body",
+			HeadRef:   "refs/heads/feature",
+			BaseRef:   "refs/heads/main",
+			Repo:      repo,
+			Changeset: &btypes.Changeset{},
+		}
+	}
+
+	// newSource returns a source backed by a fake Bitbucket Cloud API that
+	// answers with the given pull request state for every pull request
+	// endpoint, and records the requests it received.
+	newSource := func(t *testing.T, state bitbucketcloud.PullRequestState, id int64) (*BitbucketCloudSource, *[]string) {
+		var requests []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			if r.URL.Path == fmt.Sprintf("/2.0/repositories/sglocal/mux/pullrequests/%d/statuses", id) {
+				fmt.Fprint(w, `{"values":[{"key":"ci","state":"SUCCESSFUL"}]}`)
+				return
+			}
+
+			json.NewEncoder(w).Encode(&bitbucketcloud.PullRequest{
+				ID:          id,
+				Title:       "Title",
+				State:       state,
+				Source:      bitbucketcloud.PullRequestEndpoint{Branch: bitbucketcloud.Branch{Name: "feature"}},
+				Destination: bitbucketcloud.PullRequestEndpoint{Branch: bitbucketcloud.Branch{Name: "main"}},
+			})
+		}))
+		t.Cleanup(srv.Close)
+
+		src, err := newBitbucketCloudSource(&schema.BitbucketCloudConnection{
+			ApiURL:      srv.URL,
+			Username:    "user",
+			AppPassword: "app-password",
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return src, &requests
+	}
+
+	assertRequests := func(t *testing.T, have *[]string, want ...string) {
+		t.Helper()
+		if len(*have) != len(want) {
+			t.Fatalf("unexpected requests: have %v; want %v", *have, want)
+		}
+		for i := range want {
+			if (*have)[i] != want[i] {
+				t.Errorf("unexpected request %d: have %q; want %q", i, (*have)[i], want[i])
+			}
+		}
+	}
+
+	t.Run("CreateChangeset", func(t *testing.T) {
+		src, requests := newSource(t, bitbucketcloud.PullRequestStateOpen, 1)
+		cs := newChangeset()
+
+		exists, err := src.CreateChangeset(ctx, cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			t.Error("expected the changeset to be reported as possibly existing")
+		}
+
+		assertRequests(t, requests,
+			"POST /2.0/repositories/sglocal/mux/pullrequests",
+			"GET /2.0/repositories/sglocal/mux/pullrequests/1/statuses",
+		)
+
+		pr := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest)
+		if cs.ExternalID != "1" || len(pr.Statuses) != 1 {
+			t.Errorf("unexpected changeset metadata: %+v", cs.Changeset)
+		}
+	})
+
+	t.Run("CloseChangeset", func(t *testing.T) {
+		src, requests := newSource(t, bitbucketcloud.PullRequestStateDeclined, 1)
+		cs := newChangeset()
+		cs.Changeset.Metadata = &bitbucketcloud.PullRequest{ID: 1, State: bitbucketcloud.PullRequestStateOpen}
+
+		if err := src.CloseChangeset(ctx, cs); err != nil {
+			t.Fatal(err)
+		}
+
+		assertRequests(t, requests,
+			"POST /2.0/repositories/sglocal/mux/pullrequests/1/decline",
+			"GET /2.0/repositories/sglocal/mux/pullrequests/1/statuses",
+		)
+
+		if have := cs.Changeset.Metadata.(*bitbucketcloud.PullRequest).State; have != bitbucketcloud.PullRequestStateDeclined {
+			t.Errorf("unexpected state: %q", have)
+		}
+	})
+
+	t.Run("ReopenChangeset", func(t *testing.T) {
+		src, requests := newSource(t, bitbucketcloud.PullRequestStateOpen, 2)
+		cs := newChangeset()
+		if err := cs.Changeset.SetMetadata(&bitbucketcloud.PullRequest{
+			ID:          1,
+			State:       bitbucketcloud.PullRequestStateDeclined,
+			Source:      bitbucketcloud.PullRequestEndpoint{Branch: bitbucketcloud.Branch{Name: "feature"}},
+			Destination: bitbucketcloud.PullRequestEndpoint{Branch: bitbucketcloud.Branch{Name: "main"}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := src.ReopenChangeset(ctx, cs); err != nil {
+			t.Fatal(err)
+		}
+
+		assertRequests(t, requests,
+			"POST /2.0/repositories/sglocal/mux/pullrequests",
+			"GET /2.0/repositories/sglocal/mux/pullrequests/2/statuses",
+		)
+
+		if cs.ExternalID != "2" {
+			t.Errorf("expected changeset to point at the new pull request, have external ID %q", cs.ExternalID)
+		}
+	})
+
+	t.Run("LoadChangeset not found", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		t.Cleanup(srv.Close)
+
+		src, err := newBitbucketCloudSource(&schema.BitbucketCloudConnection{ApiURL: srv.URL}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cs := newChangeset()
+		cs.ExternalID = "1"
+
+		err = src.LoadChangeset(ctx, cs)
+		if !errors.HasType(err, ChangesetNotFoundError{}) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestBitbucketCloudSource_WithAuthenticator(t *testing.T) {
+	t.Run("supported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"BasicAuth":        &auth.BasicAuth{},
+			"BasicAuthWithSSH": &auth.BasicAuthWithSSH{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				var src ChangesetSource
+				src, err := newBitbucketCloudSource(&schema.BitbucketCloudConnection{}, nil)
+				if err != nil {
+					t.Errorf("unexpected non-nil error: %v", err)
+				}
+				src, err = src.WithAuthenticator(tc)
+				if err != nil {
+					t.Errorf("unexpected non-nil error: %v", err)
+				}
+
+				if bs, ok := src.(*BitbucketCloudSource); !ok {
+					t.Error("cannot coerce Source into BitbucketCloudSource")
+				} else if bs == nil {
+					t.Error("unexpected nil Source")
+				}
+			})
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"nil":              nil,
+			"OAuthBearerToken": &auth.OAuthBearerToken{},
+			"OAuthClient":      &auth.OAuthClient{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				var src ChangesetSource
+				src, err := newBitbucketCloudSource(&schema.BitbucketCloudConnection{}, nil)
+				if err != nil {
+					t.Errorf("unexpected non-nil error: %v", err)
+				}
+				src, err = src.WithAuthenticator(tc)
+				if err == nil {
+					t.Error("unexpected nil error")
+				} else if !errors.HasType(err, UnsupportedAuthenticatorError{}) {
+					t.Errorf("unexpected error of type %T: %v", err, err)
+				}
+				if src != nil {
+					t.Errorf("expected nil Source: %v", src)
+				}
+			})
+		}
+	})
+}
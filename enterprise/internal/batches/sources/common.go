@@ -37,6 +37,14 @@ type DraftChangesetSource interface {
 	UndraftChangeset(context.Context, *Changeset) error
 }
 
+// A ReviewerChangesetSource can request reviews of changesets.
+type ReviewerChangesetSource interface {
+	// RequestReviewers requests reviews of the Changeset from the given
+	// owners, in CODEOWNERS syntax. Owners that don't exist on the code host
+	// or can't be requested as reviewers are skipped.
+	RequestReviewers(ctx context.Context, c *Changeset, owners []string) error
+}
+
 // A ChangesetSource can load the latest state of a list of Changesets.
 type ChangesetSource interface {
 	// GitserverPushConfig returns an authenticated push config used for pushing
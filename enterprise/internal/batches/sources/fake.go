@@ -51,6 +51,7 @@ type FakeChangesetSource struct {
 	AuthenticatedUsernameCalled bool
 	ValidateAuthenticatorCalled bool
 	MergeChangesetCalled        bool
+	RequestReviewersCalled      bool
 
 	// The Changeset.HeadRef to be expected in CreateChangeset/UpdateChangeset calls.
 	WantHeadRef string
@@ -93,10 +94,15 @@ type FakeChangesetSource struct {
 
 	// Username is the username returned by AuthenticatedUsername
 	Username string
+
+	// RequestedReviewers contains the owners that were passed to
+	// RequestReviewers
+	RequestedReviewers []string
 }
 
 var _ ChangesetSource = &FakeChangesetSource{}
 var _ DraftChangesetSource = &FakeChangesetSource{}
+var _ ReviewerChangesetSource = &FakeChangesetSource{}
 
 func (s *FakeChangesetSource) CreateDraftChangeset(ctx context.Context, c *Changeset) (bool, error) {
 	s.CreateDraftChangesetCalled = true
@@ -283,3 +289,14 @@ func (s *FakeChangesetSource) MergeChangeset(ctx context.Context, c *Changeset,
 	s.MergeChangesetCalled = true
 	return s.Err
 }
+
+func (s *FakeChangesetSource) RequestReviewers(ctx context.Context, c *Changeset, owners []string) error {
+	s.RequestReviewersCalled = true
+
+	if s.Err != nil {
+		return s.Err
+	}
+
+	s.RequestedReviewers = append(s.RequestedReviewers, owners...)
+	return nil
+}
@@ -0,0 +1,265 @@
+package sources
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// giteaReviewsPerPage is the number of pull request reviews requested per page.
+const giteaReviewsPerPage = 50
+
+type GiteaSource struct {
+	client *gitea.Client
+	au     auth.Authenticator
+}
+
+// NewGiteaSource returns a new GiteaSource from the given external service.
+func NewGiteaSource(svc *types.ExternalService, cf *httpcli.Factory) (*GiteaSource, error) {
+	var c schema.GiteaConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGiteaSource(&c, cf)
+}
+
+func newGiteaSource(c *schema.GiteaConnection, cf *httpcli.Factory) (*GiteaSource, error) {
+	u, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	u = extsvc.NormalizeBaseURL(u)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var au auth.Authenticator
+	if c.Token != "" {
+		au = &auth.OAuthBearerToken{Token: c.Token}
+	}
+
+	return &GiteaSource{
+		client: gitea.NewClient(u, cli).WithToken(c.Token),
+		au:     au,
+	}, nil
+}
+
+func (s GiteaSource) GitserverPushConfig(ctx context.Context, store *database.ExternalServiceStore, repo *types.Repo) (*protocol.PushConfig, error) {
+	return gitserverPushConfig(ctx, store, repo, s.au)
+}
+
+// WithAuthenticator returns a copy of the source that uses the given
+// authenticator. Gitea only supports access token credentials.
+func (s GiteaSource) WithAuthenticator(a auth.Authenticator) (ChangesetSource, error) {
+	var token string
+	switch av := a.(type) {
+	case *auth.OAuthBearerToken:
+		token = av.Token
+	case *auth.OAuthBearerTokenWithSSH:
+		token = av.Token
+	default:
+		return nil, newUnsupportedAuthenticatorError("GiteaSource", a)
+	}
+
+	return &GiteaSource{
+		client: s.client.WithToken(token),
+		au:     a,
+	}, nil
+}
+
+func (s GiteaSource) ValidateAuthenticator(ctx context.Context) error {
+	_, err := s.client.CurrentUser(ctx)
+	return err
+}
+
+// LoadChangeset loads the latest state of the given Changeset from the codehost.
+func (s GiteaSource) LoadChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*gitea.Repository)
+	number, err := strconv.ParseInt(cs.ExternalID, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "converting external ID")
+	}
+
+	pr, err := s.client.GetPullRequest(ctx, repo, number)
+	if err != nil {
+		if gitea.IsNotFound(err) {
+			return ChangesetNotFoundError{Changeset: cs}
+		}
+		return errors.Wrap(err, "getting pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, pr, cs)
+}
+
+// CreateChangeset creates the given *Changeset in the code host.
+func (s GiteaSource) CreateChangeset(ctx context.Context, cs *Changeset) (bool, error) {
+	repo := cs.Repo.Metadata.(*gitea.Repository)
+	input := gitea.CreatePullRequestInput{
+		Title: cs.Title,
+		Body:  cs.Body,
+		Head:  git.AbbreviateRef(cs.HeadRef),
+		Base:  git.AbbreviateRef(cs.BaseRef),
+	}
+
+	exists := false
+	pr, err := s.client.CreatePullRequest(ctx, repo, input)
+	if err != nil {
+		if !gitea.IsAlreadyExists(err) {
+			return false, errors.Wrap(err, "creating pull request")
+		}
+
+		exists = true
+		pr, err = s.client.FindOpenPullRequest(ctx, repo, input.Head, input.Base)
+		if err != nil {
+			return exists, errors.Wrap(err, "fetching existing pull request")
+		}
+	}
+
+	if err := s.setChangesetMetadata(ctx, repo, pr, cs); err != nil {
+		return exists, err
+	}
+
+	return exists, nil
+}
+
+// CloseChangeset closes the given *Changeset on the code host and updates the
+// Metadata column in the *batches.Changeset to the closed pull request.
+func (s GiteaSource) CloseChangeset(ctx context.Context, cs *Changeset) error {
+	return s.setPullRequestState(ctx, cs, gitea.PullRequestStateClosed)
+}
+
+// UpdateChangeset updates the title, body and base branch of the pull request.
+func (s GiteaSource) UpdateChangeset(ctx context.Context, cs *Changeset) error {
+	repo := cs.Repo.Metadata.(*gitea.Repository)
+	pr, ok := cs.Changeset.Metadata.(*gitea.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Gitea pull request")
+	}
+
+	updated, err := s.client.EditPullRequest(ctx, repo, pr.Number, gitea.EditPullRequestInput{
+		Title: cs.Title,
+		Body:  &cs.Body,
+		Base:  git.AbbreviateRef(cs.BaseRef),
+	})
+	if err != nil {
+		return errors.Wrap(err, "updating pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, cs)
+}
+
+// ReopenChangeset reopens the *Changeset on the code host and updates the
+// Metadata column in the *batches.Changeset.
+func (s GiteaSource) ReopenChangeset(ctx context.Context, cs *Changeset) error {
+	return s.setPullRequestState(ctx, cs, gitea.PullRequestStateOpen)
+}
+
+// CreateComment posts a comment on the Changeset.
+func (s GiteaSource) CreateComment(ctx context.Context, cs *Changeset, text string) error {
+	repo := cs.Repo.Metadata.(*gitea.Repository)
+	pr, ok := cs.Changeset.Metadata.(*gitea.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Gitea pull request")
+	}
+
+	return s.client.CreatePullRequestComment(ctx, repo, pr.Number, text)
+}
+
+// MergeChangeset merges a Changeset on the code host, if in a mergeable state.
+// If squash is true, the pull request is squash merged.
+func (s GiteaSource) MergeChangeset(ctx context.Context, cs *Changeset, squash bool) error {
+	repo := cs.Repo.Metadata.(*gitea.Repository)
+	pr, ok := cs.Changeset.Metadata.(*gitea.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Gitea pull request")
+	}
+
+	style := gitea.MergeStyleMerge
+	if squash {
+		style = gitea.MergeStyleSquash
+	}
+
+	if err := s.client.MergePullRequest(ctx, repo, pr.Number, style); err != nil {
+		if gitea.IsNotMergeable(err) {
+			return &ChangesetNotMergeableError{ErrorMsg: err.Error()}
+		}
+		return errors.Wrap(err, "merging pull request")
+	}
+
+	// The merge endpoint doesn't return the pull request.
+	merged, err := s.client.GetPullRequest(ctx, repo, pr.Number)
+	if err != nil {
+		return errors.Wrap(err, "getting merged pull request")
+	}
+
+	return s.setChangesetMetadata(ctx, repo, merged, cs)
+}
+
+func (s GiteaSource) setPullRequestState(ctx context.Context, cs *Changeset, state gitea.PullRequestState) error {
+	repo := cs.Repo.Metadata.(*gitea.Repository)
+	pr, ok := cs.Changeset.Metadata.(*gitea.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a Gitea pull request")
+	}
+
+	if pr.State == state {
+		return nil
+	}
+
+	updated, err := s.client.EditPullRequest(ctx, repo, pr.Number, gitea.EditPullRequestInput{State: &state})
+	if err != nil {
+		return errors.Wrapf(err, "setting pull request state to %q", state)
+	}
+
+	return s.setChangesetMetadata(ctx, repo, updated, cs)
+}
+
+// setChangesetMetadata loads the combined commit status of the pull request's
+// head and the reviews of the pull request, which aren't part of the pull
+// request payload, and sets the changeset metadata.
+func (s GiteaSource) setChangesetMetadata(ctx context.Context, repo *gitea.Repository, pr *gitea.PullRequest, cs *Changeset) error {
+	if pr.Head.SHA != "" {
+		status, err := s.client.GetCombinedStatus(ctx, repo, pr.Head.SHA)
+		if err != nil {
+			return errors.Wrap(err, "getting commit status")
+		}
+		pr.CombinedStatus = status
+	}
+
+	pr.Reviews = nil
+	for args := (gitea.PageArgs{Page: 1, Limit: giteaReviewsPerPage}); ; args.Page++ {
+		reviews, nextPage, err := s.client.ListPullRequestReviews(ctx, repo, pr.Number, args)
+		if err != nil {
+			return errors.Wrap(err, "listing reviews")
+		}
+		pr.Reviews = append(pr.Reviews, reviews...)
+		if !nextPage {
+			break
+		}
+	}
+
+	if err := cs.SetMetadata(pr); err != nil {
+		return errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return nil
+}
@@ -0,0 +1,294 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// The test fixtures in testdata/sources/GiteaSource_*.yaml are hand-written,
+// modelled on the responses of the Gitea v1 API, and weren't recorded against a
+// live instance. To replace them with real recordings, point GITEA_URL and
+// GITEA_TOKEN at an instance with a sourcegraph/automation-testing repository and
+// run the tests with -update.
+
+func TestGiteaSource_LoadChangeset(t *testing.T) {
+	testCases := []struct {
+		name string
+		cs   *Changeset
+		err  string
+	}{
+		{
+			name: "found",
+			cs:   &Changeset{Repo: giteaTestRepo, Changeset: &btypes.Changeset{ExternalID: "1"}},
+			err:  "<nil>",
+		},
+		{
+			name: "not-found",
+			cs:   &Changeset{Repo: giteaTestRepo, Changeset: &btypes.Changeset{ExternalID: "999"}},
+			err:  "Changeset with external ID 999 not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		tc.name = "GiteaSource_LoadChangeset_" + tc.name
+
+		t.Run(tc.name, func(t *testing.T) {
+			src, save := newGiteaTestSource(t, tc.name)
+			defer save(t)
+
+			err := src.LoadChangeset(context.Background(), tc.cs)
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Fatalf("error:\nhave: %q\nwant: %q", have, want)
+			}
+			if err != nil {
+				return
+			}
+
+			testutil.AssertGolden(t, "testdata/golden/"+tc.name, update(tc.name), tc.cs.Changeset.Metadata.(*gitea.PullRequest))
+		})
+	}
+}
+
+func TestGiteaSource_CreateChangeset(t *testing.T) {
+	testCases := []struct {
+		name    string
+		headRef string
+		exists  bool
+	}{
+		{name: "success", headRef: "refs/heads/test-pr-gitea-5"},
+		// CreateChangeset is idempotent, so if the pull request already
+		// exists it is not an error.
+		{name: "already-exists", headRef: "refs/heads/always-open-pr", exists: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		tc.name = "GiteaSource_CreateChangeset_" + tc.name
+
+		t.Run(tc.name, func(t *testing.T) {
+			src, save := newGiteaTestSource(t, tc.name)
+			defer save(t)
+
+			cs := &Changeset{
+				Title:     "This is a test PR",
+				Body:      "This is the body of a test PR",
+				BaseRef:   "refs/heads/main",
+				HeadRef:   tc.headRef,
+				Repo:      giteaTestRepo,
+				Changeset: &btypes.Changeset{},
+			}
+
+			exists, err := src.CreateChangeset(context.Background(), cs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exists != tc.exists {
+				t.Errorf("exists:\nhave: %t\nwant: %t", exists, tc.exists)
+			}
+			if have, want := cs.ExternalBranch, tc.headRef; have != want {
+				t.Errorf("external branch:\nhave: %q\nwant: %q", have, want)
+			}
+
+			testutil.AssertGolden(t, "testdata/golden/"+tc.name, update(tc.name), cs.Changeset.Metadata.(*gitea.PullRequest))
+		})
+	}
+}
+
+func TestGiteaSource_CloseChangeset(t *testing.T) {
+	name := "GiteaSource_CloseChangeset_success"
+	src, save := newGiteaTestSource(t, name)
+	defer save(t)
+
+	cs := newGiteaTestChangeset(2, gitea.PullRequestStateOpen)
+	if err := src.CloseChangeset(context.Background(), cs); err != nil {
+		t.Fatal(err)
+	}
+
+	pr := cs.Changeset.Metadata.(*gitea.PullRequest)
+	if pr.State != gitea.PullRequestStateClosed {
+		t.Errorf("unexpected state: %q", pr.State)
+	}
+	testutil.AssertGolden(t, "testdata/golden/"+name, update(name), pr)
+}
+
+func TestGiteaSource_ReopenChangeset(t *testing.T) {
+	name := "GiteaSource_ReopenChangeset_success"
+	src, save := newGiteaTestSource(t, name)
+	defer save(t)
+
+	cs := newGiteaTestChangeset(3, gitea.PullRequestStateClosed)
+	if err := src.ReopenChangeset(context.Background(), cs); err != nil {
+		t.Fatal(err)
+	}
+
+	pr := cs.Changeset.Metadata.(*gitea.PullRequest)
+	if pr.State != gitea.PullRequestStateOpen {
+		t.Errorf("unexpected state: %q", pr.State)
+	}
+	testutil.AssertGolden(t, "testdata/golden/"+name, update(name), pr)
+}
+
+func TestGiteaSource_UpdateChangeset(t *testing.T) {
+	name := "GiteaSource_UpdateChangeset_success"
+	src, save := newGiteaTestSource(t, name)
+	defer save(t)
+
+	cs := newGiteaTestChangeset(4, gitea.PullRequestStateOpen)
+	cs.Title = "This is a new title"
+	cs.Body = "This is a new body"
+	cs.BaseRef = "refs/heads/main"
+
+	if err := src.UpdateChangeset(context.Background(), cs); err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.AssertGolden(t, "testdata/golden/"+name, update(name), cs.Changeset.Metadata.(*gitea.PullRequest))
+}
+
+func TestGiteaSource_CreateComment(t *testing.T) {
+	name := "GiteaSource_CreateComment_success"
+	src, save := newGiteaTestSource(t, name)
+	defer save(t)
+
+	cs := newGiteaTestChangeset(4, gitea.PullRequestStateOpen)
+	if err := src.CreateComment(context.Background(), cs, "test-comment"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGiteaSource_MergeChangeset(t *testing.T) {
+	testCases := []struct {
+		name   string
+		number int64
+		squash bool
+		err    string
+	}{
+		{name: "merged", number: 7, squash: true, err: "<nil>"},
+		{name: "not-mergeable", number: 8, err: "changeset cannot be merged:\n"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		tc.name = "GiteaSource_MergeChangeset_" + tc.name
+
+		t.Run(tc.name, func(t *testing.T) {
+			src, save := newGiteaTestSource(t, tc.name)
+			defer save(t)
+
+			cs := newGiteaTestChangeset(tc.number, gitea.PullRequestStateOpen)
+			err := src.MergeChangeset(context.Background(), cs, tc.squash)
+			if have := fmt.Sprint(err); len(have) < len(tc.err) || have[:len(tc.err)] != tc.err {
+				t.Fatalf("error:\nhave: %q\nwant prefix: %q", have, tc.err)
+			}
+			if err != nil {
+				return
+			}
+
+			pr := cs.Changeset.Metadata.(*gitea.PullRequest)
+			if !pr.Merged {
+				t.Error("expected pull request to be merged")
+			}
+		})
+	}
+}
+
+func TestGiteaSource_WithAuthenticator(t *testing.T) {
+	src, err := newGiteaSource(&schema.GiteaConnection{Url: "https://gitea.sgdev.org"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"OAuthBearerToken":        &auth.OAuthBearerToken{Token: "abc"},
+			"OAuthBearerTokenWithSSH": &auth.OAuthBearerTokenWithSSH{OAuthBearerToken: auth.OAuthBearerToken{Token: "abc"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				newSrc, err := src.WithAuthenticator(tc)
+				if err != nil {
+					t.Fatalf("unexpected non-nil error: %v", err)
+				}
+				if gs, ok := newSrc.(*GiteaSource); !ok {
+					t.Error("cannot coerce Source into GiteaSource")
+				} else if gs.client.Token != "abc" {
+					t.Errorf("unexpected token: %q", gs.client.Token)
+				}
+			})
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, tc := range map[string]auth.Authenticator{
+			"nil":       nil,
+			"BasicAuth": &auth.BasicAuth{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				newSrc, err := src.WithAuthenticator(tc)
+				if err == nil {
+					t.Error("unexpected nil error")
+				} else if !errors.HasType(err, UnsupportedAuthenticatorError{}) {
+					t.Errorf("unexpected error of type %T: %v", err, err)
+				}
+				if newSrc != nil {
+					t.Errorf("unexpected non-nil Source: %v", newSrc)
+				}
+			})
+		}
+	})
+}
+
+var giteaTestRepo = &types.Repo{
+	Metadata: &gitea.Repository{
+		ID:       3,
+		Name:     "automation-testing",
+		FullName: "sourcegraph/automation-testing",
+	},
+}
+
+func newGiteaTestChangeset(number int64, state gitea.PullRequestState) *Changeset {
+	return &Changeset{
+		Repo: giteaTestRepo,
+		Changeset: &btypes.Changeset{
+			ExternalID: fmt.Sprint(number),
+			Metadata:   &gitea.PullRequest{Number: number, State: state},
+		},
+	}
+}
+
+func newGiteaTestSource(t *testing.T, name string) (*GiteaSource, func(testing.TB)) {
+	t.Helper()
+
+	instanceURL := os.Getenv("GITEA_URL")
+	if instanceURL == "" {
+		instanceURL = "https://gitea.sgdev.org"
+	}
+
+	cf, save := newClientFactory(t, name)
+
+	svc := &types.ExternalService{
+		Kind: extsvc.KindGitea,
+		Config: marshalJSON(t, &schema.GiteaConnection{
+			Url:   instanceURL,
+			Token: os.Getenv("GITEA_TOKEN"),
+		}),
+	}
+
+	src, err := NewGiteaSource(svc, cf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src, save
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -268,3 +269,50 @@ func (s GithubSource) MergeChangeset(ctx context.Context, c *Changeset, squash b
 
 	return c.Changeset.SetMetadata(pr)
 }
+
+// RequestReviewers requests reviews of the Changeset from the given owners.
+// Owners are either users ("@login") or teams ("@org/team") of the
+// organization owning the repository. Email addresses are skipped, because
+// reviews can't be requested by email.
+func (s GithubSource) RequestReviewers(ctx context.Context, c *Changeset, owners []string) error {
+	pr, ok := c.Changeset.Metadata.(*github.PullRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitHub pull request")
+	}
+	repo := c.Repo.Metadata.(*github.Repository)
+
+	repoOwner, _, err := github.SplitRepositoryNameWithOwner(repo.NameWithOwner)
+	if err != nil {
+		return errors.Wrap(err, "getting owner of repository")
+	}
+
+	users, teams := githubReviewers(owners, repoOwner, pr.Author.Login)
+	if err := s.client.RequestPullRequestReviews(ctx, pr, users, teams); err != nil {
+		return err
+	}
+
+	return c.Changeset.SetMetadata(pr)
+}
+
+// githubReviewers splits the given owners into the logins of users and the
+// slugs of teams, in the form "org/team", that reviews can be requested from.
+// GitHub doesn't allow requesting reviews from the author of a pull request
+// or from teams of other organizations.
+func githubReviewers(owners []string, repoOwner, author string) (users, teams []string) {
+	for _, o := range owners {
+		if !strings.HasPrefix(o, "@") {
+			continue
+		}
+		o = strings.TrimPrefix(o, "@")
+		if i := strings.Index(o, "/"); i >= 0 {
+			if strings.EqualFold(o[:i], repoOwner) {
+				teams = append(teams, o)
+			}
+			continue
+		}
+		if !strings.EqualFold(o, author) {
+			users = append(users, o)
+		}
+	}
+	return users, teams
+}
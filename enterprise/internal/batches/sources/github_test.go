@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
 	"github.com/inconshreveable/log15"
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
@@ -498,3 +499,22 @@ func TestGithubSource_WithAuthenticator(t *testing.T) {
 		}
 	})
 }
+
+func TestGithubReviewers(t *testing.T) {
+	owners := []string{
+		"@sourcegraph/batchers",
+		"@alice",
+		"@other-org/team",
+		"@Author",
+		"bob@example.com",
+		"@SourceGraph/code-intel",
+	}
+
+	users, teams := githubReviewers(owners, "sourcegraph", "author")
+	if diff := cmp.Diff([]string{"alice"}, users); diff != "" {
+		t.Errorf("wrong users (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"sourcegraph/batchers", "SourceGraph/code-intel"}, teams); diff != "" {
+		t.Errorf("wrong teams (-want +got):\n%s", diff)
+	}
+}
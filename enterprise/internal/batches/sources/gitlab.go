@@ -2,8 +2,11 @@ package sources
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 
@@ -447,3 +450,99 @@ func (s *GitLabSource) MergeChangeset(ctx context.Context, c *Changeset, squash
 
 	return c.Changeset.SetMetadata(updated)
 }
+
+// RequestReviewers requests reviews of the Changeset from the given owners.
+// Owners are either users ("@username") or groups ("@group/subgroup"), in
+// which case reviews are requested from the direct members of the group.
+// Email addresses are skipped. Reviewers that have already been requested
+// are kept.
+func (s *GitLabSource) RequestReviewers(ctx context.Context, c *Changeset, owners []string) error {
+	mr, ok := c.Changeset.Metadata.(*gitlab.MergeRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitLab merge request")
+	}
+	project := c.Repo.Metadata.(*gitlab.Project)
+
+	var ids []int32
+	seen := map[int32]bool{mr.Author.ID: true}
+	add := func(id int32) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, r := range mr.Reviewers {
+		add(r.ID)
+	}
+	existing := len(ids)
+
+	for _, o := range owners {
+		if !strings.HasPrefix(o, "@") {
+			continue
+		}
+		userIDs, err := s.ownerUserIDs(ctx, strings.TrimPrefix(o, "@"))
+		if err != nil {
+			return errors.Wrapf(err, "looking up users of %s", o)
+		}
+		for _, id := range userIDs {
+			add(id)
+		}
+	}
+
+	if len(ids) == existing {
+		return nil
+	}
+
+	updated, err := s.client.SetMergeRequestReviewers(ctx, project, mr, ids)
+	if err != nil {
+		return errors.Wrap(err, "setting GitLab merge request reviewers")
+	}
+
+	// These additional API calls can go away once we can use the GraphQL API.
+	if err := s.decorateMergeRequestData(ctx, project, updated); err != nil {
+		return errors.Wrapf(err, "retrieving additional data for merge request %d", updated.IID)
+	}
+
+	return c.Changeset.SetMetadata(updated)
+}
+
+// ownerUserIDs returns the IDs of the users that the given code owner, without
+// the leading "@", refers to. That's either the user with that username, or
+// the direct members of the group with that path.
+func (s *GitLabSource) ownerUserIDs(ctx context.Context, owner string) ([]int32, error) {
+	// Usernames can't contain slashes, but group paths of subgroups do.
+	if !strings.Contains(owner, "/") {
+		users, _, err := s.client.ListUsers(ctx, "users?username="+url.QueryEscape(owner))
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			if strings.EqualFold(u.Username, owner) {
+				return []int32{u.ID}, nil
+			}
+		}
+	}
+
+	var ids []int32
+	next := fmt.Sprintf("groups/%s/members?per_page=100", url.PathEscape(owner))
+	for next != "" {
+		members, nextPageURL, err := s.client.ListMembers(ctx, next)
+		if err != nil {
+			var e gitlab.HTTPError
+			if errors.As(err, &e) && e.Code() == http.StatusNotFound {
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, m := range members {
+			ids = append(ids, m.ID)
+		}
+
+		next = ""
+		if nextPageURL != nil {
+			next = *nextPageURL
+		}
+	}
+	return ids, nil
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/cockroachdb/errors"
@@ -690,6 +691,68 @@ func TestGitLabSource_ChangesetSource(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("RequestReviewers", func(t *testing.T) {
+		t.Run("invalid metadata", func(t *testing.T) {
+			p := newGitLabChangesetSourceTestProvider(t)
+
+			err := p.source.RequestReviewers(p.ctx, &Changeset{
+				Changeset: &btypes.Changeset{Metadata: struct{}{}},
+			}, []string{"@alice"})
+			if err == nil {
+				t.Error("unexpected nil error")
+			}
+		})
+
+		t.Run("no new reviewers", func(t *testing.T) {
+			mr := &gitlab.MergeRequest{
+				IID:       2,
+				Author:    gitlab.User{ID: 1, Username: "author"},
+				Reviewers: []gitlab.User{{ID: 2, Username: "alice"}},
+			}
+
+			p := newGitLabChangesetSourceTestProvider(t)
+			p.changeset.Changeset.Metadata = mr
+			p.mockListUsers(map[string][]*gitlab.User{
+				"author": {{ID: 1, Username: "author"}},
+				"alice":  {{ID: 2, Username: "alice"}},
+			})
+
+			if err := p.source.RequestReviewers(p.ctx, p.changeset, []string{"@author", "@alice", "alice@example.com"}); err != nil {
+				t.Errorf("unexpected error: %+v", err)
+			}
+		})
+
+		t.Run("success", func(t *testing.T) {
+			in := &gitlab.MergeRequest{
+				IID:       2,
+				Author:    gitlab.User{ID: 1, Username: "author"},
+				Reviewers: []gitlab.User{{ID: 2, Username: "alice"}},
+			}
+			out := &gitlab.MergeRequest{IID: 2}
+
+			p := newGitLabChangesetSourceTestProvider(t)
+			p.changeset.Changeset.Metadata = in
+			p.mockListUsers(map[string][]*gitlab.User{
+				"bob": {{ID: 3, Username: "Bob"}},
+			})
+			p.mockListMembers(map[string][]*gitlab.Member{
+				"sourcegraph/batchers": {{ID: 1}, {ID: 3}, {ID: 4}},
+			})
+			p.mockSetMergeRequestReviewers(in, []int32{2, 3, 4}, out, nil)
+			p.mockGetMergeRequestNotes(in.IID, nil, 20, nil)
+			p.mockGetMergeRequestResourceStateEvents(in.IID, nil, 20, nil)
+			p.mockGetMergeRequestPipelines(in.IID, nil, 20, nil)
+
+			owners := []string{"@bob", "@sourcegraph/batchers", "@unknown"}
+			if err := p.source.RequestReviewers(p.ctx, p.changeset, owners); err != nil {
+				t.Errorf("unexpected error: %+v", err)
+			}
+			if p.changeset.Changeset.Metadata != out {
+				t.Errorf("metadata not correctly updated: have %+v; want %+v", p.changeset.Changeset.Metadata, out)
+			}
+		})
+	})
 }
 
 func TestReadNotesUntilSeen(t *testing.T) {
@@ -948,6 +1011,50 @@ func (p *gitLabChangesetSourceTestProvider) mockCreateComment(expected string, m
 	}
 }
 
+// mockListUsers mocks gitlab.ListUsers calls looking up users by username.
+func (p *gitLabChangesetSourceTestProvider) mockListUsers(users map[string][]*gitlab.User) {
+	gitlab.MockListUsers = func(client *gitlab.Client, ctx context.Context, urlStr string) ([]*gitlab.User, *string, error) {
+		u, err := url.Parse(urlStr)
+		if err != nil {
+			p.t.Fatal(err)
+		}
+		if u.Path != "users" {
+			p.t.Errorf("unexpected URL: %s", urlStr)
+		}
+		return users[u.Query().Get("username")], nil, nil
+	}
+}
+
+// mockListMembers mocks gitlab.ListMembers calls listing the members of the
+// groups with the given paths. Unknown groups are not found.
+func (p *gitLabChangesetSourceTestProvider) mockListMembers(members map[string][]*gitlab.Member) {
+	gitlab.MockListMembers = func(client *gitlab.Client, ctx context.Context, urlStr string) ([]*gitlab.Member, *string, error) {
+		u, err := url.Parse(urlStr)
+		if err != nil {
+			p.t.Fatal(err)
+		}
+		group := strings.TrimSuffix(strings.TrimPrefix(u.Path, "groups/"), "/members")
+		m, ok := members[group]
+		if !ok {
+			return nil, nil, gitlab.NewHTTPError(http.StatusNotFound, nil)
+		}
+		return m, nil, nil
+	}
+}
+
+func (p *gitLabChangesetSourceTestProvider) mockSetMergeRequestReviewers(expectedMR *gitlab.MergeRequest, expectedIDs []int32, updated *gitlab.MergeRequest, err error) {
+	gitlab.MockSetMergeRequestReviewers = func(client *gitlab.Client, ctx context.Context, project *gitlab.Project, mr *gitlab.MergeRequest, reviewerIDs []int32) (*gitlab.MergeRequest, error) {
+		p.testCommonParams(ctx, client, project)
+		if expectedMR != mr {
+			p.t.Errorf("unexpected MergeRequest: have %+v; want %+v", mr, expectedMR)
+		}
+		if diff := cmp.Diff(expectedIDs, reviewerIDs); diff != "" {
+			p.t.Errorf("unexpected reviewer IDs (-want +got):\n%s", diff)
+		}
+		return updated, err
+	}
+}
+
 func (p *gitLabChangesetSourceTestProvider) unmock() {
 	gitlab.MockCreateMergeRequest = nil
 	gitlab.MockGetMergeRequest = nil
@@ -957,6 +1064,9 @@ func (p *gitLabChangesetSourceTestProvider) unmock() {
 	gitlab.MockGetOpenMergeRequestByRefs = nil
 	gitlab.MockUpdateMergeRequest = nil
 	gitlab.MockCreateMergeRequestNote = nil
+	gitlab.MockListUsers = nil
+	gitlab.MockListMembers = nil
+	gitlab.MockSetMergeRequestReviewers = nil
 }
 
 // panicDoer provides a httpcli.Doer implementation that panics if any attempt
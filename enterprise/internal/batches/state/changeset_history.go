@@ -67,6 +67,12 @@ var RequiredEventTypesForHistory = []btypes.ChangesetEventKind{
 	btypes.ChangesetEventKindBitbucketServerUnapproved,
 	btypes.ChangesetEventKindBitbucketServerDismissed,
 	btypes.ChangesetEventKindGitLabUnapproved,
+	btypes.ChangesetEventKindBitbucketCloudPullRequestRejected,
+	btypes.ChangesetEventKindBitbucketCloudPullRequestFulfilled,
+	btypes.ChangesetEventKindBitbucketCloudPullRequestApproved,
+	btypes.ChangesetEventKindBitbucketCloudPullRequestChangesRequestCreated,
+	btypes.ChangesetEventKindBitbucketCloudPullRequestUnapproved,
+	btypes.ChangesetEventKindBitbucketCloudPullRequestChangesRequestRemoved,
 }
 
 type changesetStatesAtTime struct {
@@ -121,7 +127,8 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 		switch e.Kind {
 		case btypes.ChangesetEventKindGitHubClosed,
 			btypes.ChangesetEventKindBitbucketServerDeclined,
-			btypes.ChangesetEventKindGitLabClosed:
+			btypes.ChangesetEventKindGitLabClosed,
+			btypes.ChangesetEventKindBitbucketCloudPullRequestRejected:
 			// Merged is a final state. We can ignore everything after.
 			if currentExtState != btypes.ChangesetExternalStateMerged {
 				currentExtState = btypes.ChangesetExternalStateClosed
@@ -130,7 +137,8 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 
 		case btypes.ChangesetEventKindGitHubMerged,
 			btypes.ChangesetEventKindBitbucketServerMerged,
-			btypes.ChangesetEventKindGitLabMerged:
+			btypes.ChangesetEventKindGitLabMerged,
+			btypes.ChangesetEventKindBitbucketCloudPullRequestFulfilled:
 			currentExtState = btypes.ChangesetExternalStateMerged
 			pushStates(et)
 
@@ -175,7 +183,9 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 		case btypes.ChangesetEventKindGitHubReviewed,
 			btypes.ChangesetEventKindBitbucketServerApproved,
 			btypes.ChangesetEventKindBitbucketServerReviewed,
-			btypes.ChangesetEventKindGitLabApproved:
+			btypes.ChangesetEventKindGitLabApproved,
+			btypes.ChangesetEventKindBitbucketCloudPullRequestApproved,
+			btypes.ChangesetEventKindBitbucketCloudPullRequestChangesRequestCreated:
 
 			s, err := e.ReviewState()
 			if err != nil {
@@ -217,7 +227,9 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 
 		case btypes.ChangesetEventKindBitbucketServerUnapproved,
 			btypes.ChangesetEventKindBitbucketServerDismissed,
-			btypes.ChangesetEventKindGitLabUnapproved:
+			btypes.ChangesetEventKindGitLabUnapproved,
+			btypes.ChangesetEventKindBitbucketCloudPullRequestUnapproved,
+			btypes.ChangesetEventKindBitbucketCloudPullRequestChangesRequestRemoved:
 			author := e.ReviewAuthor()
 			// If the user has been deleted, skip their reviews, as they don't count towards the final state anymore.
 			if author == "" {
@@ -16,7 +16,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
@@ -105,6 +107,12 @@ func computeCheckState(c *btypes.Changeset, events ChangesetEvents) btypes.Chang
 
 	case *gitlab.MergeRequest:
 		return computeGitLabCheckState(c.UpdatedAt, m, events)
+
+	case *bitbucketcloud.PullRequest:
+		return computeBitbucketCloudBuildStatus(c.UpdatedAt, m, events)
+
+	case *gitea.PullRequest:
+		return computeGiteaCheckState(m)
 	}
 
 	return btypes.ChangesetCheckStateUnknown
@@ -197,6 +205,94 @@ func parseBitbucketBuildState(s string) btypes.ChangesetCheckState {
 	}
 }
 
+func computeBitbucketCloudBuildStatus(lastSynced time.Time, pr *bitbucketcloud.PullRequest, events []*btypes.ChangesetEvent) btypes.ChangesetCheckState {
+	// Statuses are keyed by their key, since a status is updated in place
+	// when a build progresses. Only statuses for the current head commit of
+	// the pull request are taken into account.
+	stateMap := make(map[string]btypes.ChangesetCheckState)
+
+	isHead := func(commit *bitbucketcloud.Commit) bool {
+		return commit == nil || pr.Source.Commit == nil || pr.Source.Commit.HasHashPrefix(commit.Hash) || commit.HasHashPrefix(pr.Source.Commit.Hash)
+	}
+
+	// States from last sync
+	for _, status := range pr.Statuses {
+		if !isHead(status.Commit) {
+			continue
+		}
+		stateMap[status.Key] = parseBitbucketCloudBuildState(status.State)
+	}
+
+	// Add any events we've received since our last sync
+	for _, e := range events {
+		var status *bitbucketcloud.PullRequestStatus
+		switch m := e.Metadata.(type) {
+		case *bitbucketcloud.RepoCommitStatusCreatedEvent:
+			status = &m.CommitStatus
+		case *bitbucketcloud.RepoCommitStatusUpdatedEvent:
+			status = &m.CommitStatus
+		default:
+			continue
+		}
+
+		if !isHead(status.Commit) || status.UpdatedOn.Before(lastSynced) {
+			continue
+		}
+		stateMap[status.Key] = parseBitbucketCloudBuildState(status.State)
+	}
+
+	states := make([]btypes.ChangesetCheckState, 0, len(stateMap))
+	for _, v := range stateMap {
+		states = append(states, v)
+	}
+
+	return combineCheckStates(states)
+}
+
+// computeGiteaCheckState computes the check state from the combined commit
+// status of the pull request's head commit. Gitea doesn't send webhook events
+// for commit statuses, so only the state from the last sync is taken into
+// account.
+func computeGiteaCheckState(pr *gitea.PullRequest) btypes.ChangesetCheckState {
+	if pr.CombinedStatus == nil || pr.CombinedStatus.SHA != pr.Head.SHA {
+		return btypes.ChangesetCheckStateUnknown
+	}
+
+	states := make([]btypes.ChangesetCheckState, 0, len(pr.CombinedStatus.Statuses))
+	for _, status := range pr.CombinedStatus.Statuses {
+		states = append(states, parseGiteaCommitStatusState(status.Status))
+	}
+
+	return combineCheckStates(states)
+}
+
+func parseGiteaCommitStatusState(s gitea.CommitStatusState) btypes.ChangesetCheckState {
+	switch s {
+	case gitea.CommitStatusError, gitea.CommitStatusFailure:
+		return btypes.ChangesetCheckStateFailed
+	case gitea.CommitStatusPending:
+		return btypes.ChangesetCheckStatePending
+	case gitea.CommitStatusSuccess, gitea.CommitStatusWarning:
+		// Gitea doesn't block merging on warnings.
+		return btypes.ChangesetCheckStatePassed
+	default:
+		return btypes.ChangesetCheckStateUnknown
+	}
+}
+
+func parseBitbucketCloudBuildState(s bitbucketcloud.PullRequestStatusState) btypes.ChangesetCheckState {
+	switch s {
+	case bitbucketcloud.PullRequestStatusStateFailed, bitbucketcloud.PullRequestStatusStateStopped:
+		return btypes.ChangesetCheckStateFailed
+	case bitbucketcloud.PullRequestStatusStateInProgress:
+		return btypes.ChangesetCheckStatePending
+	case bitbucketcloud.PullRequestStatusStateSuccessful:
+		return btypes.ChangesetCheckStatePassed
+	default:
+		return btypes.ChangesetCheckStateUnknown
+	}
+}
+
 func computeGitHubCheckState(lastSynced time.Time, pr *github.PullRequest, events []*btypes.ChangesetEvent) btypes.ChangesetCheckState {
 	// We should only consider the latest commit. This could be from a sync or a webhook that
 	// has occurred later
@@ -450,6 +546,30 @@ func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.Changese
 		default:
 			return "", errors.Errorf("unknown GitLab merge request state: %s", m.State)
 		}
+	case *bitbucketcloud.PullRequest:
+		switch m.State {
+		case bitbucketcloud.PullRequestStateDeclined, bitbucketcloud.PullRequestStateSuperseded:
+			s = btypes.ChangesetExternalStateClosed
+		case bitbucketcloud.PullRequestStateMerged:
+			s = btypes.ChangesetExternalStateMerged
+		case bitbucketcloud.PullRequestStateOpen:
+			s = btypes.ChangesetExternalStateOpen
+		default:
+			return "", errors.Errorf("unknown Bitbucket Cloud pull request state: %s", m.State)
+		}
+	case *gitea.PullRequest:
+		switch m.State {
+		case gitea.PullRequestStateClosed:
+			if m.Merged {
+				s = btypes.ChangesetExternalStateMerged
+			} else {
+				s = btypes.ChangesetExternalStateClosed
+			}
+		case gitea.PullRequestStateOpen:
+			s = btypes.ChangesetExternalStateOpen
+		default:
+			return "", errors.Errorf("unknown Gitea pull request state: %s", m.State)
+		}
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -507,6 +627,46 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 		}
 		return btypes.ChangesetReviewStatePending, nil
 
+	case *bitbucketcloud.PullRequest:
+		for _, p := range m.Participants {
+			switch p.State {
+			case bitbucketcloud.ParticipantStateApproved:
+				states[btypes.ChangesetReviewStateApproved] = true
+			case bitbucketcloud.ParticipantStateChangesRequested:
+				states[btypes.ChangesetReviewStateChangesRequested] = true
+			default:
+				if p.Role == bitbucketcloud.ParticipantRoleReviewer {
+					states[btypes.ChangesetReviewStatePending] = true
+				}
+			}
+		}
+
+	case *gitea.PullRequest:
+		// Only the latest submitted review of each reviewer counts. Comments
+		// don't change a reviewer's verdict, and dismissed reviews are void.
+		latest := map[int64]gitea.PullRequestReviewState{}
+		for _, r := range m.Reviews {
+			if r.User == nil || r.Dismissed {
+				continue
+			}
+			switch r.State {
+			case gitea.PullRequestReviewStateApproved,
+				gitea.PullRequestReviewStateRequestChanges,
+				gitea.PullRequestReviewStateRequestReview:
+				latest[r.User.ID] = r.State
+			}
+		}
+		for _, state := range latest {
+			switch state {
+			case gitea.PullRequestReviewStateApproved:
+				states[btypes.ChangesetReviewStateApproved] = true
+			case gitea.PullRequestReviewStateRequestChanges:
+				states[btypes.ChangesetReviewStateChangesRequested] = true
+			default:
+				states[btypes.ChangesetReviewStatePending] = true
+			}
+		}
+
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -9,7 +9,9 @@ import (
 
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
@@ -276,6 +278,82 @@ func TestComputeBitbucketBuildStatus(t *testing.T) {
 	}
 }
 
+func TestComputeBitbucketCloudBuildStatus(t *testing.T) {
+	t.Parallel()
+
+	now := timeutil.Now()
+	lastSynced := now.Add(-1 * time.Minute)
+	sha := "abcdef1234567890abcdef1234567890abcdef12"
+
+	statusEvent := func(commit, key string, state bitbucketcloud.PullRequestStatusState) *btypes.ChangesetEvent {
+		return &btypes.ChangesetEvent{
+			Kind: btypes.ChangesetEventKindBitbucketCloudRepoCommitStatusUpdated,
+			Metadata: &bitbucketcloud.RepoCommitStatusUpdatedEvent{
+				RepoCommitStatusEvent: bitbucketcloud.RepoCommitStatusEvent{
+					CommitStatus: bitbucketcloud.PullRequestStatus{
+						Key:       key,
+						State:     state,
+						Commit:    &bitbucketcloud.Commit{Hash: commit},
+						UpdatedOn: now,
+					},
+				},
+			},
+		}
+	}
+
+	// Bitbucket Cloud returns abbreviated hashes on pull requests.
+	pr := &bitbucketcloud.PullRequest{
+		Source: bitbucketcloud.PullRequestEndpoint{
+			Commit: &bitbucketcloud.Commit{Hash: sha[:12]},
+		},
+		Statuses: []*bitbucketcloud.PullRequestStatus{
+			{Key: "ctx1", State: bitbucketcloud.PullRequestStatusStateInProgress, Commit: &bitbucketcloud.Commit{Hash: sha}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		events []*btypes.ChangesetEvent
+		want   btypes.ChangesetCheckState
+	}{
+		{
+			name: "synced status only",
+			want: btypes.ChangesetCheckStatePending,
+		},
+		{
+			name: "event updates synced status",
+			events: []*btypes.ChangesetEvent{
+				statusEvent(sha, "ctx1", bitbucketcloud.PullRequestStatusStateSuccessful),
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		{
+			name: "failure on another key",
+			events: []*btypes.ChangesetEvent{
+				statusEvent(sha, "ctx1", bitbucketcloud.PullRequestStatusStateSuccessful),
+				statusEvent(sha, "ctx2", bitbucketcloud.PullRequestStatusStateFailed),
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+		{
+			name: "events for other commits are ignored",
+			events: []*btypes.ChangesetEvent{
+				statusEvent("0123456789", "ctx1", bitbucketcloud.PullRequestStatusStateFailed),
+			},
+			want: btypes.ChangesetCheckStatePending,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			have := computeBitbucketCloudBuildStatus(lastSynced, pr, tc.events)
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf(diff)
+			}
+		})
+	}
+}
+
 func TestComputeGitLabCheckState(t *testing.T) {
 	t.Parallel()
 
@@ -535,6 +613,41 @@ func TestComputeReviewState(t *testing.T) {
 			},
 			want: btypes.ChangesetReviewStateChangesRequested,
 		},
+		{
+			name:      "gitea - no reviews",
+			changeset: giteaChangeset(daysAgo(0), nil),
+			history:   []changesetStatesAtTime{},
+			want:      btypes.ChangesetReviewStatePending,
+		},
+		{
+			name: "gitea - approved",
+			changeset: giteaChangeset(daysAgo(0), []*gitea.PullRequestReview{
+				{User: &gitea.User{ID: 1}, State: gitea.PullRequestReviewStateRequestChanges},
+				{User: &gitea.User{ID: 1}, State: gitea.PullRequestReviewStateComment},
+				{User: &gitea.User{ID: 1}, State: gitea.PullRequestReviewStateApproved},
+				{User: &gitea.User{ID: 2}, State: gitea.PullRequestReviewStatePending},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateApproved,
+		},
+		{
+			name: "gitea - changes requested",
+			changeset: giteaChangeset(daysAgo(0), []*gitea.PullRequestReview{
+				{User: &gitea.User{ID: 1}, State: gitea.PullRequestReviewStateApproved},
+				{User: &gitea.User{ID: 2}, State: gitea.PullRequestReviewStateRequestChanges},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStateChangesRequested,
+		},
+		{
+			name: "gitea - dismissed and requested reviews",
+			changeset: giteaChangeset(daysAgo(0), []*gitea.PullRequestReview{
+				{User: &gitea.User{ID: 1}, State: gitea.PullRequestReviewStateRequestChanges, Dismissed: true},
+				{User: &gitea.User{ID: 2}, State: gitea.PullRequestReviewStateRequestReview},
+			}),
+			history: []changesetStatesAtTime{},
+			want:    btypes.ChangesetReviewStatePending,
+		},
 	}
 
 	for i, tc := range tests {
@@ -865,6 +978,17 @@ func gitLabChangeset(updatedAt time.Time, state gitlab.MergeRequestState, notes
 	}
 }
 
+func giteaChangeset(updatedAt time.Time, reviews []*gitea.PullRequestReview) *btypes.Changeset {
+	return &btypes.Changeset{
+		ExternalServiceType: extsvc.TypeGitea,
+		UpdatedAt:           updatedAt,
+		Metadata: &gitea.PullRequest{
+			State:   gitea.PullRequestStateOpen,
+			Reviews: reviews,
+		},
+	}
+}
+
 func setDeletedAt(c *btypes.Changeset, deletedAt time.Time) *btypes.Changeset {
 	c.ExternalDeletedAt = deletedAt
 	return c
@@ -0,0 +1,215 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// autoMergePolicyColumns are used by the auto-merge policy related Store
+// methods to query and upsert policies.
+var autoMergePolicyColumns = []*sqlf.Query{
+	sqlf.Sprintf("batch_change_auto_merge_policies.batch_change_id"),
+	sqlf.Sprintf("batch_change_auto_merge_policies.user_id"),
+	sqlf.Sprintf("batch_change_auto_merge_policies.required_check_state"),
+	sqlf.Sprintf("batch_change_auto_merge_policies.required_review_state"),
+	sqlf.Sprintf("batch_change_auto_merge_policies.squash"),
+	sqlf.Sprintf("batch_change_auto_merge_policies.merge_window_start"),
+	sqlf.Sprintf("batch_change_auto_merge_policies.merge_window_end"),
+	sqlf.Sprintf("batch_change_auto_merge_policies.merge_window_weekdays"),
+	sqlf.Sprintf("batch_change_auto_merge_policies.created_at"),
+	sqlf.Sprintf("batch_change_auto_merge_policies.updated_at"),
+}
+
+// UpsertAutoMergePolicy creates the given auto-merge policy or replaces the
+// existing policy of its batch change.
+func (s *Store) UpsertAutoMergePolicy(ctx context.Context, p *btypes.AutoMergePolicy) error {
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = s.now()
+	}
+	p.UpdatedAt = s.now()
+
+	return s.query(ctx, upsertAutoMergePolicyQuery(p), func(sc scanner) error {
+		return scanAutoMergePolicy(p, sc)
+	})
+}
+
+var upsertAutoMergePolicyQueryFmtstr = `
+-- source: enterprise/internal/batches/store/auto_merge_policies.go:UpsertAutoMergePolicy
+INSERT INTO batch_change_auto_merge_policies (
+	batch_change_id,
+	user_id,
+	required_check_state,
+	required_review_state,
+	squash,
+	merge_window_start,
+	merge_window_end,
+	merge_window_weekdays,
+	created_at,
+	updated_at
+)
+VALUES
+	(%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (batch_change_id) DO UPDATE SET
+	user_id = EXCLUDED.user_id,
+	required_check_state = EXCLUDED.required_check_state,
+	required_review_state = EXCLUDED.required_review_state,
+	squash = EXCLUDED.squash,
+	merge_window_start = EXCLUDED.merge_window_start,
+	merge_window_end = EXCLUDED.merge_window_end,
+	merge_window_weekdays = EXCLUDED.merge_window_weekdays,
+	updated_at = EXCLUDED.updated_at
+RETURNING
+	%s
+`
+
+func upsertAutoMergePolicyQuery(p *btypes.AutoMergePolicy) *sqlf.Query {
+	var (
+		windowStart, windowEnd *int32
+		weekdays               = []int64{}
+	)
+	if w := p.MergeWindow; w != nil {
+		start, end := int32(w.Start), int32(w.End)
+		windowStart, windowEnd = &start, &end
+		for _, d := range w.Weekdays {
+			weekdays = append(weekdays, int64(d))
+		}
+	}
+
+	return sqlf.Sprintf(
+		upsertAutoMergePolicyQueryFmtstr,
+		p.BatchChangeID,
+		p.UserID,
+		nullStringColumn(string(p.RequiredCheckState)),
+		nullStringColumn(string(p.RequiredReviewState)),
+		p.Squash,
+		windowStart,
+		windowEnd,
+		pq.Array(weekdays),
+		p.CreatedAt,
+		p.UpdatedAt,
+		sqlf.Join(autoMergePolicyColumns, ", "),
+	)
+}
+
+// GetAutoMergePolicy gets the auto-merge policy of the given batch change.
+// ErrNoResults is returned if the batch change has none.
+func (s *Store) GetAutoMergePolicy(ctx context.Context, batchChangeID int64) (*btypes.AutoMergePolicy, error) {
+	q := sqlf.Sprintf(
+		getAutoMergePolicyQueryFmtstr,
+		sqlf.Join(autoMergePolicyColumns, ", "),
+		batchChangeID,
+	)
+
+	var p btypes.AutoMergePolicy
+	err := s.query(ctx, q, func(sc scanner) error {
+		return scanAutoMergePolicy(&p, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if p.BatchChangeID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &p, nil
+}
+
+var getAutoMergePolicyQueryFmtstr = `
+-- source: enterprise/internal/batches/store/auto_merge_policies.go:GetAutoMergePolicy
+SELECT %s FROM batch_change_auto_merge_policies
+WHERE batch_change_id = %s
+`
+
+// ListAutoMergePolicies lists the auto-merge policies of all batch changes
+// that are not closed.
+func (s *Store) ListAutoMergePolicies(ctx context.Context) (ps []*btypes.AutoMergePolicy, err error) {
+	q := sqlf.Sprintf(
+		listAutoMergePoliciesQueryFmtstr,
+		sqlf.Join(autoMergePolicyColumns, ", "),
+	)
+
+	err = s.query(ctx, q, func(sc scanner) error {
+		var p btypes.AutoMergePolicy
+		if err := scanAutoMergePolicy(&p, sc); err != nil {
+			return err
+		}
+		ps = append(ps, &p)
+		return nil
+	})
+
+	return ps, err
+}
+
+var listAutoMergePoliciesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/auto_merge_policies.go:ListAutoMergePolicies
+SELECT %s FROM batch_change_auto_merge_policies
+INNER JOIN batch_changes ON batch_changes.id = batch_change_auto_merge_policies.batch_change_id
+WHERE batch_changes.closed_at IS NULL
+ORDER BY batch_change_auto_merge_policies.batch_change_id ASC
+`
+
+// DeleteAutoMergePolicy deletes the auto-merge policy of the given batch
+// change and clears the blocked reasons recorded on its changesets.
+func (s *Store) DeleteAutoMergePolicy(ctx context.Context, batchChangeID int64) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteAutoMergePolicyQueryFmtstr, batchChangeID))
+}
+
+var deleteAutoMergePolicyQueryFmtstr = `
+-- source: enterprise/internal/batches/store/auto_merge_policies.go:DeleteAutoMergePolicy
+WITH policy AS (
+	DELETE FROM batch_change_auto_merge_policies
+	WHERE batch_change_id = %s
+	RETURNING batch_change_id
+)
+UPDATE changesets
+SET auto_merge_blocked_reason = NULL
+FROM policy
+WHERE changesets.owned_by_batch_change_id = policy.batch_change_id
+`
+
+func scanAutoMergePolicy(p *btypes.AutoMergePolicy, sc scanner) error {
+	var (
+		requiredCheckState, requiredReviewState string
+		windowStart, windowEnd                  sql.NullInt32
+		weekdays                                []int64
+	)
+
+	if err := sc.Scan(
+		&p.BatchChangeID,
+		&p.UserID,
+		&dbutil.NullString{S: &requiredCheckState},
+		&dbutil.NullString{S: &requiredReviewState},
+		&p.Squash,
+		&windowStart,
+		&windowEnd,
+		pq.Array(&weekdays),
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	p.RequiredCheckState = btypes.ChangesetCheckState(requiredCheckState)
+	p.RequiredReviewState = btypes.ChangesetReviewState(requiredReviewState)
+
+	p.MergeWindow = nil
+	if windowStart.Valid && windowEnd.Valid {
+		p.MergeWindow = &btypes.MergeWindow{
+			Start: int(windowStart.Int32),
+			End:   int(windowEnd.Int32),
+		}
+		for _, d := range weekdays {
+			p.MergeWindow.Weekdays = append(p.MergeWindow.Weekdays, time.Weekday(d))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func testStoreAutoMergePolicies(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	repoStore := database.ReposWith(s)
+	esStore := database.ExternalServicesWith(s)
+
+	repo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	if err := repoStore.Create(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+
+	batchChange := ct.CreateBatchChange(t, ctx, s, "auto-merge", 1, 1)
+	closedBatchChange := ct.CreateBatchChange(t, ctx, s, "auto-merge-closed", 1, 1)
+	closedBatchChange.ClosedAt = clock.Now()
+	if err := s.UpdateBatchChange(ctx, closedBatchChange); err != nil {
+		t.Fatal(err)
+	}
+
+	user := ct.CreateTestUser(t, s.DB(), false)
+
+	policy := &btypes.AutoMergePolicy{
+		BatchChangeID:       batchChange.ID,
+		UserID:              user.ID,
+		RequiredCheckState:  btypes.ChangesetCheckStatePassed,
+		RequiredReviewState: btypes.ChangesetReviewStateApproved,
+		Squash:              true,
+		MergeWindow: &btypes.MergeWindow{
+			Start:    22 * 60,
+			End:      2 * 60,
+			Weekdays: []time.Weekday{time.Monday, time.Friday},
+		},
+	}
+
+	t.Run("Upsert", func(t *testing.T) {
+		if err := s.UpsertAutoMergePolicy(ctx, policy); err != nil {
+			t.Fatal(err)
+		}
+		if policy.CreatedAt.IsZero() || policy.UpdatedAt.IsZero() {
+			t.Fatal("timestamps should be set")
+		}
+
+		if err := s.UpsertAutoMergePolicy(ctx, &btypes.AutoMergePolicy{BatchChangeID: closedBatchChange.ID, UserID: user.ID}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		have, err := s.GetAutoMergePolicy(ctx, batchChange.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(have, policy); diff != "" {
+			t.Fatal(diff)
+		}
+
+		if _, err := s.GetAutoMergePolicy(ctx, 0xdeadbeef); err != ErrNoResults {
+			t.Fatalf("have err %v, want %v", err, ErrNoResults)
+		}
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		clock.Add(1 * time.Second)
+
+		policy.RequiredCheckState = ""
+		policy.MergeWindow = nil
+		if err := s.UpsertAutoMergePolicy(ctx, policy); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := s.GetAutoMergePolicy(ctx, batchChange.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(have, policy); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		have, err := s.ListAutoMergePolicies(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Policies of closed batch changes are not listed.
+		if diff := cmp.Diff(have, []*btypes.AutoMergePolicy{policy}); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		changeset := ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
+			Repo:               repo.ID,
+			BatchChange:        batchChange.ID,
+			OwnedByBatchChange: batchChange.ID,
+		})
+
+		reason := "The changeset is a draft."
+		if err := s.UpdateChangesetAutoMergeBlockedReason(ctx, changeset, &reason); err != nil {
+			t.Fatal(err)
+		}
+		if changeset.AutoMergeBlockedReason == nil || *changeset.AutoMergeBlockedReason != reason {
+			t.Fatalf("blocked reason not recorded: %v", changeset.AutoMergeBlockedReason)
+		}
+
+		if err := s.DeleteAutoMergePolicy(ctx, batchChange.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := s.GetAutoMergePolicy(ctx, batchChange.ID); err != ErrNoResults {
+			t.Fatalf("have err %v, want %v", err, ErrNoResults)
+		}
+
+		changeset, err := s.GetChangeset(ctx, GetChangesetOpts{ID: changeset.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changeset.AutoMergeBlockedReason != nil {
+			t.Fatalf("blocked reason not cleared: %q", *changeset.AutoMergeBlockedReason)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package store
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// batchChangeRolloutColumns are used by the rollout related Store methods to
+// query and update rollouts.
+var batchChangeRolloutColumns = []*sqlf.Query{
+	sqlf.Sprintf("batch_change_rollouts.batch_change_id"),
+	sqlf.Sprintf("batch_change_rollouts.current_wave"),
+	sqlf.Sprintf("batch_change_rollouts.paused_at"),
+	sqlf.Sprintf("batch_change_rollouts.pause_reason"),
+	sqlf.Sprintf("batch_change_rollouts.created_at"),
+	sqlf.Sprintf("batch_change_rollouts.updated_at"),
+}
+
+// EnsureBatchChangeRollout creates the rollout of the given batch change,
+// starting at the first wave, unless it already has one.
+func (s *Store) EnsureBatchChangeRollout(ctx context.Context, batchChangeID int64) error {
+	now := s.now()
+	return s.Exec(ctx, sqlf.Sprintf(ensureBatchChangeRolloutQueryFmtstr, batchChangeID, now, now))
+}
+
+var ensureBatchChangeRolloutQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_change_rollouts.go:EnsureBatchChangeRollout
+INSERT INTO batch_change_rollouts (batch_change_id, current_wave, created_at, updated_at)
+VALUES (%s, 0, %s, %s)
+ON CONFLICT (batch_change_id) DO NOTHING
+`
+
+// GetBatchChangeRollout gets the rollout of the given batch change.
+// ErrNoResults is returned if the batch change has none.
+func (s *Store) GetBatchChangeRollout(ctx context.Context, batchChangeID int64) (*btypes.BatchChangeRollout, error) {
+	q := sqlf.Sprintf(
+		getBatchChangeRolloutQueryFmtstr,
+		sqlf.Join(batchChangeRolloutColumns, ", "),
+		batchChangeID,
+	)
+
+	var r btypes.BatchChangeRollout
+	err := s.query(ctx, q, func(sc scanner) error {
+		return scanBatchChangeRollout(&r, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.BatchChangeID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &r, nil
+}
+
+var getBatchChangeRolloutQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_change_rollouts.go:GetBatchChangeRollout
+SELECT %s FROM batch_change_rollouts
+WHERE batch_change_id = %s
+`
+
+// ListBatchChangeRollouts lists the rollouts of all batch changes that are not
+// closed.
+func (s *Store) ListBatchChangeRollouts(ctx context.Context) (rs []*btypes.BatchChangeRollout, err error) {
+	q := sqlf.Sprintf(
+		listBatchChangeRolloutsQueryFmtstr,
+		sqlf.Join(batchChangeRolloutColumns, ", "),
+	)
+
+	err = s.query(ctx, q, func(sc scanner) error {
+		var r btypes.BatchChangeRollout
+		if err := scanBatchChangeRollout(&r, sc); err != nil {
+			return err
+		}
+		rs = append(rs, &r)
+		return nil
+	})
+
+	return rs, err
+}
+
+var listBatchChangeRolloutsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_change_rollouts.go:ListBatchChangeRollouts
+SELECT %s FROM batch_change_rollouts
+INNER JOIN batch_changes ON batch_changes.id = batch_change_rollouts.batch_change_id
+WHERE batch_changes.closed_at IS NULL
+ORDER BY batch_change_rollouts.batch_change_id ASC
+`
+
+// UpdateBatchChangeRollout updates the current wave and the pause state of the
+// given rollout.
+func (s *Store) UpdateBatchChangeRollout(ctx context.Context, r *btypes.BatchChangeRollout) error {
+	r.UpdatedAt = s.now()
+
+	q := sqlf.Sprintf(
+		updateBatchChangeRolloutQueryFmtstr,
+		r.CurrentWave,
+		nullTimeColumn(r.PausedAt),
+		nullStringColumn(r.PauseReason),
+		r.UpdatedAt,
+		r.BatchChangeID,
+		sqlf.Join(batchChangeRolloutColumns, ", "),
+	)
+
+	return s.query(ctx, q, func(sc scanner) error {
+		return scanBatchChangeRollout(r, sc)
+	})
+}
+
+var updateBatchChangeRolloutQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_change_rollouts.go:UpdateBatchChangeRollout
+UPDATE batch_change_rollouts
+SET
+	current_wave = %s,
+	paused_at = %s,
+	pause_reason = %s,
+	updated_at = %s
+WHERE batch_change_id = %s
+RETURNING
+	%s
+`
+
+// DeleteBatchChangeRollout deletes the rollout of the given batch change and
+// clears the rollout waves of its changesets.
+func (s *Store) DeleteBatchChangeRollout(ctx context.Context, batchChangeID int64) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteBatchChangeRolloutQueryFmtstr, batchChangeID))
+}
+
+var deleteBatchChangeRolloutQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_change_rollouts.go:DeleteBatchChangeRollout
+WITH rollout AS (
+	DELETE FROM batch_change_rollouts
+	WHERE batch_change_id = %s
+	RETURNING batch_change_id
+)
+UPDATE changesets
+SET
+	rollout_wave = NULL,
+	rollout_held = FALSE
+FROM rollout
+WHERE changesets.owned_by_batch_change_id = rollout.batch_change_id
+`
+
+// ReleaseHeldRolloutChangesets enqueues the changesets of the given batch
+// change whose publication the reconciler held back, if their rollout wave is
+// at most the given wave.
+func (s *Store) ReleaseHeldRolloutChangesets(ctx context.Context, batchChangeID int64, wave int32, state btypes.ReconcilerState) error {
+	q := sqlf.Sprintf(
+		releaseHeldRolloutChangesetsQueryFmtstr,
+		state.ToDB(),
+		s.now(),
+		batchChangeID,
+		wave,
+		btypes.ReconcilerStateCompleted.ToDB(),
+	)
+
+	return s.Exec(ctx, q)
+}
+
+var releaseHeldRolloutChangesetsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_change_rollouts.go:ReleaseHeldRolloutChangesets
+UPDATE changesets
+SET
+	rollout_held = FALSE,
+	reconciler_state = %s,
+	num_resets = 0,
+	num_failures = 0,
+	failure_message = NULL,
+	updated_at = %s
+WHERE
+	owned_by_batch_change_id = %s AND
+	rollout_held AND
+	rollout_wave <= %s AND
+	reconciler_state = %s
+`
+
+// GetRolloutWaveStats returns the outcomes of the changesets in each rollout
+// wave of the given batch change, ordered by wave. Waves without changesets
+// are omitted.
+func (s *Store) GetRolloutWaveStats(ctx context.Context, batchChangeID int64) (stats []*btypes.RolloutWaveStats, err error) {
+	q := sqlf.Sprintf(getRolloutWaveStatsQueryFmtstr, batchChangeID)
+
+	err = s.query(ctx, q, func(sc scanner) error {
+		var st btypes.RolloutWaveStats
+		if err := sc.Scan(
+			&st.Wave,
+			&st.Changesets,
+			&st.Succeeded,
+			&st.Failed,
+			&st.Pending,
+		); err != nil {
+			return err
+		}
+		stats = append(stats, &st)
+		return nil
+	})
+
+	return stats, err
+}
+
+// Changesets count as succeeded once they're merged or their checks passed.
+// Unpublished changesets that the reconciler completed without holding them
+// back aren't meant to be published and don't count at all.
+var getRolloutWaveStatsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_change_rollouts.go:GetRolloutWaveStats
+WITH outcomes AS (
+	SELECT
+		rollout_wave,
+		CASE
+			WHEN publication_state = 'PUBLISHED' AND (
+				external_state = 'MERGED' OR
+				(external_state IN ('OPEN', 'DRAFT') AND external_check_state = 'PASSED')
+			) THEN 'succeeded'
+			WHEN reconciler_state = 'failed' OR (publication_state = 'PUBLISHED' AND (
+				external_state IN ('CLOSED', 'DELETED', 'READONLY') OR
+				external_check_state = 'FAILED'
+			)) THEN 'failed'
+			WHEN publication_state = 'PUBLISHED' OR rollout_held OR reconciler_state <> 'completed' THEN 'pending'
+		END AS outcome
+	FROM changesets
+	WHERE
+		owned_by_batch_change_id = %s AND
+		rollout_wave IS NOT NULL
+)
+SELECT
+	rollout_wave,
+	COUNT(*),
+	COUNT(*) FILTER (WHERE outcome = 'succeeded'),
+	COUNT(*) FILTER (WHERE outcome = 'failed'),
+	COUNT(*) FILTER (WHERE outcome = 'pending')
+FROM outcomes
+GROUP BY rollout_wave
+ORDER BY rollout_wave ASC
+`
+
+func scanBatchChangeRollout(r *btypes.BatchChangeRollout, sc scanner) error {
+	return sc.Scan(
+		&r.BatchChangeID,
+		&r.CurrentWave,
+		&dbutil.NullTime{Time: &r.PausedAt},
+		&dbutil.NullString{S: &r.PauseReason},
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	)
+}
@@ -0,0 +1,221 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func testStoreBatchChangeRollouts(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	repoStore := database.ReposWith(s)
+	esStore := database.ExternalServicesWith(s)
+
+	repo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	if err := repoStore.Create(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+
+	batchChange := ct.CreateBatchChange(t, ctx, s, "rollout", 1, 1)
+	closedBatchChange := ct.CreateBatchChange(t, ctx, s, "rollout-closed", 1, 1)
+	closedBatchChange.ClosedAt = clock.Now()
+	if err := s.UpdateBatchChange(ctx, closedBatchChange); err != nil {
+		t.Fatal(err)
+	}
+
+	var rollout *btypes.BatchChangeRollout
+
+	t.Run("Ensure", func(t *testing.T) {
+		for _, id := range []int64{batchChange.ID, closedBatchChange.ID} {
+			if err := s.EnsureBatchChangeRollout(ctx, id); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var err error
+		rollout, err = s.GetBatchChangeRollout(ctx, batchChange.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &btypes.BatchChangeRollout{
+			BatchChangeID: batchChange.ID,
+			CreatedAt:     clock.Now(),
+			UpdatedAt:     clock.Now(),
+		}
+		if diff := cmp.Diff(want, rollout); diff != "" {
+			t.Fatal(diff)
+		}
+
+		if _, err := s.GetBatchChangeRollout(ctx, 0xdeadbeef); err != ErrNoResults {
+			t.Fatalf("have err %v, want %v", err, ErrNoResults)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		clock.Add(1 * time.Second)
+
+		rollout.CurrentWave = 1
+		rollout.PausedAt = clock.Now()
+		rollout.PauseReason = "Paused by a user."
+		if err := s.UpdateBatchChangeRollout(ctx, rollout); err != nil {
+			t.Fatal(err)
+		}
+
+		// Ensuring an existing rollout doesn't reset it.
+		if err := s.EnsureBatchChangeRollout(ctx, batchChange.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := s.GetBatchChangeRollout(ctx, batchChange.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(rollout, have); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		have, err := s.ListBatchChangeRollouts(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Rollouts of closed batch changes are not listed.
+		if diff := cmp.Diff([]*btypes.BatchChangeRollout{rollout}, have); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	wave := func(w int32) *int32 { return &w }
+	createChangeset := func(t *testing.T, w *int32, held bool, opts ct.TestChangesetOpts) *btypes.Changeset {
+		opts.Repo = repo.ID
+		opts.BatchChange = batchChange.ID
+		opts.OwnedByBatchChange = batchChange.ID
+		c := ct.CreateChangeset(t, ctx, s, opts)
+
+		c.RolloutWave = w
+		c.RolloutHeld = held
+		if err := s.UpdateChangesetRollout(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	published := ct.TestChangesetOpts{
+		PublicationState: btypes.ChangesetPublicationStatePublished,
+		ReconcilerState:  btypes.ReconcilerStateCompleted,
+		ExternalState:    btypes.ChangesetExternalStateOpen,
+	}
+	withState := func(opts ct.TestChangesetOpts, external btypes.ChangesetExternalState, check btypes.ChangesetCheckState) ct.TestChangesetOpts {
+		opts.ExternalState = external
+		opts.ExternalCheckState = check
+		return opts
+	}
+
+	merged := createChangeset(t, wave(0), false, withState(published, btypes.ChangesetExternalStateMerged, ""))
+	createChangeset(t, wave(0), false, withState(published, btypes.ChangesetExternalStateOpen, btypes.ChangesetCheckStatePassed))
+	createChangeset(t, wave(0), false, withState(published, btypes.ChangesetExternalStateOpen, btypes.ChangesetCheckStateFailed))
+	createChangeset(t, wave(0), false, withState(published, btypes.ChangesetExternalStateOpen, btypes.ChangesetCheckStatePending))
+	createChangeset(t, wave(0), false, ct.TestChangesetOpts{
+		PublicationState: btypes.ChangesetPublicationStateUnpublished,
+		ReconcilerState:  btypes.ReconcilerStateFailed,
+	})
+	// Not meant to be published.
+	createChangeset(t, wave(0), false, ct.TestChangesetOpts{
+		PublicationState: btypes.ChangesetPublicationStateUnpublished,
+		ReconcilerState:  btypes.ReconcilerStateCompleted,
+	})
+	held := createChangeset(t, wave(1), true, ct.TestChangesetOpts{
+		PublicationState: btypes.ChangesetPublicationStateUnpublished,
+		ReconcilerState:  btypes.ReconcilerStateCompleted,
+	})
+	unassigned := createChangeset(t, nil, true, ct.TestChangesetOpts{
+		PublicationState: btypes.ChangesetPublicationStateUnpublished,
+		ReconcilerState:  btypes.ReconcilerStateCompleted,
+	})
+
+	t.Run("UpdateChangesetRollout", func(t *testing.T) {
+		have, err := s.GetChangeset(ctx, GetChangesetOpts{ID: merged.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have.RolloutWave == nil || *have.RolloutWave != 0 || have.RolloutHeld {
+			t.Fatalf("wrong rollout wave %v or held %t", have.RolloutWave, have.RolloutHeld)
+		}
+	})
+
+	t.Run("GetRolloutWaveStats", func(t *testing.T) {
+		have, err := s.GetRolloutWaveStats(ctx, batchChange.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []*btypes.RolloutWaveStats{
+			{Wave: 0, Changesets: 6, Succeeded: 2, Failed: 2, Pending: 1},
+			{Wave: 1, Changesets: 1, Pending: 1},
+		}
+		if diff := cmp.Diff(want, have); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("ReleaseHeldRolloutChangesets", func(t *testing.T) {
+		if err := s.ReleaseHeldRolloutChangesets(ctx, batchChange.ID, 0, btypes.ReconcilerStateQueued); err != nil {
+			t.Fatal(err)
+		}
+		have, err := s.GetChangeset(ctx, GetChangesetOpts{ID: held.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !have.RolloutHeld || have.ReconcilerState != btypes.ReconcilerStateCompleted {
+			t.Fatal("changeset in a later wave released")
+		}
+
+		if err := s.ReleaseHeldRolloutChangesets(ctx, batchChange.ID, 1, btypes.ReconcilerStateQueued); err != nil {
+			t.Fatal(err)
+		}
+		have, err = s.GetChangeset(ctx, GetChangesetOpts{ID: held.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have.RolloutHeld || have.ReconcilerState != btypes.ReconcilerStateQueued {
+			t.Fatalf("changeset not released: held=%t state=%s", have.RolloutHeld, have.ReconcilerState)
+		}
+
+		// Changesets without a wave are only released once they're assigned
+		// to one.
+		have, err = s.GetChangeset(ctx, GetChangesetOpts{ID: unassigned.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !have.RolloutHeld {
+			t.Fatal("unassigned changeset released")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := s.DeleteBatchChangeRollout(ctx, batchChange.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := s.GetBatchChangeRollout(ctx, batchChange.ID); err != ErrNoResults {
+			t.Fatalf("have err %v, want %v", err, ErrNoResults)
+		}
+
+		for _, c := range []*btypes.Changeset{merged, unassigned} {
+			have, err := s.GetChangeset(ctx, GetChangesetOpts{ID: c.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have.RolloutWave != nil || have.RolloutHeld {
+				t.Fatalf("rollout of changeset %d not cleared", c.ID)
+			}
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// BatchSpecResolutionJobColumns are used by the resolution job related Store
+// methods and by the worker store to query resolution jobs.
+var BatchSpecResolutionJobColumns = SQLColumns{
+	"batch_spec_resolution_jobs.id",
+	"batch_spec_resolution_jobs.batch_spec_id",
+	"batch_spec_resolution_jobs.state",
+	"batch_spec_resolution_jobs.failure_message",
+	"batch_spec_resolution_jobs.started_at",
+	"batch_spec_resolution_jobs.finished_at",
+	"batch_spec_resolution_jobs.process_after",
+	"batch_spec_resolution_jobs.num_resets",
+	"batch_spec_resolution_jobs.num_failures",
+	"batch_spec_resolution_jobs.execution_logs",
+	"batch_spec_resolution_jobs.worker_hostname",
+	"batch_spec_resolution_jobs.created_at",
+	"batch_spec_resolution_jobs.updated_at",
+}
+
+// CreateBatchSpecResolutionJob creates the given resolution job, which is
+// queued for processing.
+func (s *Store) CreateBatchSpecResolutionJob(ctx context.Context, j *btypes.BatchSpecResolutionJob) error {
+	if j.CreatedAt.IsZero() {
+		j.CreatedAt = s.now()
+	}
+
+	if j.UpdatedAt.IsZero() {
+		j.UpdatedAt = j.CreatedAt
+	}
+
+	q := sqlf.Sprintf(
+		createBatchSpecResolutionJobQueryFmtstr,
+		j.BatchSpecID,
+		j.CreatedAt,
+		j.UpdatedAt,
+		sqlf.Join(BatchSpecResolutionJobColumns.ToSqlf(), ", "),
+	)
+
+	return s.query(ctx, q, func(sc scanner) error { return scanBatchSpecResolutionJob(j, sc) })
+}
+
+var createBatchSpecResolutionJobQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_resolution_jobs.go:CreateBatchSpecResolutionJob
+INSERT INTO batch_spec_resolution_jobs (batch_spec_id, state, created_at, updated_at)
+VALUES (%s, 'queued', %s, %s)
+RETURNING %s
+`
+
+// GetBatchSpecResolutionJobOpts captures the query options needed for getting
+// a BatchSpecResolutionJob.
+type GetBatchSpecResolutionJobOpts struct {
+	ID          int64
+	BatchSpecID int64
+}
+
+// GetBatchSpecResolutionJob gets a BatchSpecResolutionJob matching the given
+// options.
+func (s *Store) GetBatchSpecResolutionJob(ctx context.Context, opts GetBatchSpecResolutionJobOpts) (*btypes.BatchSpecResolutionJob, error) {
+	var preds []*sqlf.Query
+	if opts.ID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_resolution_jobs.id = %s", opts.ID))
+	}
+	if opts.BatchSpecID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_resolution_jobs.batch_spec_id = %s", opts.BatchSpecID))
+	}
+	if len(preds) == 0 {
+		return nil, errors.New("no predicates given")
+	}
+
+	q := sqlf.Sprintf(
+		getBatchSpecResolutionJobQueryFmtstr,
+		sqlf.Join(BatchSpecResolutionJobColumns.ToSqlf(), ", "),
+		sqlf.Join(preds, "\n AND "),
+	)
+
+	var j btypes.BatchSpecResolutionJob
+	err := s.query(ctx, q, func(sc scanner) error { return scanBatchSpecResolutionJob(&j, sc) })
+	if err != nil {
+		return nil, err
+	}
+
+	if j.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &j, nil
+}
+
+var getBatchSpecResolutionJobQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_resolution_jobs.go:GetBatchSpecResolutionJob
+SELECT %s FROM batch_spec_resolution_jobs
+WHERE %s
+LIMIT 1
+`
+
+func scanBatchSpecResolutionJob(j *btypes.BatchSpecResolutionJob, sc scanner) error {
+	var executionLogs []dbworkerstore.ExecutionLogEntry
+
+	if err := sc.Scan(
+		&j.ID,
+		&j.BatchSpecID,
+		&j.State,
+		&j.FailureMessage,
+		&j.StartedAt,
+		&j.FinishedAt,
+		&j.ProcessAfter,
+		&j.NumResets,
+		&j.NumFailures,
+		pq.Array(&executionLogs),
+		&j.WorkerHostname,
+		&j.CreatedAt,
+		&j.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	for _, entry := range executionLogs {
+		j.ExecutionLogs = append(j.ExecutionLogs, workerutil.ExecutionLogEntry(entry))
+	}
+
+	return nil
+}
+
+// ScanFirstBatchSpecResolutionJob scans a slice of resolution jobs from the
+// rows and returns the first.
+func ScanFirstBatchSpecResolutionJob(rows *sql.Rows, err error) (*btypes.BatchSpecResolutionJob, bool, error) {
+	if err != nil {
+		return nil, false, err
+	}
+
+	var jobs []*btypes.BatchSpecResolutionJob
+	err = scanAll(rows, func(sc scanner) error {
+		var j btypes.BatchSpecResolutionJob
+		if err := scanBatchSpecResolutionJob(&j, sc); err != nil {
+			return err
+		}
+		jobs = append(jobs, &j)
+		return nil
+	})
+	if err != nil || len(jobs) == 0 {
+		return nil, false, err
+	}
+
+	return jobs[0], true, nil
+}
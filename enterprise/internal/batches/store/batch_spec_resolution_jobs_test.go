@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+func testStoreBatchSpecResolutionJobs(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	spec := ct.CreateBatchSpec(t, ctx, s, "resolution", 1)
+
+	job := &btypes.BatchSpecResolutionJob{BatchSpecID: spec.ID}
+
+	t.Run("Create", func(t *testing.T) {
+		if err := s.CreateBatchSpecResolutionJob(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+
+		want := &btypes.BatchSpecResolutionJob{
+			ID:          job.ID,
+			BatchSpecID: spec.ID,
+			State:       btypes.BatchSpecExecutionStateQueued,
+			CreatedAt:   clock.Now(),
+			UpdatedAt:   clock.Now(),
+		}
+		if diff := cmp.Diff(want, job); diff != "" {
+			t.Fatal(diff)
+		}
+
+		// Every batch spec has at most one resolution job.
+		if err := s.CreateBatchSpecResolutionJob(ctx, &btypes.BatchSpecResolutionJob{BatchSpecID: spec.ID}); err == nil {
+			t.Fatal("expected error creating second resolution job, got none")
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		for name, opts := range map[string]GetBatchSpecResolutionJobOpts{
+			"ByID":          {ID: job.ID},
+			"ByBatchSpecID": {BatchSpecID: spec.ID},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, err := s.GetBatchSpecResolutionJob(ctx, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(job, have); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+		}
+
+		t.Run("NoResults", func(t *testing.T) {
+			opts := GetBatchSpecResolutionJobOpts{ID: 0xdeadbeef}
+			if _, err := s.GetBatchSpecResolutionJob(ctx, opts); err != ErrNoResults {
+				t.Fatalf("have err %v, want %v", err, ErrNoResults)
+			}
+		})
+	})
+}
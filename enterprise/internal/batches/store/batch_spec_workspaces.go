@@ -0,0 +1,348 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// batchSpecWorkspaceInsertColumns is the list of batch_spec_workspaces
+// columns that are modified in CreateBatchSpecWorkspace.
+var batchSpecWorkspaceInsertColumns = []string{
+	"batch_spec_id",
+	"repo_id",
+	"branch",
+	"commit",
+	"path",
+	"only_fetch_workspace",
+	"state",
+	"created_at",
+	"updated_at",
+}
+
+// BatchSpecWorkspaceColumns are used by the workspace related Store methods
+// and by the executor queue to query workspaces.
+var BatchSpecWorkspaceColumns = SQLColumns{
+	"batch_spec_workspaces.id",
+	"batch_spec_workspaces.batch_spec_id",
+	"batch_spec_workspaces.repo_id",
+	"batch_spec_workspaces.branch",
+	"batch_spec_workspaces.commit",
+	"batch_spec_workspaces.path",
+	"batch_spec_workspaces.only_fetch_workspace",
+	"batch_spec_workspaces.changeset_spec_ids",
+	"batch_spec_workspaces.state",
+	"batch_spec_workspaces.failure_message",
+	"batch_spec_workspaces.started_at",
+	"batch_spec_workspaces.finished_at",
+	"batch_spec_workspaces.process_after",
+	"batch_spec_workspaces.num_resets",
+	"batch_spec_workspaces.num_failures",
+	"batch_spec_workspaces.execution_logs",
+	"batch_spec_workspaces.worker_hostname",
+	"batch_spec_workspaces.created_at",
+	"batch_spec_workspaces.updated_at",
+}
+
+// CreateBatchSpecWorkspace creates the given workspaces, which are queued for
+// execution.
+func (s *Store) CreateBatchSpecWorkspace(ctx context.Context, ws ...*btypes.BatchSpecWorkspace) error {
+	inserter := func(inserter *batch.Inserter) error {
+		for _, w := range ws {
+			if w.CreatedAt.IsZero() {
+				w.CreatedAt = s.now()
+			}
+
+			if w.UpdatedAt.IsZero() {
+				w.UpdatedAt = w.CreatedAt
+			}
+
+			if err := inserter.Insert(
+				ctx,
+				w.BatchSpecID,
+				w.RepoID,
+				w.Branch,
+				w.Commit,
+				w.Path,
+				w.OnlyFetchWorkspace,
+				btypes.BatchSpecExecutionStateQueued,
+				w.CreatedAt,
+				w.UpdatedAt,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	i := -1
+	return batch.WithInserterWithReturn(
+		ctx,
+		s.Handle().DB(),
+		"batch_spec_workspaces",
+		batchSpecWorkspaceInsertColumns,
+		BatchSpecWorkspaceColumns,
+		func(rows *sql.Rows) error {
+			i++
+			return scanBatchSpecWorkspace(ws[i], rows)
+		},
+		inserter,
+	)
+}
+
+// GetBatchSpecWorkspace gets the BatchSpecWorkspace with the given ID.
+func (s *Store) GetBatchSpecWorkspace(ctx context.Context, id int64) (*btypes.BatchSpecWorkspace, error) {
+	q := sqlf.Sprintf(
+		getBatchSpecWorkspaceQueryFmtstr,
+		sqlf.Join(BatchSpecWorkspaceColumns.ToSqlf(), ", "),
+		id,
+	)
+
+	var w btypes.BatchSpecWorkspace
+	err := s.query(ctx, q, func(sc scanner) error { return scanBatchSpecWorkspace(&w, sc) })
+	if err != nil {
+		return nil, err
+	}
+
+	if w.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &w, nil
+}
+
+var getBatchSpecWorkspaceQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspaces.go:GetBatchSpecWorkspace
+SELECT %s FROM batch_spec_workspaces
+INNER JOIN repo ON repo.id = batch_spec_workspaces.repo_id
+WHERE batch_spec_workspaces.id = %s AND repo.deleted_at IS NULL
+LIMIT 1
+`
+
+// ListBatchSpecWorkspacesOpts captures the query options needed for listing
+// the workspaces of a batch spec.
+type ListBatchSpecWorkspacesOpts struct {
+	LimitOpts
+	Cursor int64
+
+	BatchSpecID int64
+}
+
+// ListBatchSpecWorkspaces lists the workspaces of a batch spec, ordered by ID.
+func (s *Store) ListBatchSpecWorkspaces(ctx context.Context, opts ListBatchSpecWorkspacesOpts) (ws []*btypes.BatchSpecWorkspace, next int64, err error) {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("repo.deleted_at IS NULL"),
+		sqlf.Sprintf("batch_spec_workspaces.id >= %s", opts.Cursor),
+	}
+	if opts.BatchSpecID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_workspaces.batch_spec_id = %s", opts.BatchSpecID))
+	}
+
+	q := sqlf.Sprintf(
+		listBatchSpecWorkspacesQueryFmtstr+opts.LimitOpts.ToDB(),
+		sqlf.Join(BatchSpecWorkspaceColumns.ToSqlf(), ", "),
+		sqlf.Join(preds, "\n AND "),
+	)
+
+	ws = make([]*btypes.BatchSpecWorkspace, 0, opts.DBLimit())
+	err = s.query(ctx, q, func(sc scanner) error {
+		var w btypes.BatchSpecWorkspace
+		if err := scanBatchSpecWorkspace(&w, sc); err != nil {
+			return err
+		}
+		ws = append(ws, &w)
+		return nil
+	})
+
+	if opts.Limit != 0 && len(ws) == opts.DBLimit() {
+		next = ws[len(ws)-1].ID
+		ws = ws[:len(ws)-1]
+	}
+
+	return ws, next, err
+}
+
+var listBatchSpecWorkspacesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspaces.go:ListBatchSpecWorkspaces
+SELECT %s FROM batch_spec_workspaces
+INNER JOIN repo ON repo.id = batch_spec_workspaces.repo_id
+WHERE %s
+ORDER BY batch_spec_workspaces.id ASC
+`
+
+// GetBatchSpecWorkspaceStats counts the workspaces of the given batch spec by
+// state.
+func (s *Store) GetBatchSpecWorkspaceStats(ctx context.Context, batchSpecID int64) (btypes.BatchSpecWorkspaceStats, error) {
+	q := sqlf.Sprintf(
+		getBatchSpecWorkspaceStatsQueryFmtstr,
+		btypes.BatchSpecWorkspaceMaxNumRetries,
+		btypes.BatchSpecWorkspaceMaxNumRetries,
+		batchSpecID,
+	)
+
+	var stats btypes.BatchSpecWorkspaceStats
+	err := s.query(ctx, q, func(sc scanner) error {
+		return sc.Scan(
+			&stats.Total,
+			&stats.Queued,
+			&stats.Processing,
+			&stats.Completed,
+			&stats.Errored,
+			&stats.Failed,
+		)
+	})
+
+	return stats, err
+}
+
+var getBatchSpecWorkspaceStatsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspaces.go:GetBatchSpecWorkspaceStats
+SELECT
+	COUNT(*),
+	COUNT(*) FILTER (WHERE state = 'queued' OR (state = 'errored' AND num_failures < %s)),
+	COUNT(*) FILTER (WHERE state = 'processing'),
+	COUNT(*) FILTER (WHERE state = 'completed'),
+	COUNT(*) FILTER (WHERE state = 'errored' AND num_failures >= %s),
+	COUNT(*) FILTER (WHERE state = 'failed')
+FROM batch_spec_workspaces
+INNER JOIN repo ON repo.id = batch_spec_workspaces.repo_id
+WHERE batch_spec_workspaces.batch_spec_id = %s AND repo.deleted_at IS NULL
+`
+
+// SetBatchSpecWorkspaceChangesetSpecs records the changeset specs that were
+// created from the diff the execution of the workspace produced.
+func (s *Store) SetBatchSpecWorkspaceChangesetSpecs(ctx context.Context, id int64, changesetSpecIDs []int64) error {
+	if changesetSpecIDs == nil {
+		changesetSpecIDs = []int64{}
+	}
+
+	ids, err := json.Marshal(changesetSpecIDs)
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(setBatchSpecWorkspaceChangesetSpecsQueryFmtstr, ids, s.now(), id))
+}
+
+var setBatchSpecWorkspaceChangesetSpecsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspaces.go:SetBatchSpecWorkspaceChangesetSpecs
+UPDATE batch_spec_workspaces
+SET changeset_spec_ids = %s, updated_at = %s
+WHERE id = %s
+`
+
+// RetryBatchSpecWorkspace queues the given workspace for execution again, if
+// its last execution errored or failed. It returns the updated workspace, or
+// ErrNoResults if the workspace can't be retried.
+func (s *Store) RetryBatchSpecWorkspace(ctx context.Context, id int64) (*btypes.BatchSpecWorkspace, error) {
+	q := sqlf.Sprintf(
+		retryBatchSpecWorkspaceQueryFmtstr,
+		s.now(),
+		id,
+		sqlf.Join(BatchSpecWorkspaceColumns.ToSqlf(), ", "),
+	)
+
+	var w btypes.BatchSpecWorkspace
+	err := s.query(ctx, q, func(sc scanner) error { return scanBatchSpecWorkspace(&w, sc) })
+	if err != nil {
+		return nil, err
+	}
+
+	if w.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &w, nil
+}
+
+var retryBatchSpecWorkspaceQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_workspaces.go:RetryBatchSpecWorkspace
+UPDATE batch_spec_workspaces
+SET
+	state = 'queued',
+	failure_message = NULL,
+	started_at = NULL,
+	finished_at = NULL,
+	process_after = NULL,
+	num_resets = 0,
+	num_failures = 0,
+	execution_logs = NULL,
+	worker_hostname = '',
+	changeset_spec_ids = '[]'::jsonb,
+	updated_at = %s
+WHERE id = %s AND state IN ('errored', 'failed')
+RETURNING %s
+`
+
+func scanBatchSpecWorkspace(w *btypes.BatchSpecWorkspace, sc scanner) error {
+	var (
+		changesetSpecIDs json.RawMessage
+		executionLogs    []dbworkerstore.ExecutionLogEntry
+	)
+
+	if err := sc.Scan(
+		&w.ID,
+		&w.BatchSpecID,
+		&w.RepoID,
+		&w.Branch,
+		&w.Commit,
+		&w.Path,
+		&w.OnlyFetchWorkspace,
+		&changesetSpecIDs,
+		&w.State,
+		&w.FailureMessage,
+		&w.StartedAt,
+		&w.FinishedAt,
+		&w.ProcessAfter,
+		&w.NumResets,
+		&w.NumFailures,
+		pq.Array(&executionLogs),
+		&w.WorkerHostname,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(changesetSpecIDs, &w.ChangesetSpecIDs); err != nil {
+		return err
+	}
+
+	for _, entry := range executionLogs {
+		w.ExecutionLogs = append(w.ExecutionLogs, workerutil.ExecutionLogEntry(entry))
+	}
+
+	return nil
+}
+
+// ScanFirstBatchSpecWorkspace scans a slice of workspaces from the rows and
+// returns the first.
+func ScanFirstBatchSpecWorkspace(rows *sql.Rows, err error) (*btypes.BatchSpecWorkspace, bool, error) {
+	if err != nil {
+		return nil, false, err
+	}
+
+	var ws []*btypes.BatchSpecWorkspace
+	err = scanAll(rows, func(sc scanner) error {
+		var w btypes.BatchSpecWorkspace
+		if err := scanBatchSpecWorkspace(&w, sc); err != nil {
+			return err
+		}
+		ws = append(ws, &w)
+		return nil
+	})
+	if err != nil || len(ws) == 0 {
+		return nil, false, err
+	}
+
+	return ws[0], true, nil
+}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func testStoreBatchSpecWorkspaces(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	repoStore := database.ReposWith(s)
+	esStore := database.ExternalServicesWith(s)
+
+	repo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	deletedRepo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	if err := repoStore.Create(ctx, repo, deletedRepo); err != nil {
+		t.Fatal(err)
+	}
+	if err := repoStore.Delete(ctx, deletedRepo.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := ct.CreateBatchSpec(t, ctx, s, "workspaces", 1)
+
+	workspaces := make([]*btypes.BatchSpecWorkspace, 0, 3)
+	for _, path := range []string{"", "a", "b"} {
+		workspaces = append(workspaces, &btypes.BatchSpecWorkspace{
+			BatchSpecID:        spec.ID,
+			RepoID:             repo.ID,
+			Branch:             "refs/heads/main",
+			Commit:             "d34db33f",
+			Path:               path,
+			OnlyFetchWorkspace: path != "",
+		})
+	}
+	deletedRepoWorkspace := &btypes.BatchSpecWorkspace{
+		BatchSpecID: spec.ID,
+		RepoID:      deletedRepo.ID,
+		Branch:      "refs/heads/main",
+		Commit:      "d34db33f",
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		if err := s.CreateBatchSpecWorkspace(ctx, append(workspaces, deletedRepoWorkspace)...); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, ws := range workspaces {
+			if ws.ID == 0 {
+				t.Fatal("ID should not be zero")
+			}
+			if have, want := ws.State, btypes.BatchSpecExecutionStateQueued; have != want {
+				t.Fatalf("wrong state. want=%s, have=%s", want, have)
+			}
+			if have, want := ws.CreatedAt, clock.Now(); !have.Equal(want) {
+				t.Fatalf("wrong created at. want=%s, have=%s", want, have)
+			}
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		for _, ws := range workspaces {
+			have, err := s.GetBatchSpecWorkspace(ctx, ws.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(ws, have); diff != "" {
+				t.Fatal(diff)
+			}
+		}
+
+		if _, err := s.GetBatchSpecWorkspace(ctx, deletedRepoWorkspace.ID); err != ErrNoResults {
+			t.Fatalf("have err %v, want %v", err, ErrNoResults)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		have, next, err := s.ListBatchSpecWorkspaces(ctx, ListBatchSpecWorkspacesOpts{BatchSpecID: spec.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next != 0 {
+			t.Fatalf("have next %d, want 0", next)
+		}
+		if diff := cmp.Diff(workspaces, have); diff != "" {
+			t.Fatal(diff)
+		}
+
+		t.Run("Paginated", func(t *testing.T) {
+			var cursor int64
+			for i := 1; i <= len(workspaces); i++ {
+				opts := ListBatchSpecWorkspacesOpts{
+					BatchSpecID: spec.ID,
+					Cursor:      cursor,
+					LimitOpts:   LimitOpts{Limit: 1},
+				}
+				have, next, err := s.ListBatchSpecWorkspaces(ctx, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(workspaces[i-1:i], have); diff != "" {
+					t.Fatalf("page %d: %s", i, diff)
+				}
+				cursor = next
+			}
+			if cursor != 0 {
+				t.Fatalf("have cursor %d after last page, want 0", cursor)
+			}
+		})
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		setState := func(ws *btypes.BatchSpecWorkspace, state btypes.BatchSpecExecutionState, numFailures int64) {
+			t.Helper()
+			q := sqlf.Sprintf("UPDATE batch_spec_workspaces SET state = %s, num_failures = %s WHERE id = %s", state, numFailures, ws.ID)
+			if err := s.Exec(ctx, q); err != nil {
+				t.Fatal(err)
+			}
+			ws.State = state
+			ws.NumFailures = numFailures
+		}
+
+		stats, err := s.GetBatchSpecWorkspaceStats(ctx, spec.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(btypes.BatchSpecWorkspaceStats{Total: 3, Queued: 3}, stats); diff != "" {
+			t.Fatal(diff)
+		}
+		if stats.Finished() {
+			t.Fatal("stats should not be finished")
+		}
+
+		setState(workspaces[0], btypes.BatchSpecExecutionStateCompleted, 0)
+		setState(workspaces[1], btypes.BatchSpecExecutionStateErrored, 1)
+		setState(workspaces[2], btypes.BatchSpecExecutionStateFailed, btypes.BatchSpecWorkspaceMaxNumRetries)
+
+		stats, err = s.GetBatchSpecWorkspaceStats(ctx, spec.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The errored workspace is retried, so it counts as queued.
+		if diff := cmp.Diff(btypes.BatchSpecWorkspaceStats{Total: 3, Queued: 1, Completed: 1, Failed: 1}, stats); diff != "" {
+			t.Fatal(diff)
+		}
+
+		setState(workspaces[1], btypes.BatchSpecExecutionStateErrored, btypes.BatchSpecWorkspaceMaxNumRetries)
+
+		stats, err = s.GetBatchSpecWorkspaceStats(ctx, spec.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(btypes.BatchSpecWorkspaceStats{Total: 3, Completed: 1, Errored: 1, Failed: 1}, stats); diff != "" {
+			t.Fatal(diff)
+		}
+		if !stats.Finished() {
+			t.Fatal("stats should be finished")
+		}
+	})
+
+	t.Run("SetChangesetSpecs", func(t *testing.T) {
+		clock.Add(1 * time.Second)
+
+		ws := workspaces[0]
+		if err := s.SetBatchSpecWorkspaceChangesetSpecs(ctx, ws.ID, []int64{1, 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := s.GetBatchSpecWorkspace(ctx, ws.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]int64{1, 2}, have.ChangesetSpecIDs); diff != "" {
+			t.Fatal(diff)
+		}
+		if !have.UpdatedAt.Equal(clock.Now()) {
+			t.Fatalf("wrong updated at. want=%s, have=%s", clock.Now(), have.UpdatedAt)
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		// Completed workspaces can't be retried.
+		if _, err := s.RetryBatchSpecWorkspace(ctx, workspaces[0].ID); err != ErrNoResults {
+			t.Fatalf("have err %v, want %v", err, ErrNoResults)
+		}
+
+		for _, ws := range workspaces[1:] {
+			have, err := s.RetryBatchSpecWorkspace(ctx, ws.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have.State != btypes.BatchSpecExecutionStateQueued {
+				t.Fatalf("wrong state %s", have.State)
+			}
+			if have.NumFailures != 0 {
+				t.Fatalf("wrong number of failures %d", have.NumFailures)
+			}
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// GetChangesetDependencyOpts captures the query options needed for getting
+// the changeset a btypes.ChangesetDependency refers to.
+type GetChangesetDependencyOpts struct {
+	RepoName api.RepoName
+
+	// HeadRef and OwnedByBatchChangeID select the changeset created for a
+	// branch by a batch change. The changeset doesn't need to be published.
+	HeadRef              string
+	OwnedByBatchChangeID int64
+
+	// ExternalID selects a changeset tracked on Sourcegraph.
+	ExternalID string
+}
+
+// GetChangesetDependency gets the changeset matching the given options.
+// ErrNoResults is returned if there is none.
+func (s *Store) GetChangesetDependency(ctx context.Context, opts GetChangesetDependencyOpts) (*btypes.Changeset, error) {
+	q := getChangesetDependencyQuery(&opts)
+
+	var c btypes.Changeset
+	err := s.query(ctx, q, func(sc scanner) error {
+		return scanChangeset(&c, sc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ID == 0 {
+		return nil, ErrNoResults
+	}
+
+	return &c, nil
+}
+
+var getChangesetDependencyQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changeset_dependencies.go:GetChangesetDependency
+SELECT %s FROM changesets
+INNER JOIN repo ON repo.id = changesets.repo_id
+LEFT JOIN changeset_specs ON changeset_specs.id = changesets.current_spec_id
+WHERE %s
+ORDER BY changesets.id ASC
+LIMIT 1
+`
+
+func getChangesetDependencyQuery(opts *GetChangesetDependencyOpts) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("repo.deleted_at IS NULL"),
+		sqlf.Sprintf("repo.name = %s", opts.RepoName),
+	}
+
+	if opts.ExternalID != "" {
+		preds = append(preds, sqlf.Sprintf("changesets.external_id = %s", opts.ExternalID))
+	} else {
+		preds = append(preds,
+			sqlf.Sprintf("changesets.owned_by_batch_change_id = %s", opts.OwnedByBatchChangeID),
+			sqlf.Sprintf("(changesets.external_branch = %s OR changeset_specs.head_ref = %s)", opts.HeadRef, opts.HeadRef),
+		)
+	}
+
+	return sqlf.Sprintf(
+		getChangesetDependencyQueryFmtstr,
+		sqlf.Join(ChangesetColumns, ", "),
+		sqlf.Join(preds, "\n AND "),
+	)
+}
+
+// ReleaseChangesetHeldByDependencies enqueues the given changeset, whose
+// publication the reconciler held back because of its dependencies, so that
+// it's published. Changesets that are being processed are left untouched,
+// since the reconciler reevaluates their dependencies anyway.
+func (s *Store) ReleaseChangesetHeldByDependencies(ctx context.Context, cs *btypes.Changeset, state btypes.ReconcilerState) error {
+	q := sqlf.Sprintf(
+		releaseChangesetHeldByDependenciesQueryFmtstr,
+		state.ToDB(),
+		s.now(),
+		cs.ID,
+		btypes.ReconcilerStateCompleted.ToDB(),
+		sqlf.Join(ChangesetColumns, ", "),
+	)
+
+	return s.query(ctx, q, func(sc scanner) error {
+		return scanChangeset(cs, sc)
+	})
+}
+
+var releaseChangesetHeldByDependenciesQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changeset_dependencies.go:ReleaseChangesetHeldByDependencies
+UPDATE changesets
+SET
+	dependencies_held = FALSE,
+	reconciler_state = %s,
+	num_resets = 0,
+	num_failures = 0,
+	failure_message = NULL,
+	updated_at = %s
+WHERE
+	id = %s AND
+	dependencies_held AND
+	reconciler_state = %s
+RETURNING
+  %s
+`
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func testStoreChangesetDependencies(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
+	repoStore := database.ReposWith(s)
+	esStore := database.ExternalServicesWith(s)
+
+	repo := ct.TestRepo(t, esStore, extsvc.KindGitHub)
+	if err := repoStore.Create(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+
+	batchChange := ct.CreateBatchChange(t, ctx, s, "dependencies", 1, 1)
+	otherBatchChange := ct.CreateBatchChange(t, ctx, s, "dependencies-other", 1, 1)
+
+	spec := ct.CreateChangesetSpec(t, ctx, s, ct.TestSpecOpts{
+		User:    1,
+		Repo:    repo.ID,
+		HeadRef: "refs/heads/unpublished",
+	})
+	unpublished := ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
+		Repo:               repo.ID,
+		BatchChange:        batchChange.ID,
+		OwnedByBatchChange: batchChange.ID,
+		CurrentSpec:        spec.ID,
+		PublicationState:   btypes.ChangesetPublicationStateUnpublished,
+		ReconcilerState:    btypes.ReconcilerStateCompleted,
+	})
+	published := ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
+		Repo:               repo.ID,
+		BatchChange:        batchChange.ID,
+		OwnedByBatchChange: batchChange.ID,
+		ExternalID:         "12",
+		ExternalBranch:     "published",
+		ExternalState:      btypes.ChangesetExternalStateMerged,
+		PublicationState:   btypes.ChangesetPublicationStatePublished,
+		ReconcilerState:    btypes.ReconcilerStateCompleted,
+	})
+	imported := ct.CreateChangeset(t, ctx, s, ct.TestChangesetOpts{
+		Repo:             repo.ID,
+		BatchChange:      otherBatchChange.ID,
+		ExternalID:       "13",
+		ExternalState:    btypes.ChangesetExternalStateOpen,
+		PublicationState: btypes.ChangesetPublicationStatePublished,
+		ReconcilerState:  btypes.ReconcilerStateCompleted,
+	})
+
+	t.Run("GetChangesetDependency", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			opts GetChangesetDependencyOpts
+			want *btypes.Changeset
+		}{
+			"unpublished branch": {
+				opts: GetChangesetDependencyOpts{RepoName: repo.Name, HeadRef: "refs/heads/unpublished", OwnedByBatchChangeID: batchChange.ID},
+				want: unpublished,
+			},
+			"published branch": {
+				opts: GetChangesetDependencyOpts{RepoName: repo.Name, HeadRef: "refs/heads/published", OwnedByBatchChangeID: batchChange.ID},
+				want: published,
+			},
+			"external ID": {
+				opts: GetChangesetDependencyOpts{RepoName: repo.Name, ExternalID: "13"},
+				want: imported,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, err := s.GetChangesetDependency(ctx, tc.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if have.ID != tc.want.ID {
+					t.Fatalf("wrong changeset. want=%d, have=%d", tc.want.ID, have.ID)
+				}
+			})
+		}
+
+		for name, opts := range map[string]GetChangesetDependencyOpts{
+			"other batch change": {RepoName: repo.Name, HeadRef: "refs/heads/published", OwnedByBatchChangeID: otherBatchChange.ID},
+			"other repository":   {RepoName: "github.com/sourcegraph/other", ExternalID: "13"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				if _, err := s.GetChangesetDependency(ctx, opts); err != ErrNoResults {
+					t.Fatalf("have err %v, want %v", err, ErrNoResults)
+				}
+			})
+		}
+	})
+
+	t.Run("UpdateChangesetDependenciesHeld", func(t *testing.T) {
+		unpublished.DependenciesHeld = true
+		if err := s.UpdateChangesetDependenciesHeld(ctx, unpublished); err != nil {
+			t.Fatal(err)
+		}
+
+		have, _, err := s.ListChangesets(ctx, ListChangesetsOpts{OnlyDependenciesHeld: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 1 || have[0].ID != unpublished.ID || !have[0].DependenciesHeld {
+			t.Fatalf("wrong held changesets %+v", have)
+		}
+	})
+
+	t.Run("ReleaseChangesetHeldByDependencies", func(t *testing.T) {
+		if err := s.ReleaseChangesetHeldByDependencies(ctx, unpublished, btypes.ReconcilerStateQueued); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := s.GetChangeset(ctx, GetChangesetOpts{ID: unpublished.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have.DependenciesHeld || have.ReconcilerState != btypes.ReconcilerStateQueued {
+			t.Fatalf("changeset not released: held=%t state=%s", have.DependenciesHeld, have.ReconcilerState)
+		}
+	})
+}
@@ -113,6 +113,12 @@ func (s *Store) CreateChangesetJob(ctx context.Context, cs ...*btypes.ChangesetJ
 // GetChangesetJobOpts captures the query options needed for getting a ChangesetJob
 type GetChangesetJobOpts struct {
 	ID int64
+
+	// If no ID is given, the most recent job matching the following
+	// options is returned.
+	BatchChangeID int64
+	ChangesetID   int64
+	JobType       btypes.ChangesetJobType
 }
 
 // GetChangesetJob gets a ChangesetJob matching the given options.
@@ -139,13 +145,25 @@ SELECT %s FROM changeset_jobs
 INNER JOIN changesets ON changesets.id = changeset_jobs.changeset_id
 INNER JOIN repo ON repo.id = changesets.repo_id
 WHERE %s
+ORDER BY changeset_jobs.id DESC
 LIMIT 1
 `
 
 func getChangesetJobQuery(opts *GetChangesetJobOpts) *sqlf.Query {
 	preds := []*sqlf.Query{
 		sqlf.Sprintf("repo.deleted_at IS NULL"),
-		sqlf.Sprintf("changeset_jobs.id = %s", opts.ID),
+	}
+	if opts.ID != 0 {
+		preds = append(preds, sqlf.Sprintf("changeset_jobs.id = %s", opts.ID))
+	}
+	if opts.BatchChangeID != 0 {
+		preds = append(preds, sqlf.Sprintf("changeset_jobs.batch_change_id = %s", opts.BatchChangeID))
+	}
+	if opts.ChangesetID != 0 {
+		preds = append(preds, sqlf.Sprintf("changeset_jobs.changeset_id = %s", opts.ChangesetID))
+	}
+	if opts.JobType != "" {
+		preds = append(preds, sqlf.Sprintf("changeset_jobs.job_type = %s", opts.JobType))
 	}
 
 	return sqlf.Sprintf(
@@ -156,7 +174,10 @@ func getChangesetJobQuery(opts *GetChangesetJobOpts) *sqlf.Query {
 }
 
 func scanChangesetJob(c *btypes.ChangesetJob, s scanner) error {
-	var raw json.RawMessage
+	var (
+		raw            json.RawMessage
+		failureMessage string
+	)
 	if err := s.Scan(
 		&c.ID,
 		&c.BulkGroup,
@@ -166,7 +187,7 @@ func scanChangesetJob(c *btypes.ChangesetJob, s scanner) error {
 		&c.JobType,
 		&raw,
 		&c.State,
-		&dbutil.NullString{S: c.FailureMessage},
+		&dbutil.NullString{S: &failureMessage},
 		&dbutil.NullTime{Time: &c.StartedAt},
 		&dbutil.NullTime{Time: &c.FinishedAt},
 		&dbutil.NullTime{Time: &c.ProcessAfter},
@@ -177,6 +198,9 @@ func scanChangesetJob(c *btypes.ChangesetJob, s scanner) error {
 	); err != nil {
 		return err
 	}
+	if failureMessage != "" {
+		c.FailureMessage = &failureMessage
+	}
 	switch c.JobType {
 	case btypes.ChangesetJobTypeComment:
 		c.Payload = new(btypes.ChangesetJobCommentPayload)
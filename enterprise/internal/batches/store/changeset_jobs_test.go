@@ -96,6 +96,20 @@ func testStoreChangesetJobs(t *testing.T, ctx context.Context, s *Store, clock c
 			})
 		}
 
+		t.Run("LatestForChangeset", func(t *testing.T) {
+			have, err := s.GetChangesetJob(ctx, GetChangesetJobOpts{
+				ChangesetID: changeset.ID,
+				JobType:     btypes.ChangesetJobTypeComment,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(have, jobs[1]); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+
 		t.Run("NoResults", func(t *testing.T) {
 			opts := GetChangesetJobOpts{ID: 0xdeadbeef}
 
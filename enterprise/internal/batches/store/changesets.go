@@ -19,7 +19,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 )
@@ -60,6 +62,10 @@ var ChangesetColumns = []*sqlf.Query{
 	sqlf.Sprintf("changesets.num_failures"),
 	sqlf.Sprintf("changesets.closing"),
 	sqlf.Sprintf("changesets.syncer_error"),
+	sqlf.Sprintf("changesets.auto_merge_blocked_reason"),
+	sqlf.Sprintf("changesets.rollout_wave"),
+	sqlf.Sprintf("changesets.rollout_held"),
+	sqlf.Sprintf("changesets.dependencies_held"),
 }
 
 // changesetInsertColumns is the list of changeset columns that are modified in
@@ -510,6 +516,7 @@ type ListChangesetsOpts struct {
 	ExternalReviewState  *btypes.ChangesetReviewState
 	ExternalCheckState   *btypes.ChangesetCheckState
 	OwnedByBatchChangeID int64
+	OnlyDependenciesHeld bool
 	TextSearch           []search.TextSearchTerm
 	EnforceAuthz         bool
 	RepoID               api.RepoID
@@ -603,6 +610,9 @@ func listChangesetsQuery(opts *ListChangesetsOpts, authzConds *sqlf.Query) *sqlf
 	if opts.OwnedByBatchChangeID != 0 {
 		preds = append(preds, sqlf.Sprintf("changesets.owned_by_batch_change_id = %s", opts.OwnedByBatchChangeID))
 	}
+	if opts.OnlyDependenciesHeld {
+		preds = append(preds, sqlf.Sprintf("changesets.dependencies_held"))
+	}
 	if opts.EnforceAuthz {
 		preds = append(preds, authzConds)
 	}
@@ -772,6 +782,98 @@ RETURNING
   %s
 `
 
+// UpdateChangesetAutoMergeBlockedReason records why the auto-merge policy of
+// the owning batch change has not merged the given changeset. A nil reason
+// clears a previously recorded one.
+func (s *Store) UpdateChangesetAutoMergeBlockedReason(ctx context.Context, cs *btypes.Changeset, reason *string) error {
+	q := sqlf.Sprintf(
+		updateChangesetAutoMergeBlockedReasonQueryFmtstr,
+		reason,
+		cs.ID,
+		sqlf.Join(ChangesetColumns, ", "),
+	)
+
+	return s.query(ctx, q, func(sc scanner) (err error) {
+		return scanChangeset(cs, sc)
+	})
+}
+
+var updateChangesetAutoMergeBlockedReasonQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changesets.go:UpdateChangesetAutoMergeBlockedReason
+UPDATE changesets
+SET auto_merge_blocked_reason = %s
+WHERE id = %s
+RETURNING
+  %s
+`
+
+// ClearAutoMergeBlockedReasons clears the auto-merge blocked reasons of the
+// changesets owned by the given batch change that are no longer open.
+func (s *Store) ClearAutoMergeBlockedReasons(ctx context.Context, batchChangeID int64) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		clearAutoMergeBlockedReasonsQueryFmtstr,
+		batchChangeID,
+		btypes.ChangesetExternalStateOpen,
+		btypes.ChangesetExternalStateDraft,
+	))
+}
+
+var clearAutoMergeBlockedReasonsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changesets.go:ClearAutoMergeBlockedReasons
+UPDATE changesets
+SET auto_merge_blocked_reason = NULL
+WHERE
+	owned_by_batch_change_id = %s AND
+	auto_merge_blocked_reason IS NOT NULL AND
+	COALESCE(external_state, '') NOT IN (%s, %s)
+`
+
+// UpdateChangesetRollout updates the rollout wave and the rollout_held flag of
+// the given changeset.
+func (s *Store) UpdateChangesetRollout(ctx context.Context, cs *btypes.Changeset) error {
+	q := sqlf.Sprintf(
+		updateChangesetRolloutQueryFmtstr,
+		cs.RolloutWave,
+		cs.RolloutHeld,
+		cs.ID,
+		sqlf.Join(ChangesetColumns, ", "),
+	)
+
+	return s.query(ctx, q, func(sc scanner) (err error) {
+		return scanChangeset(cs, sc)
+	})
+}
+
+var updateChangesetRolloutQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changesets.go:UpdateChangesetRollout
+UPDATE changesets
+SET
+	rollout_wave = %s,
+	rollout_held = %s
+WHERE id = %s
+RETURNING
+  %s
+`
+
+// UpdateChangesetDependenciesHeld updates the dependencies_held flag of the
+// given changeset. Unlike the other update methods, it doesn't overwrite the
+// other fields of the changeset with their values in the database, since the
+// reconciler still changes them afterwards.
+func (s *Store) UpdateChangesetDependenciesHeld(ctx context.Context, cs *btypes.Changeset) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		updateChangesetDependenciesHeldQueryFmtstr,
+		cs.DependenciesHeld,
+		cs.ID,
+	))
+}
+
+var updateChangesetDependenciesHeldQueryFmtstr = `
+-- source: enterprise/internal/batches/store/changesets.go:UpdateChangesetDependenciesHeld
+UPDATE changesets
+SET dependencies_held = %s
+WHERE id = %s
+`
+
 // GetChangesetExternalIDs allows us to find the external ids for pull requests based on
 // a slice of head refs. We need this in order to match incoming webhooks to pull requests as
 // the only information they provide is the remote branch
@@ -1021,6 +1123,8 @@ func scanChangeset(t *btypes.Changeset, s scanner) error {
 		failureMessage      string
 		syncErrorMessage    string
 		reconcilerState     string
+		autoMergeBlocked    string
+		rolloutWave         sql.NullInt32
 	)
 	err := s.Scan(
 		&t.ID,
@@ -1055,6 +1159,10 @@ func scanChangeset(t *btypes.Changeset, s scanner) error {
 		&t.NumFailures,
 		&t.Closing,
 		&dbutil.NullString{S: &syncErrorMessage},
+		&dbutil.NullString{S: &autoMergeBlocked},
+		&rolloutWave,
+		&t.RolloutHeld,
+		&t.DependenciesHeld,
 	)
 	if err != nil {
 		return errors.Wrap(err, "scanning changeset")
@@ -1069,6 +1177,13 @@ func scanChangeset(t *btypes.Changeset, s scanner) error {
 	if syncErrorMessage != "" {
 		t.SyncErrorMessage = &syncErrorMessage
 	}
+	if autoMergeBlocked != "" {
+		t.AutoMergeBlockedReason = &autoMergeBlocked
+	}
+	t.RolloutWave = nil
+	if rolloutWave.Valid {
+		t.RolloutWave = &rolloutWave.Int32
+	}
 	t.ReconcilerState = btypes.ReconcilerState(strings.ToUpper(reconcilerState))
 
 	switch t.ExternalServiceType {
@@ -1078,6 +1193,10 @@ func scanChangeset(t *btypes.Changeset, s scanner) error {
 		t.Metadata = new(bitbucketserver.PullRequest)
 	case extsvc.TypeGitLab:
 		t.Metadata = new(gitlab.MergeRequest)
+	case extsvc.TypeBitbucketCloud:
+		t.Metadata = new(bitbucketcloud.PullRequest)
+	case extsvc.TypeGitea:
+		t.Metadata = new(gitea.PullRequest)
 	default:
 		return errors.New("unknown external service type")
 	}
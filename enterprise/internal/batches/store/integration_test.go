@@ -35,6 +35,11 @@ func TestIntegration(t *testing.T) {
 		t.Run("ChangesetJobs", storeTest(db, nil, testStoreChangesetJobs))
 		t.Run("BulkOperations", storeTest(db, nil, testStoreBulkOperations))
 		t.Run("BatchSpecExecutions", storeTest(db, nil, testStoreChangesetSpecExecutions))
+		t.Run("AutoMergePolicies", storeTest(db, nil, testStoreAutoMergePolicies))
+		t.Run("BatchChangeRollouts", storeTest(db, nil, testStoreBatchChangeRollouts))
+		t.Run("ChangesetDependencies", storeTest(db, nil, testStoreChangesetDependencies))
+		t.Run("BatchSpecResolutionJobs", storeTest(db, nil, testStoreBatchSpecResolutionJobs))
+		t.Run("BatchSpecWorkspaces", storeTest(db, nil, testStoreBatchSpecWorkspaces))
 
 		for name, key := range map[string]encryption.Key{
 			"no key":   nil,
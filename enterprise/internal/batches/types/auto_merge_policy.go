@@ -0,0 +1,127 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// An AutoMergePolicy describes the conditions under which the changesets owned
+// by a batch change are merged without user interaction.
+type AutoMergePolicy struct {
+	BatchChangeID int64
+
+	// UserID is the user who set the policy. Changesets are merged with their
+	// credentials.
+	UserID int32
+
+	// RequiredCheckState is the check state a changeset needs to be in to be
+	// merged. If empty, checks are not considered.
+	RequiredCheckState ChangesetCheckState
+	// RequiredReviewState is the review state a changeset needs to be in to be
+	// merged. If empty, reviews are not considered.
+	RequiredReviewState ChangesetReviewState
+
+	// Squash is passed on to the code host when merging.
+	Squash bool
+
+	// MergeWindow restricts the times at which changesets are merged. If nil,
+	// changesets are merged as soon as they are eligible.
+	MergeWindow *MergeWindow
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BlockedReason returns a human-readable explanation of why the given
+// changeset may not be merged at the given time under this policy, or an
+// empty string if it may be merged.
+func (p *AutoMergePolicy) BlockedReason(c *Changeset, now time.Time) string {
+	switch c.ExternalState {
+	case ChangesetExternalStateOpen:
+	case ChangesetExternalStateDraft:
+		return "The changeset is a draft."
+	default:
+		return "The changeset is not open."
+	}
+
+	if p.RequiredReviewState != "" && c.ExternalReviewState != p.RequiredReviewState {
+		return fmt.Sprintf("The review state is %s, but %s is required.", stateOrUnknown(string(c.ExternalReviewState)), p.RequiredReviewState)
+	}
+
+	if p.RequiredCheckState != "" && c.ExternalCheckState != p.RequiredCheckState {
+		return fmt.Sprintf("The check state is %s, but %s is required.", stateOrUnknown(string(c.ExternalCheckState)), p.RequiredCheckState)
+	}
+
+	if p.MergeWindow != nil && !p.MergeWindow.Contains(now) {
+		return fmt.Sprintf("Changesets are only merged during the merge window (%s).", p.MergeWindow)
+	}
+
+	return ""
+}
+
+func stateOrUnknown(state string) string {
+	if state == "" {
+		return "UNKNOWN"
+	}
+	return state
+}
+
+// A MergeWindow is a daily period of time, in UTC, during which changesets
+// may be merged.
+type MergeWindow struct {
+	// Start and End are given in minutes after midnight UTC. If End is before
+	// Start, the window wraps around midnight. If both are equal, the window
+	// spans the whole day.
+	Start int
+	End   int
+
+	// Weekdays are the days on which the window starts. If empty, the window
+	// applies on every day.
+	Weekdays []time.Weekday
+}
+
+// Contains returns true if the given time falls within the merge window.
+func (w *MergeWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	minute := t.Hour()*60 + t.Minute()
+	weekday := t.Weekday()
+
+	switch {
+	case w.Start < w.End:
+		if minute < w.Start || minute >= w.End {
+			return false
+		}
+	case w.Start > w.End:
+		if minute < w.End {
+			// The part of a window after midnight belongs to the day on
+			// which the window started.
+			weekday = (weekday + 6) % 7
+		} else if minute < w.Start {
+			return false
+		}
+	}
+
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range w.Weekdays {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *MergeWindow) String() string {
+	s := fmt.Sprintf("%02d:%02d-%02d:%02d UTC", w.Start/60, w.Start%60, w.End/60, w.End%60)
+	if len(w.Weekdays) == 0 {
+		return s
+	}
+
+	days := make([]string, 0, len(w.Weekdays))
+	for _, d := range w.Weekdays {
+		days = append(days, d.String())
+	}
+	return s + " on " + strings.Join(days, ", ")
+}
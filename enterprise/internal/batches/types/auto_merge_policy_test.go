@@ -0,0 +1,124 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAutoMergePolicy_BlockedReason(t *testing.T) {
+	now := time.Date(2021, 6, 7, 12, 0, 0, 0, time.UTC) // A Monday.
+
+	policy := &AutoMergePolicy{
+		RequiredCheckState:  ChangesetCheckStatePassed,
+		RequiredReviewState: ChangesetReviewStateApproved,
+	}
+
+	eligible := func() *Changeset {
+		return &Changeset{
+			ExternalState:       ChangesetExternalStateOpen,
+			ExternalCheckState:  ChangesetCheckStatePassed,
+			ExternalReviewState: ChangesetReviewStateApproved,
+		}
+	}
+
+	tests := map[string]struct {
+		policy    *AutoMergePolicy
+		changeset func(c *Changeset)
+		want      string
+	}{
+		"eligible": {
+			policy: policy,
+			want:   "",
+		},
+		"draft": {
+			policy:    policy,
+			changeset: func(c *Changeset) { c.ExternalState = ChangesetExternalStateDraft },
+			want:      "The changeset is a draft.",
+		},
+		"merged": {
+			policy:    policy,
+			changeset: func(c *Changeset) { c.ExternalState = ChangesetExternalStateMerged },
+			want:      "The changeset is not open.",
+		},
+		"review pending": {
+			policy:    policy,
+			changeset: func(c *Changeset) { c.ExternalReviewState = ChangesetReviewStatePending },
+			want:      "The review state is PENDING, but APPROVED is required.",
+		},
+		"checks failed": {
+			policy:    policy,
+			changeset: func(c *Changeset) { c.ExternalCheckState = ChangesetCheckStateFailed },
+			want:      "The check state is FAILED, but PASSED is required.",
+		},
+		"no checks": {
+			policy:    policy,
+			changeset: func(c *Changeset) { c.ExternalCheckState = "" },
+			want:      "The check state is UNKNOWN, but PASSED is required.",
+		},
+		"checks not required": {
+			policy:    &AutoMergePolicy{RequiredReviewState: ChangesetReviewStateApproved},
+			changeset: func(c *Changeset) { c.ExternalCheckState = ChangesetCheckStateFailed },
+			want:      "",
+		},
+		"inside merge window": {
+			policy: &AutoMergePolicy{MergeWindow: &MergeWindow{Start: 9 * 60, End: 17 * 60}},
+			want:   "",
+		},
+		"outside merge window": {
+			policy: &AutoMergePolicy{MergeWindow: &MergeWindow{Start: 14 * 60, End: 17 * 60, Weekdays: []time.Weekday{time.Monday, time.Tuesday}}},
+			want:   "Changesets are only merged during the merge window (14:00-17:00 UTC on Monday, Tuesday).",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := eligible()
+			if tc.changeset != nil {
+				tc.changeset(c)
+			}
+
+			if have := tc.policy.BlockedReason(c, now); have != tc.want {
+				t.Fatalf("wrong reason. want=%q, have=%q", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestMergeWindow_Contains(t *testing.T) {
+	weekdays := []time.Weekday{time.Friday}
+
+	tests := []struct {
+		window *MergeWindow
+		t      time.Time
+		want   bool
+	}{
+		// Whole day.
+		{&MergeWindow{}, time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC), true},
+		{&MergeWindow{}, time.Date(2021, 6, 7, 23, 59, 0, 0, time.UTC), true},
+
+		// Within a single day.
+		{&MergeWindow{Start: 9 * 60, End: 17 * 60}, time.Date(2021, 6, 7, 8, 59, 0, 0, time.UTC), false},
+		{&MergeWindow{Start: 9 * 60, End: 17 * 60}, time.Date(2021, 6, 7, 9, 0, 0, 0, time.UTC), true},
+		{&MergeWindow{Start: 9 * 60, End: 17 * 60}, time.Date(2021, 6, 7, 17, 0, 0, 0, time.UTC), false},
+
+		// Times are compared in UTC.
+		{&MergeWindow{Start: 9 * 60, End: 17 * 60}, time.Date(2021, 6, 7, 10, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)), false},
+
+		// Wrapping around midnight.
+		{&MergeWindow{Start: 22 * 60, End: 2 * 60}, time.Date(2021, 6, 7, 23, 0, 0, 0, time.UTC), true},
+		{&MergeWindow{Start: 22 * 60, End: 2 * 60}, time.Date(2021, 6, 7, 1, 0, 0, 0, time.UTC), true},
+		{&MergeWindow{Start: 22 * 60, End: 2 * 60}, time.Date(2021, 6, 7, 12, 0, 0, 0, time.UTC), false},
+
+		// Weekdays. June 11th 2021 is a Friday.
+		{&MergeWindow{Weekdays: weekdays}, time.Date(2021, 6, 11, 12, 0, 0, 0, time.UTC), true},
+		{&MergeWindow{Weekdays: weekdays}, time.Date(2021, 6, 12, 12, 0, 0, 0, time.UTC), false},
+		{&MergeWindow{Start: 22 * 60, End: 2 * 60, Weekdays: weekdays}, time.Date(2021, 6, 12, 1, 0, 0, 0, time.UTC), true},
+		{&MergeWindow{Start: 22 * 60, End: 2 * 60, Weekdays: weekdays}, time.Date(2021, 6, 11, 1, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tc := range tests {
+		if have := tc.window.Contains(tc.t); have != tc.want {
+			t.Errorf("wrong result for window %s at %s. want=%t, have=%t", tc.window, tc.t, tc.want, have)
+		}
+	}
+}
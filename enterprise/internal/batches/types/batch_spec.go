@@ -41,7 +41,15 @@ func (cs *BatchSpec) Clone() *BatchSpec {
 // UnmarshalValidate unmarshals the RawSpec into Spec and validates it against
 // the BatchSpec schema and does additional semantic validation.
 func (cs *BatchSpec) UnmarshalValidate() error {
-	return yaml.UnmarshalValidate(schema.BatchSpecSchemaJSON, []byte(cs.RawSpec), &cs.Spec)
+	if err := yaml.UnmarshalValidate(schema.BatchSpecSchemaJSON, []byte(cs.RawSpec), &cs.Spec); err != nil {
+		return err
+	}
+
+	if cs.Spec.Rollout != nil {
+		return cs.Spec.Rollout.validate()
+	}
+
+	return nil
 }
 
 // BatchSpecTTL specifies the TTL of BatchSpecs that haven't been applied
@@ -58,20 +66,35 @@ type BatchSpecFields struct {
 	Name              string                       `json:"name" yaml:"name"`
 	Description       string                       `json:"description,omitempty" yaml:"description,omitempty"`
 	On                []BatchSpecOn                `json:"on,omitempty" yaml:"on,omitempty"`
+	Workspaces        []WorkspaceConfiguration     `json:"workspaces,omitempty" yaml:"workspaces,omitempty"`
 	Steps             []BatchSpecStep              `json:"steps,omitempty" yaml:"steps,omitempty"`
 	ImportChangeset   []BatchChangeImportChangeset `json:"importChangesets,omitempty" yaml:"importChangesets,omitempty"`
 	ChangesetTemplate ChangesetTemplate            `json:"changesetTemplate,omitempty" yaml:"changesetTemplate,omitempty"`
+	Rollout           *BatchSpecRollout            `json:"rollout,omitempty" yaml:"rollout,omitempty"`
 }
 
 type BatchSpecOn struct {
 	RepositoriesMatchingQuery string `json:"repositoriesMatchingQuery,omitempty" yaml:"repositoriesMatchingQuery,omitempty"`
 	Repository                string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	Branch                    string `json:"branch,omitempty" yaml:"branch,omitempty"`
+}
+
+type WorkspaceConfiguration struct {
+	RootAtLocationOf   string `json:"rootAtLocationOf,omitempty" yaml:"rootAtLocationOf"`
+	In                 string `json:"in,omitempty" yaml:"in,omitempty"`
+	OnlyFetchWorkspace bool   `json:"onlyFetchWorkspace,omitempty" yaml:"onlyFetchWorkspace,omitempty"`
 }
 
 type BatchSpecStep struct {
 	Run       string          `json:"run" yaml:"run"`
 	Container string          `json:"container" yaml:"container"`
 	Env       env.Environment `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// Files, If and Outputs are only evaluated by src-cli. Server-side
+	// execution rejects steps that use them.
+	Files   map[string]string      `json:"files,omitempty" yaml:"files,omitempty"`
+	If      interface{}            `json:"if,omitempty" yaml:"if,omitempty"`
+	Outputs map[string]interface{} `json:"outputs,omitempty" yaml:"outputs,omitempty"`
 }
 
 type BatchChangeImportChangeset struct {
@@ -85,8 +108,28 @@ type ChangesetTemplate struct {
 	Branch    string                   `json:"branch,omitempty" yaml:"branch,omitempty"`
 	Commit    CommitTemplate           `json:"commit,omitempty" yaml:"commit,omitempty"`
 	Published overridable.BoolOrString `json:"published,omitempty" yaml:"published,omitempty"`
+	DependsOn []ChangesetDependency    `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	Reviewers *ChangesetReviewers      `json:"reviewers,omitempty" yaml:"reviewers,omitempty"`
 }
 
 type CommitTemplate struct {
-	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	Message string           `json:"message,omitempty" yaml:"message,omitempty"`
+	Author  *GitCommitAuthor `json:"author,omitempty" yaml:"author,omitempty"`
+}
+
+type GitCommitAuthor struct {
+	Name  string `json:"name" yaml:"name"`
+	Email string `json:"email" yaml:"email"`
+}
+
+type BatchSpecRollout struct {
+	Waves           []BatchSpecRolloutWave `json:"waves" yaml:"waves"`
+	MinSuccessRatio *float64               `json:"minSuccessRatio,omitempty" yaml:"minSuccessRatio,omitempty"`
+	PauseOnFailure  bool                   `json:"pauseOnFailure,omitempty" yaml:"pauseOnFailure,omitempty"`
+}
+
+type BatchSpecRolloutWave struct {
+	Count        int      `json:"count,omitempty" yaml:"count,omitempty"`
+	Percentage   float64  `json:"percentage,omitempty" yaml:"percentage,omitempty"`
+	Repositories []string `json:"repositories,omitempty" yaml:"repositories,omitempty"`
 }
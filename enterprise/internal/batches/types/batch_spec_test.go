@@ -94,6 +94,39 @@ changesetTemplate:
 			}`,
 			err: "1 error occurred:\n\t* name: Does not match pattern '^[\\w.-]+$'\n\n",
 		},
+		{
+			name: "valid rollout",
+			rawSpec: `
+name: my-unique-name
+changesetTemplate:
+  title: Hello World
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+rollout:
+  waves:
+  - repositories: ["github.com/sourcegraph/*"]
+  - percentage: 25
+  - {}
+  minSuccessRatio: 0.9
+  pauseOnFailure: true
+`,
+		},
+		{
+			name: "invalid rollout pattern",
+			rawSpec: `
+name: my-unique-name
+changesetTemplate:
+  title: Hello World
+  branch: hello-world
+  commit:
+    message: Append Hello World to all README.md files
+rollout:
+  waves:
+  - repositories: ["github.com/["]
+`,
+			err: "rollout wave 0: invalid repository pattern \"github.com/[\": unexpected end of input",
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+// BatchSpecResolutionJob resolves the `on` and `workspaces` of a batch spec
+// that is executed server-side into BatchSpecWorkspaces.
+type BatchSpecResolutionJob struct {
+	ID int64
+
+	BatchSpecID int64
+
+	State          BatchSpecExecutionState
+	FailureMessage *string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	ProcessAfter   *time.Time
+	NumResets      int64
+	NumFailures    int64
+	ExecutionLogs  []workerutil.ExecutionLogEntry
+	WorkerHostname string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (j *BatchSpecResolutionJob) RecordID() int {
+	return int(j.ID)
+}
+
+// BatchSpecWorkspace is a directory in a repository revision in which the
+// steps of a batch spec are executed by an executor. Each workspace is a job
+// in the executor queue, and its execution logs are kept per workspace.
+type BatchSpecWorkspace struct {
+	ID int64
+
+	BatchSpecID int64
+
+	RepoID             api.RepoID
+	Branch             string
+	Commit             string
+	Path               string
+	OnlyFetchWorkspace bool
+
+	// ChangesetSpecIDs are the changeset specs created from the diff the
+	// execution produced. It's empty if the steps didn't change anything.
+	ChangesetSpecIDs []int64
+
+	State          BatchSpecExecutionState
+	FailureMessage *string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	ProcessAfter   *time.Time
+	NumResets      int64
+	NumFailures    int64
+	ExecutionLogs  []workerutil.ExecutionLogEntry
+	WorkerHostname string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w *BatchSpecWorkspace) RecordID() int {
+	return int(w.ID)
+}
+
+// BatchSpecWorkspaceMaxNumRetries is the number of times the execution of a
+// workspace is retried after it errored.
+const BatchSpecWorkspaceMaxNumRetries = 2
+
+// BatchSpecWorkspaceStats counts the workspaces of a batch spec by state.
+// Errored workspaces that will be retried count as queued.
+type BatchSpecWorkspaceStats struct {
+	Total      int32
+	Queued     int32
+	Processing int32
+	Completed  int32
+	Errored    int32
+	Failed     int32
+}
+
+// Finished returns whether all workspaces finished executing, successfully
+// or not.
+func (s BatchSpecWorkspaceStats) Finished() bool {
+	return s.Queued+s.Processing == 0
+}
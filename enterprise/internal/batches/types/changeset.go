@@ -11,7 +11,9 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
@@ -284,6 +286,22 @@ type Changeset struct {
 	// Closing is set to true (along with the ReocncilerState) when the
 	// reconciler should close the changeset.
 	Closing bool
+
+	// AutoMergeBlockedReason is set by the auto-merge worker when the
+	// auto-merge policy of the owning batch change does not allow the
+	// changeset to be merged yet.
+	AutoMergeBlockedReason *string
+
+	// RolloutWave is the rollout wave the changeset is published in, if the
+	// batch spec of the owning batch change declares rollout waves.
+	RolloutWave *int32
+	// RolloutHeld is set by the reconciler when it didn't publish the
+	// changeset because its rollout wave hasn't been released yet.
+	RolloutHeld bool
+	// DependenciesHeld is set by the reconciler when it didn't publish the
+	// changeset, or only published it as a draft, because the changesets it
+	// depends on haven't been merged yet.
+	DependenciesHeld bool
 }
 
 // RecordID is needed to implement the workerutil.Record interface.
@@ -379,6 +397,18 @@ func (c *Changeset) SetMetadata(meta interface{}) error {
 		c.ExternalServiceType = extsvc.TypeGitLab
 		c.ExternalBranch = git.EnsureRefPrefix(pr.SourceBranch)
 		c.ExternalUpdatedAt = pr.UpdatedAt.Time
+	case *bitbucketcloud.PullRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.FormatInt(pr.ID, 10)
+		c.ExternalServiceType = extsvc.TypeBitbucketCloud
+		c.ExternalBranch = git.EnsureRefPrefix(pr.Source.Branch.Name)
+		c.ExternalUpdatedAt = pr.UpdatedOn
+	case *gitea.PullRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.FormatInt(pr.Number, 10)
+		c.ExternalServiceType = extsvc.TypeGitea
+		c.ExternalBranch = git.EnsureRefPrefix(pr.Head.Ref)
+		c.ExternalUpdatedAt = pr.UpdatedAt
 	default:
 		return errors.New("unknown changeset type")
 	}
@@ -404,6 +434,10 @@ func (c *Changeset) Title() (string, error) {
 		return m.Title, nil
 	case *gitlab.MergeRequest:
 		return m.Title, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Title, nil
+	case *gitea.PullRequest:
+		return m.Title, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -421,6 +455,13 @@ func (c *Changeset) AuthorName() (string, error) {
 		return m.Author.User.Name, nil
 	case *gitlab.MergeRequest:
 		return m.Author.Username, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Author.Nickname, nil
+	case *gitea.PullRequest:
+		if m.User == nil {
+			return "", nil
+		}
+		return m.User.Login, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -444,6 +485,16 @@ func (c *Changeset) AuthorEmail() (string, error) {
 		return m.Author.User.EmailAddress, nil
 	case *gitlab.MergeRequest:
 		return m.Author.Email, nil
+	case *bitbucketcloud.PullRequest:
+		// Bitbucket Cloud doesn't expose email addresses through its API.
+		return "", nil
+	case *gitea.PullRequest:
+		// Gitea only returns the email address to administrators, and
+		// redacts it otherwise.
+		if m.User == nil {
+			return "", nil
+		}
+		return m.User.Email, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -460,6 +511,10 @@ func (c *Changeset) ExternalCreatedAt() time.Time {
 		return unixMilliToTime(int64(m.CreatedDate))
 	case *gitlab.MergeRequest:
 		return m.CreatedAt.Time
+	case *bitbucketcloud.PullRequest:
+		return m.CreatedOn
+	case *gitea.PullRequest:
+		return m.CreatedAt
 	default:
 		return time.Time{}
 	}
@@ -474,6 +529,10 @@ func (c *Changeset) Body() (string, error) {
 		return m.Description, nil
 	case *gitlab.MergeRequest:
 		return m.Description, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Description, nil
+	case *gitea.PullRequest:
+		return m.Body, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -511,6 +570,10 @@ func (c *Changeset) URL() (s string, err error) {
 		return selfLink.Href, nil
 	case *gitlab.MergeRequest:
 		return m.WebURL, nil
+	case *bitbucketcloud.PullRequest:
+		return m.Links.HTML.Href, nil
+	case *gitea.PullRequest:
+		return m.HTMLURL, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -658,6 +721,12 @@ func (c *Changeset) HeadRefOid() (string, error) {
 		return "", nil
 	case *gitlab.MergeRequest:
 		return m.DiffRefs.HeadSHA, nil
+	case *bitbucketcloud.PullRequest:
+		// Bitbucket Cloud only returns abbreviated commit hashes for pull
+		// requests, so we have to resolve the ref instead.
+		return "", nil
+	case *gitea.PullRequest:
+		return m.Head.SHA, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -673,6 +742,10 @@ func (c *Changeset) HeadRef() (string, error) {
 		return m.FromRef.ID, nil
 	case *gitlab.MergeRequest:
 		return "refs/heads/" + m.SourceBranch, nil
+	case *bitbucketcloud.PullRequest:
+		return "refs/heads/" + m.Source.Branch.Name, nil
+	case *gitea.PullRequest:
+		return "refs/heads/" + m.Head.Ref, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -689,6 +762,10 @@ func (c *Changeset) BaseRefOid() (string, error) {
 		return "", nil
 	case *gitlab.MergeRequest:
 		return m.DiffRefs.BaseSHA, nil
+	case *bitbucketcloud.PullRequest:
+		return "", nil
+	case *gitea.PullRequest:
+		return m.Base.SHA, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -704,6 +781,10 @@ func (c *Changeset) BaseRef() (string, error) {
 		return m.ToRef.ID, nil
 	case *gitlab.MergeRequest:
 		return "refs/heads/" + m.TargetBranch, nil
+	case *bitbucketcloud.PullRequest:
+		return "refs/heads/" + m.Destination.Branch.Name, nil
+	case *gitea.PullRequest:
+		return "refs/heads/" + m.Base.Ref, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -981,6 +1062,23 @@ func ChangesetEventKindFor(e interface{}) (ChangesetEventKind, error) {
 		return ChangesetEventKindGitLabReopened, nil
 	case *gitlab.MergeRequestMergedEvent:
 		return ChangesetEventKindGitLabMerged, nil
+
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		return ChangesetEventKindBitbucketCloudPullRequestApproved, nil
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		return ChangesetEventKindBitbucketCloudPullRequestUnapproved, nil
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		return ChangesetEventKindBitbucketCloudPullRequestChangesRequestCreated, nil
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		return ChangesetEventKindBitbucketCloudPullRequestChangesRequestRemoved, nil
+	case *bitbucketcloud.PullRequestFulfilledEvent:
+		return ChangesetEventKindBitbucketCloudPullRequestFulfilled, nil
+	case *bitbucketcloud.PullRequestRejectedEvent:
+		return ChangesetEventKindBitbucketCloudPullRequestRejected, nil
+	case *bitbucketcloud.RepoCommitStatusCreatedEvent:
+		return ChangesetEventKindBitbucketCloudRepoCommitStatusCreated, nil
+	case *bitbucketcloud.RepoCommitStatusUpdatedEvent:
+		return ChangesetEventKindBitbucketCloudRepoCommitStatusUpdated, nil
 	}
 
 	return ChangesetEventKindInvalid, errors.Errorf("unknown changeset event kind for %T", e)
@@ -1061,6 +1159,25 @@ func NewChangesetEventMetadata(k ChangesetEventKind) (interface{}, error) {
 		case ChangesetEventKindGitLabReopened:
 			return new(gitlab.MergeRequestReopenedEvent), nil
 		}
+	case strings.HasPrefix(string(k), "bitbucketcloud"):
+		switch k {
+		case ChangesetEventKindBitbucketCloudPullRequestApproved:
+			return new(bitbucketcloud.PullRequestApprovedEvent), nil
+		case ChangesetEventKindBitbucketCloudPullRequestUnapproved:
+			return new(bitbucketcloud.PullRequestUnapprovedEvent), nil
+		case ChangesetEventKindBitbucketCloudPullRequestChangesRequestCreated:
+			return new(bitbucketcloud.PullRequestChangesRequestCreatedEvent), nil
+		case ChangesetEventKindBitbucketCloudPullRequestChangesRequestRemoved:
+			return new(bitbucketcloud.PullRequestChangesRequestRemovedEvent), nil
+		case ChangesetEventKindBitbucketCloudPullRequestFulfilled:
+			return new(bitbucketcloud.PullRequestFulfilledEvent), nil
+		case ChangesetEventKindBitbucketCloudPullRequestRejected:
+			return new(bitbucketcloud.PullRequestRejectedEvent), nil
+		case ChangesetEventKindBitbucketCloudRepoCommitStatusCreated:
+			return new(bitbucketcloud.RepoCommitStatusCreatedEvent), nil
+		case ChangesetEventKindBitbucketCloudRepoCommitStatusUpdated:
+			return new(bitbucketcloud.RepoCommitStatusUpdatedEvent), nil
+		}
 	}
 	return nil, errors.Errorf("unknown changeset event kind %q", k)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
@@ -78,6 +79,15 @@ const (
 	ChangesetEventKindGitLabMarkWorkInProgress   ChangesetEventKind = "gitlab:mark_wip"
 	ChangesetEventKindGitLabUnmarkWorkInProgress ChangesetEventKind = "gitlab:unmark_wip"
 
+	ChangesetEventKindBitbucketCloudPullRequestApproved              ChangesetEventKind = "bitbucketcloud:pullrequest:approved"
+	ChangesetEventKindBitbucketCloudPullRequestChangesRequestCreated ChangesetEventKind = "bitbucketcloud:pullrequest:changes_request_created"
+	ChangesetEventKindBitbucketCloudPullRequestChangesRequestRemoved ChangesetEventKind = "bitbucketcloud:pullrequest:changes_request_removed"
+	ChangesetEventKindBitbucketCloudPullRequestFulfilled             ChangesetEventKind = "bitbucketcloud:pullrequest:fulfilled"
+	ChangesetEventKindBitbucketCloudPullRequestRejected              ChangesetEventKind = "bitbucketcloud:pullrequest:rejected"
+	ChangesetEventKindBitbucketCloudPullRequestUnapproved            ChangesetEventKind = "bitbucketcloud:pullrequest:unapproved"
+	ChangesetEventKindBitbucketCloudRepoCommitStatusCreated          ChangesetEventKind = "bitbucketcloud:repo:commit_status_created"
+	ChangesetEventKindBitbucketCloudRepoCommitStatusUpdated          ChangesetEventKind = "bitbucketcloud:repo:commit_status_updated"
+
 	ChangesetEventKindInvalid ChangesetEventKind = "invalid"
 )
 
@@ -121,6 +131,18 @@ func (e *ChangesetEvent) ReviewAuthor() string {
 	case *gitlab.ReviewUnapprovedEvent:
 		return meta.Author.Username
 
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		return meta.Approval.User.Nickname
+
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		return meta.Approval.User.Nickname
+
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		return meta.ChangesRequest.User.Nickname
+
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		return meta.ChangesRequest.User.Nickname
+
 	default:
 		return ""
 	}
@@ -130,9 +152,13 @@ func (e *ChangesetEvent) ReviewAuthor() string {
 func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 	switch e.Kind {
 	case ChangesetEventKindBitbucketServerApproved,
-		ChangesetEventKindGitLabApproved:
+		ChangesetEventKindGitLabApproved,
+		ChangesetEventKindBitbucketCloudPullRequestApproved:
 		return ChangesetReviewStateApproved, nil
 
+	case ChangesetEventKindBitbucketCloudPullRequestChangesRequestCreated:
+		return ChangesetReviewStateChangesRequested, nil
+
 	// BitbucketServer's "REVIEWED" activity is created when someone clicks
 	// the "Needs work" button in the UI, which is why we map it to "Changes Requested"
 	case ChangesetEventKindBitbucketServerReviewed:
@@ -155,7 +181,9 @@ func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 	case ChangesetEventKindGitHubReviewDismissed,
 		ChangesetEventKindBitbucketServerUnapproved,
 		ChangesetEventKindBitbucketServerDismissed,
-		ChangesetEventKindGitLabUnapproved:
+		ChangesetEventKindGitLabUnapproved,
+		ChangesetEventKindBitbucketCloudPullRequestUnapproved,
+		ChangesetEventKindBitbucketCloudPullRequestChangesRequestRemoved:
 		return ChangesetReviewStateDismissed, nil
 
 	default:
@@ -240,6 +268,22 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		// fall back to the event record we created when we received the
 		// webhook.
 		t = e.CreatedAt
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		t = ev.Approval.Date
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		t = ev.Approval.Date
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		t = ev.ChangesRequest.Date
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		t = ev.ChangesRequest.Date
+	case *bitbucketcloud.PullRequestFulfilledEvent:
+		t = ev.PullRequest.UpdatedOn
+	case *bitbucketcloud.PullRequestRejectedEvent:
+		t = ev.PullRequest.UpdatedOn
+	case *bitbucketcloud.RepoCommitStatusCreatedEvent:
+		t = ev.CommitStatus.UpdatedOn
+	case *bitbucketcloud.RepoCommitStatusUpdatedEvent:
+		t = ev.CommitStatus.UpdatedOn
 	}
 
 	return t
@@ -722,6 +766,25 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) error {
 		// We always get the full event, so safe to replace it
 		*e = *o
 
+	// Bitbucket Cloud webhook payloads always contain the full event, so it's
+	// safe to replace them wholesale.
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		*e = *o.Metadata.(*bitbucketcloud.PullRequestApprovedEvent)
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		*e = *o.Metadata.(*bitbucketcloud.PullRequestUnapprovedEvent)
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		*e = *o.Metadata.(*bitbucketcloud.PullRequestChangesRequestCreatedEvent)
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		*e = *o.Metadata.(*bitbucketcloud.PullRequestChangesRequestRemovedEvent)
+	case *bitbucketcloud.PullRequestFulfilledEvent:
+		*e = *o.Metadata.(*bitbucketcloud.PullRequestFulfilledEvent)
+	case *bitbucketcloud.PullRequestRejectedEvent:
+		*e = *o.Metadata.(*bitbucketcloud.PullRequestRejectedEvent)
+	case *bitbucketcloud.RepoCommitStatusCreatedEvent:
+		*e = *o.Metadata.(*bitbucketcloud.RepoCommitStatusCreatedEvent)
+	case *bitbucketcloud.RepoCommitStatusUpdatedEvent:
+		*e = *o.Metadata.(*bitbucketcloud.RepoCommitStatusUpdatedEvent)
+
 	default:
 		return errors.Errorf("unknown changeset event metadata %T", e)
 	}
@@ -1,13 +1,15 @@
 package types
 
 import (
+	"encoding/json"
 	"io"
 	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
-	jsonutil "github.com/sourcegraph/batch-change-utils/json"
+	"github.com/hashicorp/go-multierror"
 	"github.com/sourcegraph/go-diff/diff"
+	"github.com/xeipuuv/gojsonschema"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -95,6 +97,41 @@ func (cs *ChangesetSpec) DiffStat() diff.Stat {
 	}
 }
 
+// ChangedFiles returns the paths of the files changed by the Diff of the
+// ChangesetSpecDescription. Renamed files are included with both their old
+// and new path.
+func (cs *ChangesetSpec) ChangedFiles() ([]string, error) {
+	if cs.Spec.IsImportingExisting() {
+		return nil, nil
+	}
+
+	d, err := cs.Spec.Diff()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	reader := diff.NewMultiFileDiffReader(strings.NewReader(d))
+	for {
+		fileDiff, err := reader.ReadFile()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range []string{fileDiff.OrigName, fileDiff.NewName} {
+			if name == "/dev/null" || (len(paths) > 0 && paths[len(paths)-1] == name) {
+				continue
+			}
+			paths = append(paths, name)
+		}
+	}
+
+	return paths, nil
+}
+
 // ErrHeadBaseMismatch is returned by (*ChangesetSpec).UnmarshalValidate() if
 // the head and base repositories do not match (a case which we do not support
 // yet).
@@ -103,8 +140,14 @@ var ErrHeadBaseMismatch = errors.New("headRepository does not match baseReposito
 // UnmarshalValidate unmarshals the RawSpec into Spec and validates it against
 // the ChangesetSpec schema and does additional semantic validation.
 func (cs *ChangesetSpec) UnmarshalValidate() error {
-	err := jsonutil.UnmarshalValidate(schema.ChangesetSpecSchemaJSON, []byte(cs.RawSpec), &cs.Spec)
-	if err != nil {
+	var errs *multierror.Error
+	if err := validateChangesetSpecSchema([]byte(cs.RawSpec)); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := json.Unmarshal([]byte(cs.RawSpec), &cs.Spec); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := errs.ErrorOrNil(); err != nil {
 		return err
 	}
 
@@ -117,6 +160,34 @@ func (cs *ChangesetSpec) UnmarshalValidate() error {
 	return nil
 }
 
+// validateChangesetSpecSchema validates the given input against the
+// ChangesetSpec schema. The schema refers to definitions it shares with the
+// BatchSpec schema, so both are loaded.
+func validateChangesetSpecSchema(input []byte) error {
+	sl := gojsonschema.NewSchemaLoader()
+	if err := sl.AddSchemas(gojsonschema.NewStringLoader(schema.BatchSpecSchemaJSON)); err != nil {
+		return errors.Wrap(err, "failed to load BatchSpec JSON schema")
+	}
+	sc, err := sl.Compile(gojsonschema.NewStringLoader(schema.ChangesetSpecSchemaJSON))
+	if err != nil {
+		return errors.Wrap(err, "failed to compile JSON schema")
+	}
+
+	res, err := sc.Validate(gojsonschema.NewBytesLoader(input))
+	if err != nil {
+		return errors.Wrap(err, "failed to validate input against schema")
+	}
+
+	var errs *multierror.Error
+	for _, err := range res.Errors() {
+		// Remove `(root): ` from error formatting since these errors are
+		// presented to users.
+		errs = multierror.Append(errs, errors.New(strings.TrimPrefix(err.String(), "(root): ")))
+	}
+
+	return errs.ErrorOrNil()
+}
+
 // ChangesetSpecTTL specifies the TTL of ChangesetSpecs that haven't been
 // attached to a BatchSpec.
 // It's lower than BatchSpecTTL because ChangesetSpecs should be attached to
@@ -4,6 +4,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/graph-gophers/graphql-go"
 
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 	"github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
@@ -26,6 +27,45 @@ type ChangesetSpecDescription struct {
 	Commits []GitCommitDescription `json:"commits,omitempty"`
 
 	Published batches.PublishedValue `json:"published,omitempty"`
+
+	DependsOn []ChangesetDependency `json:"dependsOn,omitempty"`
+
+	Reviewers *ChangesetReviewers `json:"reviewers,omitempty"`
+}
+
+// ChangesetDependency references a changeset that has to be merged before the
+// changeset described by a ChangesetSpecDescription is published. It either
+// references the changeset created by a batch change for a branch, or a
+// changeset tracked on Sourcegraph by its external ID.
+type ChangesetDependency struct {
+	Repository string `json:"repository" yaml:"repository"`
+
+	Branch string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	// BatchChange is the name of the batch change that created the changeset
+	// for Branch. It has to be in the same namespace as the batch change of
+	// the dependent changeset, which is also the default.
+	BatchChange string `json:"batchChange,omitempty" yaml:"batchChange,omitempty"`
+
+	ExternalID string `json:"externalID,omitempty" yaml:"externalID,omitempty"`
+}
+
+// HeadRef returns the full name of the Git ref of Branch.
+func (d ChangesetDependency) HeadRef() string {
+	if d.Branch == "" {
+		return ""
+	}
+	return git.EnsureRefPrefix(d.Branch)
+}
+
+// ChangesetReviewers describes the reviewers that are requested when the
+// changeset described by a ChangesetSpecDescription is published.
+type ChangesetReviewers struct {
+	// CodeOwners requests reviews from the owners of the changed files, as
+	// defined in the CODEOWNERS file of the repository.
+	CodeOwners bool `json:"codeOwners,omitempty" yaml:"codeOwners,omitempty"`
+	// Fallback are the owners, in CODEOWNERS syntax, whose reviews are
+	// requested when no code owner matches.
+	Fallback []string `json:"fallback,omitempty" yaml:"fallback,omitempty"`
 }
 
 // Type returns the ChangesetSpecDescriptionType of the ChangesetSpecDescription.
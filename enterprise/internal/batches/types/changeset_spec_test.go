@@ -111,6 +111,67 @@ func TestChangesetSpecUnmarshalValidate(t *testing.T) {
 			}`,
 			err: "2 errors occurred:\n\t* Must validate one and only one schema (oneOf)\n\t* commits: Array must have at most 1 items\n\n",
 		},
+		{
+			name: "valid dependencies in GitBranchChangesetDescription",
+			rawSpec: `{
+				"baseRepository": "graphql-id",
+				"baseRef": "refs/heads/master",
+				"baseRev": "d34db33f",
+				"headRef": "refs/heads/my-branch",
+				"headRepository": "graphql-id",
+				"title": "my title",
+				"body": "my body",
+				"commits": [{
+				  "message": "commit message",
+				  "diff": "the diff",
+				  "authorName": "Mary McButtons",
+				  "authorEmail": "mary@example.com"
+				}],
+				"dependsOn": [
+				  {"repository": "github.com/sourcegraph/lib", "branch": "api-v2", "batchChange": "lib-api-v2"},
+				  {"repository": "github.com/sourcegraph/lib", "externalID": "12"}
+				]
+			}`,
+		},
+		{
+			name: "dependency without branch or external ID",
+			rawSpec: `{
+				"baseRepository": "graphql-id",
+				"baseRef": "refs/heads/master",
+				"baseRev": "d34db33f",
+				"headRef": "refs/heads/my-branch",
+				"headRepository": "graphql-id",
+				"title": "my title",
+				"body": "my body",
+				"commits": [{
+				  "message": "commit message",
+				  "diff": "the diff",
+				  "authorName": "Mary McButtons",
+				  "authorEmail": "mary@example.com"
+				}],
+				"dependsOn": [{"repository": "github.com/sourcegraph/lib"}]
+			}`,
+			err: "3 errors occurred:\n\t* Must validate one and only one schema (oneOf)\n\t* dependsOn.0: Must validate one and only one schema (oneOf)\n\t* dependsOn.0: branch is required\n\n",
+		},
+		{
+			name: "valid reviewers in GitBranchChangesetDescription",
+			rawSpec: `{
+				"baseRepository": "graphql-id",
+				"baseRef": "refs/heads/master",
+				"baseRev": "d34db33f",
+				"headRef": "refs/heads/my-branch",
+				"headRepository": "graphql-id",
+				"title": "my title",
+				"body": "my body",
+				"commits": [{
+				  "message": "commit message",
+				  "diff": "the diff",
+				  "authorName": "Mary McButtons",
+				  "authorEmail": "mary@example.com"
+				}],
+				"reviewers": {"codeOwners": true, "fallback": ["@sourcegraph/batchers"]}
+			}`,
+		},
 	}
 
 	for _, tc := range tests {
@@ -126,3 +187,35 @@ func TestChangesetSpecUnmarshalValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestChangesetSpecChangedFiles(t *testing.T) {
+	spec := &ChangesetSpec{Spec: &ChangesetSpecDescription{
+		Commits: []GitCommitDescription{{Diff: `diff README.md README.md
+--- README.md
++++ README.md
+@@ -1 +1 @@
+-Hello
++Hello World
+diff old.go new.go
+--- old.go
++++ new.go
+@@ -1 +1 @@
+-package old
++package new
+diff docs/removed.md docs/removed.md
+--- docs/removed.md
++++ /dev/null
+@@ -1 +0,0 @@
+-Removed
+`}},
+	}}
+
+	have, err := spec.ChangedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"README.md", "old.go", "new.go", "docs/removed.md"}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatalf("wrong changed files (-want +got):\n%s", diff)
+	}
+}
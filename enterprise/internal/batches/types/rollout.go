@@ -0,0 +1,204 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gobwas/glob"
+)
+
+// DefaultRolloutMinSuccessRatio is the success ratio required to publish the
+// next rollout wave if the batch spec doesn't specify one.
+const DefaultRolloutMinSuccessRatio = 1.0
+
+// BatchChangeRollout is the state of the staged publication of the changesets
+// of a batch change whose batch spec declares rollout waves.
+type BatchChangeRollout struct {
+	BatchChangeID int64
+
+	// CurrentWave is the index of the last wave whose changesets can be
+	// published.
+	CurrentWave int32
+
+	PausedAt    time.Time
+	PauseReason string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Paused returns whether the rollout is paused.
+func (r *BatchChangeRollout) Paused() bool { return !r.PausedAt.IsZero() }
+
+// Holds returns whether the rollout holds back the publication of the given
+// changeset. Changesets that haven't been assigned to a wave yet are held
+// until the rollout worker assigns them.
+func (r *BatchChangeRollout) Holds(c *Changeset) bool {
+	return r.Paused() || c.RolloutWave == nil || *c.RolloutWave > r.CurrentWave
+}
+
+// RolloutCandidate is a changeset that has not been assigned to a rollout
+// wave yet.
+type RolloutCandidate struct {
+	ChangesetID int64
+	RepoName    string
+}
+
+// RolloutWaveStats are the outcomes of the changesets in a rollout wave.
+type RolloutWaveStats struct {
+	Wave int32
+
+	// Changesets is the number of changesets in the wave, including those
+	// that are not published because their spec says so.
+	Changesets int32
+	// Succeeded is the number of changesets that have been merged or passed
+	// their checks.
+	Succeeded int32
+	// Failed is the number of changesets that failed to publish, failed their
+	// checks, or have been closed without being merged.
+	Failed int32
+	// Pending is the number of changesets that are neither, including those
+	// still held back by the rollout.
+	Pending int32
+}
+
+// RolloutDecision is the outcome of evaluating the gate of the next rollout
+// wave.
+type RolloutDecision string
+
+const (
+	RolloutDecisionWait    RolloutDecision = "WAIT"
+	RolloutDecisionAdvance RolloutDecision = "ADVANCE"
+	RolloutDecisionPause   RolloutDecision = "PAUSE"
+)
+
+// SuccessRatio returns the ratio of changesets in the released waves that must
+// have succeeded before the next wave is released.
+func (r *BatchSpecRollout) SuccessRatio() float64 {
+	if r.MinSuccessRatio == nil {
+		return DefaultRolloutMinSuccessRatio
+	}
+	return *r.MinSuccessRatio
+}
+
+// WaveCount returns the number of waves of the rollout. Unless the last
+// declared wave selects all remaining changesets, the changesets that are not
+// selected by any wave are published in an additional, final wave.
+func (r *BatchSpecRollout) WaveCount() int32 {
+	if len(r.Waves) > 0 && r.Waves[len(r.Waves)-1].selectsRest() {
+		return int32(len(r.Waves))
+	}
+	return int32(len(r.Waves) + 1)
+}
+
+// AssignWaves assigns the given candidates to waves, in order. assigned holds
+// the number of changesets already assigned to each wave and total is the
+// number of changesets of the batch change, including the candidates.
+func (r *BatchSpecRollout) AssignWaves(assigned map[int32]int32, total int, candidates []RolloutCandidate) (map[int64]int32, error) {
+	globs := make([][]glob.Glob, len(r.Waves))
+	for i, w := range r.Waves {
+		for _, pattern := range w.Repositories {
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "compiling repository pattern %q", pattern)
+			}
+			globs[i] = append(globs[i], g)
+		}
+	}
+
+	sizes := make(map[int32]int32, len(assigned))
+	for wave, n := range assigned {
+		sizes[wave] = n
+	}
+
+	waves := make(map[int64]int32, len(candidates))
+	for _, c := range candidates {
+		wave := r.WaveCount() - 1
+	waves:
+		for i, w := range r.Waves {
+			switch {
+			case len(globs[i]) > 0:
+				for _, g := range globs[i] {
+					if g.Match(c.RepoName) {
+						wave = int32(i)
+						break waves
+					}
+				}
+
+			case w.selectsRest():
+				wave = int32(i)
+				break waves
+
+			case sizes[int32(i)] < w.size(total):
+				wave = int32(i)
+				break waves
+			}
+		}
+
+		waves[c.ChangesetID] = wave
+		sizes[wave]++
+	}
+
+	return waves, nil
+}
+
+// Evaluate decides, based on the stats of the waves released so far, whether
+// the next wave can be released. If the rollout should be paused, the reason
+// is returned as well.
+func (r *BatchSpecRollout) Evaluate(released []*RolloutWaveStats) (RolloutDecision, string) {
+	var succeeded, failed, pending int32
+	for _, s := range released {
+		succeeded += s.Succeeded
+		failed += s.Failed
+		pending += s.Pending
+	}
+
+	total := succeeded + failed + pending
+	if total == 0 {
+		return RolloutDecisionAdvance, ""
+	}
+
+	required := r.SuccessRatio()
+	if float64(succeeded)/float64(total) >= required {
+		return RolloutDecisionAdvance, ""
+	}
+
+	// Pause once the required ratio can't be reached anymore, even if all
+	// pending changesets succeed.
+	if r.PauseOnFailure && float64(succeeded+pending)/float64(total) < required {
+		return RolloutDecisionPause, fmt.Sprintf(
+			"%d of %d changesets in the published waves failed, so the required success ratio of %g%% can't be reached.",
+			failed,
+			total,
+			required*100,
+		)
+	}
+
+	return RolloutDecisionWait, ""
+}
+
+func (r *BatchSpecRollout) validate() error {
+	for i, w := range r.Waves {
+		for _, pattern := range w.Repositories {
+			if _, err := glob.Compile(pattern); err != nil {
+				return errors.Wrapf(err, "rollout wave %d: invalid repository pattern %q", i, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+func (w BatchSpecRolloutWave) selectsRest() bool {
+	return w.Count == 0 && w.Percentage == 0 && len(w.Repositories) == 0
+}
+
+// size returns the number of changesets a count or percentage wave selects out
+// of total changesets.
+func (w BatchSpecRolloutWave) size(total int) int32 {
+	if w.Count > 0 {
+		return int32(w.Count)
+	}
+	return int32(math.Ceil(float64(total) * w.Percentage / 100))
+}
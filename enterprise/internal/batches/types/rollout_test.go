@@ -0,0 +1,148 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBatchSpecRollout_AssignWaves(t *testing.T) {
+	candidates := func(repos ...string) []RolloutCandidate {
+		cs := make([]RolloutCandidate, 0, len(repos))
+		for i, repo := range repos {
+			cs = append(cs, RolloutCandidate{ChangesetID: int64(i + 1), RepoName: repo})
+		}
+		return cs
+	}
+
+	tests := map[string]struct {
+		rollout    *BatchSpecRollout
+		assigned   map[int32]int32
+		total      int
+		candidates []RolloutCandidate
+		want       map[int64]int32
+	}{
+		"percentages and rest": {
+			rollout: &BatchSpecRollout{Waves: []BatchSpecRolloutWave{
+				{Percentage: 10},
+				{Percentage: 25},
+				{},
+			}},
+			total:      8,
+			candidates: candidates("a", "b", "c", "d", "e", "f", "g", "h"),
+			want:       map[int64]int32{1: 0, 2: 1, 3: 1, 4: 2, 5: 2, 6: 2, 7: 2, 8: 2},
+		},
+		"implicit final wave": {
+			rollout: &BatchSpecRollout{Waves: []BatchSpecRolloutWave{
+				{Count: 2},
+			}},
+			total:      3,
+			candidates: candidates("a", "b", "c"),
+			want:       map[int64]int32{1: 0, 2: 0, 3: 1},
+		},
+		"repositories": {
+			rollout: &BatchSpecRollout{Waves: []BatchSpecRolloutWave{
+				{Repositories: []string{"github.com/canary/*"}},
+				{Count: 1},
+			}},
+			total:      3,
+			candidates: candidates("github.com/main/a", "github.com/canary/b", "github.com/main/c"),
+			want:       map[int64]int32{1: 1, 2: 0, 3: 2},
+		},
+		"already assigned": {
+			rollout: &BatchSpecRollout{Waves: []BatchSpecRolloutWave{
+				{Count: 1},
+				{Count: 1},
+			}},
+			assigned:   map[int32]int32{0: 1},
+			total:      3,
+			candidates: candidates("b", "c"),
+			want:       map[int64]int32{1: 1, 2: 2},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, err := tc.rollout.AssignWaves(tc.assigned, tc.total, tc.candidates)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf("wrong waves (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBatchSpecRollout_Evaluate(t *testing.T) {
+	ratio := 0.5
+
+	tests := map[string]struct {
+		rollout    *BatchSpecRollout
+		stats      []*RolloutWaveStats
+		want       RolloutDecision
+		wantReason string
+	}{
+		"no changesets": {
+			rollout: &BatchSpecRollout{},
+			want:    RolloutDecisionAdvance,
+		},
+		"all succeeded": {
+			rollout: &BatchSpecRollout{},
+			stats:   []*RolloutWaveStats{{Succeeded: 2}, {Succeeded: 3}},
+			want:    RolloutDecisionAdvance,
+		},
+		"pending": {
+			rollout: &BatchSpecRollout{PauseOnFailure: true},
+			stats:   []*RolloutWaveStats{{Succeeded: 2, Pending: 1}},
+			want:    RolloutDecisionWait,
+		},
+		"ratio reached despite pending": {
+			rollout: &BatchSpecRollout{MinSuccessRatio: &ratio},
+			stats:   []*RolloutWaveStats{{Succeeded: 2, Failed: 1, Pending: 1}},
+			want:    RolloutDecisionAdvance,
+		},
+		"failed without pausing": {
+			rollout: &BatchSpecRollout{},
+			stats:   []*RolloutWaveStats{{Succeeded: 2, Failed: 1}},
+			want:    RolloutDecisionWait,
+		},
+		"failed": {
+			rollout:    &BatchSpecRollout{PauseOnFailure: true},
+			stats:      []*RolloutWaveStats{{Succeeded: 2, Failed: 1, Pending: 1}},
+			want:       RolloutDecisionPause,
+			wantReason: "1 of 4 changesets in the published waves failed, so the required success ratio of 100% can't be reached.",
+		},
+		"failed but ratio still reachable": {
+			rollout: &BatchSpecRollout{MinSuccessRatio: &ratio, PauseOnFailure: true},
+			stats:   []*RolloutWaveStats{{Succeeded: 1, Failed: 2, Pending: 1}},
+			want:    RolloutDecisionWait,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, reason := tc.rollout.Evaluate(tc.stats)
+			if have != tc.want {
+				t.Errorf("wrong decision: want=%s have=%s", tc.want, have)
+			}
+			if reason != tc.wantReason {
+				t.Errorf("wrong reason: want=%q have=%q", tc.wantReason, reason)
+			}
+		})
+	}
+}
+
+func TestBatchSpecRollout_WaveCount(t *testing.T) {
+	for _, tc := range []struct {
+		waves []BatchSpecRolloutWave
+		want  int32
+	}{
+		{waves: []BatchSpecRolloutWave{{Count: 1}}, want: 2},
+		{waves: []BatchSpecRolloutWave{{Count: 1}, {}}, want: 2},
+	} {
+		if have := (&BatchSpecRollout{Waves: tc.waves}).WaveCount(); have != tc.want {
+			t.Errorf("wrong wave count for %+v: want=%d have=%d", tc.waves, tc.want, have)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gobwas/glob"
+)
+
+// WorkspaceTemplateContext is the data that the `${{ ... }}` templates in the
+// steps and the changesetTemplate of a batch spec that is executed
+// server-side can refer to. The step outputs and results that src-cli
+// provides are not available.
+type WorkspaceTemplateContext struct {
+	BatchChangeName        string
+	BatchChangeDescription string
+
+	RepositoryName string
+	// Branch is the short name of the branch the changes are proposed to.
+	Branch string
+	// Path is the path of the workspace, or empty for the repository root.
+	Path string
+}
+
+var workspaceTemplateFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"split":   strings.Split,
+	"replace": strings.ReplaceAll,
+	"matches": func(in, pattern string) (bool, error) {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		return g.Match(in), nil
+	},
+}
+
+// Render renders the given template. Referring to anything that isn't
+// available server-side is an error.
+func (c *WorkspaceTemplateContext) Render(name, tmpl string) (string, error) {
+	if !strings.Contains(tmpl, "${{") {
+		return tmpl, nil
+	}
+
+	// Like in src-cli, the variables are functions, so that they can be
+	// referred to without a leading dot.
+	vars := template.FuncMap{
+		"batch_change": func() map[string]interface{} {
+			return map[string]interface{}{
+				"name":        c.BatchChangeName,
+				"description": c.BatchChangeDescription,
+			}
+		},
+		"repository": func() map[string]interface{} {
+			return map[string]interface{}{
+				"name":   c.RepositoryName,
+				"branch": c.Branch,
+			}
+		},
+		"steps": func() map[string]interface{} {
+			return map[string]interface{}{
+				"path": c.Path,
+			}
+		},
+	}
+
+	t, err := template.New(name).
+		Delims("${{", "}}").
+		Option("missingkey=error").
+		Funcs(workspaceTemplateFuncs).
+		Funcs(vars).
+		Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing template %s", name)
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, nil); err != nil {
+		return "", errors.Wrapf(err, "rendering template %s", name)
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,62 @@
+package types
+
+import "testing"
+
+func TestWorkspaceTemplateContext_Render(t *testing.T) {
+	c := &WorkspaceTemplateContext{
+		BatchChangeName:        "hello-world",
+		BatchChangeDescription: "Add Hello World",
+		RepositoryName:         "github.com/sourcegraph/src-cli",
+		Branch:                 "main",
+		Path:                   "projects/api",
+	}
+
+	tests := map[string]struct {
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		"no template": {
+			tmpl: "echo {{ not a template }}",
+			want: "echo {{ not a template }}",
+		},
+		"variables": {
+			tmpl: "${{ batch_change.name }}: ${{ batch_change.description }} in ${{ repository.name }}@${{ repository.branch }}/${{ steps.path }}",
+			want: "hello-world: Add Hello World in github.com/sourcegraph/src-cli@main/projects/api",
+		},
+		"functions": {
+			tmpl: `${{ join (split steps.path "/") "-" }} ${{ replace repository.name "/" "_" }} ${{ matches repository.name "github.com/*" }}`,
+			want: "projects-api github.com_sourcegraph_src-cli true",
+		},
+		"unknown key": {
+			tmpl:    "${{ repository.owner }}",
+			wantErr: true,
+		},
+		"outputs are not available": {
+			tmpl:    "${{ outputs.foo }}",
+			wantErr: true,
+		},
+		"previous step is not available": {
+			tmpl:    "${{ previous_step.stdout }}",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, err := c.Render(name, tc.tmpl)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", have)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have != tc.want {
+				t.Errorf("wrong result. want=%q, have=%q", tc.want, have)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type BitbucketCloudWebhook struct {
+	*Webhook
+}
+
+func NewBitbucketCloudWebhook(store *store.Store) *BitbucketCloudWebhook {
+	return &BitbucketCloudWebhook{&Webhook{store, extsvc.TypeBitbucketCloud}}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *BitbucketCloudWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Look up the external service.
+	extSvc, err := h.getExternalServiceFromRawID(r.Context(), r.FormValue(extsvc.IDParam))
+	if err == errExternalServiceNotFound {
+		respond(w, http.StatusUnauthorized, err)
+		return
+	} else if err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "getting external service"))
+		return
+	}
+
+	// 🚨 SECURITY: Bitbucket Cloud doesn't sign its webhook payloads, so we
+	// require the secret from the external service configuration to be part of
+	// the webhook URL. If no secret is configured, or it doesn't match, we
+	// return a 401 to the client.
+	if ok, err := validateBitbucketCloudSecret(extSvc, r.URL.Query().Get("secret")); err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "validating the shared secret"))
+		return
+	} else if !ok {
+		respond(w, http.StatusUnauthorized, "shared secret is incorrect")
+		return
+	}
+
+	if r.Body == nil {
+		respond(w, http.StatusBadRequest, "missing request body")
+		return
+	}
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "reading payload"))
+		return
+	}
+
+	event, err := bitbucketcloud.ParseWebhookEvent(bitbucketcloud.WebhookEventKey(r), payload)
+	if err != nil {
+		respond(w, http.StatusBadRequest, errors.Wrap(err, "parsing payload"))
+		return
+	}
+
+	if err := h.handleEvent(r.Context(), extSvc, event); err != nil {
+		respond(w, err.code, err)
+	} else {
+		respond(w, http.StatusNoContent, nil)
+	}
+}
+
+// getExternalServiceFromRawID retrieves the Bitbucket Cloud external service
+// matching the given raw ID.
+//
+// On failure, errExternalServiceNotFound is returned if the ID doesn't match
+// any Bitbucket Cloud service.
+func (h *BitbucketCloudWebhook) getExternalServiceFromRawID(ctx context.Context, raw string) (*types.ExternalService, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing the raw external service ID")
+	}
+
+	es, err := h.Store.ExternalServices().List(ctx, database.ExternalServicesListOptions{
+		IDs:   []int64{id},
+		Kinds: []string{extsvc.KindBitbucketCloud},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing external services")
+	}
+
+	if len(es) == 0 {
+		return nil, errExternalServiceNotFound
+	} else if len(es) > 1 {
+		// This _really_ shouldn't happen, since we provided only one ID above.
+		return nil, errors.New("too many external services found")
+	}
+
+	return es[0], nil
+}
+
+// handleEvent dispatches based on the event type to perform whatever changeset
+// action is appropriate for that event.
+func (h *BitbucketCloudWebhook) handleEvent(ctx context.Context, extSvc *types.ExternalService, event interface{}) *httpError {
+	log15.Debug("Bitbucket Cloud webhook received", "type", fmt.Sprintf("%T", event))
+
+	esID, err := extractExternalServiceID(extSvc)
+	if err != nil {
+		return &httpError{
+			code: http.StatusInternalServerError,
+			err:  err,
+		}
+	}
+
+	switch e := event.(type) {
+	case *bitbucketcloud.PullRequestApprovedEvent:
+		return h.upsertPullRequestEvent(ctx, esID, &e.PullRequestEvent, e)
+	case *bitbucketcloud.PullRequestUnapprovedEvent:
+		return h.upsertPullRequestEvent(ctx, esID, &e.PullRequestEvent, e)
+	case *bitbucketcloud.PullRequestChangesRequestCreatedEvent:
+		return h.upsertPullRequestEvent(ctx, esID, &e.PullRequestEvent, e)
+	case *bitbucketcloud.PullRequestChangesRequestRemovedEvent:
+		return h.upsertPullRequestEvent(ctx, esID, &e.PullRequestEvent, e)
+	case *bitbucketcloud.PullRequestFulfilledEvent:
+		return h.upsertPullRequestEvent(ctx, esID, &e.PullRequestEvent, e)
+	case *bitbucketcloud.PullRequestRejectedEvent:
+		return h.upsertPullRequestEvent(ctx, esID, &e.PullRequestEvent, e)
+
+	// Updates and comments don't map to changeset events, but they may change
+	// the title, body, branches or review state of the pull request, so we
+	// ask repo-updater to resync the changeset instead.
+	case *bitbucketcloud.PullRequestUpdatedEvent:
+		return h.enqueueChangesetSync(ctx, esID, &e.PullRequestEvent)
+	case *bitbucketcloud.PullRequestCommentCreatedEvent:
+		return h.enqueueChangesetSync(ctx, esID, &e.PullRequestEvent)
+
+	case *bitbucketcloud.RepoCommitStatusCreatedEvent:
+		return h.upsertCommitStatusEvent(ctx, esID, &e.RepoCommitStatusEvent, e)
+	case *bitbucketcloud.RepoCommitStatusUpdatedEvent:
+		return h.upsertCommitStatusEvent(ctx, esID, &e.RepoCommitStatusEvent, e)
+	}
+
+	// We don't want to return a non-2XX status code and have Bitbucket Cloud
+	// retry the webhook, so we'll log that we don't know what to do and return
+	// 204.
+	log15.Debug("cannot handle Bitbucket Cloud webhook event of unknown type", "type", fmt.Sprintf("%T", event))
+	return nil
+}
+
+func (h *BitbucketCloudWebhook) upsertPullRequestEvent(ctx context.Context, esID string, e *bitbucketcloud.PullRequestEvent, ev keyer) *httpError {
+	if err := h.upsertChangesetEvent(ctx, esID, bitbucketCloudToPR(e), ev); err != nil {
+		return &httpError{
+			code: http.StatusInternalServerError,
+			err:  errors.Wrap(err, "upserting changeset event"),
+		}
+	}
+	return nil
+}
+
+func (h *BitbucketCloudWebhook) enqueueChangesetSync(ctx context.Context, esID string, e *bitbucketcloud.PullRequestEvent) *httpError {
+	pr := bitbucketCloudToPR(e)
+	repo, err := h.getRepoForPR(ctx, h.Store, pr, esID)
+	if err != nil {
+		log15.Debug("Webhook event could not be matched to repo", "err", err)
+		return nil
+	}
+
+	c, err := h.Store.GetChangeset(ctx, store.GetChangesetOpts{
+		RepoID:              repo.ID,
+		ExternalID:          strconv.FormatInt(pr.ID, 10),
+		ExternalServiceType: h.ServiceType,
+	})
+	if err != nil {
+		if err == store.ErrNoResults {
+			return nil
+		}
+		return &httpError{
+			code: http.StatusInternalServerError,
+			err:  errors.Wrap(err, "getting changeset"),
+		}
+	}
+
+	if err := repoupdater.DefaultClient.EnqueueChangesetSync(ctx, []int64{c.ID}); err != nil {
+		return &httpError{
+			code: http.StatusInternalServerError,
+			err:  errors.Wrap(err, "enqueuing changeset sync"),
+		}
+	}
+	return nil
+}
+
+// upsertCommitStatusEvent attaches the commit status to every open changeset
+// in the repository whose head is the commit the status belongs to, since
+// Bitbucket Cloud doesn't tell us which pull requests the commit is part of.
+func (h *BitbucketCloudWebhook) upsertCommitStatusEvent(ctx context.Context, esID string, e *bitbucketcloud.RepoCommitStatusEvent, ev keyer) *httpError {
+	if e.CommitStatus.Commit == nil {
+		log15.Debug("ignoring Bitbucket Cloud commit status event without a commit")
+		return nil
+	}
+
+	repo, err := h.getRepoForPR(ctx, h.Store, PR{RepoExternalID: e.Repository.UUID}, esID)
+	if err != nil {
+		log15.Debug("Webhook event could not be matched to repo", "err", err)
+		return nil
+	}
+
+	cs, _, err := h.Store.ListChangesets(ctx, store.ListChangesetsOpts{
+		RepoID:         repo.ID,
+		ExternalStates: []btypes.ChangesetExternalState{btypes.ChangesetExternalStateOpen},
+	})
+	if err != nil {
+		return &httpError{
+			code: http.StatusInternalServerError,
+			err:  errors.Wrap(err, "listing changesets"),
+		}
+	}
+
+	m := new(multierror.Error)
+	for _, c := range cs {
+		pr, ok := c.Metadata.(*bitbucketcloud.PullRequest)
+		if !ok || !pr.Source.Commit.HasHashPrefix(e.CommitStatus.Commit.Hash) {
+			continue
+		}
+
+		if err := h.upsertChangesetEvent(ctx, esID, PR{ID: pr.ID, RepoExternalID: e.Repository.UUID}, ev); err != nil {
+			m = multierror.Append(m, err)
+		}
+	}
+	if err := m.ErrorOrNil(); err != nil {
+		return &httpError{
+			code: http.StatusInternalServerError,
+			err:  errors.Wrap(err, "upserting changeset events"),
+		}
+	}
+	return nil
+}
+
+// bitbucketCloudToPR instantiates a new PR instance given the fields common to
+// Bitbucket Cloud pull request webhook payloads.
+func bitbucketCloudToPR(e *bitbucketcloud.PullRequestEvent) PR {
+	return PR{
+		ID:             e.PullRequest.ID,
+		RepoExternalID: e.Repository.UUID,
+	}
+}
+
+// validateBitbucketCloudSecret validates that the given secret matches the
+// webhook secret in the external service configuration.
+func validateBitbucketCloudSecret(extSvc *types.ExternalService, secret string) (bool, error) {
+	// An empty secret never succeeds.
+	if secret == "" {
+		return false, nil
+	}
+
+	c, err := extSvc.Configuration()
+	if err != nil {
+		return false, errors.Wrap(err, "getting external service configuration")
+	}
+
+	config, ok := c.(*schema.BitbucketCloudConnection)
+	if !ok {
+		return false, errExternalServiceWrongKind
+	}
+
+	if config.WebhookSecret == "" {
+		return false, nil
+	}
+	return subtle.ConstantTimeCompare([]byte(config.WebhookSecret), []byte(secret)) == 1, nil
+}
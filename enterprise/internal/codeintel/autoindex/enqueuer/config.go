@@ -12,12 +12,18 @@ type Config struct {
 	MaximumRepositoriesInspectedPerSecond    rate.Limit
 	MaximumRepositoriesUpdatedPerSecond      rate.Limit
 	MaximumIndexJobsPerInferredConfiguration int
+	PackageRegistryLookupsEnabled            bool
+	PyPIURL                                  string
+	NuGetURL                                 string
 }
 
 func (c *Config) Load() {
 	c.MaximumRepositoriesInspectedPerSecond = toRate(c.GetInt("PRECISE_CODE_INTEL_AUTO_INDEX_MAXIMUM_REPOSITORIES_INSPECTED_PER_SECOND", "0", "The maximum number of repositories inspected for auto-indexing per second. Set to zero to disable limit."))
 	c.MaximumRepositoriesUpdatedPerSecond = toRate(c.GetInt("PRECISE_CODE_INTEL_AUTO_INDEX_MAXIMUM_REPOSITORIES_UPDATED_PER_SECOND", "0", "The maximum number of repositories cloned or fetched for auto-indexing per second. Set to zero to disable limit."))
 	c.MaximumIndexJobsPerInferredConfiguration = c.GetInt("PRECISE_CODE_INTEL_AUTO_INDEX_MAXIMUM_INDEX_JOBS_PER_INFERRED_CONFIGURATION", "25", "Repositories with a number of inferred auto-index jobs exceeding this threshold will be auto-indexed.")
+	c.PackageRegistryLookupsEnabled = c.GetBool("PRECISE_CODE_INTEL_AUTO_INDEX_PACKAGE_REGISTRY_LOOKUPS_ENABLED", "false", "Look up the source repository of PyPI and NuGet dependencies in the package registry to index them.")
+	c.PyPIURL = c.Get("PRECISE_CODE_INTEL_AUTO_INDEX_PYPI_URL", "https://pypi.org/pypi", "The base URL of the PyPI JSON API used to look up the source repository of Python dependencies.")
+	c.NuGetURL = c.Get("PRECISE_CODE_INTEL_AUTO_INDEX_NUGET_URL", "https://api.nuget.org/v3-flatcontainer", "The base URL of the NuGet package content resource used to look up the source repository of .NET dependencies.")
 }
 
 func toRate(value int) rate.Limit {
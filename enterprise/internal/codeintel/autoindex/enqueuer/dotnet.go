@@ -0,0 +1,17 @@
+package enqueuer
+
+import (
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+// InferDotNetRepositoryAndRevision infers the repository of a NuGet package whose
+// moniker names the GitHub repository it was built from. The package version is
+// assumed to be a tag of that repository. Packages whose moniker carries only the
+// package ID are looked up by packageRegistryClient.
+func InferDotNetRepositoryAndRevision(pkg semantic.Package) (repoName, gitTagOrCommit string, ok bool) {
+	if pkg.Scheme != "nuget" {
+		return "", "", false
+	}
+
+	return inferGitHubRepositoryAndRevision(pkg.Name, pkg.Version)
+}
@@ -0,0 +1,31 @@
+package enqueuer
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+func TestInferDotNetRepositoryAndRevision(t *testing.T) {
+	pkg := semantic.Package{
+		Scheme:  "nuget",
+		Name:    "https://github.com/JamesNK/Newtonsoft.Json.git",
+		Version: "13.0.1",
+	}
+
+	repoName, revision, ok := InferDotNetRepositoryAndRevision(pkg)
+	if !ok {
+		t.Fatalf("expected repository to be inferred")
+	}
+
+	if want := "github.com/JamesNK/Newtonsoft.Json"; repoName != want {
+		t.Errorf("unexpected repo name. want=%q have=%q", want, repoName)
+	}
+	if want := "13.0.1"; revision != want {
+		t.Errorf("unexpected revision. want=%q have=%q", want, revision)
+	}
+
+	if _, _, ok := InferDotNetRepositoryAndRevision(semantic.Package{Scheme: "nuget", Name: "Newtonsoft.Json", Version: "13.0.1"}); ok {
+		t.Errorf("did not expect repository to be inferred for a package without a source URL")
+	}
+}
@@ -2,6 +2,7 @@ package enqueuer
 
 import (
 	"context"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
@@ -11,6 +12,7 @@ import (
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/inference"
@@ -21,6 +23,7 @@ type IndexEnqueuer struct {
 	dbStore            DBStore
 	gitserverClient    GitserverClient
 	repoUpdater        RepoUpdaterClient
+	packageRegistry    *packageRegistryClient // nil unless package registry lookups are enabled
 	config             *Config
 	gitserverLimiter   *rate.Limiter
 	repoUpdaterLimiter *rate.Limiter
@@ -34,10 +37,16 @@ func NewIndexEnqueuer(
 	config *Config,
 	observationContext *observation.Context,
 ) *IndexEnqueuer {
+	var packageRegistry *packageRegistryClient
+	if config.PackageRegistryLookupsEnabled {
+		packageRegistry = newPackageRegistryClient(httpcli.ExternalDoer(), config.PyPIURL, config.NuGetURL)
+	}
+
 	return &IndexEnqueuer{
 		dbStore:            dbStore,
 		gitserverClient:    gitClient,
 		repoUpdater:        repoUpdater,
+		packageRegistry:    packageRegistry,
 		config:             config,
 		gitserverLimiter:   rate.NewLimiter(config.MaximumRepositoriesInspectedPerSecond, 1),
 		repoUpdaterLimiter: rate.NewLimiter(config.MaximumRepositoriesUpdatedPerSecond, 1),
@@ -86,7 +95,9 @@ func (s *IndexEnqueuer) InferIndexConfiguration(ctx context.Context, repositoryI
 }
 
 // QueueIndexesForPackage enqueues index jobs for a dependency of a recently-processed precise code intelligence
-// index. Currently we only support recognition of "gomod" import monikers.
+// index. Currently we only support recognition of "gomod", "pip", "cargo", and "nuget" import monikers. If package
+// registry lookups are enabled, the source repository of packages installed from PyPI or NuGet is looked up in the
+// package index. Packages that can't be looked up are skipped.
 func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg semantic.Package) (err error) {
 	ctx, traceLog, endObservation := s.operations.QueueIndexForPackage.WithAndLogger(ctx, &err, observation.Args{
 		LogFields: []log.Field{
@@ -97,12 +108,32 @@ func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg semantic
 	})
 	defer endObservation(1, observation.Args{})
 
-	repoName, revision, ok := InferGoRepositoryAndRevision(pkg)
+	repoName, revision, ok := inferRepositoryAndRevision(pkg)
+	revisions := []string{revision}
 	if !ok {
-		return nil
+		if s.packageRegistry == nil {
+			return nil
+		}
+
+		repoName, revisions, ok, err = s.packageRegistry.InferRepositoryAndRevisions(ctx, pkg)
+		if err != nil {
+			// Registries may be unreachable, e.g. from instances without internet access, so a
+			// failed lookup must not fail the dependency indexing job.
+			log15.Warn(
+				"Failed to look up package in package registry",
+				"scheme", pkg.Scheme,
+				"name", pkg.Name,
+				"version", pkg.Version,
+				"error", err,
+			)
+			return nil
+		}
+		if !ok {
+			return nil
+		}
 	}
 	traceLog(log.String("repoName", repoName))
-	traceLog(log.String("revision", revision))
+	traceLog(log.String("revisions", strings.Join(revisions, ",")))
 
 	if err := s.repoUpdaterLimiter.Wait(ctx); err != nil {
 		return err
@@ -117,16 +148,42 @@ func (s *IndexEnqueuer) QueueIndexesForPackage(ctx context.Context, pkg semantic
 		return errors.Wrap(err, "repoUpdater.EnqueueRepoUpdate")
 	}
 
-	commit, err := s.gitserverClient.ResolveRevision(ctx, int(resp.ID), revision)
-	if err != nil {
-		if errcode.IsNotFound(err) {
-			return nil
+	// Revisions are listed in order of preference; the first one that exists is indexed.
+	for _, revision := range revisions {
+		commit, err := s.gitserverClient.ResolveRevision(ctx, int(resp.ID), revision)
+		if err != nil {
+			if errcode.IsNotFound(err) {
+				continue
+			}
+
+			return errors.Wrap(err, "gitserverClient.ResolveRevision")
 		}
 
-		return errors.Wrap(err, "gitserverClient.ResolveRevision")
+		return s.queueIndexForRepositoryAndCommit(ctx, int(resp.ID), string(commit), false, traceLog)
+	}
+
+	return nil
+}
+
+// repositoryAndRevisionInferrers is the list of functions that can infer the source repository
+// and revision of a package from an import moniker. Each function recognizes a distinct scheme.
+var repositoryAndRevisionInferrers = []func(pkg semantic.Package) (repoName, gitTagOrCommit string, ok bool){
+	InferGoRepositoryAndRevision,
+	InferPythonRepositoryAndRevision,
+	InferRustRepositoryAndRevision,
+	InferDotNetRepositoryAndRevision,
+}
+
+// inferRepositoryAndRevision returns the source repository and revision of the given package
+// as determined by the first inferrer that recognizes it.
+func inferRepositoryAndRevision(pkg semantic.Package) (repoName, gitTagOrCommit string, ok bool) {
+	for _, infer := range repositoryAndRevisionInferrers {
+		if repoName, gitTagOrCommit, ok := infer(pkg); ok {
+			return repoName, gitTagOrCommit, true
+		}
 	}
 
-	return s.queueIndexForRepositoryAndCommit(ctx, int(resp.ID), string(commit), false, traceLog)
+	return "", "", false
 }
 
 // queueIndexForRepository determines the head of the default branch of the given repository and attempts to
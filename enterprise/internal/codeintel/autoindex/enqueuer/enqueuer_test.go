@@ -3,6 +3,8 @@ package enqueuer
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"sort"
 	"testing"
@@ -427,3 +429,36 @@ func TestQueueIndexesForPackage(t *testing.T) {
 		}
 	}
 }
+
+func TestQueueIndexesForPackageRegistryLookups(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	pkg := semantic.Package{Scheme: "pip", Name: "requests", Version: "2.26.0"}
+	mockRepoUpdater := NewMockRepoUpdaterClient()
+
+	// Lookups are disabled by default
+	scheduler := NewIndexEnqueuer(NewMockDBStore(), NewMockGitserverClient(), mockRepoUpdater, &testConfig, &observation.TestContext)
+	if scheduler.packageRegistry != nil {
+		t.Fatalf("expected package registry lookups to be disabled")
+	}
+	if err := scheduler.QueueIndexesForPackage(context.Background(), pkg); err != nil {
+		t.Fatalf("unexpected error queueing package: %s", err)
+	}
+
+	// Failed lookups are not errors
+	scheduler.packageRegistry = newPackageRegistryClient(server.Client(), server.URL, server.URL)
+	if err := scheduler.QueueIndexesForPackage(context.Background(), pkg); err != nil {
+		t.Fatalf("unexpected error queueing package: %s", err)
+	}
+	if requests != 1 {
+		t.Errorf("unexpected number of registry requests. want=%d have=%d", 1, requests)
+	}
+	if len(mockRepoUpdater.EnqueueRepoUpdateFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to EnqueueRepoUpdate. want=%d have=%d", 0, len(mockRepoUpdater.EnqueueRepoUpdateFunc.History()))
+	}
+}
@@ -0,0 +1,56 @@
+package enqueuer
+
+import (
+	"net/url"
+	"strings"
+)
+
+// inferGitHubRepositoryAndRevision returns the name and revision of the GitHub
+// repository referenced by the given source URL, as written in the lockfiles and
+// manifests of package managers that can install packages directly from a git
+// repository. The following forms are recognized:
+//
+//   - https://github.com/owner/repo
+//   - git+https://github.com/owner/repo.git@rev (pip)
+//   - git+https://github.com/owner/repo?rev=rev#commit (Cargo)
+//
+// A revision encoded in the URL takes precedence over the given version. No
+// repository is inferred if there is no revision to resolve.
+func inferGitHubRepositoryAndRevision(sourceURL, version string) (repoName, gitTagOrCommit string, ok bool) {
+	u, err := url.Parse(strings.TrimPrefix(sourceURL, "git+"))
+	if err != nil || u.Scheme+"://" != GitHubScheme || u.Host != "github.com" {
+		return "", "", false
+	}
+
+	repoParts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(repoParts) < 2 || repoParts[0] == "" || repoParts[1] == "" {
+		return "", "", false
+	}
+	owner, name := repoParts[0], repoParts[1]
+
+	revision := version
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, revision = name[:i], name[i+1:]
+	}
+	name = strings.TrimSuffix(name, ".git")
+
+	query := u.Query()
+	for _, key := range []string{"rev", "tag", "branch"} {
+		if value := query.Get(key); value != "" {
+			revision = value
+			break
+		}
+	}
+
+	// Cargo records the resolved commit as the fragment. Other fragments, such
+	// as pip's #egg=name, are key-value pairs and don't name a revision.
+	if u.Fragment != "" && !strings.Contains(u.Fragment, "=") {
+		revision = u.Fragment
+	}
+
+	if name == "" || revision == "" {
+		return "", "", false
+	}
+
+	return "github.com/" + owner + "/" + name, revision, true
+}
@@ -0,0 +1,16 @@
+package enqueuer
+
+import (
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+// InferPythonRepositoryAndRevision infers the repository of a Python package that
+// was installed from a GitHub repository. The source repository of packages installed
+// from PyPI is not part of the moniker and is looked up by packageRegistryClient.
+func InferPythonRepositoryAndRevision(pkg semantic.Package) (repoName, gitTagOrCommit string, ok bool) {
+	if pkg.Scheme != "pip" {
+		return "", "", false
+	}
+
+	return inferGitHubRepositoryAndRevision(pkg.Name, pkg.Version)
+}
@@ -0,0 +1,60 @@
+package enqueuer
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+func TestInferPythonRepositoryAndRevision(t *testing.T) {
+	testCases := []struct {
+		pkg      semantic.Package
+		repoName string
+		revision string
+	}{
+		{
+			pkg: semantic.Package{
+				Scheme:  "pip",
+				Name:    "https://github.com/psf/requests",
+				Version: "v2.26.0",
+			},
+			repoName: "github.com/psf/requests",
+			revision: "v2.26.0",
+		},
+		{
+			pkg: semantic.Package{
+				Scheme:  "pip",
+				Name:    "git+https://github.com/psf/requests.git@de0123456789#egg=requests",
+				Version: "2.26.0",
+			},
+			repoName: "github.com/psf/requests",
+			revision: "de0123456789",
+		},
+	}
+
+	for _, testCase := range testCases {
+		repoName, revision, ok := InferPythonRepositoryAndRevision(testCase.pkg)
+		if !ok {
+			t.Fatalf("expected repository to be inferred")
+		}
+
+		if repoName != testCase.repoName {
+			t.Errorf("unexpected repo name. want=%q have=%q", testCase.repoName, repoName)
+		}
+		if revision != testCase.revision {
+			t.Errorf("unexpected revision. want=%q have=%q", testCase.revision, revision)
+		}
+	}
+}
+
+func TestInferPythonRepositoryAndRevisionUnrecognized(t *testing.T) {
+	for _, pkg := range []semantic.Package{
+		{Scheme: "pip", Name: "requests", Version: "2.26.0"},
+		{Scheme: "pip", Name: "https://gitlab.com/psf/requests", Version: "v2.26.0"},
+		{Scheme: "gomod", Name: "https://github.com/psf/requests", Version: "v2.26.0"},
+	} {
+		if _, _, ok := InferPythonRepositoryAndRevision(pkg); ok {
+			t.Errorf("did not expect repository to be inferred for %+v", pkg)
+		}
+	}
+}
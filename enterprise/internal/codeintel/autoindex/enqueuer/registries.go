@@ -0,0 +1,166 @@
+package enqueuer
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+// pypiSourceURLKeys are the project URL labels that commonly point at the source
+// repository of a PyPI project, in order of preference.
+var pypiSourceURLKeys = []string{"Source", "Source Code", "Code", "Repository", "Homepage"}
+
+// packageRegistryClient looks up the source repository of packages installed from a
+// package index. The import monikers of such packages carry only the package name
+// and version, so the repository is read from the metadata published to the index.
+type packageRegistryClient struct {
+	doer     httpcli.Doer
+	pypiURL  string
+	nugetURL string
+}
+
+func newPackageRegistryClient(doer httpcli.Doer, pypiURL, nugetURL string) *packageRegistryClient {
+	return &packageRegistryClient{
+		doer:     doer,
+		pypiURL:  strings.TrimSuffix(pypiURL, "/"),
+		nugetURL: strings.TrimSuffix(nugetURL, "/"),
+	}
+}
+
+// InferRepositoryAndRevisions returns the GitHub repository that the given PyPI or
+// NuGet package was built from, along with the revisions that may correspond to the
+// package version in order of preference. Packages that aren't published to either
+// index, or whose metadata doesn't name a GitHub repository, are not recognized.
+func (c *packageRegistryClient) InferRepositoryAndRevisions(ctx context.Context, pkg semantic.Package) (repoName string, revisions []string, ok bool, err error) {
+	if pkg.Name == "" || pkg.Version == "" {
+		return "", nil, false, nil
+	}
+
+	switch pkg.Scheme {
+	case "pip":
+		return c.inferPyPIRepositoryAndRevisions(ctx, pkg)
+	case "nuget":
+		return c.inferNuGetRepositoryAndRevisions(ctx, pkg)
+	}
+
+	return "", nil, false, nil
+}
+
+type pypiRelease struct {
+	Info struct {
+		HomePage    string            `json:"home_page"`
+		ProjectURLs map[string]string `json:"project_urls"`
+	} `json:"info"`
+}
+
+func (c *packageRegistryClient) inferPyPIRepositoryAndRevisions(ctx context.Context, pkg semantic.Package) (string, []string, bool, error) {
+	u := fmt.Sprintf("%s/%s/%s/json", c.pypiURL, url.PathEscape(pkg.Name), url.PathEscape(pkg.Version))
+
+	var release pypiRelease
+	if ok, err := c.get(ctx, u, func(r io.Reader) error { return json.NewDecoder(r).Decode(&release) }); err != nil || !ok {
+		return "", nil, false, err
+	}
+
+	var sourceURLs []string
+	for _, key := range pypiSourceURLKeys {
+		if value, ok := release.Info.ProjectURLs[key]; ok {
+			sourceURLs = append(sourceURLs, value)
+		}
+	}
+	keys := make([]string, 0, len(release.Info.ProjectURLs))
+	for key := range release.Info.ProjectURLs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		sourceURLs = append(sourceURLs, release.Info.ProjectURLs[key])
+	}
+	sourceURLs = append(sourceURLs, release.Info.HomePage)
+
+	for _, sourceURL := range sourceURLs {
+		if repoName, _, ok := inferGitHubRepositoryAndRevision(sourceURL, pkg.Version); ok {
+			return repoName, versionTags(pkg.Version), true, nil
+		}
+	}
+
+	return "", nil, false, nil
+}
+
+type nuspec struct {
+	Metadata struct {
+		Repository struct {
+			URL    string `xml:"url,attr"`
+			Commit string `xml:"commit,attr"`
+		} `xml:"repository"`
+		ProjectURL string `xml:"projectUrl"`
+	} `xml:"metadata"`
+}
+
+func (c *packageRegistryClient) inferNuGetRepositoryAndRevisions(ctx context.Context, pkg semantic.Package) (string, []string, bool, error) {
+	// The flat container resource addresses packages by their lowercased ID and version.
+	id, version := url.PathEscape(strings.ToLower(pkg.Name)), url.PathEscape(strings.ToLower(pkg.Version))
+	u := fmt.Sprintf("%s/%s/%s/%s.nuspec", c.nugetURL, id, version, id)
+
+	var spec nuspec
+	if ok, err := c.get(ctx, u, func(r io.Reader) error { return xml.NewDecoder(r).Decode(&spec) }); err != nil || !ok {
+		return "", nil, false, err
+	}
+
+	if repoName, _, ok := inferGitHubRepositoryAndRevision(spec.Metadata.Repository.URL, pkg.Version); ok {
+		if commit := spec.Metadata.Repository.Commit; commit != "" {
+			return repoName, []string{commit}, true, nil
+		}
+		return repoName, versionTags(pkg.Version), true, nil
+	}
+	if repoName, _, ok := inferGitHubRepositoryAndRevision(spec.Metadata.ProjectURL, pkg.Version); ok {
+		return repoName, versionTags(pkg.Version), true, nil
+	}
+
+	return "", nil, false, nil
+}
+
+// get requests the given URL and decodes the response body. A false-valued flag is
+// returned if the registry doesn't know the requested package or version.
+func (c *packageRegistryClient) get(ctx context.Context, u string, decode func(r io.Reader) error) (bool, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.doer.Do(req.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.Errorf("unexpected status code %d from %s", resp.StatusCode, u)
+	}
+
+	if err := decode(resp.Body); err != nil {
+		return false, errors.Wrapf(err, "decoding response from %s", u)
+	}
+	return true, nil
+}
+
+// versionTags returns the tags that commonly mark the release of the given version.
+func versionTags(version string) []string {
+	if strings.HasPrefix(version, "v") {
+		return []string{version}
+	}
+	return []string{version, "v" + version}
+}
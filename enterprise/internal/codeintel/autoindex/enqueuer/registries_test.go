@@ -0,0 +1,116 @@
+package enqueuer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+func TestPackageRegistryClientInferRepositoryAndRevisions(t *testing.T) {
+	responses := map[string]string{
+		"/pypi/requests/2.26.0/json": `{"info": {
+			"home_page": "https://requests.readthedocs.io",
+			"project_urls": {"Documentation": "https://requests.readthedocs.io", "Source": "https://github.com/psf/requests"}
+		}}`,
+		"/pypi/six/1.16.0/json":     `{"info": {"home_page": "https://github.com/benjaminp/six", "project_urls": null}}`,
+		"/pypi/internal/1.0.0/json": `{"info": {"home_page": "https://gitlab.com/acme/internal"}}`,
+		"/nuget/newtonsoft.json/13.0.1/newtonsoft.json.nuspec": `<?xml version="1.0" encoding="utf-8"?>
+			<package xmlns="http://schemas.microsoft.com/packaging/2013/05/nuspec.xsd">
+				<metadata>
+					<id>Newtonsoft.Json</id>
+					<version>13.0.1</version>
+					<repository type="git" url="https://github.com/JamesNK/Newtonsoft.Json.git" commit="ae9fe44e1323e91bcbd185ca1a14099fba7c021f" />
+				</metadata>
+			</package>`,
+		"/nuget/serilog/2.10.0/serilog.nuspec": `<?xml version="1.0" encoding="utf-8"?>
+			<package xmlns="http://schemas.microsoft.com/packaging/2013/05/nuspec.xsd">
+				<metadata>
+					<projectUrl>https://github.com/serilog/serilog</projectUrl>
+				</metadata>
+			</package>`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	client := newPackageRegistryClient(server.Client(), server.URL+"/pypi", server.URL+"/nuget/")
+
+	testCases := []struct {
+		pkg       semantic.Package
+		ok        bool
+		repoName  string
+		revisions []string
+	}{
+		{
+			pkg:       semantic.Package{Scheme: "pip", Name: "requests", Version: "2.26.0"},
+			ok:        true,
+			repoName:  "github.com/psf/requests",
+			revisions: []string{"2.26.0", "v2.26.0"},
+		},
+		{
+			pkg:       semantic.Package{Scheme: "pip", Name: "six", Version: "1.16.0"},
+			ok:        true,
+			repoName:  "github.com/benjaminp/six",
+			revisions: []string{"1.16.0", "v1.16.0"},
+		},
+		{
+			pkg:       semantic.Package{Scheme: "nuget", Name: "Newtonsoft.Json", Version: "13.0.1"},
+			ok:        true,
+			repoName:  "github.com/JamesNK/Newtonsoft.Json",
+			revisions: []string{"ae9fe44e1323e91bcbd185ca1a14099fba7c021f"},
+		},
+		{
+			pkg:       semantic.Package{Scheme: "nuget", Name: "Serilog", Version: "2.10.0"},
+			ok:        true,
+			repoName:  "github.com/serilog/serilog",
+			revisions: []string{"2.10.0", "v2.10.0"},
+		},
+		{pkg: semantic.Package{Scheme: "pip", Name: "internal", Version: "1.0.0"}},
+		{pkg: semantic.Package{Scheme: "pip", Name: "unknown", Version: "1.0.0"}},
+		{pkg: semantic.Package{Scheme: "nuget", Name: "Unknown", Version: "1.0.0"}},
+		{pkg: semantic.Package{Scheme: "cargo", Name: "regex", Version: "1.5.4"}},
+	}
+
+	for _, testCase := range testCases {
+		repoName, revisions, ok, err := client.InferRepositoryAndRevisions(context.Background(), testCase.pkg)
+		if err != nil {
+			t.Fatalf("unexpected error inferring repository of %+v: %s", testCase.pkg, err)
+		}
+		if ok != testCase.ok {
+			t.Errorf("unexpected ok flag for %+v. want=%v have=%v", testCase.pkg, testCase.ok, ok)
+			continue
+		}
+
+		if repoName != testCase.repoName {
+			t.Errorf("unexpected repo name for %+v. want=%q have=%q", testCase.pkg, testCase.repoName, repoName)
+		}
+		if diff := cmp.Diff(testCase.revisions, revisions); diff != "" {
+			t.Errorf("unexpected revisions for %+v (-want +got):\n%s", testCase.pkg, diff)
+		}
+	}
+}
+
+func TestPackageRegistryClientInferRepositoryAndRevisionsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := newPackageRegistryClient(server.Client(), server.URL, server.URL)
+
+	if _, _, _, err := client.InferRepositoryAndRevisions(context.Background(), semantic.Package{Scheme: "pip", Name: "requests", Version: "2.26.0"}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,16 @@
+package enqueuer
+
+import (
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+// InferRustRepositoryAndRevision infers the repository of a Rust crate that was
+// fetched from a GitHub repository as a git dependency. Crates fetched from a
+// registry such as crates.io are not recognized.
+func InferRustRepositoryAndRevision(pkg semantic.Package) (repoName, gitTagOrCommit string, ok bool) {
+	if pkg.Scheme != "cargo" {
+		return "", "", false
+	}
+
+	return inferGitHubRepositoryAndRevision(pkg.Name, pkg.Version)
+}
@@ -0,0 +1,59 @@
+package enqueuer
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+func TestInferRustRepositoryAndRevision(t *testing.T) {
+	testCases := []struct {
+		pkg      semantic.Package
+		repoName string
+		revision string
+	}{
+		{
+			pkg: semantic.Package{
+				Scheme:  "cargo",
+				Name:    "git+https://github.com/rust-lang/regex?tag=1.5.4#de0123456789",
+				Version: "1.5.4",
+			},
+			repoName: "github.com/rust-lang/regex",
+			revision: "de0123456789",
+		},
+		{
+			pkg: semantic.Package{
+				Scheme:  "cargo",
+				Name:    "git+https://github.com/rust-lang/regex?branch=main",
+				Version: "1.5.4",
+			},
+			repoName: "github.com/rust-lang/regex",
+			revision: "main",
+		},
+	}
+
+	for _, testCase := range testCases {
+		repoName, revision, ok := InferRustRepositoryAndRevision(testCase.pkg)
+		if !ok {
+			t.Fatalf("expected repository to be inferred")
+		}
+
+		if repoName != testCase.repoName {
+			t.Errorf("unexpected repo name. want=%q have=%q", testCase.repoName, repoName)
+		}
+		if revision != testCase.revision {
+			t.Errorf("unexpected revision. want=%q have=%q", testCase.revision, revision)
+		}
+	}
+}
+
+func TestInferRustRepositoryAndRevisionUnrecognized(t *testing.T) {
+	for _, pkg := range []semantic.Package{
+		{Scheme: "cargo", Name: "regex", Version: "1.5.4"},
+		{Scheme: "cargo", Name: "registry+https://github.com/rust-lang/crates.io-index", Version: "1.5.4"},
+	} {
+		if _, _, ok := InferRustRepositoryAndRevision(pkg); ok {
+			t.Errorf("did not expect repository to be inferred for %+v", pkg)
+		}
+	}
+}
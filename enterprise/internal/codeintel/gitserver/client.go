@@ -206,6 +206,9 @@ var refPrefixes = map[string]RefType{
 	"refs/tags/":  RefTypeTag,
 }
 
+// refDescriptionFormat is the for-each-ref format string parsed by parseRefDescription.
+const refDescriptionFormat = "--format=%(objectname):%(refname):%(HEAD):%(creatordate:iso8601-strict)"
+
 // RefDescriptions returns a map from commits to descriptions of the tip of each
 // branch and tag of the given repository.
 func (c *Client) RefDescriptions(ctx context.Context, repositoryID int) (_ map[string]RefDescription, err error) {
@@ -214,7 +217,7 @@ func (c *Client) RefDescriptions(ctx context.Context, repositoryID int) (_ map[s
 	}})
 	defer endObservation(1, observation.Args{})
 
-	args := []string{"for-each-ref", "--format=%(objectname):%(refname):%(HEAD):%(creatordate:iso8601-strict)"}
+	args := []string{"for-each-ref", refDescriptionFormat}
 	for prefix := range refPrefixes {
 		args = append(args, prefix)
 	}
@@ -227,14 +230,25 @@ func (c *Client) RefDescriptions(ctx context.Context, repositoryID int) (_ map[s
 	return parseRefDescriptions(strings.Split(out, "\n"))
 }
 
+// BranchDescriptions returns a map from commits to descriptions of each branch of the given
+// repository whose tip is that commit. Unlike RefDescriptions, a commit that is the tip of
+// several branches maps to all of them.
+func (c *Client) BranchDescriptions(ctx context.Context, repositoryID int) (_ map[string][]RefDescription, err error) {
+	ctx, endObservation := c.operations.branchDescriptions.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("repositoryID", repositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	out, err := c.execGitCommand(ctx, repositoryID, "for-each-ref", refDescriptionFormat, "refs/heads/")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBranchDescriptions(strings.Split(out, "\n"))
+}
+
 // parseRefDescriptions converts the output of the for-each-ref command in the RefDescriptions
-// method to a map from commits to RefDescription objects. Each line should conform to the format
-// string `%(objectname):%(refname):%(HEAD):%(creatordate)`, where
-//
-// - %(objectname) is the 40-character revhash
-// - %(refname) is the name of the tag or branch (prefixed with refs/heads/ or ref/tags/)
-// - %(HEAD) is `*` if the branch is the default branch (and whitesace otherwise)
-// - %(creatordate) is the ISO-formatted date the object was created
+// method to a map from commits to RefDescription objects.
 func parseRefDescriptions(lines []string) (map[string]RefDescription, error) {
 	refDescriptions := make(map[string]RefDescription, len(lines))
 	for _, line := range lines {
@@ -243,41 +257,79 @@ func parseRefDescriptions(lines []string) (map[string]RefDescription, error) {
 			continue
 		}
 
-		parts := strings.SplitN(line, ":", 4)
-		if len(parts) != 4 {
-			return nil, errors.Errorf(`unexpected output from git for-each-ref "%s"`, line)
+		commit, refDescription, err := parseRefDescription(line)
+		if err != nil {
+			return nil, err
 		}
 
-		commit := parts[0]
-		isDefaultBranch := parts[2] == "*"
+		refDescriptions[commit] = refDescription
+	}
 
-		var name string
-		var refType RefType
-		for prefix, typ := range refPrefixes {
-			if strings.HasPrefix(parts[1], prefix) {
-				name = parts[1][len(prefix):]
-				refType = typ
-				break
-			}
-		}
-		if refType == RefTypeUnknown {
-			return nil, errors.Errorf(`unexpected output from git for-each-ref "%s"`, line)
+	return refDescriptions, nil
+}
+
+// parseBranchDescriptions converts the output of the for-each-ref command in the
+// BranchDescriptions method to a map from commits to the RefDescription objects of
+// each branch pointing at that commit.
+func parseBranchDescriptions(lines []string) (map[string][]RefDescription, error) {
+	branchDescriptions := make(map[string][]RefDescription, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
 		}
 
-		createdDate, err := time.Parse(time.RFC3339, parts[3])
+		commit, refDescription, err := parseRefDescription(line)
 		if err != nil {
-			return nil, errors.Errorf(`unexpected output from git for-each-ref (bad date format) "%s"`, line)
+			return nil, err
 		}
 
-		refDescriptions[commit] = RefDescription{
-			Name:            name,
-			Type:            refType,
-			IsDefaultBranch: isDefaultBranch,
-			CreatedDate:     createdDate,
+		branchDescriptions[commit] = append(branchDescriptions[commit], refDescription)
+	}
+
+	return branchDescriptions, nil
+}
+
+// parseRefDescription parses a single line of for-each-ref output. Each line should conform
+// to the format string `%(objectname):%(refname):%(HEAD):%(creatordate)`, where
+//
+// - %(objectname) is the 40-character revhash
+// - %(refname) is the name of the tag or branch (prefixed with refs/heads/ or ref/tags/)
+// - %(HEAD) is `*` if the branch is the default branch (and whitesace otherwise)
+// - %(creatordate) is the ISO-formatted date the object was created
+func parseRefDescription(line string) (string, RefDescription, error) {
+	parts := strings.SplitN(line, ":", 4)
+	if len(parts) != 4 {
+		return "", RefDescription{}, errors.Errorf(`unexpected output from git for-each-ref "%s"`, line)
+	}
+
+	commit := parts[0]
+	isDefaultBranch := parts[2] == "*"
+
+	var name string
+	var refType RefType
+	for prefix, typ := range refPrefixes {
+		if strings.HasPrefix(parts[1], prefix) {
+			name = parts[1][len(prefix):]
+			refType = typ
+			break
 		}
 	}
+	if refType == RefTypeUnknown {
+		return "", RefDescription{}, errors.Errorf(`unexpected output from git for-each-ref "%s"`, line)
+	}
 
-	return refDescriptions, nil
+	createdDate, err := time.Parse(time.RFC3339, parts[3])
+	if err != nil {
+		return "", RefDescription{}, errors.Errorf(`unexpected output from git for-each-ref (bad date format) "%s"`, line)
+	}
+
+	return commit, RefDescription{
+		Name:            name,
+		Type:            refType,
+		IsDefaultBranch: isDefaultBranch,
+		CreatedDate:     createdDate,
+	}, nil
 }
 
 // RawContents returns the contents of a file in a particular commit of a repository.
@@ -207,3 +207,36 @@ func TestParseRefDescriptions(t *testing.T) {
 		t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
 	}
 }
+
+func TestParseBranchDescriptions(t *testing.T) {
+	branchDescriptions, err := parseBranchDescriptions([]string{
+		"ce30aee6cc56f39d0ac6fee03c4c151c08a8cd2e:refs/heads/main: :2021-06-16T11:51:09-07:00",
+		"ce30aee6cc56f39d0ac6fee03c4c151c08a8cd2e:refs/heads/master:*:2021-06-16T11:51:09-07:00",
+		"234b0a484519129b251164ecb0674ec27d154d2f:refs/heads/symbols: :2021-01-01T22:51:55-08:00",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error parsing branch descriptions: %s", err)
+	}
+
+	mustParseDate := func(s string) time.Time {
+		date, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatalf("unexpected error parsing date string: %s", err)
+		}
+
+		return date
+	}
+
+	expectedBranchDescriptions := map[string][]RefDescription{
+		"ce30aee6cc56f39d0ac6fee03c4c151c08a8cd2e": {
+			{Name: "main", Type: RefTypeBranch, IsDefaultBranch: false, CreatedDate: mustParseDate("2021-06-16T11:51:09-07:00")},
+			{Name: "master", Type: RefTypeBranch, IsDefaultBranch: true, CreatedDate: mustParseDate("2021-06-16T11:51:09-07:00")},
+		},
+		"234b0a484519129b251164ecb0674ec27d154d2f": {
+			{Name: "symbols", Type: RefTypeBranch, IsDefaultBranch: false, CreatedDate: mustParseDate("2021-01-01T22:51:55-08:00")},
+		},
+	}
+	if diff := cmp.Diff(expectedBranchDescriptions, branchDescriptions); diff != "" {
+		t.Errorf("unexpected branch descriptions (-want +got):\n%s", diff)
+	}
+}
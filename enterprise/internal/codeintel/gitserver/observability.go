@@ -11,16 +11,17 @@ import (
 )
 
 type operations struct {
-	commitDate        *observation.Operation
-	commitExists      *observation.Operation
-	commitGraph       *observation.Operation
-	directoryChildren *observation.Operation
-	fileExists        *observation.Operation
-	head              *observation.Operation
-	listFiles         *observation.Operation
-	rawContents       *observation.Operation
-	refDescriptions   *observation.Operation
-	resolveRevision   *observation.Operation
+	branchDescriptions *observation.Operation
+	commitDate         *observation.Operation
+	commitExists       *observation.Operation
+	commitGraph        *observation.Operation
+	directoryChildren  *observation.Operation
+	fileExists         *observation.Operation
+	head               *observation.Operation
+	listFiles          *observation.Operation
+	rawContents        *observation.Operation
+	refDescriptions    *observation.Operation
+	resolveRevision    *observation.Operation
 }
 
 func newOperations(observationContext *observation.Context) *operations {
@@ -43,15 +44,16 @@ func newOperations(observationContext *observation.Context) *operations {
 	}
 
 	return &operations{
-		commitDate:        op("CommitDate"),
-		commitExists:      op("CommitExists"),
-		commitGraph:       op("CommitGraph"),
-		directoryChildren: op("DirectoryChildren"),
-		fileExists:        op("FileExists"),
-		head:              op("Head"),
-		listFiles:         op("ListFiles"),
-		rawContents:       op("RawContents"),
-		refDescriptions:   op("RefDescriptions"),
-		resolveRevision:   op("ResolveRevision"),
+		branchDescriptions: op("BranchDescriptions"),
+		commitDate:         op("CommitDate"),
+		commitExists:       op("CommitExists"),
+		commitGraph:        op("CommitGraph"),
+		directoryChildren:  op("DirectoryChildren"),
+		fileExists:         op("FileExists"),
+		head:               op("Head"),
+		listFiles:          op("ListFiles"),
+		rawContents:        op("RawContents"),
+		refDescriptions:    op("RefDescriptions"),
+		resolveRevision:    op("ResolveRevision"),
 	}
 }
@@ -280,6 +280,94 @@ FROM lsif_dumps_with_repository_name u
 WHERE u.id IN (%s) AND %s
 `
 
+// NearestUpload is an upload visible from a particular commit along with the number of
+// commits between the upload's commit and the target commit.
+type NearestUpload struct {
+	UploadID int
+	Root     string
+	Indexer  string
+	Distance int
+}
+
+// scanNearestUploads scans a map from commits to the nearest uploads visible from that
+// commit from the return value of `*Store.query`.
+func scanNearestUploads(rows *sql.Rows, queryErr error) (_ map[string][]NearestUpload, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	nearestUploads := map[string][]NearestUpload{}
+	for rows.Next() {
+		var commit string
+		var upload NearestUpload
+		if err := rows.Scan(&commit, &upload.UploadID, &upload.Root, &upload.Indexer, &upload.Distance); err != nil {
+			return nil, err
+		}
+
+		nearestUploads[commit] = append(nearestUploads[commit], upload)
+	}
+
+	return nearestUploads, nil
+}
+
+// NearestUploadsForCommits returns a map from each of the given commits to the set of uploads visible
+// from that commit that can answer queries for the given path. Only the nearest upload for each root
+// and indexer pair is returned, ordered by distance. This reads the visible uploads persisted by the
+// last commit graph update and does not recalculate the commit graph. Commits unknown to the commit
+// graph (e.g. commits made after the last update) are absent from the result.
+func (s *Store) NearestUploadsForCommits(ctx context.Context, repositoryID int, commits []string, path string) (_ map[string][]NearestUpload, err error) {
+	ctx, traceLog, endObservation := s.operations.nearestUploadsForCommits.WithAndLogger(ctx, &err, observation.Args{
+		LogFields: []log.Field{
+			log.Int("repositoryID", repositoryID),
+			log.Int("numCommits", len(commits)),
+			log.String("path", path),
+		},
+	})
+	defer endObservation(1, observation.Args{})
+
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	conds := makeFindClosestDumpConditions(path, false, "")
+	query := sqlf.Sprintf(nearestUploadsForCommitsQuery, makeVisibleUploadCandidatesQuery(repositoryID, commits...), sqlf.Join(conds, " AND "))
+
+	nearestUploads, err := scanNearestUploads(s.Store.Query(ctx, query))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numCommitsWithUploads", len(nearestUploads)))
+
+	return nearestUploads, nil
+}
+
+const nearestUploadsForCommitsQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/dumps.go:NearestUploadsForCommits
+WITH
+visible_uploads AS (%s)
+SELECT
+	encode(t.commit_bytea, 'hex'),
+	t.upload_id,
+	t.root,
+	t.indexer,
+	t.distance
+FROM (
+	SELECT
+		vu.commit_bytea,
+		vu.upload_id,
+		vu.distance,
+		u.root,
+		u.indexer,
+		row_number() OVER (PARTITION BY vu.commit_bytea, u.root, u.indexer ORDER BY vu.distance) AS r
+	FROM visible_uploads vu
+	JOIN lsif_uploads u ON u.id = vu.upload_id
+	WHERE %s
+) t
+WHERE t.r <= 1
+ORDER BY t.commit_bytea, t.distance, t.upload_id
+`
+
 // makeVisibleUploadCandidatesQuery returns a SQL query returning the set of uploads
 // visible from the given commits. This is done by looking at each commit's row in the
 // lsif_nearest_uploads, and the (adjusted) set of uploads visible from each commit's
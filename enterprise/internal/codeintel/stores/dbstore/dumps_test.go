@@ -699,6 +699,61 @@ func testPresence(needle int, haystack []int) bool {
 	return false
 }
 
+func TestNearestUploadsForCommits(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	// This database has the following commit graph:
+	//
+	// [1] -- 2 -- [3] -- 4
+
+	uploads := []Upload{
+		{ID: 1, Commit: makeCommit(1), Root: "", Indexer: "lsif-go"},
+		{ID: 2, Commit: makeCommit(3), Root: "web/", Indexer: "lsif-tsc"},
+	}
+	insertUploads(t, db, uploads...)
+
+	graph := gitserver.ParseCommitGraph([]string{
+		strings.Join([]string{makeCommit(4), makeCommit(3)}, " "),
+		strings.Join([]string{makeCommit(3), makeCommit(2)}, " "),
+		strings.Join([]string{makeCommit(2), makeCommit(1)}, " "),
+		strings.Join([]string{makeCommit(1)}, " "),
+	})
+
+	visibleUploads, links := commitgraph.NewGraph(graph, toCommitGraphView(uploads)).Gather()
+	insertNearestUploads(t, db, 50, visibleUploads)
+	insertLinks(t, db, 50, links)
+
+	commits := []string{makeCommit(2), makeCommit(4), makeCommit(5)}
+
+	if nearestUploads, err := store.NearestUploadsForCommits(context.Background(), 50, commits, "web/app.ts"); err != nil {
+		t.Fatalf("unexpected error getting nearest uploads: %s", err)
+	} else {
+		expected := map[string][]NearestUpload{
+			makeCommit(2): {{UploadID: 1, Root: "", Indexer: "lsif-go", Distance: 1}},
+			makeCommit(4): {{UploadID: 2, Root: "web/", Indexer: "lsif-tsc", Distance: 1}, {UploadID: 1, Root: "", Indexer: "lsif-go", Distance: 3}},
+		}
+		if diff := cmp.Diff(expected, nearestUploads); diff != "" {
+			t.Errorf("unexpected nearest uploads (-want +got):\n%s", diff)
+		}
+	}
+
+	if nearestUploads, err := store.NearestUploadsForCommits(context.Background(), 50, commits, "cmd/main.go"); err != nil {
+		t.Fatalf("unexpected error getting nearest uploads: %s", err)
+	} else {
+		expected := map[string][]NearestUpload{
+			makeCommit(2): {{UploadID: 1, Root: "", Indexer: "lsif-go", Distance: 1}},
+			makeCommit(4): {{UploadID: 1, Root: "", Indexer: "lsif-go", Distance: 3}},
+		}
+		if diff := cmp.Diff(expected, nearestUploads); diff != "" {
+			t.Errorf("unexpected nearest uploads (-want +got):\n%s", diff)
+		}
+	}
+}
+
 func TestDeleteOverlappingDumps(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
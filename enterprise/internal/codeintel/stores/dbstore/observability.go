@@ -50,6 +50,7 @@ type operations struct {
 	markIndexErrored                       *observation.Operation
 	markQueued                             *observation.Operation
 	markRepositoryAsDirty                  *observation.Operation
+	nearestUploadsForCommits               *observation.Operation
 	queueSize                              *observation.Operation
 	referenceIDsAndFilters                 *observation.Operation
 	referencesForUpload                    *observation.Operation
@@ -138,6 +139,7 @@ func newOperations(observationContext *observation.Context) *operations {
 		markIndexErrored:                       op("MarkIndexErrored"),
 		markQueued:                             op("MarkQueued"),
 		markRepositoryAsDirty:                  op("MarkRepositoryAsDirty"),
+		nearestUploadsForCommits:               op("NearestUploadsForCommits"),
 		queueSize:                              op("QueueSize"),
 		referenceIDsAndFilters:                 op("ReferenceIDsAndFilters"),
 		referencesForUpload:                    op("ReferencesForUpload"),
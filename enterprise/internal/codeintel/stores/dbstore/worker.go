@@ -30,6 +30,10 @@ var uploadWorkerStoreOptions = dbworkerstore.Options{
 	OrderByExpression: sqlf.Sprintf("u.uploaded_at, u.id"),
 	StalledMaxAge:     StalledUploadMaxAge,
 	MaxNumResets:      UploadMaxNumResets,
+
+	// Round-robin across repositories so that a burst of uploads for a single
+	// repository does not hold up the uploads of every other repository.
+	FairnessKeyExpression: sqlf.Sprintf("u.repository_id"),
 }
 
 func WorkerutilUploadStore(s basestore.ShareableStore, observationContext *observation.Context) dbworkerstore.Store {
@@ -56,6 +60,10 @@ var indexWorkerStoreOptions = dbworkerstore.Options{
 	OrderByExpression: sqlf.Sprintf("u.queued_at, u.id"),
 	StalledMaxAge:     StalledIndexMaxAge,
 	MaxNumResets:      IndexMaxNumResets,
+
+	// Round-robin across repositories so that a burst of index jobs for a single
+	// repository does not hold up the index jobs of every other repository.
+	FairnessKeyExpression: sqlf.Sprintf("u.repository_id"),
 }
 
 func WorkerutilIndexStore(s basestore.ShareableStore, observationContext *observation.Context) dbworkerstore.Store {
@@ -14,6 +14,7 @@ type operations struct {
 	diagnostics                   *observation.Operation
 	exists                        *observation.Operation
 	hover                         *observation.Operation
+	implementations               *observation.Operation
 	monikerResults                *observation.Operation
 	monikersByPosition            *observation.Operation
 	packageInformation            *observation.Operation
@@ -29,6 +30,7 @@ type operations struct {
 	documentationAtPosition       *observation.Operation
 	writeDefinitions              *observation.Operation
 	writeDocuments                *observation.Operation
+	writeImplementations          *observation.Operation
 	writeMeta                     *observation.Operation
 	writeReferences               *observation.Operation
 	writeResultChunks             *observation.Operation
@@ -72,6 +74,7 @@ func newOperations(observationContext *observation.Context) *operations {
 		diagnostics:                   op("Diagnostics"),
 		exists:                        op("Exists"),
 		hover:                         op("Hover"),
+		implementations:               op("Implementations"),
 		monikerResults:                op("MonikerResults"),
 		monikersByPosition:            op("MonikersByPosition"),
 		packageInformation:            op("PackageInformation"),
@@ -87,6 +90,7 @@ func newOperations(observationContext *observation.Context) *operations {
 		documentationAtPosition:       op("DocumentationAtPosition"),
 		writeDefinitions:              op("WriteDefinitions"),
 		writeDocuments:                op("WriteDocuments"),
+		writeImplementations:          op("WriteImplementations"),
 		writeMeta:                     op("WriteMeta"),
 		writeReferences:               op("WriteReferences"),
 		writeResultChunks:             op("WriteResultChunks"),
@@ -0,0 +1,517 @@
+package uploadstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// azureAPIVersion is the version of the Blob service REST API targeted by this client.
+const azureAPIVersion = "2020-04-08"
+
+// azureBlockSize is the maximum number of bytes staged in a single block when
+// uploading an object.
+const azureBlockSize = 8 * 1024 * 1024
+
+type azureStore struct {
+	container    string
+	manageBucket bool
+	endpoint     string
+	config       AzureConfig
+	accountKey   []byte
+	client       *http.Client
+	expirer      *expirer
+	operations   *operations
+}
+
+var _ Store = &azureStore{}
+
+type AzureConfig struct {
+	AccountName string
+	AccountKey  string
+	SASToken    string
+	Endpoint    string
+}
+
+func (c *AzureConfig) load(parent *env.BaseConfig) {
+	c.AccountName = parent.Get("PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_NAME", "", "The name of the storage account containing the blob container.")
+	c.AccountKey = parent.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_KEY", "A shared key of the storage account.")
+	c.SASToken = parent.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_SAS_TOKEN", "A shared access signature with access to the blob container. Used in place of an account key.")
+	c.Endpoint = parent.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_ENDPOINT", "The target blob service endpoint. Defaults to the public Azure endpoint of the storage account.")
+
+	if c.AccountKey == "" && c.SASToken == "" {
+		parent.AddError(errors.New("invalid Azure configuration: one of PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_KEY or PRECISE_CODE_INTEL_UPLOAD_AZURE_SAS_TOKEN must be supplied"))
+	}
+}
+
+// newAzureFromConfig creates a new store backed by Azure Blob Storage or a service
+// compatible with its REST API.
+func newAzureFromConfig(ctx context.Context, config *Config, operations *operations) (Store, error) {
+	return newAzureWithClient(http.DefaultClient, config.Bucket, config.TTL, config.ManageBucket, config.Azure, operations)
+}
+
+func newAzureWithClient(client *http.Client, container string, ttl time.Duration, manageBucket bool, config AzureConfig, operations *operations) (*azureStore, error) {
+	accountKey, err := base64.StdEncoding.DecodeString(config.AccountKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode account key")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", config.AccountName)
+	}
+
+	s := &azureStore{
+		container:    container,
+		manageBucket: manageBucket,
+		endpoint:     strings.TrimSuffix(endpoint, "/"),
+		config:       config,
+		accountKey:   accountKey,
+		client:       client,
+		operations:   operations,
+	}
+
+	s.expirer = newExpirer(ttl, s.expireObjects)
+	return s, nil
+}
+
+func (s *azureStore) Init(ctx context.Context) error {
+	if !s.manageBucket {
+		return nil
+	}
+
+	if err := s.create(ctx); err != nil {
+		return errors.Wrap(err, "failed to create container")
+	}
+
+	if err := s.expirer.sweep(ctx); err != nil {
+		return errors.Wrap(err, "failed to expire objects")
+	}
+
+	return nil
+}
+
+func (s *azureStore) Get(ctx context.Context, key string) (_ io.ReadCloser, err error) {
+	ctx, endObservation := s.operations.get.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	resp, err := s.getObject(ctx, key, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+
+	reader := writeToPipe(func(w io.Writer) error {
+		zeroReads := 0
+		byteOffset := int64(0)
+
+		for {
+			n, err := ioCopyHook(w, resp.Body)
+			resp.Body.Close()
+			if err == nil || !isConnectionResetError(err) {
+				return err
+			}
+
+			byteOffset += n
+			log15.Warn("Transient error while reading payload", "key", key, "error", err)
+
+			if n == 0 {
+				zeroReads++
+
+				if zeroReads > maxZeroReads {
+					return errNoDownloadProgress
+				}
+			} else {
+				zeroReads = 0
+			}
+
+			// Resume reading from where the previous response was interrupted
+			if resp, err = s.getObject(ctx, key, byteOffset); err != nil {
+				return errors.Wrap(err, "failed to get object")
+			}
+		}
+	})
+
+	return io.NopCloser(reader), nil
+}
+
+// getObject requests the content of the given key starting at the given byte offset.
+func (s *azureStore) getObject(ctx context.Context, key string, byteOffset int64) (*http.Response, error) {
+	var header http.Header
+	if byteOffset > 0 {
+		header = http.Header{"X-Ms-Range": {fmt.Sprintf("bytes=%d-", byteOffset)}}
+	}
+
+	return s.do(ctx, "GET", key, nil, header, nil)
+}
+
+func (s *azureStore) Upload(ctx context.Context, key string, r io.Reader) (_ int64, err error) {
+	ctx, endObservation := s.operations.upload.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	n, err := s.uploadBlocks(ctx, key, r)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to upload object")
+	}
+
+	if s.manageBucket {
+		s.expirer.sweepIfDue(ctx)
+	}
+
+	return n, nil
+}
+
+func (s *azureStore) Compose(ctx context.Context, destination string, sources ...string) (_ int64, err error) {
+	ctx, endObservation := s.operations.compose.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("destination", destination),
+		log.String("sources", strings.Join(sources, ", ")),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	defer func() {
+		if err == nil {
+			// Delete sources on success
+			if err := s.deleteSources(ctx, sources); err != nil {
+				log15.Error("Failed to delete source objects", "error", err)
+			}
+		}
+	}()
+
+	// The blob service can only copy blocks from a source blob by URL, which requires
+	// the source to be readable by the service itself. Stream the sources through this
+	// process instead so that composition works regardless of the credentials in use.
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	go func() {
+		_ = pw.CloseWithError(s.readSourcesInto(ctx, pw, sources))
+	}()
+
+	n, err := s.uploadBlocks(ctx, destination, pr)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to compose objects")
+	}
+
+	return n, nil
+}
+
+// readSourcesInto writes the concatenated content of the given source objects into
+// the given writer.
+func (s *azureStore) readSourcesInto(ctx context.Context, w io.Writer, sources []string) error {
+	for _, source := range sources {
+		resp, err := s.do(ctx, "GET", source, nil, nil, nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to get source object")
+		}
+
+		_, err = io.Copy(w, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *azureStore) Delete(ctx context.Context, key string) (err error) {
+	ctx, endObservation := s.operations.delete.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return errors.Wrap(s.deleteObject(ctx, key), "failed to delete object")
+}
+
+func (s *azureStore) create(ctx context.Context) error {
+	resp, err := s.do(ctx, "PUT", "", url.Values{"restype": {"container"}}, nil, nil)
+	if err != nil {
+		if azureErrorCode(err) == "ContainerAlreadyExists" {
+			return nil
+		}
+
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// uploadBlocks stages the content of the given reader as a sequence of blocks and
+// then commits the block list as the content of the object at the given key. This
+// allows uploading objects of unknown length without buffering them entirely.
+func (s *azureStore) uploadBlocks(ctx context.Context, key string, r io.Reader) (int64, error) {
+	var (
+		total    int64
+		blockIDs []string
+		buf      = make([]byte, azureBlockSize)
+	)
+
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%010d", len(blockIDs))))
+			query := url.Values{"comp": {"block"}, "blockid": {blockID}}
+
+			resp, err := s.do(ctx, "PUT", key, query, nil, bytes.NewReader(buf[:n]))
+			if err != nil {
+				return 0, errors.Wrap(err, "failed to stage block")
+			}
+			resp.Body.Close()
+
+			total += int64(n)
+			blockIDs = append(blockIDs, blockID)
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return 0, readErr
+		}
+	}
+
+	var blockList bytes.Buffer
+	blockList.WriteString(`<?xml version="1.0" encoding="utf-8"?><BlockList>`)
+	for _, blockID := range blockIDs {
+		blockList.WriteString("<Latest>" + blockID + "</Latest>")
+	}
+	blockList.WriteString("</BlockList>")
+
+	resp, err := s.do(ctx, "PUT", key, url.Values{"comp": {"blocklist"}}, nil, bytes.NewReader(blockList.Bytes()))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to commit block list")
+	}
+	resp.Body.Close()
+
+	return total, nil
+}
+
+func (s *azureStore) deleteObject(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, "DELETE", key, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (s *azureStore) deleteSources(ctx context.Context, sources []string) error {
+	return goroutine.RunWorkersOverStrings(sources, func(index int, source string) error {
+		if err := s.deleteObject(ctx, source); err != nil {
+			return errors.Wrap(err, "failed to delete source object")
+		}
+
+		return nil
+	})
+}
+
+type azureListBlobsResult struct {
+	Blobs []struct {
+		Name       string `xml:"Name"`
+		Properties struct {
+			LastModified string `xml:"Last-Modified"`
+		} `xml:"Properties"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// expireObjects removes all blobs in the container last modified before the given time.
+func (s *azureStore) expireObjects(ctx context.Context, olderThan time.Time) error {
+	marker := ""
+	for {
+		query := url.Values{"restype": {"container"}, "comp": {"list"}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+
+		resp, err := s.do(ctx, "GET", "", query, nil, nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to list objects")
+		}
+
+		var result azureListBlobsResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return errors.Wrap(err, "failed to decode object list")
+		}
+
+		for _, blob := range result.Blobs {
+			lastModified, err := http.ParseTime(blob.Properties.LastModified)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse object modification time")
+			}
+
+			if lastModified.Before(olderThan) {
+				if err := s.deleteObject(ctx, blob.Name); err != nil && azureErrorCode(err) != "BlobNotFound" {
+					return errors.Wrap(err, "failed to delete expired object")
+				}
+			}
+		}
+
+		if result.NextMarker == "" {
+			return nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// azureError is returned when the blob service responds with a non-successful status.
+type azureError struct {
+	StatusCode int
+	Code       string
+}
+
+func (e *azureError) Error() string {
+	return fmt.Sprintf("unexpected status code %d (%s)", e.StatusCode, e.Code)
+}
+
+func azureErrorCode(err error) string {
+	var e *azureError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	return ""
+}
+
+// do performs an authenticated request against the container, or against the object at
+// the given key if non-empty. The given headers are added to the request. The caller is
+// responsible for closing the response body.
+func (s *azureStore) do(ctx context.Context, method, key string, query url.Values, header http.Header, body *bytes.Reader) (*http.Response, error) {
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.container
+	if key != "" {
+		u.Path += "/" + key
+	}
+
+	if query == nil {
+		query = url.Values{}
+	}
+	if s.config.SASToken != "" {
+		sasQuery, err := url.ParseQuery(strings.TrimPrefix(s.config.SASToken, "?"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse SAS token")
+		}
+		for name, values := range sasQuery {
+			query[name] = values
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	var requestBody io.Reader
+	var contentLength int64
+	if body != nil {
+		requestBody = body
+		contentLength = body.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), requestBody)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = contentLength
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	if s.config.SASToken == "" {
+		req.Header.Set("Authorization", "SharedKey "+s.config.AccountName+":"+s.sign(req))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, &azureError{StatusCode: resp.StatusCode, Code: resp.Header.Get("x-ms-error-code")}
+	}
+
+	return resp, nil
+}
+
+// sign returns the shared key signature of the given request. See
+// https://docs.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key.
+func (s *azureStore) sign(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	var msHeaders []string
+	for name := range req.Header {
+		if name := strings.ToLower(name); strings.HasPrefix(name, "x-ms-") {
+			msHeaders = append(msHeaders, name)
+		}
+	}
+	sort.Strings(msHeaders)
+
+	var canonicalizedHeaders strings.Builder
+	for _, name := range msHeaders {
+		canonicalizedHeaders.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+
+	query := req.URL.Query()
+	var queryNames []string
+	for name := range query {
+		queryNames = append(queryNames, name)
+	}
+	sort.Strings(queryNames)
+
+	var canonicalizedResource strings.Builder
+	canonicalizedResource.WriteString("/" + s.config.AccountName + req.URL.EscapedPath())
+	for _, name := range queryNames {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		canonicalizedResource.WriteString("\n" + strings.ToLower(name) + ":" + strings.Join(values, ","))
+	}
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date (superseded by x-ms-date)
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		canonicalizedHeaders.String() + canonicalizedResource.String(),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, s.accountKey)
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
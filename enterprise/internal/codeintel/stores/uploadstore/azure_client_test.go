@@ -0,0 +1,407 @@
+package uploadstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestAzureInit(t *testing.T) {
+	server := newFakeAzureServer(t)
+	client := testAzureClient(t, server, true)
+
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing client: %s", err)
+	}
+	if !server.containerExists {
+		t.Errorf("expected container to be created")
+	}
+
+	// Creating an existing container is not an error
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing client: %s", err)
+	}
+}
+
+func TestAzureUnmanagedInit(t *testing.T) {
+	server := newFakeAzureServer(t)
+	client := testAzureClient(t, server, false)
+
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing client: %s", err)
+	}
+	if server.containerExists {
+		t.Errorf("expected container not to be created")
+	}
+}
+
+func TestAzureUploadAndGet(t *testing.T) {
+	server := newFakeAzureServer(t)
+	client := testAzureClient(t, server, false)
+
+	payload := strings.Repeat("TEST PAYLOAD ", azureBlockSize/8)
+	size, err := client.Upload(context.Background(), "test-key", strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+	if size != int64(len(payload)) {
+		t.Errorf("unexpected size. want=%d have=%d", len(payload), size)
+	}
+	if server.blocksStaged != 2 {
+		t.Errorf("unexpected number of staged blocks. want=%d have=%d", 2, server.blocksStaged)
+	}
+
+	if contents := readAzureObject(t, client, "test-key"); contents != payload {
+		t.Errorf("unexpected contents. want=%d bytes have=%d bytes", len(payload), len(contents))
+	}
+}
+
+func TestAzureGetMissing(t *testing.T) {
+	server := newFakeAzureServer(t)
+	client := testAzureClient(t, server, false)
+
+	if _, err := client.Get(context.Background(), "test-key"); err == nil {
+		t.Fatalf("expected error getting missing object")
+	} else if code := azureErrorCode(err); code != "BlobNotFound" {
+		t.Errorf("unexpected error code. want=%s have=%s", "BlobNotFound", code)
+	}
+}
+
+func TestAzureGetTransientErrors(t *testing.T) {
+	// read 50 bytes then return a connection reset error
+	setAzureIOCopyHook(t, func(w io.Writer, r io.Reader) (int64, error) {
+		n, err := io.CopyN(w, r, 50)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		return n, errors.New("read: connection reset by peer")
+	})
+
+	server := newFakeAzureServer(t)
+	client := testAzureClient(t, server, false)
+
+	if _, err := client.Upload(context.Background(), "test-key", bytes.NewReader(fullContents)); err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+
+	if contents := readAzureObject(t, client, "test-key"); contents != string(fullContents) {
+		t.Errorf("unexpected contents. want=%d bytes have=%d bytes", len(fullContents), len(contents))
+	}
+
+	expectedRanges := []string{""}
+	for offset := 50; offset <= len(fullContents); offset += 50 {
+		expectedRanges = append(expectedRanges, fmt.Sprintf("bytes=%d-", offset))
+	}
+	if diff := cmp.Diff(expectedRanges, server.ranges); diff != "" {
+		t.Errorf("unexpected requested ranges (-want +got):\n%s", diff)
+	}
+}
+
+func TestAzureGetReadNothingLoop(t *testing.T) {
+	// read nothing then return a connection reset error
+	setAzureIOCopyHook(t, func(w io.Writer, r io.Reader) (int64, error) {
+		return 0, errors.New("read: connection reset by peer")
+	})
+
+	server := newFakeAzureServer(t)
+	client := testAzureClient(t, server, false)
+
+	if _, err := client.Upload(context.Background(), "test-key", strings.NewReader("TEST PAYLOAD")); err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+
+	rc, err := client.Get(context.Background(), "test-key")
+	if err != nil {
+		t.Fatalf("unexpected error getting key: %s", err)
+	}
+	defer rc.Close()
+
+	if _, err := io.ReadAll(rc); err != errNoDownloadProgress {
+		t.Fatalf("unexpected error reading object. want=%q have=%q", errNoDownloadProgress, err)
+	}
+}
+
+func TestAzureCombine(t *testing.T) {
+	server := newFakeAzureServer(t)
+	client := testAzureClient(t, server, false)
+
+	for key, payload := range map[string]string{"test-src1": "foo", "test-src2": "bar", "test-src3": "baz"} {
+		if _, err := client.Upload(context.Background(), key, strings.NewReader(payload)); err != nil {
+			t.Fatalf("unexpected error uploading object: %s", err)
+		}
+	}
+
+	size, err := client.Compose(context.Background(), "test-key", "test-src1", "test-src2", "test-src3")
+	if err != nil {
+		t.Fatalf("unexpected error composing objects: %s", err)
+	}
+	if size != 9 {
+		t.Errorf("unexpected size. want=%d have=%d", 9, size)
+	}
+
+	if contents := readAzureObject(t, client, "test-key"); contents != "foobarbaz" {
+		t.Errorf("unexpected contents. want=%s have=%s", "foobarbaz", contents)
+	}
+	if diff := cmp.Diff([]string{"test-key"}, server.keys()); diff != "" {
+		t.Errorf("unexpected objects (-want +got):\n%s", diff)
+	}
+}
+
+func TestAzureDelete(t *testing.T) {
+	server := newFakeAzureServer(t)
+	client := testAzureClient(t, server, false)
+
+	if _, err := client.Upload(context.Background(), "test-key", strings.NewReader("TEST PAYLOAD")); err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+	if err := client.Delete(context.Background(), "test-key"); err != nil {
+		t.Fatalf("unexpected error deleting object: %s", err)
+	}
+
+	if len(server.keys()) != 0 {
+		t.Errorf("expected object to be deleted")
+	}
+}
+
+func TestAzureExpiration(t *testing.T) {
+	server := newFakeAzureServer(t)
+	client := testAzureClient(t, server, true)
+
+	for _, key := range []string{"test-old", "test-new"} {
+		if _, err := client.Upload(context.Background(), key, strings.NewReader("TEST PAYLOAD")); err != nil {
+			t.Fatalf("unexpected error uploading object: %s", err)
+		}
+	}
+	server.blobs["test-old"].lastModified = time.Now().Add(-48 * time.Hour)
+
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing client: %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"test-new"}, server.keys()); diff != "" {
+		t.Errorf("unexpected objects (-want +got):\n%s", diff)
+	}
+}
+
+func TestAzureSASToken(t *testing.T) {
+	server := newFakeAzureServer(t)
+	server.sasToken = "sv=2020-04-08&sig=test-signature"
+
+	client, err := newAzureWithClient(server.Client(), "test-container", 24*time.Hour, false, AzureConfig{
+		AccountName: "testaccount",
+		SASToken:    "?" + server.sasToken,
+		Endpoint:    server.URL,
+	}, newOperations(&observation.TestContext))
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %s", err)
+	}
+
+	if _, err := client.Upload(context.Background(), "test-key", strings.NewReader("TEST PAYLOAD")); err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+}
+
+func setAzureIOCopyHook(t *testing.T, hook func(w io.Writer, r io.Reader) (int64, error)) {
+	ioCopyHook = hook
+	t.Cleanup(func() { ioCopyHook = io.Copy })
+}
+
+func testAzureClient(t *testing.T, server *fakeAzureServer, manageBucket bool) Store {
+	client, err := newAzureWithClient(server.Client(), "test-container", 24*time.Hour, manageBucket, AzureConfig{
+		AccountName: "testaccount",
+		AccountKey:  base64.StdEncoding.EncodeToString([]byte("test-key")),
+		Endpoint:    server.URL,
+	}, newOperations(&observation.TestContext))
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %s", err)
+	}
+
+	return client
+}
+
+func readAzureObject(t *testing.T, client Store, key string) string {
+	rc, err := client.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("unexpected error getting object: %s", err)
+	}
+	defer rc.Close()
+
+	contents, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("unexpected error reading object: %s", err)
+	}
+
+	return string(contents)
+}
+
+type fakeAzureBlob struct {
+	content      []byte
+	lastModified time.Time
+}
+
+// fakeAzureServer is a minimal in-memory implementation of the subset of the
+// blob service REST API used by the Azure store.
+type fakeAzureServer struct {
+	*httptest.Server
+	t               *testing.T
+	m               sync.Mutex
+	sasToken        string
+	containerExists bool
+	blobs           map[string]*fakeAzureBlob
+	staged          map[string][]byte
+	blocksStaged    int
+	ranges          []string // the x-ms-range header of each object read
+}
+
+func newFakeAzureServer(t *testing.T) *fakeAzureServer {
+	s := &fakeAzureServer{
+		t:      t,
+		blobs:  map[string]*fakeAzureBlob{},
+		staged: map[string][]byte{},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeAzureServer) keys() []string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	keys := make([]string, 0, len(s.blobs))
+	for key := range s.blobs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *fakeAzureServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if r.Header.Get("x-ms-version") != azureAPIVersion {
+		s.t.Errorf("unexpected API version. want=%s have=%s", azureAPIVersion, r.Header.Get("x-ms-version"))
+	}
+	if s.sasToken != "" {
+		if r.URL.Query().Get("sig") != "test-signature" {
+			s.t.Errorf("expected SAS token in request query")
+		}
+	} else if !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey testaccount:") {
+		s.t.Errorf("unexpected authorization header: %s", r.Header.Get("Authorization"))
+	}
+
+	query := r.URL.Query()
+	key := strings.TrimPrefix(r.URL.Path, "/test-container/")
+
+	switch {
+	case r.Method == "PUT" && query.Get("restype") == "container":
+		if s.containerExists {
+			writeFakeAzureError(w, http.StatusConflict, "ContainerAlreadyExists")
+			return
+		}
+		s.containerExists = true
+		w.WriteHeader(http.StatusCreated)
+
+	case r.Method == "GET" && query.Get("comp") == "list":
+		var result struct {
+			XMLName xml.Name `xml:"EnumerationResults"`
+			Blobs   []struct {
+				Name         string `xml:"Name"`
+				LastModified string `xml:"Properties>Last-Modified"`
+			} `xml:"Blobs>Blob"`
+		}
+		for name, blob := range s.blobs {
+			result.Blobs = append(result.Blobs, struct {
+				Name         string `xml:"Name"`
+				LastModified string `xml:"Properties>Last-Modified"`
+			}{name, blob.lastModified.UTC().Format(http.TimeFormat)})
+		}
+		_ = xml.NewEncoder(w).Encode(result)
+
+	case r.Method == "PUT" && query.Get("comp") == "block":
+		content, _ := io.ReadAll(r.Body)
+		s.staged[key+"/"+query.Get("blockid")] = content
+		s.blocksStaged++
+		w.WriteHeader(http.StatusCreated)
+
+	case r.Method == "PUT" && query.Get("comp") == "blocklist":
+		var blockList struct {
+			Latest []string `xml:"Latest"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&blockList); err != nil {
+			s.t.Errorf("unexpected error decoding block list: %s", err)
+		}
+
+		var content []byte
+		for _, blockID := range blockList.Latest {
+			block, ok := s.staged[key+"/"+blockID]
+			if !ok {
+				writeFakeAzureError(w, http.StatusBadRequest, "InvalidBlockList")
+				return
+			}
+			content = append(content, block...)
+		}
+		s.blobs[key] = &fakeAzureBlob{content: content, lastModified: time.Now()}
+		w.WriteHeader(http.StatusCreated)
+
+	case r.Method == "GET":
+		blob, ok := s.blobs[key]
+		if !ok {
+			writeFakeAzureError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+
+		byteRange := r.Header.Get("x-ms-range")
+		s.ranges = append(s.ranges, byteRange)
+		if byteRange == "" {
+			_, _ = w.Write(blob.content)
+			return
+		}
+
+		var offset int
+		if _, err := fmt.Sscanf(byteRange, "bytes=%d-", &offset); err != nil || offset > len(blob.content) {
+			writeFakeAzureError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(blob.content[offset:])
+
+	case r.Method == "DELETE":
+		if _, ok := s.blobs[key]; !ok {
+			writeFakeAzureError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		delete(s.blobs, key)
+		w.WriteHeader(http.StatusAccepted)
+
+	default:
+		s.t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func writeFakeAzureError(w http.ResponseWriter, statusCode int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(statusCode)
+	fmt.Fprintf(w, "<Error><Code>%s</Code></Error>", code)
+}
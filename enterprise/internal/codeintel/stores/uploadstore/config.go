@@ -18,6 +18,8 @@ type Config struct {
 	TTL          time.Duration
 	S3           S3Config
 	GCS          GCSConfig
+	Local        LocalConfig
+	Azure        AzureConfig
 }
 
 type loader interface {
@@ -25,12 +27,12 @@ type loader interface {
 }
 
 func (c *Config) Load() {
-	c.Backend = strings.ToLower(c.Get("PRECISE_CODE_INTEL_UPLOAD_BACKEND", "MinIO", "The target file service for code intelligence uploads. S3, GCS, and MinIO are supported."))
+	c.Backend = strings.ToLower(c.Get("PRECISE_CODE_INTEL_UPLOAD_BACKEND", "MinIO", "The target file service for code intelligence uploads. S3, GCS, MinIO, Azure, and Local are supported."))
 	c.ManageBucket = c.GetBool("PRECISE_CODE_INTEL_UPLOAD_MANAGE_BUCKET", "false", "Whether or not the client should manage the target bucket configuration.")
 	c.Bucket = c.Get("PRECISE_CODE_INTEL_UPLOAD_BUCKET", "lsif-uploads", "The name of the bucket to store LSIF uploads in.")
 	c.TTL = c.GetInterval("PRECISE_CODE_INTEL_UPLOAD_TTL", "168h", "The maximum age of an upload before deletion.")
 
-	if c.Backend == "minio" {
+	if c.Backend == "minio" || c.Backend == "local" {
 		// No manual provisioning
 		c.ManageBucket = true
 	}
@@ -39,11 +41,13 @@ func (c *Config) Load() {
 		"s3":    &c.S3,
 		"minio": &c.S3,
 		"gcs":   &c.GCS,
+		"azure": &c.Azure,
+		"local": &c.Local,
 	}
 
 	config, ok := loaders[c.Backend]
 	if !ok {
-		c.AddError(errors.Errorf("invalid backend %q for PRECISE_CODE_INTEL_UPLOAD_BACKEND: must be S3, GCS, or MinIO", c.Backend))
+		c.AddError(errors.Errorf("invalid backend %q for PRECISE_CODE_INTEL_UPLOAD_BACKEND: must be S3, GCS, MinIO, Azure, or Local", c.Backend))
 		return
 	}
 
@@ -189,6 +189,75 @@ func TestConfigGCS(t *testing.T) {
 	}
 }
 
+func TestConfigLocal(t *testing.T) {
+	env := map[string]string{
+		"PRECISE_CODE_INTEL_UPLOAD_BACKEND":   "Local",
+		"PRECISE_CODE_INTEL_UPLOAD_BUCKET":    "lsif-uploads",
+		"PRECISE_CODE_INTEL_UPLOAD_TTL":       "8h",
+		"PRECISE_CODE_INTEL_UPLOAD_LOCAL_DIR": "/data/uploads",
+	}
+
+	config := Config{}
+	config.SetMockGetter(mapGetter(env))
+	config.Load()
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	if !config.ManageBucket {
+		t.Errorf("expected local bucket to be managed")
+	}
+	if config.Local.Dir != "/data/uploads" {
+		t.Errorf("unexpected value for Local.Dir. want=%s have=%s", "/data/uploads", config.Local.Dir)
+	}
+}
+
+func TestConfigAzure(t *testing.T) {
+	env := map[string]string{
+		"PRECISE_CODE_INTEL_UPLOAD_BACKEND":            "Azure",
+		"PRECISE_CODE_INTEL_UPLOAD_BUCKET":             "lsif-uploads",
+		"PRECISE_CODE_INTEL_UPLOAD_TTL":                "8h",
+		"PRECISE_CODE_INTEL_UPLOAD_MANAGE_BUCKET":      "true",
+		"PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_NAME": "test-account",
+		"PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_KEY":  "dGVzdC1rZXk=",
+		"PRECISE_CODE_INTEL_UPLOAD_AZURE_ENDPOINT":     "http://azurite:10000/test-account",
+	}
+
+	config := Config{}
+	config.SetMockGetter(mapGetter(env))
+	config.Load()
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	if config.Azure.AccountName != "test-account" {
+		t.Errorf("unexpected value for Azure.AccountName. want=%s have=%s", "test-account", config.Azure.AccountName)
+	}
+	if config.Azure.AccountKey != "dGVzdC1rZXk=" {
+		t.Errorf("unexpected value for Azure.AccountKey. want=%s have=%s", "dGVzdC1rZXk=", config.Azure.AccountKey)
+	}
+	if config.Azure.Endpoint != "http://azurite:10000/test-account" {
+		t.Errorf("unexpected value for Azure.Endpoint. want=%s have=%s", "http://azurite:10000/test-account", config.Azure.Endpoint)
+	}
+}
+
+func TestConfigAzureMissingCredentials(t *testing.T) {
+	env := map[string]string{
+		"PRECISE_CODE_INTEL_UPLOAD_BACKEND":            "Azure",
+		"PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_NAME": "test-account",
+	}
+
+	config := Config{}
+	config.SetMockGetter(mapGetter(env))
+	config.Load()
+
+	if err := config.Validate(); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
+
 func mapGetter(env map[string]string) func(name, defaultValue, description string) string {
 	return func(name, defaultValue, description string) string {
 		if v, ok := env[name]; ok {
@@ -0,0 +1,53 @@
+package uploadstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+)
+
+// expirationInterval is the minimum duration between two sweeps of expired objects
+// for backends that do not support native lifecycle rules.
+const expirationInterval = time.Hour
+
+// expirer periodically removes objects older than a TTL. This emulates the bucket
+// lifecycle rules we configure on S3 and GCS for backends lacking such a feature.
+type expirer struct {
+	ttl       time.Duration
+	expire    func(ctx context.Context, olderThan time.Time) error
+	m         sync.Mutex
+	lastSweep time.Time
+}
+
+func newExpirer(ttl time.Duration, expire func(ctx context.Context, olderThan time.Time) error) *expirer {
+	return &expirer{ttl: ttl, expire: expire}
+}
+
+// sweep removes all objects older than the TTL.
+func (e *expirer) sweep(ctx context.Context) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	now := time.Now()
+	e.lastSweep = now
+	return e.expire(ctx, now.Add(-e.ttl))
+}
+
+// sweepIfDue removes all objects older than the TTL if no sweep has happened within
+// the expiration interval. Errors are logged rather than returned so that failure to
+// expire old objects does not fail the operation that triggered the sweep.
+func (e *expirer) sweepIfDue(ctx context.Context) {
+	e.m.Lock()
+	due := time.Since(e.lastSweep) >= expirationInterval
+	e.m.Unlock()
+
+	if !due {
+		return
+	}
+
+	if err := e.sweep(ctx); err != nil {
+		log15.Error("Failed to expire objects", "error", err)
+	}
+}
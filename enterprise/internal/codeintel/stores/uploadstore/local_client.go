@@ -0,0 +1,269 @@
+package uploadstore
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+type localStore struct {
+	root         string
+	manageBucket bool
+	expirer      *expirer
+	operations   *operations
+}
+
+var _ Store = &localStore{}
+
+type LocalConfig struct {
+	Dir string
+}
+
+func (c *LocalConfig) load(parent *env.BaseConfig) {
+	c.Dir = parent.Get("PRECISE_CODE_INTEL_UPLOAD_LOCAL_DIR", "/lsif-storage/uploads", "The directory in which the bucket directory is created.")
+}
+
+// newLocalFromConfig creates a new store backed by the local filesystem.
+func newLocalFromConfig(ctx context.Context, config *Config, operations *operations) (Store, error) {
+	return newLocal(filepath.Join(config.Local.Dir, config.Bucket), config.TTL, config.ManageBucket, operations), nil
+}
+
+func newLocal(root string, ttl time.Duration, manageBucket bool, operations *operations) *localStore {
+	s := &localStore{
+		root:         root,
+		manageBucket: manageBucket,
+		operations:   operations,
+	}
+
+	s.expirer = newExpirer(ttl, s.expireObjects)
+	return s
+}
+
+func (s *localStore) Init(ctx context.Context) error {
+	if !s.manageBucket {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.root, os.ModePerm); err != nil {
+		return errors.Wrap(err, "failed to create bucket directory")
+	}
+
+	if err := s.expirer.sweep(ctx); err != nil {
+		return errors.Wrap(err, "failed to expire objects")
+	}
+
+	return nil
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (_ io.ReadCloser, err error) {
+	ctx, endObservation := s.operations.get.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+
+	return f, nil
+}
+
+func (s *localStore) Upload(ctx context.Context, key string, r io.Reader) (_ int64, err error) {
+	ctx, endObservation := s.operations.upload.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	n, err := s.write(key, func(w io.Writer) (int64, error) {
+		return io.Copy(w, r)
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to upload object")
+	}
+
+	if s.manageBucket {
+		s.expirer.sweepIfDue(ctx)
+	}
+
+	return n, nil
+}
+
+func (s *localStore) Compose(ctx context.Context, destination string, sources ...string) (_ int64, err error) {
+	ctx, endObservation := s.operations.compose.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("destination", destination),
+		log.String("sources", strings.Join(sources, ", ")),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	defer func() {
+		if err == nil {
+			// Delete sources on success
+			if err := s.deleteSources(sources); err != nil {
+				log15.Error("Failed to delete source objects", "error", err)
+			}
+		}
+	}()
+
+	n, err := s.write(destination, func(w io.Writer) (int64, error) {
+		var total int64
+		for _, source := range sources {
+			n, err := s.copyObject(w, source)
+			if err != nil {
+				return 0, err
+			}
+
+			total += n
+		}
+
+		return total, nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to compose objects")
+	}
+
+	return n, nil
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) (err error) {
+	ctx, endObservation := s.operations.delete.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(os.Remove(path), "failed to delete object")
+}
+
+// path returns the path of the file backing the given key. Keys that would
+// resolve to a path outside of the bucket directory are rejected.
+func (s *localStore) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(s.root)+string(os.PathSeparator)) {
+		return "", errors.Errorf("invalid key %q", key)
+	}
+
+	return path, nil
+}
+
+// write invokes the given function with a temporary file which is moved to the
+// path of the given key once the function returns successfully. Readers of the
+// key will never observe a partially written object.
+func (s *localStore) write(key string, fn func(w io.Writer) (int64, error)) (_ int64, err error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	n, err := fn(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (s *localStore) copyObject(w io.Writer, key string) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return io.Copy(w, f)
+}
+
+func (s *localStore) deleteSources(sources []string) error {
+	for _, source := range sources {
+		path, err := s.path(source)
+		if err != nil {
+			return err
+		}
+
+		if err := os.Remove(path); err != nil {
+			return errors.Wrap(err, "failed to delete source object")
+		}
+	}
+
+	return nil
+}
+
+// expireObjects removes all files in the bucket directory last modified before
+// the given time.
+func (s *localStore) expireObjects(ctx context.Context, olderThan time.Time) error {
+	return filepath.WalkDir(s.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if info.ModTime().Before(olderThan) {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		return ctx.Err()
+	})
+}
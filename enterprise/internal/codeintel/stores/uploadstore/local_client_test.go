@@ -0,0 +1,166 @@
+package uploadstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestLocalInit(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "test-bucket")
+
+	client := testLocalClient(root, true)
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing client: %s", err)
+	}
+
+	if info, err := os.Stat(root); err != nil {
+		t.Fatalf("unexpected error statting bucket directory: %s", err)
+	} else if !info.IsDir() {
+		t.Errorf("expected bucket directory to be created")
+	}
+}
+
+func TestLocalUnmanagedInit(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "test-bucket")
+
+	client := testLocalClient(root, false)
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing client: %s", err)
+	}
+
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Errorf("expected bucket directory not to be created")
+	}
+}
+
+func TestLocalUploadAndGet(t *testing.T) {
+	client := testLocalClient(t.TempDir(), true)
+
+	size, err := client.Upload(context.Background(), "test-key", strings.NewReader("TEST PAYLOAD"))
+	if err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+	if size != 12 {
+		t.Errorf("unexpected size. want=%d have=%d", 12, size)
+	}
+
+	if contents := readLocalObject(t, client, "test-key"); contents != "TEST PAYLOAD" {
+		t.Errorf("unexpected contents. want=%s have=%s", "TEST PAYLOAD", contents)
+	}
+}
+
+func TestLocalGetMissing(t *testing.T) {
+	client := testLocalClient(t.TempDir(), true)
+
+	if _, err := client.Get(context.Background(), "test-key"); err == nil {
+		t.Fatalf("expected error getting missing object")
+	}
+}
+
+func TestLocalInvalidKey(t *testing.T) {
+	root := t.TempDir()
+	client := testLocalClient(filepath.Join(root, "test-bucket"), true)
+
+	if _, err := client.Upload(context.Background(), "../escaped", strings.NewReader("TEST PAYLOAD")); err == nil {
+		t.Fatalf("expected error uploading object outside of bucket")
+	}
+	if _, err := os.Stat(filepath.Join(root, "escaped")); !os.IsNotExist(err) {
+		t.Errorf("expected object outside of bucket not to be written")
+	}
+}
+
+func TestLocalCombine(t *testing.T) {
+	client := testLocalClient(t.TempDir(), true)
+
+	for key, payload := range map[string]string{"test-src1": "foo", "test-src2": "bar", "test-src3": "baz"} {
+		if _, err := client.Upload(context.Background(), key, strings.NewReader(payload)); err != nil {
+			t.Fatalf("unexpected error uploading object: %s", err)
+		}
+	}
+
+	size, err := client.Compose(context.Background(), "test-key", "test-src1", "test-src2", "test-src3")
+	if err != nil {
+		t.Fatalf("unexpected error composing objects: %s", err)
+	}
+	if size != 9 {
+		t.Errorf("unexpected size. want=%d have=%d", 9, size)
+	}
+
+	if contents := readLocalObject(t, client, "test-key"); contents != "foobarbaz" {
+		t.Errorf("unexpected contents. want=%s have=%s", "foobarbaz", contents)
+	}
+
+	for _, key := range []string{"test-src1", "test-src2", "test-src3"} {
+		if _, err := client.Get(context.Background(), key); err == nil {
+			t.Errorf("expected source object %s to be deleted", key)
+		}
+	}
+}
+
+func TestLocalDelete(t *testing.T) {
+	client := testLocalClient(t.TempDir(), true)
+
+	if _, err := client.Upload(context.Background(), "test-key", strings.NewReader("TEST PAYLOAD")); err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+	if err := client.Delete(context.Background(), "test-key"); err != nil {
+		t.Fatalf("unexpected error deleting object: %s", err)
+	}
+
+	if _, err := client.Get(context.Background(), "test-key"); err == nil {
+		t.Errorf("expected object to be deleted")
+	}
+}
+
+func TestLocalExpiration(t *testing.T) {
+	root := t.TempDir()
+	client := testLocalClient(root, true)
+
+	for _, key := range []string{"test-old", "test-new"} {
+		if _, err := client.Upload(context.Background(), key, strings.NewReader("TEST PAYLOAD")); err != nil {
+			t.Fatalf("unexpected error uploading object: %s", err)
+		}
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "test-old"), old, old); err != nil {
+		t.Fatalf("unexpected error changing modification time: %s", err)
+	}
+
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing client: %s", err)
+	}
+
+	if _, err := client.Get(context.Background(), "test-old"); err == nil {
+		t.Errorf("expected expired object to be deleted")
+	}
+	if contents := readLocalObject(t, client, "test-new"); contents != "TEST PAYLOAD" {
+		t.Errorf("unexpected contents. want=%s have=%s", "TEST PAYLOAD", contents)
+	}
+}
+
+func testLocalClient(root string, manageBucket bool) Store {
+	return newLocal(root, 24*time.Hour, manageBucket, newOperations(&observation.TestContext))
+}
+
+func readLocalObject(t *testing.T, client Store, key string) string {
+	rc, err := client.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("unexpected error getting object: %s", err)
+	}
+	defer rc.Close()
+
+	contents, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("unexpected error reading object: %s", err)
+	}
+
+	return string(contents)
+}
@@ -34,6 +34,8 @@ var storeConstructors = map[string]func(ctx context.Context, config *Config, ope
 	"s3":    newS3FromConfig,
 	"minio": newS3FromConfig,
 	"gcs":   newGCSFromConfig,
+	"azure": newAzureFromConfig,
+	"local": newLocalFromConfig,
 }
 
 // CreateLazy initialize a new store from the given configuration that is initialized
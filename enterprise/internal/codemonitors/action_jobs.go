@@ -15,9 +15,13 @@ import (
 
 type ActionJob struct {
 	Id           int
-	Email        int64
 	TriggerEvent int
 
+	// Exactly one of Email, Webhook and SlackWebhook is non-nil.
+	Email        *int64
+	Webhook      *int64
+	SlackWebhook *int64
+
 	// Fields demanded by any dbworker.
 	State          string
 	FailureMessage *string
@@ -45,6 +49,8 @@ type ActionJobMetadata struct {
 var ActionJobsColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_action_jobs.id"),
 	sqlf.Sprintf("cm_action_jobs.email"),
+	sqlf.Sprintf("cm_action_jobs.webhook"),
+	sqlf.Sprintf("cm_action_jobs.slack_webhook"),
 	sqlf.Sprintf("cm_action_jobs.trigger_event"),
 	sqlf.Sprintf("cm_action_jobs.state"),
 	sqlf.Sprintf("cm_action_jobs.failure_message"),
@@ -56,8 +62,8 @@ var ActionJobsColumns = []*sqlf.Query{
 	sqlf.Sprintf("cm_action_jobs.log_contents"),
 }
 
-const readActionEmailEventsFmtStr = `
-SELECT id, email, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
+const readActionEventsFmtStr = `
+SELECT id, email, webhook, slack_webhook, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
 FROM cm_action_jobs
 WHERE %s
 AND id > %s
@@ -66,18 +72,25 @@ LIMIT %s;
 `
 
 func (s *Store) ReadActionEmailEvents(ctx context.Context, emailID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) (js []*ActionJob, err error) {
-	var where *sqlf.Query
-	if triggerEventID == nil {
-		where = sqlf.Sprintf("email = %s", emailID)
-	} else {
-		where = sqlf.Sprintf("email = %s AND trigger_event = %s", emailID, *triggerEventID)
-	}
+	return s.readActionEvents(ctx, "email", emailID, triggerEventID, args)
+}
+
+func (s *Store) ReadActionWebhookEvents(ctx context.Context, webhookID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) (js []*ActionJob, err error) {
+	return s.readActionEvents(ctx, "webhook", webhookID, triggerEventID, args)
+}
+
+func (s *Store) ReadActionSlackWebhookEvents(ctx context.Context, slackWebhookID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) (js []*ActionJob, err error) {
+	return s.readActionEvents(ctx, "slack_webhook", slackWebhookID, triggerEventID, args)
+}
+
+func (s *Store) readActionEvents(ctx context.Context, actionColumn string, actionID int64, triggerEventID *int, args *graphqlbackend.ListEventsArgs) (js []*ActionJob, err error) {
+	where := actionEventsWhere(actionColumn, actionID, triggerEventID)
 	var rows *sql.Rows
 	after, err := unmarshalAfter(args.After)
 	if err != nil {
 		return nil, err
 	}
-	rows, err = s.Query(ctx, sqlf.Sprintf(readActionEmailEventsFmtStr, where, after, args.First))
+	rows, err = s.Query(ctx, sqlf.Sprintf(readActionEventsFmtStr, where, after, args.First))
 	if err != nil {
 		return nil, err
 	}
@@ -85,26 +98,43 @@ func (s *Store) ReadActionEmailEvents(ctx context.Context, emailID int64, trigge
 	return scanActionJobs(rows, err)
 }
 
-const totalActionEmailEventsFmtStr = `
+const totalActionEventsFmtStr = `
 SELECT COUNT(*)
 FROM cm_action_jobs
 WHERE %s
 `
 
 func (s *Store) TotalActionEmailEvents(ctx context.Context, emailID int64, triggerEventID *int) (totalCount int32, err error) {
-	var where *sqlf.Query
-	if triggerEventID == nil {
-		where = sqlf.Sprintf("email = %s", emailID)
-	} else {
-		where = sqlf.Sprintf("email = %s AND trigger_event = %s", emailID, *triggerEventID)
-	}
-	err = s.QueryRow(ctx, sqlf.Sprintf(totalActionEmailEventsFmtStr, where)).Scan(&totalCount)
+	return s.totalActionEvents(ctx, "email", emailID, triggerEventID)
+}
+
+func (s *Store) TotalActionWebhookEvents(ctx context.Context, webhookID int64, triggerEventID *int) (totalCount int32, err error) {
+	return s.totalActionEvents(ctx, "webhook", webhookID, triggerEventID)
+}
+
+func (s *Store) TotalActionSlackWebhookEvents(ctx context.Context, slackWebhookID int64, triggerEventID *int) (totalCount int32, err error) {
+	return s.totalActionEvents(ctx, "slack_webhook", slackWebhookID, triggerEventID)
+}
+
+func (s *Store) totalActionEvents(ctx context.Context, actionColumn string, actionID int64, triggerEventID *int) (totalCount int32, err error) {
+	where := actionEventsWhere(actionColumn, actionID, triggerEventID)
+	err = s.QueryRow(ctx, sqlf.Sprintf(totalActionEventsFmtStr, where)).Scan(&totalCount)
 	if err != nil {
 		return -1, err
 	}
 	return totalCount, nil
 }
 
+// actionEventsWhere returns the condition selecting the action jobs of the
+// action with the given ID. actionColumn must be one of email, webhook or
+// slack_webhook.
+func actionEventsWhere(actionColumn string, actionID int64, triggerEventID *int) *sqlf.Query {
+	if triggerEventID == nil {
+		return sqlf.Sprintf(actionColumn+" = %s", actionID)
+	}
+	return sqlf.Sprintf(actionColumn+" = %s AND trigger_event = %s", actionID, *triggerEventID)
+}
+
 const enqueueActionEmailFmtStr = `
 WITH due AS (
 	SELECT e.id, e.monitor, e.enabled, e.priority, e.header, e.created_by, e.created_at, e.changed_by, e.changed_at
@@ -113,8 +143,8 @@ WITH due AS (
 ),
 busy AS (
     SELECT DISTINCT email as id FROM cm_action_jobs
-    WHERE state = 'queued'
-    OR state = 'processing'
+    WHERE email IS NOT NULL
+    AND (state = 'queued' OR state = 'processing')
 )
 INSERT INTO cm_action_jobs (email, trigger_event)
 SELECT id, %s::integer from due EXCEPT SELECT id, %s::integer from busy ORDER BY id
@@ -124,6 +154,49 @@ func (s *Store) EnqueueActionEmailsForQueryIDInt64(ctx context.Context, queryID
 	return s.Store.Exec(ctx, sqlf.Sprintf(enqueueActionEmailFmtStr, queryID, triggerEventID, triggerEventID))
 }
 
+const enqueueActionWebhookFmtStr = `
+WITH due AS (
+	SELECT w.id
+	FROM %s w INNER JOIN cm_queries q ON w.monitor = q.monitor
+	WHERE q.id = %s AND w.enabled = true
+),
+busy AS (
+    SELECT DISTINCT %s as id FROM cm_action_jobs
+    WHERE %s IS NOT NULL
+    AND (state = 'queued' OR state = 'processing')
+)
+INSERT INTO cm_action_jobs (%s, trigger_event)
+SELECT id, %s::integer from due EXCEPT SELECT id, %s::integer from busy ORDER BY id
+`
+
+func (s *Store) EnqueueActionWebhooksForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
+	return s.enqueueActionWebhooks(ctx, webhooksTable, queryID, triggerEventID)
+}
+
+func (s *Store) EnqueueActionSlackWebhooksForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
+	return s.enqueueActionWebhooks(ctx, slackWebhooksTable, queryID, triggerEventID)
+}
+
+func (s *Store) enqueueActionWebhooks(ctx context.Context, t webhookTable, queryID int64, triggerEventID int) error {
+	column := t.actionJobColumn()
+	return s.Store.Exec(ctx, sqlf.Sprintf(enqueueActionWebhookFmtStr, t.name(), queryID, column, column, column, triggerEventID, triggerEventID))
+}
+
+// EnqueueActionsForQueryIDInt64 enqueues one action job for every enabled
+// action (email, webhook and Slack webhook) of the monitor the query belongs to.
+func (s *Store) EnqueueActionsForQueryIDInt64(ctx context.Context, queryID int64, triggerEventID int) (err error) {
+	if err = s.EnqueueActionEmailsForQueryIDInt64(ctx, queryID, triggerEventID); err != nil {
+		return errors.Errorf("EnqueueActionEmailsForQueryIDInt64: %w", err)
+	}
+	if err = s.EnqueueActionWebhooksForQueryIDInt64(ctx, queryID, triggerEventID); err != nil {
+		return errors.Errorf("EnqueueActionWebhooksForQueryIDInt64: %w", err)
+	}
+	if err = s.EnqueueActionSlackWebhooksForQueryIDInt64(ctx, queryID, triggerEventID); err != nil {
+		return errors.Errorf("EnqueueActionSlackWebhooksForQueryIDInt64: %w", err)
+	}
+	return nil
+}
+
 const getActionJobMetadataFmtStr = `
 select cm.description, ctj.query_string, cm.id as monitorID, ctj.num_results from
 cm_action_jobs caj
@@ -144,7 +217,7 @@ func (s *Store) GetActionJobMetadata(ctx context.Context, recordID int) (m *Acti
 }
 
 const actionJobForIDFmtStr = `
-SELECT id, email, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
+SELECT id, email, webhook, slack_webhook, trigger_event, state, failure_message, started_at, finished_at, process_after, num_resets, num_failures, log_contents
 FROM cm_action_jobs
 WHERE id = %s
 `
@@ -190,6 +263,8 @@ func scanActionJobs(rows *sql.Rows, err error) ([]*ActionJob, error) {
 		if err := rows.Scan(
 			&aj.Id,
 			&aj.Email,
+			&aj.Webhook,
+			&aj.SlackWebhook,
 			&aj.TriggerEvent,
 			&aj.State,
 			&aj.FailureMessage,
@@ -37,7 +37,7 @@ func TestEnqueueActionEmailsForQueryIDInt64QueryByRecordID(t *testing.T) {
 
 	want := &ActionJob{
 		Id:             1,
-		Email:          1,
+		Email:          int64Ptr(1),
 		TriggerEvent:   1,
 		State:          "queued",
 		FailureMessage: nil,
@@ -134,3 +134,5 @@ func TestScanActionJobs(t *testing.T) {
 		t.Fatalf("got %d, want %d", record.RecordID(), testRecordID)
 	}
 }
+
+func int64Ptr(i int64) *int64 { return &i }
@@ -0,0 +1,49 @@
+package codemonitors
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+// MonitorSlackWebhook is a code monitor action which posts a message about new
+// search results to a Slack incoming webhook. Its payload template, if set,
+// renders the text of the message.
+type MonitorSlackWebhook MonitorWebhook
+
+func (s *Store) CreateActionSlackWebhook(ctx context.Context, monitorID int64, args *graphqlbackend.CreateActionSlackWebhookArgs) (*MonitorSlackWebhook, error) {
+	w, err := s.createWebhook(ctx, slackWebhooksTable, monitorID, args.Enabled, args.URL, args.PayloadTemplate)
+	return (*MonitorSlackWebhook)(w), err
+}
+
+func (s *Store) UpdateActionSlackWebhook(ctx context.Context, monitorID int64, args *graphqlbackend.EditActionSlackWebhookArgs) (*MonitorSlackWebhook, error) {
+	w, err := s.updateWebhook(ctx, slackWebhooksTable, monitorID, args.Id, args.Update.Enabled, args.Update.URL, args.Update.PayloadTemplate)
+	return (*MonitorSlackWebhook)(w), err
+}
+
+func (s *Store) DeleteActionSlackWebhooks(ctx context.Context, actionIDs []int64, monitorID int64) error {
+	return s.deleteWebhooks(ctx, slackWebhooksTable, actionIDs, monitorID)
+}
+
+func (s *Store) TotalCountActionSlackWebhooks(ctx context.Context, monitorID int64) (int32, error) {
+	return s.totalCountWebhooks(ctx, slackWebhooksTable, monitorID)
+}
+
+func (s *Store) ActionSlackWebhookByIDInt64(ctx context.Context, slackWebhookID int64) (*MonitorSlackWebhook, error) {
+	w, err := s.webhookByID(ctx, slackWebhooksTable, slackWebhookID)
+	return (*MonitorSlackWebhook)(w), err
+}
+
+// ListActionSlackWebhooks returns all Slack webhook actions of the given
+// monitor, ordered by ID.
+func (s *Store) ListActionSlackWebhooks(ctx context.Context, monitorID int64) ([]*MonitorSlackWebhook, error) {
+	ws, err := s.listWebhooks(ctx, slackWebhooksTable, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	sws := make([]*MonitorSlackWebhook, 0, len(ws))
+	for _, w := range ws {
+		sws = append(sws, (*MonitorSlackWebhook)(w))
+	}
+	return sws, nil
+}
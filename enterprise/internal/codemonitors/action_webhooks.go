@@ -0,0 +1,250 @@
+package codemonitors
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// MonitorWebhook is a code monitor action which posts a JSON payload describing
+// new search results to an arbitrary URL.
+type MonitorWebhook struct {
+	Id        int64
+	Monitor   int64
+	Enabled   bool
+	URL       string
+	CreatedBy int32
+	CreatedAt time.Time
+	ChangedBy int32
+	ChangedAt time.Time
+
+	// PayloadTemplate is the template the payload is rendered from. If nil,
+	// the default payload is sent.
+	PayloadTemplate *string
+}
+
+// webhookTable is a table storing webhook actions. Webhook and Slack webhook
+// actions are stored in separate tables with the same columns, so they share
+// the queries below.
+type webhookTable string
+
+const (
+	webhooksTable      webhookTable = "cm_webhooks"
+	slackWebhooksTable webhookTable = "cm_slack_webhooks"
+)
+
+func (t webhookTable) columns() []*sqlf.Query {
+	columns := []string{"id", "monitor", "enabled", "url", "created_by", "created_at", "changed_by", "changed_at", "payload_template"}
+	qs := make([]*sqlf.Query, 0, len(columns))
+	for _, c := range columns {
+		qs = append(qs, sqlf.Sprintf(string(t)+"."+c))
+	}
+	return qs
+}
+
+func (t webhookTable) name() *sqlf.Query {
+	return sqlf.Sprintf(string(t))
+}
+
+// actionJobColumn returns the column of cm_action_jobs referencing the actions
+// stored in the table.
+func (t webhookTable) actionJobColumn() *sqlf.Query {
+	if t == slackWebhooksTable {
+		return sqlf.Sprintf("slack_webhook")
+	}
+	return sqlf.Sprintf("webhook")
+}
+
+func (s *Store) CreateActionWebhook(ctx context.Context, monitorID int64, args *graphqlbackend.CreateActionWebhookArgs) (*MonitorWebhook, error) {
+	return s.createWebhook(ctx, webhooksTable, monitorID, args.Enabled, args.URL, args.PayloadTemplate)
+}
+
+func (s *Store) UpdateActionWebhook(ctx context.Context, monitorID int64, args *graphqlbackend.EditActionWebhookArgs) (*MonitorWebhook, error) {
+	return s.updateWebhook(ctx, webhooksTable, monitorID, args.Id, args.Update.Enabled, args.Update.URL, args.Update.PayloadTemplate)
+}
+
+func (s *Store) DeleteActionWebhooks(ctx context.Context, actionIDs []int64, monitorID int64) error {
+	return s.deleteWebhooks(ctx, webhooksTable, actionIDs, monitorID)
+}
+
+func (s *Store) TotalCountActionWebhooks(ctx context.Context, monitorID int64) (int32, error) {
+	return s.totalCountWebhooks(ctx, webhooksTable, monitorID)
+}
+
+func (s *Store) ActionWebhookByIDInt64(ctx context.Context, webhookID int64) (*MonitorWebhook, error) {
+	return s.webhookByID(ctx, webhooksTable, webhookID)
+}
+
+// ListActionWebhooks returns all webhook actions of the given monitor, ordered
+// by ID.
+func (s *Store) ListActionWebhooks(ctx context.Context, monitorID int64) ([]*MonitorWebhook, error) {
+	return s.listWebhooks(ctx, webhooksTable, monitorID)
+}
+
+const createWebhookFmtStr = `
+INSERT INTO %s
+(monitor, enabled, url, payload_template, created_by, created_at, changed_by, changed_at)
+VALUES (%s,%s,%s,%s,%s,%s,%s,%s)
+RETURNING %s;
+`
+
+func (s *Store) createWebhook(ctx context.Context, t webhookTable, monitorID int64, enabled bool, url string, payloadTemplate *string) (*MonitorWebhook, error) {
+	if err := validatePayloadTemplate(payloadTemplate); err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	a := actor.FromContext(ctx)
+	q := sqlf.Sprintf(
+		createWebhookFmtStr,
+		t.name(),
+		monitorID,
+		enabled,
+		url,
+		payloadTemplate,
+		a.UID,
+		now,
+		a.UID,
+		now,
+		sqlf.Join(t.columns(), ", "),
+	)
+	return s.runWebhookQuery(ctx, q)
+}
+
+const updateWebhookFmtStr = `
+UPDATE %s
+SET enabled = %s,
+	url = %s,
+	payload_template = %s,
+	changed_by = %s,
+	changed_at = %s
+WHERE id = %s
+AND monitor = %s
+RETURNING %s;
+`
+
+func (s *Store) updateWebhook(ctx context.Context, t webhookTable, monitorID int64, id *graphql.ID, enabled bool, url string, payloadTemplate *string) (*MonitorWebhook, error) {
+	if id == nil {
+		return nil, errors.Errorf("nil is not a valid action ID")
+	}
+	var actionID int64
+	if err := relay.UnmarshalSpec(*id, &actionID); err != nil {
+		return nil, err
+	}
+	if err := validatePayloadTemplate(payloadTemplate); err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	a := actor.FromContext(ctx)
+	q := sqlf.Sprintf(
+		updateWebhookFmtStr,
+		t.name(),
+		enabled,
+		url,
+		payloadTemplate,
+		a.UID,
+		now,
+		actionID,
+		monitorID,
+		sqlf.Join(t.columns(), ", "),
+	)
+	return s.runWebhookQuery(ctx, q)
+}
+
+const deleteWebhooksFmtStr = `DELETE FROM %s WHERE id IN (%s) AND monitor = %s`
+
+func (s *Store) deleteWebhooks(ctx context.Context, t webhookTable, actionIDs []int64, monitorID int64) error {
+	if len(actionIDs) == 0 {
+		return nil
+	}
+	return s.Exec(ctx, sqlf.Sprintf(deleteWebhooksFmtStr, t.name(), sqlf.Join(int64sToQueries(actionIDs), ", "), monitorID))
+}
+
+const totalCountWebhooksFmtStr = `
+SELECT COUNT(*)
+FROM %s
+WHERE monitor = %s;
+`
+
+func (s *Store) totalCountWebhooks(ctx context.Context, t webhookTable, monitorID int64) (count int32, err error) {
+	err = s.QueryRow(ctx, sqlf.Sprintf(totalCountWebhooksFmtStr, t.name(), monitorID)).Scan(&count)
+	return count, err
+}
+
+const webhookByIDFmtStr = `
+SELECT %s
+FROM %s
+WHERE id = %s
+`
+
+func (s *Store) webhookByID(ctx context.Context, t webhookTable, id int64) (*MonitorWebhook, error) {
+	return s.runWebhookQuery(ctx, sqlf.Sprintf(webhookByIDFmtStr, sqlf.Join(t.columns(), ", "), t.name(), id))
+}
+
+const listWebhooksFmtStr = `
+SELECT %s
+FROM %s
+WHERE monitor = %s
+ORDER BY id ASC;
+`
+
+func (s *Store) listWebhooks(ctx context.Context, t webhookTable, monitorID int64) ([]*MonitorWebhook, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(listWebhooksFmtStr, sqlf.Join(t.columns(), ", "), t.name(), monitorID))
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhooks(rows)
+}
+
+func (s *Store) runWebhookQuery(ctx context.Context, q *sqlf.Query) (*MonitorWebhook, error) {
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := scanWebhooks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(ws) == 0 {
+		return nil, errors.Errorf("operation failed. Query should have returned 1 row")
+	}
+	return ws[0], nil
+}
+
+func scanWebhooks(rows *sql.Rows) (ws []*MonitorWebhook, err error) {
+	defer func() { err = basestore.CloseRows(rows, err) }()
+	for rows.Next() {
+		w := &MonitorWebhook{}
+		if err = rows.Scan(
+			&w.Id,
+			&w.Monitor,
+			&w.Enabled,
+			&w.URL,
+			&w.CreatedBy,
+			&w.CreatedAt,
+			&w.ChangedBy,
+			&w.ChangedAt,
+			&w.PayloadTemplate,
+		); err != nil {
+			return nil, err
+		}
+		ws = append(ws, w)
+	}
+	return ws, nil
+}
+
+func int64sToQueries(ids []int64) []*sqlf.Query {
+	qs := make([]*sqlf.Query, 0, len(ids))
+	for _, id := range ids {
+		qs = append(qs, sqlf.Sprintf("%s", id))
+	}
+	return qs
+}
@@ -0,0 +1,127 @@
+package codemonitors
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+func TestActionWebhooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := newTestStore(t)
+	_, userID, _, userCTX := newTestUser(ctx, t)
+	m, err := s.insertTestMonitor(userCTX, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payloadTemplate := `{"text":{{json .Description}}}`
+	w, err := s.CreateActionWebhook(userCTX, m.ID, &graphqlbackend.CreateActionWebhookArgs{
+		Enabled:         true,
+		URL:             "https://example.com/webhook",
+		PayloadTemplate: &payloadTemplate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &MonitorWebhook{
+		Id:              1,
+		Monitor:         m.ID,
+		Enabled:         true,
+		URL:             "https://example.com/webhook",
+		CreatedBy:       userID,
+		CreatedAt:       s.Now(),
+		ChangedBy:       userID,
+		ChangedAt:       s.Now(),
+		PayloadTemplate: &payloadTemplate,
+	}
+	if diff := cmp.Diff(want, w); diff != "" {
+		t.Fatalf("unexpected webhook (-want +got):\n%s", diff)
+	}
+
+	sw, err := s.CreateActionSlackWebhook(userCTX, m.ID, &graphqlbackend.CreateActionSlackWebhookArgs{
+		Enabled: true,
+		URL:     "https://hooks.slack.com/services/1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sw.Id != 1 || sw.PayloadTemplate != nil {
+		t.Fatalf("unexpected Slack webhook: %+v", sw)
+	}
+
+	invalidTemplate := "{{.Description"
+	if _, err := s.CreateActionWebhook(userCTX, m.ID, &graphqlbackend.CreateActionWebhookArgs{URL: "https://example.com", PayloadTemplate: &invalidTemplate}); err == nil {
+		t.Fatal("webhook with invalid payload template created")
+	}
+
+	id := relay.MarshalID("CodeMonitorActionSlackWebhook", sw.Id)
+	sw, err = s.UpdateActionSlackWebhook(userCTX, m.ID, &graphqlbackend.EditActionSlackWebhookArgs{
+		Id: &id,
+		Update: &graphqlbackend.CreateActionSlackWebhookArgs{
+			Enabled:         false,
+			URL:             "https://hooks.slack.com/services/2",
+			PayloadTemplate: &payloadTemplate,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sw.Enabled || sw.URL != "https://hooks.slack.com/services/2" || sw.PayloadTemplate == nil {
+		t.Fatalf("Slack webhook not updated: %+v", sw)
+	}
+
+	ws, err := s.ListActionWebhooks(ctx, m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*MonitorWebhook{want}, ws); diff != "" {
+		t.Fatalf("unexpected webhooks (-want +got):\n%s", diff)
+	}
+
+	// Only the enabled webhook is enqueued.
+	if err := s.EnqueueTriggerQueries(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.EnqueueActionsForQueryIDInt64(ctx, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	count, err := s.TotalActionWebhookEvents(ctx, w.Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("got %d webhook action jobs, want 1", count)
+	}
+	count, err = s.TotalActionSlackWebhookEvents(ctx, sw.Id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatalf("got %d Slack webhook action jobs, want 0", count)
+	}
+
+	if err := s.DeleteActionWebhooks(ctx, []int64{w.Id}, m.ID); err != nil {
+		t.Fatal(err)
+	}
+	count, err = s.TotalCountActionWebhooks(ctx, m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatalf("got %d webhooks after deletion, want 0", count)
+	}
+	count, err = s.TotalCountActionSlackWebhooks(ctx, m.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("got %d Slack webhooks, want 1", count)
+	}
+}
@@ -0,0 +1,33 @@
+package background
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/slack"
+)
+
+// defaultSlackMessageTemplate renders the text of the message posted by a Slack
+// webhook action without a payload template.
+var defaultSlackMessageTemplate = mustParsePayloadTemplate("slack", `Code monitor *{{escape .Description}}* found {{.NumResults}} new {{if eq .NumResults 1}}search result{{else}}search results{{end}}.
+<{{.SearchURL}}|View search results> | <{{.CodeMonitorURL}}|Edit code monitor>`)
+
+// newSlackPayload renders the message posted by a Slack webhook action, its
+// text from its payload template, or the default one if it has none.
+func newSlackPayload(payloadTemplate *string, data *templateDataNewSearchResults) (*slack.Payload, error) {
+	var b strings.Builder
+	if err := executePayloadTemplate(&b, defaultSlackMessageTemplate, payloadTemplate, data); err != nil {
+		return nil, errors.Wrap(err, "slack: render message template")
+	}
+	return &slack.Payload{
+		Username:  "Sourcegraph code monitor",
+		IconEmoji: ":mag:",
+		Text:      b.String(),
+	}, nil
+}
+
+func sendSlackNotification(ctx context.Context, url string, payload *slack.Payload) error {
+	return slack.New(url).Post(ctx, payload)
+}
@@ -0,0 +1,112 @@
+package background
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/email"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+const (
+	utmSourceWebhook      = "code-monitoring-webhook"
+	utmSourceSlackWebhook = "code-monitoring-slack-webhook"
+)
+
+// templateDataNewSearchResults is the data the payloads of webhook and Slack
+// webhook actions are rendered from.
+type templateDataNewSearchResults struct {
+	Description    string
+	Query          string
+	SearchURL      string
+	CodeMonitorURL string
+	NumResults     int
+}
+
+func newTemplateDataNewSearchResults(ctx context.Context, m *cm.ActionJobMetadata, utmSource string) (*templateDataNewSearchResults, error) {
+	searchURL, err := email.GetSearchURL(ctx, m.Query, utmSource)
+	if err != nil {
+		return nil, err
+	}
+	codeMonitorURL, err := email.GetCodeMonitorURL(ctx, m.MonitorID, utmSource)
+	if err != nil {
+		return nil, err
+	}
+	return &templateDataNewSearchResults{
+		Description:    m.Description,
+		Query:          m.Query,
+		SearchURL:      searchURL,
+		CodeMonitorURL: codeMonitorURL,
+		NumResults:     zeroOrVal(m.NumResults),
+	}, nil
+}
+
+// defaultWebhookPayloadTemplate renders the JSON body posted to the URL of a
+// webhook action without a payload template.
+var defaultWebhookPayloadTemplate = mustParsePayloadTemplate("webhook", `{"monitorDescription":{{json .Description}},"monitorURL":{{json .CodeMonitorURL}},"query":{{json .Query}},"queryURL":{{json .SearchURL}},"numResults":{{.NumResults}}}`)
+
+// newWebhookPayload renders the JSON body posted to the URL of a webhook
+// action from its payload template, or the default one if it has none.
+func newWebhookPayload(payloadTemplate *string, data *templateDataNewSearchResults) ([]byte, error) {
+	var b bytes.Buffer
+	if err := executePayloadTemplate(&b, defaultWebhookPayloadTemplate, payloadTemplate, data); err != nil {
+		return nil, errors.Wrap(err, "webhook: render payload template")
+	}
+	if !json.Valid(b.Bytes()) {
+		return nil, errors.New("webhook: payload template rendered invalid JSON")
+	}
+	return b.Bytes(), nil
+}
+
+// executePayloadTemplate renders the given payload template of an action, or
+// the default template if it's nil.
+func executePayloadTemplate(w io.Writer, defaultTemplate *template.Template, payloadTemplate *string, data *templateDataNewSearchResults) error {
+	t := defaultTemplate
+	if payloadTemplate != nil {
+		var err error
+		if t, err = cm.ParsePayloadTemplate("payload", *payloadTemplate); err != nil {
+			return err
+		}
+	}
+	return t.Execute(w, data)
+}
+
+func mustParsePayloadTemplate(name, text string) *template.Template {
+	t, err := cm.ParsePayloadTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// sendWebhookNotification posts the JSON body to url. Any non-2xx response is
+// treated as an error so that the action job is retried.
+func sendWebhookNotification(ctx context.Context, doer httpcli.Doer, url string, body []byte) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(timeoutCtx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "webhook: create post request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "webhook: http request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("webhook: request failed with %d %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
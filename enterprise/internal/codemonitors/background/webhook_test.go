@@ -0,0 +1,145 @@
+package background
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSendWebhookNotification(t *testing.T) {
+	data := &templateDataNewSearchResults{
+		Description:    "test description",
+		Query:          "test patternType:literal",
+		SearchURL:      "https://www.sourcegraph.com/search?q=test",
+		CodeMonitorURL: "https://www.sourcegraph.com/code-monitoring/1",
+		NumResults:     3,
+	}
+
+	body, err := newWebhookPayload(nil, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type webhookPayload struct {
+		MonitorDescription string `json:"monitorDescription"`
+		MonitorURL         string `json:"monitorURL"`
+		Query              string `json:"query"`
+		QueryURL           string `json:"queryURL"`
+		NumResults         int    `json:"numResults"`
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var got webhookPayload
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("unexpected content type %q", ct)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		if err := sendWebhookNotification(context.Background(), http.DefaultClient, srv.URL, body); err != nil {
+			t.Fatal(err)
+		}
+
+		want := webhookPayload{
+			MonitorDescription: "test description",
+			MonitorURL:         "https://www.sourcegraph.com/code-monitoring/1",
+			Query:              "test patternType:literal",
+			QueryURL:           "https://www.sourcegraph.com/search?q=test",
+			NumResults:         3,
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("unexpected payload (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		if err := sendWebhookNotification(context.Background(), http.DefaultClient, srv.URL, body); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestNewWebhookPayload(t *testing.T) {
+	data := &templateDataNewSearchResults{
+		Description: `say "hi"`,
+		NumResults:  2,
+	}
+
+	t.Run("payload template", func(t *testing.T) {
+		tmpl := `{"text":{{json (printf "%s: %d" .Description .NumResults)}}}`
+		have, err := newWebhookPayload(&tmpl, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(`{"text":"say \"hi\": 2"}`, string(have)); diff != "" {
+			t.Fatalf("unexpected payload (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		tmpl := `{"text":{{.Description}}}`
+		if _, err := newWebhookPayload(&tmpl, data); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestNewSlackPayload(t *testing.T) {
+	tests := []struct {
+		name            string
+		payloadTemplate *string
+		numResults      int
+		want            string
+	}{
+		{
+			name:       "1 result",
+			numResults: 1,
+			want: "Code monitor *a &lt;b&gt; &amp; c* found 1 new search result.\n" +
+				"<https://www.sourcegraph.com/search?q=test|View search results> | <https://www.sourcegraph.com/code-monitoring/1|Edit code monitor>",
+		},
+		{
+			name:       "5 results",
+			numResults: 5,
+			want: "Code monitor *a &lt;b&gt; &amp; c* found 5 new search results.\n" +
+				"<https://www.sourcegraph.com/search?q=test|View search results> | <https://www.sourcegraph.com/code-monitoring/1|Edit code monitor>",
+		},
+		{
+			name:            "payload template",
+			payloadTemplate: strPtr("{{escape .Description}}: {{.NumResults}} results, see {{.SearchURL}}"),
+			numResults:      5,
+			want:            "a &lt;b&gt; &amp; c: 5 results, see https://www.sourcegraph.com/search?q=test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := newSlackPayload(tt.payloadTemplate, &templateDataNewSearchResults{
+				Description:    "a <b> & c",
+				SearchURL:      "https://www.sourcegraph.com/search?q=test",
+				CodeMonitorURL: "https://www.sourcegraph.com/code-monitoring/1",
+				NumResults:     tt.numResults,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, payload.Text); diff != "" {
+				t.Fatalf("unexpected text (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
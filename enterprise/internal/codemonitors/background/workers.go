@@ -13,6 +13,7 @@ import (
 	cm "github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codemonitors/email"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -85,7 +86,7 @@ func newActionRunner(ctx context.Context, s *cm.Store, metrics codeMonitorsMetri
 		HeartbeatInterval: 15 * time.Second,
 		Metrics:           metrics.workerMetrics,
 	}
-	worker := dbworker.NewWorker(ctx, createDBWorkerStoreForActionJobs(s), &actionRunner{s}, options)
+	worker := dbworker.NewWorker(ctx, createDBWorkerStoreForActionJobs(s), &actionRunner{Store: s, doer: httpcli.ExternalDoer()}, options)
 	return worker
 }
 
@@ -165,9 +166,9 @@ func (r *queryRunner) Handle(ctx context.Context, record workerutil.Record) (err
 		numResults = len(results.Data.Search.Results.Results)
 	}
 	if numResults > 0 {
-		err := s.EnqueueActionEmailsForQueryIDInt64(ctx, q.Id, record.RecordID())
+		err := s.EnqueueActionsForQueryIDInt64(ctx, q.Id, record.RecordID())
 		if err != nil {
-			return errors.Errorf("store.EnqueueActionEmailsForQueryIDInt64: %w", err)
+			return errors.Errorf("store.EnqueueActionsForQueryIDInt64: %w", err)
 		}
 	}
 	// Log next_run and latest_result to table cm_queries.
@@ -186,6 +187,9 @@ func (r *queryRunner) Handle(ctx context.Context, record workerutil.Record) (err
 
 type actionRunner struct {
 	*cm.Store
+
+	// doer is used to deliver webhook actions.
+	doer httpcli.Doer
 }
 
 func (r *actionRunner) Handle(ctx context.Context, record workerutil.Record) (err error) {
@@ -202,36 +206,40 @@ func (r *actionRunner) Handle(ctx context.Context, record workerutil.Record) (er
 	}
 	defer func() { err = s.Done(err) }()
 
-	var (
-		j    *cm.ActionJob
-		m    *cm.ActionJobMetadata
-		e    *cm.MonitorEmail
-		recs []*cm.Recipient
-		data *email.TemplateDataNewSearchResults
-	)
-
-	var ok bool
-	j, ok = record.(*cm.ActionJob)
+	j, ok := record.(*cm.ActionJob)
 	if !ok {
 		return errors.Errorf("type assertion failed")
 	}
 
-	m, err = s.GetActionJobMetadata(ctx, record.RecordID())
+	m, err := s.GetActionJobMetadata(ctx, record.RecordID())
 	if err != nil {
 		return errors.Errorf("store.GetActionJobMetadata: %w", err)
 	}
 
-	e, err = s.ActionEmailByIDInt64(ctx, j.Email)
+	switch {
+	case j.Email != nil:
+		return r.handleEmail(ctx, s, j, m)
+	case j.Webhook != nil:
+		return r.handleWebhook(ctx, s, j, m)
+	case j.SlackWebhook != nil:
+		return r.handleSlackWebhook(ctx, s, j, m)
+	default:
+		return errors.Errorf("action job %d has no action", j.Id)
+	}
+}
+
+func (r *actionRunner) handleEmail(ctx context.Context, s *cm.Store, j *cm.ActionJob, m *cm.ActionJobMetadata) error {
+	e, err := s.ActionEmailByIDInt64(ctx, *j.Email)
 	if err != nil {
 		return errors.Errorf("store.ActionEmailByIDInt64: %w", err)
 	}
 
-	recs, err = s.AllRecipientsForEmailIDInt64(ctx, j.Email)
+	recs, err := s.AllRecipientsForEmailIDInt64(ctx, *j.Email)
 	if err != nil {
 		return errors.Errorf("store.AllRecipientsForEmailIDInt64: %w", err)
 	}
 
-	data, err = email.NewTemplateDataForNewSearchResults(ctx, m.Description, m.Query, e, zeroOrVal(m.NumResults))
+	data, err := email.NewTemplateDataForNewSearchResults(ctx, m.Description, m.Query, e, zeroOrVal(m.NumResults))
 	if err != nil {
 		return errors.Errorf("email.NewTemplateDataForNewSearchResults: %w", err)
 	}
@@ -251,6 +259,40 @@ func (r *actionRunner) Handle(ctx context.Context, record workerutil.Record) (er
 	return nil
 }
 
+func (r *actionRunner) handleWebhook(ctx context.Context, s *cm.Store, j *cm.ActionJob, m *cm.ActionJobMetadata) error {
+	w, err := s.ActionWebhookByIDInt64(ctx, *j.Webhook)
+	if err != nil {
+		return errors.Errorf("store.ActionWebhookByIDInt64: %w", err)
+	}
+
+	data, err := newTemplateDataNewSearchResults(ctx, m, utmSourceWebhook)
+	if err != nil {
+		return errors.Errorf("newTemplateDataNewSearchResults: %w", err)
+	}
+	body, err := newWebhookPayload(w.PayloadTemplate, data)
+	if err != nil {
+		return err
+	}
+	return sendWebhookNotification(ctx, r.doer, w.URL, body)
+}
+
+func (r *actionRunner) handleSlackWebhook(ctx context.Context, s *cm.Store, j *cm.ActionJob, m *cm.ActionJobMetadata) error {
+	w, err := s.ActionSlackWebhookByIDInt64(ctx, *j.SlackWebhook)
+	if err != nil {
+		return errors.Errorf("store.ActionSlackWebhookByIDInt64: %w", err)
+	}
+
+	data, err := newTemplateDataNewSearchResults(ctx, m, utmSourceSlackWebhook)
+	if err != nil {
+		return errors.Errorf("newTemplateDataNewSearchResults: %w", err)
+	}
+	payload, err := newSlackPayload(w.PayloadTemplate, data)
+	if err != nil {
+		return err
+	}
+	return sendSlackNotification(ctx, w.URL, payload)
+}
+
 // newQueryWithAfterFilter constructs a new query which finds search results
 // introduced after the last time we queried.
 func newQueryWithAfterFilter(q *cm.MonitorQuery) string {
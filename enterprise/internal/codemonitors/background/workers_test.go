@@ -105,7 +105,7 @@ func TestActionRunner(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			a := actionRunner{s}
+			a := actionRunner{Store: s}
 			err = a.Handle(ctx, record)
 			if err != nil {
 				t.Fatal(err)
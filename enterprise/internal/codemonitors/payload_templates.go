@@ -0,0 +1,46 @@
+package codemonitors
+
+import (
+	"encoding/json"
+	"strings"
+	"text/template"
+
+	"github.com/cockroachdb/errors"
+)
+
+// slackEscaper escapes the control characters of Slack's message formatting,
+// see https://api.slack.com/reference/surfaces/formatting#escaping.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// payloadTemplateFuncs are the functions available in the payload templates
+// of webhook and Slack webhook actions.
+var payloadTemplateFuncs = template.FuncMap{
+	// json encodes a value as JSON, for use in webhook payloads.
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	// escape escapes text for use in Slack messages.
+	"escape": slackEscaper.Replace,
+}
+
+// ParsePayloadTemplate parses the payload template of a webhook or Slack
+// webhook action. Templates are rendered with the description, query, search
+// URL, code monitor URL and number of results of a code monitor event.
+func ParsePayloadTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(payloadTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid payload template")
+	}
+	return t, nil
+}
+
+// validatePayloadTemplate returns an error if the given payload template is
+// set but can't be parsed.
+func validatePayloadTemplate(text *string) error {
+	if text == nil {
+		return nil
+	}
+	_, err := ParsePayloadTemplate("payload", *text)
+	return err
+}
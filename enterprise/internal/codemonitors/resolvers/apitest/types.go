@@ -50,7 +50,13 @@ type ActionConnection struct {
 }
 
 type Action struct {
+	Typename string `json:"__typename"`
 	ActionEmail
+
+	// URL and PayloadTemplate are only set for webhook and Slack webhook
+	// actions.
+	URL             string
+	PayloadTemplate *string
 }
 
 type ActionEmail struct {
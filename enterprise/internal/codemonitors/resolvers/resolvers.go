@@ -182,7 +182,7 @@ func (r *Resolver) UpdateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 	}
 	defer func() { err = tx.store.Done(err) }()
 
-	err = tx.store.DeleteActionsInt64(ctx, toDelete, monitorID)
+	err = tx.deleteActions(ctx, toDelete, monitorID)
 	if err != nil {
 		return nil, err
 	}
@@ -252,36 +252,100 @@ func sendTestEmail(ctx context.Context, recipient graphql.ID, description string
 	return email.SendEmailForNewSearchResult(ctx, userID, data)
 }
 
+// actionIDsForMonitorIDInt64 returns the IDs of all actions (emails, webhooks
+// and Slack webhooks) of the given monitor.
 func (r *Resolver) actionIDsForMonitorIDInt64(ctx context.Context, monitorID int64) (actionIDs []graphql.ID, err error) {
+	actions, err := r.actionsForMonitorIDInt64(ctx, nil, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	actionIDs = make([]graphql.ID, 0, len(actions))
+	for _, a := range actions {
+		id, err := actionID(a)
+		if err != nil {
+			return nil, err
+		}
+		actionIDs = append(actionIDs, id)
+	}
+	return actionIDs, nil
+}
+
+// actionsForMonitorIDInt64 returns all actions of the given monitor. Emails come
+// first, followed by webhooks and Slack webhooks, each ordered by ID.
+func (r *Resolver) actionsForMonitorIDInt64(ctx context.Context, triggerEventID *int, monitorID int64) ([]graphqlbackend.MonitorAction, error) {
+	es, err := r.emailsForMonitorIDInt64(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := r.store.ListActionWebhooks(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	sws, err := r.store.ListActionSlackWebhooks(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]graphqlbackend.MonitorAction, 0, len(es)+len(ws)+len(sws))
+	for _, e := range es {
+		actions = append(actions, &action{
+			email: &monitorEmail{
+				Resolver:       r,
+				MonitorEmail:   e,
+				triggerEventID: triggerEventID,
+			},
+		})
+	}
+	for _, w := range ws {
+		actions = append(actions, &action{
+			webhook: &monitorWebhook{
+				Resolver:       r,
+				MonitorWebhook: w,
+				triggerEventID: triggerEventID,
+			},
+		})
+	}
+	for _, w := range sws {
+		actions = append(actions, &action{
+			slackWebhook: &monitorSlackWebhook{
+				Resolver:            r,
+				MonitorSlackWebhook: w,
+				triggerEventID:      triggerEventID,
+			},
+		})
+	}
+	return actions, nil
+}
+
+func (r *Resolver) emailsForMonitorIDInt64(ctx context.Context, monitorID int64) ([]*cm.MonitorEmail, error) {
 	limit := 50
 	var (
-		ids   []graphql.ID
+		all   []*cm.MonitorEmail
 		after *string
-		q     *sqlf.Query
 	)
 	// Paging.
 	for {
-		q, err = r.store.ReadActionEmailQuery(ctx, monitorID, &graphqlbackend.ListActionArgs{
+		q, err := r.store.ReadActionEmailQuery(ctx, monitorID, &graphqlbackend.ListActionArgs{
 			First: int32(limit),
 			After: after,
 		})
 		if err != nil {
 			return nil, err
 		}
-		es, cur, err := r.actionIDsForMonitorIDINT64SinglePage(ctx, q, limit)
+		es, cur, err := r.emailsForMonitorIDInt64SinglePage(ctx, q, limit)
 		if err != nil {
 			return nil, err
 		}
-		ids = append(ids, es...)
+		all = append(all, es...)
 		if cur == nil {
 			break
 		}
 		after = cur
 	}
-	return ids, nil
+	return all, nil
 }
 
-func (r *Resolver) actionIDsForMonitorIDINT64SinglePage(ctx context.Context, q *sqlf.Query, limit int) (ids []graphql.ID, cursor *string, err error) {
+func (r *Resolver) emailsForMonitorIDInt64SinglePage(ctx context.Context, q *sqlf.Query, limit int) (es []*cm.MonitorEmail, cursor *string, err error) {
 	var rows *sql.Rows
 	rows, err = r.store.Query(ctx, q)
 	if err != nil {
@@ -289,55 +353,94 @@ func (r *Resolver) actionIDsForMonitorIDINT64SinglePage(ctx context.Context, q *
 	}
 	defer rows.Close()
 
-	var es []*cm.MonitorEmail
 	es, err = cm.ScanEmails(rows)
 	if err != nil {
 		return nil, nil, err
 	}
-	ids = make([]graphql.ID, 0, len(es))
-	for _, e := range es {
-		ids = append(ids, (&monitorEmail{MonitorEmail: e}).ID())
-	}
 
 	// Set the cursor if the result size equals limit.
-	if len(ids) == limit {
-		stringID := string(ids[len(ids)-1])
+	if len(es) == limit {
+		stringID := string((&monitorEmail{MonitorEmail: es[len(es)-1]}).ID())
 		cursor = &stringID
 	}
-	return ids, cursor, nil
+	return es, cursor, nil
 }
 
 // splitActionIDs splits actions into three buckets: create, delete and update.
 // Note: args is mutated. After splitActionIDs, args only contains actions to be updated.
-func splitActionIDs(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs, actionIDs []graphql.ID) (toCreate []*graphqlbackend.CreateActionArgs, toDelete []int64, err error) {
+func splitActionIDs(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs, actionIDs []graphql.ID) (toCreate []*graphqlbackend.CreateActionArgs, toDelete []graphql.ID, err error) {
 	aMap := make(map[graphql.ID]struct{}, len(actionIDs))
 	for _, id := range actionIDs {
 		aMap[id] = struct{}{}
 	}
 	var toUpdateActions []*graphqlbackend.EditActionArgs
 	for _, a := range args.Actions {
-		if a.Email.Id == nil {
-			toCreate = append(toCreate, &graphqlbackend.CreateActionArgs{Email: a.Email.Update})
+		id, create, err := splitEditAction(a)
+		if err != nil {
+			return nil, nil, err
+		}
+		if id == nil {
+			toCreate = append(toCreate, create)
 			continue
 		}
-		if _, ok := aMap[*a.Email.Id]; !ok {
-			return nil, nil, errors.Errorf("unknown ID=%s for action", *a.Email.Id)
+		if _, ok := aMap[*id]; !ok {
+			return nil, nil, errors.Errorf("unknown ID=%s for action", *id)
 		}
 		toUpdateActions = append(toUpdateActions, a)
-		delete(aMap, *a.Email.Id)
+		delete(aMap, *id)
 	}
-	var actionID int64
 	for k := range aMap {
-		err = relay.UnmarshalSpec(k, &actionID)
-		if err != nil {
-			return nil, nil, err
-		}
-		toDelete = append(toDelete, actionID)
+		toDelete = append(toDelete, k)
 	}
 	args.Actions = toUpdateActions
 	return toCreate, toDelete, nil
 }
 
+// splitEditAction returns the ID of the action to edit together with the
+// arguments required to create it. The ID is nil for actions that don't exist
+// yet.
+func splitEditAction(a *graphqlbackend.EditActionArgs) (*graphql.ID, *graphqlbackend.CreateActionArgs, error) {
+	switch {
+	case a.Email != nil:
+		return a.Email.Id, &graphqlbackend.CreateActionArgs{Email: a.Email.Update}, nil
+	case a.Webhook != nil:
+		return a.Webhook.Id, &graphqlbackend.CreateActionArgs{Webhook: a.Webhook.Update}, nil
+	case a.SlackWebhook != nil:
+		return a.SlackWebhook.Id, &graphqlbackend.CreateActionArgs{SlackWebhook: a.SlackWebhook.Update}, nil
+	default:
+		return nil, nil, errors.Errorf("action must be one of email, webhook or slackWebhook")
+	}
+}
+
+// deleteActions deletes the actions with the given IDs, which may be of any
+// action kind.
+func (r *Resolver) deleteActions(ctx context.Context, actionIDs []graphql.ID, monitorID int64) error {
+	var emails, webhooks, slackWebhooks []int64
+	for _, id := range actionIDs {
+		var intID int64
+		if err := relay.UnmarshalSpec(id, &intID); err != nil {
+			return err
+		}
+		switch kind := relay.UnmarshalKind(id); kind {
+		case monitorActionEmailKind:
+			emails = append(emails, intID)
+		case monitorActionWebhookKind:
+			webhooks = append(webhooks, intID)
+		case monitorActionSlackWebhookKind:
+			slackWebhooks = append(slackWebhooks, intID)
+		default:
+			return errors.Errorf("unknown action kind %q", kind)
+		}
+	}
+	if err := r.store.DeleteActionsInt64(ctx, emails, monitorID); err != nil {
+		return err
+	}
+	if err := r.store.DeleteActionWebhooks(ctx, webhooks, monitorID); err != nil {
+		return err
+	}
+	return r.store.DeleteActionSlackWebhooks(ctx, slackWebhooks, monitorID)
+}
+
 func (r *Resolver) updateCodeMonitor(ctx context.Context, args *graphqlbackend.UpdateCodeMonitorArgs) (m graphqlbackend.MonitorResolver, err error) {
 	// Update monitor.
 	var mo *cm.Monitor
@@ -357,25 +460,17 @@ func (r *Resolver) updateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 			Monitor:  mo,
 		}, nil
 	}
-	var emailID int64
-	var e *cm.MonitorEmail
-	for i, action := range args.Actions {
-		if action.Email == nil {
-			return nil, errors.Errorf("missing email object for action %d", i)
+	for _, action := range args.Actions {
+		switch {
+		case action.Email != nil:
+			err = r.updateActionEmail(ctx, mo.ID, action)
+		case action.Webhook != nil:
+			_, err = r.store.UpdateActionWebhook(ctx, mo.ID, action.Webhook)
+		case action.SlackWebhook != nil:
+			_, err = r.store.UpdateActionSlackWebhook(ctx, mo.ID, action.SlackWebhook)
+		default:
+			err = errors.Errorf("action must be one of email, webhook or slackWebhook")
 		}
-		err = relay.UnmarshalSpec(*action.Email.Id, &emailID)
-		if err != nil {
-			return nil, err
-		}
-		err = r.store.DeleteRecipients(ctx, emailID)
-		if err != nil {
-			return nil, err
-		}
-		e, err = r.store.UpdateActionEmail(ctx, mo.ID, action)
-		if err != nil {
-			return nil, err
-		}
-		err = r.store.CreateRecipients(ctx, action.Email.Update.Recipients, e.Id)
 		if err != nil {
 			return nil, err
 		}
@@ -386,6 +481,23 @@ func (r *Resolver) updateCodeMonitor(ctx context.Context, args *graphqlbackend.U
 	}, nil
 }
 
+func (r *Resolver) updateActionEmail(ctx context.Context, monitorID int64, action *graphqlbackend.EditActionArgs) error {
+	var emailID int64
+	err := relay.UnmarshalSpec(*action.Email.Id, &emailID)
+	if err != nil {
+		return err
+	}
+	err = r.store.DeleteRecipients(ctx, emailID)
+	if err != nil {
+		return err
+	}
+	e, err := r.store.UpdateActionEmail(ctx, monitorID, action)
+	if err != nil {
+		return err
+	}
+	return r.store.CreateRecipients(ctx, action.Email.Update.Recipients, e.Id)
+}
+
 func (r *Resolver) transact(ctx context.Context) (*Resolver, error) {
 	txStore, err := r.store.Transact(ctx)
 	if err != nil {
@@ -521,6 +633,8 @@ const (
 	monitorTriggerQueryKind         = "CodeMonitorTriggerQuery"
 	monitorTriggerEventKind         = "CodeMonitorTriggerEvent"
 	monitorActionEmailKind          = "CodeMonitorActionEmail"
+	monitorActionWebhookKind        = "CodeMonitorActionWebhook"
+	monitorActionSlackWebhookKind   = "CodeMonitorActionSlackWebhook"
 	monitorActionEventKind          = "CodeMonitorActionEmailEvent"
 	monitorActionEmailRecipientKind = "CodeMonitorActionEmailRecipient"
 )
@@ -567,34 +681,29 @@ func (m *monitor) Actions(ctx context.Context, args *graphqlbackend.ListActionAr
 }
 
 func (r *Resolver) actionConnectionResolverWithTriggerID(ctx context.Context, triggerEventID *int, monitorID int64, args *graphqlbackend.ListActionArgs) (graphqlbackend.MonitorActionConnectionResolver, error) {
-	// For now, we only support emails as actions. Once we add other actions such as
-	// webhooks, we have to query those tables here too.
-	q, err := r.store.ReadActionEmailQuery(ctx, monitorID, args)
+	// Actions are spread across several tables, one per action kind. Monitors
+	// only have a handful of actions, so we load all of them and paginate in
+	// memory.
+	actions, err := r.actionsForMonitorIDInt64(ctx, triggerEventID, monitorID)
 	if err != nil {
 		return nil, err
 	}
-	rows, err := r.store.Query(ctx, q)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	es, err := cm.ScanEmails(rows)
-	if err != nil {
-		return nil, err
-	}
-	totalCount, err := r.store.TotalCountActionEmails(ctx, monitorID)
-	if err != nil {
-		return nil, err
+	totalCount := int32(len(actions))
+
+	if args.After != nil {
+		for i, a := range actions {
+			id, err := actionID(a)
+			if err != nil {
+				return nil, err
+			}
+			if string(id) == *args.After {
+				actions = actions[i+1:]
+				break
+			}
+		}
 	}
-	actions := make([]graphqlbackend.MonitorAction, 0, len(es))
-	for _, e := range es {
-		actions = append(actions, &action{
-			email: &monitorEmail{
-				Resolver:       r,
-				MonitorEmail:   e,
-				triggerEventID: triggerEventID,
-			},
-		})
+	if args.First >= 0 && int(args.First) < len(actions) {
+		actions = actions[:args.First]
 	}
 	return &monitorActionConnection{actions: actions, totalCount: totalCount}, nil
 }
@@ -734,24 +843,48 @@ func (a *monitorActionConnection) PageInfo(ctx context.Context) (*graphqlutil.Pa
 	if len(a.actions) == 0 {
 		return graphqlutil.HasNextPage(false), nil
 	}
-	last := a.actions[len(a.actions)-1]
-	if email, ok := last.ToMonitorEmail(); ok {
-		return graphqlutil.NextPageCursor(string(email.ID())), nil
+	id, err := actionID(a.actions[len(a.actions)-1])
+	if err != nil {
+		return nil, err
 	}
-	return nil, errors.Errorf("we only support email actions for now")
+	return graphqlutil.NextPageCursor(string(id)), nil
 }
 
 //
 // Action <<UNION>>
 //
 type action struct {
-	email graphqlbackend.MonitorEmailResolver
+	email        graphqlbackend.MonitorEmailResolver
+	webhook      graphqlbackend.MonitorWebhookResolver
+	slackWebhook graphqlbackend.MonitorSlackWebhookResolver
 }
 
 func (a *action) ToMonitorEmail() (graphqlbackend.MonitorEmailResolver, bool) {
 	return a.email, a.email != nil
 }
 
+func (a *action) ToMonitorWebhook() (graphqlbackend.MonitorWebhookResolver, bool) {
+	return a.webhook, a.webhook != nil
+}
+
+func (a *action) ToMonitorSlackWebhook() (graphqlbackend.MonitorSlackWebhookResolver, bool) {
+	return a.slackWebhook, a.slackWebhook != nil
+}
+
+// actionID returns the ID of the action, whatever its kind.
+func actionID(a graphqlbackend.MonitorAction) (graphql.ID, error) {
+	if email, ok := a.ToMonitorEmail(); ok {
+		return email.ID(), nil
+	}
+	if webhook, ok := a.ToMonitorWebhook(); ok {
+		return webhook.ID(), nil
+	}
+	if slackWebhook, ok := a.ToMonitorSlackWebhook(); ok {
+		return slackWebhook.ID(), nil
+	}
+	return "", errors.Errorf("unknown action type")
+}
+
 //
 // Email
 //
@@ -826,11 +959,89 @@ func (m *monitorEmail) Events(ctx context.Context, args *graphqlbackend.ListEven
 	if err != nil {
 		return nil, err
 	}
-	events := make([]graphqlbackend.MonitorActionEventResolver, len(ajs))
-	for i, aj := range ajs {
-		events[i] = &monitorActionEvent{Resolver: m.Resolver, ActionJob: aj}
+	return newMonitorActionEventConnection(m.Resolver, ajs, totalCount), nil
+}
+
+//
+// Webhook
+//
+type monitorWebhook struct {
+	*Resolver
+	*cm.MonitorWebhook
+
+	// If triggerEventID == nil, all events of this action will be returned.
+	// Otherwise, only those events of this action which are related to the specified
+	// trigger event will be returned.
+	triggerEventID *int
+}
+
+func (m *monitorWebhook) ID() graphql.ID {
+	return relay.MarshalID(monitorActionWebhookKind, m.Id)
+}
+
+func (m *monitorWebhook) Enabled() bool {
+	return m.MonitorWebhook.Enabled
+}
+
+func (m *monitorWebhook) URL() string {
+	return m.MonitorWebhook.URL
+}
+
+func (m *monitorWebhook) PayloadTemplate() *string {
+	return m.MonitorWebhook.PayloadTemplate
+}
+
+func (m *monitorWebhook) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
+	ajs, err := m.store.ReadActionWebhookEvents(ctx, m.Id, m.triggerEventID, args)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := m.store.TotalActionWebhookEvents(ctx, m.Id, m.triggerEventID)
+	if err != nil {
+		return nil, err
+	}
+	return newMonitorActionEventConnection(m.Resolver, ajs, totalCount), nil
+}
+
+//
+// SlackWebhook
+//
+type monitorSlackWebhook struct {
+	*Resolver
+	*cm.MonitorSlackWebhook
+
+	// If triggerEventID == nil, all events of this action will be returned.
+	// Otherwise, only those events of this action which are related to the specified
+	// trigger event will be returned.
+	triggerEventID *int
+}
+
+func (m *monitorSlackWebhook) ID() graphql.ID {
+	return relay.MarshalID(monitorActionSlackWebhookKind, m.Id)
+}
+
+func (m *monitorSlackWebhook) Enabled() bool {
+	return m.MonitorSlackWebhook.Enabled
+}
+
+func (m *monitorSlackWebhook) URL() string {
+	return m.MonitorSlackWebhook.URL
+}
+
+func (m *monitorSlackWebhook) PayloadTemplate() *string {
+	return m.MonitorSlackWebhook.PayloadTemplate
+}
+
+func (m *monitorSlackWebhook) Events(ctx context.Context, args *graphqlbackend.ListEventsArgs) (graphqlbackend.MonitorActionEventConnectionResolver, error) {
+	ajs, err := m.store.ReadActionSlackWebhookEvents(ctx, m.Id, m.triggerEventID, args)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := m.store.TotalActionSlackWebhookEvents(ctx, m.Id, m.triggerEventID)
+	if err != nil {
+		return nil, err
 	}
-	return &monitorActionEventConnection{events: events, totalCount: totalCount}, nil
+	return newMonitorActionEventConnection(m.Resolver, ajs, totalCount), nil
 }
 
 //
@@ -865,6 +1076,14 @@ type monitorActionEventConnection struct {
 	totalCount int32
 }
 
+func newMonitorActionEventConnection(r *Resolver, ajs []*cm.ActionJob, totalCount int32) *monitorActionEventConnection {
+	events := make([]graphqlbackend.MonitorActionEventResolver, len(ajs))
+	for i, aj := range ajs {
+		events[i] = &monitorActionEvent{Resolver: r, ActionJob: aj}
+	}
+	return &monitorActionEventConnection{events: events, totalCount: totalCount}
+}
+
 func (a *monitorActionEventConnection) Nodes(ctx context.Context) ([]graphqlbackend.MonitorActionEventResolver, error) {
 	return a.events, nil
 }
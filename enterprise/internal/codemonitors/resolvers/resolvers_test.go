@@ -1197,3 +1197,110 @@ func TestMonitorKindEqualsResolvers(t *testing.T) {
 		t.Fatal("email.MonitorKind should match resolvers.MonitorKind")
 	}
 }
+
+func TestWebhookActions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := backend.WithAuthzBypass(context.Background())
+	db := dbtesting.GetDB(t)
+	r := newTestResolver(t, db)
+
+	userID := insertTestUser(t, db, "cm-user1", true)
+	ctx = actor.WithActor(ctx, actor.FromUser(userID))
+
+	// Create a code monitor with a webhook and a Slack webhook action.
+	payloadTemplate := `{"text":{{json .Description}}}`
+	actionOpt := WithActions([]*graphqlbackend.CreateActionArgs{
+		{
+			Webhook: &graphqlbackend.CreateActionWebhookArgs{
+				Enabled:         true,
+				URL:             "https://example.com/webhook",
+				PayloadTemplate: &payloadTemplate,
+			},
+		},
+		{
+			SlackWebhook: &graphqlbackend.CreateActionSlackWebhookArgs{
+				Enabled: true,
+				URL:     "https://hooks.slack.com/services/1",
+			},
+		},
+	})
+	if _, err := r.insertTestMonitorWithOpts(ctx, t, actionOpt); err != nil {
+		t.Fatal(err)
+	}
+
+	// Invalid payload templates are rejected.
+	invalidTemplate := "{{.Description"
+	invalidOpt := WithActions([]*graphqlbackend.CreateActionArgs{
+		{Webhook: &graphqlbackend.CreateActionWebhookArgs{URL: "https://example.com", PayloadTemplate: &invalidTemplate}},
+	})
+	if _, err := r.insertTestMonitorWithOpts(ctx, t, invalidOpt); err == nil {
+		t.Fatal("monitor with invalid payload template created")
+	}
+
+	// Update the webhook, delete the Slack webhook and add a new one.
+	schema, err := graphqlbackend.NewSchema(db, nil, nil, nil, nil, r, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updateInput := map[string]interface{}{
+		"monitorID": string(relay.MarshalID(MonitorKind, 1)),
+		"triggerID": string(relay.MarshalID(monitorTriggerQueryKind, 1)),
+		"webhookID": string(relay.MarshalID(monitorActionWebhookKind, 1)),
+		"user1ID":   relay.MarshalID("User", userID),
+	}
+	got := apitest.UpdateCodeMonitorResponse{}
+	batchesApitest.MustExec(ctx, t, schema, updateInput, &got, editMonitorWebhooks)
+
+	slackTemplate := "{{escape .Description}}"
+	want := []apitest.Action{
+		{
+			Typename:    "MonitorWebhook",
+			ActionEmail: apitest.ActionEmail{Id: string(relay.MarshalID(monitorActionWebhookKind, 1)), Enabled: false},
+			URL:         "https://example.com/updated",
+		},
+		{
+			Typename:        "MonitorSlackWebhook",
+			ActionEmail:     apitest.ActionEmail{Id: string(relay.MarshalID(monitorActionSlackWebhookKind, 2)), Enabled: true},
+			URL:             "https://hooks.slack.com/services/2",
+			PayloadTemplate: &slackTemplate,
+		},
+	}
+	if diff := cmp.Diff(want, got.UpdateCodeMonitor.Actions.Nodes); diff != "" {
+		t.Fatalf("unexpected actions (-want +got):\n%s", diff)
+	}
+}
+
+const editMonitorWebhooks = `
+mutation ($monitorID: ID!, $triggerID: ID!, $webhookID: ID!, $user1ID: ID!) {
+  updateCodeMonitor(
+    monitor: {id: $monitorID, update: {description: "test monitor", enabled: true, namespace: $user1ID}},
+	trigger: {id: $triggerID, update: {query: "repo:foo"}},
+	actions: [
+	  {webhook: {id: $webhookID, update: {enabled: false, url: "https://example.com/updated"}}}
+	  {slackWebhook: {update: {enabled: true, url: "https://hooks.slack.com/services/2", payloadTemplate: "{{escape .Description}}"}}}
+    ]
+  )
+  {
+	actions {
+	  nodes {
+		__typename
+		... on MonitorWebhook {
+		  id
+		  enabled
+		  url
+		  payloadTemplate
+		}
+		... on MonitorSlackWebhook {
+		  id
+		  enabled
+		  url
+		  payloadTemplate
+		}
+	  }
+	}
+  }
+}
+`
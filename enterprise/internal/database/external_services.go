@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 
+	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/authz/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/internal/authz/gitlab"
@@ -27,6 +28,9 @@ func NewExternalServicesStore(db dbutil.DB) *database.ExternalServiceStore {
 	es.BitbucketServerValidators = []func(*schema.BitbucketServerConnection) error{
 		bitbucketserver.ValidateAuthz,
 	}
+	es.BitbucketCloudValidators = []func(*schema.BitbucketCloudConnection) error{
+		bitbucketcloud.ValidateAuthz,
+	}
 	es.PerforceValidators = []func(connection *schema.PerforceConnection) error{
 		perforce.ValidateAuthz,
 	}
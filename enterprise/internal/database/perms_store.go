@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -205,6 +206,17 @@ func (s *PermsStore) SetUserPermissions(ctx context.Context, p *authz.UserPermis
 		} else if err = txs.execute(ctx, q); err != nil {
 			return errors.Wrap(err, "execute upsert repo permissions batch query")
 		}
+
+		err = database.AuditLogsWith(txs).Record(ctx,
+			database.AuditLogActionUserPermissionsUpdated,
+			database.AuditLogTargetUser,
+			strconv.Itoa(int(p.UserID)),
+			auditLogPermissions{Permission: p.Perm.String(), RepoIDs: removed.ToArray()},
+			auditLogPermissions{Permission: p.Perm.String(), RepoIDs: added.ToArray()},
+		)
+		if err != nil {
+			return errors.Wrap(err, "record audit log entry")
+		}
 	}
 
 	// NOTE: The permissions background syncing heuristics relies on SyncedAt column
@@ -221,6 +233,16 @@ func (s *PermsStore) SetUserPermissions(ctx context.Context, p *authz.UserPermis
 	return nil
 }
 
+// auditLogPermissions is a change of the permissions of a user or repository
+// recorded in the audit log. As the full sets of IDs can be very large, only the
+// IDs whose access was revoked are recorded as the state before the change, and
+// only the IDs whose access was granted as the state after the change.
+type auditLogPermissions struct {
+	Permission string   `json:"permission"`
+	RepoIDs    []uint32 `json:"repoIDs,omitempty"`
+	UserIDs    []uint32 `json:"userIDs,omitempty"`
+}
+
 // upsertUserPermissionsQuery upserts single row of user permissions, it does the
 // same thing as upsertUserPermissionsBatchQuery but also updates "synced_at"
 // column to the value of p.SyncedAt field.
@@ -328,6 +350,17 @@ func (s *PermsStore) SetRepoPermissions(ctx context.Context, p *authz.RepoPermis
 		} else if err = txs.execute(ctx, q); err != nil {
 			return errors.Wrap(err, "execute upsert user permissions batch query")
 		}
+
+		err = database.AuditLogsWith(txs).Record(ctx,
+			database.AuditLogActionRepoPermissionsUpdated,
+			database.AuditLogTargetRepo,
+			strconv.Itoa(int(p.RepoID)),
+			auditLogPermissions{Permission: p.Perm.String(), UserIDs: removed.ToArray()},
+			auditLogPermissions{Permission: p.Perm.String(), UserIDs: added.ToArray()},
+		)
+		if err != nil {
+			return errors.Wrap(err, "record audit log entry")
+		}
 	}
 
 	// NOTE: The permissions background syncing heuristics relies on SyncedAt column
@@ -21,6 +21,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 )
@@ -431,6 +432,45 @@ func testPermsStore_SetUserPermissions(db *sql.DB) func(*testing.T) {
 			}
 		})
 
+		t.Run("audit log records added and removed repositories", func(t *testing.T) {
+			s := Perms(db, clock)
+			t.Cleanup(func() {
+				cleanupPermsTables(t, s)
+			})
+
+			for _, ids := range []*roaring.Bitmap{toBitmap(1, 2, 3), toBitmap(2, 3, 4)} {
+				if err := s.SetUserPermissions(context.Background(), &authz.UserPermissions{
+					UserID: 42,
+					Perm:   authz.Read,
+					Type:   authz.PermRepos,
+					IDs:    ids,
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			entries, err := database.AuditLogs(db).List(context.Background(), database.AuditLogListOptions{
+				Actions:  []database.AuditLogAction{database.AuditLogActionUserPermissionsUpdated},
+				TargetID: "42",
+				Limit:    1,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("want 1 audit log entry, got %d", len(entries))
+			}
+			var before, after auditLogPermissions
+			if err := json.Unmarshal(entries[0].Before, &before); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(entries[0].After, &after); err != nil {
+				t.Fatal(err)
+			}
+			equal(t, "before", auditLogPermissions{Permission: "read", RepoIDs: []uint32{1}}, before)
+			equal(t, "after", auditLogPermissions{Permission: "read", RepoIDs: []uint32{4}}, after)
+		})
+
 		for _, test := range tests {
 			t.Run(test.name, func(t *testing.T) {
 				s := Perms(db, clock)
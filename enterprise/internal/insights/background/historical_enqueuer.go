@@ -431,6 +431,10 @@ func (h *historicalEnqueuer) buildSeries(ctx context.Context, bctx *buildSeriesC
 	// at that point in time.)
 	repoName := string(bctx.repo.Name)
 	if bctx.to.Before(bctx.firstHEADCommit.Author.Date) {
+		if bctx.series.GeneratedFromCaptureGroups {
+			// There are no captured values to record zero matches for.
+			return
+		}
 		if err := h.insightsStore.RecordSeriesPoint(ctx, store.RecordSeriesPointArgs{
 			SeriesID: bctx.seriesID,
 			Point: store.SeriesPoint{
@@ -487,6 +491,8 @@ func (h *historicalEnqueuer) buildSeries(ctx context.Context, bctx *buildSeriesC
 		State:       "queued",
 		Priority:    int(priority.FromTimeInterval(frameMidpoint, time.Now())), // eventually we will use the end of the historical range, for now current time works fine
 		Cost:        int(priority.Unindexed),
+
+		GeneratedFromCaptureGroups: bctx.series.GeneratedFromCaptureGroups,
 	})
 	return
 }
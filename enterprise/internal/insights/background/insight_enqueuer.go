@@ -95,6 +95,8 @@ func discoverAndEnqueueInsights(
 			State:        "queued",
 			Priority:     int(priority.High),
 			Cost:         int(priority.Indexed),
+
+			GeneratedFromCaptureGroups: series.GeneratedFromCaptureGroups,
 		})
 		if err != nil {
 			multi = multierror.Append(multi, err)
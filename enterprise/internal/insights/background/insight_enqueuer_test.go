@@ -115,6 +115,7 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
     "RecordTime": null,
     "Cost": 500,
     "Priority": 10,
+    "GeneratedFromCaptureGroups": false,
     "ID": 0,
     "State": "queued",
     "FailureMessage": null,
@@ -131,6 +132,7 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
     "RecordTime": null,
     "Cost": 500,
     "Priority": 10,
+    "GeneratedFromCaptureGroups": false,
     "ID": 0,
     "State": "queued",
     "FailureMessage": null,
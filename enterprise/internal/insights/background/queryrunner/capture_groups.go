@@ -0,0 +1,147 @@
+package queryrunner
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// This file contains the methods required to record series generated from capture groups, for
+// which one data point is recorded per distinct value of the first capture group of the search
+// pattern instead of a single match count.
+
+// withPatternTypeRegexp adds `patternType:regexp` to the given search query string iff
+// `patternType:` does not exist in the query string, as the search pattern of a series generated
+// from capture groups is always a regular expression.
+func withPatternTypeRegexp(s string) string {
+	if strings.Contains(s, "patternType:") {
+		return s
+	}
+	return s + " patternType:regexp"
+}
+
+// captureGroupPattern returns the compiled search pattern of the given query, which must consist
+// of a single regular expression containing at least one capture group. The returned pattern is
+// anchored, as it is matched against the text of a single search match.
+//
+// Like the search backend, whitespace separated terms of the pattern are matched in order with
+// anything in between. Unlike the search backend, the terms are not wrapped in capture groups
+// of their own, so the first capture group of the returned pattern is the one in the query.
+func captureGroupPattern(q string) (*regexp.Regexp, error) {
+	plan, err := query.ParseRegexp(q)
+	if err != nil {
+		return nil, errors.Wrap(err, "ParseRegexp")
+	}
+	if len(query.Dnf(plan)) != 1 {
+		return nil, errors.New("capture group series do not support and/or expressions")
+	}
+
+	nodes, err := query.Parse(q, query.SearchTypeRegex)
+	if err != nil {
+		return nil, errors.Wrap(err, "Parse")
+	}
+	var terms []string
+	query.VisitPattern(nodes, func(value string, negated bool, _ query.Annotation) {
+		if !negated {
+			terms = append(terms, value)
+		}
+	})
+	if len(terms) == 0 {
+		return nil, errors.New("capture group series require a search pattern")
+	}
+
+	pattern := "(?:" + strings.Join(terms, ").*?(?:") + ")"
+	if len(terms) == 1 {
+		pattern = terms[0]
+	}
+	if !plan.IsCaseSensitive() {
+		pattern = "(?i:" + pattern + ")"
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, errors.Wrap(err, "compiling search pattern")
+	}
+	if re.NumSubexp() == 0 {
+		return nil, errors.Errorf("search pattern %q has no capture group", strings.Join(terms, " "))
+	}
+	return re, nil
+}
+
+// captureGroupValues returns the number of times each distinct, non-empty value of the first
+// capture group of the given pattern occurs in the matches of the given result. Results other
+// than file content matches carry no matched contents and have no capture group values.
+//
+// The pattern is matched against the text of each match, as located by the offsets and lengths
+// of the line matches, rather than against the line previews as a whole: a preview may contain
+// several matches, and a match spanning multiple lines is split over the line matches of each
+// of its lines. Matches whose text cannot be fully recovered from the previews are skipped
+// rather than recorded with a partial value.
+func captureGroupValues(pattern *regexp.Regexp, r result) map[string]int {
+	fm, ok := r.(*fileMatch)
+	if !ok {
+		return nil
+	}
+
+	// continued tracks the line matches whose first range is the continuation of a match
+	// starting on a previous line.
+	continued := map[int]bool{}
+
+	values := map[string]int{}
+	for i, lineMatch := range fm.LineMatches {
+		for j, offsetAndLength := range lineMatch.OffsetAndLengths {
+			if j == 0 && continued[i] {
+				continue
+			}
+			if len(offsetAndLength) != 2 {
+				continue
+			}
+
+			text, ok := matchText(fm, i, offsetAndLength[0], offsetAndLength[1], continued)
+			if !ok {
+				continue
+			}
+			if submatches := pattern.FindStringSubmatch(text); submatches != nil && submatches[1] != "" {
+				values[submatches[1]]++
+			}
+		}
+	}
+	return values
+}
+
+// matchText returns the text of the match at the given offset and length (both in runes) of the
+// i-th line match of the given file match. If the match extends past the end of its line, it is
+// continued by the first range of the line match of the following line, which is marked as
+// continued. The second return value is false if the text cannot be fully recovered.
+func matchText(fm *fileMatch, i, offset, length int, continued map[int]bool) (string, bool) {
+	var b strings.Builder
+	for {
+		preview := []rune(fm.LineMatches[i].Preview)
+		end := offset + length
+		if offset < 0 || length < 0 || offset > len(preview) || end > len(preview)+1 {
+			return "", false
+		}
+		if end <= len(preview) {
+			b.WriteString(string(preview[offset:end]))
+			return b.String(), true
+		}
+
+		// The match includes the newline terminating the line, and may continue on the next.
+		b.WriteString(string(preview[offset:]))
+		b.WriteByte('\n')
+
+		next := i + 1
+		if next >= len(fm.LineMatches) || fm.LineMatches[next].LineNumber != fm.LineMatches[i].LineNumber+1 {
+			return b.String(), true
+		}
+		ranges := fm.LineMatches[next].OffsetAndLengths
+		if len(ranges) == 0 || len(ranges[0]) != 2 || ranges[0][0] != 0 {
+			return b.String(), true
+		}
+
+		continued[next] = true
+		i, offset, length = next, 0, ranges[0][1]
+	}
+}
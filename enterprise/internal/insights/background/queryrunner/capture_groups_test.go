@@ -0,0 +1,158 @@
+package queryrunner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCaptureGroupPattern(t *testing.T) {
+	for _, tc := range []struct {
+		query   string
+		pattern string
+		err     bool
+	}{
+		{query: `file:go\.mod github\.com/sourcegraph/go-ctags v(\S+)`, pattern: `^(?:(?i:(?:github\.com/sourcegraph/go-ctags).*?(?:v(\S+))))$`},
+		{query: `lang:go errors\.(\w+)\( case:yes count:9999999`, pattern: `^(?:errors\.(\w+)\()$`},
+		{query: `-file:vendor/ (\d+\.\d+\.\d+) repo:^a$@abc`, pattern: `^(?:(?i:(\d+\.\d+\.\d+)))$`},
+		{query: `github\.com/sourcegraph/go-ctags`, err: true},
+		{query: `(a) or (b)`, err: true},
+		{query: `repo:a`, err: true},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			re, err := captureGroupPattern(tc.query)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got pattern %q", re)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if re.String() != tc.pattern {
+				t.Errorf("unexpected pattern: want %q got %q", tc.pattern, re)
+			}
+		})
+	}
+}
+
+func TestWithPatternTypeRegexp(t *testing.T) {
+	if have, want := withPatternTypeRegexp("foo(bar)"), "foo(bar) patternType:regexp"; have != want {
+		t.Errorf("unexpected query: want %q got %q", want, have)
+	}
+	if have, want := withPatternTypeRegexp("foo(bar) patternType:regexp"), "foo(bar) patternType:regexp"; have != want {
+		t.Errorf("unexpected query: want %q got %q", want, have)
+	}
+}
+
+func TestCaptureGroupValues(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		query  string
+		result string
+		want   map[string]int
+	}{
+		{
+			name:  "several matches per line",
+			query: `errors\.(\w+)\(`,
+			result: `{"lineMatches": [
+				{"preview": "if errors.Is(err, a) || errors.As(err, &b) {", "lineNumber": 1, "offsetAndLengths": [[3, 10], [24, 10]]}
+			]}`,
+			want: map[string]int{"Is": 1, "As": 1},
+		},
+		{
+			name:  "match spanning lines",
+			query: `require\s+\(\s+(\S+)`,
+			result: `{"lineMatches": [
+				{"preview": "require (", "lineNumber": 3, "offsetAndLengths": [[0, 10]]},
+				{"preview": "\tgithub.com/sourcegraph/go-ctags v1.0.0", "lineNumber": 4, "offsetAndLengths": [[0, 32]]}
+			]}`,
+			want: map[string]int{"github.com/sourcegraph/go-ctags": 1},
+		},
+		{
+			name:  "match past end of preview",
+			query: `version = "([^"]+)"`,
+			result: `{"lineMatches": [
+				{"preview": "version = \"1.0", "lineNumber": 1, "offsetAndLengths": [[0, 18]]}
+			]}`,
+			want: map[string]int{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern, err := captureGroupPattern(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fm fileMatch
+			if err := json.Unmarshal([]byte(tc.result), &fm); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, captureGroupValues(pattern, &fm)); diff != "" {
+				t.Errorf("unexpected values (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCountMatches(t *testing.T) {
+	results := []json.RawMessage{
+		json.RawMessage(`{
+			"__typename": "FileMatch",
+			"repository": {"id": "repo1"},
+			"lineMatches": [
+				{"preview": "github.com/sourcegraph/go-ctags v1.0.0", "offsetAndLengths": [[0, 38]]},
+				{"preview": "\tgithub.com/sourcegraph/go-ctags v1.2.0 // indirect", "offsetAndLengths": [[1, 38]]}
+			]
+		}`),
+		json.RawMessage(`{
+			"__typename": "FileMatch",
+			"repository": {"id": "repo1"},
+			"lineMatches": [
+				{"preview": "github.com/sourcegraph/go-ctags v1.0.0", "offsetAndLengths": [[0, 38]]}
+			]
+		}`),
+		json.RawMessage(`{
+			"__typename": "FileMatch",
+			"repository": {"id": "repo2"},
+			"lineMatches": [
+				{"preview": "GITHUB.COM/SOURCEGRAPH/GO-CTAGS V1.0.0", "offsetAndLengths": [[0, 38]]}
+			]
+		}`),
+		json.RawMessage(`{"__typename": "Repository", "id": "repo3"}`),
+	}
+
+	t.Run("match counts", func(t *testing.T) {
+		matches, err := countMatches(&Job{SearchQuery: `github\.com/sourcegraph/go-ctags v(\S+)`}, results)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[matchKey]int{
+			{repoID: "repo1"}: 3,
+			{repoID: "repo2"}: 1,
+			{repoID: "repo3"}: 1,
+		}
+		if diff := cmp.Diff(want, matches, cmp.AllowUnexported(matchKey{})); diff != "" {
+			t.Errorf("unexpected matches (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("capture groups", func(t *testing.T) {
+		matches, err := countMatches(&Job{
+			SearchQuery:                `github\.com/sourcegraph/go-ctags v(\S+)`,
+			GeneratedFromCaptureGroups: true,
+		}, results)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[matchKey]int{
+			{repoID: "repo1", label: "1.0.0"}: 2,
+			{repoID: "repo1", label: "1.2.0"}: 1,
+			{repoID: "repo2", label: "1.0.0"}: 1,
+		}
+		if diff := cmp.Diff(want, matches, cmp.AllowUnexported(matchKey{})); diff != "" {
+			t.Errorf("unexpected matches (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -39,6 +39,8 @@ const gqlSearchQuery = `query Search(
 						id
 					}
 					lineMatches {
+						preview
+						lineNumber
 						offsetAndLengths
 					}
 					symbols {
@@ -112,6 +112,8 @@ type fileMatch struct {
 		ID string
 	}
 	LineMatches []struct {
+		Preview          string
+		LineNumber       int
 		OffsetAndLengths [][]int
 	}
 	Symbols []struct {
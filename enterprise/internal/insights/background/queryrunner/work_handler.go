@@ -2,7 +2,9 @@ package queryrunner
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -15,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 )
 
@@ -52,8 +55,12 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 	// is OK to expose to every user on Sourcegraph (e.g. total result counts are fine, exposing
 	// that a repository exists may or may not be fine, exposing individual results is definitely
 	// not, etc.)
+	searchQuery := job.SearchQuery
+	if job.GeneratedFromCaptureGroups {
+		searchQuery = withPatternTypeRegexp(searchQuery)
+	}
 	var results *gqlSearchResponse
-	results, err = search(ctx, job.SearchQuery)
+	results, err = search(ctx, searchQuery)
 	if err != nil {
 		return err
 	}
@@ -104,27 +111,33 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 		recordTime = *job.RecordTime
 	}
 
-	// Figure out how many matches we got for every unique repository returned in the search
-	// results.
-	matchesPerRepo := make(map[string]int, len(results.Data.Search.Results.Results)*4)
-	for _, result := range results.Data.Search.Results.Results {
-		decoded, err := decodeResult(result)
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf(`for query "%s"`, job.SearchQuery))
-		}
-		matchesPerRepo[decoded.repoID()] = matchesPerRepo[decoded.repoID()] + decoded.matchCount()
+	// Figure out how many matches we got for every unique repository (and, for series generated
+	// from capture groups, every distinct captured value) returned in the search results.
+	matches, err := countMatches(job, results.Data.Search.Results.Results)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf(`for query "%s"`, job.SearchQuery))
 	}
 
-	// Record the number of results we got, one data point per-repository.
+	// Record the number of results we got, one data point per-repository (and per-label).
 	repoStore := database.Repos(r.workerBaseStore.Handle().DB())
-	for graphQLRepoID, matchCount := range matchesPerRepo {
-		dbRepoID, err := graphqlbackend.UnmarshalRepositoryID(graphql.ID(graphQLRepoID))
-		if err != nil {
-			return errors.Wrap(err, "UnmarshalRepositoryID")
+	repos := map[string]*types.Repo{}
+	for key, matchCount := range matches {
+		repo, ok := repos[key.repoID]
+		if !ok {
+			dbRepoID, err := graphqlbackend.UnmarshalRepositoryID(graphql.ID(key.repoID))
+			if err != nil {
+				return errors.Wrap(err, "UnmarshalRepositoryID")
+			}
+			repo, err = repoStore.Get(ctx, dbRepoID)
+			if err != nil {
+				return errors.Wrap(err, "RepoStore.GetByID")
+			}
+			repos[key.repoID] = repo
 		}
-		repo, err := repoStore.Get(ctx, dbRepoID)
-		if err != nil {
-			return errors.Wrap(err, "RepoStore.GetByID")
+
+		var label *string
+		if job.GeneratedFromCaptureGroups {
+			label = &key.label
 		}
 
 		repoName := string(repo.Name)
@@ -133,6 +146,7 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 			Point: store.SeriesPoint{
 				Time:  recordTime,
 				Value: float64(matchCount),
+				Label: label,
 			},
 			RepoName: &repoName,
 			RepoID:   &repo.ID,
@@ -143,3 +157,39 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 	}
 	return nil
 }
+
+// matchKey identifies the matches counted towards a single data point. The label is always
+// empty unless the series is generated from capture groups.
+type matchKey struct {
+	repoID string
+	label  string
+}
+
+// countMatches decodes the given search results and counts the matches per repository, or per
+// repository and capture group value if the job records a series generated from capture groups.
+func countMatches(job *Job, results []json.RawMessage) (map[matchKey]int, error) {
+	var pattern *regexp.Regexp
+	if job.GeneratedFromCaptureGroups {
+		var err error
+		if pattern, err = captureGroupPattern(job.SearchQuery); err != nil {
+			return nil, err
+		}
+	}
+
+	matches := make(map[matchKey]int, len(results)*4)
+	for _, result := range results {
+		decoded, err := decodeResult(result)
+		if err != nil {
+			return nil, err
+		}
+
+		if pattern == nil {
+			matches[matchKey{repoID: decoded.repoID()}] += decoded.matchCount()
+			continue
+		}
+		for value, count := range captureGroupValues(pattern, decoded) {
+			matches[matchKey{repoID: decoded.repoID(), label: value}] += count
+		}
+	}
+	return matches, nil
+}
@@ -129,6 +129,7 @@ func EnqueueJob(ctx context.Context, workerBaseStore *basestore.Store, job *Job)
 			job.ProcessAfter,
 			job.Cost,
 			job.Priority,
+			job.GeneratedFromCaptureGroups,
 		),
 	))
 	return
@@ -143,8 +144,9 @@ INSERT INTO insights_query_runner_jobs (
 	state,
 	process_after,
 	cost,
-	priority
-) VALUES (%s, %s, %s, %s, %s, %s, %s)
+	priority,
+	generated_from_capture_groups
+) VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING id
 `
 
@@ -171,6 +173,7 @@ SELECT
 	record_time,
 	cost,
 	priority,
+	generated_from_capture_groups,
 	id,
 	state,
 	failure_message,
@@ -233,6 +236,10 @@ type Job struct {
 	Cost        int
 	Priority    int
 
+	// GeneratedFromCaptureGroups indicates that one data point should be recorded per distinct
+	// value of the first capture group of the search pattern, rather than a single match count.
+	GeneratedFromCaptureGroups bool
+
 	// Standard/required dbworker fields. If enqueuing a job, these may all be zero values except State.
 	//
 	// See https://sourcegraph.com/github.com/sourcegraph/sourcegraph@cd0b3904c674ee3568eb2ef5d7953395b6432d20/-/blob/internal/workerutil/dbworker/store/store.go#L114-134
@@ -276,6 +283,7 @@ func doScanJobs(rows *sql.Rows, err error) ([]*Job, error) {
 			&j.RecordTime,
 			&j.Cost,
 			&j.Priority,
+			&j.GeneratedFromCaptureGroups,
 
 			// Standard/required dbworker fields.
 			&j.ID,
@@ -308,6 +316,7 @@ var jobsColumns = []*sqlf.Query{
 	sqlf.Sprintf("insights_query_runner_jobs.record_time"),
 	sqlf.Sprintf("insights_query_runner_jobs.cost"),
 	sqlf.Sprintf("insights_query_runner_jobs.priority"),
+	sqlf.Sprintf("insights_query_runner_jobs.generated_from_capture_groups"),
 	sqlf.Sprintf("id"),
 	sqlf.Sprintf("state"),
 	sqlf.Sprintf("failure_message"),
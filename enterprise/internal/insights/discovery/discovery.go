@@ -201,9 +201,10 @@ func migrateSeries(ctx context.Context, insightStore *store.InsightStore, from i
 
 	for i, timeSeries := range from.Series {
 		temp := types.InsightSeries{
-			SeriesID:              Encode(timeSeries),
-			Query:                 timeSeries.Query,
-			RecordingIntervalDays: 1,
+			SeriesID:                   Encode(timeSeries),
+			Query:                      timeSeries.Query,
+			RecordingIntervalDays:      1,
+			GeneratedFromCaptureGroups: timeSeries.GeneratedFromCaptureGroups,
 		}
 		result, err := tx.CreateSeries(ctx, temp)
 		if err != nil {
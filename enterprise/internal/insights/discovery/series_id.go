@@ -30,6 +30,11 @@ func EncodeSeriesID(series *schema.InsightSeries) (string, error) {
 }
 
 func Encode(series insights.TimeSeries) string {
+	if series.GeneratedFromCaptureGroups {
+		// Series generated from capture groups record different data than a plain search
+		// series with the same query, so they must not share a series ID.
+		return fmt.Sprintf("c:%s", sha256String(series.Query))
+	}
 	return fmt.Sprintf("s:%s", sha256String(series.Query))
 }
 
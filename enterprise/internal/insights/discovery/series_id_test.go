@@ -6,6 +6,7 @@ import (
 
 	"github.com/hexops/autogold"
 
+	"github.com/sourcegraph/sourcegraph/internal/insights"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -40,3 +41,11 @@ func TestEncodeSeriesID(t *testing.T) {
 		})
 	}
 }
+
+func TestEncode(t *testing.T) {
+	series := insights.TimeSeries{Query: "fmt.Errorf repo:github.com/golang/go"}
+	autogold.Want("search", "s:6CB26B840C8EEBFB03DDB44A23FFBD4D7AD864B47D9AA1E975E69FCF0EE2A67E").Equal(t, Encode(series))
+
+	series.GeneratedFromCaptureGroups = true
+	autogold.Want("capture groups", "c:6CB26B840C8EEBFB03DDB44A23FFBD4D7AD864B47D9AA1E975E69FCF0EE2A67E").Equal(t, Encode(series))
+}
@@ -88,15 +88,34 @@ func (r *insightResolver) Title() string { return r.insight.Title }
 
 func (r *insightResolver) Description() string { return r.insight.Description }
 
-func (r *insightResolver) Series() []graphqlbackend.InsightSeriesResolver {
+func (r *insightResolver) Series(ctx context.Context) ([]graphqlbackend.InsightSeriesResolver, error) {
 	series := r.insight.Series
 	resolvers := make([]graphqlbackend.InsightSeriesResolver, 0, len(series))
 	for _, series := range series {
-		resolvers = append(resolvers, &insightSeriesResolver{
-			insightsStore:   r.insightsStore,
-			workerBaseStore: r.workerBaseStore,
-			series:          series,
-		})
+		if !series.GeneratedFromCaptureGroups {
+			resolvers = append(resolvers, &insightSeriesResolver{
+				insightsStore:   r.insightsStore,
+				workerBaseStore: r.workerBaseStore,
+				series:          series,
+			})
+			continue
+		}
+
+		// Series generated from capture groups are dynamic: there is one series per distinct
+		// value captured so far.
+		labels, err := r.insightsStore.DistinctLabels(ctx, discovery.Encode(series))
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range labels {
+			label := label
+			resolvers = append(resolvers, &insightSeriesResolver{
+				insightsStore:   r.insightsStore,
+				workerBaseStore: r.workerBaseStore,
+				series:          series,
+				label:           &label,
+			})
+		}
 	}
-	return resolvers
+	return resolvers, nil
 }
@@ -19,9 +19,18 @@ type insightSeriesResolver struct {
 	insightsStore   store.Interface
 	workerBaseStore *basestore.Store
 	series          insights.TimeSeries
+
+	// label is the captured value this series is generated from, if the series is generated
+	// from capture groups.
+	label *string
 }
 
-func (r *insightSeriesResolver) Label() string { return r.series.Name }
+func (r *insightSeriesResolver) Label() string {
+	if r.label != nil {
+		return *r.label
+	}
+	return r.series.Name
+}
 
 func (r *insightSeriesResolver) Points(ctx context.Context, args *graphqlbackend.InsightsPointsArgs) ([]graphqlbackend.InsightsDataPointResolver, error) {
 	var opts store.SeriesPointsOpts
@@ -29,6 +38,7 @@ func (r *insightSeriesResolver) Points(ctx context.Context, args *graphqlbackend
 	// Query data points only for the series we are representing.
 	seriesID := discovery.Encode(r.series)
 	opts.SeriesID = &seriesID
+	opts.Label = r.label
 
 	if args.From == nil {
 		// Default to last 6mo of data.
@@ -15,6 +15,9 @@ type MockInterface struct {
 	// CountDataFunc is an instance of a mock function object controlling
 	// the behavior of the method CountData.
 	CountDataFunc *InterfaceCountDataFunc
+	// DistinctLabelsFunc is an instance of a mock function object
+	// controlling the behavior of the method DistinctLabels.
+	DistinctLabelsFunc *InterfaceDistinctLabelsFunc
 	// RecordSeriesPointFunc is an instance of a mock function object
 	// controlling the behavior of the method RecordSeriesPoint.
 	RecordSeriesPointFunc *InterfaceRecordSeriesPointFunc
@@ -32,6 +35,11 @@ func NewMockInterface() *MockInterface {
 				return 0, nil
 			},
 		},
+		DistinctLabelsFunc: &InterfaceDistinctLabelsFunc{
+			defaultHook: func(context.Context, string) ([]string, error) {
+				return nil, nil
+			},
+		},
 		RecordSeriesPointFunc: &InterfaceRecordSeriesPointFunc{
 			defaultHook: func(context.Context, RecordSeriesPointArgs) error {
 				return nil
@@ -52,6 +60,9 @@ func NewMockInterfaceFrom(i Interface) *MockInterface {
 		CountDataFunc: &InterfaceCountDataFunc{
 			defaultHook: i.CountData,
 		},
+		DistinctLabelsFunc: &InterfaceDistinctLabelsFunc{
+			defaultHook: i.DistinctLabels,
+		},
 		RecordSeriesPointFunc: &InterfaceRecordSeriesPointFunc{
 			defaultHook: i.RecordSeriesPoint,
 		},
@@ -169,6 +180,115 @@ func (c InterfaceCountDataFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// InterfaceDistinctLabelsFunc describes the behavior when the
+// DistinctLabels method of the parent MockInterface instance is invoked.
+type InterfaceDistinctLabelsFunc struct {
+	defaultHook func(context.Context, string) ([]string, error)
+	hooks       []func(context.Context, string) ([]string, error)
+	history     []InterfaceDistinctLabelsFuncCall
+	mutex       sync.Mutex
+}
+
+// DistinctLabels delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) DistinctLabels(v0 context.Context, v1 string) ([]string, error) {
+	r0, r1 := m.DistinctLabelsFunc.nextHook()(v0, v1)
+	m.DistinctLabelsFunc.appendCall(InterfaceDistinctLabelsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DistinctLabels
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceDistinctLabelsFunc) SetDefaultHook(hook func(context.Context, string) ([]string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DistinctLabels method of the parent MockInterface instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *InterfaceDistinctLabelsFunc) PushHook(hook func(context.Context, string) ([]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceDistinctLabelsFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceDistinctLabelsFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(context.Context, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceDistinctLabelsFunc) nextHook() func(context.Context, string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceDistinctLabelsFunc) appendCall(r0 InterfaceDistinctLabelsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceDistinctLabelsFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceDistinctLabelsFunc) History() []InterfaceDistinctLabelsFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceDistinctLabelsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceDistinctLabelsFuncCall is an object that describes an invocation
+// of method DistinctLabels on an instance of MockInterface.
+type InterfaceDistinctLabelsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceDistinctLabelsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceDistinctLabelsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // InterfaceRecordSeriesPointFunc describes the behavior when the
 // RecordSeriesPoint method of the parent MockInterface instance is invoked.
 type InterfaceRecordSeriesPointFunc struct {
@@ -23,6 +23,7 @@ type Interface interface {
 	SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]SeriesPoint, error)
 	RecordSeriesPoint(ctx context.Context, v RecordSeriesPointArgs) error
 	CountData(ctx context.Context, opts CountDataOpts) (int, error)
+	DistinctLabels(ctx context.Context, seriesID string) ([]string, error)
 }
 
 var _ Interface = &Store{}
@@ -72,9 +73,16 @@ type SeriesPoint struct {
 	Time     time.Time
 	Value    float64
 	Metadata []byte
+
+	// Label is the value of the capture group that this point counts matches of, for series
+	// generated from capture groups. It is nil for all other series.
+	Label *string
 }
 
 func (s *SeriesPoint) String() string {
+	if s.Label != nil {
+		return fmt.Sprintf("SeriesPoint{Time: %q, Value: %v, Metadata: %s, Label: %q}", s.Time, s.Value, s.Metadata, *s.Label)
+	}
 	return fmt.Sprintf("SeriesPoint{Time: %q, Value: %v, Metadata: %s}", s.Time, s.Value, s.Metadata)
 }
 
@@ -86,6 +94,10 @@ type SeriesPointsOpts struct {
 	// RepoID, if non-nil, indicates to filter results to only points recorded with this repo ID.
 	RepoID *api.RepoID
 
+	// Label, if non-nil, indicates to filter results to only points recorded with this capture
+	// group value.
+	Label *string
+
 	Excluded []api.RepoID
 	Included []api.RepoID
 
@@ -131,6 +143,7 @@ func (s *Store) SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]Seri
 			&point.Time,
 			&point.Value,
 			&point.Metadata,
+			&point.Label,
 		)
 		if err != nil {
 			return err
@@ -143,21 +156,23 @@ func (s *Store) SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]Seri
 
 // This query is a barebones implementation of per-repo per-series last-observation carried forward. Long term
 // this query is too expensive to run in real-time and should be moved to a materialized view.
-const lastObservationCarriedPointsSql = `select sub.series_id, sub.interval_time, sum(value) as value, null as metadata from (WITH target_times AS (SELECT *
+//
+// Points of series generated from capture groups are carried forward per capture value as well.
+const lastObservationCarriedPointsSql = `select sub.series_id, sub.interval_time, sum(value) as value, null as metadata, sub.label from (WITH target_times AS (SELECT *
 FROM GENERATE_SERIES(CURRENT_TIMESTAMP::date - INTERVAL '26 weeks', CURRENT_TIMESTAMP::date, '2 weeks') as interval_time)
-SELECT sub.series_id, sub.repo_id, sub.value, interval_time, repo_name_id
-FROM (select distinct repo_id, series_id from series_points) as r
+SELECT sub.series_id, sub.repo_id, sub.value, interval_time, repo_name_id, sub.label
+FROM (select distinct repo_id, series_id, label from series_points) as r
 cross join target_times tt
 join LATERAL (
     select sp.* from series_points as sp
-    where sp.repo_id = r.repo_id and sp.time <= tt.interval_time and sp.series_id = r.series_id
+    where sp.repo_id = r.repo_id and sp.time <= tt.interval_time and sp.series_id = r.series_id and sp.label IS NOT DISTINCT FROM r.label
     order by time DESC
     limit 1
     ) sub on sub.repo_id = r.repo_id and r.series_id = sub.series_id
 order by interval_time, repo_id) as sub
 join repo_names rn on sub.repo_name_id = rn.id
 where %s
-group by sub.series_id, sub.interval_time
+group by sub.series_id, sub.interval_time, sub.label
 order by interval_time desc
 `
 
@@ -189,6 +204,9 @@ func seriesPointsQuery(opts SeriesPointsOpts) *sqlf.Query {
 	if opts.RepoID != nil {
 		preds = append(preds, sqlf.Sprintf("repo_id = %d", int32(*opts.RepoID)))
 	}
+	if opts.Label != nil {
+		preds = append(preds, sqlf.Sprintf("label = %s", *opts.Label))
+	}
 	if opts.From != nil {
 		preds = append(preds, sqlf.Sprintf("interval_time >= %s", *opts.From))
 	}
@@ -242,6 +260,37 @@ func values(ids []api.RepoID) string {
 	return query
 }
 
+// DistinctLabels returns the distinct capture group values recorded for the given series, in
+// lexicographic order. Values recorded only for repositories the current user cannot see are
+// omitted.
+func (s *Store) DistinctLabels(ctx context.Context, seriesID string) ([]string, error) {
+	// 🚨 SECURITY: The capture group values are search result contents, so we must only expose
+	// those found in repositories the current user has access to. See SeriesPoints.
+	denylist, err := s.permStore.GetUnauthorizedRepoIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return basestore.ScanStrings(s.Store.Query(ctx, distinctLabelsQuery(seriesID, denylist)))
+}
+
+const distinctLabelsFmtstr = `
+-- source: enterprise/internal/insights/store/store.go:DistinctLabels
+SELECT DISTINCT label FROM series_points WHERE %s ORDER BY label
+`
+
+func distinctLabelsQuery(seriesID string, excluded []api.RepoID) *sqlf.Query {
+	preds := []*sqlf.Query{
+		sqlf.Sprintf("series_id = %s", seriesID),
+		sqlf.Sprintf("label IS NOT NULL"),
+	}
+	if len(excluded) > 0 {
+		s := fmt.Sprintf("repo_id != all(%v)", values(excluded))
+		preds = append(preds, sqlf.Sprintf(s))
+	}
+	return sqlf.Sprintf(distinctLabelsFmtstr, sqlf.Join(preds, "\n AND "))
+}
+
 type CountDataOpts struct {
 	// The time range to look for data, if non-nil.
 	From, To *time.Time
@@ -371,6 +420,7 @@ func (s *Store) RecordSeriesPoint(ctx context.Context, v RecordSeriesPointArgs)
 		v.RepoID,           // repo_id
 		repoNameID,         // repo_name_id
 		repoNameID,         // original_repo_name_id
+		v.Point.Label,      // label
 	))
 }
 
@@ -409,8 +459,9 @@ INSERT INTO series_points(
 	metadata_id,
 	repo_id,
 	repo_name_id,
-	original_repo_name_id)
-VALUES (%s, %s, %s, %s, %s, %s, %s);
+	original_repo_name_id,
+	label)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s);
 `
 
 func (s *Store) query(ctx context.Context, q *sqlf.Query, sc scanFunc) error {
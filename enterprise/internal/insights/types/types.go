@@ -18,6 +18,10 @@ type InsightViewSeries struct {
 	RecordingIntervalDays int
 	Label                 string
 	Stroke                string
+
+	// GeneratedFromCaptureGroups indicates that one series is generated per distinct value of the
+	// first capture group of Query, rather than a single series of match counts.
+	GeneratedFromCaptureGroups bool
 }
 
 // InsightViewSeriesMetadata contains metadata about a viewable insight series such as render properties.
@@ -45,4 +49,8 @@ type InsightSeries struct {
 	LastRecordedAt        time.Time
 	NextRecordingAfter    time.Time
 	RecordingIntervalDays int
+
+	// GeneratedFromCaptureGroups indicates that one series is generated per distinct value of the
+	// first capture group of Query, rather than a single series of match counts.
+	GeneratedFromCaptureGroups bool
 }
@@ -0,0 +1,77 @@
+package bitbucketcloud
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewAuthzProviders returns the set of Bitbucket Cloud authz providers derived from the connections.
+// It also returns any validation problems with the config, separating these into "serious problems" and
+// "warnings". "Serious problems" are those that should make Sourcegraph set authz.allowAccessByDefault
+// to false. "Warnings" are all other validation problems.
+func NewAuthzProviders(
+	conns []*types.BitbucketCloudConnection,
+) (ps []authz.Provider, problems []string, warnings []string) {
+	// Authorization (i.e., permissions) providers
+	for _, c := range conns {
+		p, err := newAuthzProvider(c)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if p != nil {
+			ps = append(ps, p)
+		}
+	}
+
+	for _, p := range ps {
+		for _, problem := range p.Validate() {
+			warnings = append(warnings, fmt.Sprintf("Bitbucket Cloud config for %s was invalid: %s", p.ServiceID(), problem))
+		}
+	}
+
+	return ps, problems, warnings
+}
+
+func newAuthzProvider(c *types.BitbucketCloudConnection) (authz.Provider, error) {
+	if c.Authorization == nil {
+		return nil, nil
+	}
+
+	if c.Authorization.IdentityProvider.Username == nil {
+		return nil, errors.New("No identityProvider was specified")
+	}
+
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, errors.Errorf("Could not parse URL for Bitbucket Cloud instance %q: %s", c.Url, err)
+	}
+
+	apiURLString := c.ApiURL
+	if apiURLString == "" {
+		apiURLString = "https://api.bitbucket.org"
+	}
+	apiURL, err := url.Parse(apiURLString)
+	if err != nil {
+		return nil, errors.Errorf("Could not parse API URL for Bitbucket Cloud instance %q: %s", apiURLString, err)
+	}
+
+	client := bitbucketcloud.NewClient(extsvc.NormalizeBaseURL(apiURL), nil)
+	client.Username = c.Username
+	client.AppPassword = c.AppPassword
+
+	return NewProvider(c.URN, baseURL, client), nil
+}
+
+// ValidateAuthz validates the authorization fields of the given Bitbucket Cloud
+// external service config.
+func ValidateAuthz(c *schema.BitbucketCloudConnection) error {
+	_, err := newAuthzProvider(&types.BitbucketCloudConnection{BitbucketCloudConnection: c})
+	return err
+}
@@ -0,0 +1,291 @@
+// Package bitbucketcloud contains an authorization provider for Bitbucket Cloud.
+package bitbucketcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// Provider is an implementation of AuthzProvider that provides repository permissions as
+// determined from the Bitbucket Cloud workspace and repository permission APIs.
+type Provider struct {
+	urn      string
+	client   *bitbucketcloud.Client
+	codeHost *extsvc.CodeHost
+	pageLen  int // Page size to use in paginated requests.
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// NewProvider returns a new Bitbucket Cloud authorization provider that uses
+// the given bitbucketcloud.Client to read permissions. The client must be
+// authenticated as an owner of the workspaces whose permissions are synced. It
+// assumes usernames of Sourcegraph accounts match 1-1 with nicknames of
+// Bitbucket Cloud users.
+func NewProvider(urn string, baseURL *url.URL, cli *bitbucketcloud.Client) *Provider {
+	return &Provider{
+		urn:      urn,
+		client:   cli,
+		codeHost: extsvc.NewCodeHost(baseURL, extsvc.TypeBitbucketCloud),
+		pageLen:  100,
+	}
+}
+
+// Validate validates that the Provider has access to the Bitbucket Cloud API
+// with the credentials it was configured with, and that those credentials can
+// read the permissions of at least one workspace.
+func (p *Provider) Validate() []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	workspaces, err := p.workspaces(ctx)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if len(workspaces) == 0 {
+		return []string{"the configured user is not an owner of any workspace, so no permissions can be synced"}
+	}
+
+	return nil
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+// ServiceID returns the absolute URL that identifies the Bitbucket Cloud instance
+// this provider is configured with.
+func (p *Provider) ServiceID() string { return p.codeHost.ServiceID }
+
+// ServiceType returns the type of this Provider, namely, "bitbucketCloud".
+func (p *Provider) ServiceType() string { return p.codeHost.ServiceType }
+
+// FetchAccount satisfies the authz.Provider interface. It looks for a member of
+// the workspaces owned by the configured user whose nickname is the username of
+// the given user.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, _ []*extsvc.Account, _ []string) (acct *extsvc.Account, err error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	tr, ctx := trace.New(ctx, "bitbucketcloud.authz.provider.FetchAccount", "")
+	defer func() {
+		tr.LogFields(
+			otlog.String("user.name", user.Username),
+			otlog.Int32("user.id", user.ID),
+		)
+
+		if err != nil {
+			tr.SetError(err)
+		}
+
+		tr.Finish()
+	}()
+
+	bitbucketUser, err := p.user(ctx, user.Username)
+	if err != nil || bitbucketUser == nil {
+		return nil, err
+	}
+
+	accountData, err := json.Marshal(bitbucketUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   bitbucketUser.UUID,
+		},
+		AccountData: extsvc.AccountData{
+			Data: (*json.RawMessage)(&accountData),
+		},
+	}, nil
+}
+
+// FetchUserPerms returns a list of repository IDs (on code host) that the given account
+// has read access on the code host. The repository ID has the same value as it would be
+// used as api.ExternalRepoSpec.ID. The returned list includes both direct access and
+// inherited from group membership, in all workspaces owned by the configured user.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-workspaces-workspace-permissions-repositories-get
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account) (*authz.ExternalUserPermissions, error) {
+	switch {
+	case account == nil:
+		return nil, errors.New("no account provided")
+	case account.Data == nil:
+		return nil, errors.New("no account data provided")
+	case !extsvc.IsHostOfAccount(p.codeHost, account):
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			p.codeHost.ServiceID, account.AccountSpec.ServiceID)
+	}
+
+	var user bitbucketcloud.Account
+	if err := json.Unmarshal(*account.Data, &user); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling account data")
+	}
+
+	workspaces, err := p.workspaces(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing workspaces")
+	}
+
+	var ids []extsvc.RepoID
+	query := "user.uuid=" + strconv.Quote(user.UUID)
+	for _, workspace := range workspaces {
+		t := &bitbucketcloud.PageToken{Pagelen: p.pageLen}
+		for {
+			perms, next, err := p.client.RepoPermissions(ctx, t, workspace, query)
+			if err != nil {
+				return &authz.ExternalUserPermissions{Exacts: ids}, err
+			}
+
+			for _, perm := range perms {
+				if perm.Repo != nil {
+					ids = append(ids, extsvc.RepoID(perm.Repo.UUID))
+				}
+			}
+
+			if !next.HasMore() {
+				break
+			}
+			t = next
+		}
+	}
+
+	return &authz.ExternalUserPermissions{Exacts: ids}, nil
+}
+
+// FetchUserPermsByToken is currently only required for syncing permissions for
+// GitHub and GitLab on sourcegraph.com
+func (p *Provider) FetchUserPermsByToken(ctx context.Context, token string) (*authz.ExternalUserPermissions, error) {
+	return nil, errors.New("not implemented")
+}
+
+// FetchRepoPerms returns a list of user IDs (on code host) who have read access to
+// the given repo on the code host. The user ID has the same value as it would
+// be used as extsvc.Account.AccountID. The returned list includes both direct access
+// and inherited from group membership.
+//
+// This method may return partial but valid results in case of error, and it is up to
+// callers to decide whether to discard.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-workspaces-workspace-permissions-repositories-repo-slug-get
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository) ([]extsvc.AccountID, error) {
+	switch {
+	case repo == nil:
+		return nil, errors.New("no repo provided")
+	case !extsvc.IsHostOfRepo(p.codeHost, &repo.ExternalRepoSpec):
+		return nil, errors.Errorf("not a code host of the repo: want %q but have %q",
+			p.codeHost.ServiceID, repo.ServiceID)
+	}
+
+	// NOTE: We do not store port or scheme in our URI, so stripping the hostname alone is enough.
+	fullName := strings.TrimPrefix(repo.URI, p.codeHost.BaseURL.Hostname())
+	fullName = strings.TrimPrefix(fullName, "/")
+
+	workspace, slug, ok := splitFullName(fullName)
+	if !ok {
+		return nil, errors.Errorf("invalid repository full name %q", fullName)
+	}
+
+	var ids []extsvc.AccountID
+	t := &bitbucketcloud.PageToken{Pagelen: p.pageLen}
+	for {
+		perms, next, err := p.client.RepoUserPermissions(ctx, t, workspace, slug)
+		if err != nil {
+			return ids, err
+		}
+
+		for _, perm := range perms {
+			if perm.User != nil {
+				ids = append(ids, extsvc.AccountID(perm.User.UUID))
+			}
+		}
+
+		if !next.HasMore() {
+			break
+		}
+		t = next
+	}
+
+	return ids, nil
+}
+
+// workspaces returns the slugs of the workspaces owned by the configured user,
+// which are the only ones whose permissions it can read.
+func (p *Provider) workspaces(ctx context.Context) ([]string, error) {
+	var slugs []string
+	t := &bitbucketcloud.PageToken{Pagelen: p.pageLen}
+	for {
+		perms, next, err := p.client.CurrentUserWorkspacePermissions(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, perm := range perms {
+			if perm.Permission == bitbucketcloud.WorkspacePermissionOwner && perm.Workspace != nil {
+				slugs = append(slugs, perm.Workspace.Slug)
+			}
+		}
+
+		if !next.HasMore() {
+			return slugs, nil
+		}
+		t = next
+	}
+}
+
+// user returns the member of the owned workspaces with the given nickname, or
+// nil if there is none.
+func (p *Provider) user(ctx context.Context, nickname string) (*bitbucketcloud.Account, error) {
+	workspaces, err := p.workspaces(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing workspaces")
+	}
+
+	query := "user.nickname=" + strconv.Quote(nickname)
+	for _, workspace := range workspaces {
+		members, _, err := p.client.WorkspacePermissions(ctx, &bitbucketcloud.PageToken{Pagelen: p.pageLen}, workspace, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range members {
+			// The query is a filter expression, so we double check for an
+			// exact match.
+			if m.User != nil && m.User.Nickname == nickname {
+				return m.User, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func splitFullName(fullName string) (workspace, slug string, ok bool) {
+	parts := strings.Split(fullName, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
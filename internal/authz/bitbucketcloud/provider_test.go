@@ -0,0 +1,169 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestProvider(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		q := r.URL.Query()
+		var page map[string]interface{}
+		switch r.URL.Path {
+		case "/2.0/user/permissions/workspaces":
+			page = values(
+				map[string]interface{}{"permission": "owner", "workspace": map[string]string{"slug": "acme"}},
+				// The permissions of workspaces we don't own can't be read.
+				map[string]interface{}{"permission": "member", "workspace": map[string]string{"slug": "other"}},
+			)
+
+		case "/2.0/workspaces/acme/permissions":
+			if q.Get("q") != `user.nickname="alice"` {
+				page = values()
+				break
+			}
+			page = values(map[string]interface{}{"permission": "member", "user": alice})
+
+		case "/2.0/workspaces/acme/permissions/repositories":
+			if q.Get("q") != `user.uuid="{alice}"` {
+				t.Errorf("unexpected query: %q", q.Get("q"))
+			}
+			if q.Get("page") == "" {
+				page = values(map[string]interface{}{"permission": "write", "repository": map[string]string{"uuid": "{api}"}})
+				page["next"] = srv.URL + r.URL.Path + "?" + q.Encode() + "&page=2"
+			} else {
+				page = values(map[string]interface{}{"permission": "read", "repository": map[string]string{"uuid": "{web}"}})
+			}
+
+		case "/2.0/workspaces/acme/permissions/repositories/api":
+			page = values(
+				map[string]interface{}{"permission": "admin", "user": alice},
+				map[string]interface{}{"permission": "read", "user": map[string]string{"uuid": "{bob}", "nickname": "bob"}},
+			)
+
+		default:
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(page); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	p := newTestProvider(t, srv.URL, "secret")
+	ctx := context.Background()
+
+	t.Run("Validate", func(t *testing.T) {
+		if problems := p.Validate(); len(problems) > 0 {
+			t.Errorf("unexpected problems: %v", problems)
+		}
+		if problems := newTestProvider(t, srv.URL, "wrong").Validate(); len(problems) != 1 {
+			t.Errorf("expected one problem for bad credentials, got %v", problems)
+		}
+	})
+
+	var account *extsvc.Account
+	t.Run("FetchAccount", func(t *testing.T) {
+		acct, err := p.FetchAccount(ctx, &types.User{ID: 42, Username: "carol"}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acct != nil {
+			t.Errorf("expected no account for unknown user, got %+v", acct)
+		}
+
+		account, err = p.FetchAccount(ctx, &types.User{ID: 42, Username: "alice"}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := extsvc.AccountSpec{
+			ServiceType: extsvc.TypeBitbucketCloud,
+			ServiceID:   "https://bitbucket.org/",
+			AccountID:   "{alice}",
+		}
+		if diff := cmp.Diff(want, account.AccountSpec); diff != "" {
+			t.Errorf("account spec mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("FetchUserPerms", func(t *testing.T) {
+		perms, err := p.FetchUserPerms(ctx, account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &authz.ExternalUserPermissions{Exacts: []extsvc.RepoID{"{api}", "{web}"}}
+		if diff := cmp.Diff(want, perms); diff != "" {
+			t.Errorf("permissions mismatch (-want +got):\n%s", diff)
+		}
+
+		other := *account
+		other.ServiceID = "https://bitbucket.example.com/"
+		if _, err := p.FetchUserPerms(ctx, &other); err == nil {
+			t.Error("expected error for account of another code host")
+		}
+	})
+
+	t.Run("FetchRepoPerms", func(t *testing.T) {
+		ids, err := p.FetchRepoPerms(ctx, &extsvc.Repository{
+			URI: "bitbucket.org/acme/api",
+			ExternalRepoSpec: api.ExternalRepoSpec{
+				ID:          "{api}",
+				ServiceType: extsvc.TypeBitbucketCloud,
+				ServiceID:   "https://bitbucket.org/",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]extsvc.AccountID{"{alice}", "{bob}"}, ids); diff != "" {
+			t.Errorf("account IDs mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+var alice = map[string]string{"uuid": "{alice}", "nickname": "alice", "account_id": "557058:alice"}
+
+func values(vs ...interface{}) map[string]interface{} {
+	if vs == nil {
+		vs = []interface{}{}
+	}
+	return map[string]interface{}{"values": vs}
+}
+
+func newTestProvider(t *testing.T, apiURL, appPassword string) *Provider {
+	t.Helper()
+
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cli := bitbucketcloud.NewClient(u, http.DefaultClient)
+	cli.Username = "admin"
+	cli.AppPassword = appPassword
+	cli.RateLimit = rate.NewLimiter(rate.Inf, 0)
+
+	return NewProvider("extsvc:bitbucketcloud:1", &url.URL{Scheme: "https", Host: "bitbucket.org"}, cli)
+}
@@ -0,0 +1,402 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/sergi/go-diff/diffmatchpatch"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/version"
+)
+
+// AuditLogAction is the kind of change recorded by an audit log entry.
+type AuditLogAction string
+
+const (
+	AuditLogActionSiteConfigUpdated AuditLogAction = "SiteConfigUpdated"
+
+	AuditLogActionExternalServiceCreated AuditLogAction = "ExternalServiceCreated"
+	AuditLogActionExternalServiceUpdated AuditLogAction = "ExternalServiceUpdated"
+	AuditLogActionExternalServiceDeleted AuditLogAction = "ExternalServiceDeleted"
+
+	AuditLogActionUserPermissionsUpdated AuditLogAction = "UserPermissionsUpdated"
+	AuditLogActionRepoPermissionsUpdated AuditLogAction = "RepoPermissionsUpdated"
+
+	AuditLogActionOrgMemberAdded   AuditLogAction = "OrgMemberAdded"
+	AuditLogActionOrgMemberRemoved AuditLogAction = "OrgMemberRemoved"
+)
+
+// AuditLogTargetType is the type of the entity changed by an audit log entry.
+type AuditLogTargetType string
+
+const (
+	AuditLogTargetSiteConfig      AuditLogTargetType = "SiteConfig"
+	AuditLogTargetExternalService AuditLogTargetType = "ExternalService"
+	AuditLogTargetUser            AuditLogTargetType = "User"
+	AuditLogTargetRepo            AuditLogTargetType = "Repo"
+	AuditLogTargetOrg             AuditLogTargetType = "Org"
+)
+
+// AuditLogEntry is a change recorded in the audit log.
+type AuditLogEntry struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ActorUserID is the user who made the change, or 0 if it was not made by a
+	// user.
+	ActorUserID int32 `json:"actorUserID,omitempty"`
+	// ActorInternal is true if the change was made by an internal Sourcegraph
+	// service.
+	ActorInternal bool `json:"actorInternal,omitempty"`
+
+	Action     AuditLogAction     `json:"action"`
+	TargetType AuditLogTargetType `json:"targetType"`
+	TargetID   string             `json:"targetID,omitempty"`
+
+	// Before and After are the states of the target before and after the change,
+	// with secrets redacted. They are nil if the target didn't exist before or
+	// after the change.
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+
+	RemoteAddr   string `json:"remoteAddr,omitempty"`
+	ForwardedFor string `json:"forwardedFor,omitempty"`
+	UserAgent    string `json:"userAgent,omitempty"`
+	Version      string `json:"version"`
+}
+
+// Diff returns a line diff of the indented JSON states of the target before and
+// after the change. Removed lines are prefixed with "-", added lines with "+" and
+// unchanged lines with a space.
+func (e *AuditLogEntry) Diff() (string, error) {
+	before, err := indentAuditLogState(e.Before)
+	if err != nil {
+		return "", err
+	}
+	after, err := indentAuditLogState(e.After)
+	if err != nil {
+		return "", err
+	}
+
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	var buf strings.Builder
+	for _, d := range diffs {
+		prefix := " "
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		}
+		for _, line := range strings.SplitAfter(d.Text, "\n") {
+			if line != "" {
+				buf.WriteString(prefix + line)
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
+func indentAuditLogState(state json.RawMessage) (string, error) {
+	if len(state) == 0 {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, state, "", "  "); err != nil {
+		return "", err
+	}
+	buf.WriteByte('\n')
+	return buf.String(), nil
+}
+
+// AuditLogStore provides access to the append-only `audit_logs` table.
+type AuditLogStore struct {
+	*basestore.Store
+}
+
+// AuditLogs instantiates and returns a new AuditLogStore.
+func AuditLogs(db dbutil.DB) *AuditLogStore {
+	return &AuditLogStore{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// AuditLogsWith instantiates and returns a new AuditLogStore using the other
+// store handle, so that entries are recorded in the same transaction as the
+// changes they describe.
+func AuditLogsWith(other basestore.ShareableStore) *AuditLogStore {
+	return &AuditLogStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+// Record adds an entry for the given change to the audit log. The actor and
+// the client that made the change are taken from the context. Before and after
+// are marshaled to JSON, and any value that looks like a secret is redacted.
+func (s *AuditLogStore) Record(ctx context.Context, action AuditLogAction, targetType AuditLogTargetType, targetID string, before, after interface{}) error {
+	a := actor.FromContext(ctx)
+	e := &AuditLogEntry{
+		ActorUserID:   a.UID,
+		ActorInternal: a.Internal,
+		Action:        action,
+		TargetType:    targetType,
+		TargetID:      targetID,
+	}
+	if client := trace.RequestClientFromContext(ctx); client != nil {
+		e.RemoteAddr = client.RemoteAddr
+		e.ForwardedFor = client.ForwardedFor
+		e.UserAgent = client.UserAgent
+	}
+
+	var err error
+	if e.Before, err = marshalAuditLogState(before); err != nil {
+		return errors.Wrap(err, "marshalling state before change")
+	}
+	if e.After, err = marshalAuditLogState(after); err != nil {
+		return errors.Wrap(err, "marshalling state after change")
+	}
+
+	return s.Insert(ctx, e)
+}
+
+// Insert adds the given entry to the audit log, and sets its ID, creation time
+// and version. The before and after states are stored as is.
+func (s *AuditLogStore) Insert(ctx context.Context, e *AuditLogEntry) error {
+	e.Version = version.Version()
+
+	return s.QueryRow(ctx, sqlf.Sprintf(
+		`INSERT INTO audit_logs
+			(actor_user_id, actor_internal, action, target_type, target_id, before, after, remote_addr, forwarded_for, user_agent, version)
+		VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+		RETURNING id, created_at`,
+		nullInt32Column(e.ActorUserID),
+		e.ActorInternal,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		nullJSONColumn(e.Before),
+		nullJSONColumn(e.After),
+		e.RemoteAddr,
+		e.ForwardedFor,
+		e.UserAgent,
+		e.Version,
+	)).Scan(&e.ID, &e.CreatedAt)
+}
+
+// AuditLogListOptions contains options for listing audit log entries.
+type AuditLogListOptions struct {
+	// ActorUserID, if non-zero, only includes changes made by this user.
+	ActorUserID int32
+	// Actions, if non-empty, only includes changes of these kinds.
+	Actions []AuditLogAction
+	// TargetType, if non-empty, only includes changes to targets of this type.
+	TargetType AuditLogTargetType
+	// TargetID, if non-empty, only includes changes to the target with this ID.
+	TargetID string
+	// Since and Until, if set, only include changes made in this time range
+	// (inclusive of Since and exclusive of Until).
+	Since, Until *time.Time
+
+	// Ascending lists the oldest entries first. By default, the most recent
+	// entries are listed first.
+	Ascending bool
+	// Cursor, if non-zero, only includes entries listed after the entry with
+	// this ID.
+	Cursor int64
+	// Limit, if non-zero, is the maximum number of entries listed.
+	Limit int
+}
+
+func (opts AuditLogListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if opts.ActorUserID != 0 {
+		conds = append(conds, sqlf.Sprintf("actor_user_id = %s", opts.ActorUserID))
+	}
+	if len(opts.Actions) > 0 {
+		actions := make([]string, 0, len(opts.Actions))
+		for _, action := range opts.Actions {
+			actions = append(actions, string(action))
+		}
+		conds = append(conds, sqlf.Sprintf("action = ANY(%s)", pq.Array(actions)))
+	}
+	if opts.TargetType != "" {
+		conds = append(conds, sqlf.Sprintf("target_type = %s", opts.TargetType))
+	}
+	if opts.TargetID != "" {
+		conds = append(conds, sqlf.Sprintf("target_id = %s", opts.TargetID))
+	}
+	if opts.Since != nil {
+		conds = append(conds, sqlf.Sprintf("created_at >= %s", *opts.Since))
+	}
+	if opts.Until != nil {
+		conds = append(conds, sqlf.Sprintf("created_at < %s", *opts.Until))
+	}
+	return conds
+}
+
+// List returns the audit log entries matching the given options.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *AuditLogStore) List(ctx context.Context, opts AuditLogListOptions) (_ []*AuditLogEntry, err error) {
+	if Mocks.AuditLogs.List != nil {
+		return Mocks.AuditLogs.List(ctx, opts)
+	}
+
+	conds := opts.sqlConditions()
+	order := sqlf.Sprintf("id DESC")
+	if opts.Cursor != 0 {
+		if opts.Ascending {
+			conds = append(conds, sqlf.Sprintf("id > %s", opts.Cursor))
+		} else {
+			conds = append(conds, sqlf.Sprintf("id < %s", opts.Cursor))
+		}
+	}
+	if opts.Ascending {
+		order = sqlf.Sprintf("id ASC")
+	}
+	limit := &sqlf.Query{}
+	if opts.Limit > 0 {
+		limit = sqlf.Sprintf("LIMIT %s", opts.Limit)
+	}
+
+	rows, err := s.Query(ctx, sqlf.Sprintf(
+		`SELECT id, created_at, actor_user_id, actor_internal, action, target_type, target_id, before, after, remote_addr, forwarded_for, user_agent, version
+		FROM audit_logs WHERE %s ORDER BY %s %s`,
+		sqlf.Join(conds, "AND"),
+		order,
+		limit,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var entries []*AuditLogEntry
+	for rows.Next() {
+		var (
+			e             AuditLogEntry
+			actorUserID   sql.NullInt32
+			before, after []byte
+		)
+		if err := rows.Scan(
+			&e.ID,
+			&e.CreatedAt,
+			&actorUserID,
+			&e.ActorInternal,
+			&e.Action,
+			&e.TargetType,
+			&e.TargetID,
+			&before,
+			&after,
+			&e.RemoteAddr,
+			&e.ForwardedFor,
+			&e.UserAgent,
+			&e.Version,
+		); err != nil {
+			return nil, err
+		}
+		e.ActorUserID = actorUserID.Int32
+		if before != nil {
+			e.Before = before
+		}
+		if after != nil {
+			e.After = after
+		}
+		entries = append(entries, &e)
+	}
+
+	return entries, nil
+}
+
+// Count returns the number of audit log entries matching the given options. The
+// Cursor and Limit options are ignored.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *AuditLogStore) Count(ctx context.Context, opts AuditLogListOptions) (int, error) {
+	if Mocks.AuditLogs.Count != nil {
+		return Mocks.AuditLogs.Count(ctx, opts)
+	}
+
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(
+		"SELECT COUNT(*) FROM audit_logs WHERE %s",
+		sqlf.Join(opts.sqlConditions(), "AND"),
+	)))
+	return count, err
+}
+
+func marshalAuditLogState(state interface{}) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	buf, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactAuditLogSecrets("", v))
+}
+
+// auditLogSecretKeyPattern matches the names of object keys whose values are
+// redacted from the audit log.
+var auditLogSecretKeyPattern = lazyregexp.New(`(?i)(password|passwd|secret|token|privatekey|apikey|licensekey|credential)`)
+
+// redactAuditLogSecrets replaces all values of object keys that look like they
+// hold secrets, and the passwords of URLs, with types.RedactedSecret.
+func redactAuditLogSecrets(key string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			v[k] = redactAuditLogSecrets(k, value)
+		}
+		return v
+
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactAuditLogSecrets(key, value)
+		}
+		return v
+
+	case string:
+		if key != "" && auditLogSecretKeyPattern.MatchString(key) {
+			return types.RedactedSecret
+		}
+		if u, err := url.Parse(v); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), types.RedactedSecret)
+				return u.String()
+			}
+		}
+		return v
+
+	default:
+		if v != nil && key != "" && auditLogSecretKeyPattern.MatchString(key) {
+			return types.RedactedSecret
+		}
+		return v
+	}
+}
+
+func nullJSONColumn(v json.RawMessage) interface{} {
+	if len(v) == 0 {
+		return nil
+	}
+	return []byte(v)
+}
@@ -0,0 +1,8 @@
+package database
+
+import "context"
+
+type MockAuditLogs struct {
+	List  func(ctx context.Context, opts AuditLogListOptions) ([]*AuditLogEntry, error)
+	Count func(ctx context.Context, opts AuditLogListOptions) (int, error)
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -46,6 +48,7 @@ type ExternalServiceStore struct {
 	GitHubValidators          []func(*schema.GitHubConnection) error
 	GitLabValidators          []func(*schema.GitLabConnection, []schema.AuthProviders) error
 	BitbucketServerValidators []func(*schema.BitbucketServerConnection) error
+	BitbucketCloudValidators  []func(*schema.BitbucketCloudConnection) error
 	PerforceValidators        []func(*schema.PerforceConnection) error
 
 	key encryption.Key
@@ -60,6 +63,7 @@ func (e *ExternalServiceStore) copy() *ExternalServiceStore {
 		GitHubValidators:          e.GitHubValidators,
 		GitLabValidators:          e.GitLabValidators,
 		BitbucketServerValidators: e.BitbucketServerValidators,
+		BitbucketCloudValidators:  e.BitbucketCloudValidators,
 		PerforceValidators:        e.PerforceValidators,
 	}
 }
@@ -121,6 +125,8 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindAWSCodeCommit:   {CodeHost: true, JSONSchema: schema.AWSCodeCommitSchemaJSON},
 	extsvc.KindBitbucketCloud:  {CodeHost: true, JSONSchema: schema.BitbucketCloudSchemaJSON},
 	extsvc.KindBitbucketServer: {CodeHost: true, JSONSchema: schema.BitbucketServerSchemaJSON},
+	extsvc.KindGerrit:          {CodeHost: true, JSONSchema: schema.GerritSchemaJSON},
+	extsvc.KindGitea:           {CodeHost: true, JSONSchema: schema.GiteaSchemaJSON},
 	extsvc.KindGitHub:          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	extsvc.KindGitLab:          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
@@ -312,6 +318,20 @@ func (e *ExternalServiceStore) ValidateConfig(ctx context.Context, opt ValidateE
 		}
 		err = e.validateBitbucketCloudConnection(ctx, opt.ExternalServiceID, &c)
 
+	case extsvc.KindGerrit:
+		var c schema.GerritConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = e.validateGerritConnection(ctx, opt.ExternalServiceID, &c)
+
+	case extsvc.KindGitea:
+		var c schema.GiteaConnection
+		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
+			return nil, err
+		}
+		err = e.validateGiteaConnection(ctx, opt.ExternalServiceID, &c)
+
 	case extsvc.KindPerforce:
 		var c schema.PerforceConnection
 		if err = jsoniter.Unmarshal(normalized, &c); err != nil {
@@ -401,7 +421,38 @@ func (e *ExternalServiceStore) validateBitbucketServerConnection(ctx context.Con
 }
 
 func (e *ExternalServiceStore) validateBitbucketCloudConnection(ctx context.Context, id int64, c *schema.BitbucketCloudConnection) error {
-	return e.validateDuplicateRateLimits(ctx, id, extsvc.KindBitbucketCloud, c)
+	err := new(multierror.Error)
+	for _, validate := range e.BitbucketCloudValidators {
+		err = multierror.Append(err, validate(c))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindBitbucketCloud, c))
+
+	return err.ErrorOrNil()
+}
+
+func (e *ExternalServiceStore) validateGerritConnection(ctx context.Context, id int64, c *schema.GerritConnection) error {
+	err := new(multierror.Error)
+
+	if (c.Username == "") != (c.Password == "") {
+		err = multierror.Append(err, errors.New("username and password must be set together"))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindGerrit, c))
+
+	return err.ErrorOrNil()
+}
+
+func (e *ExternalServiceStore) validateGiteaConnection(ctx context.Context, id int64, c *schema.GiteaConnection) error {
+	err := new(multierror.Error)
+
+	if len(c.Orgs) == 0 && len(c.Users) == 0 && len(c.RepositoryQuery) == 0 && len(c.Repos) == 0 {
+		err = multierror.Append(err, errors.New("at least one of orgs, users, repositoryQuery or repos must be set"))
+	}
+
+	err = multierror.Append(err, e.validateDuplicateRateLimits(ctx, id, extsvc.KindGitea, c))
+
+	return err.ErrorOrNil()
 }
 
 func (e *ExternalServiceStore) validatePerforceConnection(ctx context.Context, id int64, c *schema.PerforceConnection) error {
@@ -536,7 +587,7 @@ func upsertAuthorizationToExternalService(kind, config string) (string, error) {
 // recalculated based on whether "authorization" field is presented in
 // `es.Config`. For Sourcegraph Cloud, the `es.Unrestricted` will always be
 // false (i.e. enforce permissions).
-func (e *ExternalServiceStore) Create(ctx context.Context, confGet func() *conf.Unified, es *types.ExternalService) error {
+func (e *ExternalServiceStore) Create(ctx context.Context, confGet func() *conf.Unified, es *types.ExternalService) (err error) {
 	if Mocks.ExternalServices.Create != nil {
 		return Mocks.ExternalServices.Create(ctx, confGet, es)
 	}
@@ -578,11 +629,54 @@ func (e *ExternalServiceStore) Create(ctx context.Context, confGet func() *conf.
 		return err
 	}
 
-	return e.Store.Handle().DB().QueryRowContext(
+	tx, err := e.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	err = tx.Store.Handle().DB().QueryRowContext(
 		ctx,
 		"INSERT INTO external_services(kind, display_name, config, encryption_key_id, created_at, updated_at, namespace_user_id, unrestricted, cloud_default) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id",
 		es.Kind, es.DisplayName, config, keyID, es.CreatedAt, es.UpdatedAt, nullInt32Column(es.NamespaceUserID), es.Unrestricted, es.CloudDefault,
 	).Scan(&es.ID)
+	if err != nil {
+		return err
+	}
+
+	after, err := newAuditLogExternalService(es)
+	if err != nil {
+		return err
+	}
+	return AuditLogsWith(tx).Record(ctx, AuditLogActionExternalServiceCreated, AuditLogTargetExternalService, strconv.FormatInt(es.ID, 10), nil, after)
+}
+
+// auditLogExternalService is the state of an external service recorded in the
+// audit log.
+type auditLogExternalService struct {
+	Kind            string          `json:"kind"`
+	DisplayName     string          `json:"displayName"`
+	Config          json.RawMessage `json:"config"`
+	NamespaceUserID int32           `json:"namespaceUserID,omitempty"`
+	Unrestricted    bool            `json:"unrestricted"`
+	CloudDefault    bool            `json:"cloudDefault"`
+}
+
+// newAuditLogExternalService returns the state of the given external service,
+// whose config must be decrypted, for the audit log.
+func newAuditLogExternalService(es *types.ExternalService) (*auditLogExternalService, error) {
+	config, err := jsonc.Parse(es.Config)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing external service config")
+	}
+	return &auditLogExternalService{
+		Kind:            es.Kind,
+		DisplayName:     es.DisplayName,
+		Config:          config,
+		NamespaceUserID: es.NamespaceUserID,
+		Unrestricted:    es.Unrestricted,
+		CloudDefault:    es.CloudDefault,
+	}, nil
 }
 
 // maybeEncryptConfig encrypts and returns externals service config if an encryption.Key is configured
@@ -883,6 +977,12 @@ func (e *ExternalServiceStore) Update(ctx context.Context, ps []schema.AuthProvi
 	}
 	defer func() { err = tx.Done(err) }()
 
+	txs := e.With(basestore.NewWithHandle(tx))
+	before, err := txs.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	if update.DisplayName != nil {
 		if err := execUpdate(ctx, tx.DB(), sqlf.Sprintf("display_name=%s", update.DisplayName)); err != nil {
 			return err
@@ -902,7 +1002,37 @@ func (e *ExternalServiceStore) Update(ctx context.Context, ps []schema.AuthProvi
 			return err
 		}
 	}
-	return nil
+
+	after, err := txs.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return recordExternalServiceChange(ctx, txs, AuditLogActionExternalServiceUpdated, before, after)
+}
+
+// recordExternalServiceChange records the change of an external service from
+// before to after in the audit log. Either may be nil.
+func recordExternalServiceChange(ctx context.Context, s basestore.ShareableStore, action AuditLogAction, before, after *types.ExternalService) error {
+	var (
+		id          int64
+		beforeState interface{}
+		afterState  interface{}
+	)
+	if before != nil {
+		state, err := newAuditLogExternalService(before)
+		if err != nil {
+			return err
+		}
+		id, beforeState = before.ID, state
+	}
+	if after != nil {
+		state, err := newAuditLogExternalService(after)
+		if err != nil {
+			return err
+		}
+		id, afterState = after.ID, state
+	}
+	return AuditLogsWith(s).Record(ctx, action, AuditLogTargetExternalService, strconv.FormatInt(id, 10), beforeState, afterState)
 }
 
 type externalServiceNotFoundError struct {
@@ -932,6 +1062,11 @@ func (e *ExternalServiceStore) Delete(ctx context.Context, id int64) (err error)
 	}
 	defer func() { err = tx.Done(err) }()
 
+	before, err := tx.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	// Create a temporary table where we'll store repos affected by the deletion of
 	// the external service
 	if err := tx.Exec(ctx, sqlf.Sprintf(`
@@ -996,7 +1131,8 @@ CREATE TEMPORARY TABLE IF NOT EXISTS
 	if nrows == 0 {
 		return externalServiceNotFoundError{id: id}
 	}
-	return nil
+
+	return recordExternalServiceChange(ctx, tx, AuditLogActionExternalServiceDeleted, before, nil)
 }
 
 // GetByID returns the external service for id.
@@ -3,12 +3,17 @@ package database
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"sync"
 
 	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -348,3 +353,298 @@ func (m *ExternalAccountsMigrator) Down(ctx context.Context) (err error) {
 
 	return nil
 }
+
+// EncryptionKeyRotationMigrator is a background job that re-encrypts
+// encrypted rows whose encryption key has been rotated, i.e. rows whose
+// encryption_key_id no longer matches the version of the configured key.
+// The configured key must still be able to decrypt values encrypted with
+// the previous versions, as is the case for file keyrings and Vault Transit
+// keys.
+// Rows that have not been encrypted yet are left to the migrators encrypting
+// them in the first place. Rows that cannot be decrypted with the configured
+// key are logged and skipped until the next restart, and count as migrated
+// towards the progress.
+// Scheduling and progress report is delegated to the out of band
+// migration package.
+// The migration is non destructive, and there is nothing to revert.
+type EncryptionKeyRotationMigrator struct {
+	store     *basestore.Store
+	BatchSize int
+
+	mu sync.Mutex
+	// skipped holds the IDs of the rows that could not be decrypted, by table.
+	skipped map[string][]int64
+}
+
+func NewEncryptionKeyRotationMigrator(store *basestore.Store) *EncryptionKeyRotationMigrator {
+	// not locking too many rows at a time to prevent congestion
+	return &EncryptionKeyRotationMigrator{store: store, BatchSize: 50, skipped: map[string][]int64{}}
+}
+
+func NewEncryptionKeyRotationMigratorWithDB(db dbutil.DB) *EncryptionKeyRotationMigrator {
+	return NewEncryptionKeyRotationMigrator(basestore.NewWithDB(db, sql.TxOptions{}))
+}
+
+// ID of the migration row in the out_of_band_migrations table.
+// This ID was defined arbitrarily in this migration file: frontend/1528395859_oob_encryption_key_rotation.up.sql
+func (m *EncryptionKeyRotationMigrator) ID() int {
+	return 11
+}
+
+// encryptedTable describes a table with encrypted columns and an
+// encryption_key_id column identifying the key they were encrypted with.
+type encryptedTable struct {
+	name    string
+	columns []string
+	// bytea is true if the encrypted columns are of type bytea rather
+	// than text.
+	bytea bool
+	// key returns the key the columns are encrypted with.
+	key func(keyring.Ring) encryption.Key
+	// placeholderKeyIDs are values of encryption_key_id that don't identify
+	// an encryption key, in addition to the empty string.
+	placeholderKeyIDs []string
+}
+
+var encryptedTables = []encryptedTable{
+	{
+		name:    "external_services",
+		columns: []string{"config"},
+		key:     func(r keyring.Ring) encryption.Key { return r.ExternalServiceKey },
+	},
+	{
+		name:    "user_external_accounts",
+		columns: []string{"auth_data", "account_data"},
+		key:     func(r keyring.Ring) encryption.Key { return r.UserExternalAccountKey },
+	},
+	{
+		name:              "user_credentials",
+		columns:           []string{"credential"},
+		bytea:             true,
+		key:               func(r keyring.Ring) encryption.Key { return r.BatchChangesCredentialKey },
+		placeholderKeyIDs: []string{UserCredentialPlaceholderEncryptionKeyID, UserCredentialUnmigratedEncryptionKeyID},
+	},
+	{
+		// Site credentials use the same placeholders as user credentials.
+		name:              "batch_changes_site_credentials",
+		columns:           []string{"credential"},
+		bytea:             true,
+		key:               func(r keyring.Ring) encryption.Key { return r.BatchChangesCredentialKey },
+		placeholderKeyIDs: []string{UserCredentialPlaceholderEncryptionKeyID, UserCredentialUnmigratedEncryptionKeyID},
+	},
+}
+
+// Progress returns a value from 0 to 1 representing the percentage of encrypted rows
+// that are encrypted with the current version of their key, or have been skipped as
+// they cannot be decrypted.
+func (m *EncryptionKeyRotationMigrator) Progress(ctx context.Context) (float64, error) {
+	ring := keyring.Default()
+
+	var current, total int
+	for _, table := range encryptedTables {
+		key := table.key(ring)
+		if key == nil {
+			continue
+		}
+
+		version, err := key.Version(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		var c, t int
+		if err := m.store.QueryRow(ctx, sqlf.Sprintf(
+			"SELECT COUNT(*) FILTER (WHERE encryption_key_id = %s OR id = ANY(%s)), COUNT(*) FROM "+table.name+" WHERE %s",
+			version.JSON(),
+			pq.Array(m.skippedRows(table.name)),
+			table.encryptedCond(),
+		)).Scan(&c, &t); err != nil {
+			return 0, err
+		}
+		current += c
+		total += t
+	}
+
+	if total == 0 {
+		return 1, nil
+	}
+	return float64(current) / float64(total), nil
+}
+
+// Up loads BatchSize rows of each table that are encrypted with a previous version of
+// the key returned by keyring.Default(), locks them, and re-encrypts them with the
+// current version of the key. Keys implementing encryption.Rewrapper, such as envelope
+// keys, only re-encrypt the key material protecting the values.
+// Up ensures the values can be decrypted with the same key before overwriting them.
+// Rows that cannot be decrypted are skipped. The key id is stored alongside the
+// encrypted values.
+func (m *EncryptionKeyRotationMigrator) Up(ctx context.Context) (err error) {
+	ring := keyring.Default()
+
+	tx, err := m.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	for _, table := range encryptedTables {
+		key := table.key(ring)
+		if key == nil {
+			continue
+		}
+
+		if err := m.reencryptTable(ctx, tx, table, key); err != nil {
+			return errors.Wrapf(err, "re-encrypting %s", table.name)
+		}
+	}
+
+	return nil
+}
+
+func (m *EncryptionKeyRotationMigrator) Down(ctx context.Context) error {
+	// Re-encrypting with the previous version would not make any difference,
+	// as the current version of the key can decrypt both.
+	return nil
+}
+
+func (m *EncryptionKeyRotationMigrator) reencryptTable(ctx context.Context, tx *basestore.Store, table encryptedTable, key encryption.Key) error {
+	version, err := key.Version(ctx)
+	if err != nil {
+		return err
+	}
+	keyIdent := version.JSON()
+
+	rows, err := m.listRowsForUpdate(ctx, tx, table, keyIdent)
+	if err != nil {
+		return err
+	}
+
+rows:
+	for _, row := range rows {
+		assignments := make([]*sqlf.Query, 0, len(table.columns)+1)
+		for i, column := range table.columns {
+			value, err := reencrypt(ctx, key, row.values[i])
+			if err != nil {
+				if errors.HasType(err, &decryptionError{}) {
+					log15.Warn("Skipping row that cannot be decrypted with the current encryption key", "table", table.name, "id", row.id, "error", err)
+					m.skipRow(table.name, row.id)
+					continue rows
+				}
+				return err
+			}
+
+			var arg interface{} = value
+			if value != nil && table.bytea {
+				arg = []byte(*value)
+			}
+			assignments = append(assignments, sqlf.Sprintf(column+" = %s", arg))
+		}
+		assignments = append(assignments, sqlf.Sprintf("encryption_key_id = %s", keyIdent))
+
+		if err := tx.Exec(ctx, sqlf.Sprintf(
+			"UPDATE "+table.name+" SET %s WHERE id = %s",
+			sqlf.Join(assignments, ", "),
+			row.id,
+		)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decryptionError is returned by reencrypt when the given value cannot be decrypted.
+type decryptionError struct {
+	err error
+}
+
+func (e *decryptionError) Error() string {
+	return "decrypting value: " + e.err.Error()
+}
+
+// reencrypt re-encrypts the given value with the current version of key. Missing
+// and empty values are not encrypted, and are returned as is.
+func reencrypt(ctx context.Context, key encryption.Key, value *string) (*string, error) {
+	if value == nil || *value == "" {
+		return value, nil
+	}
+
+	secret, err := key.Decrypt(ctx, []byte(*value))
+	if err != nil {
+		return nil, &decryptionError{err: err}
+	}
+	encrypted, err := encryption.Rewrap(ctx, key, []byte(*value))
+	if err != nil {
+		return nil, err
+	}
+
+	// ensure encryption round-trip is valid
+	decrypted, err := key.Decrypt(ctx, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	if decrypted.Secret() != secret.Secret() {
+		return nil, errors.New("invalid encryption round-trip")
+	}
+
+	return strptr(string(encrypted)), nil
+}
+
+type encryptedRow struct {
+	id     int64
+	values []*string
+}
+
+func (m *EncryptionKeyRotationMigrator) listRowsForUpdate(ctx context.Context, tx *basestore.Store, table encryptedTable, keyIdent string) (_ []encryptedRow, err error) {
+	// Select and lock a few records within this transaction. This ensures
+	// that many frontend instances can run the same migration concurrently
+	// without them all trying to convert the same record.
+	rows, err := tx.Query(ctx, sqlf.Sprintf(
+		"SELECT id, "+strings.Join(table.columns, ", ")+" FROM "+table.name+" WHERE %s AND encryption_key_id != %s AND id != ALL(%s) ORDER BY id ASC LIMIT %s FOR UPDATE SKIP LOCKED",
+		table.encryptedCond(),
+		keyIdent,
+		pq.Array(m.skippedRows(table.name)),
+		m.BatchSize,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var encryptedRows []encryptedRow
+	for rows.Next() {
+		row := encryptedRow{values: make([]*string, len(table.columns))}
+		dest := []interface{}{&row.id}
+		for i := range row.values {
+			dest = append(dest, &row.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		encryptedRows = append(encryptedRows, row)
+	}
+
+	return encryptedRows, nil
+}
+
+func (m *EncryptionKeyRotationMigrator) skipRow(table string, id int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.skipped[table] = append(m.skipped[table], id)
+}
+
+func (m *EncryptionKeyRotationMigrator) skippedRows(table string) []int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]int64{}, m.skipped[table]...)
+}
+
+// encryptedCond returns a condition matching the rows of the table that have been
+// encrypted with some version of a key.
+func (t encryptedTable) encryptedCond() *sqlf.Query {
+	keyIDs := []*sqlf.Query{sqlf.Sprintf("''")}
+	for _, keyID := range t.placeholderKeyIDs {
+		keyIDs = append(keyIDs, sqlf.Sprintf("%s", keyID))
+	}
+	return sqlf.Sprintf("encryption_key_id NOT IN (%s)", sqlf.Join(keyIDs, ", "))
+}
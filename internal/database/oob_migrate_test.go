@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -658,3 +659,125 @@ func TestExternalAccountsMigrator(t *testing.T) {
 		}
 	})
 }
+
+func TestEncryptionKeyRotationMigrator(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+
+	key := &versionedKey{Key: et.TestKey{}, version: "v1"}
+	keyring.MockDefault(keyring.Ring{ExternalServiceKey: key})
+	defer keyring.MockDefault(keyring.Ring{})
+
+	migrator := NewEncryptionKeyRotationMigratorWithDB(db)
+	migrator.BatchSize = 2
+
+	requireProgressEqual := func(want float64) {
+		t.Helper()
+
+		got, err := migrator.Progress(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprintf("%.3f", want) != fmt.Sprintf("%.3f", got) {
+			t.Fatalf("invalid progress: want %f, got %f", want, got)
+		}
+	}
+
+	// progress on empty table should be 1
+	requireProgressEqual(1)
+
+	// Create 5 external services encrypted with v1 of the key
+	svcs := types.GenerateExternalServices(5, types.MakeExternalServices()...)
+	confGet := func() *conf.Unified {
+		return &conf.Unified{}
+	}
+	for _, svc := range svcs {
+		if err := ExternalServices(db).Create(ctx, confGet, svc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// everything is encrypted with the current version
+	requireProgressEqual(1)
+
+	// rotate the key
+	key.version = "v2"
+	requireProgressEqual(0)
+
+	for i := 1; i <= 3; i++ {
+		if err := migrator.Up(ctx); err != nil {
+			t.Fatal(err)
+		}
+		requireProgressEqual(math.Min(float64(i)*0.4, 1))
+	}
+
+	// were the configs actually re-encrypted?
+	rows, err := db.Query("SELECT config, encryption_key_id FROM external_services ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	wantKeyID, _ := key.Version(ctx)
+	var i int
+	for rows.Next() {
+		var config, keyID string
+		if err := rows.Scan(&config, &keyID); err != nil {
+			t.Fatal(err)
+		}
+
+		if keyID != wantKeyID.JSON() {
+			t.Fatalf("unexpected key id: want %q, got %q", wantKeyID.JSON(), keyID)
+		}
+
+		decrypted, err := key.Decrypt(ctx, []byte(config))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decrypted.Secret() != svcs[i].Config {
+			t.Fatalf("invalid config: want %q, got %q", svcs[i].Config, decrypted.Secret())
+		}
+
+		i++
+	}
+	if rows.Err() != nil {
+		t.Fatal(err)
+	}
+
+	// corrupt a config so that it cannot be decrypted, and rotate the key again
+	if _, err := db.Exec("UPDATE external_services SET config = '!not base64!' WHERE id = $1", svcs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	key.version = "v3"
+	requireProgressEqual(0)
+
+	// the corrupt row is skipped, and counts as migrated
+	for i := 1; i <= 3; i++ {
+		if err := migrator.Up(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requireProgressEqual(1)
+
+	var keyID string
+	if err := db.QueryRow("SELECT encryption_key_id FROM external_services WHERE id = $1", svcs[0].ID).Scan(&keyID); err != nil {
+		t.Fatal(err)
+	}
+	if keyID != wantKeyID.JSON() {
+		t.Fatalf("unexpected key id of skipped row: want %q, got %q", wantKeyID.JSON(), keyID)
+	}
+}
+
+// versionedKey is an encryption.Key whose version can be changed to simulate
+// the rotation of the key.
+type versionedKey struct {
+	encryption.Key
+	version string
+}
+
+func (k *versionedKey) Version(ctx context.Context) (encryption.KeyVersion, error) {
+	return encryption.KeyVersion{Type: "versioned", Version: k.version}, nil
+}
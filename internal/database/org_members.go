@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 
 	"github.com/cockroachdb/errors"
 	"github.com/jackc/pgconn"
@@ -37,12 +38,18 @@ func (m *OrgMemberStore) Transact(ctx context.Context) (*OrgMemberStore, error)
 	return &OrgMemberStore{Store: txBase}, err
 }
 
-func (m *OrgMemberStore) Create(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+func (m *OrgMemberStore) Create(ctx context.Context, orgID, userID int32) (_ *types.OrgMembership, err error) {
+	tx, err := m.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
 	om := types.OrgMembership{
 		OrgID:  orgID,
 		UserID: userID,
 	}
-	err := m.Handle().DB().QueryRowContext(
+	err = tx.Handle().DB().QueryRowContext(
 		ctx,
 		"INSERT INTO org_members(org_id, user_id) VALUES($1, $2) RETURNING id, created_at, updated_at",
 		om.OrgID, om.UserID).Scan(&om.ID, &om.CreatedAt, &om.UpdatedAt)
@@ -53,6 +60,10 @@ func (m *OrgMemberStore) Create(ctx context.Context, orgID, userID int32) (*type
 		}
 		return nil, err
 	}
+
+	if err := AuditLogsWith(tx).Record(ctx, AuditLogActionOrgMemberAdded, AuditLogTargetOrg, strconv.Itoa(int(orgID)), nil, auditLogOrgMember{UserID: userID}); err != nil {
+		return nil, err
+	}
 	return &om, nil
 }
 
@@ -67,9 +78,28 @@ func (m *OrgMemberStore) GetByOrgIDAndUserID(ctx context.Context, orgID, userID
 	return m.getOneBySQL(ctx, "INNER JOIN users ON org_members.user_id=users.id WHERE org_id=$1 AND user_id=$2 AND users.deleted_at IS NULL LIMIT 1", orgID, userID)
 }
 
-func (m *OrgMemberStore) Remove(ctx context.Context, orgID, userID int32) error {
-	_, err := m.Handle().DB().ExecContext(ctx, "DELETE FROM org_members WHERE (org_id=$1 AND user_id=$2)", orgID, userID)
-	return err
+func (m *OrgMemberStore) Remove(ctx context.Context, orgID, userID int32) (err error) {
+	tx, err := m.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	res, err := tx.Handle().DB().ExecContext(ctx, "DELETE FROM org_members WHERE (org_id=$1 AND user_id=$2)", orgID, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+
+	return AuditLogsWith(tx).Record(ctx, AuditLogActionOrgMemberRemoved, AuditLogTargetOrg, strconv.Itoa(int(orgID)), auditLogOrgMember{UserID: userID}, nil)
+}
+
+// auditLogOrgMember is the state of an organization membership recorded in the
+// audit log.
+type auditLogOrgMember struct {
+	UserID int32 `json:"userID"`
 }
 
 // GetByOrgID returns a list of all members of a given organization.
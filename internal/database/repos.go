@@ -28,6 +28,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -401,6 +403,10 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		r.Metadata = new(bitbucketserver.Repo)
 	case extsvc.TypeBitbucketCloud:
 		r.Metadata = new(bitbucketcloud.Repo)
+	case extsvc.TypeGerrit:
+		r.Metadata = new(gerrit.Project)
+	case extsvc.TypeGitea:
+		r.Metadata = new(gitea.Repository)
 	case extsvc.TypeAWSCodeCommit:
 		r.Metadata = new(awscodecommit.Repository)
 	case extsvc.TypeGitolite:
@@ -512,6 +518,16 @@ type ReposListOptions struct {
 	// OnlyPrivate excludes non-private repositories from the list.
 	OnlyPrivate bool
 
+	// DescriptionPatterns is a list of case-insensitive regular expressions,
+	// all of which must match the description of all repositories returned
+	// in the list.
+	DescriptionPatterns []string
+
+	// Topics is a list of topics, all of which must be set on all
+	// repositories returned in the list. Topics are read from the code host
+	// metadata and are only available for GitHub and GitLab repositories.
+	Topics []string
+
 	// Index when set will only include repositories which should be indexed
 	// if true. If false it will exclude repositories which should be
 	// indexed. An example use case of this is for indexed search only
@@ -715,6 +731,28 @@ func (s *RepoStore) list(ctx context.Context, tr *trace.Trace, opt ReposListOpti
 	return rows.Err()
 }
 
+// topicCond returns a condition matching repositories which have the given
+// topic in their code host metadata, ignoring case. GitHub stores topics in
+// RepositoryTopics, GitLab in topics and, before GitLab 14.0, tag_list.
+// GitHub topics are always lowercase, but GitLab topics can be mixed case, so
+// the stored topics are lowercased too.
+func topicCond(topic string) *sqlf.Query {
+	return sqlf.Sprintf(topicCondFmtstr, strings.ToLower(topic))
+}
+
+const topicCondFmtstr = `
+EXISTS (
+	SELECT 1
+	FROM (VALUES
+		(repo.metadata->'RepositoryTopics'->'Nodes'),
+		(repo.metadata->'topics'),
+		(repo.metadata->'tag_list')
+	) AS t(topics),
+	jsonb_array_elements(CASE WHEN jsonb_typeof(t.topics) = 'array' THEN t.topics ELSE '[]'::jsonb END) AS e(topic)
+	WHERE lower(COALESCE(e.topic->'Topic'->>'Name', e.topic#>>'{}')) = %s
+)
+`
+
 func (s *RepoStore) listSQL(ctx context.Context, opt ReposListOptions) (*sqlf.Query, error) {
 	var ctes, from, where []*sqlf.Query
 
@@ -816,6 +854,12 @@ func (s *RepoStore) listSQL(ctx context.Context, opt ReposListOptions) (*sqlf.Qu
 	if opt.OnlyArchived {
 		where = append(where, sqlf.Sprintf("archived"))
 	}
+	for _, pattern := range opt.DescriptionPatterns {
+		where = append(where, sqlf.Sprintf("repo.description ~* %s", pattern))
+	}
+	for _, topic := range opt.Topics {
+		where = append(where, topicCond(topic))
+	}
 	if opt.NoCloned {
 		// TODO(ryanslade): After 3.26 has been released we can assume that gitserver_repos is populated
 		// We'll remove repo.cloned and can then switch to this:
@@ -490,6 +490,97 @@ func TestRepos_List_fork(t *testing.T) {
 	}
 }
 
+func TestRepos_List_description(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := actor.WithInternalActor(context.Background())
+
+	search := mustCreate(ctx, t, db, &types.Repo{Name: "a/search", Description: "Code search and intelligence"}, types.CloneStatusNotCloned)
+	mustCreate(ctx, t, db, &types.Repo{Name: "a/other", Description: "Something else"}, types.CloneStatusNotCloned)
+
+	tests := []struct {
+		name string
+		opt  ReposListOptions
+		want []*types.Repo
+	}{
+		{"case insensitive", ReposListOptions{DescriptionPatterns: []string{"code SEARCH"}}, search},
+		{"all patterns must match", ReposListOptions{DescriptionPatterns: []string{"search", "else"}}, nil},
+		{"regexp", ReposListOptions{DescriptionPatterns: []string{"^code.*intelligence$"}}, search},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repos, err := Repos(db).List(ctx, test.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSONEqual(t, test.want, repos)
+		})
+	}
+}
+
+func TestRepos_List_topics(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+	db := dbtest.NewDB(t, "")
+	ctx := actor.WithInternalActor(context.Background())
+
+	gh := mustCreate(ctx, t, db, &types.Repo{Name: "github.com/a/b"}, types.CloneStatusNotCloned)
+	gl := mustCreate(ctx, t, db, &types.Repo{Name: "gitlab.com/a/b"}, types.CloneStatusNotCloned)
+	glOld := mustCreate(ctx, t, db, &types.Repo{Name: "gitlab.com/a/c"}, types.CloneStatusNotCloned)
+
+	for _, r := range []struct {
+		repo     *types.Repo
+		metadata string
+	}{
+		{gh[0], `{"RepositoryTopics": {"Nodes": [{"Topic": {"Name": "go"}}, {"Topic": {"Name": "search"}}]}}`},
+		{gl[0], `{"topics": ["Go"]}`},
+		{glOld[0], `{"tag_list": ["search"]}`},
+	} {
+		if _, err := db.ExecContext(ctx, "UPDATE repo SET metadata = $1 WHERE id = $2", r.metadata, r.repo.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Re-read the repositories so that the expected values include metadata.
+	list := func(opt ReposListOptions) []*types.Repo {
+		t.Helper()
+		repos, err := Repos(db).List(ctx, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repos
+	}
+	gh = list(ReposListOptions{Names: []string{"github.com/a/b"}})
+	gl = list(ReposListOptions{Names: []string{"gitlab.com/a/b"}})
+	glOld = list(ReposListOptions{Names: []string{"gitlab.com/a/c"}})
+
+	tests := []struct {
+		name string
+		opt  ReposListOptions
+		want []*types.Repo
+	}{
+		{"github and gitlab topics", ReposListOptions{Topics: []string{"go"}}, append(append([]*types.Repo(nil), gh...), gl...)},
+		{"gitlab tag list", ReposListOptions{Topics: []string{"search"}}, append(append([]*types.Repo(nil), gh...), glOld...)},
+		{"case insensitive", ReposListOptions{Topics: []string{"Go"}}, append(append([]*types.Repo(nil), gh...), gl...)},
+		{"all topics must be set", ReposListOptions{Topics: []string{"go", "search"}}, gh},
+		{"unknown topic", ReposListOptions{Topics: []string{"rust"}}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assertJSONEqual(t, test.want, list(test.opt))
+		})
+	}
+}
+
 func TestRepos_List_FailedSync(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
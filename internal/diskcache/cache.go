@@ -139,6 +139,25 @@ func (s *Store) OpenWithPath(ctx context.Context, key string, fetcher FetcherWit
 	}
 }
 
+// OpenIfExists will open a file from the local cache with key without filling
+// the cache if it is missing. If key is not in the cache, the returned error
+// satisfies os.IsNotExist.
+func (s *Store) OpenIfExists(key string) (*File, error) {
+	if s.Dir == "" {
+		return nil, errors.New("diskcache.Store.Dir must be set")
+	}
+
+	path := s.path(key)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update modified time, since the caller is using the item.
+	touch(path)
+	return &File{File: f, Path: path}, nil
+}
+
 // path returns the path for key.
 func (s *Store) path(key string) string {
 	// path uses a sha256 hash of the key since we want to use it for the
@@ -60,3 +60,42 @@ func TestOpen(t *testing.T) {
 		t.Fatal("Item was not properly evicted")
 	}
 }
+
+func TestOpenIfExists(t *testing.T) {
+	dir, err := os.MkdirTemp("", "diskcache_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := &Store{
+		Dir:       dir,
+		Component: "test",
+	}
+
+	if _, err := store.OpenIfExists("key"); !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error on empty cache, got %v", err)
+	}
+
+	f, err := store.Open(context.Background(), "key", func(ctx context.Context) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte("foobar"))), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	f, err = store.OpenIfExists("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "foobar" {
+		t.Fatalf("unexpected contents: %q", string(got))
+	}
+}
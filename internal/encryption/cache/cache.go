@@ -46,6 +46,12 @@ func (k *Key) Decrypt(ctx context.Context, ciphertext []byte) (*encryption.Secre
 	return &s, nil
 }
 
+// Rewrap re-encrypts the ciphertext with the current version of the underlying key, so
+// that the underlying key's own way of doing so isn't hidden by the cache.
+func (k *Key) Rewrap(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return encryption.Rewrap(ctx, k.Key, ciphertext)
+}
+
 func hash(v []byte) uint64 {
 	h := fnv.New64()
 	h.Write(v)
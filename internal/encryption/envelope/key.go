@@ -0,0 +1,158 @@
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+)
+
+// dataKeySize is the size of the AES-256 data keys generated for each value.
+const dataKeySize = 32
+
+// New returns an envelope.Key wrapping the passed key.
+func New(k encryption.Key) *Key {
+	return &Key{key: k}
+}
+
+// Key is an encryption.Key implementation that encrypts each value with a fresh
+// data key using AES GCM encryption, and stores the data key encrypted by the
+// wrapped key alongside the ciphertext. Only the data key is sent to the wrapped
+// key, which keeps the payloads sent to remote key management services small.
+type Key struct {
+	key encryption.Key
+}
+
+var _ encryption.Key = &Key{}
+var _ encryption.Rewrapper = &Key{}
+
+// Version returns the version of the wrapped key, with the type prefixed by
+// "envelope/" so that values encrypted directly with the wrapped key can be told
+// apart from values encrypted with the envelope.
+func (k *Key) Version(ctx context.Context) (encryption.KeyVersion, error) {
+	version, err := k.key.Version(ctx)
+	if err != nil {
+		return encryption.KeyVersion{}, err
+	}
+	version.Type = "envelope/" + version.Type
+	return version, nil
+}
+
+func (k *Key) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	encryptedDataKey, err := k.key.Encrypt(ctx, dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting data key")
+	}
+
+	out := encryptedValue{
+		DataKey:    encryptedDataKey,
+		Ciphertext: gcm.Seal(nonce, nonce, plaintext, nil),
+	}
+	jsonKey, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(jsonKey)), nil
+}
+
+func (k *Key) Decrypt(ctx context.Context, ciphertext []byte) (*encryption.Secret, error) {
+	ev, err := decodeValue(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := k.key.Decrypt(ctx, ev.DataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting data key")
+	}
+
+	gcm, err := newGCM([]byte(dataKey.Secret()))
+	if err != nil {
+		return nil, err
+	}
+	if len(ev.Ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("malformed ciphertext")
+	}
+	plaintext, err := gcm.Open(nil, ev.Ciphertext[:gcm.NonceSize()], ev.Ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, err
+	}
+	s := encryption.NewSecret(string(plaintext))
+	return &s, nil
+}
+
+// Rewrap re-encrypts the data key of the given value with the current version of the
+// wrapped key. The value itself remains encrypted with the same data key, so rotating
+// the wrapped key doesn't require re-encrypting any data.
+func (k *Key) Rewrap(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	ev, err := decodeValue(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := k.key.Decrypt(ctx, ev.DataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting data key")
+	}
+	if ev.DataKey, err = k.key.Encrypt(ctx, []byte(dataKey.Secret())); err != nil {
+		return nil, errors.Wrap(err, "encrypting data key")
+	}
+
+	jsonKey, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(jsonKey)), nil
+}
+
+func decodeValue(ciphertext []byte) (encryptedValue, error) {
+	buf, err := base64.StdEncoding.DecodeString(string(ciphertext))
+	if err != nil {
+		return encryptedValue{}, err
+	}
+	ev := encryptedValue{}
+	if err := json.Unmarshal(buf, &ev); err != nil {
+		return encryptedValue{}, err
+	}
+	return ev, nil
+}
+
+type encryptedValue struct {
+	// DataKey is the data key, encrypted by the wrapped key.
+	DataKey    []byte
+	Ciphertext []byte
+}
+
+func newGCM(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AES cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating GCM block cipher")
+	}
+	return gcm, nil
+}
@@ -0,0 +1,96 @@
+package envelope
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingKey{Key: et.TestKey{}}
+	k := New(inner)
+
+	plaintext := "a value that is much longer than the data key that is sent to the wrapped key"
+	ciphertext, err := k.Encrypt(ctx, []byte(plaintext))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), plaintext)
+
+	secret, err := k.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, secret.Secret())
+
+	// Only the data key is sent to the wrapped key.
+	for _, n := range inner.sizes {
+		assert.Equal(t, dataKeySize, n)
+	}
+
+	// Each value is encrypted with a different data key.
+	other, err := k.Encrypt(ctx, []byte(plaintext))
+	require.NoError(t, err)
+	assert.NotEqual(t, string(ciphertext), string(other))
+
+	version, err := k.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "envelope/testkey", version.Type)
+}
+
+func TestRewrap(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingKey{Key: et.TestKey{}}
+	k := New(inner)
+
+	plaintext := "a value that is much longer than the data key that is sent to the wrapped key"
+	ciphertext, err := k.Encrypt(ctx, []byte(plaintext))
+	require.NoError(t, err)
+
+	rewrapped, err := k.Rewrap(ctx, ciphertext)
+	require.NoError(t, err)
+
+	secret, err := k.Decrypt(ctx, rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, secret.Secret())
+
+	// The value is still encrypted with the same data key, which is re-encrypted
+	// by the wrapped key.
+	before, err := decodeValue(ciphertext)
+	require.NoError(t, err)
+	after, err := decodeValue(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, before.Ciphertext, after.Ciphertext)
+	assert.Equal(t, []int{dataKeySize, dataKeySize}, inner.sizes)
+}
+
+func TestWrappedKeyErrors(t *testing.T) {
+	ctx := context.Background()
+
+	ciphertext, err := New(et.TestKey{}).Encrypt(ctx, []byte("hello"))
+	require.NoError(t, err)
+
+	k := New(&et.BadKey{Err: errors.New("oops")})
+	_, err = k.Encrypt(ctx, []byte("hello"))
+	assert.Error(t, err)
+	_, err = k.Decrypt(ctx, ciphertext)
+	assert.Error(t, err)
+	_, err = k.Rewrap(ctx, ciphertext)
+	assert.Error(t, err)
+	_, err = k.Version(ctx)
+	assert.Error(t, err)
+}
+
+// countingKey records the size of the values encrypted by the wrapped key.
+type countingKey struct {
+	encryption.Key
+	sizes []int
+}
+
+func (k *countingKey) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	k.sizes = append(k.sizes, len(plaintext))
+	return k.Key.Encrypt(ctx, plaintext)
+}
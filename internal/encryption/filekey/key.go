@@ -0,0 +1,172 @@
+package filekey
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// keyringFile is the format of the file read by NewKey.
+type keyringFile struct {
+	// Primary is the version of the key used to encrypt new values.
+	Primary string `json:"primary"`
+	// Keys maps each version of the key to its base64 encoded secret.
+	Keys map[string]string `json:"keys"`
+}
+
+// NewKey reads all versions of the key from the file configured in k. The file
+// is only read once, so a new primary version is picked up when the key is
+// recreated, i.e. on restart or when the encryption config changes.
+func NewKey(ctx context.Context, k schema.FileEncryptionKey) (*Key, error) {
+	contents, err := os.ReadFile(k.Filepath)
+	if err != nil {
+		return nil, errors.Errorf("error reading keyring file for %q: %v", k.Keyname, err)
+	}
+
+	var f keyringFile
+	if err := json.Unmarshal(contents, &f); err != nil {
+		return nil, errors.Errorf("error parsing keyring file for %q: %v", k.Keyname, err)
+	}
+	if f.Primary == "" {
+		return nil, errors.Errorf("keyring file for %q has no primary version", k.Keyname)
+	}
+
+	secrets := make(map[string][]byte, len(f.Keys))
+	for version, encoded := range f.Keys {
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.Errorf("error decoding version %q of %q: %v", version, k.Keyname, err)
+		}
+		if len(secret) != 32 {
+			return nil, errors.Errorf("invalid length of version %q of %q: %d, expected 32 bytes", version, k.Keyname, len(secret))
+		}
+		secrets[version] = secret
+	}
+	if _, ok := secrets[f.Primary]; !ok {
+		return nil, errors.Errorf("primary version %q of %q not found in keyring file", f.Primary, k.Keyname)
+	}
+
+	return &Key{
+		keyname: k.Keyname,
+		primary: f.Primary,
+		secrets: secrets,
+	}, nil
+}
+
+// Key is an encryption.Key implementation that uses AES GCM encryption, using
+// the primary version of a set of secrets loaded from a file. Values can be
+// decrypted with any version in the file, which allows for the key to be rotated
+// while existing values are re-encrypted in the background.
+type Key struct {
+	keyname string
+	primary string
+	secrets map[string][]byte
+}
+
+var _ encryption.Key = &Key{}
+
+func (k *Key) Version(ctx context.Context) (encryption.KeyVersion, error) {
+	return encryption.KeyVersion{
+		Type:    "file",
+		Name:    k.keyname,
+		Version: k.primary,
+	}, nil
+}
+
+func (k *Key) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(k.secrets[k.primary])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := encryptedValue{
+		KeyName:    k.keyname,
+		Version:    k.primary,
+		Ciphertext: gcm.Seal(nonce, nonce, plaintext, nil),
+		Checksum:   crc32Sum(plaintext),
+	}
+	jsonKey, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(jsonKey)), nil
+}
+
+func (k *Key) Decrypt(ctx context.Context, ciphertext []byte) (*encryption.Secret, error) {
+	buf, err := base64.StdEncoding.DecodeString(string(ciphertext))
+	if err != nil {
+		return nil, err
+	}
+	// unmarshal the encrypted value into encryptedValue, this struct contains the raw
+	// ciphertext, the key name and version, and a crc32 checksum
+	ev := encryptedValue{}
+	if err := json.Unmarshal(buf, &ev); err != nil {
+		return nil, err
+	}
+	if ev.KeyName != k.keyname {
+		return nil, errors.New("invalid key name, are you trying to decrypt something with the wrong key?")
+	}
+	secret, ok := k.secrets[ev.Version]
+	if !ok {
+		return nil, errors.Errorf("version %q of key %q not found in keyring file", ev.Version, k.keyname)
+	}
+
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(ev.Ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("malformed ciphertext")
+	}
+	plaintext, err := gcm.Open(nil, ev.Ciphertext[:gcm.NonceSize()], ev.Ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if crc32Sum(plaintext) != ev.Checksum {
+		return nil, errors.New("invalid checksum, either the wrong key was used, or the request was corrupted in transit")
+	}
+	s := encryption.NewSecret(string(plaintext))
+	return &s, nil
+}
+
+type encryptedValue struct {
+	KeyName    string
+	Version    string
+	Ciphertext []byte
+	Checksum   uint32
+}
+
+func newGCM(secret []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AES cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating GCM block cipher")
+	}
+	return gcm, nil
+}
+
+func crc32Sum(data []byte) uint32 {
+	t := crc32.MakeTable(crc32.Castagnoli)
+	return crc32.Checksum(data, t)
+}
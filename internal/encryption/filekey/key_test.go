@@ -0,0 +1,101 @@
+package filekey
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestRotation(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	config := schema.FileEncryptionKey{Type: "file", Keyname: "testkey", Filepath: path}
+
+	v1, v2 := newSecret(), newSecret()
+	writeKeyring(t, path, "v1", map[string]string{"v1": v1})
+
+	k1, err := NewKey(ctx, config)
+	require.NoError(t, err)
+
+	version, err := k1.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", version.Version)
+
+	ciphertext, err := k1.Encrypt(ctx, []byte("hello"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "hello")
+
+	// Rotate the key: values encrypted with the previous version can still be
+	// decrypted, and new values are encrypted with the new primary version.
+	writeKeyring(t, path, "v2", map[string]string{"v1": v1, "v2": v2})
+
+	k2, err := NewKey(ctx, config)
+	require.NoError(t, err)
+
+	version, err = k2.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", version.Version)
+
+	secret, err := k2.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", secret.Secret())
+
+	reencrypted, err := k2.Encrypt(ctx, []byte("hello"))
+	require.NoError(t, err)
+
+	// Once the previous version is removed, only values re-encrypted with the
+	// new version can be decrypted.
+	writeKeyring(t, path, "v2", map[string]string{"v2": v2})
+
+	k3, err := NewKey(ctx, config)
+	require.NoError(t, err)
+
+	_, err = k3.Decrypt(ctx, ciphertext)
+	assert.Error(t, err)
+
+	secret, err = k3.Decrypt(ctx, reencrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", secret.Secret())
+}
+
+func TestNewKeyErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	config := schema.FileEncryptionKey{Type: "file", Keyname: "testkey", Filepath: path}
+
+	for name, contents := range map[string]string{
+		"malformed":       `{`,
+		"no primary":      fmt.Sprintf(`{"keys": {"v1": %q}}`, newSecret()),
+		"missing primary": fmt.Sprintf(`{"primary": "v2", "keys": {"v1": %q}}`, newSecret()),
+		"short secret":    fmt.Sprintf(`{"primary": "v1", "keys": {"v1": %q}}`, base64.StdEncoding.EncodeToString([]byte("short"))),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+
+			if _, err := NewKey(context.Background(), config); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func newSecret() string {
+	return base64.StdEncoding.EncodeToString([]byte(rand.String(32)))
+}
+
+func writeKeyring(t *testing.T, path, primary string, keys map[string]string) {
+	t.Helper()
+
+	contents, err := json.Marshal(keyringFile{Primary: primary, Keys: keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, contents, 0600))
+}
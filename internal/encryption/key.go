@@ -37,6 +37,28 @@ type Decrypter interface {
 	Decrypt(ctx context.Context, cipherText []byte) (*Secret, error)
 }
 
+// Rewrapper is implemented by keys that can re-encrypt a value encrypted with a
+// previous version of the key without re-encrypting the value itself, such as
+// envelope keys, which only need to re-encrypt the data key of the value.
+type Rewrapper interface {
+	Rewrap(ctx context.Context, cipherText []byte) ([]byte, error)
+}
+
+// Rewrap re-encrypts a value encrypted with any version of the given key with its
+// current version. Keys implementing Rewrapper re-encrypt the value their own way,
+// all others decrypt and encrypt the value again.
+func Rewrap(ctx context.Context, key Key, cipherText []byte) ([]byte, error) {
+	if r, ok := key.(Rewrapper); ok {
+		return r.Rewrap(ctx, cipherText)
+	}
+
+	secret, err := key.Decrypt(ctx, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	return key.Encrypt(ctx, []byte(secret.Secret()))
+}
+
 func NewSecret(v string) Secret {
 	return Secret{
 		value: v,
@@ -12,7 +12,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/encryption/awskms"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/cache"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/cloudkms"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/envelope"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/filekey"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/mounted"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/vaulttransit"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -130,6 +133,18 @@ func NewKey(ctx context.Context, k *schema.EncryptionKey, config *schema.Encrypt
 		key, err = awskms.NewKey(ctx, *k.Awskms)
 	case k.Mounted != nil:
 		key, err = mounted.NewKey(ctx, *k.Mounted)
+	case k.File != nil:
+		key, err = filekey.NewKey(ctx, *k.File)
+	case k.Vaulttransit != nil:
+		key, err = vaulttransit.NewKey(ctx, *k.Vaulttransit)
+	case k.Envelope != nil:
+		// The wrapped key only ever decrypts data keys, which are unique per
+		// value, so there's no point in caching it.
+		var wrapped encryption.Key
+		wrapped, err = NewKey(ctx, &k.Envelope.Key, &schema.EncryptionKeys{})
+		if err == nil {
+			key = envelope.New(wrapped)
+		}
 	case k.Noop != nil:
 		key = &encryption.NoopKey{}
 	default:
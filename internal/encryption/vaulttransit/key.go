@@ -0,0 +1,194 @@
+package vaulttransit
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const defaultMountPath = "transit"
+
+func NewKey(ctx context.Context, config schema.VaultTransitEncryptionKey) (*Key, error) {
+	token, err := readToken(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Responses must never be cached, so we don't use the shared external
+	// client here.
+	cli, err := httpcli.NewFactory(
+		httpcli.NewMiddleware(httpcli.ContextErrorMiddleware),
+		httpcli.NewTimeoutOpt(30*time.Second),
+		httpcli.ExternalTransportOpt,
+		httpcli.TracedTransportOpt,
+	).Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	return newKey(ctx, config, token, cli)
+}
+
+func newKey(ctx context.Context, config schema.VaultTransitEncryptionKey, token string, cli httpcli.Doer) (*Key, error) {
+	mountPath := strings.Trim(config.MountPath, "/")
+	if mountPath == "" {
+		mountPath = defaultMountPath
+	}
+
+	k := &Key{
+		address:   strings.TrimSuffix(config.Address, "/"),
+		mountPath: mountPath,
+		keyname:   config.Keyname,
+		namespace: config.Namespace,
+		token:     token,
+		client:    cli,
+	}
+	// Test client connection.
+	_, err := k.Version(ctx)
+	return k, err
+}
+
+func readToken(config schema.VaultTransitEncryptionKey) (string, error) {
+	if config.TokenEnvVarName != "" && config.TokenFilepath == "" {
+		return os.Getenv(config.TokenEnvVarName), nil
+	}
+	if config.TokenFilepath != "" && config.TokenEnvVarName == "" {
+		token, err := os.ReadFile(config.TokenFilepath)
+		if err != nil {
+			return "", errors.Errorf("error reading token file for %q: %v", config.Keyname, err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+
+	// Either the user has set none of TokenEnvVarName or TokenFilepath or both in their config. Either way we return an error.
+	return "", errors.Errorf(
+		"must use only one of TokenEnvVarName and TokenFilepath, TokenEnvVarName: %q, TokenFilepath: %q",
+		config.TokenEnvVarName, config.TokenFilepath,
+	)
+}
+
+// Key is an encryption.Key implementation that uses a named key of the transit
+// secrets engine of HashiCorp Vault (or of any service implementing the same HTTP
+// API). The ciphertext returned by Vault identifies the version of the key it
+// was encrypted with, so values encrypted before the key was rotated in Vault
+// can still be decrypted.
+type Key struct {
+	address   string
+	mountPath string
+	keyname   string
+	namespace string
+	token     string
+	client    httpcli.Doer
+}
+
+var _ encryption.Key = &Key{}
+
+// Version returns the latest version of the key in Vault, which is the version
+// new values are encrypted with.
+func (k *Key) Version(ctx context.Context) (encryption.KeyVersion, error) {
+	var data struct {
+		LatestVersion int `json:"latest_version"`
+	}
+	if err := k.do(ctx, http.MethodGet, "keys", nil, &data); err != nil {
+		return encryption.KeyVersion{}, errors.Wrap(err, "reading key")
+	}
+
+	return encryption.KeyVersion{
+		Type:    "vaulttransit",
+		Name:    k.keyname,
+		Version: strconv.Itoa(data.LatestVersion),
+	}, nil
+}
+
+func (k *Key) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	req := struct {
+		Plaintext string `json:"plaintext"`
+	}{
+		Plaintext: base64.StdEncoding.EncodeToString(plaintext),
+	}
+	var data struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := k.do(ctx, http.MethodPost, "encrypt", req, &data); err != nil {
+		return nil, errors.Wrap(err, "encrypting")
+	}
+	return []byte(data.Ciphertext), nil
+}
+
+func (k *Key) Decrypt(ctx context.Context, ciphertext []byte) (*encryption.Secret, error) {
+	req := struct {
+		Ciphertext string `json:"ciphertext"`
+	}{
+		Ciphertext: string(ciphertext),
+	}
+	var data struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := k.do(ctx, http.MethodPost, "decrypt", req, &data); err != nil {
+		return nil, errors.Wrap(err, "decrypting")
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(data.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	s := encryption.NewSecret(string(plaintext))
+	return &s, nil
+}
+
+// do sends a request to the {action} endpoint of the key and decodes the data
+// field of the response into result.
+func (k *Key) do(ctx context.Context, method, action string, body, result interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", k.address, k.mountPath, action, k.keyname)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", k.token)
+	if k.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", k.namespace)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return errors.Errorf("unexpected status code %d from Vault: %s", resp.StatusCode, strings.Join(errResp.Errors, "; "))
+	}
+
+	payloadResp := struct {
+		Data interface{} `json:"data"`
+	}{
+		Data: result,
+	}
+	return json.NewDecoder(resp.Body).Decode(&payloadResp)
+}
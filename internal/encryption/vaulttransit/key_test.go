@@ -0,0 +1,89 @@
+package vaulttransit
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption/vaulttransit/vaulttest"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	server := vaulttest.NewServer(t, "s3cr3t")
+	server.Rotate("testkey")
+
+	k, err := newKey(ctx, schema.VaultTransitEncryptionKey{
+		Type:      "vaulttransit",
+		Address:   server.URL + "/",
+		Keyname:   "testkey",
+		Namespace: "ns1",
+	}, "s3cr3t", http.DefaultClient)
+	require.NoError(t, err)
+
+	version, err := k.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1", version.Version)
+
+	ciphertext, err := k.Encrypt(ctx, []byte("hello"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "hello")
+
+	// Rotating the key in Vault changes the version new values are encrypted
+	// with, but values encrypted with previous versions can still be decrypted.
+	server.Rotate("testkey")
+
+	version, err = k.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2", version.Version)
+
+	secret, err := k.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", secret.Secret())
+
+	reencrypted, err := k.Encrypt(ctx, []byte("hello"))
+	require.NoError(t, err)
+	assert.Contains(t, string(reencrypted), "vault:v2:")
+
+	if _, err := k.Decrypt(ctx, []byte("vault:v1:bm90IGVuY3J5cHRlZA==")); err == nil {
+		t.Error("expected error decrypting invalid ciphertext")
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	ctx := context.Background()
+	server := vaulttest.NewServer(t, "s3cr3t")
+	server.Rotate("testkey")
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0600))
+
+	t.Run("token file", func(t *testing.T) {
+		config := schema.VaultTransitEncryptionKey{Address: server.URL, Keyname: "testkey", TokenFilepath: tokenFile}
+		token, err := readToken(config)
+		require.NoError(t, err)
+		_, err = newKey(ctx, config, token, http.DefaultClient)
+		assert.NoError(t, err)
+	})
+
+	t.Run("token file and env var", func(t *testing.T) {
+		_, err := NewKey(ctx, schema.VaultTransitEncryptionKey{Address: server.URL, Keyname: "testkey", TokenFilepath: tokenFile, TokenEnvVarName: "VAULT_TOKEN"})
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		_, err := newKey(ctx, schema.VaultTransitEncryptionKey{Address: server.URL, Keyname: "testkey"}, "wrong", http.DefaultClient)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := newKey(ctx, schema.VaultTransitEncryptionKey{Address: server.URL, Keyname: "unknown"}, "s3cr3t", http.DefaultClient)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,185 @@
+// Package vaulttest provides a local stand-in for the transit secrets engine of
+// a HashiCorp Vault server, for use in tests.
+package vaulttest
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Server implements the subset of the Vault transit API used by
+// vaulttransit.Key: encrypting, decrypting, reading and rotating keys. Keys are
+// created on first use, like with the default policy of Vault, and are shared
+// between all mount paths and namespaces.
+type Server struct {
+	*httptest.Server
+
+	// Token is the token that requests must be authenticated with.
+	Token string
+
+	mu   sync.Mutex
+	keys map[string][]cipher.AEAD // version N of a key is at index N-1
+}
+
+// NewServer starts a new server that is closed when the test finishes.
+func NewServer(t testing.TB, token string) *Server {
+	s := &Server{Token: token, keys: map[string][]cipher.AEAD{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Rotate adds a new version of the given key, creating it if needed, and
+// returns the new latest version.
+func (s *Server) Rotate(keyname string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[keyname] = append(s.keys[keyname], newAEAD())
+	return len(s.keys[keyname])
+}
+
+var pathPattern = regexp.MustCompile(`^/v1/.+/(encrypt|decrypt|keys)/([^/]+)(/rotate)?$`)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != s.Token {
+		writeError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	match := pathPattern.FindStringSubmatch(r.URL.Path)
+	if match == nil {
+		writeError(w, http.StatusNotFound, "unsupported path")
+		return
+	}
+	action, keyname, rotate := match[1], match[2], match[3] != ""
+
+	switch {
+	case action == "keys" && rotate && r.Method == http.MethodPost:
+		s.Rotate(keyname)
+		w.WriteHeader(http.StatusNoContent)
+
+	case action == "keys" && !rotate && r.Method == http.MethodGet:
+		versions, ok := s.versions(keyname)
+		if !ok {
+			writeError(w, http.StatusNotFound, "key not found")
+			return
+		}
+		writeData(w, map[string]interface{}{"name": keyname, "type": "aes256-gcm96", "latest_version": len(versions)})
+
+	case action == "encrypt" && r.Method == http.MethodPost:
+		var req struct {
+			Plaintext string `json:"plaintext"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		plaintext, err := base64.StdEncoding.DecodeString(req.Plaintext)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "failed to base64-decode plaintext")
+			return
+		}
+
+		versions, ok := s.versions(keyname)
+		if !ok {
+			s.Rotate(keyname)
+			versions, _ = s.versions(keyname)
+		}
+		aead := versions[len(versions)-1]
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ciphertext := fmt.Sprintf("vault:v%d:%s", len(versions), base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)))
+		writeData(w, map[string]interface{}{"ciphertext": ciphertext, "key_version": len(versions)})
+
+	case action == "decrypt" && r.Method == http.MethodPost:
+		var req struct {
+			Ciphertext string `json:"ciphertext"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		versions, ok := s.versions(keyname)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "encryption key not found")
+			return
+		}
+		parts := strings.SplitN(req.Ciphertext, ":", 3)
+		if len(parts) != 3 || parts[0] != "vault" || !strings.HasPrefix(parts[1], "v") {
+			writeError(w, http.StatusBadRequest, "invalid ciphertext: no prefix")
+			return
+		}
+		version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+		if err != nil || version < 1 || version > len(versions) {
+			writeError(w, http.StatusBadRequest, "invalid key version")
+			return
+		}
+		sealed, err := base64.StdEncoding.DecodeString(parts[2])
+		aead := versions[version-1]
+		if err != nil || len(sealed) < aead.NonceSize() {
+			writeError(w, http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "cipher: message authentication failed")
+			return
+		}
+		writeData(w, map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString(plaintext)})
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "unsupported operation")
+	}
+}
+
+func (s *Server) versions(keyname string) ([]cipher.AEAD, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, ok := s.keys[keyname]
+	return versions, ok
+}
+
+func newAEAD() cipher.AEAD {
+	secret := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+		panic(err)
+	}
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		panic(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return aead
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{message}})
+}
@@ -0,0 +1,94 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// WorkspacePermissionOwner is the permission held by the owners (admins) of a
+// workspace. Only owners can read the permissions of other members.
+const WorkspacePermissionOwner = "owner"
+
+// Workspace is a Bitbucket Cloud workspace, which owns repositories.
+type Workspace struct {
+	UUID string `json:"uuid"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// WorkspacePermission is the membership of a user in a workspace.
+type WorkspacePermission struct {
+	Permission string     `json:"permission"`
+	User       *Account   `json:"user"`
+	Workspace  *Workspace `json:"workspace"`
+}
+
+// RepoPermission is the permission a user has on a repository, either granted
+// directly or inherited from a group.
+type RepoPermission struct {
+	Permission string   `json:"permission"`
+	User       *Account `json:"user"`
+	Repo       *Repo    `json:"repository"`
+}
+
+// CurrentUserWorkspacePermissions returns the workspaces the authenticated user
+// is a member of, along with the permission they hold in each.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-user-permissions-workspaces-get
+func (c *Client) CurrentUserWorkspacePermissions(ctx context.Context, pageToken *PageToken) ([]*WorkspacePermission, *PageToken, error) {
+	var perms []*WorkspacePermission
+	next, err := c.pageOrNext(ctx, "/2.0/user/permissions/workspaces", nil, pageToken, &perms)
+	return perms, next, err
+}
+
+// WorkspacePermissions returns the members of the given workspace matching the
+// query, which is a Bitbucket Cloud filter expression such as
+// `user.nickname="alice"`. An empty query matches all members.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-workspaces-workspace-permissions-get
+func (c *Client) WorkspacePermissions(ctx context.Context, pageToken *PageToken, workspace, query string) ([]*WorkspacePermission, *PageToken, error) {
+	var perms []*WorkspacePermission
+	path := fmt.Sprintf("/2.0/workspaces/%s/permissions", workspace)
+	next, err := c.pageOrNext(ctx, path, filterQuery(query), pageToken, &perms)
+	return perms, next, err
+}
+
+// RepoPermissions returns the repository permissions in the given workspace
+// matching the query, which is a Bitbucket Cloud filter expression such as
+// `user.uuid="{...}"`. An empty query matches all permissions.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-workspaces-workspace-permissions-repositories-get
+func (c *Client) RepoPermissions(ctx context.Context, pageToken *PageToken, workspace, query string) ([]*RepoPermission, *PageToken, error) {
+	var perms []*RepoPermission
+	path := fmt.Sprintf("/2.0/workspaces/%s/permissions/repositories", workspace)
+	next, err := c.pageOrNext(ctx, path, filterQuery(query), pageToken, &perms)
+	return perms, next, err
+}
+
+// RepoUserPermissions returns the permissions of all users with access to the
+// repository with the given slug in the workspace.
+//
+// API docs: https://developer.atlassian.com/cloud/bitbucket/rest/api-group-workspaces/#api-workspaces-workspace-permissions-repositories-repo-slug-get
+func (c *Client) RepoUserPermissions(ctx context.Context, pageToken *PageToken, workspace, slug string) ([]*RepoPermission, *PageToken, error) {
+	var perms []*RepoPermission
+	path := fmt.Sprintf("/2.0/workspaces/%s/permissions/repositories/%s", workspace, slug)
+	next, err := c.pageOrNext(ctx, path, nil, pageToken, &perms)
+	return perms, next, err
+}
+
+// pageOrNext requests the next page of the given token if there is one, and
+// the first page of the path otherwise.
+func (c *Client) pageOrNext(ctx context.Context, path string, qry url.Values, pageToken *PageToken, results interface{}) (*PageToken, error) {
+	if pageToken.HasMore() {
+		return c.reqPage(ctx, pageToken.Next, results)
+	}
+	return c.page(ctx, path, qry, pageToken, results)
+}
+
+func filterQuery(query string) url.Values {
+	if query == "" {
+		return nil
+	}
+	return url.Values{"q": []string{query}}
+}
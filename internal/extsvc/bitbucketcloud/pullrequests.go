@@ -0,0 +1,425 @@
+package bitbucketcloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// PullRequestState is the state of a Bitbucket Cloud pull request.
+type PullRequestState string
+
+const (
+	PullRequestStateOpen       PullRequestState = "OPEN"
+	PullRequestStateMerged     PullRequestState = "MERGED"
+	PullRequestStateDeclined   PullRequestState = "DECLINED"
+	PullRequestStateSuperseded PullRequestState = "SUPERSEDED"
+)
+
+// PullRequest is a Bitbucket Cloud pull request.
+type PullRequest struct {
+	ID                int64               `json:"id"`
+	Title             string              `json:"title"`
+	Description       string              `json:"description"`
+	State             PullRequestState    `json:"state"`
+	Author            Account             `json:"author"`
+	Source            PullRequestEndpoint `json:"source"`
+	Destination       PullRequestEndpoint `json:"destination"`
+	MergeCommit       *Commit             `json:"merge_commit,omitempty"`
+	Participants      []Participant       `json:"participants"`
+	Reviewers         []Account           `json:"reviewers"`
+	CloseSourceBranch bool                `json:"close_source_branch"`
+	ClosedBy          *Account            `json:"closed_by,omitempty"`
+	Reason            string              `json:"reason,omitempty"`
+	CommentCount      int64               `json:"comment_count"`
+	TaskCount         int64               `json:"task_count"`
+	CreatedOn         time.Time           `json:"created_on"`
+	UpdatedOn         time.Time           `json:"updated_on"`
+	Links             PullRequestLinks    `json:"links"`
+
+	// Statuses is not returned by the pull request endpoints, and has to be
+	// populated separately with GetPullRequestStatuses.
+	Statuses []*PullRequestStatus `json:"statuses,omitempty"`
+}
+
+// PullRequestLinks are the links returned for a pull request.
+type PullRequestLinks struct {
+	HTML Link `json:"html"`
+}
+
+// PullRequestEndpoint is the source or destination of a pull request.
+type PullRequestEndpoint struct {
+	Repo   RepoRef `json:"repository"`
+	Branch Branch  `json:"branch"`
+	Commit *Commit `json:"commit,omitempty"`
+}
+
+// RepoRef is the abbreviated form of a repository embedded in other objects.
+type RepoRef struct {
+	FullName string `json:"full_name"`
+	Name     string `json:"name"`
+	UUID     string `json:"uuid"`
+}
+
+// Branch is a branch reference.
+type Branch struct {
+	Name string `json:"name"`
+}
+
+// Commit is a commit reference. Note that Bitbucket Cloud usually returns
+// abbreviated hashes in pull request endpoints.
+type Commit struct {
+	Hash string `json:"hash"`
+}
+
+// Account is a Bitbucket Cloud user or team.
+type Account struct {
+	AccountID   string `json:"account_id"`
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+	UUID        string `json:"uuid"`
+}
+
+// ParticipantRole is the role a participant has on a pull request.
+type ParticipantRole string
+
+const (
+	ParticipantRoleParticipant ParticipantRole = "PARTICIPANT"
+	ParticipantRoleReviewer    ParticipantRole = "REVIEWER"
+)
+
+// ParticipantState is the review state of a participant.
+type ParticipantState string
+
+const (
+	ParticipantStateApproved         ParticipantState = "approved"
+	ParticipantStateChangesRequested ParticipantState = "changes_requested"
+	ParticipantStateNull             ParticipantState = ""
+)
+
+// Participant is a user that has participated in a pull request.
+type Participant struct {
+	User           Account          `json:"user"`
+	Role           ParticipantRole  `json:"role"`
+	Approved       bool             `json:"approved"`
+	State          ParticipantState `json:"state"`
+	ParticipatedOn *time.Time       `json:"participated_on,omitempty"`
+}
+
+// PullRequestStatusState is the state of a commit status.
+type PullRequestStatusState string
+
+const (
+	PullRequestStatusStateSuccessful PullRequestStatusState = "SUCCESSFUL"
+	PullRequestStatusStateFailed     PullRequestStatusState = "FAILED"
+	PullRequestStatusStateInProgress PullRequestStatusState = "INPROGRESS"
+	PullRequestStatusStateStopped    PullRequestStatusState = "STOPPED"
+)
+
+// PullRequestStatus is a commit status, as returned for the commits of a pull
+// request or within a commit status webhook payload.
+type PullRequestStatus struct {
+	UUID        string                 `json:"uuid"`
+	Key         string                 `json:"key"`
+	RefName     string                 `json:"refname"`
+	URL         string                 `json:"url"`
+	State       PullRequestStatusState `json:"state"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Commit      *Commit                `json:"commit,omitempty"`
+	CreatedOn   time.Time              `json:"created_on"`
+	UpdatedOn   time.Time              `json:"updated_on"`
+}
+
+// PullRequestInput is the input used to create or update a pull request.
+type PullRequestInput struct {
+	Title             string
+	Description       string
+	SourceBranch      string
+	DestinationBranch string
+
+	// SourceRepo is only required if the pull request is opened from a fork.
+	SourceRepo *Repo
+}
+
+func (input *PullRequestInput) MarshalJSON() ([]byte, error) {
+	type branch struct {
+		Name string `json:"name"`
+	}
+
+	type repository struct {
+		FullName string `json:"full_name"`
+	}
+
+	type source struct {
+		Branch     branch      `json:"branch"`
+		Repository *repository `json:"repository,omitempty"`
+	}
+
+	type request struct {
+		Title       string  `json:"title"`
+		Description string  `json:"description,omitempty"`
+		Source      source  `json:"source"`
+		Destination *source `json:"destination,omitempty"`
+	}
+
+	req := request{
+		Title:       input.Title,
+		Description: input.Description,
+		Source: source{
+			Branch: branch{Name: input.SourceBranch},
+		},
+	}
+	if input.SourceRepo != nil {
+		req.Source.Repository = &repository{FullName: input.SourceRepo.FullName}
+	}
+	if input.DestinationBranch != "" {
+		req.Destination = &source{Branch: branch{Name: input.DestinationBranch}}
+	}
+
+	return json.Marshal(&req)
+}
+
+// CreatePullRequest opens a new pull request in the given repository.
+//
+// Note that Bitbucket Cloud silently updates and returns the existing pull
+// request if one already exists for the same source and destination branches.
+func (c *Client) CreatePullRequest(ctx context.Context, repo *Repo, input PullRequestInput) (*PullRequest, error) {
+	data, err := json.Marshal(&input)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling request")
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("/2.0/repositories/%s/pullrequests", repo.FullName), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+
+	return &pr, nil
+}
+
+// GetPullRequest retrieves a single pull request.
+func (c *Client) GetPullRequest(ctx context.Context, repo *Repo, id int64) (*PullRequest, error) {
+	req, err := http.NewRequest("GET", pullRequestPath(repo, id), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+
+	return &pr, nil
+}
+
+// GetPullRequestStatuses retrieves all commit statuses attached to the
+// commits of the given pull request.
+func (c *Client) GetPullRequestStatuses(ctx context.Context, repo *Repo, id int64) ([]*PullRequestStatus, error) {
+	var statuses []*PullRequestStatus
+
+	var page []*PullRequestStatus
+	next, err := c.page(ctx, pullRequestPath(repo, id)+"/statuses", nil, nil, &page)
+	for {
+		if err != nil {
+			return nil, errors.Wrap(err, "getting pull request statuses")
+		}
+		statuses = append(statuses, page...)
+		if !next.HasMore() {
+			return statuses, nil
+		}
+
+		page = nil
+		next, err = c.reqPage(ctx, next.Next, &page)
+	}
+}
+
+// UpdatePullRequest updates the title, description and destination branch of
+// the given pull request.
+func (c *Client) UpdatePullRequest(ctx context.Context, repo *Repo, id int64, input PullRequestInput) (*PullRequest, error) {
+	data, err := json.Marshal(&input)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling request")
+	}
+
+	req, err := http.NewRequest("PUT", pullRequestPath(repo, id), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	var updated PullRequest
+	if err := c.do(ctx, req, &updated); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+
+	return &updated, nil
+}
+
+// DeclinePullRequest declines (closes without merging) the given pull
+// request. Declined pull requests cannot be reopened.
+func (c *Client) DeclinePullRequest(ctx context.Context, repo *Repo, id int64) (*PullRequest, error) {
+	req, err := http.NewRequest("POST", pullRequestPath(repo, id)+"/decline", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+
+	return &pr, nil
+}
+
+// CommentInput is the input for a new pull request comment.
+type CommentInput struct {
+	Content string
+}
+
+func (input *CommentInput) MarshalJSON() ([]byte, error) {
+	type content struct {
+		Raw string `json:"raw"`
+	}
+	return json.Marshal(&struct {
+		Content content `json:"content"`
+	}{Content: content{Raw: input.Content}})
+}
+
+// CreatePullRequestComment adds a comment to the given pull request.
+func (c *Client) CreatePullRequestComment(ctx context.Context, repo *Repo, id int64, input CommentInput) error {
+	data, err := json.Marshal(&input)
+	if err != nil {
+		return errors.Wrap(err, "marshalling request")
+	}
+
+	req, err := http.NewRequest("POST", pullRequestPath(repo, id)+"/comments", bytes.NewBuffer(data))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+
+	if err := c.do(ctx, req, nil); err != nil {
+		return errors.Wrap(err, "sending request")
+	}
+
+	return nil
+}
+
+// MergeStrategy is the strategy used to merge a pull request.
+type MergeStrategy string
+
+const (
+	MergeStrategyMergeCommit MergeStrategy = "merge_commit"
+	MergeStrategySquash      MergeStrategy = "squash"
+	MergeStrategyFastForward MergeStrategy = "fast_forward"
+)
+
+// MergePullRequestOpts are the options available when merging a pull request.
+type MergePullRequestOpts struct {
+	Message           *string        `json:"message,omitempty"`
+	CloseSourceBranch *bool          `json:"close_source_branch,omitempty"`
+	MergeStrategy     *MergeStrategy `json:"merge_strategy,omitempty"`
+}
+
+// MergePullRequest merges the given pull request.
+func (c *Client) MergePullRequest(ctx context.Context, repo *Repo, id int64, opts MergePullRequestOpts) (*PullRequest, error) {
+	data, err := json.Marshal(&opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling request")
+	}
+
+	req, err := http.NewRequest("POST", pullRequestPath(repo, id)+"/merge", bytes.NewBuffer(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	var pr PullRequest
+	if err := c.do(ctx, req, &pr); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+
+	return &pr, nil
+}
+
+// Repo retrieves a single repository by its full name, in the form
+// "workspace/slug".
+func (c *Client) Repo(ctx context.Context, fullName string) (*Repo, error) {
+	req, err := http.NewRequest("GET", "/2.0/repositories/"+fullName, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	var repo Repo
+	if err := c.do(ctx, req, &repo); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+
+	return &repo, nil
+}
+
+// CurrentUser returns the account that the client is authenticated as.
+func (c *Client) CurrentUser(ctx context.Context) (*Account, error) {
+	req, err := http.NewRequest("GET", "/2.0/user", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	var account Account
+	if err := c.do(ctx, req, &account); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+
+	return &account, nil
+}
+
+// WithCredentials returns a copy of the client that authenticates with the
+// given username and app password.
+func (c *Client) WithCredentials(username, appPassword string) *Client {
+	return &Client{
+		httpClient:  c.httpClient,
+		URL:         c.URL,
+		Username:    username,
+		AppPassword: appPassword,
+		RateLimit:   c.RateLimit,
+	}
+}
+
+func pullRequestPath(repo *Repo, id int64) string {
+	return fmt.Sprintf("/2.0/repositories/%s/pullrequests/%d", repo.FullName, id)
+}
+
+// IsNotFound reports whether err is a Bitbucket Cloud API not found error.
+func IsNotFound(err error) bool {
+	return errcode.IsNotFound(err)
+}
+
+// IsUnauthorized reports whether err is a Bitbucket Cloud API 401 error.
+func IsUnauthorized(err error) bool {
+	return errcode.IsUnauthorized(err)
+}
+
+// IsNotMergeable reports whether err is the error returned by
+// MergePullRequest when the pull request cannot be merged, such as when it has
+// conflicts or doesn't satisfy the repository's merge checks.
+func IsNotMergeable(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && e.StatusCode == http.StatusBadRequest
+}
+
+// HasHashPrefix reports whether the (possibly abbreviated) hash of the commit
+// matches the given full commit hash.
+func (c *Commit) HasHashPrefix(hash string) bool {
+	return c != nil && c.Hash != "" && strings.HasPrefix(hash, c.Hash)
+}
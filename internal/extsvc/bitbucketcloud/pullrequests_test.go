@@ -0,0 +1,148 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newPullRequestTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewClient(u, srv.Client()).WithCredentials("user", "app-password")
+}
+
+func TestClient_CreatePullRequest(t *testing.T) {
+	repo := &Repo{FullName: "sglocal/mux"}
+
+	var body map[string]interface{}
+	cli := newPullRequestTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if have, want := r.Method+" "+r.URL.Path, "POST /2.0/repositories/sglocal/mux/pullrequests"; have != want {
+			t.Errorf("unexpected request: have %q, want %q", have, want)
+		}
+		if user, pass, _ := r.BasicAuth(); user != "user" || pass != "app-password" {
+			t.Errorf("unexpected credentials: %q:%q", user, pass)
+		}
+
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Fatal(err)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":42,"title":"Title","state":"OPEN","source":{"branch":{"name":"feature"},"commit":{"hash":"abcdef123456"}},"destination":{"branch":{"name":"main"}}}`)
+	})
+
+	pr, err := cli.CreatePullRequest(context.Background(), repo, PullRequestInput{
+		Title:             "Title",
+		Description:       "Body",
+		SourceBranch:      "feature",
+		DestinationBranch: "main",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantBody := map[string]interface{}{
+		"title":       "Title",
+		"description": "Body",
+		"source":      map[string]interface{}{"branch": map[string]interface{}{"name": "feature"}},
+		"destination": map[string]interface{}{"branch": map[string]interface{}{"name": "main"}},
+	}
+	if diff := cmp.Diff(wantBody, body); diff != "" {
+		t.Errorf("unexpected request body (-want +have):\n%s", diff)
+	}
+
+	if pr.ID != 42 || pr.State != PullRequestStateOpen || pr.Source.Branch.Name != "feature" {
+		t.Errorf("unexpected pull request: %+v", pr)
+	}
+	if !pr.Source.Commit.HasHashPrefix("abcdef1234567890abcdef1234567890abcdef12") {
+		t.Errorf("expected source commit to match full hash")
+	}
+}
+
+func TestClient_GetPullRequestStatuses(t *testing.T) {
+	repo := &Repo{FullName: "sglocal/mux"}
+
+	var srvURL string
+	cli := newPullRequestTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2.0/repositories/sglocal/mux/pullrequests/42/statuses" {
+			t.Errorf("unexpected path: %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{"values":[{"key":"b","state":"FAILED"}]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"values":[{"key":"a","state":"SUCCESSFUL"}],"next":%q}`, srvURL+r.URL.Path+"?page=2")
+	})
+	srvURL = cli.URL.String()
+
+	statuses, err := cli.GetPullRequestStatuses(context.Background(), repo, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*PullRequestStatus{
+		{Key: "a", State: PullRequestStatusStateSuccessful},
+		{Key: "b", State: PullRequestStatusStateFailed},
+	}
+	if diff := cmp.Diff(want, statuses); diff != "" {
+		t.Errorf("unexpected statuses (-want +have):\n%s", diff)
+	}
+}
+
+func TestClient_DeclinePullRequest_NotFound(t *testing.T) {
+	repo := &Repo{FullName: "sglocal/mux"}
+
+	cli := newPullRequestTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if have, want := r.Method+" "+r.URL.Path, "POST /2.0/repositories/sglocal/mux/pullrequests/42/decline"; have != want {
+			t.Errorf("unexpected request: have %q, want %q", have, want)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := cli.DeclinePullRequest(context.Background(), repo, 42)
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	payload := []byte(`{
+		"pullrequest": {"id": 42, "state": "OPEN"},
+		"repository": {"full_name": "sglocal/mux", "uuid": "{e1e75436-05e6-4c38-8543-9c36ec26fad1}"},
+		"approval": {"date": "2021-06-01T10:00:00Z", "user": {"uuid": "{user}"}}
+	}`)
+
+	ev, err := ParseWebhookEvent("pullrequest:approved", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	approved, ok := ev.(*PullRequestApprovedEvent)
+	if !ok {
+		t.Fatalf("unexpected event type %T", ev)
+	}
+	if approved.PullRequest.ID != 42 || approved.Repository.UUID != "{e1e75436-05e6-4c38-8543-9c36ec26fad1}" || approved.Approval.User.UUID != "{user}" {
+		t.Errorf("unexpected event: %+v", approved)
+	}
+
+	if ev, err := ParseWebhookEvent("repo:push", payload); err != nil || ev != nil {
+		t.Errorf("expected unknown events to be ignored, got %v, %v", ev, err)
+	}
+}
@@ -0,0 +1,145 @@
+package bitbucketcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// EventKeyHeader is the HTTP header Bitbucket Cloud uses to identify the type
+// of a webhook event.
+const EventKeyHeader = "X-Event-Key"
+
+// WebhookEventKey returns the event key of the given webhook request.
+func WebhookEventKey(r *http.Request) string {
+	return r.Header.Get(EventKeyHeader)
+}
+
+// ParseWebhookEvent parses the webhook payload into the event type identified
+// by the given event key. Unknown event keys result in a nil event and no
+// error, so that callers can ignore events they are not interested in.
+func ParseWebhookEvent(eventKey string, payload []byte) (interface{}, error) {
+	var target interface{}
+	switch eventKey {
+	case "pullrequest:approved":
+		target = &PullRequestApprovedEvent{}
+	case "pullrequest:changes_request_created":
+		target = &PullRequestChangesRequestCreatedEvent{}
+	case "pullrequest:changes_request_removed":
+		target = &PullRequestChangesRequestRemovedEvent{}
+	case "pullrequest:comment_created":
+		target = &PullRequestCommentCreatedEvent{}
+	case "pullrequest:created":
+		target = &PullRequestCreatedEvent{}
+	case "pullrequest:fulfilled":
+		target = &PullRequestFulfilledEvent{}
+	case "pullrequest:rejected":
+		target = &PullRequestRejectedEvent{}
+	case "pullrequest:unapproved":
+		target = &PullRequestUnapprovedEvent{}
+	case "pullrequest:updated":
+		target = &PullRequestUpdatedEvent{}
+	case "repo:commit_status_created":
+		target = &RepoCommitStatusCreatedEvent{}
+	case "repo:commit_status_updated":
+		target = &RepoCommitStatusUpdatedEvent{}
+	default:
+		return nil, nil
+	}
+
+	if err := json.Unmarshal(payload, target); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling %q event", eventKey)
+	}
+	return target, nil
+}
+
+// PullRequestEvent contains the fields common to all pull request webhook
+// events.
+type PullRequestEvent struct {
+	PullRequest PullRequest `json:"pullrequest"`
+	Repository  Repo        `json:"repository"`
+	Actor       Account     `json:"actor"`
+}
+
+// Approval is an approval or change request on a pull request.
+type Approval struct {
+	Date time.Time `json:"date"`
+	User Account   `json:"user"`
+}
+
+// PullRequestApprovalEvent contains the fields common to pull request events
+// that add or remove an approval.
+type PullRequestApprovalEvent struct {
+	PullRequestEvent
+	Approval Approval `json:"approval"`
+}
+
+func (e *PullRequestApprovalEvent) Key() string {
+	return fmt.Sprintf("%d:%s:%s", e.PullRequest.ID, e.Approval.User.UUID, e.Approval.Date)
+}
+
+// PullRequestChangesRequestEvent contains the fields common to pull request
+// events that add or remove a change request.
+type PullRequestChangesRequestEvent struct {
+	PullRequestEvent
+	ChangesRequest Approval `json:"changes_request"`
+}
+
+func (e *PullRequestChangesRequestEvent) Key() string {
+	return fmt.Sprintf("%d:%s:%s", e.PullRequest.ID, e.ChangesRequest.User.UUID, e.ChangesRequest.Date)
+}
+
+type PullRequestApprovedEvent struct{ PullRequestApprovalEvent }
+type PullRequestUnapprovedEvent struct{ PullRequestApprovalEvent }
+type PullRequestChangesRequestCreatedEvent struct{ PullRequestChangesRequestEvent }
+type PullRequestChangesRequestRemovedEvent struct{ PullRequestChangesRequestEvent }
+
+// PullRequestStateEvent is sent when a pull request changes state.
+type PullRequestStateEvent struct {
+	PullRequestEvent
+}
+
+func (e *PullRequestStateEvent) Key() string {
+	return fmt.Sprintf("%d:%s:%s", e.PullRequest.ID, e.PullRequest.State, e.PullRequest.UpdatedOn)
+}
+
+type PullRequestCreatedEvent struct{ PullRequestStateEvent }
+type PullRequestFulfilledEvent struct{ PullRequestStateEvent }
+type PullRequestRejectedEvent struct{ PullRequestStateEvent }
+type PullRequestUpdatedEvent struct{ PullRequestStateEvent }
+
+// PullRequestCommentCreatedEvent is sent when a comment is added to a pull
+// request.
+type PullRequestCommentCreatedEvent struct {
+	PullRequestEvent
+	Comment struct {
+		ID int64 `json:"id"`
+	} `json:"comment"`
+}
+
+func (e *PullRequestCommentCreatedEvent) Key() string {
+	return strconv.FormatInt(e.Comment.ID, 10)
+}
+
+// RepoCommitStatusEvent is sent when a commit status is created or updated.
+// Note that it does not reference the pull requests the commit belongs to.
+type RepoCommitStatusEvent struct {
+	CommitStatus PullRequestStatus `json:"commit_status"`
+	Repository   Repo              `json:"repository"`
+	Actor        Account           `json:"actor"`
+}
+
+func (e *RepoCommitStatusEvent) Key() string {
+	var commit string
+	if e.CommitStatus.Commit != nil {
+		commit = e.CommitStatus.Commit.Hash
+	}
+	return fmt.Sprintf("%s:%s:%s:%s", commit, e.CommitStatus.Key, e.CommitStatus.State, e.CommitStatus.UpdatedOn)
+}
+
+type RepoCommitStatusCreatedEvent struct{ RepoCommitStatusEvent }
+type RepoCommitStatusUpdatedEvent struct{ RepoCommitStatusEvent }
@@ -0,0 +1,252 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("gerrit_requests_count", "Total number of requests sent to the Gerrit API.")
+
+// These fields define the self-imposed Gerrit rate limit (since Gerrit does not have a
+// concept of rate limiting in HTTP response headers).
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 2 // 120/min or 7200/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// xssiPrefix is prepended by Gerrit to every JSON response body to prevent cross-site
+// script inclusion. It must be stripped before decoding the response.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api.html#output
+var xssiPrefix = []byte(")]}'")
+
+// Client access a Gerrit instance via its REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of the Gerrit instance.
+	URL *url.URL
+
+	// The username and HTTP password credentials for accessing the server. If unset,
+	// requests are made anonymously.
+	Username, Password string
+
+	// RateLimit is the self-imposed rate limiter (since Gerrit does not have a concept
+	// of rate limiting in HTTP response headers).
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Gerrit API client with the given base URL. If a nil httpClient
+// is provided, httpcli.ExternalDoer will be used.
+func NewClient(baseURL *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer()
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The first component of the path after the optional authentication
+		// prefix maps to the type of API request we are making.
+		path := strings.TrimPrefix(strings.TrimPrefix(u.Path, baseURL.Path), "a/")
+		if i := strings.Index(path, "/"); i >= 0 {
+			path = path[:i]
+		}
+		return path
+	})
+
+	// Normally our registry will return a default infinite limiter when nothing has been
+	// synced from config. However, we always want to ensure there is at least some form of rate
+	// limiting for Gerrit.
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		RateLimit:  l,
+	}
+}
+
+// ListProjectsArgs are the pagination arguments of ListProjects.
+type ListProjectsArgs struct {
+	// Skip is the number of projects to skip, counted from the start of the listing.
+	Skip int
+	// Limit is the maximum number of projects to return.
+	Limit int
+}
+
+// ListProjects returns a page of the code projects visible to the client's user, sorted
+// by name. nextPage is true if there may be further projects after the returned page.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#list-projects
+func (c *Client) ListProjects(ctx context.Context, args ListProjectsArgs) (projects []*Project, nextPage bool, err error) {
+	qry := make(url.Values)
+	qry.Set("d", "") // include descriptions
+	qry.Set("type", "CODE")
+	if args.Skip > 0 {
+		qry.Set("S", strconv.Itoa(args.Skip))
+	}
+	if args.Limit > 0 {
+		qry.Set("n", strconv.Itoa(args.Limit))
+	}
+
+	// Projects are returned as a map keyed by name, with the name omitted from the
+	// values themselves.
+	var byName map[string]*Project
+	if err := c.get(ctx, "projects/", qry, &byName); err != nil {
+		return nil, false, err
+	}
+
+	projects = make([]*Project, 0, len(byName))
+	for name, p := range byName {
+		p.Name = name
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+
+	return projects, args.Limit > 0 && len(projects) >= args.Limit, nil
+}
+
+// GetProject returns the project with the given name.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#get-project
+func (c *Client) GetProject(ctx context.Context, name string) (*Project, error) {
+	var p Project
+	if err := c.get(ctx, "projects/"+url.PathEscape(name), nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, qry url.Values, result interface{}) error {
+	// Authenticated requests are made against the /a/ prefix of every endpoint.
+	if c.Username != "" {
+		path = "a/" + path
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = qry.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, req, result)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req.Header.Set("Accept", "application/json")
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gerrit"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Gerrit self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return json.Unmarshal(bytes.TrimPrefix(bs, xssiPrefix), result)
+	}
+
+	return nil
+}
+
+// Project is a Gerrit project, as described by the ProjectInfo entity.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#project-info
+type Project struct {
+	// ID is the URL encoded project name.
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Parent      string    `json:"parent,omitempty"`
+	Description string    `json:"description,omitempty"`
+	State       string    `json:"state,omitempty"`
+	WebLinks    []WebLink `json:"web_links,omitempty"`
+}
+
+// Project states, as documented for ProjectInfo.
+const (
+	ProjectStateActive   = "ACTIVE"
+	ProjectStateReadOnly = "READ_ONLY"
+	ProjectStateHidden   = "HIDDEN"
+)
+
+type WebLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gerrit API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
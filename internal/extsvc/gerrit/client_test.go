@@ -0,0 +1,99 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+)
+
+func TestClient_ListProjects(t *testing.T) {
+	var have []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		have = append(have, r.URL.RequestURI())
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `)]}'
+{
+  "platform/build": {"id": "platform%2Fbuild", "state": "ACTIVE", "description": "Build system"},
+  "device/google": {"id": "device%2Fgoogle", "state": "READ_ONLY"}
+}`)
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(t, srv.URL+"/gerrit/")
+	cli.Username = "admin"
+	cli.Password = "secret"
+
+	projects, next, err := cli.ListProjects(context.Background(), ListProjectsArgs{Skip: 2, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"/gerrit/a/projects/?S=2&d=&n=2&type=CODE"}, have); diff != "" {
+		t.Errorf("unexpected requests (-want +got):\n%s", diff)
+	}
+	want := []*Project{
+		{ID: "device%2Fgoogle", Name: "device/google", State: ProjectStateReadOnly},
+		{ID: "platform%2Fbuild", Name: "platform/build", State: ProjectStateActive, Description: "Build system"},
+	}
+	if diff := cmp.Diff(want, projects); diff != "" {
+		t.Errorf("unexpected projects (-want +got):\n%s", diff)
+	}
+	if !next {
+		t.Error("expected a next page for a full page of results")
+	}
+}
+
+func TestClient_GetProject(t *testing.T) {
+	var have string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		have = r.URL.EscapedPath()
+		if r.URL.Path != "/projects/platform/build" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `)]}'
+{"id": "platform%2Fbuild", "name": "platform/build", "parent": "All-Projects"}`)
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(t, srv.URL)
+
+	p, err := cli.GetProject(context.Background(), "platform/build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/projects/platform%2Fbuild"; have != want {
+		t.Errorf("unexpected request path: have %q want %q", have, want)
+	}
+	if diff := cmp.Diff(&Project{ID: "platform%2Fbuild", Name: "platform/build", Parent: "All-Projects"}, p); diff != "" {
+		t.Errorf("unexpected project (-want +got):\n%s", diff)
+	}
+
+	_, err = cli.GetProject(context.Background(), "missing")
+	var e *httpError
+	if !errors.As(err, &e) || !e.NotFound() {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func newTestClient(t *testing.T, rawURL string) *Client {
+	t.Helper()
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := NewClient(u, http.DefaultClient)
+	cli.RateLimit = rate.NewLimiter(rate.Inf, 0)
+	return cli
+}
@@ -0,0 +1,320 @@
+// Package gitea implements a Gitea API client. Forgejo is a fork of Gitea that
+// serves the same API, so it is supported by this client as well.
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("gitea_requests_count", "Total number of requests sent to the Gitea API.")
+
+// These fields define the self-imposed Gitea rate limit (since Gitea does not have a
+// concept of rate limiting in HTTP response headers).
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 2 // 120/min or 7200/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// apiPath is the path of the v1 API, relative to the root of the Gitea instance.
+const apiPath = "api/v1/"
+
+// Client access a Gitea instance via its v1 REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of the Gitea instance, not of its API.
+	URL *url.URL
+
+	// Token is the access token used to authenticate requests. If unset,
+	// requests are made anonymously.
+	Token string
+
+	// RateLimit is the self-imposed rate limiter (since Gitea does not have a concept
+	// of rate limiting in HTTP response headers).
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Gitea API client with the given base URL. If a nil httpClient
+// is provided, httpcli.ExternalDoer will be used.
+func NewClient(baseURL *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer()
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The first component of the path after the API prefix maps to the
+		// type of API request we are making.
+		path := strings.TrimPrefix(strings.TrimPrefix(u.Path, baseURL.Path), apiPath)
+		if i := strings.Index(path, "/"); i >= 0 {
+			path = path[:i]
+		}
+		return path
+	})
+
+	// Normally our registry will return a default infinite limiter when nothing has been
+	// synced from config. However, we always want to ensure there is at least some form of rate
+	// limiting for Gitea.
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		RateLimit:  l,
+	}
+}
+
+// WithToken returns a copy of the client that authenticates with the given
+// access token.
+func (c *Client) WithToken(token string) *Client {
+	return &Client{
+		httpClient: c.httpClient,
+		URL:        c.URL,
+		Token:      token,
+		RateLimit:  c.RateLimit,
+	}
+}
+
+// PageArgs are the pagination arguments of the listing endpoints. Pages are
+// numbered starting at 1.
+type PageArgs struct {
+	Page  int
+	Limit int
+}
+
+func (a PageArgs) values() url.Values {
+	qry := make(url.Values)
+	if a.Page > 0 {
+		qry.Set("page", strconv.Itoa(a.Page))
+	}
+	if a.Limit > 0 {
+		qry.Set("limit", strconv.Itoa(a.Limit))
+	}
+	return qry
+}
+
+// hasNextPage reports whether a listing that returned n results for the given
+// arguments may have further results. Gitea caps the page size at its
+// configured maximum, so a short page is the only reliable end marker.
+func (a PageArgs) hasNextPage(n int) bool {
+	return n > 0 && (a.Limit == 0 || n >= a.Limit)
+}
+
+// ListOrgRepos returns a page of the repositories owned by the given
+// organization. nextPage is true if there may be further repositories.
+//
+// See https://try.gitea.io/api/swagger#/organization/orgListRepos
+func (c *Client) ListOrgRepos(ctx context.Context, org string, args PageArgs) (repos []*Repository, nextPage bool, err error) {
+	err = c.get(ctx, "orgs/"+url.PathEscape(org)+"/repos", args.values(), &repos)
+	return repos, args.hasNextPage(len(repos)), err
+}
+
+// ListUserRepos returns a page of the repositories owned by the given user.
+// nextPage is true if there may be further repositories.
+//
+// See https://try.gitea.io/api/swagger#/user/userListRepos
+func (c *Client) ListUserRepos(ctx context.Context, user string, args PageArgs) (repos []*Repository, nextPage bool, err error) {
+	err = c.get(ctx, "users/"+url.PathEscape(user)+"/repos", args.values(), &repos)
+	return repos, args.hasNextPage(len(repos)), err
+}
+
+// SearchRepos returns a page of the repositories matching the given keyword.
+// An empty query matches all repositories visible to the client. nextPage is
+// true if there may be further repositories.
+//
+// See https://try.gitea.io/api/swagger#/repository/repoSearch
+func (c *Client) SearchRepos(ctx context.Context, query string, args PageArgs) (repos []*Repository, nextPage bool, err error) {
+	qry := args.values()
+	if query != "" {
+		qry.Set("q", query)
+	}
+
+	var result struct {
+		OK   bool          `json:"ok"`
+		Data []*Repository `json:"data"`
+	}
+	if err := c.get(ctx, "repos/search", qry, &result); err != nil {
+		return nil, false, err
+	}
+	return result.Data, args.hasNextPage(len(result.Data)), nil
+}
+
+// GetRepo returns the repository with the given "owner/name".
+//
+// See https://try.gitea.io/api/swagger#/repository/repoGet
+func (c *Client) GetRepo(ctx context.Context, nameWithOwner string) (*Repository, error) {
+	if strings.Count(nameWithOwner, "/") != 1 {
+		return nil, errors.Errorf("invalid Gitea repository %q, expected owner/name", nameWithOwner)
+	}
+
+	var repo Repository
+	if err := c.get(ctx, "repos/"+nameWithOwner, nil, &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// CurrentUser returns the user that the client is authenticated as.
+//
+// See https://try.gitea.io/api/swagger#/user/userGetCurrent
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.get(ctx, "user", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, qry url.Values, result interface{}) error {
+	return c.send(ctx, "GET", path, qry, nil, result)
+}
+
+func (c *Client) send(ctx context.Context, method, path string, qry url.Values, payload, result interface{}) error {
+	u, err := url.Parse(apiPath + path)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = qry.Encode()
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return errors.Wrap(err, "marshalling request")
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.do(ctx, req, result)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req.Header.Set("Accept", "application/json")
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gitea"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Gitea self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil && len(bs) > 0 {
+		return json.Unmarshal(bs, result)
+	}
+
+	return nil
+}
+
+// Repository is a Gitea repository.
+//
+// See https://try.gitea.io/api/swagger#model-Repository
+type Repository struct {
+	ID            int64     `json:"id"`
+	Owner         *User     `json:"owner"`
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	Description   string    `json:"description"`
+	Empty         bool      `json:"empty"`
+	Private       bool      `json:"private"`
+	Fork          bool      `json:"fork"`
+	Mirror        bool      `json:"mirror"`
+	Archived      bool      `json:"archived"`
+	HTMLURL       string    `json:"html_url"`
+	SSHURL        string    `json:"ssh_url"`
+	CloneURL      string    `json:"clone_url"`
+	DefaultBranch string    `json:"default_branch"`
+	StarsCount    int       `json:"stars_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// User is a Gitea user or organization.
+//
+// See https://try.gitea.io/api/swagger#model-User
+type User struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gitea API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,112 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+)
+
+func TestClient_SearchRepos(t *testing.T) {
+	var have []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		have = append(have, r.URL.RequestURI())
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"ok": true, "data": [
+  {"id": 1, "name": "api", "full_name": "acme/api", "owner": {"id": 7, "login": "acme"}},
+  {"id": 2, "name": "web", "full_name": "acme/web", "owner": {"id": 7, "login": "acme"}, "archived": true}
+]}`)
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(t, srv.URL+"/gitea/").WithToken("secret")
+
+	repos, next, err := cli.SearchRepos(context.Background(), "acme", PageArgs{Page: 2, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"/gitea/api/v1/repos/search?limit=2&page=2&q=acme"}, have); diff != "" {
+		t.Errorf("unexpected requests (-want +got):\n%s", diff)
+	}
+	owner := &User{ID: 7, Login: "acme"}
+	want := []*Repository{
+		{ID: 1, Name: "api", FullName: "acme/api", Owner: owner},
+		{ID: 2, Name: "web", FullName: "acme/web", Owner: owner, Archived: true},
+	}
+	if diff := cmp.Diff(want, repos); diff != "" {
+		t.Errorf("unexpected repos (-want +got):\n%s", diff)
+	}
+	if !next {
+		t.Error("expected a next page for a full page of results")
+	}
+
+	_, err = newTestClient(t, srv.URL).CurrentUser(context.Background())
+	if !IsUnauthorized(err) {
+		t.Errorf("expected unauthorized error, got %v", err)
+	}
+}
+
+func TestClient_FindOpenPullRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/acme/api/pulls" || r.URL.Query().Get("state") != "open" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// A pull request from a fork with the same branch name must not match.
+		fmt.Fprint(w, `[
+  {"number": 3, "head": {"ref": "feature", "repo_id": 9}, "base": {"ref": "main", "repo_id": 1}},
+  {"number": 4, "head": {"ref": "feature", "repo_id": 1}, "base": {"ref": "main", "repo_id": 1}}
+]`)
+	}))
+	defer srv.Close()
+
+	cli := newTestClient(t, srv.URL)
+	repo := &Repository{FullName: "acme/api"}
+
+	pr, err := cli.FindOpenPullRequest(context.Background(), repo, "feature", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Number != 4 {
+		t.Errorf("unexpected pull request: have #%d want #4", pr.Number)
+	}
+
+	_, err = cli.FindOpenPullRequest(context.Background(), repo, "other", "main")
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestIsNotMergeable(t *testing.T) {
+	for code, want := range map[int]bool{
+		http.StatusMethodNotAllowed:    true,
+		http.StatusConflict:            true,
+		http.StatusNotFound:            false,
+		http.StatusInternalServerError: false,
+	} {
+		if have := IsNotMergeable(&httpError{StatusCode: code}); have != want {
+			t.Errorf("IsNotMergeable(%d): have %v want %v", code, have, want)
+		}
+	}
+}
+
+func newTestClient(t *testing.T, rawURL string) *Client {
+	t.Helper()
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := NewClient(u, http.DefaultClient)
+	cli.RateLimit = rate.NewLimiter(rate.Inf, 0)
+	return cli
+}
@@ -0,0 +1,307 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+// PullRequestState is the state of a Gitea pull request. Merged pull requests
+// are closed, with Merged set to true.
+type PullRequestState string
+
+const (
+	PullRequestStateOpen   PullRequestState = "open"
+	PullRequestStateClosed PullRequestState = "closed"
+)
+
+// PullRequest is a Gitea pull request.
+//
+// See https://try.gitea.io/api/swagger#model-PullRequest
+type PullRequest struct {
+	ID             int64            `json:"id"`
+	Number         int64            `json:"number"`
+	Title          string           `json:"title"`
+	Body           string           `json:"body"`
+	State          PullRequestState `json:"state"`
+	User           *User            `json:"user"`
+	HTMLURL        string           `json:"html_url"`
+	Mergeable      bool             `json:"mergeable"`
+	Merged         bool             `json:"merged"`
+	MergedAt       *time.Time       `json:"merged_at"`
+	MergedBy       *User            `json:"merged_by"`
+	MergeCommitSHA *string          `json:"merge_commit_sha"`
+	Head           PullRequestRef   `json:"head"`
+	Base           PullRequestRef   `json:"base"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	ClosedAt       *time.Time       `json:"closed_at"`
+
+	// CombinedStatus is not returned by the pull request endpoints, and has to
+	// be populated separately with GetCombinedStatus.
+	CombinedStatus *CombinedStatus `json:"combined_status,omitempty"`
+
+	// Reviews are not returned by the pull request endpoints, and have to be
+	// populated separately with ListPullRequestReviews.
+	Reviews []*PullRequestReview `json:"reviews,omitempty"`
+}
+
+// PullRequestReviewState is the state of a pull request review.
+type PullRequestReviewState string
+
+const (
+	PullRequestReviewStateApproved       PullRequestReviewState = "APPROVED"
+	PullRequestReviewStatePending        PullRequestReviewState = "PENDING"
+	PullRequestReviewStateComment        PullRequestReviewState = "COMMENT"
+	PullRequestReviewStateRequestChanges PullRequestReviewState = "REQUEST_CHANGES"
+	PullRequestReviewStateRequestReview  PullRequestReviewState = "REQUEST_REVIEW"
+)
+
+// PullRequestReview is a review of a pull request, or a request for one.
+// Pending reviews are drafts that haven't been submitted yet.
+//
+// See https://try.gitea.io/api/swagger#model-PullReview
+type PullRequestReview struct {
+	ID          int64                  `json:"id"`
+	User        *User                  `json:"user"`
+	State       PullRequestReviewState `json:"state"`
+	Body        string                 `json:"body"`
+	CommitID    string                 `json:"commit_id"`
+	Stale       bool                   `json:"stale"`
+	Dismissed   bool                   `json:"dismissed"`
+	SubmittedAt *time.Time             `json:"submitted_at"`
+}
+
+// PullRequestRef is the head or base branch of a pull request.
+type PullRequestRef struct {
+	Label  string      `json:"label"`
+	Ref    string      `json:"ref"`
+	SHA    string      `json:"sha"`
+	RepoID int64       `json:"repo_id"`
+	Repo   *Repository `json:"repo"`
+}
+
+// CommitStatusState is the state of a commit status, or the combined state of
+// all statuses of a commit.
+type CommitStatusState string
+
+const (
+	CommitStatusPending CommitStatusState = "pending"
+	CommitStatusSuccess CommitStatusState = "success"
+	CommitStatusError   CommitStatusState = "error"
+	CommitStatusFailure CommitStatusState = "failure"
+	CommitStatusWarning CommitStatusState = "warning"
+)
+
+// CombinedStatus is the latest status of every context reported for a commit.
+//
+// See https://try.gitea.io/api/swagger#model-CombinedStatus
+type CombinedStatus struct {
+	State    CommitStatusState `json:"state"`
+	SHA      string            `json:"sha"`
+	Statuses []*CommitStatus   `json:"statuses"`
+}
+
+// CommitStatus is a single status reported for a commit by an external system.
+//
+// See https://try.gitea.io/api/swagger#model-CommitStatus
+type CommitStatus struct {
+	ID          int64             `json:"id"`
+	Status      CommitStatusState `json:"status"`
+	Context     string            `json:"context"`
+	Description string            `json:"description"`
+	TargetURL   string            `json:"target_url"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// CreatePullRequestInput is the input for a new pull request. Head and Base
+// are branch names.
+type CreatePullRequestInput struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+}
+
+// CreatePullRequest opens a new pull request in the given repository. If an
+// open pull request already exists for the same branches, an error for which
+// IsAlreadyExists returns true is returned.
+//
+// See https://try.gitea.io/api/swagger#/repository/repoCreatePullRequest
+func (c *Client) CreatePullRequest(ctx context.Context, repo *Repository, input CreatePullRequestInput) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.send(ctx, "POST", "repos/"+repo.FullName+"/pulls", nil, &input, &pr); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+	return &pr, nil
+}
+
+// GetPullRequest retrieves a single pull request by its number.
+//
+// See https://try.gitea.io/api/swagger#/repository/repoGetPullRequest
+func (c *Client) GetPullRequest(ctx context.Context, repo *Repository, number int64) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.get(ctx, pullRequestPath(repo, number), nil, &pr); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+	return &pr, nil
+}
+
+// FindOpenPullRequest returns the open pull request from the head branch into
+// the base branch, or a *PullRequestNotFoundError if there is none.
+//
+// See https://try.gitea.io/api/swagger#/repository/repoListPullRequests
+func (c *Client) FindOpenPullRequest(ctx context.Context, repo *Repository, head, base string) (*PullRequest, error) {
+	args := PageArgs{Page: 1, Limit: 50}
+	for {
+		qry := args.values()
+		qry.Set("state", string(PullRequestStateOpen))
+
+		var prs []*PullRequest
+		if err := c.get(ctx, "repos/"+repo.FullName+"/pulls", qry, &prs); err != nil {
+			return nil, errors.Wrap(err, "sending request")
+		}
+
+		for _, pr := range prs {
+			if pr.Head.Ref == head && pr.Base.Ref == base && pr.Head.RepoID == pr.Base.RepoID {
+				return pr, nil
+			}
+		}
+
+		if !args.hasNextPage(len(prs)) {
+			return nil, &PullRequestNotFoundError{Repo: repo.FullName, Head: head, Base: base}
+		}
+		args.Page++
+	}
+}
+
+// EditPullRequestInput is the input for editing a pull request. Unset fields
+// are left unchanged.
+type EditPullRequestInput struct {
+	Title string            `json:"title,omitempty"`
+	Body  *string           `json:"body,omitempty"`
+	Base  string            `json:"base,omitempty"`
+	State *PullRequestState `json:"state,omitempty"`
+}
+
+// EditPullRequest updates the given pull request. It is also used to close and
+// reopen pull requests, by setting State.
+//
+// See https://try.gitea.io/api/swagger#/repository/repoEditPullRequest
+func (c *Client) EditPullRequest(ctx context.Context, repo *Repository, number int64, input EditPullRequestInput) (*PullRequest, error) {
+	var pr PullRequest
+	if err := c.send(ctx, "PATCH", pullRequestPath(repo, number), nil, &input, &pr); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+	return &pr, nil
+}
+
+// CreatePullRequestComment adds a comment to the given pull request. Pull
+// requests share their number and comments with the issue that backs them.
+//
+// See https://try.gitea.io/api/swagger#/issue/issueCreateComment
+func (c *Client) CreatePullRequestComment(ctx context.Context, repo *Repository, number int64, body string) error {
+	input := struct {
+		Body string `json:"body"`
+	}{Body: body}
+
+	path := fmt.Sprintf("repos/%s/issues/%d/comments", repo.FullName, number)
+	if err := c.send(ctx, "POST", path, nil, &input, nil); err != nil {
+		return errors.Wrap(err, "sending request")
+	}
+	return nil
+}
+
+// MergeStyle is the strategy used to merge a pull request.
+type MergeStyle string
+
+const (
+	MergeStyleMerge       MergeStyle = "merge"
+	MergeStyleRebase      MergeStyle = "rebase"
+	MergeStyleRebaseMerge MergeStyle = "rebase-merge"
+	MergeStyleSquash      MergeStyle = "squash"
+)
+
+// MergePullRequest merges the given pull request with the given style. Gitea
+// doesn't return the merged pull request, so it has to be fetched again by the
+// caller.
+//
+// See https://try.gitea.io/api/swagger#/repository/repoMergePullRequest
+func (c *Client) MergePullRequest(ctx context.Context, repo *Repository, number int64, style MergeStyle) error {
+	input := struct {
+		Do MergeStyle `json:"Do"`
+	}{Do: style}
+
+	if err := c.send(ctx, "POST", pullRequestPath(repo, number)+"/merge", nil, &input, nil); err != nil {
+		return errors.Wrap(err, "sending request")
+	}
+	return nil
+}
+
+// GetCombinedStatus returns the combined commit status of the given ref.
+//
+// See https://try.gitea.io/api/swagger#/repository/repoGetCombinedStatusByRef
+func (c *Client) GetCombinedStatus(ctx context.Context, repo *Repository, ref string) (*CombinedStatus, error) {
+	var status CombinedStatus
+	if err := c.get(ctx, "repos/"+repo.FullName+"/commits/"+url.PathEscape(ref)+"/status", nil, &status); err != nil {
+		return nil, errors.Wrap(err, "sending request")
+	}
+	return &status, nil
+}
+
+// ListPullRequestReviews returns a page of the reviews of the given pull
+// request, oldest first. nextPage is true if there may be further reviews.
+//
+// See https://try.gitea.io/api/swagger#/repository/repoListPullReviews
+func (c *Client) ListPullRequestReviews(ctx context.Context, repo *Repository, number int64, args PageArgs) (reviews []*PullRequestReview, nextPage bool, err error) {
+	err = c.get(ctx, pullRequestPath(repo, number)+"/reviews", args.values(), &reviews)
+	return reviews, args.hasNextPage(len(reviews)), err
+}
+
+// PullRequestNotFoundError is returned by FindOpenPullRequest if there is no
+// open pull request for the given branches.
+type PullRequestNotFoundError struct {
+	Repo, Head, Base string
+}
+
+func (e *PullRequestNotFoundError) Error() string {
+	return fmt.Sprintf("no open pull request from %q into %q in %s", e.Head, e.Base, e.Repo)
+}
+
+func (e *PullRequestNotFoundError) NotFound() bool { return true }
+
+func pullRequestPath(repo *Repository, number int64) string {
+	return fmt.Sprintf("repos/%s/pulls/%d", repo.FullName, number)
+}
+
+// IsNotFound reports whether err is a Gitea API not found error.
+func IsNotFound(err error) bool {
+	return errcode.IsNotFound(err)
+}
+
+// IsUnauthorized reports whether err is a Gitea API 401 error.
+func IsUnauthorized(err error) bool {
+	return errcode.IsUnauthorized(err)
+}
+
+// IsAlreadyExists reports whether err is the error returned by
+// CreatePullRequest when an open pull request for the same branches exists.
+func IsAlreadyExists(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && e.StatusCode == http.StatusConflict
+}
+
+// IsNotMergeable reports whether err is the error returned by
+// MergePullRequest when the pull request cannot be merged, such as when it has
+// conflicts or doesn't pass the branch protection checks.
+func IsNotMergeable(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && (e.StatusCode == http.StatusMethodNotAllowed || e.StatusCode == http.StatusConflict)
+}
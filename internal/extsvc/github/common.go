@@ -1004,6 +1004,134 @@ func (c *V4Client) MergePullRequest(ctx context.Context, pr *PullRequest, squash
 	return nil
 }
 
+const requestReviewsMutation = `
+mutation RequestReviews($input: RequestReviewsInput!) {
+  requestReviews(input: $input) {
+    pullRequest {
+      ...pr
+    }
+  }
+}
+`
+
+// RequestPullRequestReviews requests reviews of the PullRequest on GitHub from
+// the users with the given logins and the teams with the given slugs, in the
+// form "org/team". Users and teams that can't be found are skipped.
+func (c *V4Client) RequestPullRequestReviews(ctx context.Context, pr *PullRequest, users, teams []string) error {
+	if len(users) == 0 && len(teams) == 0 {
+		return nil
+	}
+
+	userIDs, teamIDs, err := c.lookupReviewerIDs(ctx, users, teams)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 && len(teamIDs) == 0 {
+		return nil
+	}
+
+	version := c.determineGitHubVersion(ctx)
+	prFragment, err := pullRequestFragments(version)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		RequestReviews struct {
+			PullRequest struct {
+				PullRequest
+				Participants  struct{ Nodes []Actor }
+				TimelineItems TimelineItemConnection
+			} `json:"pullRequest"`
+		} `json:"requestReviews"`
+	}
+
+	input := map[string]interface{}{"input": struct {
+		PullRequestID string   `json:"pullRequestId"`
+		UserIDs       []string `json:"userIds,omitempty"`
+		TeamIDs       []string `json:"teamIds,omitempty"`
+		Union         bool     `json:"union"`
+	}{
+		PullRequestID: pr.ID,
+		UserIDs:       userIDs,
+		TeamIDs:       teamIDs,
+		// Keep the reviews that have already been requested.
+		Union: true,
+	}}
+	if err := c.requestGraphQL(ctx, prFragment+"\n"+requestReviewsMutation, input, &result); err != nil {
+		return err
+	}
+
+	ti := result.RequestReviews.PullRequest.TimelineItems
+	*pr = result.RequestReviews.PullRequest.PullRequest
+	pr.TimelineItems = ti.Nodes
+	pr.Participants = result.RequestReviews.PullRequest.Participants.Nodes
+
+	items, err := c.loadRemainingTimelineItems(ctx, pr.ID, ti.PageInfo)
+	if err != nil {
+		return err
+	}
+	pr.TimelineItems = append(pr.TimelineItems, items...)
+	return nil
+}
+
+// lookupReviewerIDs returns the node IDs of the users with the given logins
+// and the teams with the given slugs, in the form "org/team". Users and teams
+// that can't be found are skipped.
+func (c *V4Client) lookupReviewerIDs(ctx context.Context, users, teams []string) (userIDs, teamIDs []string, err error) {
+	var (
+		params []string
+		fields []string
+		vars   = map[string]interface{}{}
+	)
+	for i, login := range users {
+		params = append(params, fmt.Sprintf("$user%d: String!", i))
+		fields = append(fields, fmt.Sprintf("user%d: user(login: $user%d) { id }", i, i))
+		vars[fmt.Sprintf("user%d", i)] = login
+	}
+	for i, team := range teams {
+		parts := strings.SplitN(team, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, nil, errors.Errorf("invalid GitHub team \"org/team\" string: %q", team)
+		}
+		params = append(params, fmt.Sprintf("$org%d: String!", i), fmt.Sprintf("$team%d: String!", i))
+		fields = append(fields, fmt.Sprintf("team%d: organization(login: $org%d) { team(slug: $team%d) { id } }", i, i, i))
+		vars[fmt.Sprintf("org%d", i)] = parts[0]
+		vars[fmt.Sprintf("team%d", i)] = parts[1]
+	}
+	q := fmt.Sprintf("query(%s) {\n%s\n}", strings.Join(params, ", "), strings.Join(fields, "\n"))
+
+	var result map[string]*struct {
+		ID   string
+		Team *struct{ ID string }
+	}
+	if err := c.requestGraphQL(ctx, q, vars, &result); err != nil {
+		// Users and organizations that don't exist are reported as NOT_FOUND
+		// errors, while teams that don't exist are null.
+		var e graphqlErrors
+		if !errors.As(err, &e) {
+			return nil, nil, err
+		}
+		for _, err2 := range e {
+			if err2.Type != graphqlErrTypeNotFound {
+				return nil, nil, err
+			}
+		}
+	}
+
+	for i := range users {
+		if u := result[fmt.Sprintf("user%d", i)]; u != nil && u.ID != "" {
+			userIDs = append(userIDs, u.ID)
+		}
+	}
+	for i := range teams {
+		if o := result[fmt.Sprintf("team%d", i)]; o != nil && o.Team != nil {
+			teamIDs = append(teamIDs, o.Team.ID)
+		}
+	}
+	return userIDs, teamIDs, nil
+}
+
 func (c *V4Client) loadRemainingTimelineItems(ctx context.Context, prID string, pageInfo PageInfo) (items []TimelineItem, err error) {
 	version := c.determineGitHubVersion(ctx)
 	timelineItemTypes, err := timelineItemTypes(version)
@@ -1644,6 +1772,38 @@ type Repository struct {
 	// Metadata retained for ranking
 	StargazerCount int `json:",omitempty"`
 	ForkCount      int `json:",omitempty"`
+
+	// RepositoryTopics is the list of topics the repository has been tagged
+	// with. It is retained for searching with repo:has.topic().
+	RepositoryTopics *RepositoryTopics `json:",omitempty"`
+}
+
+// RepositoryTopics is the topic connection of a repository.
+type RepositoryTopics struct {
+	Nodes []RepositoryTopic
+}
+
+// RepositoryTopic is a topic a repository is tagged with.
+type RepositoryTopic struct {
+	Topic Topic
+}
+
+// Topic is a GitHub topic.
+type Topic struct {
+	Name string
+}
+
+// newRepositoryTopics returns the topic connection for the given topic names,
+// or nil if there are none.
+func newRepositoryTopics(names []string) *RepositoryTopics {
+	if len(names) == 0 {
+		return nil
+	}
+	nodes := make([]RepositoryTopic, 0, len(names))
+	for _, name := range names {
+		nodes = append(nodes, RepositoryTopic{Topic: Topic{Name: name}})
+	}
+	return &RepositoryTopics{Nodes: nodes}
 }
 
 func ownerNameCacheKey(owner, name string) string       { return "0:" + owner + "/" + name }
@@ -1715,6 +1875,7 @@ type restRepository struct {
 	Permissions restRepositoryPermissions `json:"permissions"`
 	Stars       int                       `json:"stargazers_count"`
 	Forks       int                       `json:"forks_count"`
+	Topics      []string                  `json:"topics"`
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -1750,6 +1911,7 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		ViewerPermission: convertRestRepoPermissions(restRepo.Permissions),
 		StargazerCount:   restRepo.Stars,
 		ForkCount:        restRepo.Forks,
+		RepositoryTopics: newRepositoryTopics(restRepo.Topics),
 	}
 }
 
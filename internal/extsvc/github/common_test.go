@@ -3,6 +3,7 @@ package github
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -519,6 +520,64 @@ func TestClient_GetReposByNameWithOwner(t *testing.T) {
 	}
 }
 
+func TestV4Client_lookupReviewerIDs(t *testing.T) {
+	mock := mockHTTPResponseBody{responseBody: `
+{
+  "data": {
+    "user0": { "id": "MDQ6VXNlcjE=" },
+    "user1": null,
+    "team0": { "team": { "id": "MDQ6VGVhbTE=" } },
+    "team1": { "team": null }
+  },
+  "errors": [
+    {
+      "type": "NOT_FOUND",
+      "path": ["user1"],
+      "message": "Could not resolve to a User with the login of 'ghost'."
+    }
+  ]
+}
+`}
+	var variables map[string]interface{}
+	doer := httpcli.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		var body struct{ Variables map[string]interface{} }
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		variables = body.Variables
+		return mock.Do(req)
+	})
+	apiURL := &url.URL{Scheme: "https", Host: "example.com", Path: "/"}
+	c := NewV4Client(apiURL, nil, doer)
+
+	userIDs, teamIDs, err := c.lookupReviewerIDs(context.Background(), []string{"alice", "ghost"}, []string{"sourcegraph/batchers", "sourcegraph/ghosts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"MDQ6VXNlcjE="}, userIDs); diff != "" {
+		t.Errorf("wrong user IDs (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"MDQ6VGVhbTE="}, teamIDs); diff != "" {
+		t.Errorf("wrong team IDs (-want +got):\n%s", diff)
+	}
+
+	wantVariables := map[string]interface{}{
+		"user0": "alice",
+		"user1": "ghost",
+		"org0":  "sourcegraph",
+		"team0": "batchers",
+		"org1":  "sourcegraph",
+		"team1": "ghosts",
+	}
+	if diff := cmp.Diff(wantVariables, variables); diff != "" {
+		t.Errorf("wrong query variables (-want +got):\n%s", diff)
+	}
+
+	if _, _, err := c.lookupReviewerIDs(context.Background(), nil, []string{"batchers"}); err == nil {
+		t.Error("no error for team without organization")
+	}
+}
+
 // NOTE: To update VCR for this test, please use the token of "sourcegraph-vcr"
 // for GITHUB_TOKEN, which can be found in 1Password.
 func TestListRepositoryCollaborators(t *testing.T) {
@@ -537,6 +537,13 @@ fragment RepositoryFields on Repository {
 	viewerPermission
 	stargazerCount
 	forkCount
+	repositoryTopics(first: 100) {
+		nodes {
+			topic {
+				name
+			}
+		}
+	}
 }
 	`
 	}
@@ -561,6 +568,13 @@ fragment RepositoryFields on Repository {
 	isLocked
 	isDisabled
 	forkCount
+	repositoryTopics(first: 100) {
+		nodes {
+			topic {
+				name
+			}
+		}
+	}
 	%s
 }
 	`, strings.Join(ghe300Fields, "\n	"))
@@ -26,6 +26,10 @@ type Member struct {
 
 // ListMembers returns a list of members parsed from reponse of given URL.
 func (c *Client) ListMembers(ctx context.Context, urlStr string) (members []*Member, nextPageURL *string, err error) {
+	if MockListMembers != nil {
+		return MockListMembers(c, ctx, urlStr)
+	}
+
 	req, err := http.NewRequest("GET", urlStr, nil)
 	if err != nil {
 		return nil, nil, err
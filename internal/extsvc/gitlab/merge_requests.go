@@ -42,6 +42,7 @@ type MergeRequest struct {
 	WebURL         string            `json:"web_url"`
 	WorkInProgress bool              `json:"work_in_progress"`
 	Author         User              `json:"author"`
+	Reviewers      []User            `json:"reviewers"`
 
 	DiffRefs DiffRefs `json:"diff_refs"`
 
@@ -241,6 +242,38 @@ func (c *Client) UpdateMergeRequest(ctx context.Context, project *Project, mr *M
 	return resp, nil
 }
 
+// SetMergeRequestReviewers replaces the reviewers of the merge request with
+// the users with the given IDs. This requires GitLab 13.8 or later.
+func (c *Client) SetMergeRequestReviewers(ctx context.Context, project *Project, mr *MergeRequest, reviewerIDs []int32) (*MergeRequest, error) {
+	if MockSetMergeRequestReviewers != nil {
+		return MockSetMergeRequestReviewers(c, ctx, project, mr, reviewerIDs)
+	}
+
+	payload := struct {
+		ReviewerIDs []int32 `json:"reviewer_ids"`
+	}{
+		ReviewerIDs: reviewerIDs,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling payload")
+	}
+
+	time.Sleep(c.rateLimitMonitor.RecommendedWaitForBackgroundOp(1))
+
+	req, err := http.NewRequest("PUT", fmt.Sprintf("projects/%d/merge_requests/%d", project.ID, mr.IID), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request to set merge request reviewers")
+	}
+
+	resp := &MergeRequest{}
+	if _, _, err := c.do(ctx, req, resp); err != nil {
+		return nil, errors.Wrap(err, "sending request to set merge request reviewers")
+	}
+
+	return resp, nil
+}
+
 // ErrNotMergeable is returned by MergeMergeRequest when the merge request cannot
 // be merged, because a precondition isn't met.
 var ErrNotMergeable = errors.New("merge request is not in a mergeable state")
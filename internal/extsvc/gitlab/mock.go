@@ -8,6 +8,9 @@ var MockListProjects func(c *Client, ctx context.Context, urlStr string) (proj [
 // MockListUsers, if non-nil, will be called instead of Client.ListUsers
 var MockListUsers func(c *Client, ctx context.Context, urlStr string) (users []*User, nextPageURL *string, err error)
 
+// MockListMembers, if non-nil, will be called instead of Client.ListMembers
+var MockListMembers func(c *Client, ctx context.Context, urlStr string) (members []*Member, nextPageURL *string, err error)
+
 // MockGetUser, if non-nil, will be called instead of Client.GetUser
 var MockGetUser func(c *Client, ctx context.Context, id string) (*User, error)
 
@@ -45,6 +48,10 @@ var MockGetOpenMergeRequestByRefs func(c *Client, ctx context.Context, project *
 // Client.UpdateMergeRequest
 var MockUpdateMergeRequest func(c *Client, ctx context.Context, project *Project, mr *MergeRequest, opts UpdateMergeRequestOpts) (*MergeRequest, error)
 
+// MockSetMergeRequestReviewers, if non-nil, will be called instead of
+// Client.SetMergeRequestReviewers
+var MockSetMergeRequestReviewers func(c *Client, ctx context.Context, project *Project, mr *MergeRequest, reviewerIDs []int32) (*MergeRequest, error)
+
 // MockMergeMergeRequest, if non-nil, will be called instead of
 // Client.MergeMergeRequest
 var MockMergeMergeRequest func(c *Client, ctx context.Context, project *Project, mr *MergeRequest, squash bool) (*MergeRequest, error)
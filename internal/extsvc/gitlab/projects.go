@@ -30,6 +30,8 @@ type Project struct {
 	Archived          bool           `json:"archived"`
 	StarCount         int            `json:"star_count"`
 	ForksCount        int            `json:"forks_count"`
+	Topics            []string       `json:"topics,omitempty"`   // Topics of the project (GitLab 14.0+)
+	TagList           []string       `json:"tag_list,omitempty"` // Topics of the project (deprecated in GitLab 14.0)
 }
 
 type ProjectCommon struct {
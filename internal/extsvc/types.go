@@ -77,6 +77,8 @@ const (
 	KindAWSCodeCommit   = "AWSCODECOMMIT"
 	KindBitbucketServer = "BITBUCKETSERVER"
 	KindBitbucketCloud  = "BITBUCKETCLOUD"
+	KindGerrit          = "GERRIT"
+	KindGitea           = "GITEA"
 	KindGitHub          = "GITHUB"
 	KindGitLab          = "GITLAB"
 	KindGitolite        = "GITOLITE"
@@ -102,6 +104,14 @@ const (
 	// ServiceID value is the base URL to the Bitbucket Cloud.
 	TypeBitbucketCloud = "bitbucketCloud"
 
+	// TypeGerrit is the (api.ExternalRepoSpec).ServiceType value for Gerrit projects. The ServiceID
+	// value is the base URL to the Gerrit instance.
+	TypeGerrit = "gerrit"
+
+	// TypeGitea is the (api.ExternalRepoSpec).ServiceType value for Gitea repositories. The ServiceID
+	// value is the base URL to the Gitea instance.
+	TypeGitea = "gitea"
+
 	// TypeGitHub is the (api.ExternalRepoSpec).ServiceType value for GitHub repositories. The ServiceID value
 	// is the base URL to the GitHub instance (https://github.com or the GitHub Enterprise URL).
 	TypeGitHub = "github"
@@ -138,6 +148,10 @@ func KindToType(kind string) string {
 		return TypeBitbucketServer
 	case KindBitbucketCloud:
 		return TypeBitbucketCloud
+	case KindGerrit:
+		return TypeGerrit
+	case KindGitea:
+		return TypeGitea
 	case KindGitHub:
 		return TypeGitHub
 	case KindGitLab:
@@ -167,6 +181,10 @@ func TypeToKind(t string) string {
 		return KindBitbucketServer
 	case TypeBitbucketCloud:
 		return KindBitbucketCloud
+	case TypeGerrit:
+		return KindGerrit
+	case TypeGitea:
+		return KindGitea
 	case TypeGitHub:
 		return KindGitHub
 	case TypeGitLab:
@@ -203,6 +221,10 @@ func ParseServiceType(s string) (string, bool) {
 		return TypeBitbucketServer, true
 	case bbcLower:
 		return TypeBitbucketCloud, true
+	case TypeGerrit:
+		return TypeGerrit, true
+	case TypeGitea:
+		return TypeGitea, true
 	case TypeGitHub:
 		return TypeGitHub, true
 	case TypeGitLab:
@@ -232,6 +254,10 @@ func ParseServiceKind(s string) (string, bool) {
 		return KindBitbucketServer, true
 	case KindBitbucketCloud:
 		return KindBitbucketCloud, true
+	case KindGerrit:
+		return KindGerrit, true
+	case KindGitea:
+		return KindGitea, true
 	case KindGitHub:
 		return KindGitHub, true
 	case KindGitLab:
@@ -273,6 +299,10 @@ func ParseConfig(kind, config string) (cfg interface{}, _ error) {
 		cfg = &schema.BitbucketServerConnection{}
 	case KindBitbucketCloud:
 		cfg = &schema.BitbucketCloudConnection{}
+	case KindGerrit:
+		cfg = &schema.GerritConnection{}
+	case KindGitea:
+		cfg = &schema.GiteaConnection{}
 	case KindGitHub:
 		cfg = &schema.GitHubConnection{}
 	case KindGitLab:
@@ -304,6 +334,8 @@ func WebhookURL(kind string, externalServiceID int64, externalURL string) string
 		path = "bitbucket-server-webhooks"
 	case KindGitLab:
 		path = "gitlab-webhooks"
+	case KindBitbucketCloud:
+		path = "bitbucket-cloud-webhooks"
 	default:
 		return ""
 	}
@@ -396,6 +428,20 @@ func GetLimitFromConfig(kind string, config interface{}) (rlc RateLimitConfig, e
 			rlc.IsDefault = false
 		}
 		rlc.BaseURL = c.Url
+	case *schema.GerritConnection:
+		rlc.Limit = defaultRateLimit
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
+	case *schema.GiteaConnection:
+		rlc.Limit = defaultRateLimit
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
 	case *schema.PerforceConnection:
 		rlc.Limit = rate.Limit(5000.0 / 3600.0)
 		if c != nil && c.RateLimit != nil {
@@ -492,6 +538,10 @@ func UniqueCodeHostIdentifier(kind, config string) (string, error) {
 		rawURL = c.Url
 	case *schema.BitbucketCloudConnection:
 		rawURL = c.Url
+	case *schema.GerritConnection:
+		rawURL = c.Url
+	case *schema.GiteaConnection:
+		rawURL = c.Url
 	case *schema.PhabricatorConnection:
 		rawURL = c.Url
 	case *schema.OtherExternalServiceConnection:
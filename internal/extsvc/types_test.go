@@ -108,6 +108,30 @@ func TestExtractRateLimitConfig(t *testing.T) {
 				IsDefault:   true,
 			},
 		},
+		{
+			name:        "Gerrit default",
+			config:      `{"url": "https://example.com/"}`,
+			kind:        KindGerrit,
+			displayName: "Gerrit 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gerrit 1",
+				Limit:       2.0,
+				IsDefault:   true,
+			},
+		},
+		{
+			name:        "Gitea non-default",
+			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
+			kind:        KindGitea,
+			displayName: "Gitea 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gitea 1",
+				Limit:       1.0,
+				IsDefault:   false,
+			},
+		},
 		{
 			name:        "GitLab non-default",
 			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
@@ -303,6 +327,16 @@ func TestUniqueCodeHostIdentifier(t *testing.T) {
 			config: `{"url": "https://bitbucket.org/"}`,
 			want:   "https://bitbucket.org/",
 		},
+		{
+			kind:   KindGerrit,
+			config: `{"url": "https://android-review.googlesource.com"}`,
+			want:   "https://android-review.googlesource.com/",
+		},
+		{
+			kind:   KindGitea,
+			config: `{"url": "https://gitea.example.com/gitea"}`,
+			want:   "https://gitea.example.com/gitea/",
+		},
 
 		{
 			kind:   KindGitolite,
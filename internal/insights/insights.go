@@ -222,6 +222,10 @@ type TimeSeries struct {
 	Name   string
 	Stroke string
 	Query  string
+
+	// GeneratedFromCaptureGroups indicates that Query is a regexp with a capture group, and that
+	// one series should be generated per distinct value it captures instead of counting matches.
+	GeneratedFromCaptureGroups bool
 }
 
 type Interval struct {
@@ -11,6 +11,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -43,6 +45,14 @@ func CloneURL(kind, config string, repo *types.Repo) (string, error) {
 		if r, ok := repo.Metadata.(*bitbucketcloud.Repo); ok {
 			return bitbucketCloudCloneURL(r, t), nil
 		}
+	case *schema.GerritConnection:
+		if r, ok := repo.Metadata.(*gerrit.Project); ok {
+			return gerritCloneURL(r, t)
+		}
+	case *schema.GiteaConnection:
+		if r, ok := repo.Metadata.(*gitea.Repository); ok {
+			return giteaCloneURL(r, t), nil
+		}
 	case *schema.GitHubConnection:
 		if r, ok := repo.Metadata.(*github.Repository); ok {
 			return githubCloneURL(r, t)
@@ -142,6 +152,44 @@ func bitbucketCloudCloneURL(repo *bitbucketcloud.Repo, cfg *schema.BitbucketClou
 	return u.String()
 }
 
+// gerritCloneURL returns the project's Git remote URL. If credentials are
+// configured, the URL points at the authenticated /a/ prefix of the Gerrit
+// instance and has the HTTP password inserted in the URL userinfo.
+func gerritCloneURL(project *gerrit.Project, cfg *schema.GerritConnection) (string, error) {
+	u, err := url.Parse(cfg.Url)
+	if err != nil {
+		return "", err
+	}
+	u = extsvc.NormalizeBaseURL(u)
+
+	path := project.Name
+	if cfg.Username != "" {
+		u.User = url.UserPassword(cfg.Username, cfg.Password)
+		path = "a/" + path
+	}
+	return u.ResolveReference(&url.URL{Path: path}).String(), nil
+}
+
+// giteaCloneURL returns the repository's Git remote URL with the configured
+// Gitea access token inserted in the URL userinfo.
+func giteaCloneURL(repo *gitea.Repository, cfg *schema.GiteaConnection) string {
+	if cfg.GitURLType == "ssh" {
+		return repo.SSHURL // SSH authentication must be provided out-of-band
+	}
+	if cfg.Token == "" {
+		return repo.CloneURL
+	}
+	u, err := url.Parse(repo.CloneURL)
+	if err != nil {
+		log15.Warn("Error adding authentication to Gitea repository Git remote URL.", "url", repo.CloneURL, "error", err)
+		return repo.CloneURL
+	}
+	// Gitea accepts an access token in place of the username when the
+	// password is empty.
+	u.User = url.User(cfg.Token)
+	return u.String()
+}
+
 func githubCloneURL(repo *github.Repository, cfg *schema.GitHubConnection) (string, error) {
 	if cfg.GitURLType == "ssh" {
 		baseURL, err := url.Parse(cfg.Url)
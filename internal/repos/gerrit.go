@@ -0,0 +1,174 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GerritSource yields repositories from a single Gerrit connection configured
+// in Sourcegraph via the external services configuration.
+type GerritSource struct {
+	svc     *types.ExternalService
+	config  *schema.GerritConnection
+	baseURL *url.URL
+	exclude excludeFunc
+	client  *gerrit.Client
+
+	// perPage is the number of projects requested per page when listing all
+	// projects visible to the configured user.
+	perPage int
+}
+
+// NewGerritSource returns a new GerritSource from the given external service.
+func NewGerritSource(svc *types.ExternalService, cf *httpcli.Factory) (*GerritSource, error) {
+	var c schema.GerritConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGerritSource(svc, &c, cf)
+}
+
+func newGerritSource(svc *types.ExternalService, c *schema.GerritConnection, cf *httpcli.Factory) (*GerritSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	client := gerrit.NewClient(baseURL, cli)
+	client.Username = c.Username
+	client.Password = c.Password
+
+	return &GerritSource{
+		svc:     svc,
+		config:  c,
+		baseURL: baseURL,
+		exclude: exclude,
+		client:  client,
+		perPage: 100,
+	}, nil
+}
+
+// ListRepos returns all Gerrit projects configured with this GerritSource's config.
+func (s *GerritSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	if len(s.config.Projects) > 0 {
+		s.listConfiguredProjects(ctx, results)
+		return
+	}
+
+	args := gerrit.ListProjectsArgs{Limit: s.perPage}
+	for {
+		projects, next, err := s.client.ListProjects(ctx, args)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.projects: skip=%d", args.Skip)}
+			return
+		}
+
+		for _, p := range projects {
+			if s.excludes(p) {
+				continue
+			}
+			results <- SourceResult{Source: s, Repo: s.makeRepo(p)}
+		}
+
+		if !next || len(projects) == 0 {
+			return
+		}
+		args.Skip += len(projects)
+	}
+}
+
+// listConfiguredProjects fetches the projects explicitly listed in the
+// configuration. Projects that can't be found are reported as errors without
+// aborting the listing of the remaining ones.
+func (s *GerritSource) listConfiguredProjects(ctx context.Context, results chan SourceResult) {
+	for _, name := range s.config.Projects {
+		if s.exclude(name) {
+			continue
+		}
+
+		p, err := s.client.GetProject(ctx, name)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.project: name=%q", name)}
+			continue
+		}
+		if p.Name == "" {
+			p.Name = name
+		}
+
+		if s.excludes(p) {
+			continue
+		}
+		results <- SourceResult{Source: s, Repo: s.makeRepo(p)}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *GerritSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s *GerritSource) excludes(p *gerrit.Project) bool {
+	// Hidden projects are only listed to administrators and can't be cloned.
+	return p.State == gerrit.ProjectStateHidden || s.exclude(p.Name)
+}
+
+func (s *GerritSource) makeRepo(p *gerrit.Project) *types.Repo {
+	urn := s.svc.URN()
+	return &types.Repo{
+		Name: reposource.GerritRepoName(
+			s.config.RepositoryPathPattern,
+			s.baseURL.Hostname(),
+			p.Name,
+		),
+		URI: string(reposource.GerritRepoName(
+			"",
+			s.baseURL.Hostname(),
+			p.Name,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          p.ID,
+			ServiceType: extsvc.TypeGerrit,
+			ServiceID:   s.baseURL.String(),
+		},
+		Description: p.Description,
+		Archived:    p.State == gerrit.ProjectStateReadOnly,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.baseURL.ResolveReference(&url.URL{Path: p.Name}).String(),
+			},
+		},
+		Metadata: p,
+	}
+}
@@ -0,0 +1,124 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGerritSource_ListRepos(t *testing.T) {
+	projects := []string{
+		"All-Projects",
+		"device/google/coral",
+		"device/google/redfin",
+		"platform/build",
+		"platform/frameworks/base",
+		"platform/hidden",
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/a/projects/":
+			skip, _ := strconv.Atoi(r.URL.Query().Get("S"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("n"))
+
+			var entries []string
+			for i := skip; i < len(projects) && i < skip+limit; i++ {
+				state := gerrit.ProjectStateActive
+				if strings.HasSuffix(projects[i], "hidden") {
+					state = gerrit.ProjectStateHidden
+				}
+				entries = append(entries, fmt.Sprintf("%q: {\"id\": %q, \"state\": %q}", projects[i], projects[i], state))
+			}
+			fmt.Fprintf(w, ")]}'\n{%s}", strings.Join(entries, ","))
+
+		case strings.HasPrefix(r.URL.Path, "/a/projects/"):
+			name := strings.TrimPrefix(r.URL.Path, "/a/projects/")
+			if !strings.HasPrefix(name, "platform/") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprintf(w, ")]}'\n{\"id\": %q, \"name\": %q}", name, name)
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	testCases := []struct {
+		name string
+		conf *schema.GerritConnection
+		want []string
+		err  string
+	}{
+		{
+			name: "all projects",
+			conf: &schema.GerritConnection{
+				Exclude: []*schema.ExcludedGerritProject{
+					{Name: "all-projects"},
+					{Pattern: "redfin$"},
+				},
+			},
+			want: []string{
+				"127.0.0.1/device/google/coral",
+				"127.0.0.1/platform/build",
+				"127.0.0.1/platform/frameworks/base",
+			},
+			err: "<nil>",
+		},
+		{
+			name: "configured projects",
+			conf: &schema.GerritConnection{
+				RepositoryPathPattern: "android/{name}",
+				Projects:              []string{"platform/build", "platform/frameworks/base", "device/missing"},
+				Exclude: []*schema.ExcludedGerritProject{
+					{Name: "platform/frameworks/base"},
+				},
+			},
+			want: []string{"android/platform/build"},
+			err:  `1 error occurred:`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.conf.Url = srv.URL
+			tc.conf.Username = "admin"
+			tc.conf.Password = "secret"
+
+			svc := &types.ExternalService{
+				Kind:   extsvc.KindGerrit,
+				Config: marshalJSON(t, tc.conf),
+			}
+
+			src, err := newGerritSource(svc, tc.conf, httpcli.NewFactory(nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Use a small page size to exercise pagination.
+			src.perPage = 2
+
+			repos, err := listAll(context.Background(), src)
+			if have := fmt.Sprint(err); !strings.HasPrefix(have, tc.err) {
+				t.Errorf("error:\nhave: %q\nwant prefix: %q", have, tc.err)
+			}
+
+			if diff := cmp.Diff(tc.want, types.Repos(repos).Names()); diff != "" {
+				t.Errorf("Mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GiteaSource yields repositories from a single Gitea connection configured
+// in Sourcegraph via the external services configuration.
+type GiteaSource struct {
+	svc             *types.ExternalService
+	config          *schema.GiteaConnection
+	baseURL         *url.URL
+	exclude         excludeFunc
+	excludeArchived bool
+	excludeForks    bool
+	client          *gitea.Client
+
+	// perPage is the number of repositories requested per page from the
+	// listing endpoints.
+	perPage int
+}
+
+// NewGiteaSource returns a new GiteaSource from the given external service.
+func NewGiteaSource(svc *types.ExternalService, cf *httpcli.Factory) (*GiteaSource, error) {
+	var c schema.GiteaConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGiteaSource(svc, &c, cf)
+}
+
+func newGiteaSource(svc *types.ExternalService, c *schema.GiteaConnection, cf *httpcli.Factory) (*GiteaSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		eb              excludeBuilder
+		excludeArchived bool
+		excludeForks    bool
+	)
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		if r.Id != 0 {
+			eb.Exact(strconv.Itoa(r.Id))
+		}
+		eb.Pattern(r.Pattern)
+
+		if r.Archived {
+			excludeArchived = true
+		}
+		if r.Forks {
+			excludeForks = true
+		}
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GiteaSource{
+		svc:             svc,
+		config:          c,
+		baseURL:         baseURL,
+		exclude:         exclude,
+		excludeArchived: excludeArchived,
+		excludeForks:    excludeForks,
+		client:          gitea.NewClient(baseURL, cli).WithToken(c.Token),
+		perPage:         50,
+	}, nil
+}
+
+// ListRepos returns all Gitea repositories matched by the "repos", "orgs",
+// "users" and "repositoryQuery" settings of this GiteaSource's config.
+// Repositories matched by more than one setting are only yielded once.
+func (s *GiteaSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	seen := make(map[int64]bool)
+	yield := func(r *gitea.Repository) {
+		if seen[r.ID] || s.excludes(r) {
+			return
+		}
+		seen[r.ID] = true
+		results <- SourceResult{Source: s, Repo: s.makeRepo(r)}
+	}
+
+	for _, nameWithOwner := range s.config.Repos {
+		if s.exclude(nameWithOwner) {
+			continue
+		}
+
+		r, err := s.client.GetRepo(ctx, nameWithOwner)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gitea.repo: name=%q", nameWithOwner)}
+			continue
+		}
+		yield(r)
+	}
+
+	for _, org := range s.config.Orgs {
+		s.paginate(ctx, results, yield, "gitea.orgs: org="+org, func(args gitea.PageArgs) ([]*gitea.Repository, bool, error) {
+			return s.client.ListOrgRepos(ctx, org, args)
+		})
+	}
+
+	for _, user := range s.config.Users {
+		s.paginate(ctx, results, yield, "gitea.users: user="+user, func(args gitea.PageArgs) ([]*gitea.Repository, bool, error) {
+			return s.client.ListUserRepos(ctx, user, args)
+		})
+	}
+
+	for _, query := range s.config.RepositoryQuery {
+		// "all" is the documented way of listing every visible repository,
+		// which is what an empty search query does.
+		q := query
+		if q == "all" {
+			q = ""
+		}
+		s.paginate(ctx, results, yield, "gitea.search: query="+query, func(args gitea.PageArgs) ([]*gitea.Repository, bool, error) {
+			return s.client.SearchRepos(ctx, q, args)
+		})
+	}
+}
+
+// paginate yields all repositories returned by the given listing. An error
+// aborts the listing, but not the ones that come after it.
+func (s *GiteaSource) paginate(
+	ctx context.Context,
+	results chan SourceResult,
+	yield func(*gitea.Repository),
+	desc string,
+	list func(gitea.PageArgs) ([]*gitea.Repository, bool, error),
+) {
+	args := gitea.PageArgs{Page: 1, Limit: s.perPage}
+	for {
+		repos, next, err := list(args)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "%s page=%d", desc, args.Page)}
+			return
+		}
+
+		for _, r := range repos {
+			yield(r)
+		}
+
+		if !next {
+			return
+		}
+		args.Page++
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *GiteaSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s *GiteaSource) excludes(r *gitea.Repository) bool {
+	// Empty repositories have nothing to clone yet.
+	if r.Empty {
+		return true
+	}
+
+	if s.exclude(r.FullName) || s.exclude(strconv.FormatInt(r.ID, 10)) {
+		return true
+	}
+
+	if s.excludeArchived && r.Archived {
+		return true
+	}
+
+	if s.excludeForks && r.Fork {
+		return true
+	}
+
+	return false
+}
+
+func (s *GiteaSource) makeRepo(r *gitea.Repository) *types.Repo {
+	urn := s.svc.URN()
+
+	cloneURL := r.CloneURL
+	if s.config.GitURLType == "ssh" {
+		cloneURL = r.SSHURL
+	}
+
+	return &types.Repo{
+		Name: reposource.GiteaRepoName(
+			s.config.RepositoryPathPattern,
+			s.baseURL.Hostname(),
+			r.FullName,
+		),
+		URI: string(reposource.GiteaRepoName(
+			"",
+			s.baseURL.Hostname(),
+			r.FullName,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strconv.FormatInt(r.ID, 10),
+			ServiceType: extsvc.TypeGitea,
+			ServiceID:   s.baseURL.String(),
+		},
+		Description: r.Description,
+		Fork:        r.Fork,
+		Archived:    r.Archived,
+		Private:     r.Private,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: cloneURL,
+			},
+		},
+		Metadata: r,
+	}
+}
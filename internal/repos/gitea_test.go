@@ -0,0 +1,190 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGiteaSource_ListRepos(t *testing.T) {
+	repos := []*gitea.Repository{
+		{ID: 1, FullName: "acme/api"},
+		{ID: 2, FullName: "acme/web", Archived: true},
+		{ID: 3, FullName: "acme/empty", Empty: true},
+		{ID: 4, FullName: "acme/tools"},
+		{ID: 5, FullName: "alice/api", Fork: true},
+		{ID: 6, FullName: "alice/dotfiles"},
+	}
+
+	// filter returns the page of repositories matching the predicate.
+	filter := func(r *http.Request, match func(*gitea.Repository) bool) []*gitea.Repository {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var matched []*gitea.Repository
+		for _, repo := range repos {
+			if match(repo) {
+				matched = append(matched, repo)
+			}
+		}
+
+		start, end := (page-1)*limit, page*limit
+		if start > len(matched) {
+			start = len(matched)
+		}
+		if end > len(matched) {
+			end = len(matched)
+		}
+		return matched[start:end]
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/")
+		var result interface{}
+		switch {
+		case path == "repos/search":
+			q := r.URL.Query().Get("q")
+			result = map[string]interface{}{
+				"ok": true,
+				"data": filter(r, func(repo *gitea.Repository) bool {
+					return strings.Contains(repo.FullName, q)
+				}),
+			}
+
+		case strings.HasPrefix(path, "orgs/"), strings.HasPrefix(path, "users/"):
+			owner := strings.Split(path, "/")[1]
+			if owner == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			result = filter(r, func(repo *gitea.Repository) bool {
+				return strings.HasPrefix(repo.FullName, owner+"/")
+			})
+
+		case strings.HasPrefix(path, "repos/"):
+			name := strings.TrimPrefix(path, "repos/")
+			for _, repo := range repos {
+				if repo.FullName == name {
+					result = repo
+				}
+			}
+			if result == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	testCases := []struct {
+		name string
+		conf *schema.GiteaConnection
+		want []string
+		err  string
+	}{
+		{
+			name: "orgs and users",
+			conf: &schema.GiteaConnection{
+				Orgs:  []string{"acme"},
+				Users: []string{"alice", "missing"},
+				Exclude: []*schema.ExcludedGiteaRepo{
+					{Archived: true},
+					{Forks: true},
+				},
+			},
+			want: []string{
+				"127.0.0.1/acme/api",
+				"127.0.0.1/acme/tools",
+				"127.0.0.1/alice/dotfiles",
+			},
+			err: `gitea.users: user=missing page=1`,
+		},
+		{
+			name: "search and repos are deduplicated",
+			conf: &schema.GiteaConnection{
+				RepositoryPathPattern: "gitea/{nameWithOwner}",
+				Repos:                 []string{"acme/tools", "acme/missing"},
+				RepositoryQuery:       []string{"api", "all"},
+				Exclude: []*schema.ExcludedGiteaRepo{
+					{Name: "acme/web"},
+					{Id: 6},
+				},
+			},
+			want: []string{
+				"gitea/acme/tools",
+				"gitea/acme/api",
+				"gitea/alice/api",
+			},
+			err: `gitea.repo: name="acme/missing"`,
+		},
+		{
+			name: "all with pattern",
+			conf: &schema.GiteaConnection{
+				RepositoryQuery: []string{"all"},
+				Exclude: []*schema.ExcludedGiteaRepo{
+					{Pattern: "^alice/"},
+				},
+			},
+			want: []string{
+				"127.0.0.1/acme/api",
+				"127.0.0.1/acme/web",
+				"127.0.0.1/acme/tools",
+			},
+			err: "<nil>",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.conf.Url = srv.URL
+			tc.conf.Token = "secret"
+
+			svc := &types.ExternalService{
+				Kind:   extsvc.KindGitea,
+				Config: marshalJSON(t, tc.conf),
+			}
+
+			src, err := newGiteaSource(svc, tc.conf, httpcli.NewFactory(nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Use a small page size to exercise pagination.
+			src.perPage = 2
+
+			repos, err := listAll(context.Background(), src)
+			if have := fmt.Sprint(err); !strings.Contains(have, tc.err) {
+				t.Errorf("error:\nhave: %q\nwant: %q", have, tc.err)
+			}
+
+			if diff := cmp.Diff(tc.want, types.Repos(repos).Names()); diff != "" {
+				t.Errorf("Mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
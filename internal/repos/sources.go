@@ -64,6 +64,10 @@ func NewSource(svc *types.ExternalService, cf *httpcli.Factory) (Source, error)
 		return NewBitbucketServerSource(svc, cf)
 	case extsvc.KindBitbucketCloud:
 		return NewBitbucketCloudSource(svc, cf)
+	case extsvc.KindGerrit:
+		return NewGerritSource(svc, cf)
+	case extsvc.KindGitea:
+		return NewGiteaSource(svc, cf)
 	case extsvc.KindGitolite:
 		return NewGitoliteSource(svc, cf)
 	case extsvc.KindPhabricator:
@@ -15,6 +15,8 @@ const (
 	FieldType               = "type"
 	FieldRepoHasFile        = "repohasfile"
 	FieldRepoHasCommitAfter = "repohascommitafter"
+	FieldRepoHasDescription = "repohasdescription"
+	FieldRepoHasTopic       = "repohastopic"
 	FieldPatternType        = "patterntype"
 	FieldContent            = "content"
 	FieldVisibility         = "visibility"
@@ -56,6 +58,8 @@ var allFields = map[string]struct{}{
 	FieldVisibility:         empty,
 	FieldRepoHasFile:        empty,
 	FieldRepoHasCommitAfter: empty,
+	FieldRepoHasDescription: empty,
+	FieldRepoHasTopic:       empty,
 	FieldBefore:             empty,
 	"until":                 empty,
 	FieldAfter:              empty,
@@ -31,6 +31,8 @@ var DefaultPredicateRegistry = predicateRegistry{
 		"contains.file":         func() Predicate { return &RepoContainsFilePredicate{} },
 		"contains.content":      func() Predicate { return &RepoContainsContentPredicate{} },
 		"contains.commit.after": func() Predicate { return &RepoContainsCommitAfterPredicate{} },
+		"has.description":       func() Predicate { return &RepoHasDescriptionPredicate{} },
+		"has.topic":             func() Predicate { return &RepoHasTopicPredicate{} },
 	},
 	FieldFile: {
 		"contains.content": func() Predicate { return &FileContainsContentPredicate{} },
@@ -243,6 +245,69 @@ func (f *RepoContainsCommitAfterPredicate) Plan(parent Basic) (Plan, error) {
 	return ToPlan(Dnf(nodes))
 }
 
+/* repo:has.description(pattern) */
+
+type RepoHasDescriptionPredicate struct {
+	Pattern string
+}
+
+func (f *RepoHasDescriptionPredicate) ParseParams(params string) error {
+	if _, err := regexp.Compile(params); err != nil {
+		return errors.Errorf("has.description argument: %w", err)
+	}
+	if params == "" {
+		return errors.Errorf("has.description argument should not be empty")
+	}
+	f.Pattern = params
+	return nil
+}
+
+func (f *RepoHasDescriptionPredicate) Field() string { return FieldRepo }
+func (f *RepoHasDescriptionPredicate) Name() string  { return "has.description" }
+func (f *RepoHasDescriptionPredicate) Plan(parent Basic) (Plan, error) {
+	nodes := make([]Node, 0, 3)
+	nodes = append(nodes, Parameter{
+		Field: FieldCount,
+		Value: "99999",
+	}, Parameter{
+		Field: FieldRepoHasDescription,
+		Value: f.Pattern,
+	})
+
+	nodes = append(nodes, nonPredicateRepos(parent)...)
+	return ToPlan(Dnf(nodes))
+}
+
+/* repo:has.topic(name) */
+
+type RepoHasTopicPredicate struct {
+	Topic string
+}
+
+func (f *RepoHasTopicPredicate) ParseParams(params string) error {
+	if params == "" {
+		return errors.Errorf("has.topic argument should not be empty")
+	}
+	f.Topic = params
+	return nil
+}
+
+func (f *RepoHasTopicPredicate) Field() string { return FieldRepo }
+func (f *RepoHasTopicPredicate) Name() string  { return "has.topic" }
+func (f *RepoHasTopicPredicate) Plan(parent Basic) (Plan, error) {
+	nodes := make([]Node, 0, 3)
+	nodes = append(nodes, Parameter{
+		Field: FieldCount,
+		Value: "99999",
+	}, Parameter{
+		Field: FieldRepoHasTopic,
+		Value: f.Topic,
+	})
+
+	nodes = append(nodes, nonPredicateRepos(parent)...)
+	return ToPlan(Dnf(nodes))
+}
+
 type FileContainsContentPredicate struct {
 	Pattern string
 }
@@ -79,3 +79,75 @@ func TestParseAsPredicate(t *testing.T) {
 	}
 
 }
+
+func TestRepoHasDescriptionPredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		valid := []struct {
+			name     string
+			params   string
+			expected *RepoHasDescriptionPredicate
+		}{
+			{`literal`, `test`, &RepoHasDescriptionPredicate{Pattern: "test"}},
+			{`regexp`, `^code (search|intelligence)$`, &RepoHasDescriptionPredicate{Pattern: "^code (search|intelligence)$"}},
+		}
+
+		for _, tc := range valid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &RepoHasDescriptionPredicate{}
+				if err := p.ParseParams(tc.params); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !reflect.DeepEqual(tc.expected, p) {
+					t.Fatalf("expected %#v, got %#v", tc.expected, p)
+				}
+			})
+		}
+
+		for _, params := range []string{``, `(`} {
+			t.Run(params, func(t *testing.T) {
+				p := &RepoHasDescriptionPredicate{}
+				if err := p.ParseParams(params); err == nil {
+					t.Fatal("expected error but got none")
+				}
+			})
+		}
+	})
+}
+
+func TestRepoHasTopicPredicate(t *testing.T) {
+	t.Run("ParseParams", func(t *testing.T) {
+		p := &RepoHasTopicPredicate{}
+		if err := p.ParseParams("go"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if want := (&RepoHasTopicPredicate{Topic: "go"}); !reflect.DeepEqual(want, p) {
+			t.Fatalf("expected %#v, got %#v", want, p)
+		}
+
+		if err := (&RepoHasTopicPredicate{}).ParseParams(""); err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("Plan", func(t *testing.T) {
+		q, err := ParseLiteral("repo:has.topic(go) repo:sourcegraph archived:yes foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ToBasicQuery(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		plan, err := (&RepoHasTopicPredicate{Topic: "go"}).Plan(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := `(and "count:99999" "repohastopic:go" "repo:sourcegraph" "archived:yes")`
+		if got := plan.ToParseTree().String(); got != want {
+			t.Fatalf("unexpected plan:\nwant: %s\ngot:  %s", want, got)
+		}
+	})
+}
@@ -393,11 +393,12 @@ func (q Q) valueToTypedValue(field, value string, label labels) []*Value {
 		FieldContent:
 		return []*Value{{String: &value}}
 
-	case FieldRepoHasFile:
+	case FieldRepoHasFile, FieldRepoHasDescription:
 		return []*Value{{Regexp: parseRegexpOrPanic(field, value)}}
 
 	case
 		FieldRepoHasCommitAfter,
+		FieldRepoHasTopic,
 		FieldBefore, "until",
 		FieldAfter, "since":
 		return []*Value{{String: &value}}
@@ -308,6 +308,12 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldRepoHasCommitAfter:
 		return satisfies(isSingular, isNotNegated)
+	case
+		FieldRepoHasDescription:
+		return satisfies(isValidRegexp, isNotNegated)
+	case
+		FieldRepoHasTopic:
+		return satisfies(isNotNegated)
 	case
 		FieldBefore,
 		FieldAfter:
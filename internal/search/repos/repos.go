@@ -123,7 +123,10 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 
 	var searchableRepos []types.RepoName
 
-	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && !query.HasTypeRepo(op.Query) && searchcontexts.IsGlobalSearchContext(searchContext) {
+	// The list of searchable repositories isn't filtered by description or
+	// topic, so we only use it if neither filter is set.
+	hasMetadataFilters := len(op.DescriptionPatterns) > 0 || len(op.Topics) > 0
+	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && !hasMetadataFilters && !query.HasTypeRepo(op.Query) && searchcontexts.IsGlobalSearchContext(searchContext) {
 		start := time.Now()
 		searchableRepos, err = searchableRepositories(ctx, r.SearchableReposFunc, r.Zoekt, excludePatterns)
 		if err != nil {
@@ -152,13 +155,15 @@ func (r *Resolver) Resolve(ctx context.Context, op search.RepoOptions) (Resolved
 			Names:           versionContextRepositories,
 			ExcludePattern:  UnionRegExps(excludePatterns),
 			// List N+1 repos so we can see if there are repos omitted due to our repo limit.
-			LimitOffset:  &database.LimitOffset{Limit: limit + 1},
-			NoForks:      op.NoForks,
-			OnlyForks:    op.OnlyForks,
-			NoArchived:   op.NoArchived,
-			OnlyArchived: op.OnlyArchived,
-			NoPrivate:    op.OnlyPublic,
-			OnlyPrivate:  op.OnlyPrivate,
+			LimitOffset:         &database.LimitOffset{Limit: limit + 1},
+			NoForks:             op.NoForks,
+			OnlyForks:           op.OnlyForks,
+			NoArchived:          op.NoArchived,
+			OnlyArchived:        op.OnlyArchived,
+			NoPrivate:           op.OnlyPublic,
+			OnlyPrivate:         op.OnlyPrivate,
+			DescriptionPatterns: op.DescriptionPatterns,
+			Topics:              op.Topics,
 		}
 
 		if searchContext.ID != 0 {
@@ -451,6 +451,47 @@ func TestUseIndexableReposIfMissingOrGlobalSearchContext(t *testing.T) {
 	}
 }
 
+func TestResolveRepositoriesWithMetadataFiltersOnDotcom(t *testing.T) {
+	orig := envvar.SourcegraphDotComMode()
+	envvar.MockSourcegraphDotComMode(true)
+	defer envvar.MockSourcegraphDotComMode(orig)
+
+	queryInfo, err := query.ParseLiteral("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	database.Mocks.Repos.ListRepoNames = func(ctx context.Context, op database.ReposListOptions) ([]types.RepoName, error) {
+		if diff := cmp.Diff([]string{"go"}, op.Topics); diff != "" {
+			t.Errorf("unexpected topics (-want +got):\n%s", diff)
+		}
+		return []types.RepoName{{ID: 1, Name: "example.com/a"}}, nil
+	}
+	database.Mocks.Repos.Count = func(ctx context.Context, opt database.ReposListOptions) (int, error) {
+		return 0, nil
+	}
+	defer func() { database.Mocks = database.MockStores{} }()
+
+	// The searchable repositories aren't filtered by topic, so they must not
+	// be used.
+	mockSearchableReposFunc := func(_ context.Context) ([]types.RepoName, error) {
+		t.Fatal("searchable repositories used")
+		return nil, nil
+	}
+
+	repositoryResolver := &Resolver{SearchableReposFunc: mockSearchableReposFunc}
+	resolved, err := repositoryResolver.Resolve(context.Background(), search.RepoOptions{
+		Query:  queryInfo,
+		Topics: []string{"go"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved.RepoRevs) != 1 || resolved.RepoRevs[0].Repo.Name != "example.com/a" {
+		t.Fatalf("unexpected repositories: %+v", resolved.RepoRevs)
+	}
+}
+
 func TestResolveRepositoriesWithUserSearchContext(t *testing.T) {
 	db := dbtest.NewDB(t, *dsn)
 
@@ -57,6 +57,8 @@ func SearchRepositories(ctx context.Context, args *search.TextParameters, limit
 		query.FieldCase:               {},
 		query.FieldRepoHasFile:        {},
 		query.FieldRepoHasCommitAfter: {},
+		query.FieldRepoHasDescription: {},
+		query.FieldRepoHasTopic:       {},
 		query.FieldPatternType:        {},
 		query.FieldSelect:             {},
 	}
@@ -255,23 +255,25 @@ func (p *TextPatternInfo) String() string {
 }
 
 type RepoOptions struct {
-	RepoFilters        []string
-	MinusRepoFilters   []string
-	RepoGroupFilters   []string
-	SearchContextSpec  string
-	VersionContextName string
-	UserSettings       *schema.Settings
-	NoForks            bool
-	OnlyForks          bool
-	NoArchived         bool
-	OnlyArchived       bool
-	CommitAfter        string
-	OnlyPrivate        bool
-	OnlyPublic         bool
-	Ranked             bool // Return results ordered by rank
-	Limit              int
-	CacheLookup        bool
-	Query              query.Q
+	RepoFilters         []string
+	MinusRepoFilters    []string
+	RepoGroupFilters    []string
+	SearchContextSpec   string
+	VersionContextName  string
+	UserSettings        *schema.Settings
+	NoForks             bool
+	OnlyForks           bool
+	NoArchived          bool
+	OnlyArchived        bool
+	CommitAfter         string
+	DescriptionPatterns []string
+	Topics              []string
+	OnlyPrivate         bool
+	OnlyPublic          bool
+	Ranked              bool // Return results ordered by rank
+	Limit               int
+	CacheLookup         bool
+	Query               query.Q
 }
 
 func (op *RepoOptions) String() string {
@@ -298,6 +300,12 @@ func (op *RepoOptions) String() string {
 	if op.CommitAfter != "" {
 		_, _ = fmt.Fprintf(&b, " CommitAfter=%q", op.CommitAfter)
 	}
+	if len(op.DescriptionPatterns) > 0 {
+		_, _ = fmt.Fprintf(&b, " DescriptionPatterns=%q", op.DescriptionPatterns)
+	}
+	if len(op.Topics) > 0 {
+		_, _ = fmt.Fprintf(&b, " Topics=%q", op.Topics)
+	}
 
 	if op.NoForks {
 		b.WriteString(" NoForks")
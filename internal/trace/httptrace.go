@@ -34,6 +34,7 @@ const (
 	originKey
 	sourceKey
 	GraphQLQueryKey
+	requestClientKey
 )
 
 // trackOrigin specifies a URL value. When an incoming request has the request header "Origin" set
@@ -120,6 +121,29 @@ func RequestSource(ctx context.Context) SourceType {
 	return v.(SourceType)
 }
 
+// RequestClient describes the client that sent a request.
+type RequestClient struct {
+	// RemoteAddr is the network address of the client, or of the last proxy
+	// in front of Sourcegraph.
+	RemoteAddr string
+	// ForwardedFor is the value of the X-Forwarded-For header of the request.
+	ForwardedFor string
+	// UserAgent is the value of the User-Agent header of the request.
+	UserAgent string
+}
+
+// WithRequestClient sets the client of the request in the context.
+func WithRequestClient(ctx context.Context, client *RequestClient) context.Context {
+	return context.WithValue(ctx, requestClientKey, client)
+}
+
+// RequestClientFromContext returns the client of the request for a request context, or nil if
+// the context is not a request context.
+func RequestClientFromContext(ctx context.Context) *RequestClient {
+	client, _ := ctx.Value(requestClientKey).(*RequestClient)
+	return client
+}
+
 // HTTPTraceMiddleware captures and exports metrics to Prometheus, etc.
 //
 // 🚨 SECURITY: This handler is served to all clients, even on private servers to clients who have
@@ -159,6 +183,12 @@ func HTTPTraceMiddleware(next http.Handler) http.Handler {
 		}
 		ctx = WithRequestOrigin(ctx, origin)
 
+		ctx = WithRequestClient(ctx, &RequestClient{
+			RemoteAddr:   r.RemoteAddr,
+			ForwardedFor: r.Header.Get("X-Forwarded-For"),
+			UserAgent:    r.UserAgent(),
+		})
+
 		m := httpsnoop.CaptureMetrics(next, rw, r.WithContext(ctx))
 
 		if routeName == "graphql" {
@@ -56,7 +56,15 @@ func (e *ExternalService) RedactConfigSecrets() error {
 		}
 		newCfg, err = redactField(e.Config, fields...)
 	case *schema.BitbucketCloudConnection:
-		newCfg, err = redactField(e.Config, "appPassword")
+		fields := []string{"appPassword"}
+		if cfg.WebhookSecret != "" {
+			fields = append(fields, "webhookSecret")
+		}
+		newCfg, err = redactField(e.Config, fields...)
+	case *schema.GerritConnection:
+		newCfg, err = redactField(e.Config, "password")
+	case *schema.GiteaConnection:
+		newCfg, err = redactField(e.Config, "token")
 	case *schema.AWSCodeCommitConnection:
 		newCfg, err = redactField(e.Config, "secretAccessKey")
 	case *schema.PhabricatorConnection:
@@ -133,7 +141,15 @@ func (e *ExternalService) UnredactConfig(old *ExternalService) error {
 		}
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, fields...)
 	case *schema.BitbucketCloudConnection:
-		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"appPassword", &cfg.AppPassword})
+		fields := []jsonStringField{{"appPassword", &cfg.AppPassword}}
+		if cfg.WebhookSecret != "" {
+			fields = append(fields, jsonStringField{"webhookSecret", &cfg.WebhookSecret})
+		}
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, fields...)
+	case *schema.GerritConnection:
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"password", &cfg.Password})
+	case *schema.GiteaConnection:
+		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"token", &cfg.Token})
 	case *schema.AWSCodeCommitConnection:
 		unredacted, err = unredactField(old.Config, e.Config, &cfg, jsonStringField{"secretAccessKey", &cfg.SecretAccessKey})
 	case *schema.PhabricatorConnection:
@@ -24,6 +24,11 @@ func TestRoundTripRedactExternalServiceConfig(t *testing.T) {
 		AppPassword: someSecret,
 		Url:         "https://bitbucket.com",
 	}
+	bitbucketCloudConfigWithWebhookSecret := schema.BitbucketCloudConnection{
+		AppPassword:   someSecret,
+		WebhookSecret: someSecret,
+		Url:           "https://bitbucket.com",
+	}
 	bitbucketServerConfigWithPassword := schema.BitbucketServerConnection{
 		Password: someSecret,
 		Url:      "https://bitbucket.com",
@@ -75,6 +80,12 @@ func TestRoundTripRedactExternalServiceConfig(t *testing.T) {
 			editField:   &bitbucketCloudConfig.Url,
 			secretField: &bitbucketCloudConfig.AppPassword,
 		},
+		{
+			kind:        extsvc.KindBitbucketCloud,
+			config:      &bitbucketCloudConfigWithWebhookSecret,
+			editField:   &bitbucketCloudConfigWithWebhookSecret.Url,
+			secretField: &bitbucketCloudConfigWithWebhookSecret.WebhookSecret,
+		},
 		// BitbucketServer can have a password OR token, not both
 		{
 			kind:        extsvc.KindBitbucketServer,
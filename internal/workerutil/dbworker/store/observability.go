@@ -3,6 +3,8 @@ package store
 import (
 	"fmt"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
@@ -18,6 +20,8 @@ type operations struct {
 	markFailed              *observation.Operation
 	resetStalled            *observation.Operation
 	heartbeat               *observation.Operation
+
+	dequeuedByLane *prometheus.CounterVec
 }
 
 func newOperations(storeName string, observationContext *observation.Context) *operations {
@@ -36,6 +40,12 @@ func newOperations(storeName string, observationContext *observation.Context) *o
 		})
 	}
 
+	dequeuedByLane := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: fmt.Sprintf("src_workerutil_dbworker_store_%s_dequeued_total", storeName),
+		Help: "Total number of records dequeued, by priority lane.",
+	}, []string{"lane"})
+	observationContext.Registerer.MustRegister(dequeuedByLane)
+
 	return &operations{
 		queuedCount:             op("QueuedCount"),
 		dequeue:                 op("Dequeue"),
@@ -47,5 +57,7 @@ func newOperations(storeName string, observationContext *observation.Context) *o
 		markFailed:              op("MarkFailed"),
 		resetStalled:            op("ResetStalled"),
 		heartbeat:               op("Heartbeat"),
+
+		dequeuedByLane: dequeuedByLane,
 	}
 }
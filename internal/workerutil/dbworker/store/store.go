@@ -238,10 +238,46 @@ type Options struct {
 	// Setting this value to zero will disable retries entirely.
 	MaxNumRetries int
 
+	// Lanes optionally partitions the records into lanes of decreasing priority. A record belongs to
+	// the first lane whose condition it satisfies, and records of a lane are only dequeued when no
+	// record of a lane before it can be dequeued. Records that satisfy none of the conditions belong
+	// to an implicit lowest priority lane named `DefaultLaneName`. The number of records dequeued
+	// from each lane is emitted as a metric.
+	Lanes []Lane
+
+	// FairnessKeyExpression is an optional SQL expression that groups records, for example by the
+	// repository they belong to. If supplied, dequeues round-robin across the groups within a lane:
+	// the next record is taken from the group whose most recent record was started longest ago, so
+	// that a group with many queued records cannot starve the others. Groups that have not had a
+	// record started within `FairnessWindow` come first. Ties are broken by `OrderByExpression`,
+	// which also orders the records within a group. This expression may use the alias provided in
+	// `ViewName`, if one was supplied.
+	FairnessKeyExpression *sqlf.Query
+
+	// FairnessWindow is how far back the start times of records are considered when choosing the
+	// group to dequeue from next. Defaults to DefaultFairnessWindow.
+	FairnessWindow time.Duration
+
 	// clock is used to mock out the wall clock used for heartbeat updates.
 	clock glock.Clock
 }
 
+// Lane is a named subset of records that are dequeued with the same priority.
+type Lane struct {
+	// Name identifies the lane in emitted metrics.
+	Name string
+
+	// Condition is the SQL expression that holds for records in this lane. This expression may
+	// use the alias provided in `ViewName`, if one was supplied.
+	Condition *sqlf.Query
+}
+
+// DefaultLaneName is the name of the lane of records that do not belong to any configured lane.
+const DefaultLaneName = "default"
+
+// DefaultFairnessWindow is the default value of Options.FairnessWindow.
+const DefaultFairnessWindow = time.Hour
+
 // RecordScanFn is a function that interprets row values as a particular record. This function should
 // return a false-valued flag if the given result set was empty. This function must close the rows
 // value if the given error value is nil.
@@ -271,6 +307,10 @@ func newStore(handle *basestore.TransactableHandle, options Options, observation
 		options.clock = glock.NewRealClock()
 	}
 
+	if options.FairnessWindow == 0 {
+		options.FairnessWindow = DefaultFairnessWindow
+	}
+
 	alternateColumnNames := map[string]string{}
 	for _, column := range columns {
 		alternateColumnNames[column.name] = column.name
@@ -365,8 +405,10 @@ func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions [
 	now := s.now()
 
 	// Select and "lock" candidate record
-	id, exists, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
+	id, lane, exists, err := scanFirstCandidate(s.Query(ctx, s.formatQuery(
 		selectCandidateQuery,
+		s.fairnessCTE(now),
+		s.laneExpression(),
 		quote(s.options.ViewName),
 		now,
 		int(s.options.RetryAfter/time.Second),
@@ -374,7 +416,7 @@ func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions [
 		int(s.options.RetryAfter/time.Second),
 		s.options.MaxNumRetries,
 		makeConditionSuffix(conditions),
-		s.options.OrderByExpression,
+		s.orderByExpression(),
 		quote(s.options.TableName),
 		now,
 		now,
@@ -386,7 +428,8 @@ func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions [
 	if !exists {
 		return nil, false, nil
 	}
-	traceLog(log.Int("id", id))
+	traceLog(log.Int("id", id), log.String("lane", lane))
+	s.operations.dequeuedByLane.WithLabelValues(lane).Inc()
 
 	// Scan the actual record after updating its state
 	record, exists, err := s.options.Scan(s.Query(ctx, s.formatQuery(
@@ -407,8 +450,9 @@ func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions [
 
 const selectCandidateQuery = `
 -- source: internal/workerutil/store.go:Dequeue
-WITH candidate AS (
-	SELECT {id} FROM %s
+WITH %s
+candidate AS (
+	SELECT {id}, (%s)::text AS lane FROM %s
 	WHERE
 		(
 			(
@@ -436,9 +480,86 @@ SET
 	{execution_logs} = NULL,
 	{worker_hostname} = %s
 WHERE {id} IN (SELECT {id} FROM candidate)
-RETURNING {id}
+RETURNING {id}, (SELECT lane FROM candidate)
+`
+
+const fairnessCTE = `
+fairness AS (
+	SELECT %s AS fairness_key, MAX({started_at}) AS last_started_at
+	FROM %s
+	WHERE {started_at} > %s
+	GROUP BY 1
+),
 `
 
+// fairnessCTE returns the common table expression that computes the last time a record of each
+// fairness group was started, or an empty query if no fairness key is configured.
+func (s *store) fairnessCTE(now time.Time) *sqlf.Query {
+	if s.options.FairnessKeyExpression == nil {
+		return sqlf.Sprintf("")
+	}
+
+	return s.formatQuery(
+		fairnessCTE,
+		s.options.FairnessKeyExpression,
+		quote(s.options.ViewName),
+		now.Add(-s.options.FairnessWindow),
+	)
+}
+
+// laneExpression returns a SQL expression that evaluates to the name of the lane of a record.
+func (s *store) laneExpression() *sqlf.Query {
+	if len(s.options.Lanes) == 0 {
+		return sqlf.Sprintf("%s", DefaultLaneName)
+	}
+
+	cases := make([]*sqlf.Query, 0, len(s.options.Lanes))
+	for _, lane := range s.options.Lanes {
+		cases = append(cases, sqlf.Sprintf("WHEN %s THEN %s", lane.Condition, lane.Name))
+	}
+	return sqlf.Sprintf("CASE %s ELSE %s END", sqlf.Join(cases, " "), DefaultLaneName)
+}
+
+// orderByExpression returns the SQL expression used to order candidate records: by lane, then by
+// the last time a record of the same fairness group was started, then by OrderByExpression.
+func (s *store) orderByExpression() *sqlf.Query {
+	var expressions []*sqlf.Query
+
+	if len(s.options.Lanes) > 0 {
+		cases := make([]*sqlf.Query, 0, len(s.options.Lanes))
+		for i, lane := range s.options.Lanes {
+			cases = append(cases, sqlf.Sprintf("WHEN %s THEN %s::integer", lane.Condition, i))
+		}
+		expressions = append(expressions, sqlf.Sprintf("CASE %s ELSE %s::integer END", sqlf.Join(cases, " "), len(s.options.Lanes)))
+	}
+
+	if s.options.FairnessKeyExpression != nil {
+		expressions = append(expressions, sqlf.Sprintf(
+			"(SELECT fairness.last_started_at FROM fairness WHERE fairness.fairness_key = %s) ASC NULLS FIRST",
+			s.options.FairnessKeyExpression,
+		))
+	}
+
+	return sqlf.Join(append(expressions, s.options.OrderByExpression), ", ")
+}
+
+// scanFirstCandidate scans the identifier and lane of the record selected by selectCandidateQuery.
+func scanFirstCandidate(rows *sql.Rows, queryErr error) (id int, lane string, exists bool, err error) {
+	if queryErr != nil {
+		return 0, "", false, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	if rows.Next() {
+		if err := rows.Scan(&id, &lane); err != nil {
+			return 0, "", false, err
+		}
+		return id, lane, true, nil
+	}
+
+	return 0, "", false, nil
+}
+
 const selectRecordQuery = `
 -- source: internal/workerutil/store.go:Dequeue
 SELECT %s FROM %s WHERE {id} = %s
@@ -149,6 +149,61 @@ func TestStoreDequeueConditions(t *testing.T) {
 	assertDequeueRecordResult(t, 3, record, ok, err)
 }
 
+func TestStoreDequeueLanes(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(1, 'queued', NOW() - '5 minute'::interval),
+			(2, 'queued', NOW() - '4 minute'::interval),
+			(3, 'queued', NOW() - '3 minute'::interval),
+			(4, 'queued', NOW() - '2 minute'::interval),
+			(5, 'queued', NOW() - '1 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.Lanes = []Lane{
+		{Name: "high", Condition: sqlf.Sprintf("w.id = 4")},
+		{Name: "medium", Condition: sqlf.Sprintf("w.id IN (2, 3)")},
+	}
+	store := testStore(db, options)
+
+	for _, expectedID := range []int{4, 2, 3, 1, 5} {
+		record, ok, err := store.Dequeue(context.Background(), "test", nil)
+		assertDequeueRecordResult(t, expectedID, record, ok, err)
+	}
+}
+
+func TestStoreDequeueFairness(t *testing.T) {
+	db := setupStoreTest(t)
+
+	// Records 10-12 belong to one group and record 20 to another
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(10, 'queued', NOW() - '5 minute'::interval),
+			(11, 'queued', NOW() - '4 minute'::interval),
+			(12, 'queued', NOW() - '3 minute'::interval),
+			(20, 'queued', NOW() - '1 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	clock := glock.NewMockClockAt(time.Now())
+	options := defaultTestStoreOptions(clock)
+	options.FairnessKeyExpression = sqlf.Sprintf("w.id / 10")
+	store := testStore(db, options)
+
+	for _, expectedID := range []int{10, 20, 11, 12} {
+		record, ok, err := store.Dequeue(context.Background(), "test", nil)
+		assertDequeueRecordResult(t, expectedID, record, ok, err)
+		clock.Advance(time.Second)
+	}
+}
+
 func TestStoreDequeueResetExecutionLogs(t *testing.T) {
 	db := setupStoreTest(t)
 
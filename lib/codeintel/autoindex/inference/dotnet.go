@@ -0,0 +1,88 @@
+package inference
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func DotNetPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		extensionPattern(rawPattern("sln")),
+		extensionPattern(rawPattern("csproj")),
+	}
+}
+
+func CanIndexDotNetRepo(gitclient GitClient, paths []string) bool {
+	for _, path := range paths {
+		if isDotNetSolutionPath(path) || isDotNetProjectPath(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const lsifDotNetImage = "sourcegraph/lsif-dotnet:autoindex"
+
+// InferDotNetIndexJobs emits an index job for each solution file, as well as for
+// each project file that is not in or beneath a directory containing a solution.
+// Such projects are assumed to be referenced by that solution.
+func InferDotNetIndexJobs(gitclient GitClient, paths []string) (indexes []config.IndexJob) {
+	solutionDirs := map[string]struct{}{}
+	for _, path := range paths {
+		if isDotNetSolutionPath(path) {
+			solutionDirs[dirWithoutDot(path)] = struct{}{}
+		}
+	}
+
+	for _, path := range paths {
+		if isDotNetSolutionPath(path) {
+			indexes = append(indexes, dotNetIndexJob(path))
+		}
+	}
+
+	for _, path := range paths {
+		if !isDotNetProjectPath(path) {
+			continue
+		}
+
+		if _, ok := solutionDirs[dirWithoutDot(path)]; ok || hasAncestorIn(dirWithoutDot(path), solutionDirs) {
+			continue
+		}
+
+		indexes = append(indexes, dotNetIndexJob(path))
+	}
+
+	return indexes
+}
+
+func dotNetIndexJob(path string) config.IndexJob {
+	root := dirWithoutDot(path)
+	file := filepath.Base(path)
+
+	return config.IndexJob{
+		Steps: []config.DockerStep{
+			{
+				Root:     root,
+				Image:    lsifDotNetImage,
+				Commands: []string{"dotnet restore " + file},
+			},
+		},
+		Root:        root,
+		Indexer:     lsifDotNetImage,
+		IndexerArgs: []string{"lsif-dotnet", file, "--output", "dump.lsif"},
+		Outfile:     "dump.lsif",
+	}
+}
+
+var dotNetSegmentBlockList = append([]string{"bin", "obj", "packages"}, segmentBlockList...)
+
+func isDotNetSolutionPath(path string) bool {
+	return filepath.Ext(path) == ".sln" && containsNoSegments(path, dotNetSegmentBlockList...)
+}
+
+func isDotNetProjectPath(path string) bool {
+	return filepath.Ext(path) == ".csproj" && containsNoSegments(path, dotNetSegmentBlockList...)
+}
@@ -0,0 +1,145 @@
+package inference
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestDotNetPatterns(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"App.sln", true},
+		{"src/App/App.csproj", true},
+		{"src/App/App.fsproj", false},
+		{"App.sln.DotSettings", false},
+	}
+
+	for _, testCase := range testCases {
+		match := false
+		for _, pattern := range DotNetPatterns() {
+			if pattern.MatchString(testCase.path) {
+				match = true
+				break
+			}
+		}
+
+		if match {
+			if !testCase.expected {
+				t.Error(fmt.Sprintf("did not expect match: %s", testCase.path))
+			}
+		} else if testCase.expected {
+			t.Error(fmt.Sprintf("expected match: %s", testCase.path))
+		}
+	}
+}
+
+func TestCanIndexDotNetRepo(t *testing.T) {
+	testCases := []struct {
+		paths    []string
+		expected bool
+	}{
+		{paths: []string{"App.sln"}, expected: true},
+		{paths: []string{"src/App/App.csproj"}, expected: true},
+		{paths: []string{"packages/Foo/Foo.csproj"}, expected: false},
+		{paths: []string{"src/App/obj/App.csproj"}, expected: false},
+		{paths: []string{"App.sln.DotSettings"}, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		name := strings.Join(testCase.paths, ", ")
+
+		t.Run(name, func(t *testing.T) {
+			if value := CanIndexDotNetRepo(NewMockGitClient(), testCase.paths); value != testCase.expected {
+				t.Errorf("unexpected result from CanIndex. want=%v have=%v", testCase.expected, value)
+			}
+		})
+	}
+}
+
+func TestInferDotNetIndexJobs(t *testing.T) {
+	paths := []string{
+		"App.sln",
+		"src/App/App.csproj",
+		"src/App.Core/App.Core.csproj",
+		"tools/Tool.sln",
+		"tools/Tool.csproj",
+	}
+
+	expectedIndexJobs := []config.IndexJob{
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "",
+					Image:    lsifDotNetImage,
+					Commands: []string{"dotnet restore App.sln"},
+				},
+			},
+			Root:        "",
+			Indexer:     lsifDotNetImage,
+			IndexerArgs: []string{"lsif-dotnet", "App.sln", "--output", "dump.lsif"},
+			Outfile:     "dump.lsif",
+		},
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "tools",
+					Image:    lsifDotNetImage,
+					Commands: []string{"dotnet restore Tool.sln"},
+				},
+			},
+			Root:        "tools",
+			Indexer:     lsifDotNetImage,
+			IndexerArgs: []string{"lsif-dotnet", "Tool.sln", "--output", "dump.lsif"},
+			Outfile:     "dump.lsif",
+		},
+	}
+	if diff := cmp.Diff(expectedIndexJobs, InferDotNetIndexJobs(NewMockGitClient(), paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestInferDotNetIndexJobsProjectsWithoutSolution(t *testing.T) {
+	paths := []string{
+		"src/App/App.csproj",
+		"src/Lib/Lib.csproj",
+	}
+
+	expectedIndexJobs := []config.IndexJob{
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "src/App",
+					Image:    lsifDotNetImage,
+					Commands: []string{"dotnet restore App.csproj"},
+				},
+			},
+			Root:        "src/App",
+			Indexer:     lsifDotNetImage,
+			IndexerArgs: []string{"lsif-dotnet", "App.csproj", "--output", "dump.lsif"},
+			Outfile:     "dump.lsif",
+		},
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "src/Lib",
+					Image:    lsifDotNetImage,
+					Commands: []string{"dotnet restore Lib.csproj"},
+				},
+			},
+			Root:        "src/Lib",
+			Indexer:     lsifDotNetImage,
+			IndexerArgs: []string{"lsif-dotnet", "Lib.csproj", "--output", "dump.lsif"},
+			Outfile:     "dump.lsif",
+		},
+	}
+	if diff := cmp.Diff(expectedIndexJobs, InferDotNetIndexJobs(NewMockGitClient(), paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
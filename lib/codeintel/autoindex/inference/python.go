@@ -0,0 +1,134 @@
+package inference
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func PythonPatterns() []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, filename := range pythonProjectFilenames {
+		patterns = append(patterns, pathPattern(rawPattern(filename)))
+	}
+	patterns = append(patterns, pathPattern(rawPattern(pythonRequirementsFilename)))
+	return patterns
+}
+
+func CanIndexPythonRepo(gitclient GitClient, paths []string) bool {
+	for _, path := range paths {
+		if isPythonProjectPath(path) || isPythonRequirementsPath(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const lsifPyImage = "sourcegraph/lsif-py:autoindex"
+
+// InferPythonIndexJobs emits an index job for each directory containing a
+// pyproject.toml or setup.py file. Directories that only contain a
+// requirements.txt file are used as roots only when there are no such project
+// files. Roots nested in another root are skipped, as the indexer already
+// covers the files of the outer project.
+func InferPythonIndexJobs(gitclient GitClient, paths []string) (indexes []config.IndexJob) {
+	roots := pythonRoots(paths, isPythonProjectPath)
+	if len(roots) == 0 {
+		roots = pythonRoots(paths, isPythonRequirementsPath)
+	}
+
+	for _, root := range roots {
+		var commands []string
+		if contains(paths, filepath.Join(root, pythonRequirementsFilename)) {
+			commands = append(commands, "pip install -r "+pythonRequirementsFilename)
+		}
+		for _, filename := range pythonProjectFilenames {
+			if contains(paths, filepath.Join(root, filename)) {
+				commands = append(commands, "pip install .")
+				break
+			}
+		}
+
+		indexes = append(indexes, config.IndexJob{
+			Steps: []config.DockerStep{
+				{
+					Root:     root,
+					Image:    lsifPyImage,
+					Commands: commands,
+				},
+			},
+			Root:        root,
+			Indexer:     lsifPyImage,
+			IndexerArgs: []string{"lsif-py", "."},
+			Outfile:     "",
+		})
+	}
+
+	return indexes
+}
+
+// pythonRoots returns the directories of the paths matching the given predicate,
+// excluding directories nested within another returned directory.
+func pythonRoots(paths []string, match func(path string) bool) (roots []string) {
+	candidates := map[string]struct{}{}
+	for _, path := range paths {
+		if match(path) {
+			candidates[dirWithoutDot(path)] = struct{}{}
+		}
+	}
+
+	for _, path := range paths {
+		if !match(path) {
+			continue
+		}
+
+		root := dirWithoutDot(path)
+		if contains(roots, root) || hasAncestorIn(root, candidates) {
+			continue
+		}
+		roots = append(roots, root)
+	}
+
+	return roots
+}
+
+// hasAncestorIn returns true if a proper ancestor of the given directory is
+// present in the given set.
+func hasAncestorIn(dir string, dirs map[string]struct{}) bool {
+	if dir == "" {
+		return false
+	}
+
+	for _, ancestor := range ancestorDirs(dir) {
+		if _, ok := dirs[ancestor]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+const pythonRequirementsFilename = "requirements.txt"
+
+var pythonProjectFilenames = []string{
+	"pyproject.toml",
+	"setup.py",
+}
+
+var pythonSegmentBlockList = append([]string{"venv", ".venv", "site-packages"}, segmentBlockList...)
+
+func isPythonProjectPath(path string) bool {
+	for _, filename := range pythonProjectFilenames {
+		if filepath.Base(path) == filename {
+			return containsNoSegments(path, pythonSegmentBlockList...)
+		}
+	}
+
+	return false
+}
+
+func isPythonRequirementsPath(path string) bool {
+	return filepath.Base(path) == pythonRequirementsFilename && containsNoSegments(path, pythonSegmentBlockList...)
+}
@@ -0,0 +1,177 @@
+package inference
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func TestPythonPatterns(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"pyproject.toml", true},
+		{"setup.py", true},
+		{"requirements.txt", true},
+		{"subdir/setup.py", true},
+		{"subdir/dev-requirements.txt", false},
+		{"setup.py/subdir", false},
+	}
+
+	for _, testCase := range testCases {
+		match := false
+		for _, pattern := range PythonPatterns() {
+			if pattern.MatchString(testCase.path) {
+				match = true
+				break
+			}
+		}
+
+		if match {
+			if !testCase.expected {
+				t.Error(fmt.Sprintf("did not expect match: %s", testCase.path))
+			}
+		} else if testCase.expected {
+			t.Error(fmt.Sprintf("expected match: %s", testCase.path))
+		}
+	}
+}
+
+func TestCanIndexPythonRepo(t *testing.T) {
+	testCases := []struct {
+		paths    []string
+		expected bool
+	}{
+		{paths: []string{"pyproject.toml"}, expected: true},
+		{paths: []string{"a/setup.py"}, expected: true},
+		{paths: []string{"requirements.txt"}, expected: true},
+		{paths: []string{".venv/lib/site-packages/foo/setup.py"}, expected: false},
+		{paths: []string{"tests/requirements.txt"}, expected: false},
+		{paths: []string{"foo/bar-setup.py"}, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		name := strings.Join(testCase.paths, ", ")
+
+		t.Run(name, func(t *testing.T) {
+			if value := CanIndexPythonRepo(NewMockGitClient(), testCase.paths); value != testCase.expected {
+				t.Errorf("unexpected result from CanIndex. want=%v have=%v", testCase.expected, value)
+			}
+		})
+	}
+}
+
+func TestInferPythonIndexJobsProjects(t *testing.T) {
+	paths := []string{
+		"pyproject.toml",
+		"requirements.txt",
+		"vendored/setup.py",
+		"services/a/setup.py",
+		"services/a/requirements.txt",
+		"services/b/requirements.txt",
+	}
+
+	expectedIndexJobs := []config.IndexJob{
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "",
+					Image:    lsifPyImage,
+					Commands: []string{"pip install -r requirements.txt", "pip install ."},
+				},
+			},
+			Root:        "",
+			Indexer:     lsifPyImage,
+			IndexerArgs: []string{"lsif-py", "."},
+			Outfile:     "",
+		},
+	}
+	if diff := cmp.Diff(expectedIndexJobs, InferPythonIndexJobs(NewMockGitClient(), paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestInferPythonIndexJobsSubdirs(t *testing.T) {
+	paths := []string{
+		"a/pyproject.toml",
+		"b/setup.py",
+		"b/requirements.txt",
+		"c/requirements.txt",
+	}
+
+	expectedIndexJobs := []config.IndexJob{
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "a",
+					Image:    lsifPyImage,
+					Commands: []string{"pip install ."},
+				},
+			},
+			Root:        "a",
+			Indexer:     lsifPyImage,
+			IndexerArgs: []string{"lsif-py", "."},
+			Outfile:     "",
+		},
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "b",
+					Image:    lsifPyImage,
+					Commands: []string{"pip install -r requirements.txt", "pip install ."},
+				},
+			},
+			Root:        "b",
+			Indexer:     lsifPyImage,
+			IndexerArgs: []string{"lsif-py", "."},
+			Outfile:     "",
+		},
+	}
+	if diff := cmp.Diff(expectedIndexJobs, InferPythonIndexJobs(NewMockGitClient(), paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestInferPythonIndexJobsRequirementsOnly(t *testing.T) {
+	paths := []string{
+		"scripts/requirements.txt",
+		"tools/requirements.txt",
+	}
+
+	expectedIndexJobs := []config.IndexJob{
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "scripts",
+					Image:    lsifPyImage,
+					Commands: []string{"pip install -r requirements.txt"},
+				},
+			},
+			Root:        "scripts",
+			Indexer:     lsifPyImage,
+			IndexerArgs: []string{"lsif-py", "."},
+			Outfile:     "",
+		},
+		{
+			Steps: []config.DockerStep{
+				{
+					Root:     "tools",
+					Image:    lsifPyImage,
+					Commands: []string{"pip install -r requirements.txt"},
+				},
+			},
+			Root:        "tools",
+			Indexer:     lsifPyImage,
+			IndexerArgs: []string{"lsif-py", "."},
+			Outfile:     "",
+		},
+	}
+	if diff := cmp.Diff(expectedIndexJobs, InferPythonIndexJobs(NewMockGitClient(), paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
@@ -25,9 +25,12 @@ type IndexJobRecognizer interface {
 
 // Recognizers is a list of registered index job recognizers.
 var Recognizers = map[string]IndexJobRecognizer{
-	"go":   recognizer{GoPatterns, CanIndexGoRepo, InferGoIndexJobs},
-	"tsc":  recognizer{TypeScriptPatterns, CanIndexTypeScriptRepo, InferTypeScriptIndexJobs},
-	"java": recognizer{JavaPatterns, CanIndexJavaRepo, InferJavaIndexJobs},
+	"go":     recognizer{GoPatterns, CanIndexGoRepo, InferGoIndexJobs},
+	"tsc":    recognizer{TypeScriptPatterns, CanIndexTypeScriptRepo, InferTypeScriptIndexJobs},
+	"java":   recognizer{JavaPatterns, CanIndexJavaRepo, InferJavaIndexJobs},
+	"python": recognizer{PythonPatterns, CanIndexPythonRepo, InferPythonIndexJobs},
+	"rust":   recognizer{RustPatterns, CanIndexRustRepo, InferRustIndexJobs},
+	"dotnet": recognizer{DotNetPatterns, CanIndexDotNetRepo, InferDotNetIndexJobs},
 }
 
 type recognizer struct {
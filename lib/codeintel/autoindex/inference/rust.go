@@ -0,0 +1,86 @@
+package inference
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+)
+
+func RustPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		pathPattern(rawPattern("Cargo.toml")),
+	}
+}
+
+func CanIndexRustRepo(gitclient GitClient, paths []string) bool {
+	for _, path := range paths {
+		if isCargoManifestPath(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const lsifRustImage = "sourcegraph/lsif-rust:autoindex"
+
+// InferRustIndexJobs emits an index job for each Cargo workspace, as well as for
+// each crate that does not belong to a workspace. Crates nested under the root
+// of a workspace are indexed along with the rest of the workspace. The indexer
+// writes its output to dump.lsif in the root.
+func InferRustIndexJobs(gitclient GitClient, paths []string) (indexes []config.IndexJob) {
+	workspaces := map[string]struct{}{}
+	for _, path := range paths {
+		if isCargoManifestPath(path) && isCargoWorkspace(gitclient, path) {
+			workspaces[dirWithoutDot(path)] = struct{}{}
+		}
+	}
+
+	for _, path := range paths {
+		if !isCargoManifestPath(path) {
+			continue
+		}
+
+		root := dirWithoutDot(path)
+		if hasAncestorIn(root, workspaces) {
+			continue
+		}
+
+		indexes = append(indexes, config.IndexJob{
+			Steps: []config.DockerStep{
+				{
+					Root:     root,
+					Image:    lsifRustImage,
+					Commands: []string{"cargo fetch"},
+				},
+			},
+			Root:        root,
+			Indexer:     lsifRustImage,
+			IndexerArgs: []string{"lsif-rust", "index"},
+			Outfile:     "dump.lsif",
+		})
+	}
+
+	return indexes
+}
+
+var cargoWorkspacePattern = regexp.MustCompile(`(?m)^\s*\[workspace\]`)
+
+// isCargoWorkspace returns true if the Cargo.toml file at the given path declares
+// a workspace.
+func isCargoWorkspace(gitclient GitClient, path string) bool {
+	b, err := gitclient.RawContents(context.TODO(), path)
+	if err != nil {
+		return false
+	}
+
+	return cargoWorkspacePattern.Match(b)
+}
+
+var rustSegmentBlockList = append([]string{"target", "vendor"}, segmentBlockList...)
+
+func isCargoManifestPath(path string) bool {
+	return filepath.Base(path) == "Cargo.toml" && containsNoSegments(path, rustSegmentBlockList...)
+}
@@ -8,8 +8,8 @@ import (
 
 // canonicalize deduplicates data in the raw correlation state and collapses range,
 // result set, and moniker data that form chains via next edges.
-func canonicalize(state *State) {
-	fns := []func(state *State){
+func canonicalize(state *State) error {
+	fns := []func(state *State) error{
 		canonicalizeDocuments,
 		canonicalizeReferenceResults,
 		canonicalizeResultSets,
@@ -17,8 +17,12 @@ func canonicalize(state *State) {
 	}
 
 	for _, fn := range fns {
-		fn(state)
+		if err := fn(state); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 // canonicalizeDocuments determines if multiple documents are defined with the same URI. This can
@@ -27,7 +31,7 @@ func canonicalize(state *State) {
 // be the canonical representative and merge the contains, definition, and reference data into the
 // unique canonical document. This function guarantees that duplicate document IDs are removed from
 // the correlation state.
-func canonicalizeDocuments(state *State) {
+func canonicalizeDocuments(state *State) error {
 	documentIDs := map[string][]int{}
 	for documentID, uri := range state.DocumentData {
 		documentIDs[uri] = append(documentIDs[uri], documentID)
@@ -36,83 +40,138 @@ func canonicalizeDocuments(state *State) {
 		sort.Ints(v)
 	}
 
+	canonicalIDs := map[int]int{}
 	for documentID, uri := range state.DocumentData {
 		// Choose canonical document alphabetically
 		if canonicalID := documentIDs[uri][0]; documentID != canonicalID {
-			// Move ranges and diagnostics into the canonical document
-			state.Contains.SetUnion(canonicalID, state.Contains.Get(documentID))
-			state.Diagnostics.SetUnion(canonicalID, state.Diagnostics.Get(documentID))
+			canonicalIDs[documentID] = canonicalID
+		}
+	}
+	if len(canonicalIDs) == 0 {
+		return nil
+	}
 
-			canonicalizeDocumentsInDefinitionReferences(state, state.DefinitionData, documentID, canonicalID)
-			canonicalizeDocumentsInDefinitionReferences(state, state.ReferenceData, documentID, canonicalID)
+	for documentID, canonicalID := range canonicalIDs {
+		// Move ranges and diagnostics into the canonical document
+		state.Contains.SetUnion(canonicalID, state.Contains.Get(documentID))
+		state.Diagnostics.SetUnion(canonicalID, state.Diagnostics.Get(documentID))
 
-			// Remove non-canonical document
-			delete(state.DocumentData, documentID)
-			state.Contains.Delete(documentID)
-			state.Diagnostics.Delete(documentID)
+		// Remove non-canonical document
+		delete(state.DocumentData, documentID)
+		state.Contains.Delete(documentID)
+		state.Diagnostics.Delete(documentID)
+	}
+
+	for _, kind := range resultKinds {
+		for _, id := range state.resultIDs(kind) {
+			if err := state.updateResult(kind, id, func(documentRanges *datastructures.DefaultIDSetMap) bool {
+				return canonicalizeDocumentsInDefinitionReferences(documentRanges, canonicalIDs)
+			}); err != nil {
+				return err
+			}
 		}
 	}
+
+	return nil
 }
 
-// canonicalizeDocumentsInDefinitionReferences moves definition or reference result data from the
-// given document to the given canonical document and removes all references to the non-canonical
-// document.
-func canonicalizeDocumentsInDefinitionReferences(state *State, definitionReferenceData map[int]*datastructures.DefaultIDSetMap, documentID, canonicalID int) {
-	for _, documentRanges := range definitionReferenceData {
-		if rangeIDs := documentRanges.Get(documentID); rangeIDs != nil {
-			// Move definition/reference data into the canonical document
-			documentRanges.SetUnion(canonicalID, rangeIDs)
-
-			// Remove references to non-canonical document
-			documentRanges.Delete(documentID)
+// canonicalizeDocumentsInDefinitionReferences moves definition or reference result data from
+// non-canonical documents to their canonical documents and removes all references to the
+// non-canonical documents. This returns true if the given document ranges were modified.
+func canonicalizeDocumentsInDefinitionReferences(documentRanges *datastructures.DefaultIDSetMap, canonicalIDs map[int]int) bool {
+	var documentIDs []int
+	documentRanges.Each(func(documentID int, _ *datastructures.IDSet) {
+		if _, ok := canonicalIDs[documentID]; ok {
+			documentIDs = append(documentIDs, documentID)
 		}
+	})
+
+	for _, documentID := range documentIDs {
+		// Move definition/reference data into the canonical document
+		documentRanges.SetUnion(canonicalIDs[documentID], documentRanges.Get(documentID))
+
+		// Remove references to non-canonical document
+		documentRanges.Delete(documentID)
 	}
+
+	return len(documentIDs) > 0
 }
 
 // canonicalizeReferenceResults determines which reference results refer to another reference result.
 // We denormalize the data so that all ranges reachable from set A are also reachable from set B when
 // B is linked to A via an item edge.
-func canonicalizeReferenceResults(state *State) {
+func canonicalizeReferenceResults(state *State) error {
 	visited := map[int]struct{}{}
 
-	var visit func(state *State, id int)
-	visit = func(state *State, id int) {
+	var visit func(state *State, id int) error
+	visit = func(state *State, id int) error {
 		if _, ok := visited[id]; ok {
-			return
+			return nil
 		}
 		visited[id] = struct{}{}
 
 		nextIDs, ok := state.LinkedReferenceResults[id]
 		if !ok {
-			return
+			return nil
 		}
 
 		for _, nextID := range nextIDs {
-			visit(state, nextID)
+			if err := visit(state, nextID); err != nil {
+				return err
+			}
+
+			documentRanges, ok, err := state.result(referenceResults, nextID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
 
 			// Copy data from the referenced to the referencing set
-			state.ReferenceData[nextID].Each(func(documentID int, rangeIDs *datastructures.IDSet) {
-				state.ReferenceData[id].SetUnion(documentID, rangeIDs)
+			var documentIDs []int
+			documentRanges.Each(func(documentID int, _ *datastructures.IDSet) {
+				documentIDs = append(documentIDs, documentID)
 			})
+			for _, documentID := range documentIDs {
+				if err := state.addResultRanges(referenceResults, id, documentID, documentRanges.Get(documentID)); err != nil {
+					return err
+				}
+			}
 		}
+
+		return nil
 	}
 
-	for id := range state.ReferenceData {
-		visit(state, id)
+	for _, id := range state.resultIDs(referenceResults) {
+		if err := visit(state, id); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 // canonicalizeResultSets runs canonicalizeResultSet on each result set in the correlation state.
 // This will collapse result sets down recursively so that if a result set's next element also has
 // a next element, then both sets merge down into the original result set.
-func canonicalizeResultSets(state *State) {
-	for resultSetID, resultSetData := range state.ResultSetData {
-		canonicalizeResultSetData(state, resultSetID, resultSetData)
+func canonicalizeResultSets(state *State) error {
+	if err := state.eachResultSet(func(resultSetID int, resultSetData ResultSet) error {
+		_, err := canonicalizeResultSetData(state, resultSetID, resultSetData)
+		return err
+	}); err != nil {
+		return err
 	}
 
-	for resultSetID := range state.ResultSetData {
-		state.Monikers.SetUnion(resultSetID, gatherMonikers(state, state.Monikers.Get(resultSetID)))
-	}
+	return state.eachResultSet(func(resultSetID int, _ ResultSet) error {
+		monikers, err := gatherMonikers(state, state.Monikers.Get(resultSetID))
+		if err != nil {
+			return err
+		}
+
+		state.Monikers.SetUnion(resultSetID, monikers)
+		return nil
+	})
 }
 
 // canonicalizeResultSets "merges down" the definition, reference, and hover result identifiers
@@ -121,35 +180,55 @@ func canonicalizeResultSets(state *State) {
 //
 // This method is assumed to be invoked only after canonicalizeResultSets, otherwise the next element
 // of a range may not have all of the necessary data to perform this canonicalization step.
-func canonicalizeRanges(state *State) {
-	for rangeID, rangeData := range state.RangeData {
-		if nextID, nextItem, ok := next(state, rangeID); ok {
+func canonicalizeRanges(state *State) error {
+	return state.eachRange(func(rangeID int, rangeData Range) error {
+		nextID, nextItem, ok, err := next(state, rangeID)
+		if err != nil {
+			return err
+		}
+		if ok {
 			// Merge range and next element
 			rangeData = mergeNextRangeData(state, rangeID, rangeData, nextID, nextItem)
 			// Delete next data to prevent us from re-performing this step
 			delete(state.NextData, rangeID)
+
+			if err := state.setRangeData(rangeID, rangeData); err != nil {
+				return err
+			}
 		}
 
-		state.RangeData[rangeID] = rangeData
-		state.Monikers.SetUnion(rangeID, gatherMonikers(state, state.Monikers.Get(rangeID)))
-	}
+		monikers, err := gatherMonikers(state, state.Monikers.Get(rangeID))
+		if err != nil {
+			return err
+		}
+
+		state.Monikers.SetUnion(rangeID, monikers)
+		return nil
+	})
 }
 
 // canonicalizeResultSets "merges down" the definition, reference, and hover result identifiers
 // from the element's "next" result set if such an element exists and the identifier is not
 // already defined. This also merges down the moniker ids by unioning the sets.
-func canonicalizeResultSetData(state *State, id int, item ResultSet) ResultSet {
-	if nextID, nextItem, ok := next(state, id); ok {
-		// Recursively canonicalize the next element
-		nextItem = canonicalizeResultSetData(state, nextID, nextItem)
-		// Merge result set and canonicalized next element
-		item = mergeNextResultSetData(state, id, item, nextID, nextItem)
-		// Delete next data to prevent us from re-performing this step
-		delete(state.NextData, id)
+func canonicalizeResultSetData(state *State, id int, item ResultSet) (ResultSet, error) {
+	nextID, nextItem, ok, err := next(state, id)
+	if err != nil || !ok {
+		return item, err
 	}
 
-	state.ResultSetData[id] = item
-	return item
+	// Recursively canonicalize the next element
+	if nextItem, err = canonicalizeResultSetData(state, nextID, nextItem); err != nil {
+		return ResultSet{}, err
+	}
+	// Merge result set and canonicalized next element
+	item = mergeNextResultSetData(state, id, item, nextID, nextItem)
+	// Delete next data to prevent us from re-performing this step
+	delete(state.NextData, id)
+
+	if err := state.setResultSetData(id, item); err != nil {
+		return ResultSet{}, err
+	}
+	return item, nil
 }
 
 // mergeNextResultSetData merges the definition, reference, and hover result identifiers from
@@ -162,6 +241,9 @@ func mergeNextResultSetData(state *State, itemID int, item ResultSet, nextID int
 	if item.ReferenceResultID == 0 {
 		item = item.SetReferenceResultID(nextItem.ReferenceResultID)
 	}
+	if item.ImplementationResultID == 0 {
+		item = item.SetImplementationResultID(nextItem.ImplementationResultID)
+	}
 	if item.HoverResultID == 0 {
 		item = item.SetHoverResultID(nextItem.HoverResultID)
 	}
@@ -183,6 +265,9 @@ func mergeNextRangeData(state *State, itemID int, item Range, nextID int, nextIt
 	if item.ReferenceResultID == 0 {
 		item = item.SetReferenceResultID(nextItem.ReferenceResultID)
 	}
+	if item.ImplementationResultID == 0 {
+		item = item.SetImplementationResultID(nextItem.ImplementationResultID)
+	}
 	if item.HoverResultID == 0 {
 		item = item.SetHoverResultID(nextItem.HoverResultID)
 	}
@@ -198,30 +283,44 @@ func mergeNextRangeData(state *State, itemID int, item Range, nextID int, nextIt
 // set will additionall contain the transitive closure of all moniker identifiers linked to any
 // moniker identifier in the original set. This ignores adding any local-kind monikers to the new
 // set.
-func gatherMonikers(state *State, source *datastructures.IDSet) *datastructures.IDSet {
+func gatherMonikers(state *State, source *datastructures.IDSet) (*datastructures.IDSet, error) {
 	if source == nil || source.Len() == 0 {
-		return nil
+		return nil, nil
 	}
 
 	monikers := datastructures.NewIDSet()
 
+	var err error
 	source.Each(func(sourceID int) {
 		state.LinkedMonikers.ExtractSet(sourceID).Each(func(id int) {
-			if state.MonikerData[id].Kind != "local" {
+			if err != nil {
+				return
+			}
+
+			var moniker Moniker
+			if moniker, _, err = state.moniker(id); err == nil && moniker.Kind != "local" {
 				monikers.Add(id)
 			}
 		})
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return monikers
+	return monikers, nil
 }
 
 // next returns the "next" identifier and result set element for the given identifier, if one exists.
-func next(state *State, id int) (int, ResultSet, bool) {
+func next(state *State, id int) (int, ResultSet, bool, error) {
 	nextID, ok := state.NextData[id]
 	if !ok {
-		return 0, ResultSet{}, false
+		return 0, ResultSet{}, false, nil
+	}
+
+	nextItem, _, err := state.resultSetData(nextID)
+	if err != nil {
+		return 0, ResultSet{}, false, err
 	}
 
-	return nextID, state.ResultSetData[nextID], true
+	return nextID, nextItem, true, nil
 }
@@ -33,7 +33,9 @@ func TestCanonicalizeDocuments(t *testing.T) {
 		Monikers:    datastructures.NewDefaultIDSetMap(),
 		Diagnostics: datastructures.NewDefaultIDSetMap(),
 	}
-	canonicalizeDocuments(state)
+	if err := canonicalizeDocuments(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
 
 	expectedState := &State{
 		DocumentData: map[int]string{
@@ -94,7 +96,9 @@ func TestCanonicalizeReferenceResults(t *testing.T) {
 		},
 		LinkedReferenceResults: map[int][]int{2001: {2003, 2004}, 2002: {2001}},
 	}
-	canonicalizeReferenceResults(state)
+	if err := canonicalizeReferenceResults(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
 
 	expectedState := &State{
 		RangeData: map[int]Range{
@@ -184,7 +188,9 @@ func TestCanonicalizeResultSets(t *testing.T) {
 			5005: datastructures.IDSetWith(4005),
 		}),
 	}
-	canonicalizeResultSets(state)
+	if err := canonicalizeResultSets(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
 
 	expectedState := &State{
 		ResultSetData: map[int]ResultSet{
@@ -284,7 +290,9 @@ func TestCanonicalizeRanges(t *testing.T) {
 		}),
 		Diagnostics: datastructures.NewDefaultIDSetMap(),
 	}
-	canonicalizeRanges(state)
+	if err := canonicalizeRanges(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
 
 	expectedState := &State{
 		RangeData: map[int]Range{
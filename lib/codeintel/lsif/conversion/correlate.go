@@ -18,19 +18,48 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
 )
 
-// Correlate reads LSIF data from the given reader and returns a correlation state object with
+// Correlate reads LSIF or SCIP data from the given reader and returns a correlation state object with
 // the same data canonicalized and pruned for storage.
 //
 // If getChildren == nil, no pruning of irrelevant data is performed.
 func Correlate(ctx context.Context, r io.Reader, root string, getChildren pathexistence.GetChildrenFunc) (*semantic.GroupedBundleDataChans, error) {
+	return CorrelateWithOptions(ctx, r, root, getChildren, CorrelateOptions{})
+}
+
+// CorrelateOptions controls the resources used while correlating an LSIF index.
+type CorrelateOptions struct {
+	// MemoryBudget is the approximate number of bytes of ranges, result sets, definition,
+	// reference, and implementation results, monikers, and hover and diagnostic payloads held
+	// in memory while correlating an index. Elements beyond this budget are spilled to a
+	// temporary file and read back as the bundle data is grouped. Zero disables spilling.
+	MemoryBudget int64
+
+	// SpillDir is the directory in which the spill file is created. If empty, the default
+	// directory for temporary files is used.
+	SpillDir string
+}
+
+// CorrelateWithOptions behaves like Correlate but bounds the memory used by the elements of
+// the index as described by the given options. Any spill file created during correlation is
+// removed once the channels of the returned bundle data have been drained or the given context
+// is canceled. Errors reading the spill file while the channels are drained are returned by the
+// Err function of the returned bundle data.
+func CorrelateWithOptions(ctx context.Context, r io.Reader, root string, getChildren pathexistence.GetChildrenFunc, opts CorrelateOptions) (_ *semantic.GroupedBundleDataChans, err error) {
 	// Read raw upload stream and return a correlation state
-	state, err := correlateFromReader(ctx, r, root)
+	state, err := correlateFromIndexReader(ctx, r, root, opts)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			_ = state.Spilled.Close()
+		}
+	}()
 
 	// Remove duplicate elements, collapse linked elements
-	canonicalize(state)
+	if err := canonicalize(state); err != nil {
+		return nil, err
+	}
 
 	if getChildren != nil {
 		// Remove elements we don't need to store
@@ -114,7 +143,7 @@ func gitRoot(path string) (string, error) {
 
 // correlateFromReader reads the given upload stream and returns a correlation state object.
 // The data in the correlation state is neither canonicalized nor pruned.
-func correlateFromReader(ctx context.Context, r io.Reader, root string) (*State, error) {
+func correlateFromReader(ctx context.Context, r io.Reader, root string, opts CorrelateOptions) (_ *State, err error) {
 	ctx, cancel := context.WithCancel(ctx)
 	ch := Read(ctx, r)
 	defer func() {
@@ -126,7 +155,12 @@ func correlateFromReader(ctx context.Context, r io.Reader, root string) (*State,
 		}
 	}()
 
-	wrappedState := newWrappedState(root)
+	wrappedState := newWrappedState(root, opts)
+	defer func() {
+		if err != nil {
+			_ = wrappedState.Spilled.Close()
+		}
+	}()
 
 	i := 0
 	for pair := range ch {
@@ -154,9 +188,14 @@ type wrappedState struct {
 	unsupportedVertices *datastructures.IDSet
 }
 
-func newWrappedState(dumpRoot string) *wrappedState {
+func newWrappedState(dumpRoot string, opts CorrelateOptions) *wrappedState {
+	state := newState()
+	if opts.MemoryBudget > 0 {
+		state.Spilled = newSpillStore(opts.SpillDir, opts.MemoryBudget)
+	}
+
 	return &wrappedState{
-		State:               newState(),
+		State:               state,
 		dumpRoot:            dumpRoot,
 		unsupportedVertices: datastructures.NewIDSet(),
 	}
@@ -175,16 +214,17 @@ func correlateElement(state *wrappedState, element Element) error {
 }
 
 var vertexHandlers = map[string]func(state *wrappedState, element Element) error{
-	"metaData":           correlateMetaData,
-	"document":           correlateDocument,
-	"range":              correlateRange,
-	"resultSet":          correlateResultSet,
-	"definitionResult":   correlateDefinitionResult,
-	"referenceResult":    correlateReferenceResult,
-	"hoverResult":        correlateHoverResult,
-	"moniker":            correlateMoniker,
-	"packageInformation": correlatePackageInformation,
-	"diagnosticResult":   correlateDiagnosticResult,
+	"metaData":             correlateMetaData,
+	"document":             correlateDocument,
+	"range":                correlateRange,
+	"resultSet":            correlateResultSet,
+	"definitionResult":     correlateDefinitionResult,
+	"referenceResult":      correlateReferenceResult,
+	"implementationResult": correlateImplementationResult,
+	"hoverResult":          correlateHoverResult,
+	"moniker":              correlateMoniker,
+	"packageInformation":   correlatePackageInformation,
+	"diagnosticResult":     correlateDiagnosticResult,
 
 	// Sourcegraph extensions
 	string(protocol.VertexSourcegraphDocumentationResult): correlateDocumentationResult,
@@ -208,16 +248,17 @@ func correlateVertex(state *wrappedState, element Element) error {
 }
 
 var edgeHandlers = map[string]func(state *wrappedState, id int, edge Edge) error{
-	"contains":                correlateContainsEdge,
-	"next":                    correlateNextEdge,
-	"item":                    correlateItemEdge,
-	"textDocument/definition": correlateTextDocumentDefinitionEdge,
-	"textDocument/references": correlateTextDocumentReferencesEdge,
-	"textDocument/hover":      correlateTextDocumentHoverEdge,
-	"moniker":                 correlateMonikerEdge,
-	"nextMoniker":             correlateNextMonikerEdge,
-	"packageInformation":      correlatePackageInformationEdge,
-	"textDocument/diagnostic": correlateDiagnosticEdge,
+	"contains":                    correlateContainsEdge,
+	"next":                        correlateNextEdge,
+	"item":                        correlateItemEdge,
+	"textDocument/definition":     correlateTextDocumentDefinitionEdge,
+	"textDocument/references":     correlateTextDocumentReferencesEdge,
+	"textDocument/implementation": correlateTextDocumentImplementationEdge,
+	"textDocument/hover":          correlateTextDocumentHoverEdge,
+	"moniker":                     correlateMonikerEdge,
+	"nextMoniker":                 correlateNextMonikerEdge,
+	"packageInformation":          correlatePackageInformationEdge,
+	"textDocument/diagnostic":     correlateDiagnosticEdge,
 
 	// Sourcegraph extensions
 	string(protocol.EdgeSourcegraphDocumentationResult):   correlateDocumentationResultEdge,
@@ -294,23 +335,23 @@ func correlateRange(state *wrappedState, element Element) error {
 		return ErrUnexpectedPayload
 	}
 
-	state.RangeData[element.ID] = payload
-	return nil
+	return state.setRangeData(element.ID, payload)
 }
 
 func correlateResultSet(state *wrappedState, element Element) error {
-	state.ResultSetData[element.ID] = ResultSet{}
-	return nil
+	return state.setResultSetData(element.ID, ResultSet{})
 }
 
 func correlateDefinitionResult(state *wrappedState, element Element) error {
-	state.DefinitionData[element.ID] = datastructures.NewDefaultIDSetMap()
-	return nil
+	return state.setResult(definitionResults, element.ID, datastructures.NewDefaultIDSetMap())
 }
 
 func correlateReferenceResult(state *wrappedState, element Element) error {
-	state.ReferenceData[element.ID] = datastructures.NewDefaultIDSetMap()
-	return nil
+	return state.setResult(referenceResults, element.ID, datastructures.NewDefaultIDSetMap())
+}
+
+func correlateImplementationResult(state *wrappedState, element Element) error {
+	return state.setResult(implementationResults, element.ID, datastructures.NewDefaultIDSetMap())
 }
 
 func correlateHoverResult(state *wrappedState, element Element) error {
@@ -319,8 +360,7 @@ func correlateHoverResult(state *wrappedState, element Element) error {
 		return ErrUnexpectedPayload
 	}
 
-	state.HoverData[element.ID] = payload
-	return nil
+	return state.setHoverResult(element.ID, payload)
 }
 
 func correlateMoniker(state *wrappedState, element Element) error {
@@ -329,8 +369,7 @@ func correlateMoniker(state *wrappedState, element Element) error {
 		return ErrUnexpectedPayload
 	}
 
-	state.MonikerData[element.ID] = payload
-	return nil
+	return state.setMoniker(element.ID, payload)
 }
 
 func correlatePackageInformation(state *wrappedState, element Element) error {
@@ -349,8 +388,7 @@ func correlateDiagnosticResult(state *wrappedState, element Element) error {
 		return ErrUnexpectedPayload
 	}
 
-	state.DiagnosticResults[element.ID] = payload
-	return nil
+	return state.setDiagnosticResult(element.ID, payload)
 }
 
 func correlateContainsEdge(state *wrappedState, id int, edge Edge) error {
@@ -360,7 +398,7 @@ func correlateContainsEdge(state *wrappedState, id int, edge Edge) error {
 	}
 
 	for _, inV := range edge.InVs {
-		if _, ok := state.RangeData[inV]; !ok {
+		if !state.hasRange(inV) {
 			return malformedDump(id, inV, "range")
 		}
 		state.Contains.SetAdd(edge.OutV, inV)
@@ -369,13 +407,13 @@ func correlateContainsEdge(state *wrappedState, id int, edge Edge) error {
 }
 
 func correlateNextEdge(state *wrappedState, id int, edge Edge) error {
-	if _, ok := state.ResultSetData[edge.InV]; !ok {
+	if !state.hasResultSet(edge.InV) {
 		return malformedDump(id, edge.InV, "resultSet")
 	}
 
-	if _, ok := state.RangeData[edge.OutV]; ok {
+	if state.hasRange(edge.OutV) {
 		state.NextData[edge.OutV] = edge.InV
-	} else if _, ok := state.ResultSetData[edge.OutV]; ok {
+	} else if state.hasResultSet(edge.OutV) {
 		state.NextData[edge.OutV] = edge.InV
 	} else {
 		return malformedDump(id, edge.OutV, "range", "resultSet")
@@ -384,31 +422,50 @@ func correlateNextEdge(state *wrappedState, id int, edge Edge) error {
 }
 
 func correlateItemEdge(state *wrappedState, id int, edge Edge) error {
-	if documentMap, ok := state.DefinitionData[edge.OutV]; ok {
+	if state.hasResult(definitionResults, edge.OutV) {
 		for _, inV := range edge.InVs {
-			if _, ok := state.RangeData[inV]; !ok {
+			if !state.hasRange(inV) {
 				return malformedDump(id, inV, "range")
 			}
 
 			// Link definition data to defining range
-			documentMap.SetAdd(edge.Document, inV)
+			if err := state.addResultRanges(definitionResults, edge.OutV, edge.Document, datastructures.IDSetWith(inV)); err != nil {
+				return err
+			}
 		}
 
 		return nil
 	}
 
-	if documentMap, ok := state.ReferenceData[edge.OutV]; ok {
+	if state.hasResult(referenceResults, edge.OutV) {
 		for _, inV := range edge.InVs {
-			if _, ok := state.ReferenceData[inV]; ok {
+			if state.hasResult(referenceResults, inV) {
 				// Link reference data identifiers together
 				state.LinkedReferenceResults[edge.OutV] = append(state.LinkedReferenceResults[edge.OutV], inV)
 			} else {
-				if _, ok = state.RangeData[inV]; !ok {
+				if !state.hasRange(inV) {
 					return malformedDump(id, inV, "range")
 				}
 
 				// Link reference data to a reference range
-				documentMap.SetAdd(edge.Document, inV)
+				if err := state.addResultRanges(referenceResults, edge.OutV, edge.Document, datastructures.IDSetWith(inV)); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if state.hasResult(implementationResults, edge.OutV) {
+		for _, inV := range edge.InVs {
+			if !state.hasRange(inV) {
+				return malformedDump(id, inV, "range")
+			}
+
+			// Link implementation data to an implementing range
+			if err := state.addResultRanges(implementationResults, edge.OutV, edge.Document, datastructures.IDSetWith(inV)); err != nil {
+				return err
 			}
 		}
 
@@ -424,58 +481,63 @@ func correlateItemEdge(state *wrappedState, id int, edge Edge) error {
 }
 
 func correlateTextDocumentDefinitionEdge(state *wrappedState, id int, edge Edge) error {
-	if _, ok := state.DefinitionData[edge.InV]; !ok {
+	if !state.hasResult(definitionResults, edge.InV) {
 		return malformedDump(id, edge.InV, "definitionResult")
 	}
 
-	if source, ok := state.RangeData[edge.OutV]; ok {
-		state.RangeData[edge.OutV] = source.SetDefinitionResultID(edge.InV)
-	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
-		state.ResultSetData[edge.OutV] = source.SetDefinitionResultID(edge.InV)
-	} else {
-		return malformedDump(id, edge.OutV, "range", "resultSet")
-	}
-	return nil
+	return setResultID(state, id, edge, Range.SetDefinitionResultID, ResultSet.SetDefinitionResultID)
 }
 
 func correlateTextDocumentReferencesEdge(state *wrappedState, id int, edge Edge) error {
-	if _, ok := state.ReferenceData[edge.InV]; !ok {
+	if !state.hasResult(referenceResults, edge.InV) {
 		return malformedDump(id, edge.InV, "referenceResult")
 	}
 
-	if source, ok := state.RangeData[edge.OutV]; ok {
-		state.RangeData[edge.OutV] = source.SetReferenceResultID(edge.InV)
-	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
-		state.ResultSetData[edge.OutV] = source.SetReferenceResultID(edge.InV)
-	} else {
-		return malformedDump(id, edge.OutV, "range", "resultSet")
+	return setResultID(state, id, edge, Range.SetReferenceResultID, ResultSet.SetReferenceResultID)
+}
+
+func correlateTextDocumentImplementationEdge(state *wrappedState, id int, edge Edge) error {
+	if !state.hasResult(implementationResults, edge.InV) {
+		return malformedDump(id, edge.InV, "implementationResult")
 	}
-	return nil
+
+	return setResultID(state, id, edge, Range.SetImplementationResultID, ResultSet.SetImplementationResultID)
 }
 
 func correlateTextDocumentHoverEdge(state *wrappedState, id int, edge Edge) error {
-	if _, ok := state.HoverData[edge.InV]; !ok {
+	if !state.hasHoverResult(edge.InV) {
 		return malformedDump(id, edge.InV, "hoverResult")
 	}
 
-	if source, ok := state.RangeData[edge.OutV]; ok {
-		state.RangeData[edge.OutV] = source.SetHoverResultID(edge.InV)
-	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
-		state.ResultSetData[edge.OutV] = source.SetHoverResultID(edge.InV)
-	} else {
-		return malformedDump(id, edge.OutV, "range", "resultSet")
+	return setResultID(state, id, edge, Range.SetHoverResultID, ResultSet.SetHoverResultID)
+}
+
+// setResultID links the range or result set at the out vertex of the given edge to the result
+// at its in vertex via the given setters.
+func setResultID(state *wrappedState, id int, edge Edge, setRangeResultID func(Range, int) Range, setResultSetResultID func(ResultSet, int) ResultSet) error {
+	if source, ok, err := state.rangeData(edge.OutV); err != nil {
+		return err
+	} else if ok {
+		return state.setRangeData(edge.OutV, setRangeResultID(source, edge.InV))
 	}
-	return nil
+
+	if source, ok, err := state.resultSetData(edge.OutV); err != nil {
+		return err
+	} else if ok {
+		return state.setResultSetData(edge.OutV, setResultSetResultID(source, edge.InV))
+	}
+
+	return malformedDump(id, edge.OutV, "range", "resultSet")
 }
 
 func correlateMonikerEdge(state *wrappedState, id int, edge Edge) error {
-	if _, ok := state.MonikerData[edge.InV]; !ok {
+	if !state.hasMoniker(edge.InV) {
 		return malformedDump(id, edge.InV, "moniker")
 	}
 
-	if _, ok := state.RangeData[edge.OutV]; ok {
+	if state.hasRange(edge.OutV) {
 		state.Monikers.SetAdd(edge.OutV, edge.InV)
-	} else if _, ok := state.ResultSetData[edge.OutV]; ok {
+	} else if state.hasResultSet(edge.OutV) {
 		state.Monikers.SetAdd(edge.OutV, edge.InV)
 	} else {
 		return malformedDump(id, edge.OutV, "range", "resultSet")
@@ -484,10 +546,10 @@ func correlateMonikerEdge(state *wrappedState, id int, edge Edge) error {
 }
 
 func correlateNextMonikerEdge(state *wrappedState, id int, edge Edge) error {
-	if _, ok := state.MonikerData[edge.InV]; !ok {
+	if !state.hasMoniker(edge.InV) {
 		return malformedDump(id, edge.InV, "moniker")
 	}
-	if _, ok := state.MonikerData[edge.OutV]; !ok {
+	if !state.hasMoniker(edge.OutV) {
 		return malformedDump(id, edge.OutV, "moniker")
 	}
 
@@ -500,11 +562,16 @@ func correlatePackageInformationEdge(state *wrappedState, id int, edge Edge) err
 		return malformedDump(id, edge.InV, "packageInformation")
 	}
 
-	source, ok := state.MonikerData[edge.OutV]
+	source, ok, err := state.moniker(edge.OutV)
+	if err != nil {
+		return err
+	}
 	if !ok {
 		return malformedDump(id, edge.OutV, "moniker")
 	}
-	state.MonikerData[edge.OutV] = source.SetPackageInformationID(edge.InV)
+	if err := state.setMoniker(edge.OutV, source.SetPackageInformationID(edge.InV)); err != nil {
+		return err
+	}
 
 	switch source.Kind {
 	case "import":
@@ -523,7 +590,7 @@ func correlateDiagnosticEdge(state *wrappedState, id int, edge Edge) error {
 		return malformedDump(id, edge.OutV, "document")
 	}
 
-	if _, ok := state.DiagnosticResults[edge.InV]; !ok {
+	if !state.hasDiagnosticResult(edge.InV) {
 		return malformedDump(id, edge.InV, "diagnosticResult")
 	}
 
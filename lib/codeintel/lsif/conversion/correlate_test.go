@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -19,7 +20,7 @@ func TestCorrelate(t *testing.T) {
 		t.Fatalf("unexpected error reading test file: %s", err)
 	}
 
-	state, err := correlateFromReader(context.Background(), bytes.NewReader(input), "root")
+	state, err := correlateFromReader(context.Background(), bytes.NewReader(input), "root", CorrelateOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error correlating input: %s", err)
 	}
@@ -104,6 +105,7 @@ func TestCorrelate(t *testing.T) {
 			14: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{2: datastructures.IDSetWith(4, 5)}),
 			15: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{}),
 		},
+		ImplementationData: map[int]*datastructures.DefaultIDSetMap{},
 		HoverData: map[int]string{
 			16: "```go\ntext A\n```",
 			17: "```go\ntext B\n```",
@@ -202,7 +204,7 @@ func TestCorrelateMetaDataRoot(t *testing.T) {
 		t.Fatalf("unexpected error reading test file: %s", err)
 	}
 
-	state, err := correlateFromReader(context.Background(), bytes.NewReader(input), "root/")
+	state, err := correlateFromReader(context.Background(), bytes.NewReader(input), "root/", CorrelateOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error correlating input: %s", err)
 	}
@@ -217,6 +219,7 @@ func TestCorrelateMetaDataRoot(t *testing.T) {
 		ResultSetData:          map[int]ResultSet{},
 		DefinitionData:         map[int]*datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]*datastructures.DefaultIDSetMap{},
+		ImplementationData:     map[int]*datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]Moniker{},
 		PackageInformationData: map[int]PackageInformation{},
@@ -250,7 +253,7 @@ func TestCorrelateMetaDataRootX(t *testing.T) {
 		t.Fatalf("unexpected error reading test file: %s", err)
 	}
 
-	state, err := correlateFromReader(context.Background(), bytes.NewReader(input), "")
+	state, err := correlateFromReader(context.Background(), bytes.NewReader(input), "", CorrelateOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error correlating input: %s", err)
 	}
@@ -265,6 +268,7 @@ func TestCorrelateMetaDataRootX(t *testing.T) {
 		ResultSetData:          map[int]ResultSet{},
 		DefinitionData:         map[int]*datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]*datastructures.DefaultIDSetMap{},
+		ImplementationData:     map[int]*datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]Moniker{},
 		PackageInformationData: map[int]PackageInformation{},
@@ -291,3 +295,41 @@ func TestCorrelateMetaDataRootX(t *testing.T) {
 		t.Errorf("unexpected state (-want +got):\n%s", diff)
 	}
 }
+
+func TestCorrelateImplementations(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": "1", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test/"}`,
+		`{"id": "2", "type": "vertex", "label": "document", "uri": "file:///test/iface.go"}`,
+		`{"id": "3", "type": "vertex", "label": "document", "uri": "file:///test/impl.go"}`,
+		`{"id": "4", "type": "vertex", "label": "range", "start": {"line": 1, "character": 1}, "end": {"line": 1, "character": 5}}`,
+		`{"id": "5", "type": "vertex", "label": "range", "start": {"line": 2, "character": 1}, "end": {"line": 2, "character": 5}}`,
+		`{"id": "6", "type": "vertex", "label": "range", "start": {"line": 3, "character": 1}, "end": {"line": 3, "character": 5}}`,
+		`{"id": "7", "type": "vertex", "label": "resultSet"}`,
+		`{"id": "8", "type": "vertex", "label": "implementationResult"}`,
+		`{"id": "9", "type": "edge", "label": "contains", "outV": "2", "inVs": ["4"]}`,
+		`{"id": "10", "type": "edge", "label": "contains", "outV": "3", "inVs": ["5", "6"]}`,
+		`{"id": "11", "type": "edge", "label": "next", "outV": "4", "inV": "7"}`,
+		`{"id": "12", "type": "edge", "label": "textDocument/implementation", "outV": "7", "inV": "8"}`,
+		`{"id": "13", "type": "edge", "label": "item", "outV": "8", "inVs": ["5", "6"], "document": "3"}`,
+	}, "\n")
+
+	state, err := correlateFromReader(context.Background(), strings.NewReader(input), "", CorrelateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+
+	expectedImplementationData := map[int]*datastructures.DefaultIDSetMap{
+		8: datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{3: datastructures.IDSetWith(5, 6)}),
+	}
+	if diff := cmp.Diff(expectedImplementationData, state.ImplementationData, datastructures.Comparers...); diff != "" {
+		t.Errorf("unexpected implementation data (-want +got):\n%s", diff)
+	}
+
+	if err := canonicalize(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
+
+	if implementationResultID := state.RangeData[4].ImplementationResultID; implementationResultID != 8 {
+		t.Errorf("unexpected implementation result. want=%d have=%d", 8, implementationResultID)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/bloomfilter"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion/datastructures"
@@ -23,21 +25,57 @@ import (
 const resultsPerResultChunk = 512
 
 // groupBundleData converts a raw (but canonicalized) correlation State into a GroupedBundleData.
-func groupBundleData(ctx context.Context, state *State) (*semantic.GroupedBundleDataChans, error) {
-	numResults := len(state.DefinitionData) + len(state.ReferenceData)
+func groupBundleData(ctx context.Context, state *State) (_ *semantic.GroupedBundleDataChans, err error) {
+	numResults := state.numResults(definitionResults) + state.numResults(referenceResults) + state.numResults(implementationResults)
 	numResultChunks := int(math.Max(1, math.Floor(float64(numResults)/resultsPerResultChunk)))
 
+	// Stop the producers started before an error occurs
+	producerCtx, cancel := context.WithCancel(ctx)
+	p := &producers{}
+	defer func() {
+		if err != nil {
+			cancel()
+			p.wait()
+		}
+	}()
+
 	meta := semantic.MetaData{NumResultChunks: numResultChunks}
-	documents := serializeBundleDocuments(ctx, state)
-	resultChunks := serializeResultChunks(ctx, state, numResultChunks)
-	definitionRows := gatherMonikersLocations(ctx, state, state.DefinitionData, func(r Range) int { return r.DefinitionResultID })
-	referenceRows := gatherMonikersLocations(ctx, state, state.ReferenceData, func(r Range) int { return r.ReferenceResultID })
-	documentation := collectDocumentation(ctx, state)
-	packages := gatherPackages(state)
+	packages, err := gatherPackages(state)
+	if err != nil {
+		return nil, err
+	}
 	packageReferences, err := gatherPackageReferences(state, packages)
 	if err != nil {
 		return nil, err
 	}
+	definitionRows, err := gatherMonikersLocations(producerCtx, p, state, definitionResults, func(r Range) int { return r.DefinitionResultID })
+	if err != nil {
+		return nil, err
+	}
+	referenceRows, err := gatherMonikersLocations(producerCtx, p, state, referenceResults, func(r Range) int { return r.ReferenceResultID })
+	if err != nil {
+		return nil, err
+	}
+	implementationRows, err := gatherMonikersLocations(producerCtx, p, state, implementationResults, func(r Range) int { return r.ImplementationResultID })
+	if err != nil {
+		return nil, err
+	}
+	documentation, err := collectDocumentation(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	documents := serializeBundleDocuments(producerCtx, p, state)
+	resultChunks := serializeResultChunks(producerCtx, p, state, numResultChunks)
+
+	go func() {
+		p.wait()
+		cancel()
+
+		// The producers are the only consumers of spilled elements
+		if err := state.Spilled.Close(); err != nil {
+			log15.Warn("Failed to remove correlation spill file", "err", err)
+		}
+	}()
 
 	return &semantic.GroupedBundleDataChans{
 		Meta:                  meta,
@@ -45,42 +83,87 @@ func groupBundleData(ctx context.Context, state *State) (*semantic.GroupedBundle
 		ResultChunks:          resultChunks,
 		Definitions:           definitionRows,
 		References:            referenceRows,
+		Implementations:       implementationRows,
 		DocumentationPages:    documentation.pages,
 		DocumentationPathInfo: documentation.pathInfo,
 		DocumentationMappings: documentation.mappings,
 		Packages:              packages,
 		PackageReferences:     packageReferences,
+		Err:                   p.reportedErr,
 	}, nil
 }
 
-func serializeBundleDocuments(ctx context.Context, state *State) chan semantic.KeyedDocumentData {
-	ch := make(chan semantic.KeyedDocumentData)
+// producers tracks the goroutines producing the values of the channels of grouped bundle data
+// along with the errors they encounter.
+type producers struct {
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	err error
+}
+
+// run invokes the given function in a new goroutine. Any error returned by the function is
+// recorded before the given done function, which closes the channel the goroutine produces
+// values for, is invoked.
+func (p *producers) run(f func() error, done func()) {
+	p.wg.Add(1)
 
 	go func() {
-		defer close(ch)
+		defer p.wg.Done()
+		defer done()
+
+		if err := f(); err != nil {
+			p.mu.Lock()
+			p.err = errors.CombineErrors(p.err, err)
+			p.mu.Unlock()
+		}
+	}()
+}
+
+// wait blocks until all goroutines have exited.
+func (p *producers) wait() {
+	p.wg.Wait()
+}
+
+// reportedErr returns the errors encountered by the goroutines that have exited.
+func (p *producers) reportedErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func serializeBundleDocuments(ctx context.Context, p *producers, state *State) chan semantic.KeyedDocumentData {
+	ch := make(chan semantic.KeyedDocumentData)
 
+	p.run(func() error {
 		for documentID, uri := range state.DocumentData {
 			if strings.HasPrefix(uri, "..") {
 				continue
 			}
 
+			document, err := serializeDocument(state, documentID)
+			if err != nil {
+				return errors.Wrapf(err, "serializing document %s", uri)
+			}
+
 			data := semantic.KeyedDocumentData{
 				Path:     uri,
-				Document: serializeDocument(state, documentID),
+				Document: document,
 			}
 
 			select {
 			case ch <- data:
 			case <-ctx.Done():
-				return
+				return nil
 			}
 		}
-	}()
+
+		return nil
+	}, func() { close(ch) })
 
 	return ch
 }
 
-func serializeDocument(state *State, documentID int) semantic.DocumentData {
+func serializeDocument(state *State, documentID int) (semantic.DocumentData, error) {
 	document := semantic.DocumentData{
 		Ranges:             make(map[semantic.ID]semantic.RangeData, state.Contains.SetLen(documentID)),
 		HoverResults:       map[semantic.ID]string{},
@@ -89,12 +172,27 @@ func serializeDocument(state *State, documentID int) semantic.DocumentData {
 		Diagnostics:        make([]semantic.DiagnosticData, 0, state.Diagnostics.SetLen(documentID)),
 	}
 
+	var err error
 	state.Contains.SetEach(documentID, func(rangeID int) {
-		rangeData := state.RangeData[rangeID]
+		if err != nil {
+			return
+		}
+
+		var rangeData Range
+		if rangeData, _, err = state.rangeData(rangeID); err != nil {
+			return
+		}
 
 		monikerIDs := make([]semantic.ID, 0, state.Monikers.SetLen(rangeID))
 		state.Monikers.SetEach(rangeID, func(monikerID int) {
-			moniker := state.MonikerData[monikerID]
+			if err != nil {
+				return
+			}
+
+			var moniker Moniker
+			if moniker, _, err = state.moniker(monikerID); err != nil {
+				return
+			}
 			monikerIDs = append(monikerIDs, toID(monikerID))
 
 			document.Monikers[toID(monikerID)] = semantic.MonikerData{
@@ -112,27 +210,46 @@ func serializeDocument(state *State, documentID int) semantic.DocumentData {
 				}
 			}
 		})
+		if err != nil {
+			return
+		}
 
 		document.Ranges[toID(rangeID)] = semantic.RangeData{
-			StartLine:             rangeData.Start.Line,
-			StartCharacter:        rangeData.Start.Character,
-			EndLine:               rangeData.End.Line,
-			EndCharacter:          rangeData.End.Character,
-			DefinitionResultID:    toID(rangeData.DefinitionResultID),
-			ReferenceResultID:     toID(rangeData.ReferenceResultID),
-			HoverResultID:         toID(rangeData.HoverResultID),
-			DocumentationResultID: toID(rangeData.DocumentationResultID),
-			MonikerIDs:            monikerIDs,
+			StartLine:              rangeData.Start.Line,
+			StartCharacter:         rangeData.Start.Character,
+			EndLine:                rangeData.End.Line,
+			EndCharacter:           rangeData.End.Character,
+			DefinitionResultID:     toID(rangeData.DefinitionResultID),
+			ReferenceResultID:      toID(rangeData.ReferenceResultID),
+			ImplementationResultID: toID(rangeData.ImplementationResultID),
+			HoverResultID:          toID(rangeData.HoverResultID),
+			DocumentationResultID:  toID(rangeData.DocumentationResultID),
+			MonikerIDs:             monikerIDs,
 		}
 
 		if rangeData.HoverResultID != 0 {
-			hoverData := state.HoverData[rangeData.HoverResultID]
+			var hoverData string
+			if hoverData, _, err = state.hoverResult(rangeData.HoverResultID); err != nil {
+				return
+			}
 			document.HoverResults[toID(rangeData.HoverResultID)] = hoverData
 		}
 	})
+	if err != nil {
+		return semantic.DocumentData{}, err
+	}
 
 	state.Diagnostics.SetEach(documentID, func(diagnosticID int) {
-		for _, diagnostic := range state.DiagnosticResults[diagnosticID] {
+		if err != nil {
+			return
+		}
+
+		var diagnostics []Diagnostic
+		if diagnostics, _, err = state.diagnosticResult(diagnosticID); err != nil {
+			return
+		}
+
+		for _, diagnostic := range diagnostics {
 			document.Diagnostics = append(document.Diagnostics, semantic.DiagnosticData{
 				Severity:       diagnostic.Severity,
 				Code:           diagnostic.Code,
@@ -145,41 +262,46 @@ func serializeDocument(state *State, documentID int) semantic.DocumentData {
 			})
 		}
 	})
+	if err != nil {
+		return semantic.DocumentData{}, err
+	}
 
-	return document
+	return document, nil
 }
 
-func serializeResultChunks(ctx context.Context, state *State, numResultChunks int) chan semantic.IndexedResultChunkData {
-	chunkAssignments := make(map[int][]int, numResultChunks)
-	for id := range state.DefinitionData {
-		index := semantic.HashKey(toID(id), numResultChunks)
-		chunkAssignments[index] = append(chunkAssignments[index], id)
-	}
-	for id := range state.ReferenceData {
-		index := semantic.HashKey(toID(id), numResultChunks)
-		chunkAssignments[index] = append(chunkAssignments[index], id)
+// resultReference identifies a definition, reference, or implementation result.
+type resultReference struct {
+	kind resultKind
+	id   int
+}
+
+func serializeResultChunks(ctx context.Context, p *producers, state *State, numResultChunks int) chan semantic.IndexedResultChunkData {
+	chunkAssignments := make(map[int][]resultReference, numResultChunks)
+	for _, kind := range resultKinds {
+		for _, id := range state.resultIDs(kind) {
+			index := semantic.HashKey(toID(id), numResultChunks)
+			chunkAssignments[index] = append(chunkAssignments[index], resultReference{kind: kind, id: id})
+		}
 	}
 
 	ch := make(chan semantic.IndexedResultChunkData)
 
-	go func() {
-		defer close(ch)
-
-		for index, resultIDs := range chunkAssignments {
-			if len(resultIDs) == 0 {
+	p.run(func() error {
+		for index, results := range chunkAssignments {
+			if len(results) == 0 {
 				continue
 			}
 
 			documentPaths := map[semantic.ID]string{}
-			rangeIDsByResultID := make(map[semantic.ID][]semantic.DocumentIDRangeID, len(resultIDs))
+			rangeIDsByResultID := make(map[semantic.ID][]semantic.DocumentIDRangeID, len(results))
 
-			for _, resultID := range resultIDs {
-				documentRanges, ok := state.DefinitionData[resultID]
-				if !ok {
-					documentRanges = state.ReferenceData[resultID]
+			for _, result := range results {
+				documentRanges, _, err := state.result(result.kind, result.id)
+				if err != nil {
+					return errors.Wrapf(err, "serializing result %d", result.id)
 				}
 
-				rangeIDMap := map[semantic.ID]int{}
+				ranges := map[semantic.ID]Range{}
 				var documentIDRangeIDs []semantic.DocumentIDRangeID
 
 				documentRanges.Each(func(documentID int, rangeIDs *datastructures.IDSet) {
@@ -187,7 +309,12 @@ func serializeResultChunks(ctx context.Context, state *State, numResultChunks in
 					documentPaths[docID] = state.DocumentData[documentID]
 
 					rangeIDs.Each(func(rangeID int) {
-						rangeIDMap[toID(rangeID)] = rangeID
+						if err != nil {
+							return
+						}
+						if ranges[toID(rangeID)], _, err = state.rangeData(rangeID); err != nil {
+							return
+						}
 
 						documentIDRangeIDs = append(documentIDRangeIDs, semantic.DocumentIDRangeID{
 							DocumentID: docID,
@@ -195,19 +322,21 @@ func serializeResultChunks(ctx context.Context, state *State, numResultChunks in
 						})
 					})
 				})
+				if err != nil {
+					return errors.Wrapf(err, "serializing result %d", result.id)
+				}
 
 				// Sort locations by containing document path then by offset within the text
 				// document (in reading order). This provides us with an obvious and deterministic
 				// ordering of a result set over multiple API requests.
 
 				sort.Sort(sortableDocumentIDRangeIDs{
-					state:         state,
 					documentPaths: documentPaths,
-					rangeIDMap:    rangeIDMap,
+					ranges:        ranges,
 					s:             documentIDRangeIDs,
 				})
 
-				rangeIDsByResultID[toID(resultID)] = documentIDRangeIDs
+				rangeIDsByResultID[toID(result.id)] = documentIDRangeIDs
 			}
 
 			data := semantic.IndexedResultChunkData{
@@ -221,19 +350,20 @@ func serializeResultChunks(ctx context.Context, state *State, numResultChunks in
 			select {
 			case ch <- data:
 			case <-ctx.Done():
-				return
+				return nil
 			}
 		}
-	}()
+
+		return nil
+	}, func() { close(ch) })
 
 	return ch
 }
 
 // sortableDocumentIDRangeIDs implements sort.Interface for document/range id pairs.
 type sortableDocumentIDRangeIDs struct {
-	state         *State
 	documentPaths map[semantic.ID]string
-	rangeIDMap    map[semantic.ID]int
+	ranges        map[semantic.ID]Range
 	s             []semantic.DocumentIDRangeID
 }
 
@@ -242,8 +372,8 @@ func (s sortableDocumentIDRangeIDs) Swap(i, j int) { s.s[i], s.s[j] = s.s[j], s.
 func (s sortableDocumentIDRangeIDs) Less(i, j int) bool {
 	iDocumentID := s.s[i].DocumentID
 	jDocumentID := s.s[j].DocumentID
-	iRange := s.state.RangeData[s.rangeIDMap[s.s[i].RangeID]]
-	jRange := s.state.RangeData[s.rangeIDMap[s.s[j].RangeID]]
+	iRange := s.ranges[s.s[i].RangeID]
+	jRange := s.ranges[s.s[j].RangeID]
 
 	if s.documentPaths[iDocumentID] != s.documentPaths[jDocumentID] {
 		return s.documentPaths[iDocumentID] <= s.documentPaths[jDocumentID]
@@ -256,23 +386,35 @@ func (s sortableDocumentIDRangeIDs) Less(i, j int) bool {
 	return iRange.Start.Character-jRange.Start.Character < 0
 }
 
-func gatherMonikersLocations(ctx context.Context, state *State, data map[int]*datastructures.DefaultIDSetMap, getResultID func(r Range) int) chan semantic.MonikerLocations {
+func gatherMonikersLocations(ctx context.Context, p *producers, state *State, kind resultKind, getResultID func(r Range) int) (chan semantic.MonikerLocations, error) {
 	monikers := datastructures.NewDefaultIDSetMap()
-	for rangeID, r := range state.RangeData {
+	if err := state.eachRange(func(rangeID int, r Range) error {
 		if resultID := getResultID(r); resultID != 0 {
 			monikers.SetUnion(resultID, state.Monikers.Get(rangeID))
 		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	idsBySchemeByIdentifier := map[string]map[string][]int{}
-	for id := range data {
+	for _, id := range state.resultIDs(kind) {
 		monikerIDs := monikers.Get(id)
 		if monikerIDs == nil {
 			continue
 		}
 
+		var err error
 		monikerIDs.Each(func(monikerID int) {
-			moniker := state.MonikerData[monikerID]
+			if err != nil {
+				return
+			}
+
+			var moniker Moniker
+			if moniker, _, err = state.moniker(monikerID); err != nil {
+				return
+			}
+
 			idsByIdentifier, ok := idsBySchemeByIdentifier[moniker.Scheme]
 			if !ok {
 				idsByIdentifier = map[string][]int{}
@@ -280,35 +422,19 @@ func gatherMonikersLocations(ctx context.Context, state *State, data map[int]*da
 			}
 			idsByIdentifier[moniker.Identifier] = append(idsByIdentifier[moniker.Identifier], id)
 		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	ch := make(chan semantic.MonikerLocations)
 
-	go func() {
-		defer close(ch)
-
+	p.run(func() error {
 		for scheme, idsByIdentifier := range idsBySchemeByIdentifier {
 			for identifier, ids := range idsByIdentifier {
-				var locations []semantic.LocationData
-				for _, id := range ids {
-					data[id].Each(func(documentID int, rangeIDs *datastructures.IDSet) {
-						uri := state.DocumentData[documentID]
-						if strings.HasPrefix(uri, "..") {
-							return
-						}
-
-						rangeIDs.Each(func(id int) {
-							r := state.RangeData[id]
-
-							locations = append(locations, semantic.LocationData{
-								URI:            uri,
-								StartLine:      r.Start.Line,
-								StartCharacter: r.Start.Character,
-								EndLine:        r.End.Line,
-								EndCharacter:   r.End.Character,
-							})
-						})
-					})
+				locations, err := gatherLocations(state, kind, ids)
+				if err != nil {
+					return errors.Wrapf(err, "gathering locations of %s:%s", scheme, identifier)
 				}
 
 				if len(locations) == 0 {
@@ -330,13 +456,58 @@ func gatherMonikersLocations(ctx context.Context, state *State, data map[int]*da
 				select {
 				case ch <- data:
 				case <-ctx.Done():
-					return
+					return nil
 				}
 			}
 		}
-	}()
 
-	return ch
+		return nil
+	}, func() { close(ch) })
+
+	return ch, nil
+}
+
+// gatherLocations returns the locations of the ranges of the results of the given kind with
+// the given identifiers.
+func gatherLocations(state *State, kind resultKind, ids []int) ([]semantic.LocationData, error) {
+	var locations []semantic.LocationData
+	for _, id := range ids {
+		documentRanges, _, err := state.result(kind, id)
+		if err != nil {
+			return nil, err
+		}
+
+		documentRanges.Each(func(documentID int, rangeIDs *datastructures.IDSet) {
+			uri := state.DocumentData[documentID]
+			if strings.HasPrefix(uri, "..") {
+				return
+			}
+
+			rangeIDs.Each(func(id int) {
+				if err != nil {
+					return
+				}
+
+				var r Range
+				if r, _, err = state.rangeData(id); err != nil {
+					return
+				}
+
+				locations = append(locations, semantic.LocationData{
+					URI:            uri,
+					StartLine:      r.Start.Line,
+					StartCharacter: r.Start.Character,
+					EndLine:        r.End.Line,
+					EndCharacter:   r.End.Character,
+				})
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return locations, nil
 }
 
 // sortableLocations implements sort.Interface for locations.
@@ -356,10 +527,19 @@ func (s sortableLocations) Less(i, j int) bool {
 	return s[i].StartCharacter < s[j].StartCharacter
 }
 
-func gatherPackages(state *State) []semantic.Package {
+func gatherPackages(state *State) ([]semantic.Package, error) {
 	uniques := make(map[string]semantic.Package, state.ExportedMonikers.Len())
+
+	var err error
 	state.ExportedMonikers.Each(func(id int) {
-		source := state.MonikerData[id]
+		if err != nil {
+			return
+		}
+
+		var source Moniker
+		if source, _, err = state.moniker(id); err != nil {
+			return
+		}
 		packageInfo := state.PackageInformationData[source.PackageInformationID]
 
 		uniques[makeKey(source.Scheme, packageInfo.Name, packageInfo.Version)] = semantic.Package{
@@ -368,13 +548,16 @@ func gatherPackages(state *State) []semantic.Package {
 			Version: packageInfo.Version,
 		}
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	packages := make([]semantic.Package, 0, len(uniques))
 	for _, v := range uniques {
 		packages = append(packages, v)
 	}
 
-	return packages
+	return packages, nil
 }
 
 func gatherPackageReferences(state *State, packageDefinitions []semantic.Package) ([]semantic.PackageReference, error) {
@@ -391,8 +574,17 @@ func gatherPackageReferences(state *State, packageDefinitions []semantic.Package
 	}
 
 	uniques := make(map[string]ExpandedPackageReference, state.ImportedMonikers.Len())
+
+	var err error
 	state.ImportedMonikers.Each(func(id int) {
-		source := state.MonikerData[id]
+		if err != nil {
+			return
+		}
+
+		var source Moniker
+		if source, _, err = state.moniker(id); err != nil {
+			return
+		}
 		packageInfo := state.PackageInformationData[source.PackageInformationID]
 		key := makeKey(source.Scheme, packageInfo.Name, packageInfo.Version)
 
@@ -411,6 +603,9 @@ func gatherPackageReferences(state *State, packageDefinitions []semantic.Package
 			Identifiers: append(uniques[key].Identifiers, source.Identifier),
 		}
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	packageReferences := make([]semantic.PackageReference, 0, len(uniques))
 	for _, v := range uniques {
@@ -122,11 +122,11 @@ func newDocumentationChannels() documentationChannels {
 	return channels
 }
 
-func collectDocumentation(ctx context.Context, state *State) documentationChannels {
+func collectDocumentation(ctx context.Context, state *State) (documentationChannels, error) {
 	channels := newDocumentationChannels()
 	if state.DocumentationResultRoot == -1 {
 		channels.close()
-		return channels
+		return channels, nil
 	}
 
 	// Build a map of documentationResult IDs -> document IDs.
@@ -134,10 +134,21 @@ func collectDocumentation(ctx context.Context, state *State) documentationChanne
 	for documentID := range state.DocumentData {
 		ranges := state.Contains.Get(documentID)
 		if ranges != nil {
+			var err error
 			ranges.Each(func(rangeID int) {
-				rn := state.RangeData[rangeID]
-				documentationResultIDToDocumentID[rn.DocumentationResultID] = documentID
+				if err != nil {
+					return
+				}
+
+				var rn Range
+				if rn, _, err = state.rangeData(rangeID); err == nil {
+					documentationResultIDToDocumentID[rn.DocumentationResultID] = documentID
+				}
 			})
+			if err != nil {
+				channels.close()
+				return channels, err
+			}
 		}
 	}
 
@@ -186,7 +197,7 @@ func collectDocumentation(ctx context.Context, state *State) documentationChanne
 		}
 		channels.close()
 	}()
-	return channels
+	return channels, nil
 }
 
 type duplicateChecker struct {
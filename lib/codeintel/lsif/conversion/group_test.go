@@ -651,6 +651,12 @@ func TestGroupBundleData(t *testing.T) {
 	if diff := cmp.Diff(expectedReferences, references); diff != "" {
 		t.Errorf("unexpected references (-want +got):\n%s", diff)
 	}
+
+	for range actualBundleData.Implementations {
+	}
+	if err := actualBundleData.Err(); err != nil {
+		t.Fatalf("unexpected error grouping bundle data: %s", err)
+	}
 }
 
 //
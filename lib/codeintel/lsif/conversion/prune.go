@@ -29,18 +29,35 @@ func prune(ctx context.Context, state *State, root string, getChildren pathexist
 		}
 	}
 
-	pruneFromDefinitionReferences(state, state.DefinitionData)
-	pruneFromDefinitionReferences(state, state.ReferenceData)
+	for _, kind := range resultKinds {
+		if err := pruneFromDefinitionReferences(state, kind); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func pruneFromDefinitionReferences(state *State, definitionReferenceData map[int]*datastructures.DefaultIDSetMap) {
-	for _, documentRanges := range definitionReferenceData {
-		documentRanges.Each(func(documentID int, rangeIDs *datastructures.IDSet) {
-			if _, ok := state.DocumentData[documentID]; !ok {
+func pruneFromDefinitionReferences(state *State, kind resultKind) error {
+	for _, id := range state.resultIDs(kind) {
+		if err := state.updateResult(kind, id, func(documentRanges *datastructures.DefaultIDSetMap) bool {
+			var documentIDs []int
+			documentRanges.Each(func(documentID int, rangeIDs *datastructures.IDSet) {
+				if _, ok := state.DocumentData[documentID]; !ok {
+					documentIDs = append(documentIDs, documentID)
+				}
+			})
+
+			for _, documentID := range documentIDs {
 				// Document was pruned, remove reference
 				documentRanges.Delete(documentID)
 			}
-		})
+
+			return len(documentIDs) > 0
+		}); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
@@ -0,0 +1,461 @@
+package conversion
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion/datastructures"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/protocol"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/protocol/reader"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/scip"
+)
+
+// correlateFromIndexReader reads the given upload stream, which may hold either LSIF JSON
+// lines or a SCIP index, and returns a correlation state object.
+func correlateFromIndexReader(ctx context.Context, r io.Reader, root string, opts CorrelateOptions) (*State, error) {
+	br := bufio.NewReader(r)
+
+	isSCIP, err := scip.Sniff(br)
+	if err != nil {
+		return nil, err
+	}
+	if isSCIP {
+		return correlateFromSCIPReader(ctx, br, root, opts)
+	}
+
+	return correlateFromReader(ctx, br, root, opts)
+}
+
+// correlateFromSCIPReader reads the given SCIP index and returns a correlation state object
+// equivalent to the one an LSIF index with the same data would produce. Each symbol of the
+// index becomes a result set to which the ranges of its occurrences are linked. The data in
+// the correlation state is neither canonicalized nor pruned.
+func correlateFromSCIPReader(ctx context.Context, r io.Reader, root string, opts CorrelateOptions) (_ *State, err error) {
+	correlator := &scipCorrelator{
+		wrappedState:   newWrappedState(root, opts),
+		packageInfoIDs: map[scip.Package]int{},
+	}
+	correlator.symbols = newSCIPSymbolTable(correlator.Spilled)
+	defer func() {
+		if err != nil {
+			_ = correlator.Spilled.Close()
+		}
+	}()
+
+	i := 0
+	if err := scip.ReadIndex(r, scip.Visitor{
+		Metadata: correlator.correlateMetadata,
+		Document: func(document *scip.Document) error {
+			// stop reading more input once the context is canceled
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			i++
+			if err := correlator.correlateDocument(document); err != nil {
+				return errors.Wrapf(err, "index malformed in document %d (%s)", i, document.RelativePath)
+			}
+			return nil
+		},
+		ExternalSymbol: correlator.correlateExternalSymbol,
+	}); err != nil {
+		return nil, err
+	}
+
+	if correlator.LSIFVersion == "" {
+		return nil, ErrMissingMetaData
+	}
+
+	if err := correlator.correlateImplementations(); err != nil {
+		return nil, err
+	}
+	if err := correlator.correlateMonikers(); err != nil {
+		return nil, err
+	}
+	correlator.symbols.close()
+
+	return correlator.State, nil
+}
+
+type scipCorrelator struct {
+	*wrappedState
+	lastID         int
+	reRoot         bool // whether document paths are relative to the repository root
+	symbols        *scipSymbolTable
+	packageInfoIDs map[scip.Package]int
+}
+
+func (c *scipCorrelator) nextID() int {
+	c.lastID++
+	return c.lastID
+}
+
+func (c *scipCorrelator) correlateMetadata(metadata scip.Metadata) error {
+	// We make the same assumption about the project root as correlateMetaData. Document
+	// paths are relative to the project root in SCIP, so we need to make them relative
+	// to the dump root when the project root is the root of the repository.
+
+	projectRoot := metadata.ProjectRoot
+	if !strings.HasSuffix(projectRoot, "/") {
+		projectRoot += "/"
+	}
+
+	if c.dumpRoot != "" && !strings.HasSuffix(projectRoot, "/"+c.dumpRoot) {
+		projectRoot += c.dumpRoot
+		c.reRoot = true
+	}
+
+	c.LSIFVersion = fmt.Sprintf("scip-%d", metadata.Version)
+	c.ProjectRoot = projectRoot
+	return nil
+}
+
+func (c *scipCorrelator) correlateDocument(document *scip.Document) error {
+	if c.ProjectRoot == "" {
+		return ErrMissingMetaData
+	}
+
+	path := document.RelativePath
+	if c.reRoot {
+		relativePath, err := filepath.Rel(c.dumpRoot, path)
+		if err != nil {
+			return errors.Errorf("document path %q is not relative to dump root %q (%s)", path, c.dumpRoot, err)
+		}
+		path = relativePath
+	}
+
+	documentID := c.nextID()
+	c.DocumentData[documentID] = path
+
+	var diagnostics []Diagnostic
+	for _, occurrence := range document.Occurrences {
+		if len(occurrence.Range) != 3 && len(occurrence.Range) != 4 {
+			return errors.Errorf("occurrence of %q has malformed range %v", occurrence.Symbol, occurrence.Range)
+		}
+
+		startLine, startCharacter := occurrence.Start()
+		endLine, endCharacter := occurrence.End()
+
+		for _, diagnostic := range occurrence.Diagnostics {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity:       int(diagnostic.Severity),
+				Code:           diagnostic.Code,
+				Message:        diagnostic.Message,
+				Source:         diagnostic.Source,
+				StartLine:      startLine,
+				StartCharacter: startCharacter,
+				EndLine:        endLine,
+				EndCharacter:   endCharacter,
+			})
+		}
+
+		if occurrence.Symbol == "" {
+			continue
+		}
+
+		rangeID := c.nextID()
+		rangeData := Range{Range: reader.Range{RangeData: protocol.RangeData{
+			Start: protocol.Pos{Line: startLine, Character: startCharacter},
+			End:   protocol.Pos{Line: endLine, Character: endCharacter},
+		}}}
+
+		if len(occurrence.OverrideDocumentation) > 0 {
+			hoverResultID := c.nextID()
+			if err := c.setHoverResult(hoverResultID, strings.Join(occurrence.OverrideDocumentation, reader.HoverPartSeparator)); err != nil {
+				return err
+			}
+			rangeData = rangeData.SetHoverResultID(hoverResultID)
+		}
+
+		if err := c.setRangeData(rangeID, rangeData); err != nil {
+			return err
+		}
+		c.Contains.SetAdd(documentID, rangeID)
+
+		symbol, err := c.symbol(path, occurrence.Symbol)
+		if err != nil {
+			return err
+		}
+		changed := !symbol.hasOccurrences
+		symbol.hasOccurrences = true
+		c.NextData[rangeID] = symbol.resultSetID
+		if err := c.addResultRanges(referenceResults, symbol.referenceResultID, documentID, datastructures.IDSetWith(rangeID)); err != nil {
+			return err
+		}
+
+		if occurrence.IsDefinition() {
+			if symbol.definitionResultID == 0 {
+				changed = true
+				symbol.definitionResultID = c.nextID()
+				if err := c.setResult(definitionResults, symbol.definitionResultID, datastructures.NewDefaultIDSetMap()); err != nil {
+					return err
+				}
+				if err := c.updateResultSet(symbol.resultSetID, func(rs ResultSet) ResultSet {
+					return rs.SetDefinitionResultID(symbol.definitionResultID)
+				}); err != nil {
+					return err
+				}
+			}
+
+			if err := c.addResultRanges(definitionResults, symbol.definitionResultID, documentID, datastructures.IDSetWith(rangeID)); err != nil {
+				return err
+			}
+		}
+
+		if changed {
+			if err := c.symbols.put(symbol); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, info := range document.Symbols {
+		symbol, err := c.symbol(path, info.Symbol)
+		if err != nil {
+			return err
+		}
+
+		if err := c.correlateSymbolInformation(path, symbol, info); err != nil {
+			return err
+		}
+	}
+
+	if len(diagnostics) > 0 {
+		diagnosticResultID := c.nextID()
+		if err := c.setDiagnosticResult(diagnosticResultID, diagnostics); err != nil {
+			return err
+		}
+		c.Diagnostics.SetAdd(documentID, diagnosticResultID)
+	}
+
+	return nil
+}
+
+func (c *scipCorrelator) correlateExternalSymbol(info *scip.SymbolInformation) error {
+	symbol, ok, err := c.symbols.get(info.Symbol)
+	if err != nil {
+		return err
+	}
+	if !ok || symbol.resultSetID == 0 {
+		if len(info.Documentation) == 0 {
+			return nil
+		}
+
+		// Avoid creating hover results for symbols that are never referenced by the index
+		if !ok {
+			symbol = &scipSymbol{key: info.Symbol, symbol: info.Symbol}
+		}
+		symbol.documentation = info.Documentation
+		return c.symbols.put(symbol)
+	}
+
+	return c.correlateSymbolInformation("", symbol, info)
+}
+
+// correlateSymbolInformation attaches the documentation of the given symbol to its result set,
+// links its reference result to those of the symbols it is related to, and records the symbols
+// it implements.
+func (c *scipCorrelator) correlateSymbolInformation(path string, symbol *scipSymbol, info *scip.SymbolInformation) error {
+	if symbol.hoverResultID == 0 && len(info.Documentation) > 0 {
+		if err := c.setDocumentation(symbol, info.Documentation); err != nil {
+			return err
+		}
+		if err := c.symbols.put(symbol); err != nil {
+			return err
+		}
+	}
+
+	for _, relationship := range info.Relationships {
+		if !relationship.IsReference && !relationship.IsImplementation {
+			continue
+		}
+
+		related, err := c.symbol(path, relationship.Symbol)
+		if err != nil {
+			return err
+		}
+
+		if relationship.IsReference {
+			c.LinkedReferenceResults[symbol.referenceResultID] = append(c.LinkedReferenceResults[symbol.referenceResultID], related.referenceResultID)
+		}
+		if relationship.IsImplementation {
+			related.implementations = append(related.implementations, symbol.key)
+			if err := c.symbols.put(related); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *scipCorrelator) setDocumentation(symbol *scipSymbol, documentation []string) error {
+	if len(documentation) == 0 || symbol.hoverResultID != 0 {
+		return nil
+	}
+
+	symbol.hoverResultID = c.nextID()
+	if err := c.setHoverResult(symbol.hoverResultID, strings.Join(documentation, reader.HoverPartSeparator)); err != nil {
+		return err
+	}
+
+	return c.updateResultSet(symbol.resultSetID, func(rs ResultSet) ResultSet {
+		return rs.SetHoverResultID(symbol.hoverResultID)
+	})
+}
+
+// updateResultSet replaces the result set with the given identifier with the result of the
+// given function.
+func (c *scipCorrelator) updateResultSet(id int, f func(rs ResultSet) ResultSet) error {
+	rs, _, err := c.resultSetData(id)
+	if err != nil {
+		return err
+	}
+
+	return c.setResultSetData(id, f(rs))
+}
+
+// symbol returns the symbol with the given name, creating its result set and reference
+// result on first use. Local symbols are scoped to the document in which they occur.
+func (c *scipCorrelator) symbol(path, name string) (*scipSymbol, error) {
+	key := name
+	if scip.IsLocalSymbol(name) {
+		key = path + "\x00" + name
+	}
+
+	symbol, ok, err := c.symbols.get(key)
+	if err != nil {
+		return nil, err
+	}
+	if ok && symbol.resultSetID != 0 {
+		return symbol, nil
+	}
+	if !ok {
+		symbol = &scipSymbol{key: key, symbol: name}
+	}
+
+	symbol.resultSetID = c.nextID()
+	symbol.referenceResultID = c.nextID()
+	if err := c.setResultSetData(symbol.resultSetID, ResultSet{}.SetReferenceResultID(symbol.referenceResultID)); err != nil {
+		return nil, err
+	}
+	if err := c.setResult(referenceResults, symbol.referenceResultID, datastructures.NewDefaultIDSetMap()); err != nil {
+		return nil, err
+	}
+
+	documentation := symbol.documentation
+	symbol.documentation = nil
+	if err := c.setDocumentation(symbol, documentation); err != nil {
+		return nil, err
+	}
+	if err := c.symbols.put(symbol); err != nil {
+		return nil, err
+	}
+
+	return symbol, nil
+}
+
+// correlateImplementations creates an implementation result for each symbol that is implemented
+// by another symbol of the index, holding the definitions of the implementing symbols.
+func (c *scipCorrelator) correlateImplementations() error {
+	return c.symbols.each(func(symbol *scipSymbol) error {
+		if len(symbol.implementations) == 0 {
+			return nil
+		}
+
+		documentRanges := datastructures.NewDefaultIDSetMap()
+		for _, key := range symbol.implementations {
+			implementation, ok, err := c.symbols.get(key)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+
+			definitions, ok, err := c.result(definitionResults, implementation.definitionResultID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				definitions.Each(func(documentID int, rangeIDs *datastructures.IDSet) {
+					documentRanges.SetUnion(documentID, rangeIDs)
+				})
+			}
+		}
+
+		implementationResultID := c.nextID()
+		if err := c.setResult(implementationResults, implementationResultID, documentRanges); err != nil {
+			return err
+		}
+		return c.updateResultSet(symbol.resultSetID, func(rs ResultSet) ResultSet {
+			return rs.SetImplementationResultID(implementationResultID)
+		})
+	})
+}
+
+// correlateMonikers attaches a moniker to the result set of each global symbol that occurs in
+// the index. Symbols that are defined in the index are exported, and all other symbols are
+// imported from the package named by the symbol.
+func (c *scipCorrelator) correlateMonikers() error {
+	return c.symbols.each(func(symbol *scipSymbol) error {
+		if !symbol.hasOccurrences || scip.IsLocalSymbol(symbol.symbol) {
+			return nil
+		}
+
+		parsed, err := scip.ParseSymbol(symbol.symbol)
+		if err != nil {
+			// Symbol is still navigable within the index
+			return nil
+		}
+
+		scheme := parsed.Package.Manager
+		if scheme == "" {
+			scheme = parsed.Scheme
+		}
+
+		kind := "import"
+		if symbol.definitionResultID != 0 {
+			kind = "export"
+		}
+
+		monikerID := c.nextID()
+		moniker := Moniker{Moniker: reader.Moniker{
+			Kind:       kind,
+			Scheme:     scheme,
+			Identifier: parsed.Descriptors,
+		}}
+		c.Monikers.SetAdd(symbol.resultSetID, monikerID)
+
+		if parsed.Package.Name == "" {
+			// Without a package the moniker cannot be linked to another index
+			return c.setMoniker(monikerID, moniker)
+		}
+
+		packageInfoID, ok := c.packageInfoIDs[parsed.Package]
+		if !ok {
+			packageInfoID = c.nextID()
+			c.packageInfoIDs[parsed.Package] = packageInfoID
+			c.PackageInformationData[packageInfoID] = PackageInformation{
+				Name:    parsed.Package.Name,
+				Version: parsed.Package.Version,
+			}
+		}
+		if err := c.setMoniker(monikerID, moniker.SetPackageInformationID(packageInfoID)); err != nil {
+			return err
+		}
+
+		if kind == "export" {
+			c.ExportedMonikers.Add(monikerID)
+		} else {
+			c.ImportedMonikers.Add(monikerID)
+		}
+		return nil
+	})
+}
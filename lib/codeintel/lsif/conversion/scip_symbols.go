@@ -0,0 +1,192 @@
+package conversion
+
+import (
+	"hash/fnv"
+)
+
+// scipSymbolOverhead is the approximate in-memory size of the fixed-width parts of a symbol
+// held in a symbol table.
+const scipSymbolOverhead = 128
+
+// scipSymbol tracks the identifiers of the elements created for a single symbol.
+type scipSymbol struct {
+	id                 int    // identifier within the symbol table, zero until the symbol is stored
+	key                string // local symbols are keyed by document path
+	symbol             string
+	resultSetID        int // zero until the symbol is referenced by the index
+	definitionResultID int
+	referenceResultID  int
+	hoverResultID      int
+	hasOccurrences     bool
+	documentation      []string // documentation of an external symbol not yet referenced
+	implementations    []string // keys of the symbols that declare an implementation relationship to this one
+}
+
+// scipSymbolPayload is the serialized form of a symbol written to the spill store.
+type scipSymbolPayload struct {
+	Key                string   `json:"key"`
+	Symbol             string   `json:"symbol"`
+	ResultSetID        int      `json:"resultSetId,omitempty"`
+	DefinitionResultID int      `json:"definitionResultId,omitempty"`
+	ReferenceResultID  int      `json:"referenceResultId,omitempty"`
+	HoverResultID      int      `json:"hoverResultId,omitempty"`
+	HasOccurrences     bool     `json:"hasOccurrences,omitempty"`
+	Documentation      []string `json:"documentation,omitempty"`
+	Implementations    []string `json:"implementations,omitempty"`
+}
+
+// scipSymbolTable holds the symbols of a SCIP index by key. Symbols are held in memory until
+// the memory budget of the spill store is exhausted, after which they are written to the spill
+// store. Only a hash of the key of each spilled symbol is kept in memory.
+//
+// Symbols read from the spill store are copies, so a modified symbol must be stored again
+// with put.
+type scipSymbolTable struct {
+	spill      *SpillStore
+	lastID     int
+	ids        map[string]int      // identifiers of the symbols held in memory
+	symbols    map[int]*scipSymbol // symbols held in memory
+	hashedIDs  map[uint64]int      // identifiers of spilled symbols by the hash of their key
+	collisions map[string]int      // identifiers of spilled symbols whose key hash is already taken
+}
+
+func newSCIPSymbolTable(spill *SpillStore) *scipSymbolTable {
+	return &scipSymbolTable{
+		spill:      spill,
+		ids:        map[string]int{},
+		symbols:    map[int]*scipSymbol{},
+		hashedIDs:  map[uint64]int{},
+		collisions: map[string]int{},
+	}
+}
+
+// get returns the symbol with the given key.
+func (t *scipSymbolTable) get(key string) (*scipSymbol, bool, error) {
+	if id, ok := t.ids[key]; ok {
+		return t.symbols[id], true, nil
+	}
+	if t.spill == nil {
+		return nil, false, nil
+	}
+
+	id, ok := t.collisions[key]
+	if !ok {
+		if id, ok = t.hashedIDs[hashSCIPSymbolKey(key)]; !ok {
+			return nil, false, nil
+		}
+	}
+
+	symbol, ok, err := t.read(id)
+	if err != nil || !ok || symbol.key != key {
+		return nil, false, err
+	}
+
+	return symbol, true, nil
+}
+
+// put stores the given symbol in memory, or in the spill store if holding it in memory would
+// exceed the memory budget.
+func (t *scipSymbolTable) put(symbol *scipSymbol) error {
+	if symbol.id == 0 {
+		t.lastID++
+		symbol.id = t.lastID
+
+		if t.spill == nil || t.spill.reserve(scipSymbolSize(symbol)) {
+			t.ids[symbol.key] = symbol.id
+			t.symbols[symbol.id] = symbol
+			return nil
+		}
+
+		if hash := hashSCIPSymbolKey(symbol.key); t.hashedIDs[hash] == 0 {
+			t.hashedIDs[hash] = symbol.id
+		} else {
+			t.collisions[symbol.key] = symbol.id
+		}
+	} else if _, ok := t.symbols[symbol.id]; ok {
+		t.symbols[symbol.id] = symbol
+		return nil
+	}
+
+	return t.spill.writeJSON(spilledSCIPSymbol, symbol.id, scipSymbolPayload{
+		Key:                symbol.key,
+		Symbol:             symbol.symbol,
+		ResultSetID:        symbol.resultSetID,
+		DefinitionResultID: symbol.definitionResultID,
+		ReferenceResultID:  symbol.referenceResultID,
+		HoverResultID:      symbol.hoverResultID,
+		HasOccurrences:     symbol.hasOccurrences,
+		Documentation:      symbol.documentation,
+		Implementations:    symbol.implementations,
+	})
+}
+
+// each calls the given function with every symbol of the table in the order in which the
+// symbols were first stored.
+func (t *scipSymbolTable) each(f func(symbol *scipSymbol) error) error {
+	for id := 1; id <= t.lastID; id++ {
+		symbol, ok := t.symbols[id]
+		if !ok {
+			var err error
+			if symbol, ok, err = t.read(id); err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		if err := f(symbol); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// close releases the symbols of the table. Spilled symbols are no longer readable.
+func (t *scipSymbolTable) close() {
+	if t.spill != nil {
+		t.spill.discard(spilledSCIPSymbol)
+	}
+
+	*t = scipSymbolTable{}
+}
+
+func (t *scipSymbolTable) read(id int) (*scipSymbol, bool, error) {
+	var spilled scipSymbolPayload
+	ok, err := t.spill.readJSON(spilledSCIPSymbol, id, &spilled)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &scipSymbol{
+		id:                 id,
+		key:                spilled.Key,
+		symbol:             spilled.Symbol,
+		resultSetID:        spilled.ResultSetID,
+		definitionResultID: spilled.DefinitionResultID,
+		referenceResultID:  spilled.ReferenceResultID,
+		hoverResultID:      spilled.HoverResultID,
+		hasOccurrences:     spilled.HasOccurrences,
+		documentation:      spilled.Documentation,
+		implementations:    spilled.Implementations,
+	}, true, nil
+}
+
+func scipSymbolSize(symbol *scipSymbol) int64 {
+	size := int64(scipSymbolOverhead + len(symbol.key) + len(symbol.symbol))
+	for _, part := range symbol.documentation {
+		size += int64(len(part))
+	}
+	for _, key := range symbol.implementations {
+		size += int64(len(key))
+	}
+
+	return size
+}
+
+func hashSCIPSymbolKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
@@ -0,0 +1,316 @@
+package conversion
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion/datastructures"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/scip"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+const (
+	testSCIPFoo     = "scip-go gomod github.com/test/project v1.0.0 `github.com/test/project`/Foo()."
+	testSCIPPrintln = "scip-go gomod std go1.18 `fmt`/Println()."
+	testSCIPFooImpl = "scip-go gomod github.com/test/project v1.0.0 `github.com/test/project`/impl#Foo()."
+)
+
+var testSCIPIndex = &scip.Index{
+	Metadata: scip.Metadata{
+		ToolInfo:    scip.ToolInfo{Name: "scip-go", Version: "0.1.0"},
+		ProjectRoot: "file:///test/root",
+	},
+	Documents: []*scip.Document{
+		{
+			RelativePath: "foo.go",
+			Occurrences: []*scip.Occurrence{
+				{Range: []int32{1, 5, 8}, Symbol: testSCIPFoo, SymbolRoles: scip.SymbolRoleDefinition},
+				{Range: []int32{2, 1, 2}, Symbol: "local 0", SymbolRoles: scip.SymbolRoleDefinition},
+				{Range: []int32{3, 1, 2}, Symbol: "local 0"},
+			},
+			Symbols: []*scip.SymbolInformation{
+				{Symbol: testSCIPFoo, Documentation: []string{"```go\nfunc Foo()\n```", "Foo does things."}},
+			},
+		},
+		{
+			RelativePath: "impl.go",
+			Occurrences: []*scip.Occurrence{
+				{Range: []int32{1, 5, 8}, Symbol: testSCIPFooImpl, SymbolRoles: scip.SymbolRoleDefinition},
+			},
+			Symbols: []*scip.SymbolInformation{
+				{Symbol: testSCIPFooImpl, Relationships: []*scip.Relationship{{Symbol: testSCIPFoo, IsImplementation: true}}},
+			},
+		},
+		{
+			RelativePath: "bar.go",
+			Occurrences: []*scip.Occurrence{
+				{Range: []int32{4, 1, 4}, Symbol: testSCIPFoo},
+				{Range: []int32{5, 5, 12}, Symbol: testSCIPPrintln},
+				{Range: []int32{6, 1, 7, 2}, Symbol: "local 0", OverrideDocumentation: []string{"shadowed"}},
+				{
+					Range: []int32{8, 0, 10},
+					Diagnostics: []*scip.Diagnostic{
+						{Severity: 1, Code: "E1", Message: "broken", Source: "go"},
+					},
+				},
+			},
+		},
+	},
+	ExternalSymbols: []*scip.SymbolInformation{
+		{Symbol: testSCIPPrintln, Documentation: []string{"Println formats using the default formats."}},
+		{Symbol: "scip-go gomod std go1.18 `os`/Exit().", Documentation: []string{"Exit exits."}},
+	},
+}
+
+func TestCorrelateSCIP(t *testing.T) {
+	state, err := correlateFromIndexReader(context.Background(), bytes.NewReader(scip.Marshal(testSCIPIndex)), "", CorrelateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+	if err := canonicalize(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
+
+	if state.ProjectRoot != "file:///test/root/" {
+		t.Errorf("unexpected project root. want=%q have=%q", "file:///test/root/", state.ProjectRoot)
+	}
+
+	_, fooDefinition := findRange(t, state, "foo.go", 1, 5)
+	_, fooReference := findRange(t, state, "bar.go", 4, 1)
+	if fooDefinition.DefinitionResultID == 0 || fooDefinition.DefinitionResultID != fooReference.DefinitionResultID {
+		t.Errorf("expected ranges to share a definition result")
+	}
+	if fooDefinition.ReferenceResultID == 0 || fooDefinition.ReferenceResultID != fooReference.ReferenceResultID {
+		t.Errorf("expected ranges to share a reference result")
+	}
+	if hover := state.HoverData[fooReference.HoverResultID]; hover != "```go\nfunc Foo()\n```\n\n---\n\nFoo does things." {
+		t.Errorf("unexpected hover text %q", hover)
+	}
+
+	fooImplementationID, fooImplementation := findRange(t, state, "impl.go", 1, 5)
+	if fooReference.ImplementationResultID == 0 {
+		t.Fatalf("expected an implementation result")
+	}
+	var implementations []int
+	state.ImplementationData[fooReference.ImplementationResultID].Each(func(documentID int, rangeIDs *datastructures.IDSet) {
+		rangeIDs.Each(func(id int) { implementations = append(implementations, id) })
+	})
+	if diff := cmp.Diff([]int{fooImplementationID}, implementations); diff != "" {
+		t.Errorf("unexpected implementations (-want +got):\n%s", diff)
+	}
+	if fooImplementation.ImplementationResultID != 0 {
+		t.Errorf("expected no implementation result for the implementing symbol")
+	}
+
+	// Local symbols are scoped to their document
+	localDefinitionID, localDefinition := findRange(t, state, "foo.go", 2, 1)
+	_, localReference := findRange(t, state, "foo.go", 3, 1)
+	_, otherLocal := findRange(t, state, "bar.go", 6, 1)
+	if localDefinition.DefinitionResultID != localReference.DefinitionResultID {
+		t.Errorf("expected local ranges to share a definition result")
+	}
+	if otherLocal.DefinitionResultID != 0 || otherLocal.ReferenceResultID == localReference.ReferenceResultID {
+		t.Errorf("expected local symbols of different documents to be distinct")
+	}
+	if hover := state.HoverData[otherLocal.HoverResultID]; hover != "shadowed" {
+		t.Errorf("unexpected hover text %q", hover)
+	}
+	if state.Monikers.SetLen(localDefinitionID) != 0 {
+		t.Errorf("expected no monikers for local symbols")
+	}
+
+	printlnID, println := findRange(t, state, "bar.go", 5, 5)
+	if hover := state.HoverData[println.HoverResultID]; hover != "Println formats using the default formats." {
+		t.Errorf("unexpected hover text %q", hover)
+	}
+
+	monikers := map[string]string{}
+	state.Monikers.SetEach(printlnID, func(id int) {
+		monikers[state.MonikerData[id].Identifier] = state.MonikerData[id].Kind
+	})
+	if diff := cmp.Diff(map[string]string{"`fmt`/Println().": "import"}, monikers); diff != "" {
+		t.Errorf("unexpected monikers (-want +got):\n%s", diff)
+	}
+
+	// Documentation of unreferenced external symbols is dropped
+	if len(state.HoverData) != 3 {
+		t.Errorf("unexpected number of hover results. want=%d have=%d", 3, len(state.HoverData))
+	}
+
+	var diagnostics []Diagnostic
+	state.Diagnostics.SetEach(findDocument(t, state, "bar.go"), func(id int) {
+		diagnostics = append(diagnostics, state.DiagnosticResults[id]...)
+	})
+	expectedDiagnostics := []Diagnostic{
+		{Severity: 1, Code: "E1", Message: "broken", Source: "go", StartLine: 8, StartCharacter: 0, EndLine: 8, EndCharacter: 10},
+	}
+	if diff := cmp.Diff(expectedDiagnostics, diagnostics); diff != "" {
+		t.Errorf("unexpected diagnostics (-want +got):\n%s", diff)
+	}
+}
+
+func TestCorrelateSCIPPackages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	groupedBundleData, err := CorrelateWithOptions(ctx, bytes.NewReader(scip.Marshal(testSCIPIndex)), "", nil, CorrelateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+	maps := semantic.GroupedBundleDataChansToMaps(groupedBundleData)
+
+	expectedPackages := []semantic.Package{
+		{Scheme: "gomod", Name: "github.com/test/project", Version: "v1.0.0"},
+	}
+	if diff := cmp.Diff(expectedPackages, maps.Packages); diff != "" {
+		t.Errorf("unexpected packages (-want +got):\n%s", diff)
+	}
+
+	var packageReferences []semantic.Package
+	for _, packageReference := range maps.PackageReferences {
+		packageReferences = append(packageReferences, packageReference.Package)
+	}
+	expectedPackageReferences := []semantic.Package{
+		{Scheme: "gomod", Name: "std", Version: "go1.18"},
+	}
+	if diff := cmp.Diff(expectedPackageReferences, packageReferences); diff != "" {
+		t.Errorf("unexpected package references (-want +got):\n%s", diff)
+	}
+
+	var definitionIdentifiers []string
+	for _, monikers := range maps.Definitions {
+		for identifier := range monikers {
+			definitionIdentifiers = append(definitionIdentifiers, identifier)
+		}
+	}
+	sort.Strings(definitionIdentifiers)
+	if diff := cmp.Diff([]string{"`github.com/test/project`/Foo().", "`github.com/test/project`/impl#Foo()."}, definitionIdentifiers); diff != "" {
+		t.Errorf("unexpected definition identifiers (-want +got):\n%s", diff)
+	}
+
+	expectedImplementations := map[string]map[string][]semantic.LocationData{
+		"gomod": {
+			"`github.com/test/project`/Foo().": {
+				{URI: "impl.go", StartLine: 1, StartCharacter: 5, EndLine: 1, EndCharacter: 8},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedImplementations, maps.Implementations); diff != "" {
+		t.Errorf("unexpected implementations (-want +got):\n%s", diff)
+	}
+}
+
+func TestCorrelateSCIPWithSpillMatchesInMemory(t *testing.T) {
+	input := scip.Marshal(testSCIPIndex)
+
+	expected := correlateToMaps(t, input, CorrelateOptions{})
+	actual := correlateToMaps(t, input, CorrelateOptions{MemoryBudget: 1, SpillDir: t.TempDir()})
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected bundle data (-want +got):\n%s", diff)
+	}
+}
+
+func TestCorrelateSCIPSymbolTableSpill(t *testing.T) {
+	spill := newSpillStore(t.TempDir(), 0)
+	defer spill.Close()
+
+	symbols := newSCIPSymbolTable(spill)
+	if err := symbols.put(&scipSymbol{key: testSCIPFoo, symbol: testSCIPFoo, resultSetID: 1}); err != nil {
+		t.Fatalf("unexpected error storing symbol: %s", err)
+	}
+
+	symbol, ok, err := symbols.get(testSCIPFoo)
+	if err != nil || !ok {
+		t.Fatalf("expected spilled symbol. ok=%v err=%v", ok, err)
+	}
+	if len(symbols.symbols) != 0 || !spill.contains(spilledSCIPSymbol, symbol.id) {
+		t.Fatalf("expected symbol to be spilled")
+	}
+
+	symbol.implementations = append(symbol.implementations, testSCIPFooImpl)
+	if err := symbols.put(symbol); err != nil {
+		t.Fatalf("unexpected error storing symbol: %s", err)
+	}
+	if symbol, _, _ := symbols.get(testSCIPFoo); symbol == nil || len(symbol.implementations) != 1 {
+		t.Errorf("expected updated spilled symbol, have %+v", symbol)
+	}
+	if _, ok, _ := symbols.get(testSCIPPrintln); ok {
+		t.Errorf("unexpected symbol %q", testSCIPPrintln)
+	}
+
+	symbols.close()
+	if spill.Len() != 0 {
+		t.Errorf("expected spilled symbols to be discarded, have %d elements", spill.Len())
+	}
+}
+
+func TestCorrelateSCIPRoot(t *testing.T) {
+	testCases := []struct {
+		projectRoot  string
+		relativePath string
+	}{
+		// Project root is the repository root
+		{projectRoot: "file:///test", relativePath: "root/foo.go"},
+		// Project root is the dump root
+		{projectRoot: "file:///test/root", relativePath: "foo.go"},
+	}
+
+	for _, testCase := range testCases {
+		index := &scip.Index{
+			Metadata:  scip.Metadata{ProjectRoot: testCase.projectRoot},
+			Documents: []*scip.Document{{RelativePath: testCase.relativePath}},
+		}
+
+		state, err := correlateFromIndexReader(context.Background(), bytes.NewReader(scip.Marshal(index)), "root/", CorrelateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error correlating input: %s", err)
+		}
+
+		if state.ProjectRoot != "file:///test/root/" {
+			t.Errorf("unexpected project root. want=%q have=%q", "file:///test/root/", state.ProjectRoot)
+		}
+		if diff := cmp.Diff(map[int]string{1: "foo.go"}, state.DocumentData); diff != "" {
+			t.Errorf("unexpected documents (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestCorrelateSCIPMissingMetadata(t *testing.T) {
+	// Drop the empty metadata message written by Marshal
+	data := scip.Marshal(&scip.Index{Documents: testSCIPIndex.Documents})[2:]
+
+	if _, err := correlateFromIndexReader(context.Background(), bytes.NewReader(data), "", CorrelateOptions{}); !errors.Is(err, ErrMissingMetaData) {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrMissingMetaData, err)
+	}
+}
+
+func findDocument(t *testing.T, state *State, path string) int {
+	for id, documentPath := range state.DocumentData {
+		if documentPath == path {
+			return id
+		}
+	}
+
+	t.Fatalf("no document %q", path)
+	return 0
+}
+
+func findRange(t *testing.T, state *State, path string, line, character int) (int, Range) {
+	var rangeID int
+	state.Contains.SetEach(findDocument(t, state, path), func(id int) {
+		if r := state.RangeData[id]; r.Start.Line == line && r.Start.Character == character {
+			rangeID = id
+		}
+	})
+
+	if rangeID == 0 {
+		t.Fatalf("no range at %s:%d:%d", path, line, character)
+	}
+	return rangeID, state.RangeData[rangeID]
+}
@@ -0,0 +1,359 @@
+package conversion
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion/datastructures"
+)
+
+// spillKind distinguishes the types of elements held in a spill store.
+type spillKind int
+
+const (
+	spilledHoverResult spillKind = iota
+	spilledDiagnosticResult
+	spilledRange
+	spilledResultSet
+	spilledMoniker
+	spilledDefinitionResult
+	spilledReferenceResult
+	spilledImplementationResult
+	spilledSCIPSymbol
+	numSpillKinds
+)
+
+// SpillStore accounts for the approximate size of the elements of the correlation state
+// held in memory, and holds the elements that did not fit into the configured memory budget
+// in a temporary file. Only the location of each spilled element within the file is kept
+// in memory.
+//
+// Elements are written during correlation and canonicalization, and read back while grouping
+// bundle data. The store is not safe for concurrent writes, but can be read concurrently once
+// all writes have completed.
+type SpillStore struct {
+	dir     string
+	budget  int64
+	used    int64 // approximate size of the elements held in memory
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	size    int64
+	closed  bool
+	offsets [numSpillKinds]map[int]spillSpan
+}
+
+// spillSpan locates a payload within the spill file. The payloads of definition, reference,
+// and implementation results are written as a chain of fragments, each of which holds the
+// span of the previous fragment. A negative offset denotes an empty payload.
+type spillSpan struct {
+	offset int64
+	length int64
+}
+
+var emptySpan = spillSpan{offset: -1}
+
+// newSpillStore creates a spill store with the given memory budget. The backing temporary
+// file is created in the given directory once the first element is spilled. If dir is empty,
+// the default directory for temporary files is used.
+func newSpillStore(dir string, budget int64) *SpillStore {
+	s := &SpillStore{dir: dir, budget: budget}
+	for kind := range s.offsets {
+		s.offsets[kind] = map[int]spillSpan{}
+	}
+
+	return s
+}
+
+// Len returns the number of elements in the store.
+func (s *SpillStore) Len() int {
+	n := 0
+	for _, offsets := range s.offsets {
+		n += len(offsets)
+	}
+
+	return n
+}
+
+// Size returns the number of bytes written to the store.
+func (s *SpillStore) Size() int64 {
+	return s.size
+}
+
+// reserve returns true and accounts for an element of the given size if it fits into the
+// memory budget. Otherwise, the element should be spilled.
+func (s *SpillStore) reserve(size int64) bool {
+	if s.used+size > s.budget {
+		return false
+	}
+
+	s.used += size
+	return true
+}
+
+// release returns the given size of an element moved out of memory to the memory budget.
+func (s *SpillStore) release(size int64) {
+	if s.used -= size; s.used < 0 {
+		s.used = 0
+	}
+}
+
+// discard forgets the elements of the given kind. Their payloads remain in the spill file
+// until the store is closed.
+func (s *SpillStore) discard(kind spillKind) {
+	s.offsets[kind] = map[int]spillSpan{}
+}
+
+func (s *SpillStore) contains(kind spillKind, id int) bool {
+	_, ok := s.offsets[kind][id]
+	return ok
+}
+
+// ids returns the identifiers of the elements of the given kind in the store.
+func (s *SpillStore) ids(kind spillKind) []int {
+	ids := make([]int, 0, len(s.offsets[kind]))
+	for id := range s.offsets[kind] {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func (s *SpillStore) write(kind spillKind, id int, payload []byte) error {
+	span, err := s.writePayload(payload)
+	if err != nil {
+		return err
+	}
+
+	s.offsets[kind][id] = span
+	return nil
+}
+
+// writeFragment writes a fragment of the chained payload of the given element. If replace is
+// false, the fragment is linked to the fragments already written for the element.
+func (s *SpillStore) writeFragment(kind spillKind, id int, payload []byte, replace bool) error {
+	prev, ok := s.offsets[kind][id]
+	if replace || !ok {
+		prev = emptySpan
+	}
+
+	header := make([]byte, 2*binary.MaxVarintLen64)
+	n := binary.PutVarint(header, prev.offset)
+	n += binary.PutVarint(header[n:], prev.length)
+
+	span, err := s.writePayload(append(header[:n], payload...))
+	if err != nil {
+		return err
+	}
+
+	s.offsets[kind][id] = span
+	return nil
+}
+
+func (s *SpillStore) writePayload(payload []byte) (spillSpan, error) {
+	if s.file == nil {
+		file, err := os.CreateTemp(s.dir, "lsif-correlation-*.spill")
+		if err != nil {
+			return spillSpan{}, errors.Wrap(err, "os.CreateTemp")
+		}
+
+		s.file = file
+		s.writer = bufio.NewWriter(file)
+	}
+
+	if _, err := s.writer.Write(payload); err != nil {
+		return spillSpan{}, errors.Wrap(err, "writing spill file")
+	}
+
+	span := spillSpan{offset: s.size, length: int64(len(payload))}
+	s.size += int64(len(payload))
+	return span, nil
+}
+
+func (s *SpillStore) read(kind spillKind, id int) ([]byte, bool, error) {
+	span, ok := s.offsets[kind][id]
+	if !ok {
+		return nil, false, nil
+	}
+
+	payload, err := s.readSpan(span)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return payload, true, nil
+}
+
+// readFragments returns the fragments of the chained payload of the given element, starting
+// with the most recently written fragment.
+func (s *SpillStore) readFragments(kind spillKind, id int) ([][]byte, bool, error) {
+	span, ok := s.offsets[kind][id]
+	if !ok {
+		return nil, false, nil
+	}
+
+	var fragments [][]byte
+	for span.offset >= 0 {
+		payload, err := s.readSpan(span)
+		if err != nil {
+			return nil, false, err
+		}
+
+		offset, n := binary.Varint(payload)
+		if n <= 0 {
+			return nil, false, errors.New("malformed spill fragment")
+		}
+		length, m := binary.Varint(payload[n:])
+		if m <= 0 {
+			return nil, false, errors.New("malformed spill fragment")
+		}
+
+		fragments = append(fragments, payload[n+m:])
+		span = spillSpan{offset: offset, length: length}
+	}
+
+	return fragments, true, nil
+}
+
+func (s *SpillStore) readSpan(span spillSpan) ([]byte, error) {
+	if span.offset < 0 {
+		return nil, nil
+	}
+
+	// Ensure any buffered payloads are visible to ReadAt
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, errors.New("spill file is closed")
+	}
+	if s.writer.Buffered() > 0 {
+		if err := s.writer.Flush(); err != nil {
+			s.mu.Unlock()
+			return nil, errors.Wrap(err, "flushing spill file")
+		}
+	}
+	s.mu.Unlock()
+
+	payload := make([]byte, span.length)
+	if n, err := s.file.ReadAt(payload, span.offset); n < len(payload) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, errors.Wrap(err, "reading spill file")
+	}
+
+	return payload, nil
+}
+
+func (s *SpillStore) writeJSON(kind spillKind, id int, value interface{}) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return s.write(kind, id, payload)
+}
+
+func (s *SpillStore) readJSON(kind spillKind, id int, value interface{}) (bool, error) {
+	payload, ok, err := s.read(kind, id)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	if err := json.Unmarshal(payload, value); err != nil {
+		return false, errors.Wrap(err, "decoding spilled element")
+	}
+
+	return true, nil
+}
+
+// writeResult writes the document ranges of the given definition, reference, or implementation
+// result. If replace is false, the ranges are added to those already written for the result.
+func (s *SpillStore) writeResult(kind spillKind, id int, documentRanges *datastructures.DefaultIDSetMap, replace bool) error {
+	var payload []byte
+	buf := make([]byte, binary.MaxVarintLen64)
+	put := func(v int) {
+		n := binary.PutUvarint(buf, uint64(v))
+		payload = append(payload, buf[:n]...)
+	}
+
+	documentRanges.Each(func(documentID int, rangeIDs *datastructures.IDSet) {
+		put(documentID)
+		put(rangeIDs.Len())
+		rangeIDs.Each(put)
+	})
+
+	return s.writeFragment(kind, id, payload, replace)
+}
+
+// readResult returns the document ranges of the given definition, reference, or implementation
+// result, merged from all of its fragments.
+func (s *SpillStore) readResult(kind spillKind, id int) (*datastructures.DefaultIDSetMap, bool, error) {
+	fragments, ok, err := s.readFragments(kind, id)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	documentRanges := datastructures.NewDefaultIDSetMap()
+	for _, fragment := range fragments {
+		next := func() (int, error) {
+			v, n := binary.Uvarint(fragment)
+			if n <= 0 {
+				return 0, errors.New("malformed spilled result")
+			}
+			fragment = fragment[n:]
+			return int(v), nil
+		}
+
+		for len(fragment) > 0 {
+			documentID, err := next()
+			if err != nil {
+				return nil, false, err
+			}
+			numRanges, err := next()
+			if err != nil {
+				return nil, false, err
+			}
+
+			for i := 0; i < numRanges; i++ {
+				rangeID, err := next()
+				if err != nil {
+					return nil, false, err
+				}
+				documentRanges.SetAdd(documentID, rangeID)
+			}
+		}
+	}
+
+	return documentRanges, true, nil
+}
+
+// Close closes and removes the backing temporary file. It is safe to call Close on a
+// nil store and to call it more than once.
+func (s *SpillStore) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || s.file == nil {
+		s.closed = true
+		return nil
+	}
+	s.closed = true
+
+	err := s.file.Close()
+	if removeErr := os.Remove(s.file.Name()); removeErr != nil && !os.IsNotExist(removeErr) {
+		err = errors.CombineErrors(err, removeErr)
+	}
+
+	return err
+}
@@ -0,0 +1,266 @@
+package conversion
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/lsif/conversion/datastructures"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
+)
+
+func TestCorrelateWithSpill(t *testing.T) {
+	input, err := os.ReadFile("../testdata/dump1.lsif")
+	if err != nil {
+		t.Fatalf("unexpected error reading test file: %s", err)
+	}
+
+	state, err := correlateFromReader(context.Background(), bytes.NewReader(input), "root", CorrelateOptions{MemoryBudget: 1, SpillDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+	defer state.Spilled.Close()
+
+	inMemoryState, err := correlateFromReader(context.Background(), bytes.NewReader(input), "root", CorrelateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+
+	// A budget of a single byte spills every element
+	inMemory := len(state.RangeData) + len(state.ResultSetData) + len(state.DefinitionData) + len(state.ReferenceData) +
+		len(state.ImplementationData) + len(state.HoverData) + len(state.MonikerData) + len(state.DiagnosticResults)
+	if inMemory != 0 {
+		t.Errorf("expected all elements to be spilled. %d elements are held in memory", inMemory)
+	}
+
+	expected := len(inMemoryState.RangeData) + len(inMemoryState.ResultSetData) + len(inMemoryState.DefinitionData) + len(inMemoryState.ReferenceData) +
+		len(inMemoryState.ImplementationData) + len(inMemoryState.HoverData) + len(inMemoryState.MonikerData) + len(inMemoryState.DiagnosticResults)
+	if state.Spilled == nil || state.Spilled.Len() != expected {
+		t.Fatalf("expected %d spilled elements", expected)
+	}
+
+	for id, expected := range inMemoryState.RangeData {
+		if actual, ok, err := state.rangeData(id); err != nil || !ok {
+			t.Errorf("expected range %d to be readable. ok=%v err=%v", id, ok, err)
+		} else if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("unexpected range %d (-want +got):\n%s", id, diff)
+		}
+	}
+	for id, expected := range inMemoryState.ResultSetData {
+		if actual, ok, err := state.resultSetData(id); err != nil || !ok {
+			t.Errorf("expected result set %d to be readable. ok=%v err=%v", id, ok, err)
+		} else if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("unexpected result set %d (-want +got):\n%s", id, diff)
+		}
+	}
+	for _, kind := range resultKinds {
+		for id, expected := range inMemoryState.resultData(kind) {
+			if actual, ok, err := state.result(kind, id); err != nil || !ok {
+				t.Errorf("expected result %d to be readable. ok=%v err=%v", id, ok, err)
+			} else if diff := cmp.Diff(expected, actual, datastructures.Comparers...); diff != "" {
+				t.Errorf("unexpected result %d (-want +got):\n%s", id, diff)
+			}
+		}
+	}
+	for id, expected := range inMemoryState.MonikerData {
+		if actual, ok, err := state.moniker(id); err != nil || !ok {
+			t.Errorf("expected moniker %d to be readable. ok=%v err=%v", id, ok, err)
+		} else if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("unexpected moniker %d (-want +got):\n%s", id, diff)
+		}
+	}
+	for id, expected := range inMemoryState.HoverData {
+		if actual, ok, err := state.hoverResult(id); err != nil || !ok {
+			t.Errorf("expected hover result %d to be readable. ok=%v err=%v", id, ok, err)
+		} else if actual != expected {
+			t.Errorf("unexpected hover result %d. want=%q have=%q", id, expected, actual)
+		}
+	}
+	for id, expected := range inMemoryState.DiagnosticResults {
+		if actual, ok, err := state.diagnosticResult(id); err != nil || !ok {
+			t.Errorf("expected diagnostic result %d to be readable. ok=%v err=%v", id, ok, err)
+		} else if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("unexpected diagnostic result %d (-want +got):\n%s", id, diff)
+		}
+	}
+}
+
+func TestCorrelateWithSpillReadError(t *testing.T) {
+	input, err := os.ReadFile("../testdata/dump1.lsif")
+	if err != nil {
+		t.Fatalf("unexpected error reading test file: %s", err)
+	}
+
+	state, err := correlateFromReader(context.Background(), bytes.NewReader(input), "root", CorrelateOptions{MemoryBudget: 1, SpillDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+	defer state.Spilled.Close()
+
+	if err := canonicalize(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
+
+	// Discard the spilled elements from underneath the state
+	if err := state.Spilled.writer.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing spill file: %s", err)
+	}
+	if err := state.Spilled.file.Truncate(0); err != nil {
+		t.Fatalf("unexpected error truncating spill file: %s", err)
+	}
+
+	// Errors surface either while grouping or while draining the grouped channels
+	groupedBundleData, err := groupBundleData(context.Background(), state)
+	if err == nil {
+		semantic.GroupedBundleDataChansToMaps(groupedBundleData)
+		err = groupedBundleData.Err()
+	}
+	if err == nil {
+		t.Fatalf("expected an error reading the truncated spill file")
+	}
+}
+
+func TestCorrelateWithSpillEverythingMatchesInMemory(t *testing.T) {
+	input := generateDump(5, 20, 64)
+
+	expected := correlateToMaps(t, input, CorrelateOptions{})
+	actual := correlateToMaps(t, input, CorrelateOptions{MemoryBudget: 1, SpillDir: t.TempDir()})
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected bundle data (-want +got):\n%s", diff)
+	}
+}
+
+func TestCorrelateWithSpillMatchesInMemory(t *testing.T) {
+	input := generateDump(20, 50, 256)
+
+	expected := correlateToMaps(t, input, CorrelateOptions{})
+
+	spillDir := t.TempDir()
+	actual := correlateToMaps(t, input, CorrelateOptions{MemoryBudget: 64 * 1024, SpillDir: spillDir})
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected bundle data (-want +got):\n%s", diff)
+	}
+
+	// The spill file is removed once all documents have been consumed
+	entries, err := os.ReadDir(spillDir)
+	if err != nil {
+		t.Fatalf("unexpected error reading spill directory: %s", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected spill file to be removed, found %d entries", len(entries))
+	}
+}
+
+func BenchmarkCorrelate(b *testing.B) {
+	// 1k documents with 100 ranges each, each carrying a 1KiB hover text
+	input := generateDump(1000, 100, 1024)
+	b.Logf("synthetic dump is %d bytes", len(input))
+
+	for _, testCase := range []struct {
+		name string
+		opts CorrelateOptions
+	}{
+		{name: "in-memory", opts: CorrelateOptions{}},
+		{name: "spill", opts: CorrelateOptions{MemoryBudget: 16 * 1024 * 1024, SpillDir: b.TempDir()}},
+	} {
+		b.Run(testCase.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input)))
+
+			var maxHeap uint64
+			for i := 0; i < b.N; i++ {
+				ctx, cancel := context.WithCancel(context.Background())
+
+				groupedBundleData, err := CorrelateWithOptions(ctx, bytes.NewReader(input), "", nil, testCase.opts)
+				if err != nil {
+					b.Fatalf("unexpected error correlating input: %s", err)
+				}
+
+				// Measure the live heap while the correlated state is held for grouping
+				var stats runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&stats)
+				if stats.HeapAlloc > maxHeap {
+					maxHeap = stats.HeapAlloc
+				}
+
+				for range groupedBundleData.Documents {
+				}
+
+				cancel()
+			}
+
+			b.ReportMetric(float64(maxHeap), "heap-bytes")
+		})
+	}
+}
+
+func correlateToMaps(t *testing.T, input []byte, opts CorrelateOptions) *semantic.GroupedBundleDataMaps {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	groupedBundleData, err := CorrelateWithOptions(ctx, bytes.NewReader(input), "", nil, opts)
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+
+	maps := semantic.GroupedBundleDataChansToMaps(groupedBundleData)
+	if err := groupedBundleData.Err(); err != nil {
+		t.Fatalf("unexpected error grouping bundle data: %s", err)
+	}
+
+	return maps
+}
+
+// generateDump creates a synthetic LSIF index with the given number of documents and ranges
+// per document. Each range has its own result set, definition, reference and hover result,
+// and each document has a diagnostic.
+func generateDump(numDocuments, numRanges, hoverSize int) []byte {
+	var buf bytes.Buffer
+	id := 0
+	emit := func(format string, args ...interface{}) int {
+		id++
+		fmt.Fprintf(&buf, `{"id": "%d", `+format+"}\n", append([]interface{}{id}, args...)...)
+		return id
+	}
+
+	hover := strings.Repeat("x", hoverSize)
+
+	emit(`"type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test/"`)
+	for i := 0; i < numDocuments; i++ {
+		documentID := emit(`"type": "vertex", "label": "document", "uri": "file:///test/doc%d.go"`, i)
+
+		rangeIDs := make([]string, 0, numRanges)
+		for j := 0; j < numRanges; j++ {
+			rangeID := emit(`"type": "vertex", "label": "range", "start": {"line": %d, "character": 0}, "end": {"line": %d, "character": 5}`, j, j)
+			resultSetID := emit(`"type": "vertex", "label": "resultSet"`)
+			hoverID := emit(`"type": "vertex", "label": "hoverResult", "result": {"contents": "%s %d.%d"}`, hover, i, j)
+			definitionID := emit(`"type": "vertex", "label": "definitionResult"`)
+			referenceID := emit(`"type": "vertex", "label": "referenceResult"`)
+
+			emit(`"type": "edge", "label": "next", "outV": "%d", "inV": "%d"`, rangeID, resultSetID)
+			emit(`"type": "edge", "label": "textDocument/hover", "outV": "%d", "inV": "%d"`, resultSetID, hoverID)
+			emit(`"type": "edge", "label": "textDocument/definition", "outV": "%d", "inV": "%d"`, resultSetID, definitionID)
+			emit(`"type": "edge", "label": "textDocument/references", "outV": "%d", "inV": "%d"`, resultSetID, referenceID)
+			emit(`"type": "edge", "label": "item", "outV": "%d", "inVs": ["%d"], "document": "%d"`, definitionID, rangeID, documentID)
+			emit(`"type": "edge", "label": "item", "outV": "%d", "inVs": ["%d"], "document": "%d"`, referenceID, rangeID, documentID)
+
+			rangeIDs = append(rangeIDs, fmt.Sprintf(`"%d"`, rangeID))
+		}
+
+		emit(`"type": "edge", "label": "contains", "outV": "%d", "inVs": [%s]`, documentID, strings.Join(rangeIDs, ", "))
+
+		diagnosticID := emit(`"type": "vertex", "label": "diagnosticResult", "result": [{"severity": 1, "code": "E%d", "message": "%s", "source": "synthetic", "range": {"start": {"line": 0, "character": 0}, "end": {"line": 0, "character": 1}}}]`, i, hover)
+		emit(`"type": "edge", "label": "textDocument/diagnostic", "outV": "%d", "inV": "%d"`, documentID, diagnosticID)
+	}
+
+	return buf.Bytes()
+}
@@ -14,6 +14,7 @@ type State struct {
 	ResultSetData          map[int]ResultSet
 	DefinitionData         map[int]*datastructures.DefaultIDSetMap
 	ReferenceData          map[int]*datastructures.DefaultIDSetMap
+	ImplementationData     map[int]*datastructures.DefaultIDSetMap
 	HoverData              map[int]string
 	MonikerData            map[int]Moniker
 	PackageInformationData map[int]PackageInformation
@@ -34,6 +35,13 @@ type State struct {
 	DocumentationChildren     map[int][]int                  // maps documentationResult vertex -> ordered list of children documentationResult vertices
 	DocumentationStringLabel  map[int]int                    // maps documentationResult vertex -> label documentationString vertex
 	DocumentationStringDetail map[int]int                    // maps documentationResult vertex -> detail documentationString vertex
+
+	// Spilled holds the hover results, diagnostic results, ranges, result sets, definition,
+	// reference, and implementation results, and monikers that did not fit into the memory
+	// budget. The maps above hold only the elements kept in memory, so these elements must be
+	// accessed through the methods of the state. This is nil when the state was correlated
+	// without a memory budget.
+	Spilled *SpillStore
 }
 
 // newState create a new State with zero-valued map fields.
@@ -44,6 +52,7 @@ func newState() *State {
 		ResultSetData:          map[int]ResultSet{},
 		DefinitionData:         map[int]*datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]*datastructures.DefaultIDSetMap{},
+		ImplementationData:     map[int]*datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]Moniker{},
 		PackageInformationData: map[int]PackageInformation{},
@@ -66,3 +75,407 @@ func newState() *State {
 		DocumentationStringDetail: map[int]int{},
 	}
 }
+
+// Approximate in-memory sizes of the fixed-width parts of the elements accounted for
+// against the memory budget.
+const (
+	diagnosticOverhead  = 80
+	rangeOverhead       = 96
+	resultSetOverhead   = 64
+	monikerOverhead     = 64
+	resultOverhead      = 64
+	resultRangeOverhead = 8
+)
+
+// hoverResult returns the text of the hover result with the given identifier.
+func (s *State) hoverResult(id int) (string, bool, error) {
+	if hover, ok := s.HoverData[id]; ok {
+		return hover, true, nil
+	}
+	if s.Spilled == nil {
+		return "", false, nil
+	}
+
+	payload, ok, err := s.Spilled.read(spilledHoverResult, id)
+	return string(payload), ok, err
+}
+
+// hasHoverResult returns true if a hover result with the given identifier exists.
+func (s *State) hasHoverResult(id int) bool {
+	_, ok := s.HoverData[id]
+	return ok || s.isSpilled(spilledHoverResult, id)
+}
+
+// setHoverResult stores the given hover text in memory, or in the spill file if holding it
+// in memory would exceed the memory budget.
+func (s *State) setHoverResult(id int, hover string) error {
+	_, inMemory := s.HoverData[id]
+	if s.keepInMemory(spilledHoverResult, id, inMemory, int64(len(hover))) {
+		s.HoverData[id] = hover
+		return nil
+	}
+
+	return s.Spilled.write(spilledHoverResult, id, []byte(hover))
+}
+
+// diagnosticResult returns the diagnostics of the diagnostic result with the given identifier.
+func (s *State) diagnosticResult(id int) ([]Diagnostic, bool, error) {
+	if diagnostics, ok := s.DiagnosticResults[id]; ok {
+		return diagnostics, true, nil
+	}
+	if s.Spilled == nil {
+		return nil, false, nil
+	}
+
+	var diagnostics []Diagnostic
+	ok, err := s.Spilled.readJSON(spilledDiagnosticResult, id, &diagnostics)
+	return diagnostics, ok, err
+}
+
+// hasDiagnosticResult returns true if a diagnostic result with the given identifier exists.
+func (s *State) hasDiagnosticResult(id int) bool {
+	_, ok := s.DiagnosticResults[id]
+	return ok || s.isSpilled(spilledDiagnosticResult, id)
+}
+
+// setDiagnosticResult stores the given diagnostics in memory, or in the spill file if holding
+// them in memory would exceed the memory budget.
+func (s *State) setDiagnosticResult(id int, diagnostics []Diagnostic) error {
+	size := int64(0)
+	for _, diagnostic := range diagnostics {
+		size += diagnosticOverhead + int64(len(diagnostic.Code)+len(diagnostic.Message)+len(diagnostic.Source))
+	}
+
+	_, inMemory := s.DiagnosticResults[id]
+	if s.keepInMemory(spilledDiagnosticResult, id, inMemory, size) {
+		s.DiagnosticResults[id] = diagnostics
+		return nil
+	}
+
+	return s.Spilled.writeJSON(spilledDiagnosticResult, id, diagnostics)
+}
+
+// rangeData returns the range with the given identifier.
+func (s *State) rangeData(id int) (Range, bool, error) {
+	if r, ok := s.RangeData[id]; ok {
+		return r, true, nil
+	}
+	if s.Spilled == nil {
+		return Range{}, false, nil
+	}
+
+	var r Range
+	ok, err := s.Spilled.readJSON(spilledRange, id, &r)
+	return r, ok, err
+}
+
+// hasRange returns true if a range with the given identifier exists.
+func (s *State) hasRange(id int) bool {
+	_, ok := s.RangeData[id]
+	return ok || s.isSpilled(spilledRange, id)
+}
+
+// setRangeData stores the given range in memory, or in the spill file if holding it in memory
+// would exceed the memory budget.
+func (s *State) setRangeData(id int, r Range) error {
+	size := int64(rangeOverhead)
+	if r.Tag != nil {
+		size += int64(len(r.Tag.Type) + len(r.Tag.Text) + len(r.Tag.Detail))
+	}
+
+	_, inMemory := s.RangeData[id]
+	if s.keepInMemory(spilledRange, id, inMemory, size) {
+		s.RangeData[id] = r
+		return nil
+	}
+
+	return s.Spilled.writeJSON(spilledRange, id, r)
+}
+
+// eachRange invokes the given function with each range. The function may update the range it
+// is invoked with, but must not add ranges.
+func (s *State) eachRange(f func(id int, r Range) error) error {
+	for id, r := range s.RangeData {
+		if err := f(id, r); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range s.spilledIDs(spilledRange) {
+		r, _, err := s.rangeData(id)
+		if err != nil {
+			return err
+		}
+		if err := f(id, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resultSetData returns the result set with the given identifier.
+func (s *State) resultSetData(id int) (ResultSet, bool, error) {
+	if rs, ok := s.ResultSetData[id]; ok {
+		return rs, true, nil
+	}
+	if s.Spilled == nil {
+		return ResultSet{}, false, nil
+	}
+
+	var rs ResultSet
+	ok, err := s.Spilled.readJSON(spilledResultSet, id, &rs)
+	return rs, ok, err
+}
+
+// hasResultSet returns true if a result set with the given identifier exists.
+func (s *State) hasResultSet(id int) bool {
+	_, ok := s.ResultSetData[id]
+	return ok || s.isSpilled(spilledResultSet, id)
+}
+
+// setResultSetData stores the given result set in memory, or in the spill file if holding it
+// in memory would exceed the memory budget.
+func (s *State) setResultSetData(id int, rs ResultSet) error {
+	_, inMemory := s.ResultSetData[id]
+	if s.keepInMemory(spilledResultSet, id, inMemory, resultSetOverhead) {
+		s.ResultSetData[id] = rs
+		return nil
+	}
+
+	return s.Spilled.writeJSON(spilledResultSet, id, rs)
+}
+
+// eachResultSet invokes the given function with each result set. The function may update any
+// result set, but must not add result sets.
+func (s *State) eachResultSet(f func(id int, rs ResultSet) error) error {
+	for id := range s.ResultSetData {
+		// Read the current value, as the function may update result sets not yet visited
+		if err := f(id, s.ResultSetData[id]); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range s.spilledIDs(spilledResultSet) {
+		rs, _, err := s.resultSetData(id)
+		if err != nil {
+			return err
+		}
+		if err := f(id, rs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moniker returns the moniker with the given identifier.
+func (s *State) moniker(id int) (Moniker, bool, error) {
+	if m, ok := s.MonikerData[id]; ok {
+		return m, true, nil
+	}
+	if s.Spilled == nil {
+		return Moniker{}, false, nil
+	}
+
+	var m Moniker
+	ok, err := s.Spilled.readJSON(spilledMoniker, id, &m)
+	return m, ok, err
+}
+
+// hasMoniker returns true if a moniker with the given identifier exists.
+func (s *State) hasMoniker(id int) bool {
+	_, ok := s.MonikerData[id]
+	return ok || s.isSpilled(spilledMoniker, id)
+}
+
+// setMoniker stores the given moniker in memory, or in the spill file if holding it in memory
+// would exceed the memory budget.
+func (s *State) setMoniker(id int, m Moniker) error {
+	size := int64(monikerOverhead + len(m.Kind) + len(m.Scheme) + len(m.Identifier))
+
+	_, inMemory := s.MonikerData[id]
+	if s.keepInMemory(spilledMoniker, id, inMemory, size) {
+		s.MonikerData[id] = m
+		return nil
+	}
+
+	return s.Spilled.writeJSON(spilledMoniker, id, m)
+}
+
+// resultKind distinguishes definition, reference, and implementation results.
+type resultKind int
+
+const (
+	definitionResults resultKind = iota
+	referenceResults
+	implementationResults
+)
+
+// resultKinds lists the kinds of results in the order in which their identifiers are resolved.
+var resultKinds = []resultKind{definitionResults, referenceResults, implementationResults}
+
+// resultData returns the in-memory results of the given kind.
+func (s *State) resultData(kind resultKind) map[int]*datastructures.DefaultIDSetMap {
+	switch kind {
+	case definitionResults:
+		return s.DefinitionData
+	case referenceResults:
+		return s.ReferenceData
+	default:
+		return s.ImplementationData
+	}
+}
+
+func (kind resultKind) spillKind() spillKind {
+	switch kind {
+	case definitionResults:
+		return spilledDefinitionResult
+	case referenceResults:
+		return spilledReferenceResult
+	default:
+		return spilledImplementationResult
+	}
+}
+
+// result returns the document ranges of the result of the given kind with the given identifier.
+// The returned map may be a copy of the stored data and must not be modified.
+func (s *State) result(kind resultKind, id int) (*datastructures.DefaultIDSetMap, bool, error) {
+	if documentRanges, ok := s.resultData(kind)[id]; ok {
+		return documentRanges, true, nil
+	}
+	if s.Spilled == nil {
+		return nil, false, nil
+	}
+
+	return s.Spilled.readResult(kind.spillKind(), id)
+}
+
+// hasResult returns true if a result of the given kind with the given identifier exists.
+func (s *State) hasResult(kind resultKind, id int) bool {
+	_, ok := s.resultData(kind)[id]
+	return ok || s.isSpilled(kind.spillKind(), id)
+}
+
+// setResult stores the document ranges of the result of the given kind with the given
+// identifier in memory, or in the spill file if holding them in memory would exceed the
+// memory budget. Any ranges previously stored for the result are replaced.
+func (s *State) setResult(kind resultKind, id int, documentRanges *datastructures.DefaultIDSetMap) error {
+	data := s.resultData(kind)
+
+	_, inMemory := data[id]
+	if s.keepInMemory(kind.spillKind(), id, inMemory, resultSize(documentRanges)) {
+		data[id] = documentRanges
+		return nil
+	}
+
+	return s.Spilled.writeResult(kind.spillKind(), id, documentRanges, true)
+}
+
+// addResultRanges adds the given ranges of a document to the result of the given kind with
+// the given identifier. If a result held in memory would exceed the memory budget, it is moved
+// to the spill file.
+func (s *State) addResultRanges(kind resultKind, id, documentID int, rangeIDs *datastructures.IDSet) error {
+	data := s.resultData(kind)
+
+	if documentRanges, ok := data[id]; ok {
+		if s.Spilled == nil || s.Spilled.reserve(int64(resultRangeOverhead*rangeIDs.Len())) {
+			documentRanges.SetUnion(documentID, rangeIDs)
+			return nil
+		}
+
+		if err := s.Spilled.writeResult(kind.spillKind(), id, documentRanges, true); err != nil {
+			return err
+		}
+		s.Spilled.release(resultSize(documentRanges))
+		delete(data, id)
+	} else if !s.isSpilled(kind.spillKind(), id) {
+		// Results are created before ranges are added to them
+		return nil
+	}
+
+	return s.Spilled.writeResult(kind.spillKind(), id, datastructures.DefaultIDSetMapWith(map[int]*datastructures.IDSet{
+		documentID: rangeIDs,
+	}), false)
+}
+
+// updateResult invokes the given function with the document ranges of the result of the given
+// kind with the given identifier. The function may modify the ranges and must return true if it
+// did so. This does not account for ranges added by the function against the memory budget.
+func (s *State) updateResult(kind resultKind, id int, f func(documentRanges *datastructures.DefaultIDSetMap) bool) error {
+	if documentRanges, ok := s.resultData(kind)[id]; ok {
+		f(documentRanges)
+		return nil
+	}
+	if s.Spilled == nil {
+		return nil
+	}
+
+	documentRanges, ok, err := s.Spilled.readResult(kind.spillKind(), id)
+	if err != nil || !ok {
+		return err
+	}
+	if !f(documentRanges) {
+		return nil
+	}
+
+	return s.Spilled.writeResult(kind.spillKind(), id, documentRanges, true)
+}
+
+// resultIDs returns the identifiers of the results of the given kind.
+func (s *State) resultIDs(kind resultKind) []int {
+	data := s.resultData(kind)
+
+	ids := make([]int, 0, len(data))
+	for id := range data {
+		ids = append(ids, id)
+	}
+
+	return append(ids, s.spilledIDs(kind.spillKind())...)
+}
+
+// numResults returns the number of results of the given kind.
+func (s *State) numResults(kind resultKind) int {
+	n := len(s.resultData(kind))
+	if s.Spilled != nil {
+		n += len(s.Spilled.offsets[kind.spillKind()])
+	}
+
+	return n
+}
+
+// resultSize approximates the in-memory size of the given document ranges.
+func resultSize(documentRanges *datastructures.DefaultIDSetMap) int64 {
+	size := int64(resultOverhead)
+	documentRanges.Each(func(documentID int, rangeIDs *datastructures.IDSet) {
+		size += int64(resultRangeOverhead * rangeIDs.Len())
+	})
+
+	return size
+}
+
+// keepInMemory returns true if an element of the given kind and size should be held in memory.
+// Elements already held in memory stay there when they are updated, and elements already in
+// the spill file are rewritten there.
+func (s *State) keepInMemory(kind spillKind, id int, inMemory bool, size int64) bool {
+	if inMemory || s.Spilled == nil {
+		return true
+	}
+	if s.Spilled.contains(kind, id) {
+		return false
+	}
+
+	return s.Spilled.reserve(size)
+}
+
+func (s *State) isSpilled(kind spillKind, id int) bool {
+	return s.Spilled != nil && s.Spilled.contains(kind, id)
+}
+
+func (s *State) spilledIDs(kind spillKind) []int {
+	if s.Spilled == nil {
+		return nil
+	}
+
+	return s.Spilled.ids(kind)
+}
@@ -0,0 +1,134 @@
+package scip
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Marshal encodes the given index. Repeated scalar fields are packed and fields holding
+// their zero value are omitted, as done by protobuf implementations for proto3 schemas.
+func Marshal(index *Index) []byte {
+	var b []byte
+	b = appendMessage(b, 1, marshalMetadata(index.Metadata))
+	for _, document := range index.Documents {
+		b = appendMessage(b, 2, marshalDocument(document))
+	}
+	for _, symbol := range index.ExternalSymbols {
+		b = appendMessage(b, 3, marshalSymbolInformation(symbol))
+	}
+
+	return b
+}
+
+func marshalMetadata(m Metadata) (b []byte) {
+	b = appendVarint(b, 1, uint64(m.Version))
+	b = appendMessage(b, 2, marshalToolInfo(m.ToolInfo))
+	b = appendString(b, 3, m.ProjectRoot)
+	b = appendVarint(b, 4, uint64(m.TextDocumentEncoding))
+	return b
+}
+
+func marshalToolInfo(t ToolInfo) (b []byte) {
+	b = appendString(b, 1, t.Name)
+	b = appendString(b, 2, t.Version)
+	for _, argument := range t.Arguments {
+		b = appendRepeatedString(b, 3, argument)
+	}
+	return b
+}
+
+func marshalDocument(d *Document) (b []byte) {
+	b = appendString(b, 1, d.RelativePath)
+	for _, occurrence := range d.Occurrences {
+		b = appendMessage(b, 2, marshalOccurrence(occurrence))
+	}
+	for _, symbol := range d.Symbols {
+		b = appendMessage(b, 3, marshalSymbolInformation(symbol))
+	}
+	b = appendString(b, 4, d.Language)
+	return b
+}
+
+func marshalOccurrence(o *Occurrence) (b []byte) {
+	b = appendPackedInt32s(b, 1, o.Range)
+	b = appendString(b, 2, o.Symbol)
+	b = appendVarint(b, 3, uint64(o.SymbolRoles))
+	for _, documentation := range o.OverrideDocumentation {
+		b = appendRepeatedString(b, 4, documentation)
+	}
+	b = appendVarint(b, 5, uint64(o.SyntaxKind))
+	for _, diagnostic := range o.Diagnostics {
+		b = appendMessage(b, 6, marshalDiagnostic(diagnostic))
+	}
+	return b
+}
+
+func marshalSymbolInformation(s *SymbolInformation) (b []byte) {
+	b = appendString(b, 1, s.Symbol)
+	for _, documentation := range s.Documentation {
+		b = appendRepeatedString(b, 3, documentation)
+	}
+	for _, relationship := range s.Relationships {
+		b = appendMessage(b, 4, marshalRelationship(relationship))
+	}
+	return b
+}
+
+func marshalRelationship(r *Relationship) (b []byte) {
+	b = appendString(b, 1, r.Symbol)
+	b = appendVarint(b, 2, protowire.EncodeBool(r.IsReference))
+	b = appendVarint(b, 3, protowire.EncodeBool(r.IsImplementation))
+	b = appendVarint(b, 4, protowire.EncodeBool(r.IsTypeDefinition))
+	b = appendVarint(b, 5, protowire.EncodeBool(r.IsDefinition))
+	return b
+}
+
+func marshalDiagnostic(d *Diagnostic) (b []byte) {
+	b = appendVarint(b, 1, uint64(d.Severity))
+	b = appendString(b, 2, d.Code)
+	b = appendString(b, 3, d.Message)
+	b = appendString(b, 4, d.Source)
+	b = appendPackedInt32s(b, 5, d.Tags)
+	return b
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+
+	return appendRepeatedString(b, num, v)
+}
+
+// appendRepeatedString appends an element of a repeated string field. Unlike singular
+// fields, empty elements are significant and must be encoded.
+func appendRepeatedString(b []byte, num protowire.Number, v string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendMessage(b []byte, num protowire.Number, message []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, message)
+}
+
+func appendPackedInt32s(b []byte, num protowire.Number, values []int32) []byte {
+	if len(values) == 0 {
+		return b
+	}
+
+	var packed []byte
+	for _, v := range values {
+		packed = protowire.AppendVarint(packed, uint64(v))
+	}
+
+	return appendMessage(b, num, packed)
+}
@@ -0,0 +1,234 @@
+package scip
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// MaxMessageSize is the maximum size of a single top-level message (the metadata, a
+// document, or an external symbol) of an index. This bounds the memory used to read a
+// malformed index.
+const MaxMessageSize = 512 * 1024 * 1024
+
+// ErrMessageTooLarge occurs when a top-level message of an index exceeds MaxMessageSize.
+var ErrMessageTooLarge = errors.New("SCIP message exceeds maximum size")
+
+// Visitor receives the top-level messages of an index in the order in which they occur
+// in the input. Nil functions are skipped.
+type Visitor struct {
+	Metadata       func(metadata Metadata) error
+	Document       func(document *Document) error
+	ExternalSymbol func(symbol *SymbolInformation) error
+}
+
+// errStopReading is returned from a visitor function to stop reading an index early.
+var errStopReading = errors.New("stop reading")
+
+// ReadIndex reads the given SCIP index one top-level message at a time, so that only a
+// single document is held in memory at once, and invokes the matching visitor function
+// for each message.
+func ReadIndex(r io.Reader, visitor Visitor) error {
+	br := bufio.NewReader(r)
+
+	for {
+		tag, err := binary.ReadUvarint(br)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "reading field tag")
+		}
+
+		num, typ := protowire.DecodeTag(tag)
+		if typ != protowire.BytesType {
+			if err := skipValue(br, typ); err != nil {
+				return err
+			}
+			continue
+		}
+
+		length, err := binary.ReadUvarint(br)
+		if err != nil {
+			return errors.Wrap(err, "reading field length")
+		}
+		if length > MaxMessageSize {
+			return ErrMessageTooLarge
+		}
+
+		message := make([]byte, length)
+		if _, err := io.ReadFull(br, message); err != nil {
+			return errors.Wrap(err, "reading field value")
+		}
+
+		if err := visitMessage(visitor, num, message); err != nil {
+			if err == errStopReading {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func visitMessage(visitor Visitor, num protowire.Number, message []byte) error {
+	switch num {
+	case 1:
+		if visitor.Metadata == nil {
+			return nil
+		}
+
+		var metadata Metadata
+		if err := unmarshalMetadata(message, &metadata); err != nil {
+			return errors.Wrap(err, "metadata")
+		}
+		return visitor.Metadata(metadata)
+
+	case 2:
+		if visitor.Document == nil {
+			return nil
+		}
+
+		document := &Document{}
+		if err := unmarshalDocument(message, document); err != nil {
+			return errors.Wrap(err, "document")
+		}
+		return visitor.Document(document)
+
+	case 3:
+		if visitor.ExternalSymbol == nil {
+			return nil
+		}
+
+		symbol := &SymbolInformation{}
+		if err := unmarshalSymbolInformation(message, symbol); err != nil {
+			return errors.Wrap(err, "external symbol")
+		}
+		return visitor.ExternalSymbol(symbol)
+	}
+
+	return nil
+}
+
+func skipValue(br *bufio.Reader, typ protowire.Type) (err error) {
+	switch typ {
+	case protowire.VarintType:
+		_, err = binary.ReadUvarint(br)
+	case protowire.Fixed32Type:
+		_, err = br.Discard(4)
+	case protowire.Fixed64Type:
+		_, err = br.Discard(8)
+	default:
+		err = errors.Errorf("unsupported wire type %d", typ)
+	}
+
+	return err
+}
+
+// Unmarshal decodes a complete SCIP index held in memory.
+func Unmarshal(data []byte) (*Index, error) {
+	index := &Index{}
+	if err := ReadIndex(bytes.NewReader(data), Visitor{
+		Metadata: func(metadata Metadata) error {
+			index.Metadata = metadata
+			return nil
+		},
+		Document: func(document *Document) error {
+			index.Documents = append(index.Documents, document)
+			return nil
+		},
+		ExternalSymbol: func(symbol *SymbolInformation) error {
+			index.ExternalSymbols = append(index.ExternalSymbols, symbol)
+			return nil
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// ReadMetadata returns the metadata of the given index. Only the input preceding the
+// metadata message is read.
+func ReadMetadata(r io.Reader) (metadata Metadata, err error) {
+	found := false
+	if err := ReadIndex(r, Visitor{
+		Metadata: func(m Metadata) error {
+			metadata, found = m, true
+			return errStopReading
+		},
+	}); err != nil {
+		return Metadata{}, err
+	}
+
+	if !found {
+		return Metadata{}, errors.New("SCIP index has no metadata")
+	}
+
+	return metadata, nil
+}
+
+// sniffLength is the number of bytes inspected by Sniff.
+const sniffLength = 16
+
+// Sniff returns true if the given reader appears to contain a SCIP index rather than LSIF
+// JSON lines. The reader is not advanced.
+//
+// SCIP indexes have no magic number, so we recognize the protobuf tag of the first field
+// of an index. LSIF indexes begin with a JSON object, optionally preceded by whitespace.
+// As a newline shares its byte with the tag of the metadata field, we also require that
+// the length of that field be followed by a tag of the metadata message.
+func Sniff(r *bufio.Reader) (bool, error) {
+	prefix, err := r.Peek(sniffLength)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return isSCIPPrefix(prefix), nil
+}
+
+var (
+	metadataTag       = byte(protowire.EncodeTag(1, protowire.BytesType))
+	documentTag       = byte(protowire.EncodeTag(2, protowire.BytesType))
+	externalSymbolTag = byte(protowire.EncodeTag(3, protowire.BytesType))
+)
+
+func isSCIPPrefix(prefix []byte) bool {
+	if len(prefix) == 0 {
+		return false
+	}
+
+	switch prefix[0] {
+	case documentTag, externalSymbolTag:
+		return true
+
+	case metadataTag:
+		length, n := protowire.ConsumeVarint(prefix[1:])
+		if n < 0 {
+			return false
+		}
+		if length == 0 {
+			return true
+		}
+
+		rest := prefix[1+n:]
+		if len(rest) == 0 {
+			return false
+		}
+
+		// The tag of text_document_encoding is deliberately omitted as it is a space, and
+		// it is never the first field of the metadata written by an indexer.
+		switch rest[0] {
+		case
+			byte(protowire.EncodeTag(1, protowire.VarintType)), // version
+			byte(protowire.EncodeTag(2, protowire.BytesType)),  // tool_info
+			byte(protowire.EncodeTag(3, protowire.BytesType)):  // project_root
+			return true
+		}
+	}
+
+	return false
+}
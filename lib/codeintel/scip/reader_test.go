@@ -0,0 +1,155 @@
+package scip
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var testIndex = &Index{
+	Metadata: Metadata{
+		Version:     0,
+		ToolInfo:    ToolInfo{Name: "scip-go", Version: "0.1.0", Arguments: []string{"--module-root", "."}},
+		ProjectRoot: "file:///src/project",
+	},
+	Documents: []*Document{
+		{
+			RelativePath: "main.go",
+			Language:     "go",
+			Occurrences: []*Occurrence{
+				{
+					Range:       []int32{3, 5, 9},
+					Symbol:      "scip-go gomod github.com/test/project v1.0.0 `main`/main().",
+					SymbolRoles: SymbolRoleDefinition,
+				},
+				{
+					Range:  []int32{4, 1, 5, 2},
+					Symbol: "local 0",
+					Diagnostics: []*Diagnostic{
+						{Severity: 2, Code: "S1000", Message: "unused", Source: "staticcheck", Tags: []int32{1}},
+					},
+				},
+			},
+			Symbols: []*SymbolInformation{
+				{
+					Symbol:        "scip-go gomod github.com/test/project v1.0.0 `main`/main().",
+					Documentation: []string{"```go\nfunc main()\n```", ""},
+					Relationships: []*Relationship{{Symbol: "local 0", IsReference: true}},
+				},
+			},
+		},
+	},
+	ExternalSymbols: []*SymbolInformation{
+		{Symbol: "scip-go gomod fmt . `fmt`/Println().", Documentation: []string{"Println formats..."}},
+	},
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	index, err := Unmarshal(Marshal(testIndex))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling index: %s", err)
+	}
+
+	if diff := cmp.Diff(testIndex, index); diff != "" {
+		t.Errorf("unexpected index (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalUnpackedRange(t *testing.T) {
+	var occurrence []byte
+	for _, v := range []uint64{1, 2, 3, 4} {
+		occurrence = protowire.AppendTag(occurrence, 1, protowire.VarintType)
+		occurrence = protowire.AppendVarint(occurrence, v)
+	}
+	// Unknown fields are skipped
+	occurrence = protowire.AppendTag(occurrence, 99, protowire.Fixed64Type)
+	occurrence = protowire.AppendFixed64(occurrence, 42)
+
+	document := appendMessage(nil, 2, occurrence)
+	index, err := Unmarshal(appendMessage(nil, 2, document))
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling index: %s", err)
+	}
+
+	if diff := cmp.Diff([]int32{1, 2, 3, 4}, index.Documents[0].Occurrences[0].Range); diff != "" {
+		t.Errorf("unexpected range (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnmarshalTruncated(t *testing.T) {
+	data := Marshal(testIndex)
+	if _, err := Unmarshal(data[:len(data)-3]); err == nil {
+		t.Fatalf("expected error unmarshalling truncated index")
+	}
+}
+
+func TestReadMetadata(t *testing.T) {
+	data := Marshal(testIndex)
+
+	// Only the metadata message needs to be readable
+	metadata, err := ReadMetadata(bytes.NewReader(data[:len(data)-3]))
+	if err != nil {
+		t.Fatalf("unexpected error reading metadata: %s", err)
+	}
+	if metadata.ToolInfo.Name != "scip-go" {
+		t.Errorf("unexpected tool name. want=%q have=%q", "scip-go", metadata.ToolInfo.Name)
+	}
+}
+
+func TestSniff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []byte
+		expected bool
+	}{
+		{name: "scip", input: Marshal(testIndex), expected: true},
+		{name: "scip without metadata", input: appendMessage(nil, 2, marshalDocument(testIndex.Documents[0])), expected: true},
+		{name: "lsif", input: []byte(`{"id": "1", "type": "vertex", "label": "metaData"}`), expected: false},
+		{name: "lsif with leading newline", input: []byte("\n{\"id\": \"1\"}"), expected: false},
+		{name: "lsif with leading newlines", input: []byte("\n\n\n{\"id\": \"1\"}"), expected: false},
+		{name: "lsif with leading newline and space", input: []byte("\n{ \"id\": \"1\" }"), expected: false},
+		{name: "empty", input: nil, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := bufio.NewReader(bytes.NewReader(testCase.input))
+
+			isSCIP, err := Sniff(r)
+			if err != nil {
+				t.Fatalf("unexpected error sniffing input: %s", err)
+			}
+			if isSCIP != testCase.expected {
+				t.Errorf("unexpected result. want=%v have=%v", testCase.expected, isSCIP)
+			}
+
+			// Sniffing must not consume input
+			if n, _ := r.Discard(len(testCase.input)); n != len(testCase.input) {
+				t.Errorf("expected input to be unread. want=%d have=%d", len(testCase.input), n)
+			}
+		})
+	}
+}
+
+func BenchmarkReadIndex(b *testing.B) {
+	index := &Index{Metadata: testIndex.Metadata}
+	for i := 0; i < 1000; i++ {
+		document := *testIndex.Documents[0]
+		document.RelativePath = strings.Repeat("a", i%64) + ".go"
+		index.Documents = append(index.Documents, &document)
+	}
+	data := Marshal(index)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := ReadIndex(bytes.NewReader(data), Visitor{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package scip
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Symbol is a parsed SCIP symbol string of the form
+// `<scheme> <manager> <package-name> <version> <descriptors>`.
+type Symbol struct {
+	Scheme      string
+	Package     Package
+	Descriptors string
+}
+
+// Package identifies the package that defines a symbol.
+type Package struct {
+	Manager string
+	Name    string
+	Version string
+}
+
+// IsLocalSymbol returns true if the given symbol is only visible within a single document.
+func IsLocalSymbol(symbol string) bool {
+	return strings.HasPrefix(symbol, "local ")
+}
+
+// ParseSymbol parses a global symbol. Spaces within the scheme and package fields are
+// escaped by doubling them, and a single `.` denotes an empty package field.
+func ParseSymbol(symbol string) (Symbol, error) {
+	if IsLocalSymbol(symbol) {
+		return Symbol{}, errors.Errorf("cannot parse local symbol %q", symbol)
+	}
+
+	var fields []string
+	rest := symbol
+	for len(fields) < 4 {
+		field, remainder, ok := nextSymbolField(rest)
+		if !ok {
+			return Symbol{}, errors.Errorf("malformed symbol %q", symbol)
+		}
+
+		fields = append(fields, field)
+		rest = remainder
+	}
+	if rest == "" {
+		return Symbol{}, errors.Errorf("malformed symbol %q: missing descriptors", symbol)
+	}
+
+	return Symbol{
+		Scheme: fields[0],
+		Package: Package{
+			Manager: dotAsEmpty(fields[1]),
+			Name:    dotAsEmpty(fields[2]),
+			Version: dotAsEmpty(fields[3]),
+		},
+		Descriptors: rest,
+	}, nil
+}
+
+// nextSymbolField returns the space-terminated field at the head of the given string and
+// the remainder of the string after the terminating space.
+func nextSymbolField(s string) (field, rest string, ok bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != ' ' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == ' ' {
+			// Escaped space
+			b.WriteByte(' ')
+			i++
+			continue
+		}
+
+		return b.String(), s[i+1:], true
+	}
+
+	return "", "", false
+}
+
+func dotAsEmpty(s string) string {
+	if s == "." {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+package scip
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSymbol(t *testing.T) {
+	testCases := []struct {
+		symbol   string
+		expected Symbol
+	}{
+		{
+			symbol: "scip-go gomod github.com/test/project v1.0.0 `main`/main().",
+			expected: Symbol{
+				Scheme:      "scip-go",
+				Package:     Package{Manager: "gomod", Name: "github.com/test/project", Version: "v1.0.0"},
+				Descriptors: "`main`/main().",
+			},
+		},
+		{
+			symbol: "scip-typescript npm . . `lib.d.ts`/Array#map().",
+			expected: Symbol{
+				Scheme:      "scip-typescript",
+				Package:     Package{Manager: "npm"},
+				Descriptors: "`lib.d.ts`/Array#map().",
+			},
+		},
+		{
+			symbol: "my  scheme maven org.example 1.0 Foo#bar(). (x)",
+			expected: Symbol{
+				Scheme:      "my scheme",
+				Package:     Package{Manager: "maven", Name: "org.example", Version: "1.0"},
+				Descriptors: "Foo#bar(). (x)",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		symbol, err := ParseSymbol(testCase.symbol)
+		if err != nil {
+			t.Fatalf("unexpected error parsing symbol %q: %s", testCase.symbol, err)
+		}
+
+		if diff := cmp.Diff(testCase.expected, symbol); diff != "" {
+			t.Errorf("unexpected symbol (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestParseSymbolInvalid(t *testing.T) {
+	for _, symbol := range []string{"local 42", "scip-go gomod", "scip-go gomod name version"} {
+		if _, err := ParseSymbol(symbol); err == nil {
+			t.Errorf("expected error parsing symbol %q", symbol)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package scip reads and writes SCIP indexes, a protobuf-based alternative to the LSIF
+// JSON lines format. Only the subset of the schema used for precise code intelligence is
+// represented here; unknown fields are skipped when decoding.
+//
+// See https://github.com/sourcegraph/scip/blob/main/scip.proto for the full schema.
+package scip
+
+// Index is the root message of a SCIP index.
+type Index struct {
+	Metadata        Metadata
+	Documents       []*Document
+	ExternalSymbols []*SymbolInformation
+}
+
+// Metadata describes the tool that produced an index and the directory it was run in.
+type Metadata struct {
+	Version              int32
+	ToolInfo             ToolInfo
+	ProjectRoot          string
+	TextDocumentEncoding int32
+}
+
+// ToolInfo identifies the indexer that produced an index.
+type ToolInfo struct {
+	Name      string
+	Version   string
+	Arguments []string
+}
+
+// Document holds the occurrences and symbols of a single source file.
+type Document struct {
+	RelativePath string
+	Occurrences  []*Occurrence
+	Symbols      []*SymbolInformation
+	Language     string
+}
+
+// Occurrence associates a source range with a symbol.
+type Occurrence struct {
+	// Range is either [startLine, startCharacter, endLine, endCharacter], or
+	// [startLine, startCharacter, endCharacter] when the range spans a single line.
+	Range                 []int32
+	Symbol                string
+	SymbolRoles           int32
+	OverrideDocumentation []string
+	SyntaxKind            int32
+	Diagnostics           []*Diagnostic
+}
+
+// SymbolInformation holds the documentation and relationships of a symbol.
+type SymbolInformation struct {
+	Symbol        string
+	Documentation []string
+	Relationships []*Relationship
+}
+
+// Relationship links a symbol to another symbol it implements, references or defines.
+type Relationship struct {
+	Symbol           string
+	IsReference      bool
+	IsImplementation bool
+	IsTypeDefinition bool
+	IsDefinition     bool
+}
+
+// Diagnostic is a compiler or linter message attached to an occurrence.
+type Diagnostic struct {
+	Severity int32
+	Code     string
+	Message  string
+	Source   string
+	Tags     []int32
+}
+
+// Symbol roles are bit flags set on an occurrence.
+const (
+	SymbolRoleDefinition  int32 = 0x1
+	SymbolRoleImport      int32 = 0x2
+	SymbolRoleWriteAccess int32 = 0x4
+	SymbolRoleReadAccess  int32 = 0x8
+	SymbolRoleGenerated   int32 = 0x10
+	SymbolRoleTest        int32 = 0x20
+)
+
+// IsDefinition returns true if the occurrence defines its symbol.
+func (o *Occurrence) IsDefinition() bool {
+	return o.SymbolRoles&SymbolRoleDefinition != 0
+}
+
+// Start returns the zero-based line and character at which the occurrence begins.
+func (o *Occurrence) Start() (line, character int) {
+	if len(o.Range) < 3 {
+		return 0, 0
+	}
+
+	return int(o.Range[0]), int(o.Range[1])
+}
+
+// End returns the zero-based line and character at which the occurrence ends.
+func (o *Occurrence) End() (line, character int) {
+	switch len(o.Range) {
+	case 3:
+		return int(o.Range[0]), int(o.Range[2])
+	case 4:
+		return int(o.Range[2]), int(o.Range[3])
+	}
+
+	return 0, 0
+}
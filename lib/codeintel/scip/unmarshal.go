@@ -0,0 +1,203 @@
+package scip
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// field is a single decoded field of a protobuf message. Only one of varint or bytes
+// is set, depending on the wire type.
+type field struct {
+	num    protowire.Number
+	typ    protowire.Type
+	varint uint64
+	bytes  []byte
+}
+
+// eachField invokes the given function for each field of the given encoded message.
+func eachField(b []byte, fn func(f field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		f := field{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f field) isVarint() bool { return f.typ == protowire.VarintType }
+func (f field) isBytes() bool  { return f.typ == protowire.BytesType }
+
+// appendInt32s appends the values of a repeated int32 or enum field, which may be
+// encoded either packed or as individual varints.
+func (f field) appendInt32s(values []int32) ([]int32, error) {
+	if f.isVarint() {
+		return append(values, int32(f.varint)), nil
+	}
+	if !f.isBytes() {
+		return values, nil
+	}
+
+	b := f.bytes
+	for len(b) > 0 {
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		values = append(values, int32(v))
+		b = b[n:]
+	}
+
+	return values, nil
+}
+
+func unmarshalMetadata(b []byte, m *Metadata) error {
+	return eachField(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.isVarint():
+			m.Version = int32(f.varint)
+		case f.num == 2 && f.isBytes():
+			return unmarshalToolInfo(f.bytes, &m.ToolInfo)
+		case f.num == 3 && f.isBytes():
+			m.ProjectRoot = string(f.bytes)
+		case f.num == 4 && f.isVarint():
+			m.TextDocumentEncoding = int32(f.varint)
+		}
+		return nil
+	})
+}
+
+func unmarshalToolInfo(b []byte, t *ToolInfo) error {
+	return eachField(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.isBytes():
+			t.Name = string(f.bytes)
+		case f.num == 2 && f.isBytes():
+			t.Version = string(f.bytes)
+		case f.num == 3 && f.isBytes():
+			t.Arguments = append(t.Arguments, string(f.bytes))
+		}
+		return nil
+	})
+}
+
+func unmarshalDocument(b []byte, d *Document) error {
+	return eachField(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.isBytes():
+			d.RelativePath = string(f.bytes)
+		case f.num == 2 && f.isBytes():
+			occurrence := &Occurrence{}
+			if err := unmarshalOccurrence(f.bytes, occurrence); err != nil {
+				return err
+			}
+			d.Occurrences = append(d.Occurrences, occurrence)
+		case f.num == 3 && f.isBytes():
+			symbol := &SymbolInformation{}
+			if err := unmarshalSymbolInformation(f.bytes, symbol); err != nil {
+				return err
+			}
+			d.Symbols = append(d.Symbols, symbol)
+		case f.num == 4 && f.isBytes():
+			d.Language = string(f.bytes)
+		}
+		return nil
+	})
+}
+
+func unmarshalOccurrence(b []byte, o *Occurrence) error {
+	return eachField(b, func(f field) (err error) {
+		switch {
+		case f.num == 1:
+			o.Range, err = f.appendInt32s(o.Range)
+		case f.num == 2 && f.isBytes():
+			o.Symbol = string(f.bytes)
+		case f.num == 3 && f.isVarint():
+			o.SymbolRoles = int32(f.varint)
+		case f.num == 4 && f.isBytes():
+			o.OverrideDocumentation = append(o.OverrideDocumentation, string(f.bytes))
+		case f.num == 5 && f.isVarint():
+			o.SyntaxKind = int32(f.varint)
+		case f.num == 6 && f.isBytes():
+			diagnostic := &Diagnostic{}
+			if err := unmarshalDiagnostic(f.bytes, diagnostic); err != nil {
+				return err
+			}
+			o.Diagnostics = append(o.Diagnostics, diagnostic)
+		}
+		return err
+	})
+}
+
+func unmarshalSymbolInformation(b []byte, s *SymbolInformation) error {
+	return eachField(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.isBytes():
+			s.Symbol = string(f.bytes)
+		case f.num == 3 && f.isBytes():
+			s.Documentation = append(s.Documentation, string(f.bytes))
+		case f.num == 4 && f.isBytes():
+			relationship := &Relationship{}
+			if err := unmarshalRelationship(f.bytes, relationship); err != nil {
+				return err
+			}
+			s.Relationships = append(s.Relationships, relationship)
+		}
+		return nil
+	})
+}
+
+func unmarshalRelationship(b []byte, r *Relationship) error {
+	return eachField(b, func(f field) error {
+		switch {
+		case f.num == 1 && f.isBytes():
+			r.Symbol = string(f.bytes)
+		case f.num == 2 && f.isVarint():
+			r.IsReference = protowire.DecodeBool(f.varint)
+		case f.num == 3 && f.isVarint():
+			r.IsImplementation = protowire.DecodeBool(f.varint)
+		case f.num == 4 && f.isVarint():
+			r.IsTypeDefinition = protowire.DecodeBool(f.varint)
+		case f.num == 5 && f.isVarint():
+			r.IsDefinition = protowire.DecodeBool(f.varint)
+		}
+		return nil
+	})
+}
+
+func unmarshalDiagnostic(b []byte, d *Diagnostic) error {
+	return eachField(b, func(f field) (err error) {
+		switch {
+		case f.num == 1 && f.isVarint():
+			d.Severity = int32(f.varint)
+		case f.num == 2 && f.isBytes():
+			d.Code = string(f.bytes)
+		case f.num == 3 && f.isBytes():
+			d.Message = string(f.bytes)
+		case f.num == 4 && f.isBytes():
+			d.Source = string(f.bytes)
+		case f.num == 5:
+			d.Tags, err = f.appendInt32s(d.Tags)
+		}
+		return err
+	})
+}
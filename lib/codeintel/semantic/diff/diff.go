@@ -117,6 +117,13 @@ func Diff(old, new *semantic.GroupedBundleDataMaps) string {
 				fmt.Sprintf("Reference: %v -> ", locationString(location)),
 			)
 
+			diffLocations(
+				&builder,
+				oldResult.Implementations,
+				newResult.Implementations,
+				fmt.Sprintf("Implementation: %v -> ", locationString(location)),
+			)
+
 			diffQualifiedMonikers(
 				&builder,
 				oldResult.Monikers,
@@ -128,6 +135,7 @@ func Diff(old, new *semantic.GroupedBundleDataMaps) string {
 
 	diffMonikers(&builder, old.Definitions, new.Definitions, "MonikerDefinition: ")
 	diffMonikers(&builder, old.References, new.References, "MonikerReference: ")
+	diffMonikers(&builder, old.Implementations, new.Implementations, "MonikerImplementation: ")
 
 	return builder.String()
 }
@@ -25,15 +25,16 @@ type DocumentData struct {
 // that was reachable via a result set has been collapsed into this object during
 // conversion.
 type RangeData struct {
-	StartLine             int  // 0-indexed, inclusive
-	StartCharacter        int  // 0-indexed, inclusive
-	EndLine               int  // 0-indexed, inclusive
-	EndCharacter          int  // 0-indexed, inclusive
-	DefinitionResultID    ID   // possibly empty
-	ReferenceResultID     ID   // possibly empty
-	HoverResultID         ID   // possibly empty
-	DocumentationResultID ID   // possibly empty
-	MonikerIDs            []ID // possibly empty
+	StartLine              int  // 0-indexed, inclusive
+	StartCharacter         int  // 0-indexed, inclusive
+	EndLine                int  // 0-indexed, inclusive
+	EndCharacter           int  // 0-indexed, inclusive
+	DefinitionResultID     ID   // possibly empty
+	ReferenceResultID      ID   // possibly empty
+	ImplementationResultID ID   // possibly empty
+	HoverResultID          ID   // possibly empty
+	DocumentationResultID  ID   // possibly empty
+	MonikerIDs             []ID // possibly empty
 }
 
 // MonikerData represent a unique name (eventually) attached to a range.
@@ -210,11 +211,17 @@ type GroupedBundleDataChans struct {
 	ResultChunks          chan IndexedResultChunkData
 	Definitions           chan MonikerLocations
 	References            chan MonikerLocations
+	Implementations       chan MonikerLocations
 	Packages              []Package
 	PackageReferences     []PackageReference
 	DocumentationPages    chan *DocumentationPageData
 	DocumentationPathInfo chan *DocumentationPathInfoData
 	DocumentationMappings chan DocumentationMapping
+
+	// Err returns any error encountered while producing the values of the channels above.
+	// It should be called once the channels have been drained, as errors encountered while
+	// producing values that have not been consumed are not reported.
+	Err func() error
 }
 
 type GroupedBundleDataMaps struct {
@@ -223,6 +230,7 @@ type GroupedBundleDataMaps struct {
 	ResultChunks      map[int]ResultChunkData
 	Definitions       map[string]map[string][]LocationData
 	References        map[string]map[string][]LocationData
+	Implementations   map[string]map[string][]LocationData
 	Packages          []Package
 	PackageReferences []PackageReference
 }
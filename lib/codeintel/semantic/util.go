@@ -214,6 +214,24 @@ func GroupedBundleDataMapsToChans(ctx context.Context, maps *GroupedBundleDataMa
 			}
 		}
 	}()
+	monikerImplsChan := make(chan MonikerLocations)
+	go func() {
+		defer close(monikerImplsChan)
+
+		for scheme, identMap := range maps.Implementations {
+			for ident, locations := range identMap {
+				select {
+				case monikerImplsChan <- MonikerLocations{
+					Scheme:     scheme,
+					Identifier: ident,
+					Locations:  locations,
+				}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
 
 	return &GroupedBundleDataChans{
 		Meta:              maps.Meta,
@@ -221,8 +239,10 @@ func GroupedBundleDataMapsToChans(ctx context.Context, maps *GroupedBundleDataMa
 		ResultChunks:      resultChunkChan,
 		Definitions:       monikerDefsChan,
 		References:        monikerRefsChan,
+		Implementations:   monikerImplsChan,
 		Packages:          maps.Packages,
 		PackageReferences: maps.PackageReferences,
+		Err:               func() error { return nil },
 	}
 }
 
@@ -250,6 +270,15 @@ func GroupedBundleDataChansToMaps(chans *GroupedBundleDataChans) *GroupedBundleD
 		}
 		monikerRefsMap[monikerRefs.Scheme][monikerRefs.Identifier] = monikerRefs.Locations
 	}
+	monikerImplsMap := make(map[string]map[string][]LocationData)
+	if chans.Implementations != nil {
+		for monikerImpls := range chans.Implementations {
+			if _, exists := monikerImplsMap[monikerImpls.Scheme]; !exists {
+				monikerImplsMap[monikerImpls.Scheme] = make(map[string][]LocationData)
+			}
+			monikerImplsMap[monikerImpls.Scheme][monikerImpls.Identifier] = monikerImpls.Locations
+		}
+	}
 
 	return &GroupedBundleDataMaps{
 		Meta:              chans.Meta,
@@ -257,6 +286,7 @@ func GroupedBundleDataChansToMaps(chans *GroupedBundleDataChans) *GroupedBundleD
 		ResultChunks:      resultChunkMap,
 		Definitions:       monikerDefsMap,
 		References:        monikerRefsMap,
+		Implementations:   monikerImplsMap,
 		Packages:          chans.Packages,
 		PackageReferences: chans.PackageReferences,
 	}
@@ -6,6 +6,8 @@ import (
 	"io"
 
 	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/scip"
 )
 
 // MaxBufferSize is the maximum size of the metaData line in the dump. This should be large enough
@@ -33,9 +35,20 @@ type toolInfo struct {
 
 // ReadIndexerName returns the name of the tool that generated the given index contents.
 // This function reads only the first line of the file, where the metadata vertex is
-// assumed to be in all valid dumps.
+// assumed to be in all valid dumps. SCIP indexes are also supported, in which case only
+// the input up to the end of the metadata message is read.
 func ReadIndexerName(r io.Reader) (string, error) {
-	line, isPrefix, err := bufio.NewReaderSize(r, MaxBufferSize).ReadLine()
+	br := bufio.NewReaderSize(r, MaxBufferSize)
+
+	isSCIP, err := scip.Sniff(br)
+	if err != nil {
+		return "", err
+	}
+	if isSCIP {
+		return readSCIPIndexerName(br)
+	}
+
+	line, isPrefix, err := br.ReadLine()
 	if err != nil {
 		return "", err
 	}
@@ -54,3 +67,12 @@ func ReadIndexerName(r io.Reader) (string, error) {
 
 	return meta.ToolInfo.Name, nil
 }
+
+func readSCIPIndexerName(r io.Reader) (string, error) {
+	metadata, err := scip.ReadMetadata(r)
+	if err != nil || metadata.ToolInfo.Name == "" {
+		return "", ErrInvalidMetaDataVertex
+	}
+
+	return metadata.ToolInfo.Name, nil
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"strings"
 	"testing"
+
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/scip"
 )
 
 const testMetaDataVertex = `{"label": "metaData", "toolInfo": {"name": "test"}}`
@@ -28,6 +30,29 @@ func TestReadIndexerNameMalformed(t *testing.T) {
 	}
 }
 
+func TestReadIndexerNameSCIP(t *testing.T) {
+	index := &scip.Index{
+		Metadata:  scip.Metadata{ToolInfo: scip.ToolInfo{Name: "scip-test"}},
+		Documents: []*scip.Document{{RelativePath: "main.go"}},
+	}
+
+	name, err := ReadIndexerName(bytes.NewReader(scip.Marshal(index)))
+	if err != nil {
+		t.Fatalf("unexpected error reading indexer name: %s", err)
+	}
+	if name != "scip-test" {
+		t.Errorf("unexpected indexer name. want=%s have=%s", "scip-test", name)
+	}
+}
+
+func TestReadIndexerNameSCIPMissingToolInfo(t *testing.T) {
+	index := &scip.Index{Documents: []*scip.Document{{RelativePath: "main.go"}}}
+
+	if _, err := ReadIndexerName(bytes.NewReader(scip.Marshal(index))); err != ErrInvalidMetaDataVertex {
+		t.Fatalf("unexpected error reading indexer name. want=%q have=%q", ErrInvalidMetaDataVertex, err)
+	}
+}
+
 func generateTestIndex(metaDataVertex string) io.Reader {
 	lines := []string{metaDataVertex}
 	for i := 0; i < 20000; i++ {
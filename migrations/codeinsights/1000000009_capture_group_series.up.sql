@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE insight_series
+    ADD COLUMN generated_from_capture_groups BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN insight_series.generated_from_capture_groups IS 'Whether this series generates one dynamic series per distinct value of the first capture group of its regexp query, rather than a single series of match counts.';
+
+ALTER TABLE series_points
+    ADD COLUMN label TEXT;
+
+COMMENT ON COLUMN series_points.label IS 'The value of the capture group that this data point counts matches of, for series generated from capture groups. NULL for all other series.';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE insights_query_runner_jobs
+    DROP COLUMN IF EXISTS generated_from_capture_groups;
+
+COMMIT;
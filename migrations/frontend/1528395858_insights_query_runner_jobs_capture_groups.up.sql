@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE insights_query_runner_jobs
+    ADD COLUMN generated_from_capture_groups BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN insights_query_runner_jobs.generated_from_capture_groups IS 'Whether to record one data point per distinct value of the first capture group of the query, rather than a single match count.';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- We need to leave the migration record in place here for the OOB down
+-- migration, so no changes here.
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+INSERT INTO out_of_band_migrations (id, team, component, description, introduced_version_major, introduced_version_minor, non_destructive)
+VALUES (
+    11,                                             -- This must be consistent across all Sourcegraph instances
+    'core-application',                             -- Team owning migration
+    'frontend-db.encryption-keys',                  -- Component being migrated
+    'Re-encrypt data encrypted with rotated keys',  -- Description
+    3,                                              -- The next minor release (major version)
+    31,                                             -- The next minor release (minor version)
+    true                                            -- Can be read with previous version without down migration
+)
+ON CONFLICT DO NOTHING;
+
+COMMIT;
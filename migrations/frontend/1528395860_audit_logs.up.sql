@@ -0,0 +1,42 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id bigserial PRIMARY KEY,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    actor_user_id integer,
+    actor_internal boolean NOT NULL DEFAULT false,
+    action text NOT NULL,
+    target_type text NOT NULL,
+    target_id text NOT NULL,
+    before jsonb,
+    after jsonb,
+    remote_addr text NOT NULL DEFAULT '',
+    forwarded_for text NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    version text NOT NULL,
+    CONSTRAINT audit_logs_action_not_empty CHECK (action <> ''),
+    CONSTRAINT audit_logs_target_type_not_empty CHECK (target_type <> '')
+);
+
+CREATE INDEX IF NOT EXISTS audit_logs_created_at ON audit_logs(created_at);
+CREATE INDEX IF NOT EXISTS audit_logs_actor_user_id ON audit_logs(actor_user_id);
+CREATE INDEX IF NOT EXISTS audit_logs_action ON audit_logs(action);
+CREATE INDEX IF NOT EXISTS audit_logs_target ON audit_logs(target_type, target_id);
+
+COMMENT ON TABLE audit_logs IS 'Append-only log of changes to site configuration, external services, permissions and organization memberships.';
+COMMENT ON COLUMN audit_logs.actor_user_id IS 'The user who made the change, if any. This is not a foreign key, so that entries outlive the users they refer to.';
+COMMENT ON COLUMN audit_logs.actor_internal IS 'Whether the change was made by an internal Sourcegraph service rather than a user.';
+COMMENT ON COLUMN audit_logs.action IS 'The kind of change, e.g. ExternalServiceUpdated.';
+COMMENT ON COLUMN audit_logs.target_type IS 'The type of the changed entity, e.g. ExternalService.';
+COMMENT ON COLUMN audit_logs.target_id IS 'The ID of the changed entity, if it has one.';
+COMMENT ON COLUMN audit_logs.before IS 'The state of the changed entity before the change, with secrets redacted.';
+COMMENT ON COLUMN audit_logs.after IS 'The state of the changed entity after the change, with secrets redacted.';
+
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger LANGUAGE plpgsql AS $lang$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END $lang$;
+
+CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs FOR EACH ROW EXECUTE PROCEDURE audit_logs_append_only();
+
+COMMIT;
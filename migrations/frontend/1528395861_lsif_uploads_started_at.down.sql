@@ -0,0 +1 @@
+DROP INDEX IF EXISTS lsif_uploads_started_at;
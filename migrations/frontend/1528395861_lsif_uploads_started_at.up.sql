@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS lsif_uploads_started_at ON lsif_uploads(started_at);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS lsif_indexes_started_at;
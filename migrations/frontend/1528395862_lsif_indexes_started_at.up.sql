@@ -0,0 +1 @@
+CREATE INDEX CONCURRENTLY IF NOT EXISTS lsif_indexes_started_at ON lsif_indexes(started_at);
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE changesets DROP COLUMN IF EXISTS auto_merge_blocked_reason;
+
+DROP TABLE IF EXISTS batch_change_auto_merge_policies;
+
+COMMIT;
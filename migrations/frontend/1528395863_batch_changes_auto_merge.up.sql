@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS batch_change_auto_merge_policies (
+    batch_change_id bigint PRIMARY KEY REFERENCES batch_changes(id) ON DELETE CASCADE DEFERRABLE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+    required_check_state text,
+    required_review_state text,
+    squash boolean NOT NULL DEFAULT false,
+    merge_window_start integer,
+    merge_window_end integer,
+    merge_window_weekdays integer[] NOT NULL DEFAULT '{}',
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT batch_change_auto_merge_policies_merge_window_start_valid CHECK (merge_window_start >= 0 AND merge_window_start < 1440),
+    CONSTRAINT batch_change_auto_merge_policies_merge_window_end_valid CHECK (merge_window_end >= 0 AND merge_window_end < 1440)
+);
+
+COMMENT ON TABLE batch_change_auto_merge_policies IS 'Conditions under which the changesets of a batch change are merged automatically.';
+COMMENT ON COLUMN batch_change_auto_merge_policies.user_id IS 'The user who set the policy. Changesets are merged on their behalf.';
+COMMENT ON COLUMN batch_change_auto_merge_policies.required_check_state IS 'The check state a changeset must have to be merged. NULL if checks are not considered.';
+COMMENT ON COLUMN batch_change_auto_merge_policies.required_review_state IS 'The review state a changeset must have to be merged. NULL if reviews are not considered.';
+COMMENT ON COLUMN batch_change_auto_merge_policies.merge_window_start IS 'The start of the daily merge window in minutes after midnight UTC. NULL if changesets can be merged at any time.';
+COMMENT ON COLUMN batch_change_auto_merge_policies.merge_window_end IS 'The end of the daily merge window in minutes after midnight UTC. Windows ending before they start wrap around midnight.';
+COMMENT ON COLUMN batch_change_auto_merge_policies.merge_window_weekdays IS 'The days of the week (0 is Sunday) on which the merge window applies. Empty if it applies on every day.';
+
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS auto_merge_blocked_reason text;
+
+COMMENT ON COLUMN changesets.auto_merge_blocked_reason IS 'Why the auto-merge policy of the owning batch change has not merged this changeset yet.';
+
+COMMIT;
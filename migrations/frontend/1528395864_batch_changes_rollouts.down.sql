@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE changesets DROP COLUMN IF EXISTS rollout_held;
+ALTER TABLE changesets DROP COLUMN IF EXISTS rollout_wave;
+
+DROP TABLE IF EXISTS batch_change_rollouts;
+
+COMMIT;
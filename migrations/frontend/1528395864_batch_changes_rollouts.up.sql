@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS batch_change_rollouts (
+    batch_change_id bigint PRIMARY KEY REFERENCES batch_changes(id) ON DELETE CASCADE DEFERRABLE,
+    current_wave integer NOT NULL DEFAULT 0,
+    paused_at timestamp with time zone,
+    pause_reason text,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE batch_change_rollouts IS 'The state of the publication in waves of the changesets of batch changes whose batch spec declares rollout waves.';
+COMMENT ON COLUMN batch_change_rollouts.current_wave IS 'The index of the last wave whose changesets can be published.';
+COMMENT ON COLUMN batch_change_rollouts.paused_at IS 'When the rollout was paused. No further changesets are published while the rollout is paused.';
+
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS rollout_wave integer;
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS rollout_held boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN changesets.rollout_wave IS 'The rollout wave the changeset is published in. NULL if it has not been assigned to a wave yet.';
+COMMENT ON COLUMN changesets.rollout_held IS 'Whether the reconciler held back the publication of the changeset because its rollout wave has not been released yet.';
+
+COMMIT;
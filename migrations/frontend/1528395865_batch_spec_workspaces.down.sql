@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS batch_spec_workspaces;
+DROP TABLE IF EXISTS batch_spec_resolution_jobs;
+
+COMMIT;
@@ -0,0 +1,60 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS batch_spec_resolution_jobs (
+    id bigserial PRIMARY KEY,
+    batch_spec_id bigint NOT NULL REFERENCES batch_specs(id) ON DELETE CASCADE DEFERRABLE,
+
+    state text DEFAULT 'queued',
+    failure_message text,
+    started_at timestamp with time zone,
+    finished_at timestamp with time zone,
+    process_after timestamp with time zone,
+    num_resets integer NOT NULL DEFAULT 0,
+    num_failures integer NOT NULL DEFAULT 0,
+    execution_logs json[],
+    worker_hostname text NOT NULL DEFAULT '',
+    last_heartbeat_at timestamp with time zone,
+
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS batch_spec_resolution_jobs_batch_spec_id ON batch_spec_resolution_jobs(batch_spec_id);
+CREATE INDEX IF NOT EXISTS batch_spec_resolution_jobs_state ON batch_spec_resolution_jobs(state);
+
+COMMENT ON TABLE batch_spec_resolution_jobs IS 'Jobs that resolve the repositories and workspaces of batch specs that are executed server-side.';
+
+CREATE TABLE IF NOT EXISTS batch_spec_workspaces (
+    id bigserial PRIMARY KEY,
+    batch_spec_id bigint NOT NULL REFERENCES batch_specs(id) ON DELETE CASCADE DEFERRABLE,
+
+    repo_id integer NOT NULL REFERENCES repo(id) DEFERRABLE,
+    branch text NOT NULL,
+    commit text NOT NULL,
+    path text NOT NULL DEFAULT '',
+    only_fetch_workspace boolean NOT NULL DEFAULT false,
+    changeset_spec_ids jsonb NOT NULL DEFAULT '[]'::jsonb,
+
+    state text DEFAULT 'queued',
+    failure_message text,
+    started_at timestamp with time zone,
+    finished_at timestamp with time zone,
+    process_after timestamp with time zone,
+    num_resets integer NOT NULL DEFAULT 0,
+    num_failures integer NOT NULL DEFAULT 0,
+    execution_logs json[],
+    worker_hostname text NOT NULL DEFAULT '',
+    last_heartbeat_at timestamp with time zone,
+
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS batch_spec_workspaces_batch_spec_id ON batch_spec_workspaces(batch_spec_id);
+CREATE INDEX IF NOT EXISTS batch_spec_workspaces_state ON batch_spec_workspaces(state);
+
+COMMENT ON TABLE batch_spec_workspaces IS 'The workspaces in which the steps of batch specs that are executed server-side are run. Each workspace is a job in the executor queue.';
+COMMENT ON COLUMN batch_spec_workspaces.path IS 'The path of the workspace relative to the repository root. Empty for the repository root.';
+COMMENT ON COLUMN batch_spec_workspaces.changeset_spec_ids IS 'The IDs of the changeset specs created from the diff the execution of the workspace produced.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE changesets ADD COLUMN IF NOT EXISTS dependencies_held boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN changesets.dependencies_held IS 'Whether the reconciler held back the publication of the changeset, or published it as a draft, because the changesets it depends on have not been merged yet.';
+
+COMMIT;
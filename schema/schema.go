@@ -160,6 +160,8 @@ type BatchSpec struct {
 	Name string `json:"name"`
 	// On description: The set of repositories (and branches) to run the batch change on, specified as a list of search queries (that match repositories) and/or specific repositories.
 	On []interface{} `json:"on,omitempty"`
+	// Rollout description: Publishes the changesets of the batch change in waves. Each wave is only published once enough changesets of the earlier waves have been merged or passed their checks. Changesets that are not selected by any wave are published in a final wave.
+	Rollout *Rollout `json:"rollout,omitempty"`
 	// Steps description: The sequence of commands to run (for each repository branch matched in the `on` property) to produce the workspace changes that will be included in the batch change.
 	Steps []*Step `json:"steps,omitempty"`
 	// TransformChanges description: Optional transformations to apply to the changes produced in each repository.
@@ -168,12 +170,20 @@ type BatchSpec struct {
 	Workspaces []*WorkspaceConfiguration `json:"workspaces,omitempty"`
 }
 
+// BitbucketCloudAuthorization description: If non-null, enforces Bitbucket Cloud repository permissions. Permissions are read with the configured username and app password, so that account must be an owner of every workspace whose repositories are synced, and the app password needs the "Account: Read", "Workspace membership: Read" and "Repositories: Admin" permissions.
+type BitbucketCloudAuthorization struct {
+	// IdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the Bitbucket Cloud identity to use for a given Sourcegraph user. When 'username' is used, Sourcegraph assumes usernames are identical to Bitbucket Cloud nicknames and `auth.enableUsernameChanges` must be set to false for security reasons.
+	IdentityProvider BitbucketCloudIdentityProvider `json:"identityProvider"`
+}
+
 // BitbucketCloudConnection description: Configuration for a connection to Bitbucket Cloud.
 type BitbucketCloudConnection struct {
 	// ApiURL description: The API URL of Bitbucket Cloud, such as https://api.bitbucket.org. Generally, admin should not modify the value of this option because Bitbucket Cloud is a public hosting platform.
 	ApiURL string `json:"apiURL,omitempty"`
 	// AppPassword description: The app password to use when authenticating to the Bitbucket Cloud. Also set the corresponding "username" field.
 	AppPassword string `json:"appPassword"`
+	// Authorization description: If non-null, enforces Bitbucket Cloud repository permissions. Permissions are read with the configured username and app password, so that account must be an owner of every workspace whose repositories are synced, and the app password needs the "Account: Read", "Workspace membership: Read" and "Repositories: Admin" permissions.
+	Authorization *BitbucketCloudAuthorization `json:"authorization,omitempty"`
 	// Exclude description: A list of repositories to never mirror from Bitbucket Cloud. Takes precedence over "teams" configuration.
 	//
 	// Supports excluding by name ({"name": "myorg/myrepo"}) or by UUID ({"uuid": "{fceb73c7-cef6-4abe-956d-e471281126bd}"}).
@@ -200,6 +210,33 @@ type BitbucketCloudConnection struct {
 	Url string `json:"url"`
 	// Username description: The username to use when authenticating to the Bitbucket Cloud. Also set the corresponding "appPassword" field.
 	Username string `json:"username"`
+	// WebhookSecret description: A secret used to authenticate incoming webhook requests from Bitbucket Cloud. Bitbucket Cloud doesn't sign webhook payloads, so the secret must be appended to the webhook URL as the "secret" query parameter.
+	WebhookSecret string `json:"webhookSecret,omitempty"`
+}
+
+// BitbucketCloudIdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the Bitbucket Cloud identity to use for a given Sourcegraph user. When 'username' is used, Sourcegraph assumes usernames are identical to Bitbucket Cloud nicknames and `auth.enableUsernameChanges` must be set to false for security reasons.
+type BitbucketCloudIdentityProvider struct {
+	Username *BitbucketCloudUsernameIdentity
+}
+
+func (v BitbucketCloudIdentityProvider) MarshalJSON() ([]byte, error) {
+	if v.Username != nil {
+		return json.Marshal(v.Username)
+	}
+	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
+}
+func (v *BitbucketCloudIdentityProvider) UnmarshalJSON(data []byte) error {
+	var d struct {
+		DiscriminantProperty string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	switch d.DiscriminantProperty {
+	case "username":
+		return json.Unmarshal(data, &v.Username)
+	}
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"username"})
 }
 
 // BitbucketCloudRateLimit description: Rate limit applied when making background API requests to Bitbucket Cloud.
@@ -209,6 +246,9 @@ type BitbucketCloudRateLimit struct {
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
+type BitbucketCloudUsernameIdentity struct {
+	Type string `json:"type"`
+}
 
 // BitbucketServerAuthorization description: If non-null, enforces Bitbucket Server repository permissions.
 type BitbucketServerAuthorization struct {
@@ -360,6 +400,26 @@ type BuiltinAuthProvider struct {
 	Type        string `json:"type"`
 }
 
+// ChangesetDependency description: A changeset that has to be merged first. It is either the changeset created by a batch change for a branch, or a changeset tracked on Sourcegraph with the given external ID.
+type ChangesetDependency struct {
+	// BatchChange description: The name of the batch change that created the changeset for the branch. It has to be in the same namespace as the batch change that declares the dependency. Defaults to that batch change.
+	BatchChange string `json:"batchChange,omitempty"`
+	// Branch description: The branch of the changeset created by a batch change.
+	Branch string `json:"branch,omitempty"`
+	// ExternalID description: The ID of the changeset on the code host.
+	ExternalID string `json:"externalID,omitempty"`
+	// Repository description: The name of the repository of the changeset.
+	Repository string `json:"repository"`
+}
+
+// ChangesetReviewers description: The reviewers to request when a changeset is published. Requesting reviewers is supported on GitHub and GitLab.
+type ChangesetReviewers struct {
+	// CodeOwners description: Request reviews from the owners of the files changed by the changeset, as defined in the CODEOWNERS file of the repository. Both the GitHub and the GitLab CODEOWNERS formats are supported.
+	CodeOwners bool `json:"codeOwners,omitempty"`
+	// Fallback description: The users or teams to request reviews from when no code owner matches the changed files, in CODEOWNERS syntax.
+	Fallback []string `json:"fallback,omitempty"`
+}
+
 // ChangesetTemplate description: A template describing how to create (and update) changesets with the file changes produced by the command steps.
 type ChangesetTemplate struct {
 	// Body description: The body (description) of the changeset.
@@ -368,8 +428,11 @@ type ChangesetTemplate struct {
 	Branch string `json:"branch"`
 	// Commit description: The Git commit to create with the changes.
 	Commit ExpandedGitCommitDescription `json:"commit"`
+	// DependsOn description: Changesets that have to be merged before the changesets of this batch change are published. Until then, changesets are published as drafts on code hosts that support them and are not published otherwise.
+	DependsOn []*ChangesetDependency `json:"dependsOn,omitempty"`
 	// Published description: Whether to publish the changeset. An unpublished changeset can be previewed on Sourcegraph by any person who can view the batch change, but its commit, branch, and pull request aren't created on the code host. A published changeset results in a commit, branch, and pull request being created on the code host. If omitted, the publication state is controlled from the Batch Changes UI.
-	Published interface{} `json:"published,omitempty"`
+	Published interface{}         `json:"published,omitempty"`
+	Reviewers *ChangesetReviewers `json:"reviewers,omitempty"`
 	// Title description: The title of the changeset.
 	Title string `json:"title"`
 }
@@ -411,10 +474,13 @@ type Dotcom struct {
 
 // EncryptionKey description: Config for a key
 type EncryptionKey struct {
-	Cloudkms *CloudKMSEncryptionKey
-	Awskms   *AWSKMSEncryptionKey
-	Mounted  *MountedEncryptionKey
-	Noop     *NoOpEncryptionKey
+	Cloudkms     *CloudKMSEncryptionKey
+	Awskms       *AWSKMSEncryptionKey
+	Mounted      *MountedEncryptionKey
+	File         *FileEncryptionKey
+	Vaulttransit *VaultTransitEncryptionKey
+	Envelope     *EnvelopeEncryptionKey
+	Noop         *NoOpEncryptionKey
 }
 
 func (v EncryptionKey) MarshalJSON() ([]byte, error) {
@@ -427,6 +493,15 @@ func (v EncryptionKey) MarshalJSON() ([]byte, error) {
 	if v.Mounted != nil {
 		return json.Marshal(v.Mounted)
 	}
+	if v.File != nil {
+		return json.Marshal(v.File)
+	}
+	if v.Vaulttransit != nil {
+		return json.Marshal(v.Vaulttransit)
+	}
+	if v.Envelope != nil {
+		return json.Marshal(v.Envelope)
+	}
 	if v.Noop != nil {
 		return json.Marshal(v.Noop)
 	}
@@ -444,12 +519,18 @@ func (v *EncryptionKey) UnmarshalJSON(data []byte) error {
 		return json.Unmarshal(data, &v.Awskms)
 	case "cloudkms":
 		return json.Unmarshal(data, &v.Cloudkms)
+	case "envelope":
+		return json.Unmarshal(data, &v.Envelope)
+	case "file":
+		return json.Unmarshal(data, &v.File)
 	case "mounted":
 		return json.Unmarshal(data, &v.Mounted)
 	case "noop":
 		return json.Unmarshal(data, &v.Noop)
+	case "vaulttransit":
+		return json.Unmarshal(data, &v.Vaulttransit)
 	}
-	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"cloudkms", "awskms", "mounted", "noop"})
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"cloudkms", "awskms", "mounted", "file", "vaulttransit", "envelope", "noop"})
 }
 
 // EncryptionKeys description: Configuration for encryption keys used to encrypt data at rest in the database.
@@ -462,6 +543,13 @@ type EncryptionKeys struct {
 	ExternalServiceKey     *EncryptionKey `json:"externalServiceKey,omitempty"`
 	UserExternalAccountKey *EncryptionKey `json:"userExternalAccountKey,omitempty"`
 }
+
+// EnvelopeEncryptionKey description: Envelope encryption: every value is encrypted locally with a fresh data key, and only the data key is encrypted with the wrapped key. This limits the amount of data sent to (and the number of requests made to) a remote key management service.
+type EnvelopeEncryptionKey struct {
+	// Key description: The key encrypting the data keys.
+	Key  EncryptionKey `json:"key"`
+	Type string        `json:"type"`
+}
 type ExcludedAWSCodeCommitRepo struct {
 	// Id description: The ID of an AWS Code Commit repository (as returned by the AWS API) to exclude from mirroring. Use this to exclude the repository, even if renamed, or to differentiate between repositories with the same name in multiple regions.
 	Id string `json:"id,omitempty"`
@@ -484,6 +572,12 @@ type ExcludedBitbucketServerRepo struct {
 	// Pattern description: Regular expression which matches against the name of a Bitbucket Server repo.
 	Pattern string `json:"pattern,omitempty"`
 }
+type ExcludedGerritProject struct {
+	// Name description: The name of a Gerrit project ("platform/build") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gerrit project.
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitHubRepo struct {
 	// Archived description: If set to true, archived repositories will be excluded.
 	Archived bool `json:"archived,omitempty"`
@@ -502,6 +596,18 @@ type ExcludedGitLabProject struct {
 	// Name description: The name of a GitLab project ("group/name") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
 }
+type ExcludedGiteaRepo struct {
+	// Archived description: If set to true, archived repositories will be excluded.
+	Archived bool `json:"archived,omitempty"`
+	// Forks description: If set to true, forks will be excluded.
+	Forks bool `json:"forks,omitempty"`
+	// Id description: The ID of a Gitea repository (as returned by the Gitea instance's API) to exclude from mirroring.
+	Id int `json:"id,omitempty"`
+	// Name description: The name of a Gitea repository ("owner/name") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the "owner/name" of a Gitea repository.
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitoliteRepo struct {
 	// Name description: The name of a Gitolite repo ("my-repo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -574,6 +680,48 @@ type ExternalIdentity struct {
 	Type           string `json:"type"`
 }
 
+// FileEncryptionKey description: This encryption key is read from a keyring file containing several versions of a key, so that the key can be rotated without losing access to data encrypted with previous versions.
+type FileEncryptionKey struct {
+	// Filepath description: Path to a JSON file of the form {"primary": "<version>", "keys": {"<version>": "<base64 encoded 32 byte secret>"}}. Data is encrypted with the primary version, and can be decrypted with any version in the file.
+	Filepath string `json:"filepath"`
+	Keyname  string `json:"keyname"`
+	Type     string `json:"type"`
+}
+
+// GerritConnection description: Configuration for a connection to Gerrit.
+type GerritConnection struct {
+	// Exclude description: A list of projects to never mirror from Gerrit. Takes precedence over "projects" configuration.
+	//
+	// Supports excluding by name ({"name": "platform/build"}) or by a regular expression matching the name ({"pattern": "^device/.*"}).
+	Exclude []*ExcludedGerritProject `json:"exclude,omitempty"`
+	// Password description: The HTTP password (as generated under Settings > HTTP Credentials in Gerrit) to use when authenticating to Gerrit. Also set the corresponding "username" field.
+	Password string `json:"password,omitempty"`
+	// Projects description: An array of Gerrit project names that should be mirrored on Sourcegraph. If unset, all projects visible to the configured user are mirrored.
+	Projects []string `json:"projects,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Gerrit.
+	RateLimit *GerritRateLimit `json:"rateLimit,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gerrit project.
+	//
+	//  - "{host}" is replaced with the Gerrit URL's host (such as android-review.googlesource.com), and "{name}" is replaced with the Gerrit project's name (such as "platform/build").
+	//
+	// For example, if your Gerrit is https://android-review.googlesource.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{name}" would mean that the Gerrit project platform/build is available on Sourcegraph at https://src.example.com/android-review.googlesource.com/platform/build.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: URL of a Gerrit instance, such as https://android-review.googlesource.com.
+	Url string `json:"url"`
+	// Username description: The username to use when authenticating to Gerrit. Also set the corresponding "password" field. If neither is set, only projects visible to anonymous users are synced.
+	Username string `json:"username,omitempty"`
+}
+
+// GerritRateLimit description: Rate limit applied when making background API requests to Gerrit.
+type GerritRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // GitCommitAuthor description: The author of the Git commit.
 type GitCommitAuthor struct {
 	// Email description: The Git commit author email.
@@ -777,6 +925,52 @@ type GitLabWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GiteaConnection description: Configuration for a connection to Gitea or Forgejo.
+type GiteaConnection struct {
+	// Exclude description: A list of repositories to never mirror from this Gitea instance. Takes precedence over "orgs", "users", "repositoryQuery" and "repos" configuration.
+	//
+	// Supports excluding by name ({"name": "owner/name"}), by ID ({"id": 42}), by a regular expression matching the name ({"pattern": "^myorg/.*"}), or all archived or forked repositories ({"archived": true}, {"forks": true}).
+	Exclude []*ExcludedGiteaRepo `json:"exclude,omitempty"`
+	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Gitea instance.
+	//
+	// If "http", Sourcegraph will access Gitea repositories using Git URLs of the form http(s)://gitea.example.com/myorg/myrepo.git (using https: if the Gitea instance uses HTTPS).
+	//
+	// If "ssh", Sourcegraph will access Gitea repositories using Git URLs of the form git@gitea.example.com:myorg/myrepo.git. See the documentation for how to provide SSH private keys and known_hosts: https://docs.sourcegraph.com/admin/repo/auth#repositories-that-need-http-s-or-ssh-authentication.
+	GitURLType string `json:"gitURLType,omitempty"`
+	// Orgs description: An array of organization names identifying Gitea organizations whose repositories should be mirrored on Sourcegraph.
+	Orgs []string `json:"orgs,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Gitea.
+	RateLimit *GiteaRateLimit `json:"rateLimit,omitempty"`
+	// Repos description: An array of repository "owner/name" strings specifying which Gitea repositories to mirror on Sourcegraph.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gitea repository.
+	//
+	//  - "{host}" is replaced with the Gitea URL's host (such as gitea.example.com), and "{nameWithOwner}" is replaced with the Gitea repository's "owner/name" path (such as "myorg/myrepo").
+	//
+	// For example, if your Gitea is https://gitea.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{nameWithOwner}" would mean that the Gitea repository at https://gitea.example.com/myorg/myrepo is available on Sourcegraph at https://src.example.com/gitea.example.com/myorg/myrepo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// RepositoryQuery description: An array of search terms specifying which Gitea repositories to mirror on Sourcegraph. Each term is passed as the "q" parameter of the repository search API (https://try.gitea.io/api/swagger#/repository/repoSearch) and matches repositories by keyword.
+	//
+	// The special string "all" mirrors all repositories visible to the configured token. Repositories matched by multiple terms are only imported once.
+	RepositoryQuery []string `json:"repositoryQuery,omitempty"`
+	// Token description: A Gitea access token with read permissions on the repositories to mirror. Batch Changes additionally needs write permissions on repositories and issues to push branches and open pull requests. If unset, only public repositories are synced.
+	Token string `json:"token,omitempty"`
+	// Url description: URL of a Gitea or Forgejo instance, such as https://gitea.example.com.
+	Url string `json:"url"`
+	// Users description: An array of user names identifying Gitea users whose repositories should be mirrored on Sourcegraph.
+	Users []string `json:"users,omitempty"`
+}
+
+// GiteaRateLimit description: Rate limit applied when making background API requests to Gitea.
+type GiteaRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
 	// Exclude description: A list of repositories to never mirror from this Gitolite instance. Supports excluding by exact name ({"name": "foo"}).
@@ -1199,6 +1393,26 @@ type Responders struct {
 	Username string `json:"username,omitempty"`
 }
 
+// Rollout description: Publishes the changesets of the batch change in waves. Each wave is only published once enough changesets of the earlier waves have been merged or passed their checks. Changesets that are not selected by any wave are published in a final wave.
+type Rollout struct {
+	// MinSuccessRatio description: The ratio of changesets in the published waves that must have been merged or passed their checks before the next wave is published.
+	MinSuccessRatio float64 `json:"minSuccessRatio,omitempty"`
+	// PauseOnFailure description: Whether to pause the rollout once too many changesets in the published waves have failed to reach the required success ratio. A paused rollout has to be resumed on Sourcegraph.
+	PauseOnFailure bool `json:"pauseOnFailure,omitempty"`
+	// Waves description: The waves in which changesets are published, in order. A changeset is assigned to the first wave that selects it.
+	Waves []*RolloutWave `json:"waves"`
+}
+
+// RolloutWave description: A wave of changesets. A wave selects either a number of changesets, a percentage of all changesets, or the changesets in the repositories matching a list of glob patterns. A wave without any of these selects all remaining changesets.
+type RolloutWave struct {
+	// Count description: The number of changesets in this wave.
+	Count int `json:"count,omitempty"`
+	// Percentage description: The percentage of all changesets of the batch change in this wave.
+	Percentage float64 `json:"percentage,omitempty"`
+	// Repositories description: Glob patterns matching the names of the repositories whose changesets are in this wave.
+	Repositories []string `json:"repositories,omitempty"`
+}
+
 // SAMLAuthProvider description: Configures the SAML authentication provider for SSO.
 //
 // Note: if you are using IdP-initiated login, you must have *at most one* SAMLAuthProvider in the `auth.providers` array.
@@ -1602,6 +1816,23 @@ type UsernameIdentity struct {
 	Type string `json:"type"`
 }
 
+// VaultTransitEncryptionKey description: HashiCorp Vault Transit secrets engine key, or a key of any service compatible with its HTTP API.
+type VaultTransitEncryptionKey struct {
+	// Address description: The URL of the Vault server, e.g. https://vault.example.com:8200.
+	Address string `json:"address"`
+	// Keyname description: The name of the key in the transit secrets engine.
+	Keyname string `json:"keyname"`
+	// MountPath description: The path the transit secrets engine is mounted at.
+	MountPath string `json:"mountPath,omitempty"`
+	// Namespace description: The Vault Enterprise namespace of the transit secrets engine, if any.
+	Namespace string `json:"namespace,omitempty"`
+	// TokenEnvVarName description: Name of an environment variable containing the Vault token to authenticate with.
+	TokenEnvVarName string `json:"tokenEnvVarName,omitempty"`
+	// TokenFilepath description: Path to a file containing the Vault token to authenticate with.
+	TokenFilepath string `json:"tokenFilepath,omitempty"`
+	Type          string `json:"type"`
+}
+
 // VersionContext description: Configuration of the version context
 type VersionContext struct {
 	// Description description: Description of the version context
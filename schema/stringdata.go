@@ -22,6 +22,14 @@ var BitbucketServerSchemaJSON string
 //go:embed changeset_spec.schema.json
 var ChangesetSpecSchemaJSON string
 
+// GerritSchemaJSON is the content of the file "gerrit.schema.json".
+//go:embed gerrit.schema.json
+var GerritSchemaJSON string
+
+// GiteaSchemaJSON is the content of the file "gitea.schema.json".
+//go:embed gitea.schema.json
+var GiteaSchemaJSON string
+
 // GitHubSchemaJSON is the content of the file "github.schema.json".
 //go:embed github.schema.json
 var GitHubSchemaJSON string